@@ -61,6 +61,9 @@ const (
 	MongoOplogSize         = "MONGO_OPLOG_SIZE"
 	NumaCtlPreference      = "NUMA_CTL_PREFERENCE"
 	AllowsSecureConnection = "SECURE_STATESERVER_CONNECTION"
+	LoginRateLimit         = "LOGIN_RATE_LIMIT"
+	AgentRequestRate       = "AGENT_REQUEST_RATE"
+	AgentRequestBurst      = "AGENT_REQUEST_BURST"
 )
 
 // The Config interface is the sole way that the agent gets access to the
@@ -8,7 +8,9 @@ import (
 	"crypto/x509"
 	"fmt"
 	"io"
+	"math/rand"
 	"strings"
+	"sync"
 	"time"
 
 	"code.google.com/p/go.net/websocket"
@@ -118,6 +120,13 @@ type DialOpts struct {
 	// RetryDelay is the amount of time to wait between
 	// unsucssful connection attempts.
 	RetryDelay time.Duration
+
+	// LoginRetries holds the number of times to retry a login
+	// that the server has refused because it is too busy. The
+	// delay before each retry is chosen by TryAgainDelay,
+	// starting from LoginRetryDelay.
+	LoginRetries    int
+	LoginRetryDelay time.Duration
 }
 
 // DefaultDialOpts returns a DialOpts representing the default
@@ -127,7 +136,40 @@ func DefaultDialOpts() DialOpts {
 		DialAddressInterval: 50 * time.Millisecond,
 		Timeout:             10 * time.Minute,
 		RetryDelay:          2 * time.Second,
+		LoginRetries:        5,
+		LoginRetryDelay:     time.Second,
+	}
+}
+
+// maxTryAgainDelay holds the longest delay returned by TryAgainDelay.
+const maxTryAgainDelay = time.Minute
+
+// jitter holds the source of the random jitter added by TryAgainDelay.
+// It is seeded per process, so that agents started together do not
+// all choose the same delays; the global source is always seeded
+// the same way.
+var jitter = struct {
+	sync.Mutex
+	*rand.Rand
+}{Rand: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+// TryAgainDelay returns how long to wait before the given retry
+// (counting from zero) of a request that failed because the server
+// was too busy. The delay doubles with each retry, up to a maximum
+// of a minute, and is randomly jittered so that many clients told
+// to try again at the same time do not all come back together.
+func TryAgainDelay(base time.Duration, retry int) time.Duration {
+	delay := base
+	for i := 0; i < retry && delay < maxTryAgainDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxTryAgainDelay {
+		delay = maxTryAgainDelay
 	}
+	// Choose a delay between half and all of the full delay.
+	jitter.Lock()
+	defer jitter.Unlock()
+	return delay/2 + time.Duration(jitter.Int63n(int64(delay/2)+1))
 }
 
 func Open(info *Info, opts DialOpts) (*State, error) {
@@ -193,7 +235,7 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 		certPool: pool,
 	}
 	if info.Tag != nil || info.Password != "" {
-		if err := st.Login(info.Tag.String(), info.Password, info.Nonce); err != nil {
+		if err := st.loginWithRetry(info, opts); err != nil {
 			conn.Close()
 			return nil, err
 		}
@@ -204,6 +246,20 @@ func Open(info *Info, opts DialOpts) (*State, error) {
 	return st, nil
 }
 
+// loginWithRetry logs in to the API, retrying as specified
+// in opts if the server asks us to try again later.
+func (st *State) loginWithRetry(info *Info, opts DialOpts) error {
+	for retry := 0; ; retry++ {
+		err := st.Login(info.Tag.String(), info.Password, info.Nonce)
+		if !params.IsCodeTryAgain(err) || retry >= opts.LoginRetries {
+			return err
+		}
+		delay := TryAgainDelay(opts.LoginRetryDelay, retry)
+		logger.Debugf("server busy, retrying login in %v", delay)
+		time.Sleep(delay)
+	}
+}
+
 // toString returns the value of a tag's String method, or "" if the tag is nil.
 func toString(tag names.Tag) string {
 	if tag == nil {
@@ -237,6 +293,9 @@ func setUpWebsocket(addr, environUUID string, rootCAs *x509.CertPool) (*websocke
 		RootCAs:    rootCAs,
 		ServerName: "juju-apiserver",
 	}
+	// Tell the server we can read compressed messages. Older
+	// servers ignore the header and send plain JSON.
+	cfg.Header.Set(jsoncodec.CompressionHeader, jsoncodec.CompressionDeflate)
 	return cfg, nil
 }
 
@@ -302,6 +361,28 @@ func (s *State) APICall(facade string, version int, id, method string, args, res
 	return params.ClientError(err)
 }
 
+// APICallCancel implements base.CancelCaller.
+func (s *State) APICallCancel(facade string, version int, id, method string, args, response interface{}, cancel <-chan struct{}) error {
+	err := s.client.CallWithCancel(rpc.Request{
+		Type:    facade,
+		Version: version,
+		Id:      id,
+		Action:  method,
+	}, args, response, cancel)
+	return params.ClientError(err)
+}
+
+// Subscribe implements base.Subscriber by registering the
+// handler with the underlying RPC connection.
+func (s *State) Subscribe(subscription string, handler rpc.PushHandler) {
+	s.client.Subscribe(subscription, handler)
+}
+
+// Unsubscribe implements base.Subscriber.
+func (s *State) Unsubscribe(subscription string) {
+	s.client.Unsubscribe(subscription)
+}
+
 func (s *State) Close() error {
 	err := s.client.Close()
 	select {
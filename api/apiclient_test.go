@@ -8,6 +8,7 @@ import (
 	"io"
 	"net"
 	"strconv"
+	"time"
 
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -177,3 +178,24 @@ func (*websocketSuite) TestSetUpWebsocketConfigHandlesEnvironUUID(c *gc.C) {
 	c.Check(conf.Location.String(), gc.Equals, "wss://0.1.2.3:1234/environment/dead-beef-1234/api")
 	c.Check(conf.Origin.String(), gc.Equals, "http://localhost/")
 }
+
+func (s *websocketSuite) TestTryAgainDelay(c *gc.C) {
+	for i, test := range []struct {
+		base  time.Duration
+		retry int
+		max   time.Duration
+	}{
+		{time.Second, 0, time.Second},
+		{time.Second, 1, 2 * time.Second},
+		{time.Second, 3, 8 * time.Second},
+		{time.Second, 10, time.Minute},
+		{time.Second, 100, time.Minute},
+	} {
+		c.Logf("test %d: base %v, retry %d", i, test.base, test.retry)
+		for j := 0; j < 10; j++ {
+			delay := api.TryAgainDelay(test.base, test.retry)
+			c.Check(delay >= test.max/2, jc.IsTrue)
+			c.Check(delay <= test.max, jc.IsTrue)
+		}
+	}
+}
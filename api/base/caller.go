@@ -5,6 +5,8 @@ package base
 
 import (
 	"github.com/juju/names"
+
+	"github.com/juju/juju/rpc"
 )
 
 // APICaller is implemented by the client-facing State object.
@@ -23,6 +25,28 @@ type APICaller interface {
 	EnvironTag() (names.EnvironTag, error)
 }
 
+// Subscriber is implemented by APICallers that can receive
+// messages pushed by the API server, such as the changes
+// sent by streaming watchers.
+type Subscriber interface {
+	// Subscribe arranges for messages pushed by the API server on
+	// the given subscription to be passed to the handler.
+	Subscribe(subscription string, handler rpc.PushHandler)
+
+	// Unsubscribe stops messages on the given subscription
+	// from being passed to its handler.
+	Unsubscribe(subscription string)
+}
+
+// CancelCaller is implemented by APICallers that can abandon a call
+// made to the API server, asking the server to cancel it.
+type CancelCaller interface {
+	// APICallCancel is like APICall except that, if cancel is
+	// closed before the call completes, the server is asked to
+	// cancel the call and rpc.ErrCancelled is returned.
+	APICallCancel(objType string, version int, id, request string, params, response interface{}, cancel <-chan struct{}) error
+}
+
 // FacadeCaller is a wrapper for the common paradigm that a given client just
 // wants to make calls on a facade using the best known version of the API. And
 // without dealing with an id parameter.
@@ -97,3 +121,15 @@ func NewFacadeCallerForVersion(caller APICaller, facadeName string, version int)
 		caller:      caller,
 	}
 }
+
+// FacadeCallCancel places a request against the facade of fc as
+// FacadeCall does, abandoning the call if cancel is closed before it
+// completes. If the underlying APICaller cannot cancel calls, the call
+// runs to completion.
+func FacadeCallCancel(fc FacadeCaller, request string, params, response interface{}, cancel <-chan struct{}) error {
+	caller, ok := fc.RawAPICaller().(CancelCaller)
+	if !ok {
+		return fc.FacadeCall(request, params, response)
+	}
+	return caller.APICallCancel(fc.Name(), fc.BestAPIVersion(), "", request, params, response, cancel)
+}
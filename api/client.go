@@ -95,6 +95,28 @@ type ServiceStatus struct {
 	CanUpgradeTo  string
 	SubordinateTo []string
 	Units         map[string]UnitStatus
+	CharmHistory  []CharmHistoryEntry
+	CharmUpgrade  *CharmUpgradeStatus
+}
+
+// CharmHistoryEntry records a charm set on a service.
+type CharmHistoryEntry struct {
+	Charm string
+	// Time is zero if it is not known when the charm was set.
+	Time time.Time
+	User string
+}
+
+// CharmUpgradeStatus holds the progress of a rolling charm upgrade of
+// a service.
+type CharmUpgradeStatus struct {
+	From string
+	// Batch holds the units currently upgrading.
+	Batch []string
+	// Waiting holds the units yet to be upgraded.
+	Waiting      []string
+	Paused       bool
+	PausedReason string
 }
 
 // UnitStatus holds status info about a unit.
@@ -112,6 +134,7 @@ type UnitStatus struct {
 	OpenedPorts   []string
 	PublicAddress string
 	Charm         string
+	Leader        bool
 	Subordinates  map[string]UnitStatus
 }
 
@@ -155,9 +178,16 @@ type Status struct {
 
 // Status returns the status of the juju environment.
 func (c *Client) Status(patterns []string) (*Status, error) {
+	return c.StatusWithCancel(patterns, nil)
+}
+
+// StatusWithCancel is like Status except that the request is abandoned,
+// and rpc.ErrCancelled returned, if cancel is closed before the status
+// has been returned.
+func (c *Client) StatusWithCancel(patterns []string, cancel <-chan struct{}) (*Status, error) {
 	var result Status
 	p := params.StatusParams{Patterns: patterns}
-	if err := c.facade.FacadeCall("FullStatus", p, &result); err != nil {
+	if err := base.FacadeCallCancel(c.facade, "FullStatus", p, &result, cancel); err != nil {
 		return nil, err
 	}
 	return &result, nil
@@ -243,6 +273,21 @@ func (c *Client) PrivateAddress(target string) (string, error) {
 	return results.PrivateAddress, err
 }
 
+// ServiceLeader returns the name of the unit which leads the named
+// service, or an empty string if it has no leader.
+func (c *Client) ServiceLeader(service string) (string, error) {
+	var results params.ServiceLeaderResults
+	p := params.ServiceLeader{ServiceName: service}
+	err := c.facade.FacadeCall("ServiceLeader", p, &results)
+	return results.UnitName, err
+}
+
+// SetServiceLeader makes the named unit the leader of its service.
+func (c *Client) SetServiceLeader(unit string) error {
+	p := params.SetServiceLeader{UnitName: unit}
+	return c.facade.FacadeCall("SetServiceLeader", p, nil)
+}
+
 // ServiceSetYAML sets configuration options on a service
 // given options in YAML format.
 func (c *Client) ServiceSetYAML(service string, yaml string) error {
@@ -269,12 +314,43 @@ func (c *Client) AddRelation(endpoints ...string) (*params.AddRelationResults, e
 	return &addRelRes, err
 }
 
+// AddCrossEnvironmentRelation relates a service of this environment to
+// one offered by the named environment, and returns the relation info.
+func (c *Client) AddCrossEnvironmentRelation(endpoint, environment, offeredEndpoint string) (*params.AddRelationResults, error) {
+	var addRelRes params.AddRelationResults
+	args := params.AddCrossEnvironmentRelation{
+		Endpoint:        endpoint,
+		Environment:     environment,
+		OfferedEndpoint: offeredEndpoint,
+	}
+	err := c.facade.FacadeCall("AddCrossEnvironmentRelation", args, &addRelRes)
+	return &addRelRes, err
+}
+
 // DestroyRelation removes the relation between the specified endpoints.
 func (c *Client) DestroyRelation(endpoints ...string) error {
 	params := params.DestroyRelation{Endpoints: endpoints}
 	return c.facade.FacadeCall("DestroyRelation", params, nil)
 }
 
+// ShowRelation returns the units in scope in the relation between the
+// specified endpoints, with their settings in it.
+func (c *Client) ShowRelation(endpoints ...string) (params.RelationDetails, error) {
+	var result params.RelationDetails
+	args := params.ShowRelation{Endpoints: endpoints}
+	err := c.facade.FacadeCall("ShowRelation", args, &result)
+	return result, err
+}
+
+// ShowRelationById returns the units in scope in the relation with the
+// given id, with their settings in it.
+func (c *Client) ShowRelationById(relationId int) (params.RelationDetails, error) {
+	var result params.RelationDetails
+	args := params.ShowRelation{RelationId: relationId}
+	err := c.facade.FacadeCall("ShowRelation", args, &result)
+	return result, err
+}
+
 // ServiceCharmRelations returns the service's charms relation names.
 func (c *Client) ServiceCharmRelations(service string) ([]string, error) {
 	var results params.ServiceCharmRelationsResults
@@ -371,6 +447,33 @@ func (c *Client) ServiceDeployWithNetworks(
 	return c.facade.FacadeCall("ServiceDeployWithNetworks", params, nil)
 }
 
+// ServiceDeployWithBindings works exactly like ServiceDeployWithNetworks,
+// but also binds the given relation endpoints of the service to spaces.
+func (c *Client) ServiceDeployWithBindings(
+	charmURL string,
+	serviceName string,
+	numUnits int,
+	configYAML string,
+	cons constraints.Value,
+	toMachineSpec string,
+	networks []string,
+	storage map[string]storage.Constraints,
+	bindings map[string]string,
+) error {
+	params := params.ServiceDeploy{
+		ServiceName:      serviceName,
+		CharmUrl:         charmURL,
+		NumUnits:         numUnits,
+		ConfigYAML:       configYAML,
+		Constraints:      cons,
+		ToMachineSpec:    toMachineSpec,
+		Networks:         networks,
+		Storage:          storage,
+		EndpointBindings: bindings,
+	}
+	return c.facade.FacadeCall("ServiceDeployWithNetworks", params, nil)
+}
+
 // ServiceDeploy obtains the charm, either locally or from the charm store,
 // and deploys it.
 func (c *Client) ServiceDeploy(charmURL string, serviceName string, numUnits int, configYAML string, cons constraints.Value, toMachineSpec string) error {
@@ -402,6 +505,66 @@ func (c *Client) ServiceSetCharm(serviceName string, charmUrl string, force bool
 	return c.facade.FacadeCall("ServiceSetCharm", args, nil)
 }
 
+// ServiceSetCharmInBatches sets the charm for a given service, upgrading
+// its units batchSize at a time. Each batch is given batchTimeout to
+// become healthy before the next one is upgraded; if pauseOnError is
+// true, a unit in an error state pauses the upgrade until
+// ServiceResumeCharmUpgrade is called. If the API server cannot upgrade
+// units in batches, an error satisfying errors.IsNotSupported is
+// returned and the charm is left unchanged.
+func (c *Client) ServiceSetCharmInBatches(serviceName, charmUrl string, force bool, batchSize int, pauseOnError bool, batchTimeout time.Duration) error {
+	args := params.ServiceSetCharmInBatches{
+		ServiceName:  serviceName,
+		CharmUrl:     charmUrl,
+		Force:        force,
+		BatchSize:    batchSize,
+		PauseOnError: pauseOnError,
+		BatchTimeout: batchTimeout,
+	}
+	err := c.facade.FacadeCall("ServiceSetCharmInBatches", args, nil)
+	if params.IsCodeNotImplemented(err) {
+		return errors.NewNotSupported(err, "upgrading units in batches is not supported by the API server")
+	}
+	return err
+}
+
+// ServiceRevertCharm changes the charm of the given service back to
+// the one it ran before its current one, and returns the URL of that
+// charm. A positive batchSize upgrades the units in batches, as for
+// ServiceSetCharmInBatches.
+func (c *Client) ServiceRevertCharm(serviceName string, force bool, batchSize int, pauseOnError bool, batchTimeout time.Duration) (*charm.URL, error) {
+	var result params.StringResult
+	args := params.ServiceRevertCharm{
+		ServiceName:  serviceName,
+		Force:        force,
+		BatchSize:    batchSize,
+		PauseOnError: pauseOnError,
+		BatchTimeout: batchTimeout,
+	}
+	if err := c.facade.FacadeCall("ServiceRevertCharm", args, &result); err != nil {
+		return nil, err
+	}
+	return charm.ParseURL(result.Result)
+}
+
+// ServiceResumeCharmUpgrade resumes the paused rolling charm upgrade
+// of the given service.
+func (c *Client) ServiceResumeCharmUpgrade(serviceName string) error {
+	args := params.ServiceResumeCharmUpgrade{ServiceName: serviceName}
+	return c.facade.FacadeCall("ServiceResumeCharmUpgrade", args, nil)
+}
+
+// ServiceOffer offers the named endpoints of a service for relation to
+// services of other environments. If no endpoints are given, all those
+// of the service that can be offered are.
+func (c *Client) ServiceOffer(serviceName string, endpoints ...string) error {
+	args := params.ServiceOffer{
+		ServiceName: serviceName,
+		Endpoints:   endpoints,
+	}
+	return c.facade.FacadeCall("ServiceOffer", args, nil)
+}
+
 // ServiceGetCharmURL returns the charm URL the given service is
 // running at present.
 func (c *Client) ServiceGetCharmURL(serviceName string) (*charm.URL, error) {
@@ -663,6 +826,13 @@ func (c *Client) Run(run params.RunParams) ([]params.RunResult, error) {
 	return results.Results, err
 }
 
+// RunHook queues the hook described by run to be run on its unit.
+func (c *Client) RunHook(run params.RunHookParams) (params.RunResult, error) {
+	var result params.RunResult
+	err := c.facade.FacadeCall("RunHook", run, &result)
+	return result, err
+}
+
 // DestroyEnvironment puts the environment into a "dying" state,
 // and removes all non-manager machine instances. DestroyEnvironment
 // will fail if there are any manually-provisioned non-manager machines
@@ -680,30 +850,11 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 		return nil, errors.Errorf("expected charm URL with local: schema, got %q", curl.String())
 	}
 	// Package the charm for uploading.
-	var archive *os.File
-	switch ch := ch.(type) {
-	case *charm.CharmDir:
-		var err error
-		if archive, err = ioutil.TempFile("", "charm"); err != nil {
-			return nil, errors.Annotate(err, "cannot create temp file")
-		}
-		defer os.Remove(archive.Name())
-		defer archive.Close()
-		if err := ch.ArchiveTo(archive); err != nil {
-			return nil, errors.Annotate(err, "cannot repackage charm")
-		}
-		if _, err := archive.Seek(0, 0); err != nil {
-			return nil, errors.Annotate(err, "cannot rewind packaged charm")
-		}
-	case *charm.CharmArchive:
-		var err error
-		if archive, err = os.Open(ch.Path); err != nil {
-			return nil, errors.Annotate(err, "cannot read charm archive")
-		}
-		defer archive.Close()
-	default:
-		return nil, errors.Errorf("unknown charm type %T", ch)
+	archive, cleanup, err := openCharmArchive(ch)
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
+	defer cleanup()
 
 	// Prepare the upload request.
 	url := fmt.Sprintf("%s/charms?series=%s", c.st.serverRoot, curl.Series)
@@ -749,6 +900,94 @@ func (c *Client) AddLocalCharm(curl *charm.URL, ch charm.Charm) (*charm.URL, err
 	return charm.MustParseURL(jsonResponse.CharmURL), nil
 }
 
+// openCharmArchive returns a reader of the archive of the given
+// charm, packaging it first if it is a directory. The returned
+// function must be called once the archive is no longer needed.
+func openCharmArchive(ch charm.Charm) (*os.File, func(), error) {
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		archive, err := ioutil.TempFile("", "charm")
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "cannot create temp file")
+		}
+		cleanup := func() {
+			archive.Close()
+			os.Remove(archive.Name())
+		}
+		if err := ch.ArchiveTo(archive); err != nil {
+			cleanup()
+			return nil, nil, errors.Annotate(err, "cannot repackage charm")
+		}
+		if _, err := archive.Seek(0, 0); err != nil {
+			cleanup()
+			return nil, nil, errors.Annotate(err, "cannot rewind packaged charm")
+		}
+		return archive, cleanup, nil
+	case *charm.CharmArchive:
+		archive, err := os.Open(ch.Path)
+		if err != nil {
+			return nil, nil, errors.Annotate(err, "cannot read charm archive")
+		}
+		return archive, func() { archive.Close() }, nil
+	}
+	return nil, nil, errors.Errorf("unknown charm type %T", ch)
+}
+
+// CharmMirrorURL returns the URL of the charm mirror served by the
+// API server, suitable for use as an environment's charm-store-url.
+func (c *Client) CharmMirrorURL() (string, error) {
+	tag, err := c.st.EnvironTag()
+	if err != nil {
+		return "", errors.Annotate(err, "while extracting environment UUID")
+	}
+	return fmt.Sprintf("%s/environment/%s/charmstore", c.st.serverRoot, tag.Id()), nil
+}
+
+// MirrorCharm adds the given charm to the state server's charm mirror
+// under the charm store URL curl, which must include a revision. The
+// client must be connected to the state server environment.
+func (c *Client) MirrorCharm(curl *charm.URL, ch charm.Charm) error {
+	if curl.Schema != "cs" || curl.Revision < 0 {
+		return errors.Errorf("expected charm store URL with revision, got %q", curl.String())
+	}
+	archive, cleanup, err := openCharmArchive(ch)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer cleanup()
+
+	mirrorURL, err := c.CharmMirrorURL()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", mirrorURL+"?url="+url.QueryEscape(curl.String()), archive)
+	if err != nil {
+		return errors.Annotate(err, "cannot create upload request")
+	}
+	req.SetBasicAuth(c.st.tag, c.st.password)
+	req.Header.Set("Content-Type", "application/zip")
+
+	// See the note in AddLocalCharm about the non-validating client.
+	resp, err := utils.GetNonValidatingHTTPClient().Do(req)
+	if err != nil {
+		return errors.Annotate(err, "cannot upload charm")
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Annotate(err, "cannot read charm upload response")
+	}
+	var jsonResponse params.CharmsResponse
+	if err := json.Unmarshal(body, &jsonResponse); err != nil {
+		return errors.Errorf("charm upload failed: %v (%s)", resp.StatusCode, bytes.TrimSpace(body))
+	}
+	if jsonResponse.Error != "" {
+		return errors.Errorf("error mirroring charm: %v", jsonResponse.Error)
+	}
+	return nil
+}
+
 // AddCharm adds the given charm URL (which must include revision) to
 // the environment, if it does not exist yet. Local charms are not
 // supported, only charm store URLs. See also AddLocalCharm() in the
@@ -103,6 +103,40 @@ func (s *clientSuite) TestAddLocalCharmError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "charm upload failed: 405 \\(Method Not Allowed\\)")
 }
 
+func (s *clientSuite) TestMirrorCharm(c *gc.C) {
+	client := s.APIState.Client()
+	charmArchive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+
+	err := client.MirrorCharm(charm.MustParseURL("cs:quantal/dummy"), charmArchive)
+	c.Assert(err, gc.ErrorMatches, `expected charm store URL with revision, got "cs:quantal/dummy"`)
+
+	curl := charm.MustParseURL("cs:quantal/dummy-7")
+	err = client.MirrorCharm(curl, charmArchive)
+	c.Assert(err, jc.ErrorIsNil)
+	mirror, err := s.State.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	defer mirror.Close()
+	metadata, err := mirror.Metadata(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.URL, gc.DeepEquals, curl)
+
+	// Charm directories are packaged before upload.
+	charmDir := testcharms.Repo.ClonedDir(c.MkDir(), "dummy")
+	err = client.MirrorCharm(curl.WithRevision(8), charmDir)
+	c.Assert(err, jc.ErrorIsNil)
+	metadata, err = mirror.Metadata(curl.WithRevision(-1))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.URL, gc.DeepEquals, curl.WithRevision(8))
+}
+
+func (s *clientSuite) TestCharmMirrorURL(c *gc.C) {
+	client := s.APIState.Client()
+	api.SetServerRoot(client, "https://10.0.0.1:17070")
+	mirrorURL, err := client.CharmMirrorURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(mirrorURL, gc.Equals, "https://10.0.0.1:17070/environment/"+s.State.EnvironUUID()+"/charmstore")
+}
+
 func (s *clientSuite) TestClientEnvironmentUUID(c *gc.C) {
 	environ, err := s.State.Environment()
 	c.Assert(err, jc.ErrorIsNil)
@@ -138,6 +172,23 @@ func (s *clientSuite) TestShareEnvironmentExistingUser(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "failed to create environment user: env user already exists")
 }
 
+func (s *clientSuite) TestServiceSetCharmInBatchesNotSupported(c *gc.C) {
+	client := s.APIState.Client()
+	cleanup := api.PatchClientFacadeCall(client,
+		func(request string, args interface{}, response interface{}) error {
+			c.Assert(request, gc.Equals, "ServiceSetCharmInBatches")
+			return &params.Error{
+				Message: "no such request - method Client(0).ServiceSetCharmInBatches is not implemented",
+				Code:    params.CodeNotImplemented,
+			}
+		})
+	defer cleanup()
+
+	err := client.ServiceSetCharmInBatches("wordpress", "cs:precise/wordpress-3", false, 1, false, 0)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+	c.Assert(err, gc.ErrorMatches, "upgrading units in batches is not supported by the API server")
+}
+
 func (s *clientSuite) TestDestroyEnvironment(c *gc.C) {
 	client := s.APIState.Client()
 	var called bool
@@ -28,6 +28,7 @@ var facadeVersions = map[string]int{
 	"Firewaller":           1,
 	"HighAvailability":     1,
 	"ImageManager":         1,
+	"Introspection":        0,
 	"KeyManager":           0,
 	"KeyUpdater":           0,
 	"LeadershipService":    1,
@@ -35,16 +36,18 @@ var facadeVersions = map[string]int{
 	"Machiner":             0,
 	"MetricsManager":       0,
 	"Networker":            0,
-	"NotifyWatcher":        0,
+	"NotifyWatcher":        1,
 	"Pinger":               0,
 	"Provisioner":          0,
 	"Reboot":               1,
 	"RelationUnitsWatcher": 0,
 	"Rsyslog":              0,
 	"Service":              1,
+	"Spaces":               1,
 	"Storage":              1,
 	"StorageProvisioner":   1,
-	"StringsWatcher":       0,
+	"StringsWatcher":       1,
+	"Subnets":              1,
 	"Upgrader":             0,
 	"Uniter":               2,
 	"UserManager":          0,
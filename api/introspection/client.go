@@ -0,0 +1,34 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client provides access to the introspection service, used to
+// examine the state of a state server.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new Introspection client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "Introspection")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Report returns a report on the state server that the
+// API connection is connected to.
+func (c *Client) Report() (params.IntrospectionReport, error) {
+	var result params.IntrospectionReport
+	if err := c.facade.FacadeCall("Report", nil, &result); err != nil {
+		return params.IntrospectionReport{}, errors.Trace(err)
+	}
+	return result, nil
+}
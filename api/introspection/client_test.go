@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	stdtesting "testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/api/introspection"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+)
+
+func TestAll(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type clientSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestReport(c *gc.C) {
+	// The lease manager must be running for leases to be reported.
+	leaseWorker := worker.NewSimpleWorker(lease.WorkerLoop(s.State))
+	defer worker.Stop(leaseWorker)
+
+	client := introspection.NewClient(s.APIState)
+	report, err := client.Report()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.AgentTag, gc.Equals, "machine-0")
+	c.Assert(report.LeasesError, gc.IsNil)
+
+	var found bool
+	for _, conn := range report.Connections {
+		if conn.AuthTag == s.AdminUserTag(c).String() {
+			found = true
+			c.Check(conn.RemoteAddress, gc.Not(gc.Equals), "")
+			c.Check(conn.Connected.IsZero(), jc.IsFalse)
+		}
+	}
+	c.Assert(found, jc.IsTrue)
+}
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/rpc"
 )
 
 var logger = loggo.GetLogger("juju.api.leadership")
@@ -72,11 +73,13 @@ func (c *client) ReleaseLeadership(serviceId, unitId string) error {
 }
 
 // BlockUntilLeadershipReleased implements LeadershipManager.
-func (c *client) BlockUntilLeadershipReleased(serviceId string) error {
+func (c *client) BlockUntilLeadershipReleased(serviceId string, cancel <-chan struct{}) error {
 	const friendlyErrMsg = "error blocking on leadership release"
 	var result params.ErrorResult
-	err := c.FacadeCall("BlockUntilLeadershipReleased", names.NewServiceTag(serviceId), &result)
-	if err != nil {
+	err := c.facadeCallCancel("BlockUntilLeadershipReleased", names.NewServiceTag(serviceId), &result, cancel)
+	if err == rpc.ErrCancelled {
+		return leadership.ErrBlockCancelled
+	} else if err != nil {
 		return errors.Annotate(err, friendlyErrMsg)
 	} else if result.Error != nil {
 		return errors.Annotate(result.Error, friendlyErrMsg)
@@ -84,6 +87,34 @@ func (c *client) BlockUntilLeadershipReleased(serviceId string) error {
 	return nil
 }
 
+// BlockUntilLeadershipLost implements LeadershipManager.
+func (c *client) BlockUntilLeadershipLost(serviceId, unitId string, cancel <-chan struct{}) error {
+	const friendlyErrMsg = "error blocking on leadership loss"
+	var result params.ErrorResult
+	args := params.BlockUntilLeadershipLostParams{
+		ServiceTag: names.NewServiceTag(serviceId).String(),
+		UnitTag:    names.NewUnitTag(unitId).String(),
+	}
+	err := c.facadeCallCancel("BlockUntilLeadershipLost", args, &result, cancel)
+	if err == rpc.ErrCancelled {
+		return leadership.ErrBlockCancelled
+	} else if err != nil {
+		return errors.Annotate(err, friendlyErrMsg)
+	} else if result.Error != nil {
+		return errors.Annotate(result.Error, friendlyErrMsg)
+	}
+	return nil
+}
+
+// facadeCallCancel makes the given call, abandoning it when cancel is
+// closed if the facade caller is able to cancel calls.
+func (c *client) facadeCallCancel(request string, params, response interface{}, cancel <-chan struct{}) error {
+	if fc, ok := c.facadeCaller.(base.FacadeCaller); ok {
+		return base.FacadeCallCancel(fc, request, params, response, cancel)
+	}
+	return c.FacadeCall(request, params, response)
+}
+
 //
 // Prepare functions for building bulk-calls.
 //
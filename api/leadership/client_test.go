@@ -191,7 +191,33 @@ func (s *clientSuite) TestBlockUntilLeadershipReleasedTranslation(c *gc.C) {
 	}
 
 	client := NewClient(stub, stub)
-	err := client.BlockUntilLeadershipReleased(StubServiceNm)
+	err := client.BlockUntilLeadershipReleased(StubServiceNm, nil)
+
+	c.Check(numStubCalls, gc.Equals, 1)
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestBlockUntilLeadershipLostTranslation(c *gc.C) {
+
+	numStubCalls := 0
+	stub := &stubFacade{
+		FacadeCallFn: func(name string, parameters, response interface{}) error {
+			numStubCalls++
+			c.Check(name, gc.Equals, "BlockUntilLeadershipLost")
+			c.Check(parameters, jc.DeepEquals, params.BlockUntilLeadershipLostParams{
+				ServiceTag: names.NewServiceTag(StubServiceNm).String(),
+				UnitTag:    names.NewUnitTag(StubUnitNm).String(),
+			})
+
+			_, ok := response.(*params.ErrorResult)
+			c.Assert(ok, gc.Equals, true)
+
+			return nil
+		},
+	}
+
+	client := NewClient(stub, stub)
+	err := client.BlockUntilLeadershipLost(StubServiceNm, StubUnitNm, nil)
 
 	c.Check(numStubCalls, gc.Equals, 1)
 	c.Check(err, jc.ErrorIsNil)
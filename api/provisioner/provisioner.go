@@ -193,3 +193,17 @@ func (st *State) PrepareContainerInterfaceInfo(containerTag names.MachineTag) ([
 	}
 	return ifaceInfo, nil
 }
+
+// PrepareContainerPortForwarding prepares the given container, which
+// is on its host's NAT bridge, to be reached through its host, unless
+// it already has addresses of its own.
+func (st *State) PrepareContainerPortForwarding(containerTag names.MachineTag) error {
+	var result params.ErrorResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: containerTag.String()}},
+	}
+	if err := st.facade.FacadeCall("PrepareContainerPortForwarding", args, &result); err != nil {
+		return err
+	}
+	return result.OneError()
+}
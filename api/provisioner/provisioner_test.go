@@ -521,6 +521,61 @@ func (s *provisionerSuite) TestWatchContainers(c *gc.C) {
 	wc.AssertClosed()
 }
 
+func (s *provisionerSuite) TestPortForwards(c *gc.C) {
+	apiMachine, err := s.provisioner.Machine(s.machine.Tag().(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := apiMachine.WatchContainerPorts()
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.BackingState, w)
+
+	// Initial event.
+	wc.AssertOneChange()
+	forwards, err := apiMachine.PortForwards()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(forwards, gc.HasLen, 0)
+
+	// Add a container with a unit opening a port.
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	hostAddress := network.NewAddress("0.1.2.3", network.ScopeCloudLocal)
+	err = s.machine.SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = container.SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	forwards, err = apiMachine.PortForwards()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(forwards, jc.DeepEquals, []params.PortForward{{
+		ContainerTag:     container.Tag().String(),
+		ContainerAddress: "10.0.3.5",
+		PortRange:        params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	}})
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *provisionerSuite) TestWatchContainersAcceptsSupportedContainers(c *gc.C) {
 	apiMachine, err := s.provisioner.Machine(s.machine.Tag().(names.MachineTag))
 	c.Assert(err, jc.ErrorIsNil)
@@ -603,7 +658,8 @@ func (s *provisionerSuite) TestContainerManagerConfigKNoIPForwarding(c *gc.C) {
 
 	cfg := s.getManagerConfig(c, instance.KVM)
 	c.Assert(cfg, jc.DeepEquals, map[string]string{
-		container.ConfigName: "juju",
+		container.ConfigName:           "juju",
+		container.ConfigPortForwarding: "true",
 	})
 }
 
@@ -811,3 +867,23 @@ func (s *provisionerSuite) TestPrepareContainerInterfaceInfo(c *gc.C) {
 	expectInfo[0].Address = ifaceInfo[0].Address
 	c.Assert(ifaceInfo, jc.DeepEquals, expectInfo)
 }
+
+func (s *provisionerSuite) TestPrepareContainerPortForwarding(c *gc.C) {
+	// This test exercises just the success path, all the other cases
+	// are already tested in the apiserver package.
+	hostAddress := network.NewAddress("0.1.2.3", network.ScopeCloudLocal)
+	err := s.machine.SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	container, err := s.State.AddMachineInsideMachine(template, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.provisioner.PrepareContainerPortForwarding(container.MachineTag())
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Addresses(), jc.DeepEquals, []network.Address{hostAddress})
+}
@@ -4,6 +4,8 @@
 package service_test
 
 import (
+	"strings"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -70,3 +72,43 @@ func (s *serviceSuite) TestSetServiceMetricCredentialsNoMocks(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(service.MetricCredentials(), gc.DeepEquals, []byte("creds"))
 }
+
+func (s *serviceSuite) TestServiceResources(c *gc.C) {
+	var called bool
+	service.PatchFacadeCall(s, s.client, func(request string, a, response interface{}) error {
+		called = true
+		c.Assert(request, gc.Equals, "ServiceResources")
+		c.Assert(a, gc.DeepEquals, params.Entities{
+			Entities: []params.Entity{{Tag: "service-mysql"}},
+		})
+		result := response.(*params.ServiceResourcesResults)
+		result.Results = []params.ServiceResourcesResult{{
+			Resources: []params.ServiceResource{{Name: "jar", Revision: 3}},
+		}}
+		return nil
+	})
+	resources, err := s.client.ServiceResources("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(resources, gc.DeepEquals, []params.ServiceResource{{Name: "jar", Revision: 3}})
+}
+
+func (s *serviceSuite) TestPushResourceNoMocks(c *gc.C) {
+	svc := s.Factory.MakeService(c, nil)
+	result, err := s.client.PushResource(svc.Name(), "jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Name, gc.Equals, "jar")
+	c.Assert(result.Revision, gc.Equals, 1)
+	c.Assert(result.Size, gc.Equals, int64(7))
+
+	resources, err := s.client.ServiceResources(svc.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 1)
+	c.Assert(resources[0].Revision, gc.Equals, 1)
+	c.Assert(resources[0].SHA384, gc.Equals, result.SHA384)
+}
+
+func (s *serviceSuite) TestPushResourceServiceNotFound(c *gc.C) {
+	_, err := s.client.PushResource("missing", "jar", strings.NewReader("content"), 7)
+	c.Assert(err, gc.ErrorMatches, `service "missing" not found`)
+}
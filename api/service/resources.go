@@ -0,0 +1,63 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package service
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// ServiceResources returns the current revision of each resource
+// attached to the service.
+func (c *Client) ServiceResources(service string) ([]params.ServiceResource, error) {
+	if !names.IsValidService(service) {
+		return nil, errors.NotValidf("service name %q", service)
+	}
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: names.NewServiceTag(service).String()}},
+	}
+	var results params.ServiceResourcesResults
+	if err := c.facade.FacadeCall("ServiceResources", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	if err := results.Results[0].Error; err != nil {
+		return nil, err
+	}
+	return results.Results[0].Resources, nil
+}
+
+// PushResource stores size bytes read from content as the next
+// revision of the named resource of the service.
+func (c *Client) PushResource(service, name string, content io.Reader, size int64) (params.ServiceResource, error) {
+	meta := params.ResourceUploadArgs{
+		ServiceName: service,
+		Name:        name,
+		Size:        size,
+	}
+	_, resp, err := c.st.SendHTTPRequestReader("resources", content, &meta, name)
+	if err != nil {
+		return params.ServiceResource{}, errors.Annotate(err, "while sending HTTP request")
+	}
+	if resp.StatusCode != http.StatusOK {
+		failure, err := apihttp.ExtractAPIError(resp)
+		if err != nil {
+			return params.ServiceResource{}, errors.Annotate(err, "while extracting failure")
+		}
+		return params.ServiceResource{}, errors.Trace(failure)
+	}
+	var result params.ServiceResource
+	if err := apihttp.ExtractJSONResult(resp, &result); err != nil {
+		return params.ServiceResource{}, errors.Annotate(err, "while extracting result")
+	}
+	return result, nil
+}
@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the spaces API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the spaces API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Spaces")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// CreateSpace creates a new space with the given name, containing the
+// subnets with the given CIDRs.
+func (c *Client) CreateSpace(name string, subnetCIDRs []string) error {
+	var results params.ErrorResults
+	args := params.CreateSpacesParams{
+		Spaces: []params.Space{{Name: name, SubnetCIDRs: subnetCIDRs}},
+	}
+	if err := c.facade.FacadeCall("CreateSpaces", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListSpaces returns all the spaces known to the environment.
+func (c *Client) ListSpaces() ([]params.Space, error) {
+	var results params.ListSpacesResults
+	if err := c.facade.FacadeCall("ListSpaces", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
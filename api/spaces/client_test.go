@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type spacesMockSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&spacesMockSuite{})
+
+func (s *spacesMockSuite) TestCreateSpace(c *gc.C) {
+	var called bool
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Spaces")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "CreateSpaces")
+			c.Check(a, jc.DeepEquals, params.CreateSpacesParams{
+				Spaces: []params.Space{{Name: "internal", SubnetCIDRs: []string{"10.0.0.0/24"}}},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{
+					Error: &params.Error{Message: "boom"},
+				}}
+			}
+			return nil
+		})
+	client := spaces.NewClient(apiCaller)
+	err := client.CreateSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, "boom")
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *spacesMockSuite) TestListSpaces(c *gc.C) {
+	expected := []params.Space{
+		{Name: "internal", SubnetCIDRs: []string{"10.0.0.0/24"}},
+		{Name: "public"},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Spaces")
+			c.Check(request, gc.Equals, "ListSpaces")
+			c.Check(a, gc.IsNil)
+			if results, ok := result.(*params.ListSpacesResults); ok {
+				results.Results = expected
+			}
+			return nil
+		})
+	client := spaces.NewClient(apiCaller)
+	found, err := client.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, expected)
+}
@@ -0,0 +1,45 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Client allows access to the subnets API end point.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient creates a new client for accessing the subnets API.
+func NewClient(st base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(st, "Subnets")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// AddSubnet adds the given subnet, placing it in the named space if
+// SpaceName is set.
+func (c *Client) AddSubnet(subnet params.Subnet) error {
+	var results params.ErrorResults
+	args := params.AddSubnetsParams{
+		Subnets: []params.Subnet{subnet},
+	}
+	if err := c.facade.FacadeCall("AddSubnets", args, &results); err != nil {
+		return errors.Trace(err)
+	}
+	return results.OneError()
+}
+
+// ListSubnets returns all the subnets known to the environment.
+func (c *Client) ListSubnets() ([]params.Subnet, error) {
+	var results params.ListSubnetsResults
+	if err := c.facade.FacadeCall("ListSubnets", nil, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return results.Results, nil
+}
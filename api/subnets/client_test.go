@@ -0,0 +1,72 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnets_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	basetesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/api/subnets"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/testing"
+)
+
+type subnetsMockSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&subnetsMockSuite{})
+
+func (s *subnetsMockSuite) TestAddSubnet(c *gc.C) {
+	var called bool
+	subnet := params.Subnet{CIDR: "10.0.0.0/24", SpaceName: "internal"}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			called = true
+			c.Check(objType, gc.Equals, "Subnets")
+			c.Check(id, gc.Equals, "")
+			c.Check(request, gc.Equals, "AddSubnets")
+			c.Check(a, jc.DeepEquals, params.AddSubnetsParams{
+				Subnets: []params.Subnet{subnet},
+			})
+			if results, ok := result.(*params.ErrorResults); ok {
+				results.Results = []params.ErrorResult{{}}
+			}
+			return nil
+		})
+	client := subnets.NewClient(apiCaller)
+	err := client.AddSubnet(subnet)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *subnetsMockSuite) TestListSubnets(c *gc.C) {
+	expected := []params.Subnet{
+		{CIDR: "10.0.0.0/24", SpaceName: "internal"},
+		{CIDR: "10.0.1.0/24", VLANTag: 42},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string,
+			version int,
+			id, request string,
+			a, result interface{},
+		) error {
+			c.Check(objType, gc.Equals, "Subnets")
+			c.Check(request, gc.Equals, "ListSubnets")
+			c.Check(a, gc.IsNil)
+			if results, ok := result.(*params.ListSubnetsResults); ok {
+				results.Results = expected
+			}
+			return nil
+		})
+	client := subnets.NewClient(apiCaller)
+	found, err := client.ListSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, jc.DeepEquals, expected)
+}
@@ -40,14 +40,40 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 	return ru.endpoint
 }
 
-// PrivateAddress returns the private address of the unit and whether
-// it is valid.
+// PrivateAddress returns the private address the unit advertises on
+// the relation. If the unit's service binds the relation's endpoint to
+// a space, this is the unit's address in that space; otherwise it is
+// the unit's private address.
 //
 // NOTE: This differs from state.RelationUnit.PrivateAddress() by
 // returning an error instead of a bool, because it needs to make an
 // API call.
 func (ru *RelationUnit) PrivateAddress() (string, error) {
-	return ru.unit.PrivateAddress()
+	if ru.st.BestAPIVersion() < 2 {
+		// Endpoints cannot be bound through older API servers.
+		return ru.unit.PrivateAddress()
+	}
+	var results params.StringResults
+	args := params.RelationUnits{
+		RelationUnits: []params.RelationUnit{{
+			Relation: ru.relation.tag.String(),
+			Unit:     ru.unit.tag.String(),
+		}},
+	}
+	err := ru.st.facade.FacadeCall("RelationPrivateAddress", args, &results)
+	if params.IsCodeNotImplemented(err) {
+		return ru.unit.PrivateAddress()
+	} else if err != nil {
+		return "", err
+	}
+	if len(results.Results) != 1 {
+		return "", fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return "", result.Error
+	}
+	return result.Result, nil
 }
 
 // EnterScope ensures that the unit has entered its scope in the relation.
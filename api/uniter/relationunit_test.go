@@ -105,6 +105,25 @@ func (s *relationUnitSuite) TestPrivateAddress(c *gc.C) {
 	c.Assert(address, gc.Equals, "1.2.3.4")
 }
 
+func (s *relationUnitSuite) TestPrivateAddressBoundToSpace(c *gc.C) {
+	_, apiRelUnit := s.getRelationUnits(c)
+	err := s.wordpressMachine.SetAddresses(
+		network.NewAddress("1.2.3.4", network.ScopeCloudLocal),
+		network.NewAddress("192.168.1.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpressService.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	address, err := apiRelUnit.PrivateAddress()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(address, gc.Equals, "192.168.1.4")
+}
+
 func (s *relationUnitSuite) TestEnterScopeSuccessfully(c *gc.C) {
 	// NOTE: This test is not as exhaustive as the ones in state.
 	// Here, we just check the success case, while the two error
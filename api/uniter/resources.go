@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+)
+
+// Resources returns the current revision of each resource attached to
+// the service.
+func (s *Service) Resources() ([]params.ServiceResource, error) {
+	if err := ErrIfNotVersionFn(2, s.st.BestAPIVersion())("Resources"); err != nil {
+		return nil, err
+	}
+	var results params.ServiceResourcesResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: s.tag.String()}},
+	}
+	err := s.st.facade.FacadeCall("ServiceResources", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, errors.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return result.Resources, nil
+}
+
+// OpenResource returns a reader for the content of the current revision
+// of the named resource. The caller is responsible for closing it.
+func (s *Service) OpenResource(name string) (io.ReadCloser, error) {
+	if s.st.http == nil {
+		return nil, errors.NotSupportedf("downloading resources")
+	}
+	args := params.ResourceDownloadArgs{
+		ServiceName: s.Name(),
+		Name:        name,
+	}
+	_, resp, err := s.st.http.SendHTTPRequest("resources", &args)
+	if err != nil {
+		return nil, errors.Annotate(err, "while sending HTTP request")
+	}
+	if resp.StatusCode != http.StatusOK {
+		failure, err := apihttp.ExtractAPIError(resp)
+		if err != nil {
+			return nil, errors.Annotate(err, "while extracting failure")
+		}
+		return nil, errors.Trace(failure)
+	}
+	return resp.Body, nil
+}
@@ -4,6 +4,9 @@
 package uniter_test
 
 import (
+	"io/ioutil"
+	"strings"
+
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
@@ -143,3 +146,33 @@ func (s *serviceSuite) patchNewState(
 	s.apiService, err = s.uniter.Service(s.wordpressService.Tag().(names.ServiceTag))
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+func (s *serviceSuite) TestResources(c *gc.C) {
+	resources, err := s.apiService.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 0)
+
+	_, err = s.wordpressService.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+	resources, err = s.apiService.Resources()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resources, gc.HasLen, 1)
+	c.Assert(resources[0].Name, gc.Equals, "jar")
+	c.Assert(resources[0].Revision, gc.Equals, 1)
+}
+
+func (s *serviceSuite) TestOpenResource(c *gc.C) {
+	_, err := s.wordpressService.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+
+	reader, err := s.apiService.OpenResource("jar")
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "content")
+
+	_, err = s.apiService.OpenResource("missing")
+	c.Assert(err, gc.ErrorMatches, `resource "missing" of service "wordpress" not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
@@ -5,6 +5,7 @@ package uniter
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -29,6 +30,16 @@ type State struct {
 	facade             base.FacadeCaller
 	// unitTag contains the authenticated unit's tag.
 	unitTag names.UnitTag
+	// http is used to download resource content; it is nil if the
+	// API caller cannot make direct HTTP requests.
+	http httpClient
+}
+
+// httpClient represents the methods of api.State (see api/http.go)
+// needed by the uniter for direct HTTP requests.
+type httpClient interface {
+	// SendHTTPRequest sends an HTTP GET request relative to the client.
+	SendHTTPRequest(path string, args interface{}) (*http.Request, *http.Response, error)
 }
 
 // newStateForVersion creates a new client-side Uniter facade for the
@@ -50,6 +61,9 @@ func newStateForVersion(
 		facade:          facadeCaller,
 		unitTag:         authTag,
 	}
+	if httpCaller, ok := caller.(httpClient); ok {
+		state.http = httpCaller
+	}
 
 	if version >= 2 {
 		newWatcher := func(result params.NotifyWatchResult) watcher.NotifyWatcher {
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package watcher
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type streamHandlerSuite struct{}
+
+var _ = gc.Suite(&streamHandlerSuite{})
+
+func (s *streamHandlerSuite) TestNotifyResultsCoalesced(c *gc.C) {
+	handler := newStreamHandler(func() interface{} {
+		return new(params.NotifyWatchResult)
+	}, mergeNotifyResults)
+	for i := 0; i < 3; i++ {
+		handler.HandlePush(handler.NewBody())
+	}
+	c.Assert(handler.take(), gc.NotNil)
+	c.Assert(handler.take(), gc.IsNil)
+}
+
+func (s *streamHandlerSuite) TestNotifyErrorKept(c *gc.C) {
+	handler := newStreamHandler(func() interface{} {
+		return new(params.NotifyWatchResult)
+	}, mergeNotifyResults)
+	handler.HandlePush(&params.NotifyWatchResult{Error: &params.Error{Message: "boom"}})
+	handler.HandlePush(&params.NotifyWatchResult{})
+	result := handler.take().(*params.NotifyWatchResult)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
+
+func (s *streamHandlerSuite) TestStringsResultsMerged(c *gc.C) {
+	handler := newStreamHandler(func() interface{} {
+		return new(params.StringsWatchResult)
+	}, mergeStringsResults)
+	handler.HandlePush(&params.StringsWatchResult{Changes: []string{"a", "b"}})
+	handler.HandlePush(&params.StringsWatchResult{Changes: []string{"b", "c"}})
+	handler.HandlePush(&params.StringsWatchResult{Changes: []string{"a", "d"}})
+	result := handler.take().(*params.StringsWatchResult)
+	c.Assert(result.Changes, jc.DeepEquals, []string{"a", "b", "c", "d"})
+	c.Assert(handler.take(), gc.IsNil)
+}
+
+func (s *streamHandlerSuite) TestStringsErrorKept(c *gc.C) {
+	handler := newStreamHandler(func() interface{} {
+		return new(params.StringsWatchResult)
+	}, mergeStringsResults)
+	handler.HandlePush(&params.StringsWatchResult{Changes: []string{"a"}})
+	handler.HandlePush(&params.StringsWatchResult{Error: &params.Error{Message: "boom"}})
+	handler.HandlePush(&params.StringsWatchResult{Changes: []string{"b"}})
+	result := handler.take().(*params.StringsWatchResult)
+	c.Assert(result.Error, gc.ErrorMatches, "boom")
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 
 	"github.com/juju/loggo"
+	"github.com/juju/utils/set"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/api/base"
@@ -119,6 +120,151 @@ func (w *commonWatcher) commonLoop() {
 	wg.Wait()
 }
 
+// streamHandler implements rpc.PushHandler, coalescing the results
+// pushed by a streaming watcher so that the RPC connection is never
+// blocked waiting for the watcher to consume them, and a slow consumer
+// only ever sees a single pending result.
+type streamHandler struct {
+	newResult func() interface{}
+	merge     func(pending, result interface{}) interface{}
+	mu        sync.Mutex
+	pending   interface{}
+	ready     chan struct{}
+}
+
+// newStreamHandler returns a streamHandler that uses merge to combine
+// a newly pushed result into the one still waiting to be taken.
+func newStreamHandler(newResult func() interface{}, merge func(pending, result interface{}) interface{}) *streamHandler {
+	return &streamHandler{
+		newResult: newResult,
+		merge:     merge,
+		ready:     make(chan struct{}, 1),
+	}
+}
+
+// NewBody implements rpc.PushHandler.
+func (h *streamHandler) NewBody() interface{} {
+	return h.newResult()
+}
+
+// HandlePush implements rpc.PushHandler.
+func (h *streamHandler) HandlePush(body interface{}) {
+	h.mu.Lock()
+	if h.pending == nil {
+		h.pending = body
+	} else {
+		h.pending = h.merge(h.pending, body)
+	}
+	h.mu.Unlock()
+	select {
+	case h.ready <- struct{}{}:
+	default:
+	}
+}
+
+// take returns the result pushed since it was last called, or nil
+// if there is none.
+func (h *streamHandler) take() interface{} {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	pending := h.pending
+	h.pending = nil
+	return pending
+}
+
+// mergeNotifyResults merges two pushed NotifyWatchResults; a notify
+// watcher has no change content, so one pending event stands for any
+// number of changes.
+func mergeNotifyResults(pending, result interface{}) interface{} {
+	if pending.(*params.NotifyWatchResult).Error != nil {
+		return pending
+	}
+	return result
+}
+
+// mergeStringsResults merges two pushed StringsWatchResults, adding
+// any strings not already pending to the pending changes.
+func mergeStringsResults(pending, result interface{}) interface{} {
+	p := pending.(*params.StringsWatchResult)
+	if p.Error != nil {
+		return p
+	}
+	r := result.(*params.StringsWatchResult)
+	if r.Error != nil {
+		return r
+	}
+	seen := set.NewStrings(p.Changes...)
+	for _, change := range r.Changes {
+		if !seen.Contains(change) {
+			seen.Add(change)
+			p.Changes = append(p.Changes, change)
+		}
+	}
+	return p
+}
+
+// makeWatcherSubscriber returns the Subscriber that should be used
+// to stream changes from a watcher on the given facade, or nil if
+// either the connection or the API server cannot stream them, in
+// which case Next must be called to get each change.
+func makeWatcherSubscriber(caller base.APICaller, facadeName string) base.Subscriber {
+	if caller.BestFacadeVersion(facadeName) < 1 {
+		return nil
+	}
+	subscriber, _ := caller.(base.Subscriber)
+	return subscriber
+}
+
+// commonStreamLoop is like commonLoop, except that it receives
+// results pushed by the API server rather than calling Next. The
+// resultError function must return the error held in a pushed
+// result, if any; a result holding an error is the last one the
+// server will send. The merge function combines results pushed
+// before the watcher has consumed the pending one.
+func (w *commonWatcher) commonStreamLoop(subscriber base.Subscriber, watcherId string, resultError func(interface{}) *params.Error, merge func(pending, result interface{}) interface{}) {
+	defer close(w.in)
+	defer func() {
+		// Whether the watcher has been stopped by the client
+		// or has failed on the server, send a Stop request so
+		// its resources are cleaned up.
+		if err := w.call("Stop", nil); err != nil {
+			logger.Errorf("error trying to stop watcher: %v", err)
+		}
+	}()
+	handler := newStreamHandler(w.newResult, merge)
+	subscriber.Subscribe(watcherId, handler)
+	defer subscriber.Unsubscribe(watcherId)
+	if err := w.call("Stream", nil); err != nil {
+		w.tomb.Kill(err)
+		return
+	}
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return
+		case <-handler.ready:
+		}
+		result := handler.take()
+		if result == nil {
+			continue
+		}
+		if err := resultError(result); err != nil {
+			if params.IsCodeStopped(err) && w.tomb.Err() != tomb.ErrStillAlive {
+				// The watcher has been stopped at the client end.
+				w.tomb.Kill(tomb.ErrDying)
+			} else {
+				w.tomb.Kill(err)
+			}
+			return
+		}
+		select {
+		case <-w.tomb.Dying():
+			return
+		case w.in <- result:
+		}
+	}
+}
+
 func (w *commonWatcher) Stop() error {
 	w.tomb.Kill(nil)
 	return w.tomb.Wait()
@@ -154,11 +300,20 @@ func NewNotifyWatcher(caller base.APICaller, result params.NotifyWatchResult) No
 }
 
 func (w *notifyWatcher) loop() error {
-	// No results for this watcher type.
-	w.newResult = func() interface{} { return nil }
 	w.call = makeWatcherAPICaller(w.caller, "NotifyWatcher", w.notifyWatcherId)
-	w.commonWatcher.init()
-	go w.commonLoop()
+	if subscriber := makeWatcherSubscriber(w.caller, "NotifyWatcher"); subscriber != nil {
+		// Streamed results report any error from the watcher.
+		w.newResult = func() interface{} { return new(params.NotifyWatchResult) }
+		w.commonWatcher.init()
+		go w.commonStreamLoop(subscriber, w.notifyWatcherId, func(result interface{}) *params.Error {
+			return result.(*params.NotifyWatchResult).Error
+		}, mergeNotifyResults)
+	} else {
+		// No results for this watcher type.
+		w.newResult = func() interface{} { return nil }
+		w.commonWatcher.init()
+		go w.commonLoop()
+	}
 
 	for {
 		select {
@@ -210,7 +365,13 @@ func (w *stringsWatcher) loop(initialChanges []string) error {
 	w.newResult = func() interface{} { return new(params.StringsWatchResult) }
 	w.call = makeWatcherAPICaller(w.caller, "StringsWatcher", w.stringsWatcherId)
 	w.commonWatcher.init()
-	go w.commonLoop()
+	if subscriber := makeWatcherSubscriber(w.caller, "StringsWatcher"); subscriber != nil {
+		go w.commonStreamLoop(subscriber, w.stringsWatcherId, func(result interface{}) *params.Error {
+			return result.(*params.StringsWatchResult).Error
+		}, mergeStringsResults)
+	} else {
+		go w.commonLoop()
+	}
 
 	for {
 		select {
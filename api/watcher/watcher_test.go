@@ -11,6 +11,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/api/watcher"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/juju/testing"
@@ -83,6 +84,96 @@ func (s *watcherSuite) TestWatchMachine(c *gc.C) {
 	wc.AssertClosed()
 }
 
+// pollingCaller hides the ability of an APICaller to receive pushed
+// messages, so that watchers fall back to calling Next.
+type pollingCaller struct {
+	base.APICaller
+}
+
+func (s *watcherSuite) watchMachine(c *gc.C) params.NotifyWatchResult {
+	var results params.NotifyWatchResults
+	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
+	err := s.stateAPI.APICall("Machiner", s.stateAPI.BestFacadeVersion("Machiner"), "", "Watch", args, &results)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	result := results.Results[0]
+	c.Assert(result.Error, gc.IsNil)
+	return result
+}
+
+func (s *watcherSuite) assertNotifyWatcherChanges(c *gc.C, caller base.APICaller) {
+	w := watcher.NewNotifyWatcher(caller, s.watchMachine(c))
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	err := s.rawMachine.SetProvisioned("i-manual", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	statetesting.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
+func (s *watcherSuite) TestNotifyWatcherStreamsChanges(c *gc.C) {
+	s.assertNotifyWatcherChanges(c, s.stateAPI)
+}
+
+func (s *watcherSuite) TestNotifyWatcherPollsWithoutStreaming(c *gc.C) {
+	s.assertNotifyWatcherChanges(c, pollingCaller{s.stateAPI})
+}
+
+func (s *watcherSuite) TestNotifyWatcherStream(c *gc.C) {
+	// Once a watcher is streaming, the server pushes each change
+	// on a subscription named by the watcher id.
+	result := s.watchMachine(c)
+	pushed := make(chan *params.NotifyWatchResult, 1)
+	s.stateAPI.Subscribe(result.NotifyWatcherId, &pushHandler{pushed})
+	defer s.stateAPI.Unsubscribe(result.NotifyWatcherId)
+	version := s.stateAPI.BestFacadeVersion("NotifyWatcher")
+	c.Assert(version, gc.Equals, 1)
+	err := s.stateAPI.APICall("NotifyWatcher", version, result.NotifyWatcherId, "Stream", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Streaming a watcher twice is an error.
+	err = s.stateAPI.APICall("NotifyWatcher", version, result.NotifyWatcherId, "Stream", nil, nil)
+	c.Assert(err, gc.ErrorMatches, "watcher is already streaming")
+
+	err = s.rawMachine.SetProvisioned("i-manual", "fake_nonce", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.BackingState.StartSync()
+	select {
+	case change := <-pushed:
+		c.Assert(change, jc.DeepEquals, &params.NotifyWatchResult{
+			NotifyWatcherId: result.NotifyWatcherId,
+		})
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for pushed change")
+	}
+
+	// When the watcher is stopped, a final result holding
+	// the error is pushed.
+	err = s.stateAPI.APICall("NotifyWatcher", version, result.NotifyWatcherId, "Stop", nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case change := <-pushed:
+		c.Assert(change.Error, jc.Satisfies, params.IsCodeStopped)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for final result")
+	}
+}
+
+type pushHandler struct {
+	pushed chan *params.NotifyWatchResult
+}
+
+func (h *pushHandler) NewBody() interface{} {
+	return new(params.NotifyWatchResult)
+}
+
+func (h *pushHandler) HandlePush(body interface{}) {
+	h.pushed <- body.(*params.NotifyWatchResult)
+}
+
 func (s *watcherSuite) TestNotifyWatcherStopsWithPendingSend(c *gc.C) {
 	var results params.NotifyWatchResults
 	args := params.Entities{Entities: []params.Entity{{Tag: s.rawMachine.Tag().String()}}}
@@ -207,6 +207,14 @@ func (a *admin) doLogin(req params.LoginRequest) (params.LoginResultV1, error) {
 		return fail, err
 	}
 
+	if !isUser {
+		// Agents are limited in the rate at which they may
+		// make requests, so that they can't overwhelm the
+		// server when they all reconnect at once.
+		if bucket := a.srv.newRequestBucket(); bucket != nil {
+			authedApi = newRateLimitedRoot(authedApi, bucket)
+		}
+	}
 	a.root.rpcConn.ServeFinder(authedApi, serverError)
 
 	return params.LoginResultV1{
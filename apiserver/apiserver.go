@@ -18,6 +18,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
+	"github.com/juju/ratelimit"
 	"github.com/juju/utils"
 	"launchpad.net/tomb"
 
@@ -26,12 +27,13 @@ import (
 	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/rpc/jsoncodec"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
 )
 
 var logger = loggo.GetLogger("juju.apiserver")
 
 // loginRateLimit defines how many concurrent Login requests we will
-// accept
+// accept by default.
 const loginRateLimit = 10
 
 // Server holds the server side of the API.
@@ -44,11 +46,15 @@ type Server struct {
 	dataDir           string
 	logDir            string
 	limiter           utils.Limiter
+	requestRate       float64
+	requestBurst      int64
 	validator         LoginValidator
+	workerReporter    worker.Reporter
 	adminApiFactories map[int]adminApiFactory
 
 	mu          sync.Mutex // protects the fields that follow
 	environUUID string
+	conns       map[int64]*requestNotifier
 }
 
 // LoginValidator functions are used to decide whether login requests
@@ -65,6 +71,25 @@ type ServerConfig struct {
 	LogDir      string
 	Validator   LoginValidator
 	CertChanged chan params.StateServingInfo
+
+	// LoginRateLimit holds the number of agent logins that may
+	// be in progress at once. Further logins fail with a "try
+	// again" error. If it is zero, loginRateLimit is used.
+	LoginRateLimit int
+
+	// AgentRequestRate holds the number of requests per second
+	// that each logged in agent may make, and AgentRequestBurst
+	// holds the number that may be made at once after a quiet
+	// period. Requests over the limit are delayed, or fail with
+	// a "try again" error if they would be delayed too long.
+	// If AgentRequestRate is zero, requests are not limited; if
+	// AgentRequestBurst is zero, a second's worth is allowed.
+	AgentRequestRate  float64
+	AgentRequestBurst int
+
+	// WorkerReporter, if not nil, is used to report on the
+	// workers run by the agent running the server.
+	WorkerReporter worker.Reporter
 }
 
 // changeCertListener wraps a TLS net.Listener.
@@ -180,14 +205,30 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 	if err != nil {
 		return nil, err
 	}
+	loginLimit := cfg.LoginRateLimit
+	if loginLimit <= 0 {
+		loginLimit = loginRateLimit
+	}
+	requestBurst := int64(cfg.AgentRequestBurst)
+	if requestBurst <= 0 {
+		// Allow a second's worth of requests at once.
+		requestBurst = int64(cfg.AgentRequestRate)
+		if requestBurst < 1 {
+			requestBurst = 1
+		}
+	}
 	srv := &Server{
-		state:     s,
-		addr:      net.JoinHostPort("localhost", listeningPort),
-		tag:       cfg.Tag,
-		dataDir:   cfg.DataDir,
-		logDir:    cfg.LogDir,
-		limiter:   utils.NewLimiter(loginRateLimit),
-		validator: cfg.Validator,
+		state:          s,
+		addr:           net.JoinHostPort("localhost", listeningPort),
+		tag:            cfg.Tag,
+		dataDir:        cfg.DataDir,
+		logDir:         cfg.LogDir,
+		limiter:        utils.NewLimiter(loginLimit),
+		requestRate:    cfg.AgentRequestRate,
+		requestBurst:   requestBurst,
+		validator:      cfg.Validator,
+		workerReporter: cfg.WorkerReporter,
+		conns:          make(map[int64]*requestNotifier),
 		adminApiFactories: map[int]adminApiFactory{
 			0: newAdminApiV0,
 			1: newAdminApiV1,
@@ -204,6 +245,16 @@ func NewServer(s *state.State, lis net.Listener, cfg ServerConfig) (*Server, err
 	return srv, nil
 }
 
+// newRequestBucket returns a token bucket for limiting the rate
+// of requests made by a single agent, or nil if requests are
+// not limited.
+func (srv *Server) newRequestBucket() *ratelimit.Bucket {
+	if srv.requestRate <= 0 {
+		return nil
+	}
+	return ratelimit.NewBucketWithRate(srv.requestRate, srv.requestBurst)
+}
+
 // Dead returns a channel that signals when the server has exited.
 func (srv *Server) Dead() <-chan struct{} {
 	return srv.tomb.Dead()
@@ -230,16 +281,20 @@ type requestNotifier struct {
 	id    int64
 	start time.Time
 
-	mu   sync.Mutex
-	tag_ string
+	mu         sync.Mutex
+	tag_       string
+	remoteAddr string
 }
 
 var globalCounter int64
 
+// unknownTag is logged for connections that have not logged in.
+const unknownTag = "<unknown>"
+
 func newRequestNotifier() *requestNotifier {
 	return &requestNotifier{
 		id:    atomic.AddInt64(&globalCounter, 1),
-		tag_:  "<unknown>",
+		tag_:  unknownTag,
 		start: time.Now(),
 	}
 }
@@ -280,6 +335,9 @@ func (n *requestNotifier) ServerReply(req rpc.Request, hdr *rpc.Header, body int
 }
 
 func (n *requestNotifier) join(req *http.Request) {
+	n.mu.Lock()
+	n.remoteAddr = req.RemoteAddr
+	n.mu.Unlock()
 	logger.Infof("[%X] API connection from %s", n.id, req.RemoteAddr)
 }
 
@@ -353,6 +411,12 @@ func (srv *Server) run(lis net.Listener) {
 			stateServerEnvOnly: true,
 		}},
 	)
+	handleAll(mux, "/environment/:envuuid/resources",
+		&resourcesHandler{httpHandler{ssState: srv.state}},
+	)
+	charmStore := &charmStoreHandler{httpHandler{ssState: srv.state}}
+	handleAll(mux, "/environment/:envuuid/charmstore", charmStore)
+	handleAll(mux, "/environment/:envuuid/charmstore/", charmStore)
 	handleAll(mux, "/environment/:envuuid/api", http.HandlerFunc(srv.apiHandler))
 	handleAll(mux, "/environment/:envuuid/images/:kind/:series/:arch/:filename",
 		&imagesDownloadHandler{httpHandler{ssState: srv.state}},
@@ -387,6 +451,8 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	reqNotifier := newRequestNotifier()
 	reqNotifier.join(req)
 	defer reqNotifier.leave()
+	srv.addConn(reqNotifier)
+	defer srv.removeConn(reqNotifier)
 	wsServer := websocket.Server{
 		Handler: func(conn *websocket.Conn) {
 			srv.wg.Add(1)
@@ -408,13 +474,34 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	wsServer.ServeHTTP(w, req)
 }
 
+// addConn records that the given connection is open.
+func (srv *Server) addConn(n *requestNotifier) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.conns[n.id] = n
+}
+
+// removeConn records that the given connection has closed.
+func (srv *Server) removeConn(n *requestNotifier) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	delete(srv.conns, n.id)
+}
+
 // Addr returns the address that the server is listening on.
 func (srv *Server) Addr() string {
 	return srv.addr
 }
 
 func (srv *Server) serveConn(wsConn *websocket.Conn, reqNotifier *requestNotifier, envUUID string) error {
-	codec := jsoncodec.NewWebsocket(wsConn)
+	var codec *jsoncodec.Codec
+	if jsoncodec.AcceptsCompression(wsConn.Request()) {
+		// The client has told us it can read compressed
+		// messages, so large responses will be sent deflated.
+		codec = jsoncodec.NewWebsocketCompressed(wsConn)
+	} else {
+		codec = jsoncodec.NewWebsocket(wsConn)
+	}
 	if loggo.GetLogger("juju.rpc.jsoncodec").EffectiveLogLevel() <= loggo.TRACE {
 		codec.SetLogging(true)
 	}
@@ -56,13 +56,19 @@ func (api *CharmRevisionUpdaterAPI) UpdateLatestRevisions() (params.ErrorResult,
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
 	uuid := env.UUID()
+	envConfig, err := api.state.EnvironConfig()
+	if err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
+	publicStore := charm.Store.WithJujuAttrs("environment_uuid=" + uuid)
+	repo := common.CharmStoreRepository(api.state, envConfig, publicStore)
 
 	deployedCharms, err := fetchAllDeployedCharms(api.state)
 	if err != nil {
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
 	// Look up the revision information for all the deployed charms.
-	curls, err := retrieveLatestCharmInfo(deployedCharms, uuid)
+	curls, err := retrieveLatestCharmInfo(deployedCharms, repo)
 	if err != nil {
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
@@ -93,9 +99,9 @@ func fetchAllDeployedCharms(st *state.State) (map[string]*charm.URL, error) {
 	return deployedCharms, nil
 }
 
-// retrieveLatestCharmInfo looks up the charm store to return the charm URLs for the
-// latest revision of the deployed charms.
-func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, uuid string) ([]*charm.URL, error) {
+// retrieveLatestCharmInfo looks up the charm repository to return the charm URLs
+// for the latest revision of the deployed charms.
+func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, repo charm.Repository) ([]*charm.URL, error) {
 	var curls []*charm.URL
 	for _, curl := range deployedCharms {
 		if curl.Schema == "local" {
@@ -109,8 +115,7 @@ func retrieveLatestCharmInfo(deployedCharms map[string]*charm.URL, uuid string)
 
 	// Do a bulk call to get the revision info for all charms.
 	logger.Infof("retrieving revision information for %d charms", len(curls))
-	store := charm.Store.WithJujuAttrs("environment_uuid=" + uuid)
-	revInfo, err := store.Latest(curls...)
+	revInfo, err := repo.Latest(curls...)
 	if err != nil {
 		err = errors.Annotate(err, "finding charm revision info")
 		logger.Infof(err.Error())
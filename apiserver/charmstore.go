@@ -0,0 +1,218 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/charmmirror"
+)
+
+// charmStoreHandler serves the state server's charm mirror using the
+// charm store protocol, so that environments without access to the
+// public charm store can set charm-store-url to point at it. Like the
+// public charm store, the mirror can be read without authentication;
+// charms can only be added by users of the state server environment.
+type charmStoreHandler struct {
+	httpHandler
+}
+
+func (h *charmStoreHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	stateWrapper, err := h.validateEnvironUUID(r)
+	if err != nil {
+		h.sendError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	// The path below the mirror's root selects the operation.
+	path := r.URL.Path
+	path = path[strings.Index(path, "/charmstore")+len("/charmstore"):]
+	switch {
+	case r.Method == "POST" && (path == "" || path == "/"):
+		if err := stateWrapper.authenticate(r); err != nil {
+			h.authError(w, h)
+			return
+		}
+		if stateWrapper.state.EnvironUUID() != h.ssState.EnvironUUID() {
+			h.sendError(w, http.StatusForbidden, "charms can only be mirrored through the state server environment")
+			return
+		}
+		curl, err := h.processPost(r, stateWrapper.state)
+		if err != nil {
+			h.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		h.sendJSON(w, http.StatusOK, &params.CharmsResponse{CharmURL: curl.String()})
+	case r.Method == "GET" && path == "/charm-info":
+		if err := h.serveInfo(w, r, stateWrapper.state); err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+		}
+	case r.Method == "GET" && strings.HasPrefix(path, "/charm/"):
+		err := h.serveArchive(w, stateWrapper.state, strings.TrimPrefix(path, "/charm/"))
+		if errors.IsNotFound(err) || errors.IsNotValid(err) {
+			h.sendError(w, http.StatusNotFound, err.Error())
+		} else if err != nil {
+			h.sendError(w, http.StatusInternalServerError, err.Error())
+		}
+	case r.Method == "GET" || r.Method == "POST":
+		h.sendError(w, http.StatusNotFound, fmt.Sprintf("%q not found", r.URL.Path))
+	default:
+		h.sendError(w, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", r.Method))
+	}
+}
+
+// serveInfo answers a charm-info request, describing the latest
+// mirrored revision of each requested charm.
+func (h *charmStoreHandler) serveInfo(w http.ResponseWriter, r *http.Request, st *state.State) error {
+	mirror, err := st.CharmMirror()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer mirror.Close()
+
+	response := make(map[string]*charm.InfoResponse)
+	for _, location := range r.URL.Query()["charms"] {
+		info := &charm.InfoResponse{}
+		response[location] = info
+		ref, err := charm.ParseReference(location)
+		if err != nil {
+			info.Errors = append(info.Errors, err.Error())
+			continue
+		}
+		metadata, err := common.ResolveMirroredCharm(mirror, ref)
+		if errors.IsNotFound(err) {
+			info.Errors = append(info.Errors, "entry not found")
+			continue
+		} else if err != nil {
+			info.Errors = append(info.Errors, err.Error())
+			continue
+		}
+		info.CanonicalURL = metadata.URL.String()
+		info.Revision = metadata.URL.Revision
+		info.Sha256 = metadata.SHA256
+		info.Digest = metadata.SHA256
+	}
+	body, err := json.Marshal(response)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(http.StatusOK)
+	w.Write(body)
+	return nil
+}
+
+// serveArchive sends the archive of the mirrored charm with the given
+// path, as returned by charm.URL.Path.
+func (h *charmStoreHandler) serveArchive(w http.ResponseWriter, st *state.State, path string) error {
+	curl, err := charm.ParseURL("cs:" + path)
+	if err != nil {
+		return errors.NewNotValid(err, "")
+	}
+	mirror, err := st.CharmMirror()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer mirror.Close()
+
+	metadata, reader, err := mirror.Charm(curl)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer reader.Close()
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Length", fmt.Sprint(metadata.Size))
+	w.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(w, reader); err != nil {
+		// The response has already started, so the error can
+		// only be logged.
+		logger.Errorf("while sending mirrored charm %q: %v", curl, err)
+	}
+	return nil
+}
+
+// processPost adds the uploaded charm archive to the mirror under the
+// charm store URL given in the "url" query argument.
+func (h *charmStoreHandler) processPost(r *http.Request, st *state.State) (*charm.URL, error) {
+	curl, err := charm.ParseURL(r.URL.Query().Get("url"))
+	if err != nil {
+		return nil, errors.Annotate(err, "expected url=URL argument")
+	}
+	if curl.Schema != "cs" || curl.Revision < 0 {
+		return nil, errors.Errorf("expected charm store URL with revision, got %q", curl)
+	}
+	contentType := r.Header.Get("Content-Type")
+	if contentType != "application/zip" {
+		return nil, errors.Errorf("expected Content-Type: application/zip, got: %v", contentType)
+	}
+	tempFile, err := ioutil.TempFile("", "charm")
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create temp file")
+	}
+	defer tempFile.Close()
+	defer os.Remove(tempFile.Name())
+	hasher := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tempFile, hasher), r.Body)
+	if err != nil {
+		return nil, errors.Annotate(err, "error processing file upload")
+	}
+	archive, err := charm.ReadCharmArchive(tempFile.Name())
+	if err != nil {
+		return nil, errors.Annotate(err, "invalid charm archive")
+	}
+	if name := archive.Meta().Name; name != curl.Name {
+		return nil, errors.Errorf("charm name %q does not match URL %q", name, curl)
+	}
+	if _, err := tempFile.Seek(0, 0); err != nil {
+		return nil, errors.Annotate(err, "cannot rewind charm archive")
+	}
+
+	mirror, err := st.CharmMirror()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer mirror.Close()
+	err = mirror.AddCharm(tempFile, charmmirror.Metadata{
+		URL:    curl,
+		Size:   size,
+		SHA256: fmt.Sprintf("%x", hasher.Sum(nil)),
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("mirrored charm %q", curl)
+	return curl, nil
+}
+
+// sendJSON sends a JSON-encoded response to the client.
+func (h *charmStoreHandler) sendJSON(w http.ResponseWriter, statusCode int, response *params.CharmsResponse) {
+	body, err := json.Marshal(response)
+	if err != nil {
+		logger.Errorf("failed to serialize the response (%v): %v", response, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *charmStoreHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendJSON(w, statusCode, &params.CharmsResponse{Error: message})
+}
@@ -0,0 +1,154 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"runtime"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/testcharms"
+)
+
+type charmStoreSuite struct {
+	authHttpSuite
+}
+
+var _ = gc.Suite(&charmStoreSuite{})
+
+func (s *charmStoreSuite) SetUpSuite(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Skipping this on windows for now")
+	}
+	s.authHttpSuite.SetUpSuite(c)
+	s.archiveContentType = "application/zip"
+}
+
+func (s *charmStoreSuite) charmStoreURL(c *gc.C, path, query string) *url.URL {
+	u := s.baseURL(c)
+	u.Path = fmt.Sprintf("/environment/%s/charmstore%s", s.envUUID, path)
+	u.RawQuery = query
+	return u
+}
+
+// uploadDummy uploads the dummy charm to the mirror as curl, and
+// returns the response along with the path of the uploaded archive.
+func (s *charmStoreSuite) uploadDummy(c *gc.C, curl string) (*http.Response, string) {
+	ch := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	uri := s.charmStoreURL(c, "", "url="+url.QueryEscape(curl)).String()
+	resp, err := s.uploadRequest(c, uri, true, ch.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp, ch.Path
+}
+
+func (s *charmStoreSuite) TestPOSTRequiresAuth(c *gc.C) {
+	uri := s.charmStoreURL(c, "", "url=cs:quantal/dummy-1").String()
+	resp, err := s.sendRequest(c, "", "", "POST", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *charmStoreSuite) TestRequiresKnownEnvironment(c *gc.C) {
+	s.envUUID = "dead-beef-123456"
+	resp, err := s.sendRequest(c, "", "", "GET", s.charmStoreURL(c, "/charm-info", "").String(), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `unknown environment: "dead-beef-123456"`)
+}
+
+func (s *charmStoreSuite) TestUnsupportedMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "PUT", s.charmStoreURL(c, "", "").String(), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusMethodNotAllowed, `unsupported method: "PUT"`)
+}
+
+func (s *charmStoreSuite) TestUploadAndServe(c *gc.C) {
+	resp, path := s.uploadDummy(c, "cs:quantal/dummy-3")
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	c.Assert(jsonResponse(c, body).CharmURL, gc.Equals, "cs:quantal/dummy-3")
+
+	// The archive can be downloaded anonymously.
+	uri := s.charmStoreURL(c, "/charm/quantal/dummy-3", "").String()
+	resp, err := s.sendRequest(c, "", "", "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	data := assertResponse(c, resp, http.StatusOK, "application/zip")
+	expected, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, gc.DeepEquals, expected)
+}
+
+func (s *charmStoreSuite) TestCharmInfo(c *gc.C) {
+	resp, path := s.uploadDummy(c, "cs:quantal/dummy-3")
+	assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	hash, _, err := utils.ReadFileSHA256(path)
+	c.Assert(err, jc.ErrorIsNil)
+
+	query := url.Values{"charms": {"cs:dummy", "cs:quantal/dummy-3", "cs:quantal/wordpress"}}
+	uri := s.charmStoreURL(c, "/charm-info", query.Encode()).String()
+	resp, err = s.sendRequest(c, "", "", "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	var info map[string]*charm.InfoResponse
+	err = json.Unmarshal(body, &info)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, map[string]*charm.InfoResponse{
+		"cs:dummy": {
+			CanonicalURL: "cs:quantal/dummy-3",
+			Revision:     3,
+			Sha256:       hash,
+			Digest:       hash,
+		},
+		"cs:quantal/dummy-3": {
+			CanonicalURL: "cs:quantal/dummy-3",
+			Revision:     3,
+			Sha256:       hash,
+			Digest:       hash,
+		},
+		"cs:quantal/wordpress": {
+			Errors: []string{"entry not found"},
+		},
+	})
+}
+
+func (s *charmStoreSuite) TestServeArchiveNotFound(c *gc.C) {
+	uri := s.charmStoreURL(c, "/charm/quantal/dummy-1", "").String()
+	resp, err := s.sendRequest(c, "", "", "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertErrorResponse(c, resp, http.StatusNotFound, `charm "cs:quantal/dummy-1" not found`)
+}
+
+func (s *charmStoreSuite) TestUploadRequiresRevision(c *gc.C) {
+	resp, _ := s.uploadDummy(c, "cs:quantal/dummy")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `expected charm store URL with revision, got "cs:quantal/dummy"`)
+}
+
+func (s *charmStoreSuite) TestUploadRejectsNameMismatch(c *gc.C) {
+	resp, _ := s.uploadDummy(c, "cs:quantal/mysql-1")
+	s.assertErrorResponse(c, resp, http.StatusBadRequest, `charm name "dummy" does not match URL "cs:quantal/mysql-1"`)
+}
+
+func (s *charmStoreSuite) TestUploadFromHostedEnvironmentForbidden(c *gc.C) {
+	s.setupOtherEnvironment(c)
+	resp, _ := s.uploadDummy(c, "cs:quantal/dummy-1")
+	s.assertErrorResponse(c, resp, http.StatusForbidden, "charms can only be mirrored through the state server environment")
+}
+
+func (s *charmStoreSuite) TestMirrorReadableFromHostedEnvironment(c *gc.C) {
+	resp, _ := s.uploadDummy(c, "cs:quantal/dummy-1")
+	assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+
+	s.setupOtherEnvironment(c)
+	uri := s.charmStoreURL(c, "/charm/quantal/dummy-1", "").String()
+	resp, err := s.sendRequest(c, "", "", "GET", uri, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	assertResponse(c, resp, http.StatusOK, "application/zip")
+}
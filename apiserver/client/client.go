@@ -388,14 +388,15 @@ func (c *Client) ServiceDeploy(args params.ServiceDeploy) error {
 		jjj.DeployServiceParams{
 			ServiceName: args.ServiceName,
 			// TODO(dfc) ServiceOwner should be a tag
-			ServiceOwner:   c.api.auth.GetAuthTag().String(),
-			Charm:          ch,
-			NumUnits:       args.NumUnits,
-			ConfigSettings: settings,
-			Constraints:    args.Constraints,
-			ToMachineSpec:  args.ToMachineSpec,
-			Networks:       requestedNetworks,
-			Storage:        storageConstraints,
+			ServiceOwner:     c.api.auth.GetAuthTag().String(),
+			Charm:            ch,
+			NumUnits:         args.NumUnits,
+			ConfigSettings:   settings,
+			Constraints:      args.Constraints,
+			ToMachineSpec:    args.ToMachineSpec,
+			Networks:         requestedNetworks,
+			Storage:          storageConstraints,
+			EndpointBindings: args.EndpointBindings,
 		})
 	return err
 }
@@ -437,7 +438,7 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	}
 	// Set the charm for the given service.
 	if args.CharmUrl != "" {
-		if err = c.serviceSetCharm(service, args.CharmUrl, args.ForceCharmUrl); err != nil {
+		if err = c.serviceSetCharm(service, args.CharmUrl, args.ForceCharmUrl, nil); err != nil {
 			return err
 		}
 	}
@@ -464,8 +465,9 @@ func (c *Client) ServiceUpdate(args params.ServiceUpdate) error {
 	return nil
 }
 
-// serviceSetCharm sets the charm for the given service.
-func (c *Client) serviceSetCharm(service *state.Service, url string, force bool) error {
+// serviceSetCharm sets the charm for the given service, upgrading
+// its units in batches if upgradeArgs is not nil.
+func (c *Client) serviceSetCharm(service *state.Service, url string, force bool, upgradeArgs *state.CharmUpgradeArgs) error {
 	curl, err := charm.ParseURL(url)
 	if err != nil {
 		return err
@@ -475,17 +477,30 @@ func (c *Client) serviceSetCharm(service *state.Service, url string, force bool)
 		// Charms should be added before trying to use them, with
 		// AddCharm or AddLocalCharm API calls. When they're not,
 		// we're reverting to 1.16 compatibility mode.
-		return c.serviceSetCharm1dot16(service, curl, force)
+		return c.serviceSetCharm1dot16(service, curl, force, upgradeArgs)
 	}
 	if err != nil {
 		return err
 	}
-	return service.SetCharm(sch, force)
+	return c.setServiceCharm(service, sch, force, upgradeArgs)
+}
+
+// setServiceCharm sets the charm of the service, recording the
+// authenticated user in the service's charm history.
+func (c *Client) setServiceCharm(service *state.Service, ch *state.Charm, force bool, upgradeArgs *state.CharmUpgradeArgs) error {
+	user, isUser := c.api.auth.GetAuthTag().(names.UserTag)
+	if upgradeArgs != nil {
+		return service.SetCharmInBatches(ch, force, user, *upgradeArgs)
+	}
+	if isUser {
+		return service.SetCharmByUser(ch, force, user)
+	}
+	return service.SetCharm(ch, force)
 }
 
 // serviceSetCharm1dot16 sets the charm for the given service in 1.16
 // compatibility mode. Remove this when support for 1.16 is dropped.
-func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL, force bool) error {
+func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL, force bool, upgradeArgs *state.CharmUpgradeArgs) error {
 	if curl.Schema != "cs" {
 		return fmt.Errorf(`charm url has unsupported schema %q`, curl.Schema)
 	}
@@ -500,7 +515,7 @@ func (c *Client) serviceSetCharm1dot16(service *state.Service, curl *charm.URL,
 	if err != nil {
 		return err
 	}
-	return service.SetCharm(ch, force)
+	return c.setServiceCharm(service, ch, force, upgradeArgs)
 }
 
 // serviceSetSettingsYAML updates the settings for the given service,
@@ -565,7 +580,81 @@ func (c *Client) ServiceSetCharm(args params.ServiceSetCharm) error {
 	if err != nil {
 		return err
 	}
-	return c.serviceSetCharm(service, args.CharmUrl, args.Force)
+	return c.serviceSetCharm(service, args.CharmUrl, args.Force, nil)
+}
+
+// ServiceSetCharmInBatches sets the charm for a given service, and
+// upgrades its units in batches of the given size.
+func (c *Client) ServiceSetCharmInBatches(args params.ServiceSetCharmInBatches) error {
+	// when forced, don't block
+	if !args.Force {
+		if err := c.check.ChangeAllowed(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if args.BatchSize <= 0 {
+		return errors.NotValidf("batch size %d", args.BatchSize)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return c.serviceSetCharm(service, args.CharmUrl, args.Force, &state.CharmUpgradeArgs{
+		BatchSize:    args.BatchSize,
+		PauseOnError: args.PauseOnError,
+		Timeout:      args.BatchTimeout,
+	})
+}
+
+// ServiceRevertCharm changes the charm of a service back to the one
+// it ran before its current one, and returns the URL of that charm.
+func (c *Client) ServiceRevertCharm(args params.ServiceRevertCharm) (params.StringResult, error) {
+	// when forced, don't block
+	if !args.Force {
+		if err := c.check.ChangeAllowed(); err != nil {
+			return params.StringResult{}, errors.Trace(err)
+		}
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return params.StringResult{}, err
+	}
+	var upgradeArgs *state.CharmUpgradeArgs
+	if args.BatchSize > 0 {
+		upgradeArgs = &state.CharmUpgradeArgs{
+			BatchSize:    args.BatchSize,
+			PauseOnError: args.PauseOnError,
+			Timeout:      args.BatchTimeout,
+		}
+	}
+	user, _ := c.api.auth.GetAuthTag().(names.UserTag)
+	curl, err := service.RevertCharm(args.Force, user, upgradeArgs)
+	if err != nil {
+		return params.StringResult{}, err
+	}
+	return params.StringResult{Result: curl.String()}, nil
+}
+
+// ServiceResumeCharmUpgrade resumes the paused rolling charm upgrade
+// of a service.
+func (c *Client) ServiceResumeCharmUpgrade(args params.ServiceResumeCharmUpgrade) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	service, err := c.api.state.Service(args.ServiceName)
+	if err != nil {
+		return err
+	}
+	return service.ResumeCharmUpgrade()
+}
+
+// ServiceOffer offers endpoints of a service for relation to services
+// of other environments.
+func (c *Client) ServiceOffer(args params.ServiceOffer) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	return c.api.state.OfferService(args.ServiceName, args.Endpoints)
 }
 
 // addServiceUnits adds a given number of units to a service.
@@ -699,13 +788,51 @@ func (c *Client) AddRelation(args params.AddRelation) (params.AddRelationResults
 	if err != nil {
 		return params.AddRelationResults{}, err
 	}
+	return relationResults(rel)
+}
+
+// AddCrossEnvironmentRelation relates a service of this environment to
+// a service offered by another environment hosted by the same state
+// server. The authenticated user must be a user of that environment.
+func (c *Client) AddCrossEnvironmentRelation(args params.AddCrossEnvironmentRelation) (params.AddRelationResults, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.AddRelationResults{}, errors.Trace(err)
+	}
+	user, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return params.AddRelationResults{}, common.ErrPerm
+	}
+	env, err := c.api.state.EnvironmentByName(args.Environment)
+	if errors.IsNotFound(err) {
+		// Whether the environment exists is not revealed to those
+		// without access to it.
+		return params.AddRelationResults{}, common.ErrPerm
+	} else if err != nil {
+		return params.AddRelationResults{}, err
+	}
+	offering, err := c.api.state.ForEnviron(env.EnvironTag())
+	if err != nil {
+		return params.AddRelationResults{}, err
+	}
+	defer offering.Close()
+	if _, err := offering.EnvironmentUser(user); errors.IsNotFound(err) {
+		return params.AddRelationResults{}, common.ErrPerm
+	} else if err != nil {
+		return params.AddRelationResults{}, err
+	}
+	rel, err := c.api.state.AddCrossEnvironmentRelation(args.Endpoint, offering, args.OfferedEndpoint)
+	if err != nil {
+		return params.AddRelationResults{}, err
+	}
+	return relationResults(rel)
+}
+
+// relationResults returns the endpoints of the relation, keyed by
+// service name.
+func relationResults(rel *state.Relation) (params.AddRelationResults, error) {
 	outEps := make(map[string]charm.Relation)
-	for _, inEp := range inEps {
-		outEp, err := rel.Endpoint(inEp.ServiceName)
-		if err != nil {
-			return params.AddRelationResults{}, err
-		}
-		outEps[inEp.ServiceName] = outEp.Relation
+	for _, ep := range rel.Endpoints() {
+		outEps[ep.ServiceName] = ep.Relation
 	}
 	return params.AddRelationResults{Endpoints: outEps}, nil
 }
@@ -1231,7 +1358,8 @@ func (c *Client) AddCharm(args params.CharmURL) error {
 		return err
 	}
 	config.SpecializeCharmRepo(CharmStore, envConfig)
-	downloadedCharm, err := CharmStore.Get(charmURL)
+	repo := common.CharmStoreRepository(c.api.state, envConfig, CharmStore)
+	downloadedCharm, err := repo.Get(charmURL)
 	if err != nil {
 		return errors.Annotatef(err, "cannot download charm %q", charmURL.String())
 	}
@@ -1306,10 +1434,19 @@ func (c *Client) ResolveCharms(args params.ResolveCharms) (params.ResolveCharmRe
 		return params.ResolveCharmResults{}, err
 	}
 	config.SpecializeCharmRepo(CharmStore, envConfig)
+	repo := common.CharmStoreRepository(c.api.state, envConfig, CharmStore)
+	_, customStore := envConfig.CharmStoreURL()
 
 	for _, ref := range args.References {
 		result := params.ResolveCharmResult{}
-		curl, err := c.resolveCharm(&ref, CharmStore)
+		curl, err := c.resolveCharm(&ref, repo)
+		if err == nil && customStore && curl.Revision < 0 {
+			// Clients cannot reach a store other than the public
+			// one, so they rely on us to find the latest revision.
+			var revision int
+			revision, err = charm.Latest(repo, curl)
+			curl = curl.WithRevision(revision)
+		}
 		if err != nil {
 			result.Error = err.Error()
 		} else {
@@ -6,6 +6,7 @@ package client_test
 import (
 	"fmt"
 	"io"
+	"os"
 	"regexp"
 	"strconv"
 	"strings"
@@ -37,6 +38,7 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/provider/dummy"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/charmmirror"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
 	statestorage "github.com/juju/juju/state/storage"
@@ -88,6 +90,13 @@ func (s *serverSuite) setAgentPresence(c *gc.C, machineId string) *presence.Ping
 	return pinger
 }
 
+func (s *serverSuite) TestFullStatusCancelled(c *gc.C) {
+	cancel := make(chan struct{})
+	close(cancel)
+	_, err := s.client.FullStatus(cancel, params.StatusParams{})
+	c.Assert(err, gc.ErrorMatches, "status request cancelled")
+}
+
 func (s *serverSuite) TestEnsureAvailabilityDeprecated(c *gc.C) {
 	_, err := s.State.AddMachine("quantal", state.JobManageEnviron)
 	c.Assert(err, jc.ErrorIsNil)
@@ -2091,6 +2100,59 @@ func (s *clientSuite) TestClientServiceSetCharm(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(charm.URL().String(), gc.Equals, "cs:precise/wordpress-3")
 	c.Assert(force, jc.IsFalse)
+
+	// The change is recorded in the service's charm history.
+	history := service.CharmHistory()
+	c.Assert(history, gc.HasLen, 2)
+	c.Assert(history[0].CharmURL, gc.DeepEquals, curl)
+	c.Assert(history[1].CharmURL.String(), gc.Equals, "cs:precise/wordpress-3")
+	c.Assert(history[1].User, gc.Equals, s.AdminUserTag(c).Username())
+}
+
+func (s *clientSuite) TestClientServiceSetCharmInBatches(c *gc.C) {
+	s.makeMockCharmStore()
+	curl, _ := addCharm(c, "dummy")
+	err := s.APIState.Client().ServiceDeploy(
+		curl.String(), "service", 3, "", constraints.Value{}, "",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	addCharm(c, "wordpress")
+	err = s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 0, false, 0,
+	)
+	c.Assert(err, gc.ErrorMatches, "batch size 0 not valid")
+
+	err = s.APIState.Client().ServiceSetCharmInBatches(
+		"service", "cs:precise/wordpress-3", false, 2, false, 0,
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, err := service.CharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgrade.Batch, gc.HasLen, 2)
+	c.Assert(upgrade.Held, gc.HasLen, 1)
+}
+
+func (s *clientSuite) TestClientServiceRevertCharm(c *gc.C) {
+	s.setupServiceSetCharm(c)
+	_, err := s.APIState.Client().ServiceRevertCharm("service", false, 0, false, 0)
+	c.Assert(err, gc.ErrorMatches, `cannot revert charm of service "service": previous charm of service "service" not found`)
+
+	err = s.APIState.Client().ServiceSetCharm("service", "cs:precise/wordpress-3", false)
+	c.Assert(err, jc.ErrorIsNil)
+	reverted, err := s.APIState.Client().ServiceRevertCharm("service", true, 0, false, 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reverted.String(), gc.Equals, "cs:precise/dummy-1")
+
+	service, err := s.State.Service("service")
+	c.Assert(err, jc.ErrorIsNil)
+	curl, force := service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, reverted)
+	c.Assert(force, jc.IsTrue)
+	history := service.CharmHistory()
+	c.Assert(history, gc.HasLen, 3)
+	c.Assert(history[2].User, gc.Equals, s.AdminUserTag(c).Username())
 }
 
 func (s *clientSuite) setupServiceSetCharm(c *gc.C) {
@@ -2287,6 +2349,67 @@ func (s *clientSuite) TestSuccessfullyAddRelationSwapped(c *gc.C) {
 	s.assertAddRelation(c, endpoints)
 }
 
+func (s *clientSuite) TestClientServiceOffer(c *gc.C) {
+	s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	err := s.APIState.Client().ServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := s.State.ServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"server"})
+
+	err = s.APIState.Client().ServiceOffer("mysql", "juju-info")
+	c.Assert(err, gc.ErrorMatches, `cannot offer service "mysql": endpoint "juju-info" cannot be offered`)
+}
+
+func (s *clientSuite) TestAddCrossEnvironmentRelation(c *gc.C) {
+	offering := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "db-env"})
+	defer offering.Close()
+	f := factory.NewFactory(offering)
+	f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	_, err := s.APIState.Client().AddCrossEnvironmentRelation("wordpress", "db-env", "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot relate "wordpress" to "mysql" of environment ".*": service "mysql" is not offered`)
+
+	err = offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	res, err := s.APIState.Client().AddCrossEnvironmentRelation("wordpress", "db-env", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.checkEndpoints(c, res.Endpoints)
+	rel, err := s.State.KeyRelation("wordpress:db mysql:server")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = offering.KeyRelation(rel.String())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *clientSuite) TestAddCrossEnvironmentRelationPermissionDenied(c *gc.C) {
+	offering := s.Factory.MakeEnvironment(c, &factory.EnvParams{Name: "db-env"})
+	defer offering.Close()
+	f := factory.NewFactory(offering)
+	f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	err := offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+
+	_, err = s.APIState.Client().AddCrossEnvironmentRelation("wordpress", "no-such-env", "mysql")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+
+	// A user of this environment only cannot relate to services of the
+	// offering one.
+	user := s.Factory.MakeUser(c, &factory.UserParams{Password: "password"})
+	s.APIState = s.OpenAPIAs(c, user.Tag(), "password")
+	_, err = s.APIState.Client().AddCrossEnvironmentRelation("wordpress", "db-env", "mysql")
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *clientSuite) TestCallWithOnlyOneEndpoint(c *gc.C) {
 	s.setUpScenario(c)
 	endpoints := []string{"wordpress"}
@@ -3414,6 +3537,47 @@ func (s *clientSuite) TestResolveCharm(c *gc.C) {
 	}
 }
 
+func (s *clientSuite) TestResolveAndAddCharmFromMirror(c *gc.C) {
+	s.PatchValue(&charm.CacheDir, c.MkDir())
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	hash, size, err := utils.ReadFileSHA256(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	f, err := os.Open(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	mirror, err := s.State.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	defer mirror.Close()
+	curl := charm.MustParseURL("cs:trusty/dummy-4")
+	err = mirror.AddCharm(f, charmmirror.Metadata{URL: curl, Size: size, SHA256: hash})
+	c.Assert(err, jc.ErrorIsNil)
+
+	storeURL := fmt.Sprintf("https://10.0.0.1:17070/environment/%s/charmstore", s.State.EnvironUUID())
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"charm-store-url": storeURL}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Mirrored charms resolve to their latest revision, whether or
+	// not the series is given.
+	client := s.APIState.Client()
+	for _, location := range []string{"cs:dummy", "cs:trusty/dummy"} {
+		ref, err := charm.ParseReference(location)
+		c.Assert(err, jc.ErrorIsNil)
+		resolved, err := client.ResolveCharm(ref)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(resolved, gc.DeepEquals, curl)
+	}
+	ref, err := charm.ParseReference("cs:trusty/wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = client.ResolveCharm(ref)
+	c.Assert(err, gc.ErrorMatches, `charm "cs:trusty/wordpress" not found`)
+
+	err = client.AddCharm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	sch, err := s.State.Charm(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sch.BundleSha256(), gc.Equals, hash)
+}
+
 type blobs struct {
 	sync.Mutex
 	m map[string]bool // maps path to added (true), or deleted (false)
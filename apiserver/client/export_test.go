@@ -25,3 +25,6 @@ var (
 )
 
 type MachineAndContainers machineAndContainers
+
+// Leadership exports
+var LeadershipManager = &leadershipManager
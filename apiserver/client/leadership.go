@@ -0,0 +1,97 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/leadership"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+// leaderHandoverDuration is how long leadership is claimed for when it is
+// handed to a unit. The unit will renew the claim itself once it notices
+// that it has become leader.
+const leaderHandoverDuration = time.Minute
+
+// leadershipTransferer hands service leadership from one unit to another.
+type leadershipTransferer interface {
+	TransferLeadership(serviceId, unitId string, duration time.Duration) error
+}
+
+// leadershipManager is exposed as a variable so we can change the
+// implementation for testing purposes.
+var leadershipManager leadershipTransferer = leadership.NewLeadershipManager(lease.Manager())
+
+// ServiceLeader returns the name of the unit which currently leads the
+// given service, or an empty string if it has no leader.
+func (c *Client) ServiceLeader(args params.ServiceLeader) (params.ServiceLeaderResults, error) {
+	var result params.ServiceLeaderResults
+	if _, err := c.api.state.Service(args.ServiceName); err != nil {
+		return result, err
+	}
+	leaders, err := serviceLeaders(c.api.state)
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result.UnitName = leaders[args.ServiceName]
+	return result, nil
+}
+
+// SetServiceLeader makes the given unit the leader of its service. The
+// current leader, if any, is notified that it has lost leadership. Only
+// the owner of the environment may move leadership.
+func (c *Client) SetServiceLeader(args params.SetServiceLeader) error {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := c.checkEnvironOwner(); err != nil {
+		return err
+	}
+	unit, err := c.api.state.Unit(args.UnitName)
+	if err != nil {
+		return err
+	}
+	if unit.Life() != state.Alive {
+		return errors.Errorf("unit %q is not alive", unit.Name())
+	}
+	return leadershipManager.TransferLeadership(unit.ServiceName(), unit.Name(), leaderHandoverDuration)
+}
+
+// checkEnvironOwner returns common.ErrPerm unless the authenticated
+// entity is the owner of the environment.
+func (c *Client) checkEnvironOwner() error {
+	// Until we have real permissions, only the owner of the environment
+	// is considered to be an administrator.
+	user, ok := c.api.auth.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	env, err := c.api.state.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user != env.Owner() {
+		return common.ErrPerm
+	}
+	return nil
+}
+
+// serviceLeaders returns the leader unit name for each service with a
+// leader. The leases are read from state, so that every state server
+// gives the same answer; those that have expired but not yet been
+// removed are ignored.
+func serviceLeaders(st *state.State) (map[string]string, error) {
+	tokens, err := st.PersistedTokens()
+	if err != nil {
+		return nil, errors.Annotate(err, "could not read leadership")
+	}
+	return leadership.Leaders(tokens, time.Now()), nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/client"
+	"github.com/juju/juju/lease"
+)
+
+type leadershipSuite struct {
+	baseSuite
+	transferer *stubTransferer
+}
+
+var _ = gc.Suite(&leadershipSuite{})
+
+// stubTransferer records leadership transfers instead of making them,
+// so that tests need not run a lease manager.
+type stubTransferer struct {
+	serviceId, unitId string
+}
+
+func (t *stubTransferer) TransferLeadership(serviceId, unitId string, duration time.Duration) error {
+	t.serviceId, t.unitId = serviceId, unitId
+	return nil
+}
+
+func (s *leadershipSuite) SetUpTest(c *gc.C) {
+	s.baseSuite.SetUpTest(c)
+	s.transferer = &stubTransferer{}
+	s.PatchValue(client.LeadershipManager, s.transferer)
+}
+
+func (s *leadershipSuite) addUnits(c *gc.C) {
+	svc := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	for i := 0; i < 2; i++ {
+		_, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *leadershipSuite) TestServiceLeader(c *gc.C) {
+	s.addUnits(c)
+	err := s.State.WriteToken(nil, lease.Token{
+		Namespace:  "dummy-leadership",
+		Id:         "dummy/1",
+		Expiration: time.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	leader, err := s.APIState.Client().ServiceLeader("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(leader, gc.Equals, "dummy/1")
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	units := status.Services["dummy"].Units
+	c.Check(units["dummy/0"].Leader, jc.IsFalse)
+	c.Check(units["dummy/1"].Leader, jc.IsTrue)
+}
+
+func (s *leadershipSuite) TestServiceLeaderNoLeader(c *gc.C) {
+	s.addUnits(c)
+	leader, err := s.APIState.Client().ServiceLeader("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(leader, gc.Equals, "")
+}
+
+func (s *leadershipSuite) TestServiceLeaderExpired(c *gc.C) {
+	s.addUnits(c)
+	err := s.State.WriteToken(nil, lease.Token{
+		Namespace:  "dummy-leadership",
+		Id:         "dummy/1",
+		Expiration: time.Now().Add(-time.Hour),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	leader, err := s.APIState.Client().ServiceLeader("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(leader, gc.Equals, "")
+
+	status, err := s.APIState.Client().Status(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(status.Services["dummy"].Units["dummy/1"].Leader, jc.IsFalse)
+}
+
+func (s *leadershipSuite) TestServiceLeaderNotFound(c *gc.C) {
+	_, err := s.APIState.Client().ServiceLeader("dummy")
+	c.Assert(err, gc.ErrorMatches, `service "dummy" not found`)
+}
+
+func (s *leadershipSuite) TestSetServiceLeader(c *gc.C) {
+	s.addUnits(c)
+	err := s.APIState.Client().SetServiceLeader("dummy/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.transferer.serviceId, gc.Equals, "dummy")
+	c.Check(s.transferer.unitId, gc.Equals, "dummy/1")
+}
+
+func (s *leadershipSuite) TestSetServiceLeaderUnitNotFound(c *gc.C) {
+	err := s.APIState.Client().SetServiceLeader("dummy/0")
+	c.Assert(err, gc.ErrorMatches, `unit "dummy/0" not found`)
+	c.Check(s.transferer.unitId, gc.Equals, "")
+}
+
+func (s *leadershipSuite) TestSetServiceLeaderUnitNotAlive(c *gc.C) {
+	s.addUnits(c)
+	unit, err := s.State.Unit("dummy/1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.APIState.Client().SetServiceLeader("dummy/1")
+	c.Assert(err, gc.ErrorMatches, `unit "dummy/1" is not alive`)
+	c.Check(s.transferer.unitId, gc.Equals, "")
+}
+
+func (s *leadershipSuite) TestBlockChangesSetServiceLeader(c *gc.C) {
+	s.addUnits(c)
+	s.BlockAllChanges(c, "TestBlockChangesSetServiceLeader")
+	err := s.APIState.Client().SetServiceLeader("dummy/1")
+	s.AssertBlocked(c, err, "TestBlockChangesSetServiceLeader")
+	c.Check(s.transferer.unitId, gc.Equals, "")
+}
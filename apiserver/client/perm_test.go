@@ -12,6 +12,7 @@ import (
 	"gopkg.in/juju/charm.v4"
 
 	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/client"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/state"
@@ -59,6 +60,7 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 		userOther = names.NewLocalUserTag("other")
 	)
 	entities := s.setUpScenario(c)
+	s.PatchValue(client.LeadershipManager, &stubTransferer{})
 	for i, t := range []struct {
 		about string
 		// op performs the operation to be tested using the given state
@@ -95,6 +97,14 @@ func (s *permSuite) TestOperationPerm(c *gc.C) {
 		about: "Client.ServiceUnexpose",
 		op:    opClientServiceUnexpose,
 		allow: []names.Tag{userAdmin, userOther},
+	}, {
+		about: "Client.ServiceLeader",
+		op:    opClientServiceLeader,
+		allow: []names.Tag{userAdmin, userOther},
+	}, {
+		about: "Client.SetServiceLeader",
+		op:    opClientSetServiceLeader,
+		allow: []names.Tag{userAdmin},
 	}, {
 		about: "Client.ServiceDeploy",
 		op:    opClientServiceDeploy,
@@ -287,6 +297,22 @@ func opClientServiceExpose(c *gc.C, st *api.State, mst *state.State) (func(), er
 	}, nil
 }
 
+func opClientServiceLeader(c *gc.C, st *api.State, mst *state.State) (func(), error) {
+	_, err := st.Client().ServiceLeader("wordpress")
+	if err != nil {
+		return func() {}, err
+	}
+	return func() {}, nil
+}
+
+func opClientSetServiceLeader(c *gc.C, st *api.State, mst *state.State) (func(), error) {
+	err := st.Client().SetServiceLeader("wordpress/0")
+	if err != nil {
+		return func() {}, err
+	}
+	return func() {}, nil
+}
+
 func opClientServiceUnexpose(c *gc.C, st *api.State, mst *state.State) (func(), error) {
 	err := st.Client().ServiceUnexpose("wordpress")
 	if err != nil {
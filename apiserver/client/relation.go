@@ -0,0 +1,60 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ShowRelation returns the units in scope in a relation, with their
+// settings in it and the time those were last changed.
+func (c *Client) ShowRelation(args params.ShowRelation) (params.RelationDetails, error) {
+	var result params.RelationDetails
+	rel, err := c.relation(args)
+	if err != nil {
+		return result, err
+	}
+	units, err := rel.ScopeUnits()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	result = params.RelationDetails{
+		Id:        rel.Id(),
+		Key:       rel.String(),
+		Life:      params.Life(rel.Life().String()),
+		Endpoints: make(map[string]charm.Relation),
+		Units:     make([]params.RelationUnitDetails, len(units)),
+	}
+	for _, ep := range rel.Endpoints() {
+		result.Endpoints[ep.ServiceName] = ep.Relation
+	}
+	for i, unit := range units {
+		result.Units[i] = params.RelationUnitDetails{
+			UnitName:  unit.Unit,
+			Departing: unit.Departing,
+			Settings:  unit.Settings,
+		}
+		if !unit.SettingsChanged.IsZero() {
+			changed := unit.SettingsChanged
+			result.Units[i].SettingsChanged = &changed
+		}
+	}
+	return result, nil
+}
+
+// relation returns the relation identified by the arguments.
+func (c *Client) relation(args params.ShowRelation) (*state.Relation, error) {
+	if len(args.Endpoints) == 0 {
+		return c.api.state.Relation(args.RelationId)
+	}
+	eps, err := c.api.state.InferEndpoints(args.Endpoints...)
+	if err != nil {
+		return nil, err
+	}
+	return c.api.state.EndpointsRelation(eps...)
+}
@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package client_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+type relationSuite struct {
+	baseSuite
+}
+
+var _ = gc.Suite(&relationSuite{})
+
+func (s *relationSuite) TestShowRelation(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err := mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"password": "secret"})
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err = wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err = rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	details, err := s.APIState.Client().ShowRelation("mysql", "wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(details.Id, gc.Equals, rel.Id())
+	c.Assert(details.Key, gc.Equals, "wordpress:db mysql:server")
+	c.Assert(details.Life, gc.Equals, params.Alive)
+	c.Assert(details.Endpoints["mysql"].Name, gc.Equals, "server")
+	c.Assert(details.Endpoints["wordpress"].Name, gc.Equals, "db")
+	c.Assert(details.Units, gc.HasLen, 2)
+	c.Assert(details.Units[0].UnitName, gc.Equals, "mysql/0")
+	c.Assert(details.Units[0].Settings, jc.DeepEquals, map[string]interface{}{"password": "secret"})
+	c.Assert(details.Units[0].SettingsChanged, gc.NotNil)
+	c.Assert(details.Units[1].UnitName, gc.Equals, "wordpress/0")
+	c.Assert(details.Units[1].Departing, jc.IsFalse)
+
+	byId, err := s.APIState.Client().ShowRelationById(rel.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byId.Units, jc.DeepEquals, details.Units)
+}
+
+func (s *relationSuite) TestShowRelationNotFound(c *gc.C) {
+	_, err := s.APIState.Client().ShowRelationById(42)
+	c.Assert(err, gc.ErrorMatches, `relation 42 not found`)
+	c.Assert(err, jc.Satisfies, params.IsCodeNotFound)
+}
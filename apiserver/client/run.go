@@ -81,12 +81,36 @@ func (c *Client) getDataDir() string {
 	return dataResource.String()
 }
 
+// relationContextArgs returns the juju-run arguments that select the
+// relation context identified by relationId and remoteUnitName, either
+// of which may be empty.
+func relationContextArgs(relationId, remoteUnitName string) (string, error) {
+	if relationId == "" {
+		if remoteUnitName != "" {
+			return "", errors.Errorf("remote unit %q provided without a relation", remoteUnitName)
+		}
+		return "", nil
+	}
+	args := " --relation " + utils.ShQuote(relationId)
+	if remoteUnitName != "" {
+		args += " --remote-unit " + utils.ShQuote(remoteUnitName)
+	}
+	return args, nil
+}
+
 // Run the commands specified on the machines identified through the
 // list of machines, units and services.
 func (c *Client) Run(run params.RunParams) (results params.RunResults, err error) {
 	if err := c.check.ChangeAllowed(); err != nil {
 		return params.RunResults{}, errors.Trace(err)
 	}
+	contextArgs, err := relationContextArgs(run.RelationId, run.RemoteUnitName)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	if contextArgs != "" && len(run.Machines) > 0 {
+		return results, errors.Errorf("a relation context cannot be used when running on machines")
+	}
 	units, err := getAllUnitNames(c.api.state, run.Units, run.Services)
 	if err != nil {
 		return results, err
@@ -105,7 +129,7 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 		if err != nil {
 			return results, err
 		}
-		command := fmt.Sprintf("juju-run %s %s", unit.Name(), quotedCommands)
+		command := fmt.Sprintf("juju-run%s %s %s", contextArgs, unit.Name(), quotedCommands)
 		execParam := remoteParamsForMachine(machine, command, run.Timeout)
 		execParam.UnitId = unit.Name()
 		params = append(params, execParam)
@@ -122,6 +146,37 @@ func (c *Client) Run(run params.RunParams) (results params.RunResults, err error
 	return ParallelExecute(c.getDataDir(), params), nil
 }
 
+// RunHook runs the named hook on the unit. The hook is queued by the
+// unit agent and run as it would be in response to a change, so the
+// result reports only whether it was queued.
+func (c *Client) RunHook(run params.RunHookParams) (params.RunResult, error) {
+	if err := c.check.ChangeAllowed(); err != nil {
+		return params.RunResult{}, errors.Trace(err)
+	}
+	if run.Hook == "" {
+		return params.RunResult{}, errors.Errorf("no hook specified")
+	}
+	contextArgs, err := relationContextArgs(run.RelationId, run.RemoteUnitName)
+	if err != nil {
+		return params.RunResult{}, errors.Trace(err)
+	}
+	units, err := getAllUnitNames(c.api.state, []string{run.Unit}, nil)
+	if err != nil {
+		return params.RunResult{}, errors.Trace(err)
+	}
+	unit := units[0]
+	machineId, _ := unit.AssignedMachineId()
+	machine, err := c.api.state.Machine(machineId)
+	if err != nil {
+		return params.RunResult{}, errors.Trace(err)
+	}
+	command := fmt.Sprintf("juju-run --hook%s %s %s", contextArgs, unit.Name(), utils.ShQuote(run.Hook))
+	execParam := remoteParamsForMachine(machine, command, run.Timeout)
+	execParam.UnitId = unit.Name()
+	results := ParallelExecute(c.getDataDir(), []*RemoteExec{execParam})
+	return results.Results[0], nil
+}
+
 // RunOnAllMachines attempts to run the specified command on all the machines.
 func (c *Client) RunOnAllMachines(run params.RunParams) (params.RunResults, error) {
 	if err := c.check.ChangeAllowed(); err != nil {
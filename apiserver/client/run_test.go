@@ -5,6 +5,7 @@ package client_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	gitjujutesting "github.com/juju/testing"
@@ -346,3 +347,86 @@ func (s *runSuite) TestBlockRunMachineAndService(c *gc.C) {
 		})
 	s.AssertBlocked(c, err, "TestBlockRunMachineAndService")
 }
+
+func (s *runSuite) TestRunInRelationContext(c *gc.C) {
+	s.addMachineWithAddress(c, "10.3.2.1")
+	charm := s.AddTestingCharm(c, "dummy")
+	owner := s.Factory.MakeUser(c, nil).Tag()
+	magic, err := s.State.AddService("magic", owner.String(), charm, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnit(c, magic)
+
+	s.mockSSH(c, echoInput)
+
+	client := s.APIState.Client()
+	results, err := client.Run(
+		params.RunParams{
+			Commands:       "relation-get",
+			Timeout:        testing.LongWait,
+			Units:          []string{"magic/0"},
+			RelationId:     "db:3",
+			RemoteUnitName: "mysql/0",
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].UnitId, gc.Equals, "magic/0")
+	c.Assert(strings.TrimRight(string(results[0].Stdout), "\r\n"), gc.Equals,
+		"juju-run --relation 'db:3' --remote-unit 'mysql/0' magic/0 'relation-get'")
+}
+
+func (s *runSuite) TestRunInRelationContextInvalid(c *gc.C) {
+	client := s.APIState.Client()
+	_, err := client.Run(
+		params.RunParams{
+			Commands:       "relation-get",
+			Units:          []string{"magic/0"},
+			RemoteUnitName: "mysql/0",
+		})
+	c.Assert(err, gc.ErrorMatches, `remote unit "mysql/0" provided without a relation`)
+
+	_, err = client.Run(
+		params.RunParams{
+			Commands:   "relation-get",
+			Machines:   []string{"0"},
+			RelationId: "db:3",
+		})
+	c.Assert(err, gc.ErrorMatches, "a relation context cannot be used when running on machines")
+}
+
+func (s *runSuite) TestRunHook(c *gc.C) {
+	s.addMachineWithAddress(c, "10.3.2.1")
+	charm := s.AddTestingCharm(c, "dummy")
+	owner := s.Factory.MakeUser(c, nil).Tag()
+	magic, err := s.State.AddService("magic", owner.String(), charm, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.addUnit(c, magic)
+
+	s.mockSSH(c, echoInput)
+
+	client := s.APIState.Client()
+	result, err := client.RunHook(
+		params.RunHookParams{
+			Unit:       "magic/0",
+			Hook:       "db-relation-changed",
+			RelationId: "3",
+			Timeout:    testing.LongWait,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Error, gc.Equals, "")
+	c.Assert(result.MachineId, gc.Equals, "1")
+	c.Assert(result.UnitId, gc.Equals, "magic/0")
+	c.Assert(strings.TrimRight(string(result.Stdout), "\r\n"), gc.Equals,
+		"juju-run --hook --relation '3' magic/0 'db-relation-changed'")
+
+	_, err = client.RunHook(params.RunHookParams{Unit: "magic/0"})
+	c.Assert(err, gc.ErrorMatches, "no hook specified")
+	_, err = client.RunHook(params.RunHookParams{Unit: "magic/1", Hook: "config-changed"})
+	c.Assert(err, gc.ErrorMatches, `unit "magic/1" not found`)
+}
+
+func (s *runSuite) TestBlockRunHook(c *gc.C) {
+	client := s.APIState.Client()
+	s.BlockAllChanges(c, "TestBlockRunHook")
+	_, err := client.RunHook(params.RunHookParams{Unit: "magic/0", Hook: "config-changed"})
+	s.AssertBlocked(c, err, "TestBlockRunHook")
+}
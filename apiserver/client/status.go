@@ -20,8 +20,13 @@ import (
 	"github.com/juju/juju/tools"
 )
 
-// FullStatus gives the information needed for juju status over the api
-func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
+// errStatusCancelled is returned by FullStatus when the
+// request is cancelled before the status has been gathered.
+var errStatusCancelled = errors.New("status request cancelled")
+
+// FullStatus gives the information needed for juju status over the api.
+// Gathering the status is abandoned if cancel is closed.
+func (c *Client) FullStatus(cancel <-chan struct{}, args params.StatusParams) (api.Status, error) {
 	cfg, err := c.api.state.EnvironConfig()
 	if err != nil {
 		return api.Status{}, errors.Annotate(err, "could not get environ config")
@@ -31,12 +36,24 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	if context.services, context.units, context.latestCharms, err =
 		fetchAllServicesAndUnits(c.api.state, len(args.Patterns) <= 0); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch services and units")
+	} else if isCancelled(cancel) {
+		return noStatus, errStatusCancelled
 	} else if context.machines, err = fetchMachines(c.api.state, nil); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch machines")
+	} else if isCancelled(cancel) {
+		return noStatus, errStatusCancelled
 	} else if context.relations, err = fetchRelations(c.api.state); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch relations")
+	} else if isCancelled(cancel) {
+		return noStatus, errStatusCancelled
 	} else if context.networks, err = fetchNetworks(c.api.state); err != nil {
 		return noStatus, errors.Annotate(err, "could not fetch networks")
+	} else if isCancelled(cancel) {
+		return noStatus, errStatusCancelled
+	} else if context.leaders, err = serviceLeaders(c.api.state); err != nil {
+		return noStatus, errors.Annotate(err, "could not fetch leaders")
+	} else if isCancelled(cancel) {
+		return noStatus, errStatusCancelled
 	}
 
 	logger.Debugf("Services: %v", context.services)
@@ -121,10 +138,20 @@ func (c *Client) FullStatus(args params.StatusParams) (api.Status, error) {
 	}, nil
 }
 
+// isCancelled reports whether cancel has been closed.
+func isCancelled(cancel <-chan struct{}) bool {
+	select {
+	case <-cancel:
+		return true
+	default:
+		return false
+	}
+}
+
 // Status is a stub version of FullStatus that was introduced in 1.16
 func (c *Client) Status() (api.LegacyStatus, error) {
 	var legacyStatus api.LegacyStatus
-	status, err := c.FullStatus(params.StatusParams{})
+	status, err := c.FullStatus(nil, params.StatusParams{})
 	if err != nil {
 		return legacyStatus, err
 	}
@@ -148,6 +175,8 @@ type statusContext struct {
 	units        map[string]map[string]*state.Unit
 	networks     map[string]*state.Network
 	latestCharms map[charm.URL]string
+	// leaders: service name -> leader unit name
+	leaders map[string]string
 }
 
 // fetchMachines returns a map from top level machine id to machines, where machines[0] is the host
@@ -466,6 +495,25 @@ func (context *statusContext) processService(service *state.Service) (status api
 	if ok && latestCharm != serviceCharmURL.String() {
 		status.CanUpgradeTo = latestCharm
 	}
+	// The charm history is only of interest once the charm has changed.
+	if history := service.CharmHistory(); len(history) > 1 {
+		for _, entry := range history {
+			status.CharmHistory = append(status.CharmHistory, api.CharmHistoryEntry{
+				Charm: entry.CharmURL.String(),
+				Time:  entry.Time,
+				User:  entry.User,
+			})
+		}
+	}
+	if upgrade, err := service.CharmUpgrade(); err == nil {
+		status.CharmUpgrade = &api.CharmUpgradeStatus{
+			From:         upgrade.FromURL.String(),
+			Batch:        upgrade.Batch,
+			Waiting:      upgrade.Held,
+			Paused:       upgrade.Paused,
+			PausedReason: upgrade.PausedReason,
+		}
+	}
 	var err error
 	status.Relations, status.SubordinateTo, err = context.processServiceRelations(service)
 	if err != nil {
@@ -524,6 +572,7 @@ func (context *statusContext) processUnit(unit *state.Unit, serviceCharm string)
 		status.Charm = curl.String()
 	}
 	status.Agent, status.AgentState, status.AgentStateInfo = processAgent(unit)
+	status.Leader = context.leaders[unit.ServiceName()] == unit.Name()
 
 	// Until Juju 2.0, we need to continue to display legacy status values.
 	status.Agent.Status = params.TranslateLegacyStatus(status.Agent.Status)
@@ -45,6 +45,18 @@ func (s *statusSuite) TestFullStatus(c *gc.C) {
 	c.Check(resultMachine.Series, gc.Equals, machine.Series())
 }
 
+func (s *statusSuite) TestFullStatusCancelled(c *gc.C) {
+	s.addMachine(c)
+	cancel := make(chan struct{})
+	close(cancel)
+	client := s.APIState.Client()
+	// Either the client abandons the request or, should the
+	// response win the race, the server reports that it gave up.
+	status, err := client.StatusWithCancel(nil, cancel)
+	c.Assert(err, gc.ErrorMatches, "(call|status request) cancelled")
+	c.Assert(status, gc.IsNil)
+}
+
 func (s *statusSuite) TestLegacyStatus(c *gc.C) {
 	machine := s.addMachine(c)
 	instanceId := "i-fakeinstance"
@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/charmmirror"
+)
+
+// charmMirrorPath matches the path of the charm mirror served by the
+// API server, capturing the environment UUID.
+var charmMirrorPath = regexp.MustCompile(`^/environment/([^/]+)/charmstore/?$`)
+
+// CharmStoreRepository returns the repository from which the
+// environment obtains charm store charms. If the environment's
+// charm-store-url is not set, publicStore is returned. If it names
+// the charm mirror of this state server, the mirror is read directly
+// from state; any other URL is expected to serve the charm store
+// protocol.
+func CharmStoreRepository(st *state.State, cfg *config.Config, publicStore charm.Repository) charm.Repository {
+	storeURL, ok := cfg.CharmStoreURL()
+	if !ok {
+		return publicStore
+	}
+	if isLocalCharmMirror(st, storeURL) {
+		return &charmMirrorRepository{st}
+	}
+	return &charm.CharmStore{BaseURL: strings.TrimSuffix(storeURL, "/")}
+}
+
+// isLocalCharmMirror reports whether storeURL refers to the charm
+// mirror served by this state server for one of its environments.
+func isLocalCharmMirror(st *state.State, storeURL string) bool {
+	u, err := url.Parse(storeURL)
+	if err != nil {
+		return false
+	}
+	match := charmMirrorPath.FindStringSubmatch(u.Path)
+	if match == nil || !names.IsValidEnvironment(match[1]) {
+		return false
+	}
+	_, err = st.GetEnvironment(names.NewEnvironTag(match[1]))
+	return err == nil
+}
+
+// ResolveMirroredCharm returns the metadata of the latest mirrored
+// revision of the charm identified by ref, or of the exact revision
+// if ref specifies one. If ref does not specify a series, the series
+// is inferred from the mirrored charms: the only series available is
+// used or, failing that, the latest LTS series.
+func ResolveMirroredCharm(mirror charmmirror.Storage, ref *charm.Reference) (charmmirror.Metadata, error) {
+	if ref.Schema != "cs" {
+		return charmmirror.Metadata{}, errors.NotValidf("charm reference %q", ref)
+	}
+	series := ref.Series
+	if series == "" {
+		var err error
+		series, err = mirroredCharmSeries(mirror, ref)
+		if err != nil {
+			return charmmirror.Metadata{}, errors.Trace(err)
+		}
+	}
+	curl, err := ref.URL(series)
+	if err != nil {
+		return charmmirror.Metadata{}, errors.Trace(err)
+	}
+	return mirror.Metadata(curl)
+}
+
+// mirroredCharmSeries returns the series for which the charm
+// identified by ref should be resolved.
+func mirroredCharmSeries(mirror charmmirror.Storage, ref *charm.Reference) (string, error) {
+	all, err := mirror.AllMetadata()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	seriesSet := make(map[string]bool)
+	for _, metadata := range all {
+		curl := metadata.URL
+		if curl.User == ref.User && curl.Name == ref.Name {
+			seriesSet[curl.Series] = true
+		}
+	}
+	switch {
+	case len(seriesSet) == 0:
+		return "", errors.NotFoundf("charm %q", ref)
+	case len(seriesSet) == 1:
+		for series := range seriesSet {
+			return series, nil
+		}
+	case seriesSet[config.LatestLtsSeries()]:
+		return config.LatestLtsSeries(), nil
+	}
+	var series []string
+	for s := range seriesSet {
+		series = append(series, s)
+	}
+	sort.Strings(series)
+	return "", errors.Errorf("charm %q is mirrored for several series (%s); specify one", ref, strings.Join(series, ", "))
+}
+
+// charmMirrorRepository implements charm.Repository on top of the
+// state server's charm mirror.
+type charmMirrorRepository struct {
+	st *state.State
+}
+
+var _ charm.Repository = (*charmMirrorRepository)(nil)
+
+// Get implements charm.Repository.Get. The archive is cached in
+// charm.CacheDir, keyed by its SHA256 hash.
+func (r *charmMirrorRepository) Get(curl *charm.URL) (charm.Charm, error) {
+	mirror, err := r.st.CharmMirror()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer mirror.Close()
+
+	if curl.Revision < 0 {
+		metadata, err := mirror.Metadata(curl)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		curl = metadata.URL
+	}
+	metadata, reader, err := mirror.Charm(curl)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer reader.Close()
+
+	path := filepath.Join(charm.CacheDir, "mirror-"+metadata.SHA256+".charm")
+	if f, err := os.Open(path); err == nil {
+		hash, _, err := utils.ReadSHA256(f)
+		f.Close()
+		if err == nil && hash == metadata.SHA256 {
+			return charm.ReadCharmArchive(path)
+		}
+	}
+	if err := os.MkdirAll(charm.CacheDir, 0755); err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := ioutil.TempFile(charm.CacheDir, "mirror-")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	hasher := sha256.New()
+	_, err = io.Copy(io.MultiWriter(f, hasher), reader)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		if hash := fmt.Sprintf("%x", hasher.Sum(nil)); hash != metadata.SHA256 {
+			err = errors.Errorf("mirrored charm %q has SHA256 %q, expected %q", curl, hash, metadata.SHA256)
+		}
+	}
+	if err == nil {
+		err = utils.ReplaceFile(f.Name(), path)
+	}
+	if err != nil {
+		os.Remove(f.Name())
+		return nil, errors.Trace(err)
+	}
+	return charm.ReadCharmArchive(path)
+}
+
+// Latest implements charm.Repository.Latest.
+func (r *charmMirrorRepository) Latest(curls ...*charm.URL) ([]charm.CharmRevision, error) {
+	mirror, err := r.st.CharmMirror()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer mirror.Close()
+
+	result := make([]charm.CharmRevision, len(curls))
+	for i, curl := range curls {
+		metadata, err := mirror.Metadata(curl.WithRevision(-1))
+		if err != nil {
+			result[i].Err = err
+			continue
+		}
+		result[i].Revision = metadata.URL.Revision
+		result[i].Sha256 = metadata.SHA256
+	}
+	return result, nil
+}
+
+// Resolve implements charm.Repository.Resolve. The returned URL
+// always includes a revision.
+func (r *charmMirrorRepository) Resolve(ref *charm.Reference) (*charm.URL, error) {
+	mirror, err := r.st.CharmMirror()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer mirror.Close()
+
+	metadata, err := ResolveMirroredCharm(mirror, ref)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return metadata.URL, nil
+}
@@ -141,3 +141,14 @@ func (StringResource) Stop() error {
 func (s StringResource) String() string {
 	return string(s)
 }
+
+// Pusher is a Resource that can send messages to the client
+// without the client making a request. A Pusher is registered
+// on every API connection under the name "pusher".
+type Pusher interface {
+	Resource
+
+	// Push sends the given body to the client on the
+	// given subscription.
+	Push(subscription string, body interface{}) error
+}
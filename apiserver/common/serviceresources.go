@@ -0,0 +1,25 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// ServiceResourcesToParams converts the resources attached to a service
+// to their API representation.
+func ServiceResourcesToParams(resources []state.Resource) []params.ServiceResource {
+	results := make([]params.ServiceResource, len(resources))
+	for i, resource := range resources {
+		results[i] = params.ServiceResource{
+			Name:     resource.Name,
+			Revision: resource.Revision,
+			Size:     resource.Size,
+			SHA384:   resource.SHA384,
+			Uploaded: resource.Uploaded,
+		}
+	}
+	return results
+}
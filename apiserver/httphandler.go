@@ -71,32 +71,54 @@ func (h *httpHandler) validateEnvironUUID(r *http.Request) (*httpStateWrapper, e
 
 // authenticate parses HTTP basic authentication and authorizes the
 // request by looking up the provided tag and password against state.
+// Only users are allowed, not agents.
 func (h *httpStateWrapper) authenticate(r *http.Request) error {
+	_, err := h.authenticateTag(r, names.UserTagKind)
+	return err
+}
+
+// authenticateTag parses HTTP basic authentication and authorizes the
+// request by looking up the provided tag and password against state.
+// Only entities with one of the given tag kinds are allowed; the tag
+// of the authenticated entity is returned.
+func (h *httpStateWrapper) authenticateTag(r *http.Request, kinds ...string) (names.Tag, error) {
 	parts := strings.Fields(r.Header.Get("Authorization"))
 	if len(parts) != 2 || parts[0] != "Basic" {
 		// Invalid header format or no header provided.
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
 	// Challenge is a base64-encoded "tag:pass" string.
 	// See RFC 2617, Section 2.
 	challenge, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
 	tagPass := strings.SplitN(string(challenge), ":", 2)
 	if len(tagPass) != 2 {
-		return errors.New("invalid request format")
+		return nil, errors.New("invalid request format")
 	}
-	// Only allow users, not agents.
-	if _, err := names.ParseUserTag(tagPass[0]); err != nil {
-		return common.ErrBadCreds
+	tag, err := names.ParseTag(tagPass[0])
+	if err != nil || !tagKindAllowed(tag, kinds) {
+		return nil, common.ErrBadCreds
 	}
 	// Ensure the credentials are correct.
 	_, err = checkCreds(h.state, params.LoginRequest{
 		AuthTag:     tagPass[0],
 		Credentials: tagPass[1],
 	})
-	return err
+	if err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func tagKindAllowed(tag names.Tag, kinds []string) bool {
+	for _, kind := range kinds {
+		if tag.Kind() == kind {
+			return true
+		}
+	}
+	return false
 }
 
 func (h *httpStateWrapper) cleanup() {
@@ -0,0 +1,91 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker"
+)
+
+// serverIntrospector reports on the API server and the agent
+// running it. It implements introspection.Source and is
+// registered as the "introspector" resource.
+type serverIntrospector struct {
+	srv *Server
+}
+
+// Report returns a report on the agent's workers and on the
+// connections open to the server. Leases are not reported here.
+func (i serverIntrospector) Report() params.IntrospectionReport {
+	report := params.IntrospectionReport{
+		Connections: i.srv.connectionReports(),
+	}
+	if i.srv.tag != nil {
+		report.AgentTag = i.srv.tag.String()
+	}
+	if i.srv.workerReporter != nil {
+		report.Workers = workerReports(i.srv.workerReporter.Report())
+	}
+	return report
+}
+
+// Stop implements common.Resource.
+func (serverIntrospector) Stop() error {
+	return nil
+}
+
+// connectionReports returns a report on each open
+// connection, in the order they were made.
+func (srv *Server) connectionReports() []params.ConnectionReport {
+	srv.mu.Lock()
+	conns := make([]*requestNotifier, 0, len(srv.conns))
+	for _, n := range srv.conns {
+		conns = append(conns, n)
+	}
+	srv.mu.Unlock()
+	sort.Sort(notifiersById(conns))
+
+	reports := make([]params.ConnectionReport, len(conns))
+	for i, n := range conns {
+		n.mu.Lock()
+		reports[i] = params.ConnectionReport{
+			Id:            fmt.Sprintf("%X", n.id),
+			RemoteAddress: n.remoteAddr,
+			Connected:     n.start,
+		}
+		if n.tag_ != unknownTag {
+			reports[i].AuthTag = n.tag_
+		}
+		n.mu.Unlock()
+	}
+	return reports
+}
+
+type notifiersById []*requestNotifier
+
+func (n notifiersById) Len() int           { return len(n) }
+func (n notifiersById) Less(i, j int) bool { return n[i].id < n[j].id }
+func (n notifiersById) Swap(i, j int)      { n[i], n[j] = n[j], n[i] }
+
+func workerReports(reports []worker.WorkerReport) []params.WorkerReport {
+	if len(reports) == 0 {
+		return nil
+	}
+	result := make([]params.WorkerReport, len(reports))
+	for i, report := range reports {
+		result[i] = params.WorkerReport{
+			Name:     report.Name,
+			State:    report.State,
+			Restarts: report.Restarts,
+			Workers:  workerReports(report.Workers),
+		}
+		if report.LastError != nil {
+			result[i].LastError = report.LastError.Error()
+		}
+	}
+	return result
+}
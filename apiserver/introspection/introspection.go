@@ -0,0 +1,136 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The introspection package implements the API facade used to
+// examine the state of a state server's machine agent: the workers
+// it is running, the API connections open to it and the leases it
+// is managing.
+package introspection
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Introspection", 0, NewIntrospectionAPI)
+}
+
+// Source is implemented by the API server to report on itself
+// and the agent running it. It is registered as the
+// "introspector" resource.
+type Source interface {
+	common.Resource
+	Report() params.IntrospectionReport
+}
+
+// LeaseLister is implemented by the lease manager.
+type LeaseLister interface {
+	CopyOfLeaseTokens() []lease.Token
+}
+
+var (
+	// leaseLister is the source of lease information.
+	leaseLister LeaseLister = lease.Manager()
+
+	// leaseTimeout holds how long to wait for the lease manager
+	// to respond. If its worker is not running it never will.
+	leaseTimeout = 5 * time.Second
+)
+
+// IntrospectionAPI implements the Introspection facade.
+type IntrospectionAPI struct {
+	source Source
+}
+
+// NewIntrospectionAPI returns a new Introspection facade. Only the
+// owner of the environment or of the state server may use it.
+func NewIntrospectionAPI(st *state.State, resources *common.Resources, authorizer common.Authorizer) (*IntrospectionAPI, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	if err := checkAdministrator(st, authorizer); err != nil {
+		return nil, err
+	}
+	source, ok := resources.Get("introspector").(Source)
+	if !ok {
+		return nil, errors.New("introspection not available")
+	}
+	return &IntrospectionAPI{
+		source: source,
+	}, nil
+}
+
+// checkAdministrator returns common.ErrPerm unless the authenticated
+// user owns the environment or the state server environment. Until we
+// have real permissions, they are the only administrators.
+func checkAdministrator(st *state.State, authorizer common.Authorizer) error {
+	user, ok := authorizer.GetAuthTag().(names.UserTag)
+	if !ok {
+		return common.ErrPerm
+	}
+	env, err := st.Environment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user == env.Owner() {
+		return nil
+	}
+	stateServerEnv, err := st.StateServerEnvironment()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if user == stateServerEnv.Owner() {
+		return nil
+	}
+	return common.ErrPerm
+}
+
+// Report returns a report on the state server serving the
+// API connection.
+func (api *IntrospectionAPI) Report() (params.IntrospectionReport, error) {
+	report := api.source.Report()
+	leases, err := leaseReports()
+	report.Leases = leases
+	report.LeasesError = common.ServerError(err)
+	return report, nil
+}
+
+// leaseReports returns a report on each lease held,
+// sorted by namespace.
+func leaseReports() ([]params.LeaseReport, error) {
+	tokensc := make(chan []lease.Token, 1)
+	go func() {
+		tokensc <- leaseLister.CopyOfLeaseTokens()
+	}()
+	var tokens []lease.Token
+	select {
+	case tokens = <-tokensc:
+	case <-time.After(leaseTimeout):
+		return nil, errors.New("timed out waiting for lease manager")
+	}
+	reports := make([]params.LeaseReport, len(tokens))
+	for i, token := range tokens {
+		reports[i] = params.LeaseReport{
+			Namespace: token.Namespace,
+			Holder:    token.Id,
+			Expiry:    token.Expiration,
+		}
+	}
+	sort.Sort(leasesByNamespace(reports))
+	return reports, nil
+}
+
+type leasesByNamespace []params.LeaseReport
+
+func (l leasesByNamespace) Len() int           { return len(l) }
+func (l leasesByNamespace) Less(i, j int) bool { return l[i].Namespace < l[j].Namespace }
+func (l leasesByNamespace) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
@@ -0,0 +1,152 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package introspection_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/introspection"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+)
+
+func TestAll(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type introspectionSuite struct {
+	jujutesting.JujuConnSuite
+	resources  *common.Resources
+	authorizer apiservertesting.FakeAuthorizer
+	source     *fakeSource
+	leases     *fakeLeaseLister
+}
+
+var _ = gc.Suite(&introspectionSuite{})
+
+func (s *introspectionSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.resources = common.NewResources()
+	s.AddCleanup(func(*gc.C) { s.resources.StopAll() })
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	s.source = &fakeSource{
+		report: params.IntrospectionReport{
+			AgentTag: "machine-0",
+			Workers: []params.WorkerReport{{
+				Name:      "api",
+				State:     "running",
+				Restarts:  1,
+				LastError: "boom",
+			}},
+			Connections: []params.ConnectionReport{{
+				Id:            "1",
+				AuthTag:       "user-admin",
+				RemoteAddress: "10.0.0.1:1234",
+			}},
+		},
+	}
+	err := s.resources.RegisterNamed("introspector", s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	s.leases = &fakeLeaseLister{}
+	s.PatchValue(introspection.LeaseListerPtr, introspection.LeaseLister(s.leases))
+}
+
+func (s *introspectionSuite) TestAgentsCannotIntrospect(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("1")
+	_, err := introspection.NewIntrospectionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *introspectionSuite) TestOtherUsersCannotIntrospect(c *gc.C) {
+	user := s.Factory.MakeUser(c, &factory.UserParams{Name: "bob"})
+	s.authorizer.Tag = user.UserTag()
+	_, err := introspection.NewIntrospectionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *introspectionSuite) TestEnvironmentOwnerCanIntrospect(c *gc.C) {
+	owner := s.Factory.MakeUser(c, &factory.UserParams{Name: "carol"})
+	st := s.Factory.MakeEnvironment(c, &factory.EnvParams{Owner: owner.UserTag()})
+	defer st.Close()
+	s.authorizer.Tag = owner.UserTag()
+	_, err := introspection.NewIntrospectionAPI(st, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = introspection.NewIntrospectionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *introspectionSuite) TestNotAvailable(c *gc.C) {
+	_, err := introspection.NewIntrospectionAPI(s.State, common.NewResources(), s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "introspection not available")
+}
+
+func (s *introspectionSuite) TestReport(c *gc.C) {
+	expiry := time.Date(2015, 4, 1, 12, 0, 0, 0, time.UTC)
+	s.leases.tokens = []lease.Token{
+		{Namespace: "wordpress-leadership", Id: "wordpress/1", Expiration: expiry},
+		{Namespace: "mysql-leadership", Id: "mysql/0", Expiration: expiry},
+	}
+	api, err := introspection.NewIntrospectionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	report, err := api.Report()
+	c.Assert(err, jc.ErrorIsNil)
+
+	expect := s.source.report
+	expect.Leases = []params.LeaseReport{
+		{Namespace: "mysql-leadership", Holder: "mysql/0", Expiry: expiry},
+		{Namespace: "wordpress-leadership", Holder: "wordpress/1", Expiry: expiry},
+	}
+	c.Assert(report, jc.DeepEquals, expect)
+}
+
+func (s *introspectionSuite) TestReportLeaseManagerNotResponding(c *gc.C) {
+	s.PatchValue(introspection.LeaseTimeoutPtr, coretesting.ShortWait)
+	s.leases.block = make(chan struct{})
+	defer close(s.leases.block)
+
+	api, err := introspection.NewIntrospectionAPI(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	report, err := api.Report()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(report.AgentTag, gc.Equals, "machine-0")
+	c.Assert(report.Workers, gc.HasLen, 1)
+	c.Assert(report.Leases, gc.HasLen, 0)
+	c.Assert(report.LeasesError, gc.ErrorMatches, "timed out waiting for lease manager")
+}
+
+type fakeSource struct {
+	report params.IntrospectionReport
+}
+
+func (s *fakeSource) Report() params.IntrospectionReport {
+	return s.report
+}
+
+func (s *fakeSource) Stop() error {
+	return nil
+}
+
+type fakeLeaseLister struct {
+	tokens []lease.Token
+	block  chan struct{}
+}
+
+func (l *fakeLeaseLister) CopyOfLeaseTokens() []lease.Token {
+	if l.block != nil {
+		<-l.block
+	}
+	return l.tokens
+}
@@ -16,6 +16,10 @@ type LeadershipService interface {
 	// parameters passed in.
 	ReleaseLeadership(params params.ReleaseLeadershipBulkParams) (params.ReleaseLeadershipBulkResults, error)
 	// BlockUntilLeadershipReleased blocks the caller until leadership is
-	// released for the given service.
-	BlockUntilLeadershipReleased(serviceTag names.ServiceTag) (params.ErrorResult, error)
+	// released for the given service, or the request is cancelled.
+	BlockUntilLeadershipReleased(cancel <-chan struct{}, serviceTag names.ServiceTag) (params.ErrorResult, error)
+	// BlockUntilLeadershipLost blocks the caller until the given unit
+	// no longer holds leadership for the given service, or the request
+	// is cancelled.
+	BlockUntilLeadershipLost(cancel <-chan struct{}, args params.BlockUntilLeadershipLostParams) (params.ErrorResult, error)
 }
@@ -159,13 +159,33 @@ func (m *leadershipService) ReleaseLeadership(args params.ReleaseLeadershipBulkP
 	return params.ReleaseLeadershipBulkResults{results}, nil
 }
 
-// BlockUntilLeadershipReleased implements the LeadershipService interface.
-func (m *leadershipService) BlockUntilLeadershipReleased(serviceTag names.ServiceTag) (params.ErrorResult, error) {
+// BlockUntilLeadershipReleased implements the LeadershipService
+// interface. The cancel channel is closed by the API server when the
+// client cancels the request or the connection closes.
+func (m *leadershipService) BlockUntilLeadershipReleased(cancel <-chan struct{}, serviceTag names.ServiceTag) (params.ErrorResult, error) {
 	if !m.authorizer.AuthUnitAgent() {
 		return params.ErrorResult{Error: common.ServerError(common.ErrPerm)}, nil
 	}
 
-	if err := m.LeadershipManager.BlockUntilLeadershipReleased(serviceTag.Id()); err != nil {
+	if err := m.LeadershipManager.BlockUntilLeadershipReleased(serviceTag.Id(), cancel); err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
+	return params.ErrorResult{}, nil
+}
+
+// BlockUntilLeadershipLost implements the LeadershipService interface.
+// As with BlockUntilLeadershipReleased, the wait ends early when the
+// request is cancelled.
+func (m *leadershipService) BlockUntilLeadershipLost(cancel <-chan struct{}, args params.BlockUntilLeadershipLostParams) (params.ErrorResult, error) {
+	serviceTag, unitTag, err := parseServiceAndUnitTags(args.ServiceTag, args.UnitTag)
+	if err != nil {
+		return params.ErrorResult{Error: common.ServerError(err)}, nil
+	}
+	if !m.authorizer.AuthUnitAgent() || !m.authorizer.AuthOwner(unitTag) {
+		return params.ErrorResult{Error: common.ServerError(common.ErrPerm)}, nil
+	}
+
+	if err := m.LeadershipManager.BlockUntilLeadershipLost(serviceTag.Id(), unitTag.Id(), cancel); err != nil {
 		return params.ErrorResult{Error: common.ServerError(err)}, nil
 	}
 	return params.ErrorResult{}, nil
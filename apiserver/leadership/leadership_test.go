@@ -36,7 +36,8 @@ const (
 type stubLeadershipManager struct {
 	ClaimLeadershipFn              func(sid, uid string, duration time.Duration) error
 	ReleaseLeadershipFn            func(sid, uid string) error
-	BlockUntilLeadershipReleasedFn func(serviceId string) error
+	BlockUntilLeadershipReleasedFn func(serviceId string, cancel <-chan struct{}) error
+	BlockUntilLeadershipLostFn     func(serviceId, unitId string, cancel <-chan struct{}) error
 }
 
 func (m *stubLeadershipManager) ClaimLeadership(sid, uid string, duration time.Duration) error {
@@ -53,9 +54,16 @@ func (m *stubLeadershipManager) ReleaseLeadership(sid, uid string) error {
 	return nil
 }
 
-func (m *stubLeadershipManager) BlockUntilLeadershipReleased(serviceId string) error {
+func (m *stubLeadershipManager) BlockUntilLeadershipReleased(serviceId string, cancel <-chan struct{}) error {
 	if m.BlockUntilLeadershipReleasedFn != nil {
-		return m.BlockUntilLeadershipReleasedFn(serviceId)
+		return m.BlockUntilLeadershipReleasedFn(serviceId, cancel)
+	}
+	return nil
+}
+
+func (m *stubLeadershipManager) BlockUntilLeadershipLost(serviceId, unitId string, cancel <-chan struct{}) error {
+	if m.BlockUntilLeadershipLostFn != nil {
+		return m.BlockUntilLeadershipLostFn(serviceId, unitId, cancel)
 	}
 	return nil
 }
@@ -232,14 +240,37 @@ func (s *leadershipSuite) TestReleaseLeadershipTranslation(c *gc.C) {
 
 func (s *leadershipSuite) TestBlockUntilLeadershipReleasedTranslation(c *gc.C) {
 
+	cancel := make(chan struct{})
+	var ldrMgr stubLeadershipManager
+	ldrMgr.BlockUntilLeadershipReleasedFn = func(sid string, gotCancel <-chan struct{}) error {
+		c.Check(sid, gc.Equals, StubServiceNm)
+		c.Check(gotCancel, gc.Equals, (<-chan struct{})(cancel))
+		return nil
+	}
+
+	ldrSvc := &leadershipService{LeadershipManager: &ldrMgr, authorizer: &stubAuthorizer{}}
+	result, err := ldrSvc.BlockUntilLeadershipReleased(cancel, names.NewServiceTag(StubServiceNm))
+
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result.Error, gc.IsNil)
+}
+
+func (s *leadershipSuite) TestBlockUntilLeadershipLostTranslation(c *gc.C) {
+
+	cancel := make(chan struct{})
 	var ldrMgr stubLeadershipManager
-	ldrMgr.BlockUntilLeadershipReleasedFn = func(sid string) error {
+	ldrMgr.BlockUntilLeadershipLostFn = func(sid, uid string, gotCancel <-chan struct{}) error {
 		c.Check(sid, gc.Equals, StubServiceNm)
+		c.Check(uid, gc.Equals, StubUnitNm)
+		c.Check(gotCancel, gc.Equals, (<-chan struct{})(cancel))
 		return nil
 	}
 
 	ldrSvc := &leadershipService{LeadershipManager: &ldrMgr, authorizer: &stubAuthorizer{}}
-	result, err := ldrSvc.BlockUntilLeadershipReleased(names.NewServiceTag(StubServiceNm))
+	result, err := ldrSvc.BlockUntilLeadershipLost(cancel, params.BlockUntilLeadershipLostParams{
+		ServiceTag: names.NewServiceTag(StubServiceNm).String(),
+		UnitTag:    names.NewUnitTag(StubUnitNm).String(),
+	})
 
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(result.Error, gc.IsNil)
@@ -292,10 +323,26 @@ func (s *leadershipSuite) TestBlockUntilLeadershipReleasedErrors(c *gc.C) {
 	}
 
 	ldrSvc := &leadershipService{LeadershipManager: nil, authorizer: authorizer}
-	result, err := ldrSvc.BlockUntilLeadershipReleased(names.NewServiceTag(StubServiceNm))
+	result, err := ldrSvc.BlockUntilLeadershipReleased(nil, names.NewServiceTag(StubServiceNm))
 
 	// Overall function call should succeed, but operations should
 	// fail with a permissions issue.
 	c.Check(err, jc.ErrorIsNil)
 	c.Check(result.Error, jc.Satisfies, params.IsCodeUnauthorized)
 }
+
+func (s *leadershipSuite) TestBlockUntilLeadershipLostErrors(c *gc.C) {
+	authorizer := &stubAuthorizer{
+		AuthOwnerFn: func(names.Tag) bool { return false },
+	}
+
+	ldrSvc := &leadershipService{LeadershipManager: nil, authorizer: authorizer}
+	result, err := ldrSvc.BlockUntilLeadershipLost(nil, params.BlockUntilLeadershipLostParams{
+		ServiceTag: names.NewServiceTag(StubServiceNm).String(),
+		UnitTag:    names.NewUnitTag(StubUnitNm).String(),
+	})
+
+	// Units can only wait for their own leadership to be lost.
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(result.Error, jc.Satisfies, params.IsCodeUnauthorized)
+}
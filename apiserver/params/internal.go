@@ -486,12 +486,28 @@ type CharmsResponse struct {
 // RunParams is used to provide the parameters to the Run method.
 // Commands and Timeout are expected to have values, and one or more
 // values should be in the Machines, Services, or Units slices.
+// RelationId and RemoteUnitName, if set, give the relation context
+// in which the commands are run on units.
 type RunParams struct {
-	Commands string
-	Timeout  time.Duration
-	Machines []string
-	Services []string
-	Units    []string
+	Commands       string
+	Timeout        time.Duration
+	Machines       []string
+	Services       []string
+	Units          []string
+	RelationId     string
+	RemoteUnitName string
+}
+
+// RunHookParams is used to provide the parameters to the RunHook method.
+// Hook is the name of the hook to run on the unit, such as
+// "config-changed" or "db-relation-changed"; RelationId and
+// RemoteUnitName may be used to choose the context of a relation hook.
+type RunHookParams struct {
+	Unit           string
+	Hook           string
+	RelationId     string
+	RemoteUnitName string
+	Timeout        time.Duration
 }
 
 // RunResult contains the result from an individual run call on a machine.
@@ -0,0 +1,81 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// IntrospectionReport holds the result of an Introspection.Report
+// call, describing the state server that answered it.
+type IntrospectionReport struct {
+	// AgentTag holds the tag of the machine agent
+	// running the API server.
+	AgentTag string
+
+	// Workers holds the workers run by the agent.
+	Workers []WorkerReport
+
+	// Connections holds the API connections currently
+	// open to the API server.
+	Connections []ConnectionReport
+
+	// Leases holds the leases currently held, including
+	// service leadership.
+	Leases []LeaseReport
+
+	// LeasesError holds any error encountered when
+	// retrieving the leases.
+	LeasesError *Error `json:",omitempty"`
+}
+
+// WorkerReport describes a worker run by an agent.
+type WorkerReport struct {
+	// Name holds the name the worker was started with.
+	Name string
+
+	// State holds the state of the worker: "starting",
+	// "running" or "stopping".
+	State string
+
+	// Restarts holds the number of times the worker has
+	// been restarted after exiting with an error.
+	Restarts int
+
+	// LastError holds the error that the worker last exited
+	// with, if any.
+	LastError string `json:",omitempty"`
+
+	// Workers holds the workers run by this worker, if any.
+	Workers []WorkerReport `json:",omitempty"`
+}
+
+// ConnectionReport describes an API connection.
+type ConnectionReport struct {
+	// Id holds the identifier of the connection, as
+	// it appears in the API server's log.
+	Id string
+
+	// AuthTag holds the tag of the entity that has logged
+	// in on the connection, or "" if none has.
+	AuthTag string
+
+	// RemoteAddress holds the address that the
+	// connection was made from.
+	RemoteAddress string
+
+	// Connected holds the time the connection was made.
+	Connected time.Time
+}
+
+// LeaseReport describes a lease.
+type LeaseReport struct {
+	// Namespace holds the thing that the lease is held for.
+	Namespace string
+
+	// Holder holds the id of the lease holder.
+	Holder string
+
+	// Expiry holds the time the lease will expire
+	// unless it is renewed.
+	Expiry time.Time
+}
@@ -52,6 +52,17 @@ type ReleaseLeadershipParams struct {
 // a bulk leadership call.
 type ReleaseLeadershipBulkResults ErrorResults
 
+// BlockUntilLeadershipLostParams are the parameters needed to wait
+// for a unit to lose leadership of its service.
+type BlockUntilLeadershipLostParams struct {
+
+	// ServiceTag is the service whose leadership is held.
+	ServiceTag string
+
+	// UnitTag is the unit which holds leadership.
+	UnitTag string
+}
+
 // GetLeadershipSettingsBulkResults is the collection of results from
 // a bulk request for leadership settings.
 type GetLeadershipSettingsBulkResults struct {
@@ -400,6 +400,27 @@ type MachinePortsResults struct {
 	Results []MachinePortsResult `json:"Results"`
 }
 
+// PortForward describes a range of ports a host machine forwards to
+// one of its containers.
+type PortForward struct {
+	ContainerTag     string    `json:"ContainerTag"`
+	ContainerAddress string    `json:"ContainerAddress"`
+	PortRange        PortRange `json:"PortRange"`
+}
+
+// PortForwardsResult holds a single result of the
+// ProvisionerAPI.PortForwards() API call.
+type PortForwardsResult struct {
+	Error    *Error        `json:"Error"`
+	Forwards []PortForward `json:"Forwards"`
+}
+
+// PortForwardsResults holds all the results of the
+// ProvisionerAPI.PortForwards() API call.
+type PortForwardsResults struct {
+	Results []PortForwardsResult `json:"Results"`
+}
+
 // APIHostPortsResult holds the result of an APIHostPorts
 // call. Each element in the top level slice holds
 // the addresses for one API server.
@@ -412,3 +433,53 @@ type APIHostPortsResult struct {
 func (r APIHostPortsResult) NetworkHostsPorts() [][]network.HostPort {
 	return NetworkHostsPorts(r.Servers)
 }
+
+// Subnet describes a single subnet known to juju.
+type Subnet struct {
+	// CIDR of the subnet, in "123.45.67.89/24" format.
+	CIDR string `json:"CIDR"`
+
+	// ProviderId is the provider-specific subnet id. It may be empty.
+	ProviderId string `json:"ProviderId"`
+
+	// VLANTag needs to be between 1 and 4094 for VLANs and 0 for
+	// normal networks. It's defined by IEEE 802.1Q standard.
+	VLANTag int `json:"VLANTag"`
+
+	// AvailabilityZone is the availability zone the subnet is in. It
+	// is empty if the provider does not support availability zones.
+	AvailabilityZone string `json:"AvailabilityZone"`
+
+	// SpaceName is the name of the space the subnet belongs to. It is
+	// empty if the subnet is not part of any space.
+	SpaceName string `json:"SpaceName"`
+}
+
+// AddSubnetsParams holds the arguments of the AddSubnets API call.
+type AddSubnetsParams struct {
+	Subnets []Subnet `json:"Subnets"`
+}
+
+// ListSubnetsResults holds the result of the ListSubnets API call.
+type ListSubnetsResults struct {
+	Results []Subnet `json:"Results"`
+}
+
+// Space describes a single space: a named group of subnets.
+type Space struct {
+	// Name is the name of the space.
+	Name string `json:"Name"`
+
+	// SubnetCIDRs holds the CIDRs of the subnets in the space.
+	SubnetCIDRs []string `json:"SubnetCIDRs"`
+}
+
+// CreateSpacesParams holds the arguments of the CreateSpaces API call.
+type CreateSpacesParams struct {
+	Spaces []Space `json:"Spaces"`
+}
+
+// ListSpacesResults holds the result of the ListSpaces API call.
+type ListSpacesResults struct {
+	Results []Space `json:"Results"`
+}
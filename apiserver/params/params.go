@@ -102,6 +102,15 @@ type AddRelation struct {
 	Endpoints []string
 }
 
+// AddCrossEnvironmentRelation holds the parameters for making the
+// AddCrossEnvironmentRelation call. Endpoint names a service of this
+// environment, and OfferedEndpoint one offered by the named environment.
+type AddCrossEnvironmentRelation struct {
+	Endpoint        string
+	Environment     string
+	OfferedEndpoint string
+}
+
 // AddRelationResults holds the results of a AddRelation call. The Endpoints
 // field maps service names to the involved endpoints.
 type AddRelationResults struct {
@@ -114,6 +123,33 @@ type DestroyRelation struct {
 	Endpoints []string
 }
 
+// ShowRelation holds the parameters for making the ShowRelation call.
+// The relation is identified by its endpoints, which are unordered, or
+// by its id if no endpoints are given.
+type ShowRelation struct {
+	RelationId int
+	Endpoints  []string
+}
+
+// RelationDetails holds the results of a ShowRelation call.
+type RelationDetails struct {
+	Id        int
+	Key       string
+	Life      Life
+	Endpoints map[string]charm.Relation
+	Units     []RelationUnitDetails
+}
+
+// RelationUnitDetails describes a unit in scope in a relation.
+// SettingsChanged is nil if the time of the last change to the unit's
+// settings is not known.
+type RelationUnitDetails struct {
+	UnitName        string
+	Departing       bool
+	SettingsChanged *time.Time
+	Settings        map[string]interface{}
+}
+
 // AddMachineParams encapsulates the parameters used to create a new machine.
 type AddMachineParams struct {
 	// The following fields hold attributes that will be given to the
@@ -192,6 +228,9 @@ type ServiceDeploy struct {
 	ToMachineSpec string
 	Networks      []string
 	Storage       map[string]storage.Constraints
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they are bound to.
+	EndpointBindings map[string]string
 }
 
 // ServiceUpdate holds the parameters for making the ServiceUpdate call.
@@ -212,6 +251,44 @@ type ServiceSetCharm struct {
 	Force       bool
 }
 
+// ServiceSetCharmInBatches holds the parameters for making the
+// ServiceSetCharmInBatches call, which sets the charm for a given
+// service and upgrades its units in batches of BatchSize; see
+// state.Service.SetCharmInBatches.
+type ServiceSetCharmInBatches struct {
+	ServiceName  string
+	CharmUrl     string
+	Force        bool
+	BatchSize    int
+	PauseOnError bool
+	BatchTimeout time.Duration
+}
+
+// ServiceRevertCharm holds the parameters for making the
+// ServiceRevertCharm call. A positive BatchSize upgrades the units
+// in batches, as for ServiceSetCharmInBatches.
+type ServiceRevertCharm struct {
+	ServiceName  string
+	Force        bool
+	BatchSize    int
+	PauseOnError bool
+	BatchTimeout time.Duration
+}
+
+// ServiceResumeCharmUpgrade holds the parameters for resuming the
+// paused rolling charm upgrade of a service.
+type ServiceResumeCharmUpgrade struct {
+	ServiceName string
+}
+
+// ServiceOffer holds the parameters for making the ServiceOffer call.
+// If no endpoints are given, all those of the service that can be
+// offered are.
+type ServiceOffer struct {
+	ServiceName string
+	Endpoints   []string
+}
+
 // ServiceExpose holds the parameters for making the ServiceExpose call.
 type ServiceExpose struct {
 	ServiceName string
@@ -300,6 +377,22 @@ type PrivateAddressResults struct {
 	PrivateAddress string
 }
 
+// ServiceLeader holds parameters for the ServiceLeader call.
+type ServiceLeader struct {
+	ServiceName string
+}
+
+// ServiceLeaderResults holds results of the ServiceLeader call.
+// UnitName is empty if the service has no leader.
+type ServiceLeaderResults struct {
+	UnitName string
+}
+
+// SetServiceLeader holds parameters for the SetServiceLeader call.
+type SetServiceLeader struct {
+	UnitName string
+}
+
 // Resolved holds parameters for the Resolved call.
 type Resolved struct {
 	UnitName string
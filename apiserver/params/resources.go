@@ -0,0 +1,46 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import (
+	"time"
+)
+
+// ServiceResource describes the current revision of a resource
+// attached to a service.
+type ServiceResource struct {
+	Name     string
+	Revision int
+	Size     int64
+	SHA384   string
+	Uploaded time.Time
+}
+
+// ServiceResourcesResult holds the resources of a single service,
+// or an error.
+type ServiceResourcesResult struct {
+	Resources []ServiceResource
+	Error     *Error
+}
+
+// ServiceResourcesResults holds the results of the ServiceResources
+// API call.
+type ServiceResourcesResults struct {
+	Results []ServiceResourcesResult
+}
+
+// ResourceUploadArgs holds the metadata sent alongside resource
+// content uploaded over HTTP.
+type ResourceUploadArgs struct {
+	ServiceName string
+	Name        string
+	Size        int64
+}
+
+// ResourceDownloadArgs holds the args for downloading resource
+// content over HTTP.
+type ResourceDownloadArgs struct {
+	ServiceName string
+	Name        string
+}
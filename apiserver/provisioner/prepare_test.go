@@ -276,10 +276,6 @@ func (s *prepareSuite) TestErrorWithEnvironMethodsFailing(c *gc.C) {
 	}, {
 		method: "Subnets",
 		err:    "cannot allocate addresses: dummy.Subnets is broken",
-	}, {
-		method:   "SupportsAddressAllocation",
-		err:      "cannot allocate addresses: address allocation on any available subnets is not supported",
-		errCheck: errors.IsNotSupported,
 	}} {
 		c.Logf("test %d: broken %q", i, test.method)
 		s.breakEnvironMethods(c, test.method)
@@ -293,6 +289,106 @@ func (s *prepareSuite) TestErrorWithEnvironMethodsFailing(c *gc.C) {
 	}
 }
 
+func (s *prepareSuite) TestNoConfigWithoutAddressAllocation(c *gc.C) {
+	container := s.newAPI(c, true, true)
+	err := s.machines[0].SetAddresses(
+		network.NewAddress("0.1.2.3", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.makeArgs(container)
+
+	s.breakEnvironMethods(c, "SupportsAddressAllocation")
+	s.assertCall(c, args, s.makeResults(nil), "")
+
+	// The container's addresses are left alone.
+	err = container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Addresses(), gc.HasLen, 0)
+}
+
+func (s *prepareSuite) assertPortForwardingCall(c *gc.C, args params.Entities, expectResults params.ErrorResults) []loggo.TestLogValues {
+	logger := loggo.GetLogger("juju.apiserver.provisioner")
+	defer logger.SetLogLevel(logger.LogLevel())
+	logger.SetLogLevel(loggo.TRACE)
+	var tw loggo.TestWriter
+	c.Assert(loggo.RegisterWriter("test", &tw, loggo.TRACE), gc.IsNil)
+	defer loggo.RemoveWriter("test")
+
+	results, err := s.provAPI.PrepareContainerPortForwarding(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, jc.DeepEquals, expectResults)
+	return tw.Log()
+}
+
+func (s *prepareSuite) TestPrepareContainerPortForwarding(c *gc.C) {
+	container := s.newAPI(c, true, true)
+	err := s.machines[0].SetAddresses(
+		network.NewAddress("0.1.2.3", network.ScopeCloudLocal),
+		network.NewAddress("127.0.0.1", network.ScopeMachineLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.makeArgs(container)
+
+	logs := s.assertPortForwardingCall(c, args, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	c.Assert(logs, jc.LogMatches, jc.SimpleMessages{{
+		loggo.INFO,
+		`container "0/lxc/0" will be reached through host machine "0"`,
+	}})
+
+	// The container is given the host's reachable addresses.
+	err = container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Addresses(), jc.DeepEquals, []network.Address{
+		network.NewAddress("0.1.2.3", network.ScopeCloudLocal),
+	})
+}
+
+func (s *prepareSuite) TestPrepareContainerPortForwardingKeepsAddresses(c *gc.C) {
+	container := s.newAPI(c, true, true)
+	err := s.machines[0].SetAddresses(
+		network.NewAddress("0.1.2.3", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetAddresses(
+		network.NewAddress("0.1.2.4", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	args := s.makeArgs(container)
+
+	logs := s.assertPortForwardingCall(c, args, params.ErrorResults{
+		Results: []params.ErrorResult{{}},
+	})
+	c.Assert(logs, jc.LogMatches, jc.SimpleMessages{{
+		loggo.INFO,
+		`container "0/lxc/0" already has addresses; not forwarding ports`,
+	}})
+
+	err = container.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(container.Addresses(), jc.DeepEquals, []network.Address{
+		network.NewAddress("0.1.2.4", network.ScopeCloudLocal),
+	})
+}
+
+func (s *prepareSuite) TestPrepareContainerPortForwardingErrors(c *gc.C) {
+	container := s.newAPI(c, true, true)
+	args := s.makeArgs(container, s.machines[0])
+
+	s.assertPortForwardingCall(c, args, params.ErrorResults{
+		Results: []params.ErrorResult{{
+			Error: apiservertesting.ServerError(
+				`cannot forward ports to "0/lxc/0": host machine "0" has no addresses`,
+			),
+		}, {
+			Error: apiservertesting.ServerError(
+				`cannot allocate address for "machine-0": not a container`,
+			),
+		}},
+	})
+}
+
 func (s *prepareSuite) TestRetryingOnAllocateAddressFailure(c *gc.C) {
 	// This test verifies the retrying logic when AllocateAddress
 	// and/or setAddrState return errors.
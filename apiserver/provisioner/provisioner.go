@@ -5,6 +5,7 @@ package provisioner
 
 import (
 	"fmt"
+	"sort"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -225,17 +226,12 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 	if err != nil {
 		return result, err
 	}
-	if netEnv, ok := environs.SupportsNetworking(env); ok {
-		// Passing network.AnySubnet below should be interpreted by
-		// the provider as "does ANY subnet support this".
-		supported, err := netEnv.SupportsAddressAllocation(network.AnySubnet)
-		if err == nil && supported {
-			cfg[container.ConfigIPForwarding] = "true"
-		} else if err != nil {
-			// We log the error, but it's safe to ignore as it's not
-			// critical.
-			logger.Debugf("address allocation not supported (%v)", err)
-		}
+	if supportsAddressAllocation(env) {
+		cfg[container.ConfigIPForwarding] = "true"
+	} else {
+		// Containers cannot be given addresses of their own, so
+		// the host will forward their opened ports to them.
+		cfg[container.ConfigPortForwarding] = "true"
 	}
 
 	switch args.Type {
@@ -251,6 +247,25 @@ func (p *ProvisionerAPI) ContainerManagerConfig(args params.ContainerManagerConf
 	return result, nil
 }
 
+// supportsAddressAllocation reports whether the environment can
+// allocate addresses for containers on any subnet.
+func supportsAddressAllocation(env environs.Environ) bool {
+	netEnv, ok := environs.SupportsNetworking(env)
+	if !ok {
+		return false
+	}
+	// Passing network.AnySubnet below should be interpreted by
+	// the provider as "does ANY subnet support this".
+	supported, err := netEnv.SupportsAddressAllocation(network.AnySubnet)
+	if err != nil {
+		// We log the error, but it's safe to ignore as it's not
+		// critical.
+		logger.Debugf("address allocation not supported (%v)", err)
+		return false
+	}
+	return supported
+}
+
 // ContainerConfig returns information from the environment config that is
 // needed for container cloud-init.
 func (p *ProvisionerAPI) ContainerConfig() (params.ContainerConfig, error) {
@@ -800,18 +815,155 @@ func (p *ProvisionerAPI) WatchMachineErrorRetry() (params.NotifyWatchResult, err
 	return result, nil
 }
 
+// WatchContainerPorts returns a NotifyWatcher for each given host
+// machine, notifying of changes to the ports opened on its containers
+// or to the containers themselves.
+func (p *ProvisionerAPI) WatchContainerPorts(args params.Entities) (params.NotifyWatchResults, error) {
+	result := params.NotifyWatchResults{
+		Results: make([]params.NotifyWatchResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		watch := machine.WatchContainerPorts()
+		// Consume the initial event and forward it to the result.
+		if _, ok := <-watch.Changes(); ok {
+			result.Results[i].NotifyWatcherId = p.resources.Register(watch)
+		} else {
+			err = watcher.EnsureErr(watch)
+			result.Results[i].Error = common.ServerError(err)
+		}
+	}
+	return result, nil
+}
+
+// PortForwards returns, for each given host machine, the port ranges
+// it should forward to its containers: those opened by the units on
+// each container reached through the host that has reported an
+// address.
+func (p *ProvisionerAPI) PortForwards(args params.Entities) (params.PortForwardsResults, error) {
+	result := params.PortForwardsResults{
+		Results: make([]params.PortForwardsResult, len(args.Entities)),
+	}
+	canAccess, err := p.getAuthFunc()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseMachineTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		machine, err := p.getMachine(canAccess, tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		forwards, err := p.portForwards(machine)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Forwards = forwards
+	}
+	return result, nil
+}
+
+// portForwards returns the port ranges the given host should forward
+// to its containers.
+func (p *ProvisionerAPI) portForwards(host *state.Machine) ([]params.PortForward, error) {
+	containerIds, err := host.Containers()
+	if errors.IsNotFound(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	sort.Strings(containerIds)
+	hostAddresses := forwardingHostAddresses(host)
+	var forwards []params.PortForward
+	for _, id := range containerIds {
+		container, err := p.st.Machine(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if container.Life() == state.Dead {
+			continue
+		}
+		if !isForwardedContainer(container, hostAddresses) {
+			// The container has addresses of its own.
+			continue
+		}
+		// Use the address the container reported itself, as the
+		// provider addresses of a forwarded container are those of
+		// its host.
+		address := network.SelectInternalAddress(container.MachineAddresses(), false)
+		if address == "" {
+			logger.Debugf("container %q has no address yet; not forwarding ports", container)
+			continue
+		}
+		allPorts, err := container.AllPorts()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var portRanges []network.PortRange
+		for _, ports := range allPorts {
+			for portRange := range ports.AllPortRanges() {
+				portRanges = append(portRanges, portRange)
+			}
+		}
+		network.SortPortRanges(portRanges)
+		for _, portRange := range portRanges {
+			forwards = append(forwards, params.PortForward{
+				ContainerTag:     container.Tag().String(),
+				ContainerAddress: address,
+				PortRange:        params.FromNetworkPortRange(portRange),
+			})
+		}
+	}
+	return forwards, nil
+}
+
 // PrepareContainerInterfaceInfo allocates an address and returns
 // information for configuring networking on a container. It accepts
-// container tags as arguments.
+// container tags as arguments. When the environment does not support
+// address allocation, no configuration is returned, and the containers
+// will use their host's bridge.
 func (p *ProvisionerAPI) PrepareContainerInterfaceInfo(args params.Entities) (params.MachineNetworkConfigResults, error) {
 	result := params.MachineNetworkConfigResults{
 		Results: make([]params.MachineNetworkConfigResult, len(args.Entities)),
 	}
 	// Some preparations first.
-	environ, host, canAccess, err := p.prepareAllocationEnvironment()
+	env, host, canAccess, err := p.prepareAllocationEnvironment()
 	if err != nil {
 		return result, errors.Trace(err)
 	}
+	if !supportsAddressAllocation(env) {
+		// The containers will use their host's bridge; whether they
+		// need to be reached through their host is decided by the
+		// provisioner, which knows the bridge.
+		for i, entity := range args.Entities {
+			if _, err := p.getUnprovisionedContainer(canAccess, entity); err != nil {
+				result.Results[i].Error = common.ServerError(err)
+			}
+		}
+		return result, nil
+	}
+	environ, _ := environs.SupportsNetworking(env)
 	instId, err := host.InstanceId()
 	if err != nil && errors.IsNotProvisioned(err) {
 		// If the host machine is not provisioned yet, we have nothing
@@ -826,34 +978,10 @@ func (p *ProvisionerAPI) PrepareContainerInterfaceInfo(args params.Entities) (pa
 	}
 	// Loop over the passed container tags.
 	for i, entity := range args.Entities {
-		tag, err := names.ParseMachineTag(entity.Tag)
+		container, err := p.getUnprovisionedContainer(canAccess, entity)
 		if err != nil {
-			result.Results[i].Error = common.ServerError(common.ErrPerm)
-			continue
-		}
-
-		// The auth function (canAccess) checks that the machine is a
-		// top level machine (we filter those out next) or that the
-		// machine has the host as a parent.
-		container, err := p.getMachine(canAccess, tag)
-		if err != nil {
-			result.Results[i].Error = common.ServerError(err)
-			continue
-		} else if !container.IsContainer() {
-			err = errors.Errorf("cannot allocate address for %q: not a container", tag)
 			result.Results[i].Error = common.ServerError(err)
 			continue
-		} else if ciid, cerr := container.InstanceId(); cerr == nil {
-			// Since we want to configure and create NICs on the
-			// container before it starts, it must also be not
-			// provisioned yet.
-			err = errors.Errorf("container %q already provisioned as %q", container, ciid)
-			result.Results[i].Error = common.ServerError(err)
-			continue
-		} else if cerr != nil && !errors.IsNotProvisioned(cerr) {
-			// Any other error needs to be reported.
-			result.Results[i].Error = common.ServerError(cerr)
-			continue
 		}
 
 		// Allocate and set address.
@@ -895,9 +1023,113 @@ func (p *ProvisionerAPI) PrepareContainerInterfaceInfo(args params.Entities) (pa
 	return result, nil
 }
 
+// getUnprovisionedContainer returns the container machine for the
+// given entity, checking it is a container that is accessible and not
+// yet provisioned.
+func (p *ProvisionerAPI) getUnprovisionedContainer(canAccess common.AuthFunc, entity params.Entity) (*state.Machine, error) {
+	tag, err := names.ParseMachineTag(entity.Tag)
+	if err != nil {
+		return nil, common.ErrPerm
+	}
+	// The auth function (canAccess) checks that the machine is a
+	// top level machine (we filter those out next) or that the
+	// machine has the host as a parent.
+	container, err := p.getMachine(canAccess, tag)
+	if err != nil {
+		return nil, err
+	} else if !container.IsContainer() {
+		return nil, errors.Errorf("cannot allocate address for %q: not a container", tag)
+	} else if ciid, cerr := container.InstanceId(); cerr == nil {
+		// Since we want to configure and create NICs on the
+		// container before it starts, it must also be not
+		// provisioned yet.
+		return nil, errors.Errorf("container %q already provisioned as %q", container, ciid)
+	} else if !errors.IsNotProvisioned(cerr) {
+		// Any other error needs to be reported.
+		return nil, cerr
+	}
+	return container, nil
+}
+
+// PrepareContainerPortForwarding prepares the given containers, which
+// are on their host's NAT bridge, to be reached through their host:
+// containers without addresses are given the host's addresses, and the
+// host will forward the ports opened on the containers to them.
+// Addresses the containers already have are never replaced.
+func (p *ProvisionerAPI) PrepareContainerPortForwarding(args params.Entities) (params.ErrorResults, error) {
+	result := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Entities)),
+	}
+	_, host, canAccess, err := p.prepareAllocationEnvironment()
+	if err != nil {
+		return result, errors.Trace(err)
+	}
+	hostAddresses := forwardingHostAddresses(host)
+	for i, entity := range args.Entities {
+		container, err := p.getUnprovisionedContainer(canAccess, entity)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if len(container.Addresses()) > 0 {
+			logger.Infof("container %q already has addresses; not forwarding ports", container)
+			continue
+		}
+		if len(hostAddresses) == 0 {
+			err = errors.Errorf("cannot forward ports to %q: host machine %q has no addresses", container, host)
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		if err := container.SetAddresses(hostAddresses...); err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		logger.Infof("container %q will be reached through host machine %q", container, host)
+	}
+	return result, nil
+}
+
+// forwardingHostAddresses returns the addresses of the given host
+// through which its forwarded containers are reached.
+func forwardingHostAddresses(host *state.Machine) []network.Address {
+	var addresses []network.Address
+	for _, addr := range host.Addresses() {
+		switch addr.Scope {
+		case network.ScopeMachineLocal, network.ScopeLinkLocal:
+			// Not reachable from other machines.
+			continue
+		}
+		addresses = append(addresses, addr)
+	}
+	return addresses
+}
+
+// isForwardedContainer reports whether the container is reached
+// through its host, i.e. it was given the host's addresses by
+// PrepareContainerPortForwarding.
+func isForwardedContainer(container *state.Machine, hostAddresses []network.Address) bool {
+	containerAddresses := container.Addresses()
+	if len(containerAddresses) == 0 {
+		return false
+	}
+	for _, addr := range containerAddresses {
+		found := false
+		for _, hostAddr := range hostAddresses {
+			if addr.Value == hostAddr.Value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
 // prepareAllocationEnvironment retrieves the environment, host machine, and access
 // for the allocations.
-func (p *ProvisionerAPI) prepareAllocationEnvironment() (environs.NetworkingEnviron, *state.Machine, common.AuthFunc, error) {
+func (p *ProvisionerAPI) prepareAllocationEnvironment() (environs.Environ, *state.Machine, common.AuthFunc, error) {
 	cfg, err := p.st.EnvironConfig()
 	if err != nil {
 		return nil, nil, nil, errors.Annotate(err, "failed to get environment config")
@@ -906,11 +1138,6 @@ func (p *ProvisionerAPI) prepareAllocationEnvironment() (environs.NetworkingEnvi
 	if err != nil {
 		return nil, nil, nil, errors.Annotate(err, "failed to construct an environment from config")
 	}
-	netEnviron, supported := environs.SupportsNetworking(environ)
-	if !supported {
-		// " not supported" will be appended to the message below.
-		return nil, nil, nil, errors.NotSupportedf("environment %q networking", cfg.Name())
-	}
 
 	canAccess, err := p.getAuthFunc()
 	if err != nil {
@@ -928,7 +1155,7 @@ func (p *ProvisionerAPI) prepareAllocationEnvironment() (environs.NetworkingEnvi
 	if err != nil {
 		return nil, nil, nil, errors.Trace(err)
 	}
-	return netEnviron, host, canAccess, nil
+	return environ, host, canAccess, nil
 }
 
 // prepareAllocationNetwork retrieves the subnet, its info, and the interface info
@@ -530,6 +530,114 @@ func (s *withoutStateServerSuite) TestWatchAllContainers(c *gc.C) {
 	wc1.AssertNoChange()
 }
 
+func (s *withoutStateServerSuite) TestWatchContainerPorts(c *gc.C) {
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}}
+	result, err := s.provisioner.WatchContainerPorts(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.NotifyWatchResults{
+		Results: []params.NotifyWatchResult{
+			{NotifyWatcherId: "1"},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	// Verify the resource was registered and stop it when done.
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	// Check that the Watch has consumed the initial event ("returned"
+	// in the Watch call)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, resource.(state.NotifyWatcher))
+	wc.AssertNoChange()
+
+	// Adding a container triggers an event.
+	_, err = s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machines[0].Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
+
+func (s *withoutStateServerSuite) TestPortForwards(c *gc.C) {
+	template := state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}
+	hostAddress := network.NewAddress("0.1.2.3", network.ScopeCloudLocal)
+	err := s.machines[0].SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(template, s.machines[0].Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	err = container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, jc.ErrorIsNil)
+	// Ports are not forwarded to containers without an address.
+	unaddressed, err := s.State.AddMachineInsideMachine(template, s.machines[0].Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unaddressed.SetAddresses(hostAddress)
+	c.Assert(err, jc.ErrorIsNil)
+	// Nor to containers with addresses of their own.
+	bridged, err := s.State.AddMachineInsideMachine(template, s.machines[0].Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	err = bridged.SetAddresses(network.NewAddress("0.1.2.4", network.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+	err = bridged.SetMachineAddresses(network.NewAddress("0.1.2.4", network.ScopeUnknown))
+	c.Assert(err, jc.ErrorIsNil)
+
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPorts("tcp", 8000, 8010)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, err = svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.AssignToMachine(bridged)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: s.machines[0].Tag().String()},
+		{Tag: s.machines[1].Tag().String()},
+		{Tag: container.Tag().String()},
+		{Tag: "machine-42"},
+		{Tag: "unit-foo-0"},
+	}}
+	result, err := s.provisioner.PortForwards(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, params.PortForwardsResults{
+		Results: []params.PortForwardsResult{
+			{Forwards: []params.PortForward{{
+				ContainerTag:     "machine-0-lxc-0",
+				ContainerAddress: "10.0.3.5",
+				PortRange:        params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+			}, {
+				ContainerTag:     "machine-0-lxc-0",
+				ContainerAddress: "10.0.3.5",
+				PortRange:        params.PortRange{FromPort: 8000, ToPort: 8010, Protocol: "tcp"},
+			}}},
+			{},
+			{Error: apiservertesting.ErrUnauthorized},
+			{Error: apiservertesting.NotFoundError("machine 42")},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+}
+
 func (s *withoutStateServerSuite) TestEnvironConfigNonManager(c *gc.C) {
 	// Now test it with a non-environment manager and make sure
 	// the secret attributes are masked.
@@ -1292,6 +1400,10 @@ func (s *withoutStateServerSuite) TestContainerManagerConfigNoIPForwarding(c *gc
 	cfg := s.getManagerConfig(c, instance.KVM)
 	c.Assert(cfg, jc.DeepEquals, map[string]string{
 		container.ConfigName: "juju",
+
+		// Without address allocation, the host forwards ports to
+		// its containers instead.
+		container.ConfigPortForwarding: "true",
 	})
 }
 
@@ -0,0 +1,95 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"reflect"
+	"time"
+
+	"github.com/juju/ratelimit"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/rpcreflect"
+)
+
+// maxRequestWait holds the longest time that a rate limited
+// request will be delayed before it is rejected with
+// common.ErrTryAgain.
+var maxRequestWait = 5 * time.Second
+
+// rateLimitedRoot limits the rate at which API calls are made
+// through it. Each call takes a token from the bucket; if none
+// is available soon enough the call fails with common.ErrTryAgain.
+type rateLimitedRoot struct {
+	rpc.MethodFinder
+	bucket *ratelimit.Bucket
+}
+
+// newRateLimitedRoot returns a new rateLimitedRoot that takes
+// tokens from the given bucket.
+func newRateLimitedRoot(finder rpc.MethodFinder, bucket *ratelimit.Bucket) *rateLimitedRoot {
+	return &rateLimitedRoot{finder, bucket}
+}
+
+// FindMethod implements rpc.MethodFinder. Pinger calls are not
+// limited, so that a busy agent is not mistaken for a dead one.
+func (r *rateLimitedRoot) FindMethod(rootName string, version int, methodName string) (rpcreflect.MethodCaller, error) {
+	caller, err := r.MethodFinder.FindMethod(rootName, version, methodName)
+	if err != nil {
+		return nil, err
+	}
+	if rootName == "Pinger" {
+		return caller, nil
+	}
+	return &rateLimitedCaller{caller, r.bucket}, nil
+}
+
+// Kill implements rpc.Killer by calling Kill on the
+// underlying root, if it has one.
+func (r *rateLimitedRoot) Kill() {
+	if killer, ok := r.MethodFinder.(rpc.Killer); ok {
+		killer.Kill()
+	}
+}
+
+// Cleanup implements rpc.Cleaner by calling Cleanup on the
+// underlying root, if it has one.
+func (r *rateLimitedRoot) Cleanup() {
+	if cleaner, ok := r.MethodFinder.(rpc.Cleaner); ok {
+		cleaner.Cleanup()
+	}
+}
+
+// rateLimitedCaller waits for a token from the bucket
+// before calling the underlying method.
+type rateLimitedCaller struct {
+	rpcreflect.MethodCaller
+	bucket *ratelimit.Bucket
+}
+
+// Call implements rpcreflect.MethodCaller.
+func (c *rateLimitedCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	return c.CallCancel(objId, arg, nil)
+}
+
+// CallCancel implements rpcreflect.CancelCaller.
+func (c *rateLimitedCaller) CallCancel(objId string, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error) {
+	wait, ok := c.bucket.TakeMaxDuration(1, maxRequestWait)
+	if !ok {
+		logger.Debugf("rate limiting, try again later")
+		return reflect.Value{}, common.ErrTryAgain
+	}
+	if wait > 0 {
+		select {
+		case <-time.After(wait):
+		case <-cancel:
+			return reflect.Value{}, rpc.ErrCancelled
+		}
+	}
+	if canceller, ok := c.MethodCaller.(rpcreflect.CancelCaller); ok {
+		return canceller.CallCancel(objId, arg, cancel)
+	}
+	return c.MethodCaller.Call(objId, arg)
+}
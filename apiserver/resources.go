@@ -0,0 +1,167 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/common"
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+// resourcesHandler handles the upload and download of the content of
+// service resources. Users may upload and download any resource; unit
+// agents may only download the resources of their own service.
+type resourcesHandler struct {
+	httpHandler
+}
+
+func (h *resourcesHandler) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
+	// Validate before authenticate because the authentication is dependent
+	// on the state connection that is determined during the validation.
+	stateWrapper, err := h.validateEnvironUUID(req)
+	if err != nil {
+		h.sendError(resp, http.StatusNotFound, err.Error())
+		return
+	}
+	defer stateWrapper.cleanup()
+
+	authTag, err := stateWrapper.authenticateTag(req, names.UserTagKind, names.UnitTagKind)
+	if err != nil {
+		h.authError(resp, h)
+		return
+	}
+
+	switch req.Method {
+	case "GET":
+		if err := h.download(stateWrapper.state, authTag, resp, req); err != nil {
+			h.sendServerError(resp, resourceErrorStatus(err), err)
+		}
+	case "PUT":
+		if authTag.Kind() != names.UserTagKind {
+			h.authError(resp, h)
+			return
+		}
+		result, err := h.upload(stateWrapper.state, req)
+		if err != nil {
+			h.sendServerError(resp, resourceErrorStatus(err), err)
+			return
+		}
+		h.sendJSON(resp, http.StatusOK, result)
+	default:
+		h.sendError(resp, http.StatusMethodNotAllowed, fmt.Sprintf("unsupported method: %q", req.Method))
+	}
+}
+
+// download streams the content of the requested resource.
+func (h *resourcesHandler) download(st *state.State, authTag names.Tag, resp http.ResponseWriter, req *http.Request) error {
+	defer req.Body.Close()
+	var args params.ResourceDownloadArgs
+	if err := json.NewDecoder(req.Body).Decode(&args); err != nil {
+		return errors.Annotate(err, "while de-serializing args")
+	}
+	if unitTag, ok := authTag.(names.UnitTag); ok {
+		unit, err := st.Unit(unitTag.Id())
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if unit.ServiceName() != args.ServiceName {
+			return common.ErrPerm
+		}
+	}
+	service, err := st.Service(args.ServiceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	resource, reader, err := service.OpenResource(args.Name)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer reader.Close()
+
+	resp.Header().Set("Content-Type", apihttp.CTypeRaw)
+	resp.Header().Set("Content-Length", fmt.Sprint(resource.Size))
+	resp.WriteHeader(http.StatusOK)
+	if _, err := io.Copy(resp, reader); err != nil {
+		// The response has already started, so the error can
+		// only be logged.
+		logger.Errorf("while streaming resource %q of service %q: %v", args.Name, args.ServiceName, err)
+	}
+	return nil
+}
+
+// upload stores the attached content as a new revision of the
+// resource described by the attached metadata.
+func (h *resourcesHandler) upload(st *state.State, req *http.Request) (*params.ServiceResource, error) {
+	defer req.Body.Close()
+	var args params.ResourceUploadArgs
+	content, err := apihttp.ExtractRequestAttachment(req, &args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer content.Close()
+	service, err := st.Service(args.ServiceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	resource, err := service.SetResource(args.Name, content, args.Size)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	logger.Infof("stored revision %d of resource %q of service %q", resource.Revision, resource.Name, args.ServiceName)
+	return &params.ServiceResource{
+		Name:     resource.Name,
+		Revision: resource.Revision,
+		Size:     resource.Size,
+		SHA384:   resource.SHA384,
+		Uploaded: resource.Uploaded,
+	}, nil
+}
+
+// resourceErrorStatus returns the HTTP status code that best describes
+// the given error.
+func resourceErrorStatus(err error) int {
+	switch {
+	case errors.IsNotFound(errors.Cause(err)):
+		return http.StatusNotFound
+	case errors.Cause(err) == common.ErrPerm:
+		return http.StatusForbidden
+	case errors.IsNotValid(errors.Cause(err)):
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
+
+// sendJSON sends a JSON-encoded result.
+func (h *resourcesHandler) sendJSON(w http.ResponseWriter, statusCode int, result interface{}) {
+	body, err := json.Marshal(result)
+	if err != nil {
+		logger.Errorf("failed to serialize the result (%v): %v", result, err)
+		return
+	}
+	w.Header().Set("Content-Type", apihttp.CTypeJSON)
+	w.WriteHeader(statusCode)
+	w.Write(body)
+}
+
+// sendError sends a JSON-encoded error response.
+func (h *resourcesHandler) sendError(w http.ResponseWriter, statusCode int, message string) {
+	h.sendServerError(w, statusCode, errors.New(message))
+}
+
+// sendServerError sends a JSON-encoded error response, preserving the
+// error code so that clients can recognise, for example, missing
+// resources.
+func (h *resourcesHandler) sendServerError(w http.ResponseWriter, statusCode int, err error) {
+	logger.Debugf("sending error: %v %v", statusCode, err)
+	h.sendJSON(w, statusCode, common.ServerError(err))
+}
@@ -0,0 +1,152 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package apiserver_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	gc "gopkg.in/check.v1"
+
+	apihttp "github.com/juju/juju/apiserver/http"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
+)
+
+type resourcesSuite struct {
+	authHttpSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&resourcesSuite{})
+
+func (s *resourcesSuite) SetUpTest(c *gc.C) {
+	s.authHttpSuite.SetUpTest(c)
+	s.service = s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress"})
+}
+
+func (s *resourcesSuite) resourcesURL(c *gc.C) string {
+	uri := s.baseURL(c)
+	uri.Path = fmt.Sprintf("/environment/%s/resources", s.envUUID)
+	return uri.String()
+}
+
+func (s *resourcesSuite) makeUnit(c *gc.C) (*state.Unit, string) {
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Service: s.service})
+	password, err := utils.RandomPassword()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetPassword(password)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit, password
+}
+
+func (s *resourcesSuite) upload(c *gc.C, tag, password string, args params.ResourceUploadArgs, content string) *http.Response {
+	req, err := http.NewRequest("PUT", s.resourcesURL(c), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	req.SetBasicAuth(tag, password)
+	err = apihttp.AttachToRequest(req, strings.NewReader(content), args, args.Name)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := utils.GetNonValidatingHTTPClient().Do(req)
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func (s *resourcesSuite) download(c *gc.C, tag, password string, args params.ResourceDownloadArgs) *http.Response {
+	body, err := json.Marshal(args)
+	c.Assert(err, jc.ErrorIsNil)
+	resp, err := s.sendRequest(c, tag, password, "GET", s.resourcesURL(c), apihttp.CTypeJSON, bytes.NewReader(body))
+	c.Assert(err, jc.ErrorIsNil)
+	return resp
+}
+
+func (s *resourcesSuite) assertError(c *gc.C, resp *http.Response, expCode int, expError string) {
+	body := assertResponse(c, resp, expCode, apihttp.CTypeJSON)
+	var failure params.Error
+	err := json.Unmarshal(body, &failure)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(&failure, gc.ErrorMatches, expError)
+}
+
+func (s *resourcesSuite) TestRequiresAuth(c *gc.C) {
+	resp, err := s.sendRequest(c, "", "", "GET", s.resourcesURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertError(c, resp, http.StatusUnauthorized, "unauthorized")
+}
+
+func (s *resourcesSuite) TestInvalidMethod(c *gc.C) {
+	resp, err := s.authRequest(c, "POST", s.resourcesURL(c), "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertError(c, resp, http.StatusMethodNotAllowed, `unsupported method: "POST"`)
+}
+
+func (s *resourcesSuite) TestUploadAndDownload(c *gc.C) {
+	args := params.ResourceUploadArgs{ServiceName: "wordpress", Name: "jar", Size: 7}
+	resp := s.upload(c, s.userTag.String(), s.password, args, "content")
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeJSON)
+	var result params.ServiceResource
+	err := json.Unmarshal(body, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Name, gc.Equals, "jar")
+	c.Assert(result.Revision, gc.Equals, 1)
+	c.Assert(result.Size, gc.Equals, int64(7))
+
+	resp = s.download(c, s.userTag.String(), s.password, params.ResourceDownloadArgs{
+		ServiceName: "wordpress",
+		Name:        "jar",
+	})
+	body = assertResponse(c, resp, http.StatusOK, apihttp.CTypeRaw)
+	c.Assert(string(body), gc.Equals, "content")
+}
+
+func (s *resourcesSuite) TestDownloadNotFound(c *gc.C) {
+	resp := s.download(c, s.userTag.String(), s.password, params.ResourceDownloadArgs{
+		ServiceName: "wordpress",
+		Name:        "jar",
+	})
+	s.assertError(c, resp, http.StatusNotFound, `resource "jar" of service "wordpress" not found`)
+}
+
+func (s *resourcesSuite) TestUploadInvalidName(c *gc.C) {
+	args := params.ResourceUploadArgs{ServiceName: "wordpress", Name: "some.jar", Size: 7}
+	resp := s.upload(c, s.userTag.String(), s.password, args, "content")
+	s.assertError(c, resp, http.StatusBadRequest, `cannot set resource "some.jar" of service "wordpress": resource name "some.jar" not valid`)
+}
+
+func (s *resourcesSuite) TestUnitDownloadsOwnServiceResources(c *gc.C) {
+	_, err := s.service.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+	other := s.Factory.MakeService(c, &factory.ServiceParams{Name: "other"})
+	_, err = other.SetResource("jar", strings.NewReader("secret"), 6)
+	c.Assert(err, jc.ErrorIsNil)
+	unit, password := s.makeUnit(c)
+
+	resp := s.download(c, unit.Tag().String(), password, params.ResourceDownloadArgs{
+		ServiceName: "wordpress",
+		Name:        "jar",
+	})
+	body := assertResponse(c, resp, http.StatusOK, apihttp.CTypeRaw)
+	c.Assert(string(body), gc.Equals, "content")
+
+	resp = s.download(c, unit.Tag().String(), password, params.ResourceDownloadArgs{
+		ServiceName: "other",
+		Name:        "jar",
+	})
+	s.assertError(c, resp, http.StatusForbidden, "permission denied")
+}
+
+func (s *resourcesSuite) TestUnitCannotUpload(c *gc.C) {
+	unit, password := s.makeUnit(c)
+	args := params.ResourceUploadArgs{ServiceName: "wordpress", Name: "jar", Size: 7}
+	resp := s.upload(c, unit.Tag().String(), password, args, "content")
+	s.assertError(c, resp, http.StatusUnauthorized, "unauthorized")
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.Resources(), gc.HasLen, 0)
+}
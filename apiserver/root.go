@@ -68,9 +68,32 @@ func newApiHandler(srv *Server, st *state.State, rpcConn *rpc.Conn, reqNotifier
 	if err := r.resources.RegisterNamed("logDir", common.StringResource(srv.logDir)); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := r.resources.RegisterNamed("pusher", rpcPusher{rpcConn}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	if err := r.resources.RegisterNamed("introspector", serverIntrospector{srv}); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return r, nil
 }
 
+// rpcPusher implements common.Pusher by pushing messages
+// on the connection's rpc.Conn.
+type rpcPusher struct {
+	conn *rpc.Conn
+}
+
+// Push implements common.Pusher.
+func (p rpcPusher) Push(subscription string, body interface{}) error {
+	return p.conn.Push(subscription, body)
+}
+
+// Stop implements common.Resource. The connection itself
+// is closed when the client goes away.
+func (rpcPusher) Stop() error {
+	return nil
+}
+
 func (r *apiHandler) getResources() *common.Resources {
 	return r.resources
 }
@@ -118,11 +141,18 @@ func (s *srvCaller) ResultType() reflect.Type {
 // Call takes the object Id and an instance of ParamsType to create an object and place
 // a call on its method. It then returns an instance of ResultType.
 func (s *srvCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	return s.CallCancel(objId, arg, nil)
+}
+
+// CallCancel is like Call, but passes the given cancel channel to
+// facade methods that accept one.
+// See rpcreflect.CancelCaller for more detail.
+func (s *srvCaller) CallCancel(objId string, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error) {
 	objVal, err := s.creator(objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	return s.objMethod.Call(objVal, arg)
+	return s.objMethod.CallCancel(objVal, arg, cancel)
 }
 
 // apiRoot implements basic method dispatching to the facade registry.
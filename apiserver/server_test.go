@@ -30,6 +30,7 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/presence"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 )
 
 func TestAll(t *stdtesting.T) {
@@ -410,3 +411,92 @@ func assertStateIsOpen(c *gc.C, st *state.State) {
 func assertStateIsClosed(c *gc.C, st *state.State) {
 	c.Assert(func() { st.Ping() }, gc.PanicMatches, "Session already closed")
 }
+
+func (s *serverSuite) TestAgentRequestsRateLimited(c *gc.C) {
+	// Fail immediately if a request would have to wait.
+	s.PatchValue(apiserver.MaxRequestWait, time.Duration(0))
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(s.State, listener, apiserver.ServerConfig{
+		Cert:              []byte(coretesting.ServerCert),
+		Key:               []byte(coretesting.ServerKey),
+		Tag:               names.NewMachineTag("0"),
+		AgentRequestRate:  0.001,
+		AgentRequestBurst: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+
+	stm, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	apiInfo := &api.Info{
+		Tag:      stm.Tag(),
+		Password: password,
+		Nonce:    "fake_nonce",
+		Addrs:    []string{srv.Addr()},
+		CACert:   coretesting.CACert,
+	}
+	st, err := api.Open(apiInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// The first request takes the only token.
+	_, err = st.Machiner().Machine(stm.Tag().(names.MachineTag))
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = st.Machiner().Machine(stm.Tag().(names.MachineTag))
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+
+	// Pings are never limited.
+	err = st.Ping()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Users are never limited.
+	userInfo := *apiInfo
+	userInfo.Tag = s.AdminUserTag(c)
+	userInfo.Password = "dummy-secret"
+	userInfo.Nonce = ""
+	userSt, err := api.Open(&userInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer userSt.Close()
+	for i := 0; i < 3; i++ {
+		_, err = userSt.Client().Status(nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *serverSuite) TestAgentRequestsDelayed(c *gc.C) {
+	listener, err := net.Listen("tcp", ":0")
+	c.Assert(err, jc.ErrorIsNil)
+	srv, err := apiserver.NewServer(s.State, listener, apiserver.ServerConfig{
+		Cert:             []byte(coretesting.ServerCert),
+		Key:              []byte(coretesting.ServerKey),
+		Tag:              names.NewMachineTag("0"),
+		AgentRequestRate: 10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer srv.Stop()
+
+	stm, password := s.Factory.MakeMachineReturningPassword(c, &factory.MachineParams{
+		Nonce: "fake_nonce",
+	})
+	apiInfo := &api.Info{
+		Tag:      stm.Tag(),
+		Password: password,
+		Nonce:    "fake_nonce",
+		Addrs:    []string{srv.Addr()},
+		CACert:   coretesting.CACert,
+	}
+	st, err := api.Open(apiInfo, fastDialOpts)
+	c.Assert(err, jc.ErrorIsNil)
+	defer st.Close()
+
+	// After the burst of 10 requests is used up, the
+	// rest are delayed rather than refused.
+	start := time.Now()
+	for i := 0; i < 15; i++ {
+		_, err = st.Machiner().Machine(stm.Tag().(names.MachineTag))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(time.Since(start), jc.GreaterThan, 400*time.Millisecond)
+}
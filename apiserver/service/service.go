@@ -6,6 +6,7 @@ package service
 
 import (
 	"github.com/juju/loggo"
+	"github.com/juju/names"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -21,6 +22,7 @@ func init() {
 // Service defines the methods on the service API end point.
 type Service interface {
 	SetMetricCredentials(args params.ServiceMetricCredentials) (params.ErrorResults, error)
+	ServiceResources(args params.Entities) (params.ServiceResourcesResults, error)
 }
 
 // API implements the service interface and is the concrete
@@ -67,3 +69,25 @@ func (api *API) SetMetricCredentials(args params.ServiceMetricCredentials) (para
 	}
 	return result, nil
 }
+
+// ServiceResources returns the current revision of every resource
+// attached to each of the given services.
+func (api *API) ServiceResources(args params.Entities) (params.ServiceResourcesResults, error) {
+	result := params.ServiceResourcesResults{
+		Results: make([]params.ServiceResourcesResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := api.state.Service(tag.Id())
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Resources = common.ServiceResourcesToParams(service.Resources())
+	}
+	return result, nil
+}
@@ -4,6 +4,8 @@
 package service_test
 
 import (
+	"strings"
+
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -109,3 +111,26 @@ func (s *serviceSuite) TestSetMetricCredentials(c *gc.C) {
 		}
 	}
 }
+
+func (s *serviceSuite) TestServiceResources(c *gc.C) {
+	_, err := s.service.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.serviceApi.ServiceResources(params.Entities{
+		Entities: []params.Entity{
+			{Tag: s.service.Tag().String()},
+			{Tag: "service-missing"},
+			{Tag: "unit-foo-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	resources := results.Results[0].Resources
+	c.Assert(resources, gc.HasLen, 1)
+	c.Assert(resources[0].Name, gc.Equals, "jar")
+	c.Assert(resources[0].Revision, gc.Equals, 1)
+	c.Assert(resources[0].Size, gc.Equals, int64(7))
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, `service "missing" not found`)
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "permission denied")
+}
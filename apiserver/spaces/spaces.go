@@ -0,0 +1,92 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package spaces implements the API facade used to create and list
+// spaces: named groups of subnets to which service endpoints can be
+// bound.
+package spaces
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Spaces", 1, NewAPI)
+}
+
+// Spaces defines the methods on the spaces API end point.
+type Spaces interface {
+	CreateSpaces(args params.CreateSpacesParams) (params.ErrorResults, error)
+	ListSpaces() (params.ListSpacesResults, error)
+}
+
+// API implements the Spaces interface and is the concrete
+// implementation of the api end point.
+type API struct {
+	state      *state.State
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+var _ Spaces = (*API)(nil)
+
+// NewAPI returns a new spaces API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+
+	return &API{
+		state:      st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// CreateSpaces creates each of the given spaces, moving the listed
+// subnets into it.
+func (api *API) CreateSpaces(args params.CreateSpacesParams) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Spaces)),
+	}
+	for i, space := range args.Spaces {
+		_, err := api.state.AddSpace(space.Name, space.SubnetCIDRs)
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListSpaces returns all the spaces known to the environment, along
+// with the subnets in each.
+func (api *API) ListSpaces() (params.ListSpacesResults, error) {
+	spaces, err := api.state.AllSpaces()
+	if err != nil {
+		return params.ListSpacesResults{}, errors.Trace(err)
+	}
+	results := params.ListSpacesResults{
+		Results: make([]params.Space, len(spaces)),
+	}
+	for i, space := range spaces {
+		subnets, err := space.Subnets()
+		if err != nil {
+			return params.ListSpacesResults{}, errors.Trace(err)
+		}
+		result := params.Space{Name: space.Name()}
+		for _, subnet := range subnets {
+			result.SubnetCIDRs = append(result.SubnetCIDRs, subnet.CIDR())
+		}
+		results.Results[i] = result
+	}
+	return results, nil
+}
@@ -0,0 +1,84 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package spaces_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/spaces"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+)
+
+type spacesSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *spaces.API
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&spacesSuite{})
+
+func (s *spacesSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = spaces.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *spacesSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := spaces.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *spacesSuite) TestCreateSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.CreateSpaces(params.CreateSpacesParams{
+		Spaces: []params.Space{
+			{Name: "internal", SubnetCIDRs: []string{"10.0.0.0/24"}},
+			{Name: "public"},
+			{Name: "internal"},
+			{Name: "dmz", SubnetCIDRs: []string{"10.0.9.0/24"}},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `cannot add space "internal": space "internal" already exists`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `cannot add space "dmz": subnet "10.0.9.0/24" not found`)
+
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "internal")
+}
+
+func (s *spacesSuite) TestListSpaces(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{CIDR: "10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("public", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.SameContents, []params.Space{
+		{Name: "internal", SubnetCIDRs: []string{"10.0.0.0/24"}},
+		{Name: "public"},
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package subnets implements the API facade used to add and list the
+// subnets known to juju.
+package subnets
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("Subnets", 1, NewAPI)
+}
+
+// Subnets defines the methods on the subnets API end point.
+type Subnets interface {
+	AddSubnets(args params.AddSubnetsParams) (params.ErrorResults, error)
+	ListSubnets() (params.ListSubnetsResults, error)
+}
+
+// API implements the Subnets interface and is the concrete
+// implementation of the api end point.
+type API struct {
+	state      *state.State
+	authorizer common.Authorizer
+	check      *common.BlockChecker
+}
+
+var _ Subnets = (*API)(nil)
+
+// NewAPI returns a new subnets API facade.
+func NewAPI(
+	st *state.State,
+	resources *common.Resources,
+	authorizer common.Authorizer,
+) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+
+	return &API{
+		state:      st,
+		authorizer: authorizer,
+		check:      common.NewBlockChecker(st),
+	}, nil
+}
+
+// AddSubnets adds each of the given subnets, optionally placing them
+// in an existing space.
+func (api *API) AddSubnets(args params.AddSubnetsParams) (params.ErrorResults, error) {
+	if err := api.check.ChangeAllowed(); err != nil {
+		return params.ErrorResults{}, errors.Trace(err)
+	}
+	results := params.ErrorResults{
+		Results: make([]params.ErrorResult, len(args.Subnets)),
+	}
+	for i, subnet := range args.Subnets {
+		_, err := api.state.AddSubnet(state.SubnetInfo{
+			CIDR:             subnet.CIDR,
+			ProviderId:       subnet.ProviderId,
+			VLANTag:          subnet.VLANTag,
+			AvailabilityZone: subnet.AvailabilityZone,
+			SpaceName:        subnet.SpaceName,
+		})
+		results.Results[i].Error = common.ServerError(err)
+	}
+	return results, nil
+}
+
+// ListSubnets returns all the subnets known to the environment.
+func (api *API) ListSubnets() (params.ListSubnetsResults, error) {
+	subnets, err := api.state.AllSubnets()
+	if err != nil {
+		return params.ListSubnetsResults{}, errors.Trace(err)
+	}
+	results := params.ListSubnetsResults{
+		Results: make([]params.Subnet, len(subnets)),
+	}
+	for i, subnet := range subnets {
+		results.Results[i] = params.Subnet{
+			CIDR:             subnet.CIDR(),
+			ProviderId:       subnet.ProviderId(),
+			VLANTag:          subnet.VLANTag(),
+			AvailabilityZone: subnet.AvailabilityZone(),
+			SpaceName:        subnet.SpaceName(),
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnets_test
+
+import (
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/subnets"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	jujutesting "github.com/juju/juju/juju/testing"
+)
+
+type subnetsSuite struct {
+	jujutesting.JujuConnSuite
+
+	api        *subnets.API
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&subnetsSuite{})
+
+func (s *subnetsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: s.AdminUserTag(c),
+	}
+	var err error
+	s.api, err = subnets.NewAPI(s.State, nil, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *subnetsSuite) TestNewAPIRefusesNonClient(c *gc.C) {
+	authorizer := apiservertesting.FakeAuthorizer{
+		Tag: names.NewMachineTag("0"),
+	}
+	_, err := subnets.NewAPI(s.State, nil, authorizer)
+	c.Assert(err, gc.Equals, common.ErrPerm)
+}
+
+func (s *subnetsSuite) TestAddSubnets(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.AddSubnets(params.AddSubnetsParams{
+		Subnets: []params.Subnet{
+			{CIDR: "10.0.0.0/24", SpaceName: "internal", ProviderId: "sn-1"},
+			{CIDR: "10.0.1.0/24", AvailabilityZone: "zone1"},
+			{CIDR: "10.0.2.0/24", SpaceName: "public"},
+			{CIDR: "bogus"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 4)
+	c.Check(results.Results[0].Error, gc.IsNil)
+	c.Check(results.Results[1].Error, gc.IsNil)
+	c.Check(results.Results[2].Error, gc.ErrorMatches, `cannot add subnet "10.0.2.0/24": space "public" not found`)
+	c.Check(results.Results[3].Error, gc.ErrorMatches, `cannot add subnet "bogus": invalid CIDR address: bogus`)
+
+	subnet, err := s.State.Subnet("10.0.0.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(subnet.SpaceName(), gc.Equals, "internal")
+	c.Check(subnet.ProviderId(), gc.Equals, "sn-1")
+}
+
+func (s *subnetsSuite) TestListSubnets(c *gc.C) {
+	_, err := s.api.AddSubnets(params.AddSubnetsParams{
+		Subnets: []params.Subnet{
+			{CIDR: "10.0.0.0/24", VLANTag: 42},
+			{CIDR: "10.0.1.0/24", AvailabilityZone: "zone1"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.api.ListSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, jc.SameContents, []params.Subnet{
+		{CIDR: "10.0.0.0/24", VLANTag: 42},
+		{CIDR: "10.0.1.0/24", AvailabilityZone: "zone1"},
+	})
+}
@@ -425,10 +425,18 @@ func (u *uniterBaseAPI) CharmURL(args params.Entities) (params.StringBoolResults
 			var unitOrService state.Entity
 			unitOrService, err = u.st.FindEntity(tag)
 			if err == nil {
-				charmURLer := unitOrService.(interface {
-					CharmURL() (*charm.URL, bool)
-				})
-				curl, ok := charmURLer.CharmURL()
+				var curl *charm.URL
+				var ok bool
+				if service, isService := unitOrService.(*state.Service); isService {
+					// Units held back by a rolling charm upgrade
+					// are told to keep their current charm.
+					curl, ok = service.CharmURLForUnit(u.unit.Name())
+				} else {
+					charmURLer := unitOrService.(interface {
+						CharmURL() (*charm.URL, bool)
+					})
+					curl, ok = charmURLer.CharmURL()
+				}
 				if curl != nil {
 					result.Results[i].Result = curl.String()
 					result.Results[i].Ok = ok
@@ -13,6 +13,7 @@ import (
 	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing/factory"
 )
 
 type uniterV1Suite struct {
@@ -125,6 +126,43 @@ func (s *uniterV1Suite) TestCharmURL(c *gc.C) {
 	s.testCharmURL(c, s.uniter)
 }
 
+func (s *uniterV1Suite) TestCharmURLDuringRollingUpgrade(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	heldUnit := s.Factory.MakeUnit(c, &factory.UnitParams{
+		Service:     s.wordpress,
+		Machine:     s.machine0,
+		SetCharmURL: true,
+	})
+	newCharm := s.Factory.MakeCharm(c, &factory.CharmParams{
+		Name: "wordpress",
+		URL:  "cs:quantal/wordpress-4",
+	})
+	err = s.wordpress.SetCharmInBatches(newCharm, false, s.AdminUserTag(c), state.CharmUpgradeArgs{BatchSize: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The first unit is upgraded at once.
+	args := params.Entities{Entities: []params.Entity{{Tag: "service-wordpress"}}}
+	result, err := s.uniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: newCharm.String()}},
+	})
+
+	// The other one keeps its charm until its batch is released.
+	heldUniter, err := uniter.NewUniterAPIV1(
+		s.State,
+		s.resources,
+		apiservertesting.FakeAuthorizer{Tag: heldUnit.Tag()},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = heldUniter.CharmURL(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringBoolResults{
+		Results: []params.StringBoolResult{{Result: s.wpCharm.String()}},
+	})
+}
+
 func (s *uniterV1Suite) TestSetCharmURL(c *gc.C) {
 	s.testSetCharmURL(c, s.uniter)
 }
@@ -7,7 +7,10 @@
 package uniter
 
 import (
+	"github.com/juju/names"
+
 	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state"
 )
 
@@ -36,3 +39,61 @@ func NewUniterAPIV2(st *state.State, resources *common.Resources, authorizer com
 		StorageAPI:  *storageAPI,
 	}, nil
 }
+
+// ServiceResources returns the current revision of every resource
+// attached to each of the given services.
+func (u *UniterAPIV2) ServiceResources(args params.Entities) (params.ServiceResourcesResults, error) {
+	result := params.ServiceResourcesResults{
+		Results: make([]params.ServiceResourcesResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessService()
+	if err != nil {
+		return params.ServiceResourcesResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseServiceTag(entity.Tag)
+		if err != nil || !canAccess(tag) {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		service, err := u.getService(tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		result.Results[i].Resources = common.ServiceResourcesToParams(service.Resources())
+	}
+	return result, nil
+}
+
+// RelationPrivateAddress returns the private address each given unit
+// advertises on each given relation. If the unit's service binds the
+// relation's endpoint to a space, this is the unit's address in that
+// space; otherwise it is the unit's private address.
+func (u *UniterAPIV2) RelationPrivateAddress(args params.RelationUnits) (params.StringResults, error) {
+	result := params.StringResults{
+		Results: make([]params.StringResult, len(args.RelationUnits)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringResults{}, err
+	}
+	for i, arg := range args.RelationUnits {
+		unit, err := names.ParseUnitTag(arg.Unit)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		relUnit, err := u.getRelationUnit(canAccess, arg.Relation, unit)
+		if err == nil {
+			address, ok := relUnit.PrivateAddress()
+			if ok {
+				result.Results[i].Result = address
+			} else {
+				err = common.NoAddressSetError(unit, "private")
+			}
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
@@ -4,12 +4,16 @@
 package uniter_test
 
 import (
+	"strings"
+
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
 	"github.com/juju/juju/apiserver/uniter"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
 )
 
@@ -102,3 +106,67 @@ func (s *uniterV2Suite) TestSetAgentStatus(c *gc.C) {
 func (s *uniterV2Suite) TestSetUnitStatus(c *gc.C) {
 	s.testSetUnitStatus(c, s.uniter)
 }
+
+func (s *uniterV2Suite) TestServiceResources(c *gc.C) {
+	_, err := s.wordpress.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.uniter.ServiceResources(params.Entities{
+		Entities: []params.Entity{
+			{Tag: "service-wordpress"},
+			{Tag: "service-mysql"},
+			{Tag: "unit-wordpress-0"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 3)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	resources := results.Results[0].Resources
+	c.Assert(resources, gc.HasLen, 1)
+	c.Assert(resources[0].Name, gc.Equals, "jar")
+	c.Assert(resources[0].Revision, gc.Equals, 1)
+	c.Assert(results.Results[1].Error, gc.ErrorMatches, "permission denied")
+	c.Assert(results.Results[2].Error, gc.ErrorMatches, "permission denied")
+}
+
+func (s *uniterV2Suite) TestRelationPrivateAddress(c *gc.C) {
+	rel := s.addRelation(c, "wordpress", "mysql")
+	err := s.machine0.SetAddresses(
+		network.NewAddress("10.0.0.10", network.ScopeCloudLocal),
+		network.NewAddress("192.168.1.10", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := params.RelationUnits{RelationUnits: []params.RelationUnit{
+		{Relation: rel.Tag().String(), Unit: "unit-wordpress-0"},
+		{Relation: rel.Tag().String(), Unit: "unit-mysql-0"},
+		{Relation: "relation-42", Unit: "unit-wordpress-0"},
+		{Relation: rel.Tag().String(), Unit: "service-wordpress"},
+	}}
+	expectResults := func(address string) params.StringResults {
+		return params.StringResults{
+			Results: []params.StringResult{
+				{Result: address},
+				{Error: apiservertesting.ErrUnauthorized},
+				{Error: apiservertesting.ErrUnauthorized},
+				{Error: apiservertesting.ErrUnauthorized},
+			},
+		}
+	}
+
+	// Without a binding, the unit's private address is used.
+	result, err := s.uniter.RelationPrivateAddress(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, expectResults("10.0.0.10"))
+
+	// With one, its address in the bound space is used.
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.wordpress.SetEndpointBindings(map[string]string{"db": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	result, err = s.uniter.RelationPrivateAddress(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, expectResults("192.168.1.10"))
+}
@@ -5,6 +5,9 @@ package apiserver
 
 import (
 	"reflect"
+	"sync"
+
+	"github.com/juju/errors"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -20,10 +23,18 @@ func init() {
 		"NotifyWatcher", 0, newNotifyWatcher,
 		reflect.TypeOf((*srvNotifyWatcher)(nil)),
 	)
+	common.RegisterFacade(
+		"NotifyWatcher", 1, newNotifyWatcherV1,
+		reflect.TypeOf((*srvNotifyWatcherV1)(nil)),
+	)
 	common.RegisterFacade(
 		"StringsWatcher", 0, newStringsWatcher,
 		reflect.TypeOf((*srvStringsWatcher)(nil)),
 	)
+	common.RegisterFacade(
+		"StringsWatcher", 1, newStringsWatcherV1,
+		reflect.TypeOf((*srvStringsWatcherV1)(nil)),
+	)
 	common.RegisterFacade(
 		"RelationUnitsWatcher", 0, newRelationUnitsWatcher,
 		reflect.TypeOf((*srvRelationUnitsWatcher)(nil)),
@@ -111,6 +122,94 @@ func (w *srvNotifyWatcher) Stop() error {
 	return w.resources.Stop(w.id)
 }
 
+// watcherStreamer holds the logic shared by watcher facades that can
+// push their changes to the client instead of waiting for Next calls.
+type watcherStreamer struct {
+	mu        sync.Mutex
+	streaming bool
+	pusher    common.Pusher
+}
+
+func newWatcherStreamer(resources *common.Resources) (*watcherStreamer, error) {
+	pusher, ok := resources.Get("pusher").(common.Pusher)
+	if !ok {
+		return nil, errors.New("connection does not support streaming")
+	}
+	return &watcherStreamer{pusher: pusher}, nil
+}
+
+// start runs the given function in its own goroutine, unless
+// the watcher is already streaming.
+func (s *watcherStreamer) start(stream func()) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.streaming {
+		return errors.New("watcher is already streaming")
+	}
+	s.streaming = true
+	go stream()
+	return nil
+}
+
+// srvNotifyWatcherV1 extends srvNotifyWatcher so that changes
+// can be streamed to the client.
+type srvNotifyWatcherV1 struct {
+	*srvNotifyWatcher
+	streamer *watcherStreamer
+}
+
+func newNotifyWatcherV1(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	w, err := newNotifyWatcher(st, resources, auth, id)
+	if err != nil {
+		return nil, err
+	}
+	streamer, err := newWatcherStreamer(resources)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &srvNotifyWatcherV1{
+		srvNotifyWatcher: w.(*srvNotifyWatcher),
+		streamer:         streamer,
+	}, nil
+}
+
+// Stream starts pushing a params.NotifyWatchResult to the client,
+// on a subscription named by the watcher id, whenever the entity
+// being watched changes. When the watcher stops, a final result
+// holding the error is pushed. Stream returns immediately; Next
+// must not be called on a streaming watcher.
+func (w *srvNotifyWatcherV1) Stream() error {
+	return w.streamer.start(w.stream)
+}
+
+func (w *srvNotifyWatcherV1) stream() {
+	for {
+		_, ok := <-w.watcher.Changes()
+		result := params.NotifyWatchResult{
+			NotifyWatcherId: w.id,
+		}
+		if !ok {
+			result.Error = common.ServerError(watcherError(w.watcher))
+		}
+		if err := w.streamer.pusher.Push(w.id, result); err != nil {
+			logger.Debugf("cannot push change for watcher %q: %v", w.id, err)
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
+// watcherError returns the error that caused the
+// given watcher to stop.
+func watcherError(w state.Watcher) error {
+	if err := w.Err(); err != nil {
+		return err
+	}
+	return common.ErrStoppedWatcher
+}
+
 // srvStringsWatcher defines the API for methods on a state.StringsWatcher.
 // Each client has its own current set of watchers, stored in resources.
 // srvStringsWatcher notifies about changes for all entities of a given kind,
@@ -157,6 +256,58 @@ func (w *srvStringsWatcher) Stop() error {
 	return w.resources.Stop(w.id)
 }
 
+// srvStringsWatcherV1 extends srvStringsWatcher so that changes
+// can be streamed to the client.
+type srvStringsWatcherV1 struct {
+	*srvStringsWatcher
+	streamer *watcherStreamer
+}
+
+func newStringsWatcherV1(st *state.State, resources *common.Resources, auth common.Authorizer, id string) (interface{}, error) {
+	w, err := newStringsWatcher(st, resources, auth, id)
+	if err != nil {
+		return nil, err
+	}
+	streamer, err := newWatcherStreamer(resources)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &srvStringsWatcherV1{
+		srvStringsWatcher: w.(*srvStringsWatcher),
+		streamer:          streamer,
+	}, nil
+}
+
+// Stream starts pushing a params.StringsWatchResult holding the
+// changes to the client, on a subscription named by the watcher id,
+// whenever entities of the collection being watched change. When
+// the watcher stops, a final result holding the error is pushed.
+// Stream returns immediately; Next must not be called on a
+// streaming watcher.
+func (w *srvStringsWatcherV1) Stream() error {
+	return w.streamer.start(w.stream)
+}
+
+func (w *srvStringsWatcherV1) stream() {
+	for {
+		changes, ok := <-w.watcher.Changes()
+		result := params.StringsWatchResult{
+			StringsWatcherId: w.id,
+			Changes:          changes,
+		}
+		if !ok {
+			result.Error = common.ServerError(watcherError(w.watcher))
+		}
+		if err := w.streamer.pusher.Push(w.id, result); err != nil {
+			logger.Debugf("cannot push change for watcher %q: %v", w.id, err)
+			return
+		}
+		if !ok {
+			return
+		}
+	}
+}
+
 // srvRelationUnitsWatcher defines the API wrapping a state.RelationUnitsWatcher.
 // It notifies about units entering and leaving the scope of a RelationUnit,
 // and changes to the settings of those units known to have entered.
@@ -5,6 +5,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/juju/cmd"
 
@@ -16,13 +17,29 @@ import (
 type AddRelationCommand struct {
 	envcmd.EnvCommandBase
 	Endpoints []string
+
+	// OfferingEnvironment holds the name of the environment offering
+	// the service of the second endpoint, if it is not this one.
+	OfferingEnvironment string
 }
 
+var jujuAddRelationHelp = `
+Adds a relation between two services. Either service may instead be one
+offered by another environment hosted by the same state server, of which
+you are a user, named by prefixing it with the name of that environment
+and a slash:
+
+    juju add-relation wordpress db-env/mysql
+
+See "juju help offer" for details.
+`
+
 func (c *AddRelationCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "add-relation",
-		Args:    "<service1>[:<relation name1>] <service2>[:<relation name2>]",
+		Args:    "<service1>[:<relation name1>] [<environment>/]<service2>[:<relation name2>]",
 		Purpose: "add a relation between two services",
+		Doc:     jujuAddRelationHelp,
 	}
 }
 
@@ -30,6 +47,18 @@ func (c *AddRelationCommand) Init(args []string) error {
 	if len(args) != 2 {
 		return fmt.Errorf("a relation must involve two services")
 	}
+	// Neither environment nor service names may contain a slash, so
+	// one marks an endpoint offered by another environment.
+	if strings.Contains(args[0], "/") {
+		args = []string{args[1], args[0]}
+	}
+	if strings.Contains(args[0], "/") {
+		return fmt.Errorf("only one service may be of another environment")
+	}
+	if i := strings.Index(args[1], "/"); i != -1 {
+		c.OfferingEnvironment = args[1][:i]
+		args = []string{args[0], args[1][i+1:]}
+	}
 	c.Endpoints = args
 	return nil
 }
@@ -40,6 +69,10 @@ func (c *AddRelationCommand) Run(_ *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
-	_, err = client.AddRelation(c.Endpoints...)
+	if c.OfferingEnvironment != "" {
+		_, err = client.AddCrossEnvironmentRelation(c.Endpoints[0], c.OfferingEnvironment, c.Endpoints[1])
+	} else {
+		_, err = client.AddRelation(c.Endpoints...)
+	}
 	return block.ProcessBlockedError(err, block.BlockChange)
 }
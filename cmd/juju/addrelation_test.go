@@ -188,3 +188,37 @@ func (s *AddRelationSuite) TestBlockAddRelation(c *gc.C) {
 		}
 	}
 }
+
+func (s *AddRelationSuite) TestInitOfferedEndpoint(c *gc.C) {
+	for i, t := range []struct {
+		args        []string
+		endpoints   []string
+		environment string
+		err         string
+	}{{
+		args:      []string{"wp", "ms:server"},
+		endpoints: []string{"wp", "ms:server"},
+	}, {
+		args:        []string{"wp", "db-env/ms:server"},
+		endpoints:   []string{"wp", "ms:server"},
+		environment: "db-env",
+	}, {
+		args:        []string{"db-env/ms", "wp:db"},
+		endpoints:   []string{"wp:db", "ms"},
+		environment: "db-env",
+	}, {
+		args: []string{"app-env/wp", "db-env/ms"},
+		err:  "only one service may be of another environment",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		command := &AddRelationCommand{}
+		err := command.Init(t.args)
+		if t.err != "" {
+			c.Assert(err, gc.ErrorMatches, t.err)
+			continue
+		}
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(command.Endpoints, jc.DeepEquals, t.endpoints)
+		c.Assert(command.OfferingEnvironment, gc.Equals, t.environment)
+	}
+}
@@ -89,6 +89,7 @@ Examples:
  juju add-unit mysql --to 23       (Add a mysql unit to machine 23)
  juju add-unit mysql --to 24/lxc/3 (Add unit to lxc container 3 on host machine 24)
  juju add-unit mysql --to lxc:25   (Add unit to a new lxc container on host machine 25)
+ juju add-unit mysql --to lxd:26   (Add unit to a new lxd container on host machine 26)
 `
 
 func (c *AddUnitCommand) Info() *cmd.Info {
@@ -57,7 +57,14 @@ Juju tools to cloud storage if no outgoing Internet access is available. In this
 use the --metadata-source paramater to tell bootstrap a local directory from which to
 upload tools and/or image metadata.
 
+Credentials and regions can be kept out of environments.yaml: an
+environment can name a cloud defined in ~/.juju/clouds.yaml and a
+credential stored with "juju credentials add". The --credential and
+--region flags select the credential and region to bootstrap with, in
+place of any named by the environment.
+
 See Also:
+   juju help credentials
    juju help switch
    juju help constraints
    juju help set-constraints
@@ -75,6 +82,8 @@ type BootstrapCommand struct {
 	MetadataSource        string
 	Placement             string
 	KeepBrokenEnvironment bool
+	Credential            string
+	Region                string
 }
 
 func (c *BootstrapCommand) Info() *cmd.Info {
@@ -93,6 +102,8 @@ func (c *BootstrapCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.MetadataSource, "metadata-source", "", "local path to use as tools and/or metadata source")
 	f.StringVar(&c.Placement, "to", "", "a placement directive indicating an instance to bootstrap")
 	f.BoolVar(&c.KeepBrokenEnvironment, "keep-broken", false, "do not destroy the environment if bootstrap fails")
+	f.StringVar(&c.Credential, "credential", "", "the name of the credential to bootstrap with")
+	f.StringVar(&c.Region, "region", "", "the cloud region to bootstrap in")
 }
 
 func (c *BootstrapCommand) Init(args []string) (err error) {
@@ -183,6 +194,10 @@ func (c *BootstrapCommand) Run(ctx *cmd.Context) (resultErr error) {
 	environ, cleanup, err := environFromName(
 		ctx,
 		c.ConnectionName(),
+		environs.CloudSelection{
+			Credential: c.Credential,
+			Region:     c.Region,
+		},
 		"Bootstrap",
 		bootstrapFuncs.EnsureNotBootstrapped,
 	)
@@ -360,6 +360,7 @@ func (s *BootstrapSuite) TestBootstrapCleansUpIfEnvironPrepFails(c *gc.C) {
 		func(
 			*cmd.Context,
 			string,
+			environs.CloudSelection,
 			string,
 			func(environs.Environ) error,
 		) (environs.Environ, func(), error) {
@@ -373,6 +374,33 @@ func (s *BootstrapSuite) TestBootstrapCleansUpIfEnvironPrepFails(c *gc.C) {
 	c.Check(cleanupRan, jc.IsTrue)
 }
 
+func (s *BootstrapSuite) TestBootstrapPassesCloudSelection(c *gc.C) {
+	var selection environs.CloudSelection
+	s.PatchValue(
+		&environFromName,
+		func(
+			_ *cmd.Context,
+			_ string,
+			sel environs.CloudSelection,
+			_ string,
+			_ func(environs.Environ) error,
+		) (environs.Environ, func(), error) {
+			selection = sel
+			return nil, nil, fmt.Errorf("mock")
+		},
+	)
+
+	ctx := coretesting.Context(c)
+	_, errc := cmdtesting.RunCommand(ctx, envcmd.Wrap(new(BootstrapCommand)),
+		"-e", "peckham", "--credential", "work", "--region", "eu-west-1",
+	)
+	c.Check(<-errc, gc.ErrorMatches, "there was an issue examining the environment: mock")
+	c.Check(selection, jc.DeepEquals, environs.CloudSelection{
+		Credential: "work",
+		Region:     "eu-west-1",
+	})
+}
+
 // When attempting to bootstrap, check that when prepare errors out,
 // the code cleans up the created jenv file, but *not* any existing
 // environment that may have previously been bootstrapped.
@@ -403,6 +431,7 @@ func (s *BootstrapSuite) TestBootstrapFailToPrepareDiesGracefully(c *gc.C) {
 	mockEnvironFromName := func(
 		ctx *cmd.Context,
 		envName string,
+		selection environs.CloudSelection,
 		action string,
 		_ func(environs.Environ) error,
 	) (environs.Environ, func(), error) {
@@ -410,6 +439,7 @@ func (s *BootstrapSuite) TestBootstrapFailToPrepareDiesGracefully(c *gc.C) {
 		return environFromNameProductionFunc(
 			ctx,
 			envName,
+			selection,
 			action,
 			func(env environs.Environ) error {
 				return environs.ErrAlreadyBootstrapped
@@ -0,0 +1,29 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmcmd
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const charmCommandDoc = `
+"juju charm" is used to manage the charms held by the state server.
+`
+
+const charmCommandPurpose = "manage charms held by the state server"
+
+// NewSuperCommand creates the charm supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	charmcmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "charm",
+		Doc:         charmCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     charmCommandPurpose,
+	})
+	charmcmd.Register(envcmd.Wrap(&MirrorCommand{}))
+	return charmcmd
+}
@@ -0,0 +1,188 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmcmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const mirrorCommandDoc = `
+Add charms to the state server's charm mirror, so that environments
+without access to the public charm store can deploy them. Each charm is
+given a charm store URL made of its series, name and revision; the
+series is taken from --series or, if that is not set, from the
+directory holding the charm, as laid out in a local charm repository.
+Paths may name charm directories, charm archives, or directories of
+charms, including a whole local charm repository.
+
+The mirror is shared by all environments hosted by the state server,
+and charms can only be added to it through the state server
+environment. To deploy mirrored charms, set the charm-store-url of an
+environment to the URL printed by this command.
+
+Examples:
+
+  # Mirror the mysql and wordpress charms of a local repository.
+  juju charm mirror ~/charms/trusty/mysql ~/charms/trusty/wordpress
+
+  # Mirror every charm of a local repository.
+  juju charm mirror ~/charms
+
+  # Mirror a charm archive downloaded from the charm store.
+  juju charm mirror --series trusty ./mysql.zip
+`
+
+// MirrorCommand adds charms to the state server's charm mirror.
+type MirrorCommand struct {
+	envcmd.EnvCommandBase
+	Series string
+	Paths  []string
+}
+
+// Info implements Command.Info.
+func (c *MirrorCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "mirror",
+		Args:    "<charm path> ...",
+		Purpose: "add charms to the state server's charm mirror",
+		Doc:     mirrorCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *MirrorCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.StringVar(&c.Series, "series", "", "series of the mirrored charms")
+}
+
+// Init implements Command.Init.
+func (c *MirrorCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no charms specified")
+	}
+	if c.Series != "" && !charm.IsValidSeries(c.Series) {
+		return errors.Errorf("invalid series %q", c.Series)
+	}
+	c.Paths = args
+	return nil
+}
+
+// MirrorAPI defines the API methods that the mirror command uses.
+type MirrorAPI interface {
+	MirrorCharm(curl *charm.URL, ch charm.Charm) error
+	CharmMirrorURL() (string, error)
+	Close() error
+}
+
+var getMirrorAPI = func(c *MirrorCommand) (MirrorAPI, error) {
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *MirrorCommand) Run(ctx *cmd.Context) error {
+	client, err := getMirrorAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	var charmPaths []string
+	for _, path := range c.Paths {
+		paths, err := findCharms(ctx.AbsPath(path), 2)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(paths) == 0 {
+			return errors.Errorf("no charms found in %q", path)
+		}
+		charmPaths = append(charmPaths, paths...)
+	}
+	for _, path := range charmPaths {
+		curl, ch, err := c.readCharm(path)
+		if err != nil {
+			return errors.Annotatef(err, "cannot read charm %q", path)
+		}
+		if err := client.MirrorCharm(curl, ch); err != nil {
+			return errors.Annotatef(err, "cannot mirror charm %q", curl)
+		}
+		ctx.Infof("mirrored charm %q", curl)
+	}
+	mirrorURL, err := client.CharmMirrorURL()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stdout, "charm-store-url: %s\n", mirrorURL)
+	return nil
+}
+
+// readCharm reads the charm at path, and returns the charm store URL
+// under which it should be mirrored.
+func (c *MirrorCommand) readCharm(path string) (*charm.URL, charm.Charm, error) {
+	ch, err := charm.ReadCharm(path)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	series := c.Series
+	if series == "" {
+		series = filepath.Base(filepath.Dir(path))
+		if !charm.IsValidSeries(series) {
+			return nil, nil, errors.Errorf("cannot infer series from directory %q; use --series", filepath.Dir(path))
+		}
+	}
+	curl := &charm.URL{
+		Schema:   "cs",
+		Series:   series,
+		Name:     ch.Meta().Name,
+		Revision: ch.Revision(),
+	}
+	return curl, ch, nil
+}
+
+// findCharms returns the paths of the charms at path: path itself if
+// it is a charm archive or directory, or else the charms found by
+// descending at most depth levels of directories.
+func findCharms(path string, depth int) ([]string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if !info.IsDir() {
+		return []string{path}, nil
+	}
+	if _, err := os.Stat(filepath.Join(path, "metadata.yaml")); err == nil {
+		return []string{path}, nil
+	}
+	if depth == 0 {
+		return nil, nil
+	}
+	entries, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var paths []string
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+		if !entry.IsDir() && !strings.HasSuffix(entry.Name(), ".charm") && !strings.HasSuffix(entry.Name(), ".zip") {
+			continue
+		}
+		found, err := findCharms(filepath.Join(path, entry.Name()), depth-1)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		paths = append(paths, found...)
+	}
+	return paths, nil
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmcmd_test
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/charmcmd"
+	"github.com/juju/juju/testcharms"
+	"github.com/juju/juju/testing"
+)
+
+type mirrorCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakeMirrorAPI
+}
+
+var _ = gc.Suite(&mirrorCommandSuite{})
+
+type fakeMirrorAPI struct {
+	mirrored []string
+	err      error
+}
+
+func (*fakeMirrorAPI) Close() error {
+	return nil
+}
+
+func (f *fakeMirrorAPI) MirrorCharm(curl *charm.URL, ch charm.Charm) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.mirrored = append(f.mirrored, curl.String())
+	return nil
+}
+
+func (f *fakeMirrorAPI) CharmMirrorURL() (string, error) {
+	return "https://10.0.0.1:17070/environment/deadbeef-0bad-400d-8000-4b1d0d06f00d/charmstore", nil
+}
+
+func (s *mirrorCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakeMirrorAPI{}
+	s.PatchValue(charmcmd.GetMirrorAPI, func(c *charmcmd.MirrorCommand) (charmcmd.MirrorAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runMirrorCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&charmcmd.MirrorCommand{}), args...)
+}
+
+func (s *mirrorCommandSuite) TestMirrorRepositoryLayout(c *gc.C) {
+	seriesPath := filepath.Join(c.MkDir(), "trusty")
+	err := os.Mkdir(seriesPath, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	dir := testcharms.Repo.ClonedDirPath(seriesPath, "dummy")
+	archive := testcharms.Repo.CharmArchivePath(seriesPath, "wordpress")
+
+	ctx, err := runMirrorCommand(c, dir, archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.mirrored, jc.DeepEquals, []string{
+		"cs:trusty/dummy-1",
+		"cs:trusty/wordpress-3",
+	})
+	c.Check(testing.Stdout(ctx), gc.Equals,
+		"charm-store-url: https://10.0.0.1:17070/environment/deadbeef-0bad-400d-8000-4b1d0d06f00d/charmstore\n")
+	c.Check(testing.Stderr(ctx), gc.Equals,
+		"mirrored charm \"cs:trusty/dummy-1\"\nmirrored charm \"cs:trusty/wordpress-3\"\n")
+}
+
+func (s *mirrorCommandSuite) TestMirrorRepository(c *gc.C) {
+	repoPath := c.MkDir()
+	for _, series := range []string{"precise", "trusty"} {
+		seriesPath := filepath.Join(repoPath, series)
+		err := os.Mkdir(seriesPath, 0755)
+		c.Assert(err, jc.ErrorIsNil)
+		testcharms.Repo.ClonedDirPath(seriesPath, "dummy")
+	}
+
+	_, err := runMirrorCommand(c, repoPath)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.mirrored, jc.DeepEquals, []string{
+		"cs:precise/dummy-1",
+		"cs:trusty/dummy-1",
+	})
+}
+
+func (s *mirrorCommandSuite) TestMirrorEmptyDirectory(c *gc.C) {
+	_, err := runMirrorCommand(c, c.MkDir())
+	c.Assert(err, gc.ErrorMatches, `no charms found in ".*"`)
+}
+
+func (s *mirrorCommandSuite) TestMirrorWithSeries(c *gc.C) {
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := runMirrorCommand(c, "--series", "precise", archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mockAPI.mirrored, jc.DeepEquals, []string{"cs:precise/dummy-1"})
+}
+
+func (s *mirrorCommandSuite) TestMirrorCannotInferSeries(c *gc.C) {
+	charmsPath := filepath.Join(c.MkDir(), "charms")
+	err := os.Mkdir(charmsPath, 0755)
+	c.Assert(err, jc.ErrorIsNil)
+	dir := testcharms.Repo.ClonedDirPath(charmsPath, "dummy")
+	_, err = runMirrorCommand(c, dir)
+	c.Assert(err, gc.ErrorMatches, `cannot read charm ".*": cannot infer series from directory ".*charms"; use --series`)
+	c.Assert(s.mockAPI.mirrored, gc.HasLen, 0)
+}
+
+func (s *mirrorCommandSuite) TestMirrorError(c *gc.C) {
+	s.mockAPI.err = errors.New("charms can only be mirrored through the state server environment")
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	_, err := runMirrorCommand(c, "--series", "trusty", archive.Path)
+	c.Assert(err, gc.ErrorMatches, `cannot mirror charm "cs:trusty/dummy-1": charms can only be mirrored through the state server environment`)
+}
+
+func (*mirrorCommandSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no charms specified",
+	}, {
+		args: []string{"--series", "bad-series", "dummy"},
+		err:  `invalid series "bad-series"`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runMirrorCommand(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -51,12 +51,14 @@ func destroyEnvInfoProductionFunc(
 // one. If there are no errors, it returns the environ and a closure to
 // clean up in case we need to further up the stack. If an error has
 // occurred, the environment and cleanup function will be nil, and the
-// error will be filled in.
+// error will be filled in. A new environment is prepared with the
+// credential and region in selection, when set.
 var environFromName = environFromNameProductionFunc
 
 func environFromNameProductionFunc(
 	ctx *cmd.Context,
 	envName string,
+	selection environs.CloudSelection,
 	action string,
 	ensureNotBootstrapped func(environs.Environ) error,
 ) (env environs.Environ, cleanup func(), err error) {
@@ -92,7 +94,12 @@ func environFromNameProductionFunc(
 		}
 	}
 
-	if env, err = environs.PrepareFromName(envName, envcmd.BootstrapContext(ctx), store); err != nil {
+	if selection != (environs.CloudSelection{}) {
+		env, err = environs.PrepareFromNameWithSelection(envName, selection, envcmd.BootstrapContext(ctx), store)
+	} else {
+		env, err = environs.PrepareFromName(envName, envcmd.BootstrapContext(ctx), store)
+	}
+	if err != nil {
 		return nil, cleanup, err
 	}
 
@@ -125,3 +132,39 @@ func resolveCharmURL(url string, client *api.Client, conf *config.Config) (*char
 	logger.Errorf("The series is not specified in the environment (default-series) or with the charm. Did you mean:\n\t%s", &possibleURL)
 	return nil, fmt.Errorf("cannot resolve series for charm: %q", ref)
 }
+
+// specializeCharmStore returns the repository through which the client
+// should resolve charm store charms. When the environment's
+// charm-store-url is set, the configured store may not be reachable
+// from the client, so the latest revision of each charm is resolved by
+// the API server instead.
+func specializeCharmStore(repo charm.Repository, client *api.Client, conf *config.Config) charm.Repository {
+	if _, ok := conf.CharmStoreURL(); !ok {
+		return repo
+	}
+	if _, ok := repo.(*charm.CharmStore); !ok {
+		return repo
+	}
+	return &apiResolvingRepository{repo, client}
+}
+
+// apiResolvingRepository is a charm.Repository that asks the API server
+// for the latest revision of charms.
+type apiResolvingRepository struct {
+	charm.Repository
+	client *api.Client
+}
+
+// Latest implements charm.Repository.Latest.
+func (r *apiResolvingRepository) Latest(curls ...*charm.URL) ([]charm.CharmRevision, error) {
+	result := make([]charm.CharmRevision, len(curls))
+	for i, curl := range curls {
+		resolved, err := r.client.ResolveCharm(curl.WithRevision(-1).Reference())
+		if err != nil {
+			result[i].Err = err
+			continue
+		}
+		result[i].Revision = resolved.Revision
+	}
+	return result, nil
+}
@@ -0,0 +1,96 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/utils/keyvalues"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/cloud"
+)
+
+const addCommandDoc = `
+Adds a credential for clouds of the given provider type, with the given
+environment attributes. An existing credential can only be updated, for
+example to rotate its keys, when --replace is given.
+
+Environments prepared with a credential use its current attributes on
+the client. A replaced credential is not sent to the state servers of
+bootstrapped environments; update those with "juju set-environment".
+
+Example:
+    juju credentials add work-aws ec2 access-key=AKIA... secret-key=...
+`
+
+// AddCommand adds a credential to the client's credentials file.
+type AddCommand struct {
+	cmd.CommandBase
+	Name         string
+	ProviderType string
+	Attributes   map[string]string
+	Replace      bool
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<name> <provider type> <key>=<value> ...",
+		Purpose: "add a cloud credential",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.BoolVar(&c.Replace, "replace", false, "replace an existing credential with the same name")
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no credential name specified")
+	case 1:
+		return errors.New("no provider type specified")
+	case 2:
+		return errors.New("no credential attributes specified")
+	}
+	c.Name, c.ProviderType = args[0], args[1]
+	attrs, err := keyvalues.Parse(args[2:], false)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	c.Attributes = attrs
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) error {
+	if _, err := environs.Provider(c.ProviderType); err != nil {
+		return errors.Trace(err)
+	}
+	path := cloud.JujuCredentialsPath()
+	creds, err := cloud.ReadCredentials(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := creds[c.Name]; ok && !c.Replace {
+		return errors.Errorf("credential %q already exists (use --replace to update it)", c.Name)
+	}
+	creds[c.Name] = cloud.Credential{
+		Type:       c.ProviderType,
+		Attributes: c.Attributes,
+	}
+	if err := cloud.WriteCredentials(path, creds); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "credential %q saved\n", c.Name)
+	return nil
+}
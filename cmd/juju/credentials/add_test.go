@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/credentials"
+	"github.com/juju/juju/environs/cloud"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AddSuite struct {
+	BaseCredentialsSuite
+}
+
+var _ = gc.Suite(&AddSuite{})
+
+func runAdd(c *gc.C, args ...string) (*cmd.Context, error) {
+	return coretesting.RunCommand(c, &credentials.AddCommand{}, args...)
+}
+
+func (s *AddSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{
+		{nil, "no credential name specified"},
+		{[]string{"new"}, "no provider type specified"},
+		{[]string{"new", "dummy"}, "no credential attributes specified"},
+		{[]string{"new", "dummy", "secret"}, `expected "key=value", got "secret"`},
+		{[]string{"new", "dummy", "secret="}, `expected "key=value", got "secret="`},
+	} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runAdd(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AddSuite) TestAdd(c *gc.C) {
+	ctx, err := runAdd(c, "new", "dummy", "secret=chicken")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "credential \"new\" saved\n")
+
+	creds := s.readCredentials(c)
+	c.Assert(creds, gc.HasLen, 3)
+	c.Assert(creds["new"], jc.DeepEquals, cloud.Credential{
+		Type:       "dummy",
+		Attributes: map[string]string{"secret": "chicken"},
+	})
+}
+
+func (s *AddSuite) TestAddExisting(c *gc.C) {
+	_, err := runAdd(c, "work", "dummy", "secret=chicken")
+	c.Assert(err, gc.ErrorMatches, `credential "work" already exists \(use --replace to update it\)`)
+	c.Assert(s.readCredentials(c)["work"].Attributes["secret"], gc.Equals, "pork")
+
+	_, err = runAdd(c, "--replace", "work", "dummy", "secret=chicken")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.readCredentials(c)["work"].Attributes["secret"], gc.Equals, "chicken")
+}
+
+func (s *AddSuite) TestAddUnknownProviderType(c *gc.C) {
+	_, err := runAdd(c, "new", "wondercloud", "secret=chicken")
+	c.Assert(err, gc.ErrorMatches, `no registered provider for "wondercloud"`)
+	c.Assert(s.readCredentials(c), gc.HasLen, 2)
+}
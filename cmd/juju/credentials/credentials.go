@@ -0,0 +1,52 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials
+
+import (
+	"github.com/juju/cmd"
+)
+
+const credentialsCmdDoc = `
+"juju credentials" is used to manage the cloud credentials stored on this
+client, in ~/.juju/credentials.yaml.
+
+A credential holds the environment attributes used to authenticate with
+clouds of one provider type, such as the access and secret keys for ec2.
+An environment uses a credential when its environments.yaml stanza names
+it, as in:
+
+    environments:
+        aws-staging:
+            type: ec2
+            credential: work-aws
+
+or when bootstrapped with "juju bootstrap --credential <name>". Updating
+a credential with "juju credentials add --replace" updates every
+environment using it, without editing their stanzas.
+`
+
+const credentialsCmdPurpose = "manage cloud credentials"
+
+// Command is the top-level command wrapping all credentials
+// functionality.
+type Command struct {
+	cmd.SuperCommand
+}
+
+// NewSuperCommand creates the credentials supercommand and registers
+// the subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	credentialscmd := Command{
+		SuperCommand: *cmd.NewSuperCommand(
+			cmd.SuperCommandParams{
+				Name:        "credentials",
+				Doc:         credentialsCmdDoc,
+				UsagePrefix: "juju",
+				Purpose:     credentialsCmdPurpose,
+			})}
+	credentialscmd.Register(&AddCommand{})
+	credentialscmd.Register(&ListCommand{})
+	credentialscmd.Register(&RemoveCommand{})
+	return &credentialscmd
+}
@@ -0,0 +1,36 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/credentials"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type CredentialsCommandSuite struct {
+	BaseCredentialsSuite
+}
+
+var _ = gc.Suite(&CredentialsCommandSuite{})
+
+var expectedSubcommands = []string{
+	"add",
+	"help",
+	"list",
+	"remove",
+}
+
+func (s *CredentialsCommandSuite) TestHelpSubcommands(c *gc.C) {
+	ctx, err := coretesting.RunCommand(c, credentials.NewSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+
+	namesFound := coretesting.ExtractCommandsFromHelpOutput(ctx)
+	c.Assert(namesFound, jc.DeepEquals, expectedSubcommands)
+	c.Assert(strings.Contains(coretesting.Stdout(ctx), "credential: work-aws"), jc.IsTrue)
+}
@@ -0,0 +1,80 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials
+
+import (
+	"sort"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/environs/cloud"
+)
+
+const listCommandDoc = `
+Lists the credentials stored on this client, along with their provider
+types and the names of the attributes they set. Attribute values are
+not shown.
+`
+
+// ListCommand lists the credentials in the client's credentials file.
+type ListCommand struct {
+	cmd.CommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list cloud credentials",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// CredentialInfo defines the serialization behaviour of a credential.
+type CredentialInfo struct {
+	Name       string   `yaml:"name" json:"name"`
+	Type       string   `yaml:"type" json:"type"`
+	Attributes []string `yaml:"attributes" json:"attributes"`
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	creds, err := cloud.ReadCredentials(cloud.JujuCredentialsPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var names []string
+	for name := range creds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	output := make([]CredentialInfo, len(names))
+	for i, name := range names {
+		cred := creds[name]
+		var attrNames []string
+		for attr := range cred.Attributes {
+			attrNames = append(attrNames, attr)
+		}
+		sort.Strings(attrNames)
+		output[i] = CredentialInfo{
+			Name:       name,
+			Type:       cred.Type,
+			Attributes: attrNames,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
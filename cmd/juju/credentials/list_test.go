@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/credentials"
+	"github.com/juju/juju/environs/cloud"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	BaseCredentialsSuite
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return coretesting.RunCommand(c, &credentials.ListCommand{}, args...)
+}
+
+func (s *ListSuite) TestList(c *gc.C) {
+	ctx, err := runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, `
+- name: home
+  type: dummy
+  attributes:
+  - comment
+  - secret
+- name: work
+  type: dummy
+  attributes:
+  - secret
+`[1:])
+}
+
+func (s *ListSuite) TestListJSON(c *gc.C) {
+	ctx, err := runList(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals,
+		`[{"name":"home","type":"dummy","attributes":["comment","secret"]},`+
+			`{"name":"work","type":"dummy","attributes":["secret"]}]`+"\n",
+	)
+}
+
+func (s *ListSuite) TestListEmpty(c *gc.C) {
+	err := cloud.WriteCredentials(cloud.JujuCredentialsPath(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	ctx, err := runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stdout(ctx), gc.Equals, "[]\n")
+}
+
+func (s *ListSuite) TestListUnexpectedArgs(c *gc.C) {
+	_, err := runList(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
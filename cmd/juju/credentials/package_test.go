@@ -0,0 +1,49 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/cloud"
+	_ "github.com/juju/juju/provider/dummy"
+	coretesting "github.com/juju/juju/testing"
+)
+
+func TestAll(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type BaseCredentialsSuite struct {
+	coretesting.FakeJujuHomeSuite
+}
+
+func (s *BaseCredentialsSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	err := cloud.WriteCredentials(cloud.JujuCredentialsPath(), map[string]cloud.Credential{
+		"work": {
+			Type: "dummy",
+			Attributes: map[string]string{
+				"secret": "pork",
+			},
+		},
+		"home": {
+			Type: "dummy",
+			Attributes: map[string]string{
+				"secret":  "beef",
+				"comment": "at home",
+			},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *BaseCredentialsSuite) readCredentials(c *gc.C) map[string]cloud.Credential {
+	creds, err := cloud.ReadCredentials(cloud.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	return creds
+}
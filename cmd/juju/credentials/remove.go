@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials
+
+import (
+	"fmt"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/environs/cloud"
+)
+
+const removeCommandDoc = `
+Removes a credential from this client. Environments naming the
+credential cannot be used until it is added again.
+`
+
+// RemoveCommand removes a credential from the client's credentials
+// file.
+type RemoveCommand struct {
+	cmd.CommandBase
+	Name string
+}
+
+// Info implements Command.Info.
+func (c *RemoveCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "remove",
+		Args:    "<name>",
+		Purpose: "remove a cloud credential",
+		Doc:     removeCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *RemoveCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no credential name specified")
+	}
+	c.Name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run implements Command.Run.
+func (c *RemoveCommand) Run(ctx *cmd.Context) error {
+	path := cloud.JujuCredentialsPath()
+	creds, err := cloud.ReadCredentials(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, ok := creds[c.Name]; !ok {
+		return errors.NotFoundf("credential %q", c.Name)
+	}
+	delete(creds, c.Name)
+	if err := cloud.WriteCredentials(path, creds); err != nil {
+		return errors.Trace(err)
+	}
+	fmt.Fprintf(ctx.Stderr, "credential %q removed\n", c.Name)
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package credentials_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/credentials"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type RemoveSuite struct {
+	BaseCredentialsSuite
+}
+
+var _ = gc.Suite(&RemoveSuite{})
+
+func runRemove(c *gc.C, args ...string) (*cmd.Context, error) {
+	return coretesting.RunCommand(c, &credentials.RemoveCommand{}, args...)
+}
+
+func (s *RemoveSuite) TestInit(c *gc.C) {
+	_, err := runRemove(c)
+	c.Assert(err, gc.ErrorMatches, "no credential name specified")
+
+	_, err = runRemove(c, "work", "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
+
+func (s *RemoveSuite) TestRemove(c *gc.C) {
+	ctx, err := runRemove(c, "work")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(coretesting.Stderr(ctx), gc.Equals, "credential \"work\" removed\n")
+
+	creds := s.readCredentials(c)
+	c.Assert(creds, gc.HasLen, 1)
+	_, ok := creds["home"]
+	c.Assert(ok, jc.IsTrue)
+}
+
+func (s *RemoveSuite) TestRemoveNotFound(c *gc.C) {
+	_, err := runRemove(c, "missing")
+	c.Assert(err, gc.ErrorMatches, `credential "missing" not found`)
+	c.Assert(s.readCredentials(c), gc.HasLen, 2)
+}
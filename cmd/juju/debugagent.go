@@ -0,0 +1,226 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/introspection"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// DebugAgentCommand reports on the internal state of
+// a state server's machine agent.
+type DebugAgentCommand struct {
+	envcmd.EnvCommandBase
+	out       cmd.Output
+	MachineId string
+}
+
+const debugAgentDoc = `
+Report on the internal state of a state server's machine agent: the
+workers it is running, whether they are running or restarting and the
+last error each one exited with; the API connections open to it, by
+entity; and the leases it holds, including service leadership.
+
+If no machine is specified, the state server that the client connects
+to is reported on.
+
+Only the owner of the environment or of the state server may use
+debug-agent.
+
+Examples:
+ juju debug-agent
+     Report on the state server the client connects to.
+ juju debug-agent 1
+     Report on the state server running on machine 1.
+`
+
+func (c *DebugAgentCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "debug-agent",
+		Args:    "[<machine>]",
+		Purpose: "report on the workers and connections of a state server",
+		Doc:     debugAgentDoc,
+	}
+}
+
+func (c *DebugAgentCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+func (c *DebugAgentCommand) Init(args []string) error {
+	if len(args) > 0 {
+		c.MachineId = args[0]
+		if !names.IsValidMachine(c.MachineId) {
+			return errors.Errorf("invalid machine id %q", c.MachineId)
+		}
+		args = args[1:]
+	}
+	return cmd.CheckEmpty(args)
+}
+
+// Run connects to the environment's API and reports on
+// the requested state server.
+func (c *DebugAgentCommand) Run(ctx *cmd.Context) error {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return errors.Annotate(err, "cannot get API connection")
+	}
+	defer root.Close()
+	report, err := introspection.NewClient(root).Report()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if c.MachineId != "" && report.AgentTag != names.NewMachineTag(c.MachineId).String() {
+		// We've connected to a different state server, so
+		// connect directly to the one we were asked about.
+		report, err = c.machineReport(root)
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return c.out.Write(ctx, formatAgentReport(report))
+}
+
+// machineReport connects to the API server running on the requested
+// machine and returns its report.
+func (c *DebugAgentCommand) machineReport(root *api.State) (params.IntrospectionReport, error) {
+	var noReport params.IntrospectionReport
+	status, err := root.Client().Status([]string{c.MachineId})
+	if err != nil {
+		return noReport, errors.Trace(err)
+	}
+	machine, ok := status.Machines[c.MachineId]
+	if !ok {
+		return noReport, errors.NotFoundf("machine %s", c.MachineId)
+	}
+	if !isStateServer(machine) {
+		return noReport, errors.Errorf("machine %s is not a state server", c.MachineId)
+	}
+	if machine.DNSName == "" {
+		return noReport, errors.Errorf("machine %s has no address", c.MachineId)
+	}
+	_, port, err := net.SplitHostPort(root.Addr())
+	if err != nil {
+		return noReport, errors.Trace(err)
+	}
+	st, err := c.openAPIAt(net.JoinHostPort(machine.DNSName, port))
+	if err != nil {
+		return noReport, errors.Annotatef(err, "cannot connect to machine %s", c.MachineId)
+	}
+	defer st.Close()
+	return introspection.NewClient(st).Report()
+}
+
+// openAPIAt opens an API connection to the given address, using
+// the environment's credentials.
+func (c *DebugAgentCommand) openAPIAt(addr string) (*api.State, error) {
+	creds, err := c.ConnectionCredentials()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	endpoint, err := c.ConnectionEndpoint(false)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	info := &api.Info{
+		Addrs:    []string{addr},
+		CACert:   endpoint.CACert,
+		Tag:      names.NewUserTag(creds.User),
+		Password: creds.Password,
+	}
+	if endpoint.EnvironUUID != "" {
+		info.EnvironTag = names.NewEnvironTag(endpoint.EnvironUUID)
+	}
+	return api.Open(info, api.DefaultDialOpts())
+}
+
+func isStateServer(machine api.MachineStatus) bool {
+	for _, job := range machine.Jobs {
+		if job == multiwatcher.JobManageEnviron {
+			return true
+		}
+	}
+	return false
+}
+
+type agentReport struct {
+	Agent       string                  `json:"agent" yaml:"agent"`
+	Workers     map[string]workerReport `json:"workers,omitempty" yaml:"workers,omitempty"`
+	Connections []connectionReport      `json:"connections,omitempty" yaml:"connections,omitempty"`
+	Leases      []leaseReport           `json:"leases,omitempty" yaml:"leases,omitempty"`
+	LeasesError string                  `json:"leases-error,omitempty" yaml:"leases-error,omitempty"`
+}
+
+type workerReport struct {
+	State     string                  `json:"state" yaml:"state"`
+	Restarts  int                     `json:"restarts,omitempty" yaml:"restarts,omitempty"`
+	LastError string                  `json:"last-error,omitempty" yaml:"last-error,omitempty"`
+	Workers   map[string]workerReport `json:"workers,omitempty" yaml:"workers,omitempty"`
+}
+
+type connectionReport struct {
+	Id            string    `json:"id" yaml:"id"`
+	Entity        string    `json:"entity,omitempty" yaml:"entity,omitempty"`
+	RemoteAddress string    `json:"remote-address" yaml:"remote-address"`
+	Connected     time.Time `json:"connected" yaml:"connected"`
+}
+
+type leaseReport struct {
+	Namespace string    `json:"namespace" yaml:"namespace"`
+	Holder    string    `json:"holder" yaml:"holder"`
+	Expiry    time.Time `json:"expiry" yaml:"expiry"`
+}
+
+func formatAgentReport(report params.IntrospectionReport) agentReport {
+	result := agentReport{
+		Agent:   report.AgentTag,
+		Workers: formatWorkerReports(report.Workers),
+	}
+	for _, conn := range report.Connections {
+		result.Connections = append(result.Connections, connectionReport{
+			Id:            conn.Id,
+			Entity:        conn.AuthTag,
+			RemoteAddress: conn.RemoteAddress,
+			Connected:     conn.Connected,
+		})
+	}
+	for _, lease := range report.Leases {
+		result.Leases = append(result.Leases, leaseReport{
+			Namespace: lease.Namespace,
+			Holder:    lease.Holder,
+			Expiry:    lease.Expiry,
+		})
+	}
+	if report.LeasesError != nil {
+		result.LeasesError = report.LeasesError.Error()
+	}
+	return result
+}
+
+func formatWorkerReports(reports []params.WorkerReport) map[string]workerReport {
+	if len(reports) == 0 {
+		return nil
+	}
+	result := make(map[string]workerReport)
+	for _, report := range reports {
+		result[report.Name] = workerReport{
+			State:     report.State,
+			Restarts:  report.Restarts,
+			LastError: report.LastError,
+			Workers:   formatWorkerReports(report.Workers),
+		}
+	}
+	return result
+}
@@ -0,0 +1,131 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"bytes"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker"
+)
+
+type DebugAgentSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&DebugAgentSuite{})
+
+func (s *DebugAgentSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	// The lease manager must be running for leases to be reported.
+	leaseWorker := worker.NewSimpleWorker(lease.WorkerLoop(s.State))
+	s.AddCleanup(func(c *gc.C) {
+		c.Assert(worker.Stop(leaseWorker), jc.ErrorIsNil)
+	})
+}
+
+func (s *DebugAgentSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args      []string
+		machineId string
+		err       string
+	}{{
+		args: nil,
+	}, {
+		args:      []string{"0"},
+		machineId: "0",
+	}, {
+		args: []string{"foo"},
+		err:  `invalid machine id "foo"`,
+	}, {
+		args: []string{"0", "1"},
+		err:  `unrecognized args: \["1"\]`,
+	}} {
+		c.Logf("test %d: %q", i, test.args)
+		command := &DebugAgentCommand{}
+		err := coretesting.InitCommand(envcmd.Wrap(command), test.args)
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(command.MachineId, gc.Equals, test.machineId)
+	}
+}
+
+func (s *DebugAgentSuite) TestDebugAgent(c *gc.C) {
+	for i, args := range [][]string{nil, {"0"}} {
+		c.Logf("test %d: %q", i, args)
+		ctx, err := coretesting.RunCommand(c, envcmd.Wrap(&DebugAgentCommand{}), args...)
+		c.Assert(err, jc.ErrorIsNil)
+
+		var report agentReport
+		err = goyaml.Unmarshal(ctx.Stdout.(*bytes.Buffer).Bytes(), &report)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(report.Agent, gc.Equals, "machine-0")
+		c.Assert(report.LeasesError, gc.Equals, "")
+		var found bool
+		for _, conn := range report.Connections {
+			if conn.Entity == s.AdminUserTag(c).String() {
+				found = true
+			}
+		}
+		c.Assert(found, jc.IsTrue)
+	}
+}
+
+func (s *DebugAgentSuite) TestDebugAgentNotStateServer(c *gc.C) {
+	// The API server reports itself as machine 0,
+	// so ask about a different machine.
+	var m *state.Machine
+	for i := 0; i < 2; i++ {
+		var err error
+		m, err = s.State.AddMachine("quantal", state.JobHostUnits)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	_, err := coretesting.RunCommand(c, envcmd.Wrap(&DebugAgentCommand{}), m.Id())
+	c.Assert(err, gc.ErrorMatches, "machine "+m.Id()+" is not a state server")
+}
+
+func (s *DebugAgentSuite) TestFormatAgentReport(c *gc.C) {
+	report := params.IntrospectionReport{
+		AgentTag: "machine-0",
+		Workers: []params.WorkerReport{{
+			Name:  "api",
+			State: "running",
+			Workers: []params.WorkerReport{{
+				Name:      "machiner",
+				State:     "starting",
+				Restarts:  3,
+				LastError: "boom",
+			}},
+		}},
+		LeasesError: &params.Error{Message: "timed out"},
+	}
+	c.Assert(formatAgentReport(report), jc.DeepEquals, agentReport{
+		Agent: "machine-0",
+		Workers: map[string]workerReport{
+			"api": {
+				State: "running",
+				Workers: map[string]workerReport{
+					"machiner": {
+						State:     "starting",
+						Restarts:  3,
+						LastError: "boom",
+					},
+				},
+			},
+		},
+		LeasesError: "timed out",
+	})
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/network"
 	"github.com/juju/juju/storage"
 )
 
@@ -34,6 +35,7 @@ type DeployCommand struct {
 	Config       cmd.FileVar
 	Constraints  constraints.Value
 	Networks     string
+	BindToSpaces string
 	BumpRevision bool   // Remove this once the 1.16 support is dropped.
 	RepoPath     string // defaults to JUJU_REPOSITORY
 
@@ -43,6 +45,10 @@ type DeployCommand struct {
 	// Storage is a map of storage constraints, keyed on the storage name
 	// defined in charm storage metadata.
 	Storage map[string]storage.Constraints
+
+	// Bindings maps relation endpoint names to the names of the
+	// spaces they are bound to, as given by --bind.
+	Bindings map[string]string
 }
 
 const deployDoc = `
@@ -96,6 +102,7 @@ Examples:
    juju deploy mysql --to 23       (deploy to machine 23)
    juju deploy mysql --to 24/lxc/3 (deploy to lxc container 3 on host machine 24)
    juju deploy mysql --to lxc:25   (deploy to a new lxc container on host machine 25)
+   juju deploy mysql --to lxd:26   (deploy to a new lxd container on host machine 26)
 
    juju deploy mysql -n 5 --constraints mem=8G
    (deploy 5 instances of mysql with at least 8 GB of RAM each)
@@ -113,6 +120,17 @@ to configure on it. The --networks argument instructs juju to add all the
 networks specified with it to all new machines deployed to host units of
 the service. Not supported on all providers.
 
+   juju deploy mysql --bind "db=internal server=public"
+   (deploy mysql so that the address it gives for relations on its "db"
+    endpoint is in the "internal" space, and for relations on its
+    "server" endpoint is in the "public" space)
+
+Relation endpoints of the service can be bound to spaces with the --bind
+argument, which takes a space-delimited list of <endpoint>=<space> pairs.
+The address a unit advertises as its private-address on a relation is
+then taken from the space the relation's endpoint is bound to. See
+"juju help space" for creating spaces.
+
 See Also:
    juju help constraints
    juju help set-constraints
@@ -136,6 +154,7 @@ func (c *DeployCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.Config, "config", "path to yaml-formatted service config")
 	f.Var(constraints.ConstraintsValue{Target: &c.Constraints}, "constraints", "set service constraints")
 	f.StringVar(&c.Networks, "networks", "", "bind the service to specific networks")
+	f.StringVar(&c.BindToSpaces, "bind", "", "bind service endpoints to spaces")
 	f.StringVar(&c.RepoPath, "repository", os.Getenv(osenv.JujuRepositoryEnvKey), "local charm repository")
 	if featureflag.Enabled(feature.Storage) {
 		// NOTE: if/when the feature flag is removed, bump the client
@@ -163,6 +182,11 @@ func (c *DeployCommand) Init(args []string) error {
 	default:
 		return cmd.CheckEmpty(args[2:])
 	}
+	bindings, err := parseBindings(c.BindToSpaces)
+	if err != nil {
+		return err
+	}
+	c.Bindings = bindings
 	return c.UnitCommandBase.Init(args)
 }
 
@@ -193,6 +217,7 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 
 	config.SpecializeCharmRepo(repo, conf)
+	repo = specializeCharmStore(repo, client, conf)
 
 	curl, err = addCharmViaAPI(client, ctx, curl, repo)
 	if err != nil {
@@ -248,7 +273,7 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 	}
 	// TODO(axw) rename ServiceDeployWithNetworks to ServiceDeploy,
 	// and ServiceDeploy to ServiceDeployLegacy or some such.
-	err = client.ServiceDeployWithNetworks(
+	err = client.ServiceDeployWithBindings(
 		curl.String(),
 		serviceName,
 		numUnits,
@@ -257,8 +282,12 @@ func (c *DeployCommand) Run(ctx *cmd.Context) error {
 		c.ToMachineSpec,
 		requestedNetworks,
 		c.Storage,
+		c.Bindings,
 	)
 	if params.IsCodeNotImplemented(err) {
+		if len(c.Bindings) > 0 {
+			return errors.New("cannot use --bind: not supported by the API server")
+		}
 		if haveNetworks {
 			return errors.New("cannot use --networks/--constraints networks=...: not supported by the API server")
 		}
@@ -321,6 +350,31 @@ func parseNetworks(networksValue string) []string {
 	return networks
 }
 
+// parseBindings returns a map of relation endpoint names to space names
+// by parsing the space-delimited <endpoint>=<space> pairs of the --bind
+// argument.
+func parseBindings(bindValue string) (map[string]string, error) {
+	var bindings map[string]string
+	for _, pair := range strings.Fields(bindValue) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid --bind value %q: expected <endpoint>=<space>", pair)
+		}
+		endpoint, space := parts[0], parts[1]
+		if !network.IsValidSpace(space) {
+			return nil, fmt.Errorf("%q is not a valid space name", space)
+		}
+		if _, ok := bindings[endpoint]; ok {
+			return nil, fmt.Errorf("endpoint %q bound more than once", endpoint)
+		}
+		if bindings == nil {
+			bindings = make(map[string]string)
+		}
+		bindings[endpoint] = space
+	}
+	return bindings, nil
+}
+
 // networkNamesToTags returns the given network names converted to
 // tags, or an error.
 func networkNamesToTags(networks []string) ([]string, error) {
@@ -4,6 +4,8 @@
 package main
 
 import (
+	"fmt"
+	"os"
 	"strings"
 
 	"github.com/juju/errors"
@@ -22,6 +24,7 @@ import (
 	"github.com/juju/juju/juju/osenv"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/charmmirror"
 	"github.com/juju/juju/storage/poolmanager"
 	"github.com/juju/juju/storage/provider"
 	"github.com/juju/juju/testcharms"
@@ -75,6 +78,15 @@ var initErrorTests = []struct {
 	}, {
 		args: []string{"craziness", "burble1", "--constraints", "gibber=plop"},
 		err:  `invalid value "gibber=plop" for flag --constraints: unknown constraint "gibber"`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db"},
+		err:  `invalid --bind value "db": expected <endpoint>=<space>`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=Internal"},
+		err:  `"Internal" is not a valid space name`,
+	}, {
+		args: []string{"craziness", "burble1", "--bind", "db=internal db=public"},
+		err:  `endpoint "db" bound more than once`,
 	},
 }
 
@@ -143,6 +155,32 @@ func (s *DeploySuite) TestCharmBundle(c *gc.C) {
 	s.AssertService(c, "some-service-name", curl, 1, 0)
 }
 
+func (s *DeploySuite) TestDeployFromCharmMirror(c *gc.C) {
+	s.PatchValue(&charm.CacheDir, c.MkDir())
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "dummy")
+	hash, size, err := utils.ReadFileSHA256(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	f, err := os.Open(archive.Path)
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	mirror, err := s.State.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	defer mirror.Close()
+	curl := charm.MustParseURL("cs:quantal/dummy-5")
+	err = mirror.AddCharm(f, charmmirror.Metadata{URL: curl, Size: size, SHA256: hash})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The mirror is not reachable from here; the revision must be
+	// resolved by the API server.
+	storeURL := fmt.Sprintf("https://10.0.0.1:17070/environment/%s/charmstore", s.State.EnvironUUID())
+	err = s.State.UpdateEnvironConfig(map[string]interface{}{"charm-store-url": storeURL}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = runDeploy(c, "cs:quantal/dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	s.AssertService(c, "dummy", curl, 1, 0)
+}
+
 func (s *DeploySuite) TestSubordinateCharm(c *gc.C) {
 	testcharms.Repo.CharmArchivePath(s.SeriesPath, "logging")
 	err := runDeploy(c, "local:logging")
@@ -208,6 +246,17 @@ func (s *DeploySuite) TestNetworks(c *gc.C) {
 	c.Assert(cons, jc.DeepEquals, constraints.MustParse("mem=2G cpu-cores=2 networks=net1,net0,^net3,^net4"))
 }
 
+func (s *DeploySuite) TestBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	testcharms.Repo.CharmArchivePath(s.SeriesPath, "dummy")
+	err = runDeploy(c, "local:dummy", "--bind", "juju-info=internal")
+	c.Assert(err, jc.ErrorIsNil)
+	curl := charm.MustParseURL("local:trusty/dummy-1")
+	service, _ := s.AssertService(c, "dummy", curl, 1, 0)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"juju-info": "internal"})
+}
+
 func (s *DeploySuite) TestStorageWithoutFeatureFlag(c *gc.C) {
 	err := runDeploy(c, "local:storage-block", "--storage", "data=1G")
 	c.Assert(err, gc.ErrorMatches, "flag provided but not defined: --storage")
@@ -400,6 +400,18 @@ instance-type
    conflict with other constraints depending on the provider (since the instance
    type my determine things like memory size etc.)
 
+preemptible
+   Preemptible, if true, starts the machine on cheaper capacity that the
+   provider may take back at any time: a spot instance on EC2, or a
+   preemptible instance on GCE. When that happens the machine goes down and
+   its status reports that the instance was reclaimed by the provider.
+   State servers can never be preemptible.
+
+spot-price
+   Spot-price is the maximum price, in US dollars per hour, to bid for a
+   preemptible EC2 instance. It defaults to the on-demand price of the
+   chosen instance type, and requires preemptible=true.
+
 Example:
 
    juju add-machine --constraints "arch=amd64 mem=8G tags=foo,^bar"
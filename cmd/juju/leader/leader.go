@@ -0,0 +1,30 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const leaderCommandDoc = `
+"juju leader" is used to see and change which unit leads each service.
+`
+
+const leaderCommandPurpose = "show and change service leadership"
+
+// NewSuperCommand creates the leader supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	leadercmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "leader",
+		Doc:         leaderCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     leaderCommandPurpose,
+	})
+	leadercmd.Register(envcmd.Wrap(&ShowCommand{}))
+	leadercmd.Register(envcmd.Wrap(&SetCommand{}))
+	return leadercmd
+}
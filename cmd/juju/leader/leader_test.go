@@ -0,0 +1,32 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader_test
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/leader"
+	"github.com/juju/juju/testing"
+)
+
+type leaderSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&leaderSuite{})
+
+var expectedLeaderCommandNames = []string{
+	"help",
+	"set",
+	"show",
+}
+
+func (s *leaderSuite) TestHelp(c *gc.C) {
+	// Check the help output
+	ctx, err := testing.RunCommand(c, leader.NewSuperCommand(), "--help")
+	c.Assert(err, jc.ErrorIsNil)
+	namesFound := testing.ExtractCommandsFromHelpOutput(ctx)
+	c.Assert(namesFound, gc.DeepEquals, expectedLeaderCommandNames)
+}
@@ -0,0 +1,77 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+const setCommandDoc = `
+Make the given unit the leader of its service.
+
+The current leader's lease is handed to the given unit, which runs its
+leader-elected hook once it notices. The previous leader is told it has
+lost its lease straight away, and runs its leader-deposed hook.
+
+Only the owner of the environment may change leadership.
+
+Example:
+
+  # Move leadership of mysql away from mysql/0 before rebooting its machine.
+  juju leader set mysql/1
+`
+
+// SetCommand hands leadership of a service to one of its units.
+type SetCommand struct {
+	envcmd.EnvCommandBase
+	UnitName string
+}
+
+// Info implements Command.Info.
+func (c *SetCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "set",
+		Args:    "<unit>",
+		Purpose: "make a unit the leader of its service",
+		Doc:     setCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *SetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no unit name specified")
+	}
+	if !names.IsValidUnit(args[0]) {
+		return errors.Errorf("invalid unit name %q", args[0])
+	}
+	c.UnitName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// SetLeaderAPI defines the API methods that the set command uses.
+type SetLeaderAPI interface {
+	SetServiceLeader(unit string) error
+	Close() error
+}
+
+var getSetLeaderAPI = func(c *SetCommand) (SetLeaderAPI, error) {
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *SetCommand) Run(ctx *cmd.Context) error {
+	client, err := getSetLeaderAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	return block.ProcessBlockedError(client.SetServiceLeader(c.UnitName), block.BlockChange)
+}
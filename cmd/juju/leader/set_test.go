@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/leader"
+	"github.com/juju/juju/testing"
+)
+
+type setCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakeSetLeaderAPI
+}
+
+var _ = gc.Suite(&setCommandSuite{})
+
+type fakeSetLeaderAPI struct {
+	unit string
+}
+
+func (*fakeSetLeaderAPI) Close() error {
+	return nil
+}
+
+func (f *fakeSetLeaderAPI) SetServiceLeader(unit string) error {
+	f.unit = unit
+	return nil
+}
+
+func (s *setCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakeSetLeaderAPI{}
+	s.PatchValue(leader.GetSetLeaderAPI, func(c *leader.SetCommand) (leader.SetLeaderAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runSetCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&leader.SetCommand{}), args...)
+}
+
+func (s *setCommandSuite) TestSet(c *gc.C) {
+	_, err := runSetCommand(c, "mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.mockAPI.unit, gc.Equals, "mysql/1")
+}
+
+func (*setCommandSuite) TestUnitRequired(c *gc.C) {
+	_, err := runSetCommand(c)
+	c.Assert(err, gc.ErrorMatches, "no unit name specified")
+}
+
+func (*setCommandSuite) TestInvalidUnit(c *gc.C) {
+	_, err := runSetCommand(c, "mysql")
+	c.Assert(err, gc.ErrorMatches, `invalid unit name "mysql"`)
+}
+
+func (*setCommandSuite) TestTooManyArgs(c *gc.C) {
+	_, err := runSetCommand(c, "mysql/1", "bad")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bad"\]`)
+}
@@ -0,0 +1,85 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const showCommandDoc = `
+Show the unit which currently leads the given service. Nothing is shown
+if the service has no leader.
+
+Example:
+
+  # Show the leader of the mysql service.
+  juju leader show mysql
+`
+
+// ShowCommand shows the leader unit of a service.
+type ShowCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *ShowCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show",
+		Args:    "<service>",
+		Purpose: "show the leader unit of a service",
+		Doc:     showCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ShowCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ShowCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// ShowLeaderAPI defines the API methods that the show command uses.
+type ShowLeaderAPI interface {
+	ServiceLeader(service string) (string, error)
+	Close() error
+}
+
+var getShowLeaderAPI = func(c *ShowCommand) (ShowLeaderAPI, error) {
+	return c.NewAPIClient()
+}
+
+// Run implements Command.Run.
+func (c *ShowCommand) Run(ctx *cmd.Context) error {
+	client, err := getShowLeaderAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	unitName, err := client.ServiceLeader(c.ServiceName)
+	if err != nil {
+		return err
+	}
+	if unitName == "" {
+		return nil
+	}
+	return c.out.Write(ctx, unitName)
+}
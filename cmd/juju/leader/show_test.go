@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package leader_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/leader"
+	"github.com/juju/juju/testing"
+)
+
+type showCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakeShowLeaderAPI
+}
+
+var _ = gc.Suite(&showCommandSuite{})
+
+type fakeShowLeaderAPI struct {
+	service string
+	leader  string
+}
+
+func (*fakeShowLeaderAPI) Close() error {
+	return nil
+}
+
+func (f *fakeShowLeaderAPI) ServiceLeader(service string) (string, error) {
+	f.service = service
+	return f.leader, nil
+}
+
+func (s *showCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakeShowLeaderAPI{leader: "mysql/1"}
+	s.PatchValue(leader.GetShowLeaderAPI, func(c *leader.ShowCommand) (leader.ShowLeaderAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runShowCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&leader.ShowCommand{}), args...)
+}
+
+func (s *showCommandSuite) TestShow(c *gc.C) {
+	ctx, err := runShowCommand(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.mockAPI.service, gc.Equals, "mysql")
+	c.Check(testing.Stdout(ctx), gc.Equals, "mysql/1\n")
+}
+
+func (s *showCommandSuite) TestShowNoLeader(c *gc.C) {
+	s.mockAPI.leader = ""
+	ctx, err := runShowCommand(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+}
+
+func (*showCommandSuite) TestServiceRequired(c *gc.C) {
+	_, err := runShowCommand(c)
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+}
+
+func (*showCommandSuite) TestInvalidService(c *gc.C) {
+	_, err := runShowCommand(c, "mysql/0")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "mysql/0"`)
+}
+
+func (*showCommandSuite) TestTooManyArgs(c *gc.C) {
+	_, err := runShowCommand(c, "mysql", "bad")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["bad"\]`)
+}
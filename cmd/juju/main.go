@@ -17,10 +17,16 @@ import (
 	"github.com/juju/juju/cmd/juju/backups"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/cmd/juju/cachedimages"
+	"github.com/juju/juju/cmd/juju/charmcmd"
 	"github.com/juju/juju/cmd/juju/common"
+	"github.com/juju/juju/cmd/juju/credentials"
 	"github.com/juju/juju/cmd/juju/environment"
+	"github.com/juju/juju/cmd/juju/leader"
 	"github.com/juju/juju/cmd/juju/machine"
+	"github.com/juju/juju/cmd/juju/resources"
+	"github.com/juju/juju/cmd/juju/space"
 	"github.com/juju/juju/cmd/juju/storage"
+	"github.com/juju/juju/cmd/juju/subnet"
 	"github.com/juju/juju/cmd/juju/user"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/feature"
@@ -128,20 +134,24 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Reporting commands.
 	r.Register(wrapEnvCommand(&StatusCommand{}))
+	r.Register(wrapEnvCommand(&ShowRelationCommand{}))
 	r.Register(&SwitchCommand{})
 	r.Register(wrapEnvCommand(&EndpointCommand{}))
 	r.Register(wrapEnvCommand(&APIInfoCommand{}))
 
 	// Error resolution and debugging commands.
 	r.Register(wrapEnvCommand(&RunCommand{}))
+	r.Register(wrapEnvCommand(&RunHookCommand{}))
 	r.Register(wrapEnvCommand(&SCPCommand{}))
 	r.Register(wrapEnvCommand(&SSHCommand{}))
 	r.Register(wrapEnvCommand(&ResolvedCommand{}))
 	r.Register(wrapEnvCommand(&DebugLogCommand{}))
 	r.Register(wrapEnvCommand(&DebugHooksCommand{}))
+	r.Register(wrapEnvCommand(&DebugAgentCommand{}))
 
 	// Configuration commands.
 	r.Register(&InitCommand{})
+	r.Register(credentials.NewSuperCommand())
 	r.Register(wrapEnvCommand(&GetCommand{}))
 	r.Register(wrapEnvCommand(&SetCommand{}))
 	r.Register(wrapEnvCommand(&UnsetCommand{}))
@@ -150,6 +160,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.RegisterDeprecated(wrapEnvCommand(&common.SetConstraintsCommand{}),
 		twoDotOhDeprecation("environment set-constraints or service set-constraints"))
 	r.Register(wrapEnvCommand(&ExposeCommand{}))
+	r.Register(wrapEnvCommand(&OfferCommand{}))
 	r.Register(wrapEnvCommand(&SyncToolsCommand{}))
 	r.Register(wrapEnvCommand(&UnexposeCommand{}))
 	r.Register(wrapEnvCommand(&UpgradeJujuCommand{}))
@@ -157,6 +168,7 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 
 	// Charm publishing commands.
 	r.Register(wrapEnvCommand(&PublishCommand{}))
+	r.Register(charmcmd.NewSuperCommand())
 
 	// Charm tool commands.
 	r.Register(&HelpToolCommand{})
@@ -173,6 +185,13 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	// Manage cached images
 	r.Register(cachedimages.NewSuperCommand())
 
+	// Manage service leadership
+	r.Register(leader.NewSuperCommand())
+
+	// Manage service resources
+	r.Register(resources.NewSuperCommand())
+	r.Register(wrapEnvCommand(&resources.PushCommand{}))
+
 	// Manage machines
 	r.Register(machine.NewSuperCommand())
 	r.RegisterSuperAlias("add-machine", "machine", "add", twoDotOhDeprecation("machine add"))
@@ -200,6 +219,10 @@ func registerCommands(r commandRegistry, ctx *cmd.Context) {
 	r.Register(block.NewSuperBlockCommand())
 	r.Register(wrapEnvCommand(&block.UnblockCommand{}))
 
+	// Manage networks
+	r.Register(space.NewSuperCommand())
+	r.Register(subnet.NewSuperCommand())
+
 	// Manage storage
 	if featureflag.Enabled(feature.Storage) {
 		r.Register(storage.NewSuperCommand())
@@ -200,6 +200,9 @@ var commandNames = []string{
 	"block",
 	"bootstrap",
 	"cached-images",
+	"charm",
+	"credentials",
+	"debug-agent",
 	"debug-hooks",
 	"debug-log",
 	"deploy",
@@ -220,24 +223,32 @@ var commandNames = []string{
 	"help",
 	"help-tool",
 	"init",
+	"leader",
 	"machine",
+	"offer",
 	"publish",
+	"push-resource",
 	"remove-machine",  // alias for destroy-machine
 	"remove-relation", // alias for destroy-relation
 	"remove-service",  // alias for destroy-service
 	"remove-unit",     // alias for destroy-unit
 	"resolved",
+	"resources",
 	"retry-provisioning",
 	"run",
+	"run-hook",
 	"scp",
 	"set",
 	"set-constraints",
 	"set-env", // alias for set-environment
 	"set-environment",
+	"show-relation",
+	"space",
 	"ssh",
 	"stat", // alias for status
 	"status",
 	"storage",
+	"subnet",
 	"switch",
 	"sync-tools",
 	"terminate-machine", // alias for destroy-machine
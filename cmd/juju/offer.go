@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// OfferCommand offers a service for relation to services of other
+// environments.
+type OfferCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName string
+	Endpoints   []string
+}
+
+var jujuOfferHelp = `
+Offers endpoints of a service for relation to services of other environments
+hosted by the same state server. If no relation names are given, all of the
+service's global, non-peer relations are offered; offering a service again
+replaces the relations offered.
+
+Services of other environments relate to the offered service with add-relation,
+prefixing it with the name of this environment and a slash:
+
+    juju offer mysql db
+    juju add-relation -e app-env wordpress db-env/mysql
+
+The units of each service then appear in the relation in the environment of
+the other, with their relation settings kept up to date.
+`
+
+func (c *OfferCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "offer",
+		Args:    "<service> [<relation name> ...]",
+		Purpose: "offer a service for relation to other environments",
+		Doc:     jujuOfferHelp,
+	}
+}
+
+func (c *OfferCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	c.ServiceName = args[0]
+	c.Endpoints = args[1:]
+	return nil
+}
+
+func (c *OfferCommand) Run(_ *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	err = client.ServiceOffer(c.ServiceName, c.Endpoints...)
+	return block.ProcessBlockedError(err, block.BlockChange)
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/testing"
+)
+
+type OfferSuite struct {
+	jujutesting.JujuConnSuite
+	CmdBlockHelper
+}
+
+func (s *OfferSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	s.CmdBlockHelper = NewCmdBlockHelper(s.APIState)
+	c.Assert(s.CmdBlockHelper, gc.NotNil)
+	s.AddCleanup(func(*gc.C) { s.CmdBlockHelper.Close() })
+}
+
+var _ = gc.Suite(&OfferSuite{})
+
+func runOffer(c *gc.C, args ...string) error {
+	_, err := testing.RunCommand(c, envcmd.Wrap(&OfferCommand{}), args...)
+	return err
+}
+
+func (s *OfferSuite) TestInit(c *gc.C) {
+	err := runOffer(c)
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+}
+
+func (s *OfferSuite) TestOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	err := runOffer(c, "wordpress", "db", "url")
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := s.State.ServiceOffer("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"db", "url"})
+
+	err = runOffer(c, "wordpress", "logging-dir")
+	c.Assert(err, gc.ErrorMatches, `cannot offer service "wordpress": endpoint "logging-dir" cannot be offered`)
+}
+
+func (s *OfferSuite) TestBlockOffer(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	s.BlockAllChanges(c, "TestBlockOffer")
+	err := runOffer(c, "wordpress")
+	s.AssertBlocked(c, err, ".*TestBlockOffer.*")
+}
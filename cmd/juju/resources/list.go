@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources
+
+import (
+	"bytes"
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const listCommandDoc = `
+List the current revision of each resource attached to a service.
+
+Example:
+
+  # List the resources of the hadoop service.
+  juju resources list hadoop
+`
+
+// ListCommand lists the resources of a service.
+type ListCommand struct {
+	envcmd.EnvCommandBase
+	out         cmd.Output
+	ServiceName string
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Args:    "<service>",
+		Purpose: "list the resources of a service",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no service name specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// ListResourcesAPI defines the API methods that the list command uses.
+type ListResourcesAPI interface {
+	ServiceResources(service string) ([]params.ServiceResource, error)
+	Close() error
+}
+
+var getListResourcesAPI = func(c *ListCommand) (ListResourcesAPI, error) {
+	return getServiceAPI(&c.EnvCommandBase)
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	client, err := getListResourcesAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resources, err := client.ServiceResources(c.ServiceName)
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatResources(resources))
+}
+
+// ResourceInfo defines the serialization behaviour of a resource.
+type ResourceInfo struct {
+	Name     string `yaml:"name" json:"name"`
+	Revision int    `yaml:"revision" json:"revision"`
+	Size     int64  `yaml:"size" json:"size"`
+	SHA384   string `yaml:"sha384" json:"sha384"`
+	Uploaded string `yaml:"uploaded" json:"uploaded"`
+}
+
+func formatResources(resources []params.ServiceResource) []ResourceInfo {
+	output := make([]ResourceInfo, len(resources))
+	for i, resource := range resources {
+		output[i] = ResourceInfo{
+			Name:     resource.Name,
+			Revision: resource.Revision,
+			Size:     resource.Size,
+			SHA384:   resource.SHA384,
+			Uploaded: resource.Uploaded.UTC().Format(time.RFC3339),
+		}
+	}
+	return output
+}
+
+// formatTabular returns a tabular summary of the resources.
+func formatTabular(value interface{}) ([]byte, error) {
+	resources, ok := value.([]ResourceInfo)
+	if !ok {
+		return nil, errors.Errorf("expected value of type %T, got %T", resources, value)
+	}
+	if len(resources) == 0 {
+		return nil, nil
+	}
+	var out bytes.Buffer
+	tw := tabwriter.NewWriter(&out, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(tw, "NAME\tREVISION\tSIZE\tUPLOADED")
+	for _, resource := range resources {
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\n",
+			resource.Name,
+			resource.Revision,
+			resource.Size,
+			resource.Uploaded,
+		)
+	}
+	tw.Flush()
+	return out.Bytes(), nil
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/resources"
+	"github.com/juju/juju/testing"
+)
+
+type listCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakeListResourcesAPI
+}
+
+var _ = gc.Suite(&listCommandSuite{})
+
+type fakeListResourcesAPI struct {
+	service   string
+	resources []params.ServiceResource
+}
+
+func (*fakeListResourcesAPI) Close() error {
+	return nil
+}
+
+func (f *fakeListResourcesAPI) ServiceResources(service string) ([]params.ServiceResource, error) {
+	f.service = service
+	return f.resources, nil
+}
+
+func (s *listCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakeListResourcesAPI{
+		resources: []params.ServiceResource{{
+			Name:     "tarball",
+			Revision: 3,
+			Size:     1024,
+			SHA384:   "abc",
+			Uploaded: time.Date(2015, 6, 1, 12, 0, 0, 0, time.UTC),
+		}},
+	}
+	s.PatchValue(resources.GetListResourcesAPI, func(c *resources.ListCommand) (resources.ListResourcesAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runListCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&resources.ListCommand{}), args...)
+}
+
+func (s *listCommandSuite) TestList(c *gc.C) {
+	ctx, err := runListCommand(c, "hadoop")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.mockAPI.service, gc.Equals, "hadoop")
+	c.Check(testing.Stdout(ctx), gc.Equals, ""+
+		"NAME    REVISION SIZE UPLOADED\n"+
+		"tarball 3        1024 2015-06-01T12:00:00Z\n",
+	)
+}
+
+func (s *listCommandSuite) TestListJSON(c *gc.C) {
+	ctx, err := runListCommand(c, "hadoop", "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), jc.JSONEquals, []interface{}{
+		map[string]interface{}{
+			"name":     "tarball",
+			"revision": 3,
+			"size":     1024,
+			"sha384":   "abc",
+			"uploaded": "2015-06-01T12:00:00Z",
+		},
+	})
+}
+
+func (s *listCommandSuite) TestListNoResources(c *gc.C) {
+	s.mockAPI.resources = nil
+	ctx, err := runListCommand(c, "hadoop")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "")
+}
+
+func (*listCommandSuite) TestServiceRequired(c *gc.C) {
+	_, err := runListCommand(c)
+	c.Assert(err, gc.ErrorMatches, "no service name specified")
+}
+
+func (*listCommandSuite) TestInvalidService(c *gc.C) {
+	_, err := runListCommand(c, "hadoop/0")
+	c.Assert(err, gc.ErrorMatches, `invalid service name "hadoop/0"`)
+}
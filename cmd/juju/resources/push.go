@@ -0,0 +1,104 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources
+
+import (
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const pushCommandDoc = `
+Upload the content of a file as the next revision of the named resource of
+a service. The resource must be declared in the resources section of the
+service charm's metadata.yaml. Units of the service are notified of the new
+revision with an upgrade-charm hook, in which they can fetch it with the
+resource-get hook tool.
+
+Example:
+
+  # Upload hadoop.tar.gz as the "tarball" resource of the hadoop service.
+  juju push-resource hadoop tarball=./hadoop.tar.gz
+`
+
+// PushCommand uploads a new revision of a service resource.
+type PushCommand struct {
+	envcmd.EnvCommandBase
+	ServiceName  string
+	ResourceName string
+	Filename     string
+}
+
+// Info implements Command.Info.
+func (c *PushCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "push-resource",
+		Args:    "<service> <name>=<file>",
+		Purpose: "upload a new revision of a service resource",
+		Doc:     pushCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *PushCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no service name specified")
+	case 1:
+		return errors.New("no resource specified")
+	}
+	if !names.IsValidService(args[0]) {
+		return errors.Errorf("invalid service name %q", args[0])
+	}
+	c.ServiceName = args[0]
+	parts := strings.SplitN(args[1], "=", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return errors.Errorf("expected name=file, got %q", args[1])
+	}
+	c.ResourceName, c.Filename = parts[0], parts[1]
+	return cmd.CheckEmpty(args[2:])
+}
+
+// PushResourceAPI defines the API methods that the push command uses.
+type PushResourceAPI interface {
+	PushResource(service, name string, content io.Reader, size int64) (params.ServiceResource, error)
+	Close() error
+}
+
+var getPushResourceAPI = func(c *PushCommand) (PushResourceAPI, error) {
+	return getServiceAPI(&c.EnvCommandBase)
+}
+
+// Run implements Command.Run.
+func (c *PushCommand) Run(ctx *cmd.Context) error {
+	f, err := os.Open(ctx.AbsPath(c.Filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	client, err := getPushResourceAPI(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	resource, err := client.PushResource(c.ServiceName, c.ResourceName, f, info.Size())
+	if err != nil {
+		return err
+	}
+	ctx.Infof("uploaded revision %d of resource %q", resource.Revision, resource.Name)
+	return nil
+}
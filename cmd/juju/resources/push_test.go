@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources_test
+
+import (
+	"io"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/resources"
+	"github.com/juju/juju/testing"
+)
+
+type pushCommandSuite struct {
+	testing.FakeJujuHomeSuite
+	mockAPI *fakePushResourceAPI
+}
+
+var _ = gc.Suite(&pushCommandSuite{})
+
+type fakePushResourceAPI struct {
+	service string
+	name    string
+	content string
+	size    int64
+}
+
+func (*fakePushResourceAPI) Close() error {
+	return nil
+}
+
+func (f *fakePushResourceAPI) PushResource(service, name string, content io.Reader, size int64) (params.ServiceResource, error) {
+	data, err := ioutil.ReadAll(content)
+	if err != nil {
+		return params.ServiceResource{}, err
+	}
+	f.service, f.name, f.content, f.size = service, name, string(data), size
+	return params.ServiceResource{Name: name, Revision: 2, Size: size}, nil
+}
+
+func (s *pushCommandSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+	s.mockAPI = &fakePushResourceAPI{}
+	s.PatchValue(resources.GetPushResourceAPI, func(c *resources.PushCommand) (resources.PushResourceAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+func runPushCommand(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&resources.PushCommand{}), args...)
+}
+
+func (s *pushCommandSuite) TestPush(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "hadoop.tar.gz")
+	err := ioutil.WriteFile(path, []byte("content"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := runPushCommand(c, "hadoop", "tarball="+path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.mockAPI.service, gc.Equals, "hadoop")
+	c.Check(s.mockAPI.name, gc.Equals, "tarball")
+	c.Check(s.mockAPI.content, gc.Equals, "content")
+	c.Check(s.mockAPI.size, gc.Equals, int64(7))
+	c.Check(testing.Stderr(ctx), gc.Equals, "uploaded revision 2 of resource \"tarball\"\n")
+}
+
+func (s *pushCommandSuite) TestPushMissingFile(c *gc.C) {
+	path := filepath.Join(c.MkDir(), "missing")
+	_, err := runPushCommand(c, "hadoop", "tarball="+path)
+	c.Assert(err, gc.ErrorMatches, "open .*missing: no such file or directory")
+	c.Check(s.mockAPI.service, gc.Equals, "")
+}
+
+func (*pushCommandSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "no service name specified",
+	}, {
+		args: []string{"hadoop"},
+		err:  "no resource specified",
+	}, {
+		args: []string{"hadoop/0", "tarball=file"},
+		err:  `invalid service name "hadoop/0"`,
+	}, {
+		args: []string{"hadoop", "tarball"},
+		err:  `expected name=file, got "tarball"`,
+	}, {
+		args: []string{"hadoop", "=file"},
+		err:  `expected name=file, got "=file"`,
+	}, {
+		args: []string{"hadoop", "tarball=file", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runPushCommand(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package resources
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/service"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const resourcesCommandDoc = `
+"juju resources" is used to inspect the resources attached to services.
+Resources are uploaded with "juju push-resource".
+`
+
+const resourcesCommandPurpose = "inspect service resources"
+
+// NewSuperCommand creates the resources supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	resourcescmd := cmd.NewSuperCommand(cmd.SuperCommandParams{
+		Name:        "resources",
+		Doc:         resourcesCommandDoc,
+		UsagePrefix: "juju",
+		Purpose:     resourcesCommandPurpose,
+	})
+	resourcescmd.Register(envcmd.Wrap(&ListCommand{}))
+	return resourcescmd
+}
+
+func getServiceAPI(c *envcmd.EnvCommandBase) (*service.Client, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return service.NewClient(root), nil
+}
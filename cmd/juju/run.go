@@ -29,6 +29,9 @@ type RunCommand struct {
 	services []string
 	units    []string
 	commands string
+
+	relationId     string
+	remoteUnitName string
 }
 
 const runDoc = `
@@ -57,6 +60,16 @@ the unit.
 in the environment.  If you specify --all you cannot provide additional
 targets.
 
+--relation runs the commands on units in the context of a relation, as
+if they were run by a hook of that relation; the relation may be given
+by its id, or by the name and id shown by "relation-ids", such as
+"db:3". --remote-unit further chooses the remote unit of that relation
+in whose context the commands are run. For example:
+
+  juju run --unit wordpress/0 --relation db:3 --remote-unit mysql/0 relation-get
+
+A relation context cannot be used when running on machines.
+
 `
 
 func (c *RunCommand) Info() *cmd.Info {
@@ -75,6 +88,8 @@ func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewStringsValue(nil, &c.machines), "machine", "one or more machine ids")
 	f.Var(cmd.NewStringsValue(nil, &c.services), "service", "one or more service names")
 	f.Var(cmd.NewStringsValue(nil, &c.units), "unit", "one or more unit ids")
+	f.StringVar(&c.relationId, "relation", "", "the relation in whose context the commands are run on units")
+	f.StringVar(&c.remoteUnitName, "remote-unit", "", "the remote unit in whose context the commands are run on units")
 }
 
 func (c *RunCommand) Init(args []string) error {
@@ -98,6 +113,13 @@ func (c *RunCommand) Init(args []string) error {
 			return fmt.Errorf("You must specify a target, either through --all, --machine, --service or --unit")
 		}
 	}
+	if c.relationId != "" {
+		if c.all || len(c.machines) != 0 {
+			return fmt.Errorf("You cannot specify --relation when running on machines")
+		}
+	} else if c.remoteUnitName != "" {
+		return fmt.Errorf("You cannot specify --remote-unit without --relation")
+	}
 
 	var nameErrors []string
 	for _, machineId := range c.machines {
@@ -115,6 +137,9 @@ func (c *RunCommand) Init(args []string) error {
 			nameErrors = append(nameErrors, fmt.Sprintf("  %q is not a valid unit name", unit))
 		}
 	}
+	if c.remoteUnitName != "" && !names.IsValidUnit(c.remoteUnitName) {
+		nameErrors = append(nameErrors, fmt.Sprintf("  %q is not a valid remote unit name", c.remoteUnitName))
+	}
 	if len(nameErrors) > 0 {
 		return fmt.Errorf("The following run targets are not valid:\n%s",
 			strings.Join(nameErrors, "\n"))
@@ -184,11 +209,13 @@ func (c *RunCommand) Run(ctx *cmd.Context) error {
 		runResults, err = client.RunOnAllMachines(c.commands, c.timeout)
 	} else {
 		params := params.RunParams{
-			Commands: c.commands,
-			Timeout:  c.timeout,
-			Machines: c.machines,
-			Services: c.services,
-			Units:    c.units,
+			Commands:       c.commands,
+			Timeout:        c.timeout,
+			Machines:       c.machines,
+			Services:       c.services,
+			Units:          c.units,
+			RelationId:     c.relationId,
+			RemoteUnitName: c.remoteUnitName,
 		}
 		runResults, err = client.Run(params)
 	}
@@ -36,6 +36,9 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		services []string
 		commands string
 		errMatch string
+
+		relationId string
+		remoteUnit string
 	}{{
 		message:  "no args",
 		errMatch: "no commands specified",
@@ -107,6 +110,31 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 		machines: []string{"0"},
 		services: []string{"mysql"},
 		units:    []string{"wordpress/0", "wordpress/1"},
+	}, {
+		message:    "command to unit in relation context",
+		args:       []string{"--unit=wordpress/0", "--relation=db:3", "--remote-unit=mysql/0", "relation-get"},
+		commands:   "relation-get",
+		units:      []string{"wordpress/0"},
+		relationId: "db:3",
+		remoteUnit: "mysql/0",
+	}, {
+		message:  "relation context on machines",
+		args:     []string{"--machine=0", "--relation=db:3", "relation-get"},
+		errMatch: "You cannot specify --relation when running on machines",
+	}, {
+		message:  "relation context on all machines",
+		args:     []string{"--all", "--relation=db:3", "relation-get"},
+		errMatch: "You cannot specify --relation when running on machines",
+	}, {
+		message:  "remote unit without relation",
+		args:     []string{"--unit=wordpress/0", "--remote-unit=mysql/0", "relation-get"},
+		errMatch: "You cannot specify --remote-unit without --relation",
+	}, {
+		message: "bad remote unit name",
+		args:    []string{"--unit=wordpress/0", "--relation=db:3", "--remote-unit=mysql", "relation-get"},
+		errMatch: "" +
+			"The following run targets are not valid:\n" +
+			"  \"mysql\" is not a valid remote unit name",
 	}} {
 		c.Log(fmt.Sprintf("%v: %s", i, test.message))
 		runCmd := &RunCommand{}
@@ -117,6 +145,8 @@ func (*RunSuite) TestTargetArgParsing(c *gc.C) {
 			c.Check(runCmd.services, gc.DeepEquals, test.services)
 			c.Check(runCmd.units, gc.DeepEquals, test.units)
 			c.Check(runCmd.commands, gc.Equals, test.commands)
+			c.Check(runCmd.relationId, gc.Equals, test.relationId)
+			c.Check(runCmd.remoteUnitName, gc.Equals, test.remoteUnit)
 		}
 	}
 }
@@ -269,6 +299,22 @@ func (s *RunSuite) TestRunForMachineAndUnit(c *gc.C) {
 	c.Check(testing.Stdout(context), gc.Equals, string(jsonFormatted)+"\n")
 }
 
+func (s *RunSuite) TestRunInRelationContext(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.setResponse("wordpress/0", mockResponse{
+		stdout:    "host: db-0\n",
+		machineId: "1",
+		unitId:    "wordpress/0",
+	})
+	context, err := testing.RunCommand(c, envcmd.Wrap(&RunCommand{}),
+		"--unit=wordpress/0", "--relation=db:3", "--remote-unit=mysql/0", "relation-get",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(context), gc.Equals, "host: db-0\n")
+	c.Check(mock.runParams.RelationId, gc.Equals, "db:3")
+	c.Check(mock.runParams.RemoteUnitName, gc.Equals, "mysql/0")
+}
+
 func (s *RunSuite) TestBlockRunForMachineAndUnit(c *gc.C) {
 	mock := s.setupMockAPI()
 	// Block operation
@@ -391,6 +437,7 @@ type mockRunAPI struct {
 	machines  map[string]bool
 	responses map[string]params.RunResult
 	block     bool
+	runParams params.RunParams
 }
 
 type mockResponse struct {
@@ -463,6 +510,7 @@ func (m *mockRunAPI) RunOnAllMachines(commands string, timeout time.Duration) ([
 
 func (m *mockRunAPI) Run(runParams params.RunParams) ([]params.RunResult, error) {
 	var result []params.RunResult
+	m.runParams = runParams
 
 	if m.block {
 		return result, common.ErrOperationBlocked("The operation has been blocked.")
@@ -0,0 +1,126 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/names"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/block"
+)
+
+// RunHookCommand queues a hook to run on a unit.
+type RunHookCommand struct {
+	envcmd.EnvCommandBase
+	timeout        time.Duration
+	unit           string
+	hook           string
+	relationId     string
+	remoteUnitName string
+}
+
+const runHookDoc = `
+Run the named hook again on a unit. The hook is queued by the unit agent
+and run in the same way as hooks run in response to changes, so a hook
+that fails puts the unit into an error state that must be resolved.
+
+Only hooks that a charm must already expect to run repeatedly can be
+run: config-changed, start, upgrade-charm, leader-settings-changed,
+meter-status-changed, collect-metrics and the relation-changed hook of
+any relation, such as db-relation-changed.
+
+If the unit has more than one relation with the hook's relation name,
+--relation chooses one, by its id or by the name and id shown by
+"relation-ids", such as "db:3". If the relation has more than one remote
+unit, --remote-unit chooses the one for which the hook is run.
+
+Examples:
+
+    juju run-hook wordpress/0 config-changed
+    juju run-hook wordpress/0 db-relation-changed --relation db:3 --remote-unit mysql/0
+`
+
+func (c *RunHookCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "run-hook",
+		Args:    "<unit> <hook name>",
+		Purpose: "run a hook again on a unit",
+		Doc:     runHookDoc,
+	}
+}
+
+func (c *RunHookCommand) SetFlags(f *gnuflag.FlagSet) {
+	f.DurationVar(&c.timeout, "timeout", 5*time.Minute, "how long to wait for the unit agent to queue the hook")
+	f.StringVar(&c.relationId, "relation", "", "the relation to run a relation hook for")
+	f.StringVar(&c.remoteUnitName, "remote-unit", "", "the remote unit to run a relation hook for")
+}
+
+func (c *RunHookCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return fmt.Errorf("no unit specified")
+	case 1:
+		return fmt.Errorf("no hook specified")
+	}
+	c.unit, c.hook, args = args[0], args[1], args[2:]
+	if !names.IsValidUnit(c.unit) {
+		return fmt.Errorf("invalid unit name %q", c.unit)
+	}
+	if c.remoteUnitName != "" {
+		if c.relationId == "" {
+			return fmt.Errorf("You cannot specify --remote-unit without --relation")
+		}
+		if !names.IsValidUnit(c.remoteUnitName) {
+			return fmt.Errorf("invalid remote unit name %q", c.remoteUnitName)
+		}
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func (c *RunHookCommand) Run(ctx *cmd.Context) error {
+	client, err := getRunHookAPIClient(c)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	result, err := client.RunHook(params.RunHookParams{
+		Unit:           c.unit,
+		Hook:           c.hook,
+		RelationId:     c.relationId,
+		RemoteUnitName: c.remoteUnitName,
+		Timeout:        c.timeout,
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Stdout.Write(result.Stdout)
+	ctx.Stderr.Write(result.Stderr)
+	if result.Error != "" {
+		// Convert the error string back into an error object.
+		return fmt.Errorf("%s", result.Error)
+	}
+	if result.Code != 0 {
+		return cmd.NewRcPassthroughError(result.Code)
+	}
+	ctx.Infof("Queued %s hook on unit %s.", c.hook, c.unit)
+	return nil
+}
+
+// RunHookClient is the part of the API client used by RunHookCommand,
+// which may be replaced for testing.
+type RunHookClient interface {
+	Close() error
+	RunHook(run params.RunHookParams) (params.RunResult, error)
+}
+
+var getRunHookAPIClient = func(c *RunHookCommand) (RunHookClient, error) {
+	return c.NewAPIClient()
+}
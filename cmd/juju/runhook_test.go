@@ -0,0 +1,143 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/exec"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/testing"
+)
+
+type RunHookSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&RunHookSuite{})
+
+func (*RunHookSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args       []string
+		unit       string
+		hook       string
+		relationId string
+		remoteUnit string
+		errMatch   string
+	}{{
+		errMatch: "no unit specified",
+	}, {
+		args:     []string{"wordpress/0"},
+		errMatch: "no hook specified",
+	}, {
+		args:     []string{"wordpress", "config-changed"},
+		errMatch: `invalid unit name "wordpress"`,
+	}, {
+		args:     []string{"wordpress/0", "config-changed", "start"},
+		errMatch: `unrecognized args: \["start"\]`,
+	}, {
+		args:     []string{"--remote-unit", "mysql/0", "wordpress/0", "db-relation-changed"},
+		errMatch: "You cannot specify --remote-unit without --relation",
+	}, {
+		args:     []string{"--relation", "db:3", "--remote-unit", "mysql", "wordpress/0", "db-relation-changed"},
+		errMatch: `invalid remote unit name "mysql"`,
+	}, {
+		args: []string{"wordpress/0", "config-changed"},
+		unit: "wordpress/0",
+		hook: "config-changed",
+	}, {
+		args:       []string{"--relation", "db:3", "--remote-unit", "mysql/0", "wordpress/0", "db-relation-changed"},
+		unit:       "wordpress/0",
+		hook:       "db-relation-changed",
+		relationId: "db:3",
+		remoteUnit: "mysql/0",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		runHookCmd := &RunHookCommand{}
+		testing.TestInit(c, envcmd.Wrap(runHookCmd), test.args, test.errMatch)
+		if test.errMatch == "" {
+			c.Check(runHookCmd.unit, gc.Equals, test.unit)
+			c.Check(runHookCmd.hook, gc.Equals, test.hook)
+			c.Check(runHookCmd.relationId, gc.Equals, test.relationId)
+			c.Check(runHookCmd.remoteUnitName, gc.Equals, test.remoteUnit)
+		}
+	}
+}
+
+func (s *RunHookSuite) setupMockAPI() *mockRunHookAPI {
+	mock := &mockRunHookAPI{}
+	s.PatchValue(&getRunHookAPIClient, func(_ *RunHookCommand) (RunHookClient, error) {
+		return mock, nil
+	})
+	return mock
+}
+
+func (s *RunHookSuite) TestRunHook(c *gc.C) {
+	mock := s.setupMockAPI()
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RunHookCommand{}),
+		"--relation", "db:3", "wordpress/0", "db-relation-changed",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(mock.params, jc.DeepEquals, params.RunHookParams{
+		Unit:       "wordpress/0",
+		Hook:       "db-relation-changed",
+		RelationId: "db:3",
+		Timeout:    5 * time.Minute,
+	})
+	c.Check(testing.Stderr(ctx), gc.Equals, "Queued db-relation-changed hook on unit wordpress/0.\n")
+}
+
+func (s *RunHookSuite) TestRunHookFailure(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.result = params.RunResult{
+		ExecResponse: exec.ExecResponse{
+			Code:   1,
+			Stderr: []byte("error: cannot run \"install\" hook on request\n"),
+		},
+	}
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&RunHookCommand{}), "wordpress/0", "install")
+	c.Check(cmd.IsRcPassthroughError(err), jc.IsTrue)
+	c.Check(testing.Stderr(ctx), gc.Equals, "error: cannot run \"install\" hook on request\n")
+
+	mock.result = params.RunResult{Error: "command timed out"}
+	_, err = testing.RunCommand(c, envcmd.Wrap(&RunHookCommand{}), "wordpress/0", "config-changed")
+	c.Check(err, gc.ErrorMatches, "command timed out")
+}
+
+func (s *RunHookSuite) TestBlockRunHook(c *gc.C) {
+	mock := s.setupMockAPI()
+	mock.block = true
+	_, err := testing.RunCommand(c, envcmd.Wrap(&RunHookCommand{}), "wordpress/0", "config-changed")
+	c.Assert(err, gc.ErrorMatches, cmd.ErrSilent.Error())
+	// msg is logged
+	stripped := strings.Replace(c.GetTestLog(), "\n", "", -1)
+	c.Check(stripped, gc.Matches, ".*To unblock changes.*")
+}
+
+type mockRunHookAPI struct {
+	params params.RunHookParams
+	result params.RunResult
+	block  bool
+}
+
+var _ RunHookClient = (*mockRunHookAPI)(nil)
+
+func (*mockRunHookAPI) Close() error {
+	return nil
+}
+
+func (m *mockRunHookAPI) RunHook(run params.RunHookParams) (params.RunResult, error) {
+	if m.block {
+		return params.RunResult{}, common.ErrOperationBlocked("The operation has been blocked.")
+	}
+	m.params = run
+	return m.result, nil
+}
@@ -0,0 +1,132 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+// ShowRelationCommand shows the units in scope in a relation and their
+// settings in it.
+type ShowRelationCommand struct {
+	envcmd.EnvCommandBase
+	out        cmd.Output
+	RelationId int
+	Endpoints  []string
+}
+
+var jujuShowRelationHelp = `
+Shows the units in scope in a relation, identified either by its id (as
+given to hooks in JUJU_RELATION_ID, without the relation name) or by the
+services it relates. For each unit, the settings it has set on the
+relation are shown, along with the time they last changed. Units that
+are preparing to leave the relation are marked as departing.
+
+Examples:
+
+    juju show-relation wordpress mysql
+    juju show-relation wordpress:db mysql:server
+    juju show-relation 3
+`
+
+func (c *ShowRelationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-relation",
+		Args:    "<relation id> | <service1>[:<relation name1>] <service2>[:<relation name2>]",
+		Purpose: "show the units in a relation and their relation settings",
+		Doc:     jujuShowRelationHelp,
+	}
+}
+
+func (c *ShowRelationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "yaml", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+	})
+}
+
+func (c *ShowRelationCommand) Init(args []string) error {
+	switch len(args) {
+	case 1:
+		id, err := strconv.Atoi(args[0])
+		if err != nil || id < 0 {
+			return fmt.Errorf("invalid relation id %q", args[0])
+		}
+		c.RelationId = id
+	case 2:
+		c.Endpoints = args
+	default:
+		return fmt.Errorf("a relation must be given by its id or two services")
+	}
+	return nil
+}
+
+func (c *ShowRelationCommand) Run(ctx *cmd.Context) error {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+	var details params.RelationDetails
+	if len(c.Endpoints) == 0 {
+		details, err = client.ShowRelationById(c.RelationId)
+	} else {
+		details, err = client.ShowRelation(c.Endpoints...)
+	}
+	if err != nil {
+		return err
+	}
+	return c.out.Write(ctx, formatRelationDetails(details))
+}
+
+// relationInfo defines the serialization behaviour of a relation.
+type relationInfo struct {
+	Id        int                         `yaml:"id" json:"id"`
+	Key       string                      `yaml:"key" json:"key"`
+	Life      string                      `yaml:"life" json:"life"`
+	Endpoints map[string]string           `yaml:"endpoints" json:"endpoints"`
+	Units     map[string]relationUnitInfo `yaml:"units,omitempty" json:"units,omitempty"`
+}
+
+// relationUnitInfo defines the serialization behaviour of a unit in a
+// relation.
+type relationUnitInfo struct {
+	Departing       bool                   `yaml:"departing,omitempty" json:"departing,omitempty"`
+	SettingsChanged string                 `yaml:"settings-changed,omitempty" json:"settings-changed,omitempty"`
+	Settings        map[string]interface{} `yaml:"settings" json:"settings"`
+}
+
+func formatRelationDetails(details params.RelationDetails) relationInfo {
+	info := relationInfo{
+		Id:        details.Id,
+		Key:       details.Key,
+		Life:      string(details.Life),
+		Endpoints: make(map[string]string),
+	}
+	for serviceName, rel := range details.Endpoints {
+		info.Endpoints[serviceName] = rel.Name
+	}
+	if len(details.Units) > 0 {
+		info.Units = make(map[string]relationUnitInfo)
+	}
+	for _, unit := range details.Units {
+		unitInfo := relationUnitInfo{
+			Departing: unit.Departing,
+			Settings:  unit.Settings,
+		}
+		if unit.SettingsChanged != nil {
+			unitInfo.SettingsChanged = unit.SettingsChanged.UTC().Format(time.RFC3339)
+		}
+		info.Units[unit.UnitName] = unitInfo
+	}
+	return info
+}
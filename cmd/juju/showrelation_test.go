@@ -0,0 +1,103 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package main
+
+import (
+	"strconv"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	jujutesting "github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testing"
+)
+
+type ShowRelationSuite struct {
+	jujutesting.JujuConnSuite
+}
+
+var _ = gc.Suite(&ShowRelationSuite{})
+
+func runShowRelation(c *gc.C, args ...string) (map[string]interface{}, error) {
+	ctx, err := testing.RunCommand(c, envcmd.Wrap(&ShowRelationCommand{}), args...)
+	if err != nil {
+		return nil, err
+	}
+	var out map[string]interface{}
+	err = goyaml.Unmarshal([]byte(testing.Stdout(ctx)), &out)
+	c.Assert(err, jc.ErrorIsNil)
+	return out, nil
+}
+
+func (s *ShowRelationSuite) TestInit(c *gc.C) {
+	for i, t := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "a relation must be given by its id or two services",
+	}, {
+		args: []string{"a", "b", "c"},
+		err:  "a relation must be given by its id or two services",
+	}, {
+		args: []string{"wordpress"},
+		err:  `invalid relation id "wordpress"`,
+	}, {
+		args: []string{"-1"},
+		err:  `invalid relation id "-1"`,
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		err := testing.InitCommand(&ShowRelationCommand{}, t.args)
+		c.Check(err, gc.ErrorMatches, t.err)
+	}
+}
+
+func (s *ShowRelationSuite) TestShowRelation(c *gc.C) {
+	s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	s.enterScope(c, rel, mysql, map[string]interface{}{"password": "secret"})
+
+	out, err := runShowRelation(c, "wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	units := out["units"].(map[interface{}]interface{})
+	unit := units["mysql/0"].(map[interface{}]interface{})
+	c.Assert(unit["settings-changed"], gc.NotNil)
+	delete(unit, "settings-changed")
+	c.Assert(out, jc.DeepEquals, map[string]interface{}{
+		"id":   rel.Id(),
+		"key":  "wordpress:db mysql:server",
+		"life": "alive",
+		"endpoints": map[interface{}]interface{}{
+			"mysql":     "server",
+			"wordpress": "db",
+		},
+		"units": map[interface{}]interface{}{
+			"mysql/0": map[interface{}]interface{}{
+				"settings": map[interface{}]interface{}{"password": "secret"},
+			},
+		},
+	})
+
+	byId, err := runShowRelation(c, strconv.Itoa(rel.Id()))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(byId["key"], gc.Equals, "wordpress:db mysql:server")
+
+	_, err = runShowRelation(c, "42")
+	c.Assert(err, gc.ErrorMatches, "relation 42 not found")
+}
+
+func (s *ShowRelationSuite) enterScope(c *gc.C, rel *state.Relation, svc *state.Service, settings map[string]interface{}) {
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(settings)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,74 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+const createCommandDoc = `
+Creates a new space with the given name, containing the given subnets.
+Subnets are identified by their CIDR, and must already be known to Juju
+(see "juju subnet add"). A subnet can only be in one space.
+
+Examples:
+   juju space create internal 10.0.1.0/24 10.0.2.0/24
+   juju space create public
+`
+
+// CreateCommand creates a new space.
+type CreateCommand struct {
+	SpaceCommandBase
+	Name  string
+	CIDRs []string
+}
+
+// Info implements Command.Info.
+func (c *CreateCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create",
+		Args:    "<name> [<CIDR> ...]",
+		Purpose: "create a new space",
+		Doc:     createCommandDoc,
+	}
+}
+
+// Init implements Command.Init.
+func (c *CreateCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("space name is required")
+	}
+	if !network.IsValidSpace(args[0]) {
+		return errors.Errorf("%q is not a valid space name", args[0])
+	}
+	c.Name = args[0]
+	for _, cidr := range args[1:] {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return errors.Errorf("%q is not a valid CIDR", cidr)
+		}
+	}
+	c.CIDRs = args[1:]
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *CreateCommand) Run(ctx *cmd.Context) error {
+	api, err := getSpaceAPI(&c.SpaceCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	if err := api.CreateSpace(c.Name, c.CIDRs); err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("created space %q", c.Name)
+	return nil
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type CreateSuite struct {
+	SubSpaceSuite
+}
+
+var _ = gc.Suite(&CreateSuite{})
+
+func runCreate(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&space.CreateCommand{}), args...)
+}
+
+func (s *CreateSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "space name is required",
+	}, {
+		args: []string{"Bad_Name"},
+		err:  `"Bad_Name" is not a valid space name`,
+	}, {
+		args: []string{"internal", "10.0.0.0"},
+		err:  `"10.0.0.0" is not a valid CIDR`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runCreate(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *CreateSuite) TestCreate(c *gc.C) {
+	ctx, err := runCreate(c, "internal", "10.0.0.0/24", "10.0.1.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "created space \"internal\"\n")
+	c.Assert(s.mockAPI.created, jc.DeepEquals, map[string][]string{
+		"internal": {"10.0.0.0/24", "10.0.1.0/24"},
+	})
+}
+
+func (s *CreateSuite) TestCreateError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := runCreate(c, "internal")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
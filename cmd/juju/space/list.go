@@ -0,0 +1,67 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const listCommandDoc = `
+Lists the spaces in the environment, along with the subnets in each.
+`
+
+// ListCommand lists the spaces in the environment.
+type ListCommand struct {
+	SpaceCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list spaces",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SpaceCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// SpaceInfo defines the serialization behaviour of a space.
+type SpaceInfo struct {
+	Name    string   `yaml:"name" json:"name"`
+	Subnets []string `yaml:"subnets,omitempty" json:"subnets,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	api, err := getSpaceAPI(&c.SpaceCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	spaces, err := api.ListSpaces()
+	if err != nil {
+		return err
+	}
+	output := make([]SpaceInfo, len(spaces))
+	for i, space := range spaces {
+		output[i] = SpaceInfo{
+			Name:    space.Name,
+			Subnets: space.SubnetCIDRs,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
@@ -0,0 +1,59 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	SubSpaceSuite
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.SubSpaceSuite.SetUpTest(c)
+	s.mockAPI.spaces = []params.Space{{
+		Name:        "internal",
+		SubnetCIDRs: []string{"10.0.0.0/24"},
+	}, {
+		Name: "public",
+	}}
+}
+
+func runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&space.ListCommand{}), args...)
+}
+
+func (s *ListSuite) TestList(c *gc.C) {
+	ctx, err := runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- name: internal
+  subnets:
+  - 10.0.0.0/24
+- name: public
+`[1:])
+}
+
+func (s *ListSuite) TestListJSON(c *gc.C) {
+	ctx, err := runList(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`[{"name":"internal","subnets":["10.0.0.0/24"]},{"name":"public"}]`+"\n",
+	)
+}
+
+func (s *ListSuite) TestListUnexpectedArgs(c *gc.C) {
+	_, err := runList(c, "extra")
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["extra"\]`)
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space_test
+
+import (
+	"os"
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/space"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/testing"
+)
+
+func TestAll(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type BaseSpaceSuite struct {
+	jujutesting.FakeJujuHomeSuite
+
+	command *space.Command
+}
+
+func (s *BaseSpaceSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	s.command = space.NewSuperCommand().(*space.Command)
+}
+
+type SubSpaceSuite struct {
+	jujutesting.BaseSuite
+	mockAPI *mockSpaceAPI
+}
+
+func (s *SubSpaceSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	memstore := configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return memstore, nil
+	})
+	os.Setenv(osenv.JujuEnvEnvKey, "testing")
+	info := memstore.CreateInfo("testing")
+	info.SetBootstrapConfig(map[string]interface{}{"random": "extra data"})
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"127.0.0.1:12345"},
+		Hostnames:   []string{"localhost:12345"},
+		CACert:      jujutesting.CACert,
+		EnvironUUID: "env-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.mockAPI = &mockSpaceAPI{}
+	s.PatchValue(space.GetSpaceAPI, func(*space.SpaceCommandBase) (space.SpaceAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+type mockSpaceAPI struct {
+	created map[string][]string
+	spaces  []params.Space
+	err     error
+}
+
+func (m *mockSpaceAPI) Close() error {
+	return nil
+}
+
+func (m *mockSpaceAPI) CreateSpace(name string, subnetCIDRs []string) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.created == nil {
+		m.created = make(map[string][]string)
+	}
+	m.created[name] = subnetCIDRs
+	return nil
+}
+
+func (m *mockSpaceAPI) ListSpaces() ([]params.Space, error) {
+	return m.spaces, m.err
+}
@@ -0,0 +1,69 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package space
+
+import (
+	"github.com/juju/cmd"
+
+	"github.com/juju/juju/api/spaces"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+)
+
+const spaceCmdDoc = `
+"juju space" is used to manage the spaces in the Juju environment.
+
+A space is a named group of subnets. Service endpoints can be bound to
+spaces when deploying, so that the address a unit gives to the other
+side of a relation is taken from the space that the relation's endpoint
+is bound to.
+`
+
+const spaceCmdPurpose = "manage network spaces"
+
+// Command is the top-level command wrapping all space functionality.
+type Command struct {
+	cmd.SuperCommand
+}
+
+// NewSuperCommand creates the space supercommand and registers the
+// subcommands that it supports.
+func NewSuperCommand() cmd.Command {
+	spacecmd := Command{
+		SuperCommand: *cmd.NewSuperCommand(
+			cmd.SuperCommandParams{
+				Name:        "space",
+				Doc:         spaceCmdDoc,
+				UsagePrefix: "juju",
+				Purpose:     spaceCmdPurpose,
+			})}
+	spacecmd.Register(envcmd.Wrap(&CreateCommand{}))
+	spacecmd.Register(envcmd.Wrap(&ListCommand{}))
+	return &spacecmd
+}
+
+// SpaceAPI defines the API methods that the space commands use.
+type SpaceAPI interface {
+	Close() error
+	CreateSpace(name string, subnetCIDRs []string) error
+	ListSpaces() ([]params.Space, error)
+}
+
+// SpaceCommandBase is a helper base structure that has a method to
+// get the space managing client.
+type SpaceCommandBase struct {
+	envcmd.EnvCommandBase
+}
+
+var getSpaceAPI = (*SpaceCommandBase).newSpaceAPI
+
+// newSpaceAPI returns a spaces api for the root api endpoint that the
+// environment command returns.
+func (c *SpaceCommandBase) newSpaceAPI() (SpaceAPI, error) {
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, err
+	}
+	return spaces.NewClient(root), nil
+}
@@ -6,6 +6,9 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"os/signal"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
@@ -42,8 +45,13 @@ There are a number of ways to format the status output:
            - Services: NAME, EXPOSED, CHARM
            - Units: ID, STATE, VERSION, MACHINE, PORTS, PUBLIC-ADDRESS
              - Also displays subordinate units.
+             - The leader unit of each service is marked with a '*'.
 - yaml (DEFAULT): Displays information on machines, services, and units
-                  in the yaml format.
+                  in the yaml format. The leader unit of each service
+                  is marked with "leader: true". Services whose charm
+                  has been upgraded list the charms they have run,
+                  oldest first, under "charm-history"; the progress
+                  of a batched upgrade is shown under "charm-upgrade".
 
 Service or unit names may be specified to filter the status to only those
 services and units that match, along with the related machines, services
@@ -91,7 +99,7 @@ Error details:
 `
 
 type statusAPI interface {
-	Status(patterns []string) (*api.Status, error)
+	StatusWithCancel(patterns []string, cancel <-chan struct{}) (*api.Status, error)
 	Close() error
 }
 
@@ -99,6 +107,13 @@ var newApiClientForStatus = func(c *StatusCommand) (statusAPI, error) {
 	return c.NewAPIClient()
 }
 
+// notifyInterrupt and stopInterrupt start and stop the relaying of
+// interrupts to the status command; they are patched out by tests.
+var (
+	notifyInterrupt = func(c chan<- os.Signal) { signal.Notify(c, os.Interrupt) }
+	stopInterrupt   = func(c chan<- os.Signal) { signal.Stop(c) }
+)
+
 func (c *StatusCommand) Run(ctx *cmd.Context) error {
 
 	apiclient, err := newApiClientForStatus(c)
@@ -107,7 +122,23 @@ func (c *StatusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer apiclient.Close()
 
-	status, err := apiclient.Status(c.patterns)
+	// Abandon the request, rather than leave the API server to
+	// gather a status nobody will read, if the user interrupts it.
+	interrupted := make(chan os.Signal, 1)
+	notifyInterrupt(interrupted)
+	defer stopInterrupt(interrupted)
+	cancel := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-interrupted:
+			close(cancel)
+		case <-done:
+		}
+	}()
+
+	status, err := apiclient.StatusWithCancel(c.patterns, cancel)
 	if err != nil {
 		if status == nil {
 			// Status call completely failed, there is nothing to report
@@ -182,6 +213,22 @@ type serviceStatus struct {
 	Networks      map[string][]string   `json:"networks,omitempty" yaml:"networks,omitempty"`
 	SubordinateTo []string              `json:"subordinate-to,omitempty" yaml:"subordinate-to,omitempty"`
 	Units         map[string]unitStatus `json:"units,omitempty" yaml:"units,omitempty"`
+	CharmHistory  []charmHistoryEntry   `json:"charm-history,omitempty" yaml:"charm-history,omitempty"`
+	CharmUpgrade  *charmUpgradeStatus   `json:"charm-upgrade,omitempty" yaml:"charm-upgrade,omitempty"`
+}
+
+type charmUpgradeStatus struct {
+	From         string   `json:"from" yaml:"from"`
+	Upgrading    []string `json:"upgrading,omitempty" yaml:"upgrading,omitempty"`
+	Waiting      []string `json:"waiting,omitempty" yaml:"waiting,omitempty"`
+	Paused       bool     `json:"paused,omitempty" yaml:"paused,omitempty"`
+	PausedReason string   `json:"paused-reason,omitempty" yaml:"paused-reason,omitempty"`
+}
+
+type charmHistoryEntry struct {
+	Charm string `json:"charm" yaml:"charm"`
+	Time  string `json:"time,omitempty" yaml:"time,omitempty"`
+	User  string `json:"user,omitempty" yaml:"user,omitempty"`
 }
 
 type serviceStatusNoMarshal serviceStatus
@@ -212,6 +259,7 @@ type unitStatus struct {
 	Machine        string                `json:"machine,omitempty" yaml:"machine,omitempty"`
 	OpenedPorts    []string              `json:"open-ports,omitempty" yaml:"open-ports,omitempty"`
 	PublicAddress  string                `json:"public-address,omitempty" yaml:"public-address,omitempty"`
+	Leader         bool                  `json:"leader,omitempty" yaml:"leader,omitempty"`
 	Subordinates   map[string]unitStatus `json:"subordinates,omitempty" yaml:"subordinates,omitempty"`
 }
 
@@ -370,6 +418,22 @@ func (sf *statusFormatter) formatService(name string, service api.ServiceStatus)
 	for k, m := range service.Units {
 		out.Units[k] = sf.formatUnit(m, name)
 	}
+	for _, entry := range service.CharmHistory {
+		outEntry := charmHistoryEntry{Charm: entry.Charm, User: entry.User}
+		if !entry.Time.IsZero() {
+			outEntry.Time = entry.Time.UTC().Format(time.RFC3339)
+		}
+		out.CharmHistory = append(out.CharmHistory, outEntry)
+	}
+	if upgrade := service.CharmUpgrade; upgrade != nil {
+		out.CharmUpgrade = &charmUpgradeStatus{
+			From:         upgrade.From,
+			Upgrading:    upgrade.Batch,
+			Waiting:      upgrade.Waiting,
+			Paused:       upgrade.Paused,
+			PausedReason: upgrade.PausedReason,
+		}
+	}
 	return out
 }
 
@@ -384,6 +448,7 @@ func (sf *statusFormatter) formatUnit(unit api.UnitStatus, serviceName string) u
 		OpenedPorts:    unit.OpenedPorts,
 		PublicAddress:  unit.PublicAddress,
 		Charm:          unit.Charm,
+		Leader:         unit.Leader,
 		Subordinates:   make(map[string]unitStatus),
 	}
 	for k, m := range unit.Subordinates {
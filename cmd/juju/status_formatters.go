@@ -93,6 +93,9 @@ func FormatTabular(value interface{}) ([]byte, error) {
 	tw.Flush()
 
 	pUnit := func(name string, u unitStatus, level int) {
+		if u.Leader {
+			name += "*"
+		}
 		p(
 			indent("", level*2, name),
 			u.AgentState,
@@ -7,7 +7,9 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"os"
 	"strings"
+	"time"
 
 	"github.com/juju/cmd"
 	jc "github.com/juju/testing/checkers"
@@ -21,7 +23,9 @@ import (
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/lease"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/rpc"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/presence"
@@ -1549,6 +1553,10 @@ var statusTests = []testCase{
 								"public-address": "dummyenv-1.dns",
 							},
 						},
+						"charm-history": L{
+							M{"charm": "cs:quantal/mysql-1", "user": "admin@local"},
+							M{"charm": "local:quantal/mysql-1"},
+						},
 					},
 				},
 			},
@@ -1593,6 +1601,10 @@ var statusTests = []testCase{
 								"public-address": "dummyenv-1.dns",
 							},
 						},
+						"charm-history": L{
+							M{"charm": "cs:quantal/mysql-1", "user": "admin@local"},
+							M{"charm": "cs:quantal/mysql-2"},
+						},
 					},
 				},
 			},
@@ -1636,6 +1648,10 @@ var statusTests = []testCase{
 								"public-address": "dummyenv-1.dns",
 							},
 						},
+						"charm-history": L{
+							M{"charm": "cs:quantal/mysql-1", "user": "admin@local"},
+							M{"charm": "local:quantal/mysql-1"},
+						},
 					},
 				},
 			},
@@ -1916,6 +1932,22 @@ func (sus setUnitStatus) step(c *gc.C, ctx *context) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+type setServiceLeader struct {
+	serviceName string
+	unitName    string
+}
+
+func (ssl setServiceLeader) step(c *gc.C, ctx *context) {
+	// The lease manager isn't running, so record the leadership lease
+	// directly.
+	err := ctx.st.WriteToken(nil, lease.Token{
+		Namespace:  ssl.serviceName + "-leadership",
+		Id:         ssl.unitName,
+		Expiration: time.Now().Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type setUnitCharmURL struct {
 	unitName string
 	charm    string
@@ -2061,10 +2093,50 @@ func (e scopedExpect) step(c *gc.C, ctx *context) {
 		actual := make(M)
 		err = format.unmarshal(stdout, &actual)
 		c.Assert(err, jc.ErrorIsNil)
+		stripCharmHistoryTimes(c, actual)
 		c.Assert(actual, jc.DeepEquals, expected)
 	}
 }
 
+// stripCharmHistoryTimes removes the times recorded in the charm
+// history of services from the unmarshalled status output v, after
+// checking they are valid; they depend on when the test runs.
+func stripCharmHistoryTimes(c *gc.C, v interface{}) {
+	visit := func(key, value interface{}) {
+		if key != "charm-history" {
+			stripCharmHistoryTimes(c, value)
+			return
+		}
+		for _, entry := range value.([]interface{}) {
+			var t interface{}
+			switch entry := entry.(type) {
+			case map[string]interface{}:
+				t = entry["time"]
+				delete(entry, "time")
+			case map[interface{}]interface{}:
+				t = entry["time"]
+				delete(entry, "time")
+			}
+			_, err := time.Parse(time.RFC3339, fmt.Sprint(t))
+			c.Check(err, jc.ErrorIsNil)
+		}
+	}
+	switch v := v.(type) {
+	case M:
+		for key, value := range v {
+			visit(key, value)
+		}
+	case map[string]interface{}:
+		for key, value := range v {
+			visit(key, value)
+		}
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			visit(key, value)
+		}
+	}
+}
+
 func (e expect) step(c *gc.C, ctx *context) {
 	scopedExpect{e.what, nil, e.output}.step(c, ctx)
 }
@@ -2093,7 +2165,7 @@ func newFakeApiClient(statusReturn *api.Status) fakeApiClient {
 	}
 }
 
-func (a *fakeApiClient) Status(patterns []string) (*api.Status, error) {
+func (a *fakeApiClient) StatusWithCancel(patterns []string, cancel <-chan struct{}) (*api.Status, error) {
 	a.patternsUsed = patterns
 	return a.statusReturn, nil
 }
@@ -2398,6 +2470,7 @@ func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 		setUnitsAlive{"logging"},
 		setUnitStatus{"logging/0", state.StatusActive, "", nil},
 		setUnitStatus{"logging/1", state.StatusError, "somehow lost in all those logs", nil},
+		setServiceLeader{"mysql", "mysql/0"},
 	}
 	for _, s := range steps {
 		s.step(c, ctx)
@@ -2422,7 +2495,7 @@ func (s *StatusSuite) TestStatusWithFormatTabular(c *gc.C) {
 			"\n"+
 			"[Units]     \n"+
 			"ID          STATE   VERSION MACHINE PORTS PUBLIC-ADDRESS \n"+
-			"mysql/0     started         2             dummyenv-2.dns \n"+
+			"mysql/0*    started         2             dummyenv-2.dns \n"+
 			"  logging/1 error                         dummyenv-2.dns \n"+
 			"wordpress/0 started         1             dummyenv-1.dns \n"+
 			"  logging/0 started                       dummyenv-1.dns \n"+
@@ -2445,8 +2518,8 @@ func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	}
 
 	client := fakeApiClient{}
-	var status = client.Status
-	s.PatchValue(&status, func(_ []string) (*api.Status, error) {
+	var status = client.StatusWithCancel
+	s.PatchValue(&status, func(_ []string, _ <-chan struct{}) (*api.Status, error) {
 		return nil, nil
 	})
 	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
@@ -2458,6 +2531,37 @@ func (s *StatusSuite) TestStatusWithNilStatusApi(c *gc.C) {
 	c.Check(string(stderr), gc.Equals, "error: unable to obtain the current status\n")
 }
 
+// interruptedApiClient is a statusAPI whose requests
+// only complete when they are cancelled.
+type interruptedApiClient struct {
+	fakeApiClient
+}
+
+func (a *interruptedApiClient) StatusWithCancel(patterns []string, cancel <-chan struct{}) (*api.Status, error) {
+	select {
+	case <-cancel:
+		return nil, rpc.ErrCancelled
+	case <-time.After(coretesting.LongWait):
+		return nil, fmt.Errorf("status request not cancelled")
+	}
+}
+
+func (s *StatusSuite) TestStatusInterrupted(c *gc.C) {
+	s.PatchValue(&notifyInterrupt, func(ch chan<- os.Signal) {
+		ch <- os.Interrupt
+	})
+	s.PatchValue(&stopInterrupt, func(chan<- os.Signal) {})
+	client := &interruptedApiClient{}
+	s.PatchValue(&newApiClientForStatus, func(_ *StatusCommand) (statusAPI, error) {
+		return client, nil
+	})
+
+	code, _, stderr := runStatus(c)
+	c.Check(code, gc.Equals, 1)
+	c.Check(string(stderr), gc.Equals, "error: call cancelled\n")
+	c.Check(client.closeCalled, jc.IsTrue)
+}
+
 //
 // Filtering Feature
 //
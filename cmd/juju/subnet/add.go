@@ -0,0 +1,100 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+import (
+	"net"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/block"
+	"github.com/juju/juju/network"
+)
+
+const addCommandDoc = `
+Adds a subnet with the given CIDR to the environment, optionally
+placing it in an existing space.
+
+Examples:
+   juju subnet add 10.0.1.0/24
+   juju subnet add 10.0.1.0/24 internal --zone us-east-1a
+`
+
+// AddCommand adds a subnet to the environment.
+type AddCommand struct {
+	SubnetCommandBase
+	CIDR       string
+	Space      string
+	ProviderId string
+	VLANTag    int
+	Zone       string
+}
+
+// Info implements Command.Info.
+func (c *AddCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "add",
+		Args:    "<CIDR> [<space>]",
+		Purpose: "add a subnet",
+		Doc:     addCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *AddCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SubnetCommandBase.SetFlags(f)
+	f.StringVar(&c.ProviderId, "provider-id", "", "the provider's identifier for the subnet")
+	f.IntVar(&c.VLANTag, "vlan-tag", 0, "the VLAN tag of the subnet")
+	f.StringVar(&c.Zone, "zone", "", "the availability zone of the subnet")
+}
+
+// Init implements Command.Init.
+func (c *AddCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("subnet CIDR is required")
+	case 1, 2:
+	default:
+		return cmd.CheckEmpty(args[2:])
+	}
+	if _, _, err := net.ParseCIDR(args[0]); err != nil {
+		return errors.Errorf("%q is not a valid CIDR", args[0])
+	}
+	c.CIDR = args[0]
+	if len(args) == 2 {
+		if !network.IsValidSpace(args[1]) {
+			return errors.Errorf("%q is not a valid space name", args[1])
+		}
+		c.Space = args[1]
+	}
+	if c.VLANTag < 0 || c.VLANTag > 4094 {
+		return errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", c.VLANTag)
+	}
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *AddCommand) Run(ctx *cmd.Context) error {
+	api, err := getSubnetAPI(&c.SubnetCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	err = api.AddSubnet(params.Subnet{
+		CIDR:             c.CIDR,
+		ProviderId:       c.ProviderId,
+		VLANTag:          c.VLANTag,
+		AvailabilityZone: c.Zone,
+		SpaceName:        c.Space,
+	})
+	if err != nil {
+		return block.ProcessBlockedError(err, block.BlockChange)
+	}
+	ctx.Infof("added subnet %q", c.CIDR)
+	return nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/subnet"
+	"github.com/juju/juju/testing"
+)
+
+type AddSuite struct {
+	SubSubnetSuite
+}
+
+var _ = gc.Suite(&AddSuite{})
+
+func runAdd(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&subnet.AddCommand{}), args...)
+}
+
+func (s *AddSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		err: "subnet CIDR is required",
+	}, {
+		args: []string{"10.0.0.0"},
+		err:  `"10.0.0.0" is not a valid CIDR`,
+	}, {
+		args: []string{"10.0.0.0/24", "Bad_Name"},
+		err:  `"Bad_Name" is not a valid space name`,
+	}, {
+		args: []string{"10.0.0.0/24", "internal", "extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"10.0.0.0/24", "--vlan-tag", "4095"},
+		err:  "invalid VLAN tag 4095: must be between 0 and 4094",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		_, err := runAdd(c, test.args...)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AddSuite) TestAdd(c *gc.C) {
+	ctx, err := runAdd(c,
+		"10.0.0.0/24", "internal",
+		"--provider-id", "subnet-1", "--vlan-tag", "42", "--zone", "zone1",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "added subnet \"10.0.0.0/24\"\n")
+	c.Assert(s.mockAPI.added, jc.DeepEquals, []params.Subnet{{
+		CIDR:             "10.0.0.0/24",
+		ProviderId:       "subnet-1",
+		VLANTag:          42,
+		AvailabilityZone: "zone1",
+		SpaceName:        "internal",
+	}})
+}
+
+func (s *AddSuite) TestAddError(c *gc.C) {
+	s.mockAPI.err = errors.New("boom")
+	_, err := runAdd(c, "10.0.0.0/24")
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
@@ -0,0 +1,73 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet
+
+import (
+	"github.com/juju/cmd"
+	"launchpad.net/gnuflag"
+)
+
+const listCommandDoc = `
+Lists the subnets known to the environment.
+`
+
+// ListCommand lists the subnets in the environment.
+type ListCommand struct {
+	SubnetCommandBase
+	out cmd.Output
+}
+
+// Info implements Command.Info.
+func (c *ListCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "list",
+		Purpose: "list subnets",
+		Doc:     listCommandDoc,
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *ListCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.SubnetCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", cmd.DefaultFormatters)
+}
+
+// Init implements Command.Init.
+func (c *ListCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+// SubnetInfo defines the serialization behaviour of a subnet.
+type SubnetInfo struct {
+	CIDR       string `yaml:"cidr" json:"cidr"`
+	ProviderId string `yaml:"provider-id,omitempty" json:"provider-id,omitempty"`
+	VLANTag    int    `yaml:"vlan-tag,omitempty" json:"vlan-tag,omitempty"`
+	Zone       string `yaml:"zone,omitempty" json:"zone,omitempty"`
+	Space      string `yaml:"space,omitempty" json:"space,omitempty"`
+}
+
+// Run implements Command.Run.
+func (c *ListCommand) Run(ctx *cmd.Context) error {
+	api, err := getSubnetAPI(&c.SubnetCommandBase)
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	subnets, err := api.ListSubnets()
+	if err != nil {
+		return err
+	}
+	output := make([]SubnetInfo, len(subnets))
+	for i, subnet := range subnets {
+		output[i] = SubnetInfo{
+			CIDR:       subnet.CIDR,
+			ProviderId: subnet.ProviderId,
+			VLANTag:    subnet.VLANTag,
+			Zone:       subnet.AvailabilityZone,
+			Space:      subnet.SpaceName,
+		}
+	}
+	return c.out.Write(ctx, output)
+}
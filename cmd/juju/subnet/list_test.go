@@ -0,0 +1,55 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet_test
+
+import (
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/envcmd"
+	"github.com/juju/juju/cmd/juju/subnet"
+	"github.com/juju/juju/testing"
+)
+
+type ListSuite struct {
+	SubSubnetSuite
+}
+
+var _ = gc.Suite(&ListSuite{})
+
+func (s *ListSuite) SetUpTest(c *gc.C) {
+	s.SubSubnetSuite.SetUpTest(c)
+	s.mockAPI.subnets = []params.Subnet{{
+		CIDR:             "10.0.0.0/24",
+		AvailabilityZone: "zone1",
+		SpaceName:        "internal",
+	}, {
+		CIDR: "10.0.1.0/24",
+	}}
+}
+
+func runList(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, envcmd.Wrap(&subnet.ListCommand{}), args...)
+}
+
+func (s *ListSuite) TestList(c *gc.C) {
+	ctx, err := runList(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `
+- cidr: 10.0.0.0/24
+  zone: zone1
+  space: internal
+- cidr: 10.0.1.0/24
+`[1:])
+}
+
+func (s *ListSuite) TestListJSON(c *gc.C) {
+	ctx, err := runList(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals,
+		`[{"cidr":"10.0.0.0/24","zone":"zone1","space":"internal"},{"cidr":"10.0.1.0/24"}]`+"\n",
+	)
+}
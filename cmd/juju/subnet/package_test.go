@@ -0,0 +1,90 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package subnet_test
+
+import (
+	"os"
+	"testing"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/subnet"
+	"github.com/juju/juju/environs/configstore"
+	"github.com/juju/juju/juju/osenv"
+	jujutesting "github.com/juju/juju/testing"
+)
+
+func TestAll(t *testing.T) {
+	gc.TestingT(t)
+}
+
+type BaseSubnetSuite struct {
+	jujutesting.FakeJujuHomeSuite
+
+	command *subnet.Command
+}
+
+func (s *BaseSubnetSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuHomeSuite.SetUpTest(c)
+
+	s.command = subnet.NewSuperCommand().(*subnet.Command)
+}
+
+type SubSubnetSuite struct {
+	jujutesting.BaseSuite
+	mockAPI *mockSubnetAPI
+}
+
+func (s *SubSubnetSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	memstore := configstore.NewMem()
+	s.PatchValue(&configstore.Default, func() (configstore.Storage, error) {
+		return memstore, nil
+	})
+	os.Setenv(osenv.JujuEnvEnvKey, "testing")
+	info := memstore.CreateInfo("testing")
+	info.SetBootstrapConfig(map[string]interface{}{"random": "extra data"})
+	info.SetAPIEndpoint(configstore.APIEndpoint{
+		Addresses:   []string{"127.0.0.1:12345"},
+		Hostnames:   []string{"localhost:12345"},
+		CACert:      jujutesting.CACert,
+		EnvironUUID: "env-uuid",
+	})
+	info.SetAPICredentials(configstore.APICredentials{
+		User:     "user-test",
+		Password: "password",
+	})
+	err := info.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.mockAPI = &mockSubnetAPI{}
+	s.PatchValue(subnet.GetSubnetAPI, func(*subnet.SubnetCommandBase) (subnet.SubnetAPI, error) {
+		return s.mockAPI, nil
+	})
+}
+
+type mockSubnetAPI struct {
+	added   []params.Subnet
+	subnets []params.Subnet
+	err     error
+}
+
+func (m *mockSubnetAPI) Close() error {
+	return nil
+}
+
+func (m *mockSubnetAPI) AddSubnet(subnet params.Subnet) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.added = append(m.added, subnet)
+	return nil
+}
+
+func (m *mockSubnetAPI) ListSubnets() ([]params.Subnet, error) {
+	return m.subnets, m.err
+}
@@ -6,12 +6,14 @@ package main
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/juju/cmd"
 	"github.com/juju/names"
 	"gopkg.in/juju/charm.v4"
 	"launchpad.net/gnuflag"
 
+	"github.com/juju/juju/api"
 	"github.com/juju/juju/cmd/envcmd"
 	"github.com/juju/juju/cmd/juju/block"
 	"github.com/juju/juju/environs/config"
@@ -25,6 +27,14 @@ type UpgradeCharmCommand struct {
 	RepoPath    string // defaults to JUJU_REPOSITORY
 	SwitchURL   string
 	Revision    int // defaults to -1 (latest)
+	Revert      bool
+
+	// BatchSize, if positive, upgrades the units of the service
+	// that many at a time.
+	BatchSize    int
+	PauseOnError bool
+	BatchTimeout time.Duration
+	Resume       bool
 }
 
 const upgradeCharmDoc = `
@@ -60,9 +70,26 @@ The new charm may add new relations and configuration settings.
 number with --switch, give it in the charm URL, for instance "cs:wordpress-5"
 would specify revision number 5 of the wordpress charm.
 
+The --revert flag (or its alias --to-previous) switches the service back to
+the charm it ran before its current one, as recorded in the service's charm
+history; see "juju status --format=yaml". It cannot be combined with --switch
+or --revision. Reverting again returns to the charm that was reverted.
+
+By default all units of the service are upgraded at once. The --batch-size
+flag upgrades them that many at a time instead: each batch is upgraded only
+once the units of the previous batch run the new charm with an active agent
+and a running or unknown workload status, or after --batch-timeout has
+elapsed. Batches with a unit whose workload is busy, waiting or blocked, or
+in an error state, are only released by the timeout. Units added to the service during the
+upgrade start with the new charm. With --pause-on-error, a unit of the batch
+in an error state pauses the upgrade; once the unit has been dealt with, run
+"juju upgrade-charm --resume <service>" to continue with the next batch. The
+progress of the upgrade is shown by "juju status --format=yaml".
+
 Use of the --force flag is not generally recommended; units upgraded while in an
 error state will not have upgrade-charm hooks executed, and may cause unexpected
-behavior.
+behavior. The same applies when reverting: use --force to revert units left in
+an error state by a failed upgrade.
 `
 
 func (c *UpgradeCharmCommand) Info() *cmd.Info {
@@ -79,6 +106,12 @@ func (c *UpgradeCharmCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.RepoPath, "repository", os.Getenv("JUJU_REPOSITORY"), "local charm repository path")
 	f.StringVar(&c.SwitchURL, "switch", "", "crossgrade to a different charm")
 	f.IntVar(&c.Revision, "revision", -1, "explicit revision of current charm")
+	f.BoolVar(&c.Revert, "revert", false, "revert to the previous charm of the service")
+	f.BoolVar(&c.Revert, "to-previous", false, "")
+	f.IntVar(&c.BatchSize, "batch-size", 0, "upgrade units this many at a time")
+	f.BoolVar(&c.PauseOnError, "pause-on-error", false, "pause a batched upgrade when a unit is in an error state")
+	f.DurationVar(&c.BatchTimeout, "batch-timeout", 10*time.Minute, "time to wait for a batch to become healthy")
+	f.BoolVar(&c.Resume, "resume", false, "resume a paused batched upgrade")
 }
 
 func (c *UpgradeCharmCommand) Init(args []string) error {
@@ -96,6 +129,21 @@ func (c *UpgradeCharmCommand) Init(args []string) error {
 	if c.SwitchURL != "" && c.Revision != -1 {
 		return fmt.Errorf("--switch and --revision are mutually exclusive")
 	}
+	if c.Revert && (c.SwitchURL != "" || c.Revision != -1) {
+		return fmt.Errorf("--revert cannot be used with --switch or --revision")
+	}
+	if c.BatchSize < 0 {
+		return fmt.Errorf("--batch-size must not be negative")
+	}
+	if c.BatchTimeout <= 0 {
+		return fmt.Errorf("--batch-timeout must be positive")
+	}
+	if c.PauseOnError && c.BatchSize == 0 {
+		return fmt.Errorf("--pause-on-error requires --batch-size")
+	}
+	if c.Resume && (c.SwitchURL != "" || c.Revision != -1 || c.Revert || c.BatchSize != 0) {
+		return fmt.Errorf("--resume cannot be used with other upgrade options")
+	}
 	return nil
 }
 
@@ -107,6 +155,17 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	defer client.Close()
+	if c.Resume {
+		return block.ProcessBlockedError(client.ServiceResumeCharmUpgrade(c.ServiceName), block.BlockChange)
+	}
+	if c.Revert {
+		newURL, err := client.ServiceRevertCharm(c.ServiceName, c.Force, c.BatchSize, c.PauseOnError, c.BatchTimeout)
+		if err != nil {
+			return block.ProcessBlockedError(err, block.BlockChange)
+		}
+		ctx.Infof("Reverted service %q to charm %q.", c.ServiceName, newURL)
+		return nil
+	}
 	oldURL, err := client.ServiceGetCharmURL(c.ServiceName)
 	if err != nil {
 		return err
@@ -137,6 +196,7 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return err
 	}
 	config.SpecializeCharmRepo(repo, conf)
+	repo = specializeCharmStore(repo, client, conf)
 
 	// If no explicit revision was set with either SwitchURL
 	// or Revision flags, discover the latest.
@@ -165,5 +225,14 @@ func (c *UpgradeCharmCommand) Run(ctx *cmd.Context) error {
 		return block.ProcessBlockedError(err, block.BlockChange)
 	}
 
-	return block.ProcessBlockedError(client.ServiceSetCharm(c.ServiceName, addedURL.String(), c.Force), block.BlockChange)
+	return block.ProcessBlockedError(c.setCharm(client, addedURL), block.BlockChange)
+}
+
+// setCharm sets the charm of the service, upgrading its units in
+// batches if requested.
+func (c *UpgradeCharmCommand) setCharm(client *api.Client, curl *charm.URL) error {
+	if c.BatchSize > 0 {
+		return client.ServiceSetCharmInBatches(c.ServiceName, curl.String(), c.Force, c.BatchSize, c.PauseOnError, c.BatchTimeout)
+	}
+	return client.ServiceSetCharm(c.ServiceName, curl.String(), c.Force)
 }
@@ -96,6 +96,36 @@ func (s *UpgradeCharmErrorsSuite) TestInvalidRevision(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, `invalid value "blah" for flag --revision: strconv.ParseInt: parsing "blah": invalid syntax`)
 }
 
+func (s *UpgradeCharmErrorsSuite) TestRevertAndSwitchFails(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--revert", "--switch=riak")
+	c.Assert(err, gc.ErrorMatches, "--revert cannot be used with --switch or --revision")
+	err = runUpgradeCharm(c, "riak", "--to-previous", "--revision=2")
+	c.Assert(err, gc.ErrorMatches, "--revert cannot be used with --switch or --revision")
+}
+
+func (s *UpgradeCharmErrorsSuite) TestRevertWithoutHistory(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--revert")
+	c.Assert(err, gc.ErrorMatches, `cannot revert charm of service "riak": previous charm of service "riak" not found`)
+}
+
+func (s *UpgradeCharmErrorsSuite) TestBatchFlagsFail(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--pause-on-error")
+	c.Assert(err, gc.ErrorMatches, "--pause-on-error requires --batch-size")
+	err = runUpgradeCharm(c, "riak", "--batch-size=-1")
+	c.Assert(err, gc.ErrorMatches, "--batch-size must not be negative")
+	err = runUpgradeCharm(c, "riak", "--resume", "--revert")
+	c.Assert(err, gc.ErrorMatches, "--resume cannot be used with other upgrade options")
+}
+
+func (s *UpgradeCharmErrorsSuite) TestResumeWithoutUpgrade(c *gc.C) {
+	s.deployService(c)
+	err := runUpgradeCharm(c, "riak", "--resume")
+	c.Assert(err, gc.ErrorMatches, `cannot resume charm upgrade of service "riak": charm upgrade of service "riak" not found`)
+}
+
 type UpgradeCharmSuccessSuite struct {
 	jujutesting.RepoSuite
 	CmdBlockHelper
@@ -219,6 +249,72 @@ func (s *UpgradeCharmSuccessSuite) TestBlockForcedUpgrade(c *gc.C) {
 	s.assertLocalRevision(c, 7, s.path)
 }
 
+func (s *UpgradeCharmSuccessSuite) TestRevert(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	upgradedURL := s.assertUpgraded(c, 8, false)
+
+	err = runUpgradeCharm(c, "riak", "--revert")
+	c.Assert(err, jc.ErrorIsNil)
+	revertedURL := s.assertUpgraded(c, 7, false)
+
+	history := s.riak.CharmHistory()
+	c.Assert(history, gc.HasLen, 3)
+	for i, curl := range []*charm.URL{revertedURL, upgradedURL, revertedURL} {
+		c.Check(history[i].CharmURL, gc.DeepEquals, curl)
+		c.Check(history[i].User, gc.Equals, "admin@local")
+	}
+
+	// Reverting again returns to the upgraded charm.
+	err = runUpgradeCharm(c, "riak", "--to-previous")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestForcedRevert(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+
+	err = runUpgradeCharm(c, "riak", "--revert", "--force")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 7, true)
+}
+
+func (s *UpgradeCharmSuccessSuite) TestBlockRevert(c *gc.C) {
+	err := runUpgradeCharm(c, "riak")
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Block operation
+	s.BlockAllChanges(c, "TestBlockRevert")
+	err = runUpgradeCharm(c, "riak", "--revert")
+	s.AssertBlocked(c, err, ".*TestBlockRevert.*")
+}
+
+func (s *UpgradeCharmSuccessSuite) TestUpgradeInBatches(c *gc.C) {
+	oldURL, _ := s.riak.CharmURL()
+	for i := 0; i < 3; i++ {
+		unit, err := s.riak.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(oldURL)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	err := runUpgradeCharm(c, "riak", "--batch-size=2", "--pause-on-error")
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgraded(c, 8, false)
+	upgrade, err := s.riak.CharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgrade.FromURL, gc.DeepEquals, oldURL)
+	c.Assert(upgrade.Batch, jc.DeepEquals, []string{"riak/0", "riak/1"})
+	c.Assert(upgrade.Held, jc.DeepEquals, []string{"riak/2"})
+	c.Assert(upgrade.Paused, jc.IsFalse)
+
+	// Held units are told to keep their charm.
+	curl, _ := s.riak.CharmURLForUnit("riak/2")
+	c.Assert(curl, gc.DeepEquals, oldURL)
+}
+
 var myriakMeta = []byte(`
 name: myriak
 summary: "K/V storage engine"
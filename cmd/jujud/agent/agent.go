@@ -34,6 +34,16 @@ var (
 		Total: 1 * time.Minute,
 		Delay: 5 * time.Second,
 	}
+
+	// agentDialOpts holds the options used by agents to open the
+	// API. Dialing fails immediately, but logins refused by a busy
+	// server are retried a few times with a jittered delay, so that
+	// agents reconnecting after a state server restart are spread
+	// out rather than all retrying together.
+	agentDialOpts = api.DialOpts{
+		LoginRetries:    3,
+		LoginRetryDelay: 2 * time.Second,
+	}
 )
 
 // AgentConf handles command-line flags shared by all agents.
@@ -198,7 +208,7 @@ func OpenAPIState(agentConfig agent.Config, a Agent) (_ *api.State, _ *apiagent.
 		// Reconnect to the API with the new password.
 		st.Close()
 		info.Password = newPassword
-		st, err = apiOpen(info, api.DialOpts{})
+		st, err = apiOpen(info, agentDialOpts)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -220,8 +230,8 @@ func openAPIStateUsingInfo(info *api.Info, a Agent, oldPassword string) (*api.St
 	// runner's loop outside the caller of openAPIState will
 	// keep on retrying. If we block for ages here,
 	// then the worker that's calling this cannot
-	// be interrupted.
-	st, err := apiOpen(info, api.DialOpts{})
+	// be interrupted. See agentDialOpts.
+	st, err := apiOpen(info, agentDialOpts)
 	usedOldPassword := false
 	if params.IsCodeUnauthorized(err) {
 		// We've perhaps used the wrong password, so
@@ -230,13 +240,13 @@ func openAPIStateUsingInfo(info *api.Info, a Agent, oldPassword string) (*api.St
 		info = &infoCopy
 		info.Password = oldPassword
 		usedOldPassword = true
-		st, err = apiOpen(info, api.DialOpts{})
+		st, err = apiOpen(info, agentDialOpts)
 	}
 	// The provisioner may take some time to record the agent's
 	// machine instance ID, so wait until it does so.
 	if params.IsCodeNotProvisioned(err) {
 		for a := checkProvisionedStrategy.Start(); a.Next(); {
-			st, err = apiOpen(info, api.DialOpts{})
+			st, err = apiOpen(info, agentDialOpts)
 			if !params.IsCodeNotProvisioned(err) {
 				break
 			}
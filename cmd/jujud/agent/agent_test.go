@@ -104,6 +104,19 @@ func (s *apiOpenSuite) TestOpenAPIStateWaitsProvisionedGivesUp(c *gc.C) {
 	c.Assert(called, gc.Equals, checkProvisionedStrategy.Min+1)
 }
 
+func (s *apiOpenSuite) TestOpenAPIStateRetriesBusyLogins(c *gc.C) {
+	var gotOpts api.DialOpts
+	s.PatchValue(&apiOpen, func(info *api.Info, opts api.DialOpts) (*api.State, error) {
+		gotOpts = opts
+		return nil, &params.Error{Code: params.CodeTryAgain}
+	})
+	_, _, err := OpenAPIState(fakeAPIOpenConfig{}, nil)
+	c.Assert(err, jc.Satisfies, params.IsCodeTryAgain)
+	c.Assert(gotOpts, gc.Equals, agentDialOpts)
+	c.Assert(gotOpts.LoginRetries, jc.GreaterThan, 0)
+	c.Assert(gotOpts.Timeout, gc.Equals, time.Duration(0))
+}
+
 type acCreator func() (cmd.Command, *AgentConf)
 
 // CheckAgentCommand is a utility function for verifying that common agent
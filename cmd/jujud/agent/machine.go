@@ -39,6 +39,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/feature"
@@ -62,6 +63,7 @@ import (
 	"github.com/juju/juju/worker/authenticationworker"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/charmrevisionworker"
+	"github.com/juju/juju/worker/charmupgrader"
 	"github.com/juju/juju/worker/cleaner"
 	"github.com/juju/juju/worker/deployer"
 	"github.com/juju/juju/worker/diskformatter"
@@ -79,6 +81,7 @@ import (
 	"github.com/juju/juju/worker/provisioner"
 	"github.com/juju/juju/worker/proxyupdater"
 	rebootworker "github.com/juju/juju/worker/reboot"
+	"github.com/juju/juju/worker/remoterelations"
 	"github.com/juju/juju/worker/resumer"
 	"github.com/juju/juju/worker/rsyslog"
 	"github.com/juju/juju/worker/singular"
@@ -786,6 +789,14 @@ func (a *MachineAgent) setupContainerSupport(runner worker.Runner, st *api.State
 	if err == nil && supportsKvm {
 		supportedContainers = append(supportedContainers, instance.KVM)
 	}
+
+	supportsLXD, err := lxd.IsLXDSupported()
+	if err != nil {
+		logger.Warningf("determining lxd support: %v\nno lxd containers possible", err)
+	}
+	if err == nil && supportsLXD {
+		supportedContainers = append(supportedContainers, instance.LXD)
+	}
 	return a.updateSupportedContainers(runner, st, entity.Tag(), supportedContainers, agentConfig)
 }
 
@@ -1001,6 +1012,12 @@ func (a *MachineAgent) startEnvWorkers(
 	singularRunner.StartWorker("minunitsworker", func() (worker.Worker, error) {
 		return minunitsworker.NewMinUnitsWorker(st), nil
 	})
+	singularRunner.StartWorker("charmupgrader", func() (worker.Worker, error) {
+		return charmupgrader.NewCharmUpgrader(st), nil
+	})
+	singularRunner.StartWorker("remoterelations", func() (worker.Worker, error) {
+		return remoterelations.NewRemoteRelations(st), nil
+	})
 
 	// Start workers that use an API connection.
 	singularRunner.StartWorker("environ-provisioner", func() (worker.Worker, error) {
@@ -1073,12 +1090,7 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 	dataDir := agentConfig.DataDir()
 	logDir := agentConfig.LogDir()
 
-	endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
-	listener, err := net.Listen("tcp", endpoint)
-	if err != nil {
-		return nil, err
-	}
-	return apiserver.NewServer(st, listener, apiserver.ServerConfig{
+	serverConfig := apiserver.ServerConfig{
 		Cert:        cert,
 		Key:         key,
 		Tag:         tag,
@@ -1086,7 +1098,49 @@ func (a *MachineAgent) newApiserverWorker(st *state.State, certChanged chan para
 		LogDir:      logDir,
 		Validator:   a.limitLogins,
 		CertChanged: certChanged,
-	})
+	}
+	if err := setServerRateLimits(&serverConfig, agentConfig); err != nil {
+		return nil, &cmdutil.FatalError{err.Error()}
+	}
+	if reporter, ok := a.runner.(worker.Reporter); ok {
+		serverConfig.WorkerReporter = reporter
+	}
+
+	endpoint := net.JoinHostPort("", strconv.Itoa(info.APIPort))
+	listener, err := net.Listen("tcp", endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return apiserver.NewServer(st, listener, serverConfig)
+}
+
+// setServerRateLimits sets the API server's rate limits from
+// any values specified in the agent configuration. Limits that
+// are not specified are left as zero, so the server uses its
+// defaults.
+func setServerRateLimits(cfg *apiserver.ServerConfig, agentConfig agent.Config) error {
+	if s := agentConfig.Value(agent.LoginRateLimit); s != "" {
+		limit, err := strconv.Atoi(s)
+		if err != nil || limit < 0 {
+			return errors.Errorf("invalid login rate limit: %q", s)
+		}
+		cfg.LoginRateLimit = limit
+	}
+	if s := agentConfig.Value(agent.AgentRequestRate); s != "" {
+		rate, err := strconv.ParseFloat(s, 64)
+		if err != nil || rate < 0 {
+			return errors.Errorf("invalid agent request rate: %q", s)
+		}
+		cfg.AgentRequestRate = rate
+	}
+	if s := agentConfig.Value(agent.AgentRequestBurst); s != "" {
+		burst, err := strconv.Atoi(s)
+		if err != nil || burst < 0 {
+			return errors.Errorf("invalid agent request burst: %q", s)
+		}
+		cfg.AgentRequestBurst = burst
+	}
+	return nil
 }
 
 // limitLogins is called by the API server for each login attempt.
@@ -1329,7 +1383,8 @@ func (a *MachineAgent) startWorkerAfterUpgrade(runner worker.Runner, name string
 
 // upgradeWaiterWorker runs the specified worker after upgrades have completed.
 func (a *MachineAgent) upgradeWaiterWorker(start func() (worker.Worker, error)) worker.Worker {
-	return worker.NewSimpleWorker(func(stop <-chan struct{}) error {
+	waiter := &upgradeWaiter{}
+	waiter.Worker = worker.NewSimpleWorker(func(stop <-chan struct{}) error {
 		// Wait for the upgrade to complete (or for us to be stopped).
 		select {
 		case <-stop:
@@ -1341,6 +1396,7 @@ func (a *MachineAgent) upgradeWaiterWorker(start func() (worker.Worker, error))
 		if err != nil {
 			return err
 		}
+		waiter.setStarted(worker)
 		// Wait for worker to finish or for us to be stopped.
 		waitCh := make(chan error)
 		go func() {
@@ -1354,6 +1410,35 @@ func (a *MachineAgent) upgradeWaiterWorker(start func() (worker.Worker, error))
 		}
 		return <-waitCh // Ensure worker has stopped before returning.
 	})
+	return waiter
+}
+
+// upgradeWaiter is the worker returned by upgradeWaiterWorker.
+// It records the worker started after the upgrade so that
+// it can be reported on.
+type upgradeWaiter struct {
+	worker.Worker
+
+	mu      sync.Mutex
+	started worker.Worker
+}
+
+func (w *upgradeWaiter) setStarted(started worker.Worker) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.started = started
+}
+
+// Report implements worker.Reporter by reporting on the
+// started worker, if it is a reporter.
+func (w *upgradeWaiter) Report() []worker.WorkerReport {
+	w.mu.Lock()
+	started := w.started
+	w.mu.Unlock()
+	if reporter, ok := started.(worker.Reporter); ok {
+		return reporter.Report()
+	}
+	return nil
 }
 
 func (a *MachineAgent) setMachineStatus(apiState *api.State, status params.Status, info string) error {
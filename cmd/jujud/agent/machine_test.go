@@ -34,6 +34,7 @@ import (
 	apimetricsmanager "github.com/juju/juju/api/metricsmanager"
 	apinetworker "github.com/juju/juju/api/networker"
 	apirsyslog "github.com/juju/juju/api/rsyslog"
+	"github.com/juju/juju/apiserver"
 	charmtesting "github.com/juju/juju/apiserver/charmrevisionupdater/testing"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cert"
@@ -240,6 +241,8 @@ type MachineSuite struct {
 var perEnvSingularWorkers = []string{
 	"cleaner",
 	"minunitsworker",
+	"charmupgrader",
+	"remoterelations",
 	"environ-provisioner",
 	"charm-revision-updater",
 	"firewaller",
@@ -1699,10 +1702,63 @@ func (s *shouldWriteProxyFilesSuite) TestAll(c *gc.C) {
 	}
 }
 
+type serverRateLimitsSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&serverRateLimitsSuite{})
+
+func (s *serverRateLimitsSuite) TestSetServerRateLimits(c *gc.C) {
+	tests := []struct {
+		about  string
+		values map[string]string
+		expect apiserver.ServerConfig
+		err    string
+	}{{
+		about: "no limits specified",
+	}, {
+		about: "all limits specified",
+		values: map[string]string{
+			agent.LoginRateLimit:    "20",
+			agent.AgentRequestRate:  "2.5",
+			agent.AgentRequestBurst: "50",
+		},
+		expect: apiserver.ServerConfig{
+			LoginRateLimit:    20,
+			AgentRequestRate:  2.5,
+			AgentRequestBurst: 50,
+		},
+	}, {
+		about:  "invalid login rate limit",
+		values: map[string]string{agent.LoginRateLimit: "lots"},
+		err:    `invalid login rate limit: "lots"`,
+	}, {
+		about:  "negative agent request rate",
+		values: map[string]string{agent.AgentRequestRate: "-1"},
+		err:    `invalid agent request rate: "-1"`,
+	}, {
+		about:  "invalid agent request burst",
+		values: map[string]string{agent.AgentRequestBurst: "1.5"},
+		err:    `invalid agent request burst: "1.5"`,
+	}}
+	for i, test := range tests {
+		c.Logf("test %d: %s", i, test.about)
+		var cfg apiserver.ServerConfig
+		err := setServerRateLimits(&cfg, &mockAgentConfig{values: test.values})
+		if test.err != "" {
+			c.Check(err, gc.ErrorMatches, test.err)
+			continue
+		}
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(cfg, jc.DeepEquals, test.expect)
+	}
+}
+
 type mockAgentConfig struct {
 	agent.Config
 	providerType string
 	tag          names.Tag
+	values       map[string]string
 }
 
 func (m *mockAgentConfig) Tag() names.Tag {
@@ -1713,7 +1769,7 @@ func (m *mockAgentConfig) Value(key string) string {
 	if key == agent.ProviderType {
 		return m.providerType
 	}
-	return ""
+	return m.values[key]
 }
 
 type singularRunnerRecord struct {
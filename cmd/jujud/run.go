@@ -20,6 +20,7 @@ import (
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 type RunCommand struct {
@@ -28,6 +29,7 @@ type RunCommand struct {
 	commands        string
 	showHelp        bool
 	noContext       bool
+	hook            bool
 	forceRemoteUnit bool
 	relationId      string
 	remoteUnitName  string
@@ -45,6 +47,13 @@ If --no-context is specified, the <unit-name> positional
 argument is not needed.
 
 The commands are executed with '/bin/bash -s', and the output returned.
+
+If --hook is specified, the <commands> argument instead names a hook,
+such as "config-changed" or "db-relation-changed", which the unit agent
+queues to run in the same way as it runs hooks in response to changes;
+the relation and remote unit of a relation hook may be given with
+--relation and --remote-unit. Only hooks that charms must already
+expect to run repeatedly may be run this way.
 `
 
 // Info returns usage information for the command.
@@ -59,6 +68,7 @@ func (c *RunCommand) Info() *cmd.Info {
 
 func (c *RunCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.noContext, "no-context", false, "do not run the command in a unit context")
+	f.BoolVar(&c.hook, "hook", false, "queue the named hook to run on the unit")
 	f.StringVar(&c.relationId, "r", "", "run the commands for a specific relation context on a unit")
 	f.StringVar(&c.relationId, "relation", "", "")
 	f.StringVar(&c.remoteUnitName, "remote-unit", "", "run the commands for a specific remote unit in a relation context on a unit")
@@ -70,6 +80,14 @@ func (c *RunCommand) Init(args []string) error {
 	if contextId, err := getenv("JUJU_CONTEXT_ID"); err == nil && contextId != "" {
 		return fmt.Errorf("juju-run cannot be called from within a hook, have context %q", contextId)
 	}
+	if c.hook {
+		if c.noContext {
+			return fmt.Errorf("cannot run a hook without a unit context")
+		}
+		if c.forceRemoteUnit {
+			return fmt.Errorf("cannot force the remote unit of a hook")
+		}
+	}
 	if !c.noContext {
 		if len(args) < 1 {
 			return fmt.Errorf("missing unit-name")
@@ -90,6 +108,9 @@ func (c *RunCommand) Init(args []string) error {
 		}
 	}
 	if len(args) < 1 {
+		if c.hook {
+			return fmt.Errorf("missing hook name")
+		}
 		return fmt.Errorf("missing commands")
 	}
 	c.commands, args = args[0], args[1:]
@@ -97,6 +118,9 @@ func (c *RunCommand) Init(args []string) error {
 }
 
 func (c *RunCommand) Run(ctx *cmd.Context) error {
+	if c.hook {
+		return c.queueHookInUnitContext()
+	}
 	var result *exec.ExecResponse
 	var err error
 	if c.noContext {
@@ -118,24 +142,35 @@ func (c *RunCommand) socketPath() string {
 	return paths.Runtime.JujuRunSocket
 }
 
-func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
+// checkUnitContext verifies that the unit is deployed on this machine
+// and returns the id of the relation given by the user, or -1 if none
+// was given.
+func (c *RunCommand) checkUnitContext() (int, error) {
 	unitDir := agent.Dir(cmdutil.DataDir, c.unit)
 	logger.Debugf("looking for unit dir %s", unitDir)
 	// make sure the unit exists
 	_, err := os.Stat(unitDir)
 	if os.IsNotExist(err) {
-		return nil, errors.Errorf("unit %q not found on this machine", c.unit.Id())
+		return -1, errors.Errorf("unit %q not found on this machine", c.unit.Id())
 	} else if err != nil {
-		return nil, errors.Trace(err)
+		return -1, errors.Trace(err)
 	}
 
 	relationId, err := checkRelationId(c.relationId)
 	if err != nil {
-		return nil, errors.Trace(err)
+		return -1, errors.Trace(err)
 	}
 
 	if len(c.remoteUnitName) > 0 && relationId == -1 {
-		return nil, errors.Errorf("remote unit: %s, provided without a relation", c.remoteUnitName)
+		return -1, errors.Errorf("remote unit: %s, provided without a relation", c.remoteUnitName)
+	}
+	return relationId, nil
+}
+
+func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
+	relationId, err := c.checkUnitContext()
+	if err != nil {
+		return nil, errors.Trace(err)
 	}
 	client, err := sockets.Dial(c.socketPath())
 	if err != nil {
@@ -154,6 +189,30 @@ func (c *RunCommand) executeInUnitContext() (*exec.ExecResponse, error) {
 	return &result, errors.Trace(err)
 }
 
+func (c *RunCommand) queueHookInUnitContext() error {
+	relationId, err := c.checkUnitContext()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	client, err := sockets.Dial(c.socketPath())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	var result hook.Info
+	args := uniter.RunHookArgs{
+		Hook:           c.commands,
+		RelationId:     relationId,
+		RemoteUnitName: c.remoteUnitName,
+	}
+	if err := client.Call(uniter.JujuRunHookEndpoint, args, &result); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Debugf("queued hook %+v", result)
+	return nil
+}
+
 // appendProxyToCommands activates proxy settings on platforms
 // that support this feature via the command line. Currently this
 // will work on most GNU/Linux systems, but has no use in Windows
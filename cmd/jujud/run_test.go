@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
@@ -19,11 +20,13 @@ import (
 	"github.com/juju/utils/exec"
 	"github.com/juju/utils/fslock"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4/hooks"
 
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 type RunTestSuite struct {
@@ -48,6 +51,7 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 		relationId      string
 		remoteUnit      string
 		forceRemoteUnit bool
+		hook            bool
 	}{{
 		title:    "no args",
 		errMatch: "missing unit-name",
@@ -107,6 +111,26 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 		unit:            names.NewUnitTag("name/2"),
 		relationId:      "mongodb:1",
 		forceRemoteUnit: true,
+	}, {
+		title:      "hook",
+		args:       []string{"--hook", "--relation", "db:1", "--remote-unit", "mysql/0", "unit-name-2", "db-relation-changed"},
+		commands:   "db-relation-changed",
+		unit:       names.NewUnitTag("name/2"),
+		relationId: "db:1",
+		remoteUnit: "mysql/0",
+		hook:       true,
+	}, {
+		title:    "hook without name",
+		args:     []string{"--hook", "unit-name-2"},
+		errMatch: "missing hook name",
+	}, {
+		title:    "hook without context",
+		args:     []string{"--hook", "--no-context", "config-changed"},
+		errMatch: "cannot run a hook without a unit context",
+	}, {
+		title:    "hook with forced remote unit",
+		args:     []string{"--hook", "--force-remote-unit", "--relation", "db:1", "unit-name-2", "db-relation-changed"},
+		errMatch: "cannot force the remote unit of a hook",
 	},
 	} {
 		c.Logf("%d: %s", i, test.title)
@@ -120,6 +144,7 @@ func (*RunTestSuite) TestArgParsing(c *gc.C) {
 			c.Assert(runCommand.relationId, gc.Equals, test.relationId)
 			c.Assert(runCommand.remoteUnitName, gc.Equals, test.remoteUnit)
 			c.Assert(runCommand.forceRemoteUnit, gc.Equals, test.forceRemoteUnit)
+			c.Assert(runCommand.hook, gc.Equals, test.hook)
 		} else {
 			c.Assert(err, gc.ErrorMatches, test.errMatch)
 		}
@@ -260,6 +285,20 @@ func (s *RunTestSuite) TestSkipCheckAndRemoteUnit(c *gc.C) {
 	c.Assert(testing.Stderr(ctx), gc.Equals, "bar stderr")
 }
 
+func (s *RunTestSuite) TestRunningHook(c *gc.C) {
+	loggo.GetLogger("worker.uniter").SetLogLevel(loggo.TRACE)
+	s.runListenerForAgent(c, "unit-foo-1")
+
+	ctx, err := testing.RunCommand(c, &RunCommand{}, "--hook", "--relation", "db:1", "foo/1", "db-relation-changed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, "")
+	c.Assert(testing.Stderr(ctx), gc.Equals, "")
+
+	_, err = testing.RunCommand(c, &RunCommand{}, "--hook", "foo/1", "install")
+	c.Check(cmd.IsRcPassthroughError(err), jc.IsFalse)
+	c.Assert(err, gc.ErrorMatches, `cannot run "install" hook on request`)
+}
+
 func (s *RunTestSuite) TestCheckRelationIdValid(c *gc.C) {
 	for i, test := range []struct {
 		title  string
@@ -331,3 +370,15 @@ func (r *mockRunner) RunCommands(args uniter.RunCommandsArgs) (results *exec.Exe
 		Stderr: []byte(args.Commands + " stderr"),
 	}, nil
 }
+
+func (r *mockRunner) RunHook(args uniter.RunHookArgs) (hook.Info, error) {
+	r.c.Log("mock runner: hook " + args.Hook)
+	if args.Hook == "install" {
+		return hook.Info{}, errors.Errorf("cannot run %q hook on request", args.Hook)
+	}
+	return hook.Info{
+		Kind:       hooks.RelationChanged,
+		RelationId: args.RelationId,
+		RemoteUnit: args.RemoteUnitName,
+	}, nil
+}
@@ -228,6 +228,15 @@ func (c *CloseWorker) Wait() error {
 	return err
 }
 
+// Report implements worker.Reporter by reporting on the
+// wrapped worker, if it is a reporter.
+func (c *CloseWorker) Report() []worker.WorkerReport {
+	if reporter, ok := c.worker.(worker.Reporter); ok {
+		return reporter.Report()
+	}
+	return nil
+}
+
 // HookExecutionLock returns an *fslock.Lock suitable for use as a
 // unit hook execution lock. Other workers may also use this lock if
 // they require isolation from hook execution.
@@ -15,6 +15,7 @@ import (
 
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/network"
 )
 
 // The following constants list the supported constraint attribute names, as defined
@@ -29,6 +30,10 @@ const (
 	Tags         = "tags"
 	InstanceType = "instance-type"
 	Networks     = "networks"
+	Zones        = "zones"
+	Spaces       = "spaces"
+	Preemptible  = "preemptible"
+	SpotPrice    = "spot-price"
 )
 
 // Value describes a user's requirements of the hardware on which units
@@ -78,6 +83,30 @@ type Value struct {
 	// negative values are accepted, and the difference is the latter
 	// have a "^" prefix to the name.
 	Networks *[]string `json:"networks,omitempty" yaml:"networks,omitempty"`
+
+	// Zones, if not nil, holds a list of availability zone names. The
+	// machine must be started in one of the listed zones. An empty list
+	// is treated the same as a nil (unspecified) list, except an empty
+	// list will override any default zones, where a nil list will not.
+	Zones *[]string `json:"zones,omitempty" yaml:"zones,omitempty"`
+
+	// Spaces, if not nil, holds a list of juju space names that the
+	// machine must (or must not) have an address in. Positive and
+	// negative values are accepted, and the difference is the latter
+	// have a "^" prefix to the name.
+	Spaces *[]string `json:"spaces,omitempty" yaml:"spaces,omitempty"`
+
+	// Preemptible, if true, indicates that the machine may be started
+	// on capacity the provider can reclaim at any time (EC2 spot
+	// instances, GCE preemptible instances) in exchange for a lower
+	// price. Such machines cannot host state servers.
+	Preemptible *bool `json:"preemptible,omitempty" yaml:"preemptible,omitempty"`
+
+	// SpotPrice, if not nil, holds the maximum price in US dollars per
+	// hour the user is willing to pay for a preemptible machine, in
+	// providers that bid for such capacity. It is only meaningful
+	// together with preemptible=true.
+	SpotPrice *float64 `json:"spot-price,omitempty" yaml:"spot-price,omitempty"`
 }
 
 // fieldNames records a mapping from the constraint tag to struct field name.
@@ -151,6 +180,69 @@ func (v *Value) HaveNetworks() bool {
 	return v.Networks != nil && len(*v.Networks) > 0
 }
 
+// extractSpaces returns the list of spaces to include or exclude
+// (without the "^" prefixes).
+func (v *Value) extractSpaces() (include, exclude []string) {
+	if v.Spaces == nil {
+		return nil, nil
+	}
+	for _, name := range *v.Spaces {
+		if strings.HasPrefix(name, "^") {
+			exclude = append(exclude, strings.TrimPrefix(name, "^"))
+		} else {
+			include = append(include, name)
+		}
+	}
+	return include, exclude
+}
+
+// IncludeSpaces returns a list of spaces to include when starting a
+// machine, if specified.
+func (v *Value) IncludeSpaces() []string {
+	include, _ := v.extractSpaces()
+	return include
+}
+
+// ExcludeSpaces returns a list of spaces to exclude when starting a
+// machine, if specified. They are given in the spaces constraint with
+// a "^" prefix to the name, which is stripped before returning.
+func (v *Value) ExcludeSpaces() []string {
+	_, exclude := v.extractSpaces()
+	return exclude
+}
+
+// HaveSpaces returns whether any space constraints were specified.
+func (v *Value) HaveSpaces() bool {
+	return v.Spaces != nil && len(*v.Spaces) > 0
+}
+
+// IsPreemptible returns true if the constraints.Value requests a
+// machine that the provider may reclaim at any time.
+func (v *Value) IsPreemptible() bool {
+	return v.Preemptible != nil && *v.Preemptible
+}
+
+// HasZones returns true if the constraints.Value restricts the
+// availability zones a machine may be started in.
+func (v *Value) HasZones() bool {
+	return v.Zones != nil && len(*v.Zones) > 0
+}
+
+// IncludesZone returns true if a machine may be started in the named
+// availability zone; that is, if no zones are specified or the zone
+// is one of those specified.
+func (v *Value) IncludesZone(zone string) bool {
+	if !v.HasZones() {
+		return true
+	}
+	for _, z := range *v.Zones {
+		if z == zone {
+			return true
+		}
+	}
+	return false
+}
+
 // String expresses a constraints.Value in the language in which it was specified.
 func (v Value) String() string {
 	var strs []string
@@ -191,6 +283,20 @@ func (v Value) String() string {
 		s := strings.Join(*v.Networks, ",")
 		strs = append(strs, "networks="+s)
 	}
+	if v.Zones != nil {
+		s := strings.Join(*v.Zones, ",")
+		strs = append(strs, "zones="+s)
+	}
+	if v.Spaces != nil {
+		s := strings.Join(*v.Spaces, ",")
+		strs = append(strs, "spaces="+s)
+	}
+	if v.Preemptible != nil {
+		strs = append(strs, "preemptible="+strconv.FormatBool(*v.Preemptible))
+	}
+	if v.SpotPrice != nil {
+		strs = append(strs, "spot-price="+priceStr(*v.SpotPrice))
+	}
 	return strings.Join(strs, " ")
 }
 
@@ -201,6 +307,13 @@ func uintStr(i uint64) string {
 	return fmt.Sprintf("%d", i)
 }
 
+func priceStr(f float64) string {
+	if f == 0 {
+		return ""
+	}
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
 // Parse constructs a constraints.Value from the supplied arguments,
 // each of which must contain only spaces and name=value pairs. If any
 // name is specified more than once, an error is returned.
@@ -330,6 +443,14 @@ func (v *Value) setRaw(raw string) error {
 		err = v.setInstanceType(str)
 	case Networks:
 		err = v.setNetworks(str)
+	case Zones:
+		err = v.setZones(str)
+	case Spaces:
+		err = v.setSpaces(str)
+	case Preemptible:
+		err = v.setPreemptible(str)
+	case SpotPrice:
+		err = v.setSpotPrice(str)
 	default:
 		return fmt.Errorf("unknown constraint %q", name)
 	}
@@ -376,6 +497,18 @@ func (v *Value) SetYAML(tag string, value interface{}) bool {
 			if err == nil {
 				err = v.validateNetworks(networks)
 			}
+		case Zones:
+			v.Zones, err = parseYamlStrings("zones", val)
+		case Spaces:
+			var spaces *[]string
+			spaces, err = parseYamlStrings("spaces", val)
+			if err == nil {
+				err = v.validateSpaces(spaces)
+			}
+		case Preemptible:
+			v.Preemptible, err = parseBool(vstr)
+		case SpotPrice:
+			v.SpotPrice, err = parsePrice(vstr)
 		default:
 			return false
 		}
@@ -467,6 +600,51 @@ func (v *Value) setTags(str string) error {
 	return nil
 }
 
+func (v *Value) setPreemptible(str string) (err error) {
+	if v.Preemptible != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Preemptible, err = parseBool(str)
+	return
+}
+
+func (v *Value) setSpotPrice(str string) (err error) {
+	if v.SpotPrice != nil {
+		return fmt.Errorf("already set")
+	}
+	v.SpotPrice, err = parsePrice(str)
+	return
+}
+
+func (v *Value) setZones(str string) error {
+	if v.Zones != nil {
+		return fmt.Errorf("already set")
+	}
+	v.Zones = parseCommaDelimited(str)
+	return nil
+}
+
+func (v *Value) setSpaces(str string) error {
+	if v.Spaces != nil {
+		return fmt.Errorf("already set")
+	}
+	return v.validateSpaces(parseCommaDelimited(str))
+}
+
+func (v *Value) validateSpaces(spaces *[]string) error {
+	if spaces == nil {
+		return nil
+	}
+	for _, name := range *spaces {
+		name = strings.TrimPrefix(name, "^")
+		if !network.IsValidSpace(name) {
+			return fmt.Errorf("%q is not a valid space name", name)
+		}
+	}
+	v.Spaces = spaces
+	return nil
+}
+
 func (v *Value) setNetworks(str string) error {
 	if v.Networks != nil {
 		return fmt.Errorf("already set")
@@ -494,6 +672,30 @@ func (v *Value) validateNetworks(networks *[]string) error {
 	return nil
 }
 
+func parseBool(str string) (*bool, error) {
+	var value bool
+	if str != "" {
+		val, err := strconv.ParseBool(str)
+		if err != nil {
+			return nil, fmt.Errorf("must be 'true' or 'false'")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
+func parsePrice(str string) (*float64, error) {
+	var value float64
+	if str != "" {
+		val, err := strconv.ParseFloat(str, 64)
+		if err != nil || val < 0 || math.IsInf(val, 0) || math.IsNaN(val) {
+			return nil, fmt.Errorf("must be a non-negative price in US dollars per hour")
+		}
+		value = val
+	}
+	return &value, nil
+}
+
 func parseUint64(str string) (*uint64, error) {
 	var value uint64
 	if str != "" {
@@ -525,7 +727,8 @@ func parseSize(str string) (*uint64, error) {
 }
 
 // parseCommaDelimited returns the items in the value s. We expect the
-// tags to be comma delimited strings. It is used for tags and networks.
+// tags to be comma delimited strings. It is used for tags, networks
+// and zones.
 func parseCommaDelimited(s string) *[]string {
 	if s == "" {
 		return &[]string{}
@@ -280,6 +280,83 @@ var parseConstraintsTests = []struct {
 		args:    []string{"networks="},
 	},
 
+	// zones
+	{
+		summary: "single zone",
+		args:    []string{"zones=us-east-1a"},
+	}, {
+		summary: "multiple zones",
+		args:    []string{"zones=us-east-1a,us-east-1b"},
+	}, {
+		summary: "no zones",
+		args:    []string{"zones="},
+	}, {
+		summary: "double set zones together",
+		args:    []string{"zones=a zones=b"},
+		err:     `bad "zones" constraint: already set`,
+	},
+
+	// spaces
+	{
+		summary: "single space",
+		args:    []string{"spaces=internal"},
+	}, {
+		summary: "multiple spaces - positive and negative",
+		args:    []string{"spaces=internal,^dmz,db-1"},
+	}, {
+		summary: "no spaces",
+		args:    []string{"spaces="},
+	}, {
+		summary: "invalid space",
+		args:    []string{"spaces=Internal"},
+		err:     `bad "spaces" constraint: "Internal" is not a valid space name`,
+	}, {
+		summary: "double set spaces together",
+		args:    []string{"spaces=a spaces=b"},
+		err:     `bad "spaces" constraint: already set`,
+	},
+
+	// preemptible
+	{
+		summary: "set preemptible",
+		args:    []string{"preemptible=true"},
+	}, {
+		summary: "unset preemptible",
+		args:    []string{"preemptible=false"},
+	}, {
+		summary: "preemptible empty",
+		args:    []string{"preemptible="},
+	}, {
+		summary: "preemptible not a boolean",
+		args:    []string{"preemptible=maybe"},
+		err:     `bad "preemptible" constraint: must be 'true' or 'false'`,
+	}, {
+		summary: "double set preemptible together",
+		args:    []string{"preemptible=true preemptible=false"},
+		err:     `bad "preemptible" constraint: already set`,
+	},
+
+	// spot price
+	{
+		summary: "set spot price",
+		args:    []string{"spot-price=0.05"},
+	}, {
+		summary: "spot price empty",
+		args:    []string{"spot-price="},
+	}, {
+		summary: "negative spot price",
+		args:    []string{"spot-price=-1"},
+		err:     `bad "spot-price" constraint: must be a non-negative price in US dollars per hour`,
+	}, {
+		summary: "spot price not a number",
+		args:    []string{"spot-price=cheap"},
+		err:     `bad "spot-price" constraint: must be a non-negative price in US dollars per hour`,
+	}, {
+		summary: "double set spot price together",
+		args:    []string{"spot-price=1 spot-price=2"},
+		err:     `bad "spot-price" constraint: already set`,
+	},
+
 	// instance type
 	{
 		summary: "set instance type",
@@ -294,12 +371,14 @@ var parseConstraintsTests = []struct {
 		summary: "kitchen sink together",
 		args: []string{
 			"root-disk=8G mem=2T  arch=i386  cpu-cores=4096 cpu-power=9001 container=lxc " +
-				"tags=foo,bar networks=net1,^net2 instance-type=foo"},
+				"tags=foo,bar networks=net1,^net2 instance-type=foo zones=az1,az2 spaces=sp1,^sp2 " +
+				"preemptible=true spot-price=0.25"},
 	}, {
 		summary: "kitchen sink separately",
 		args: []string{
 			"root-disk=8G", "mem=2T", "cpu-cores=4096", "cpu-power=9001", "arch=armhf",
-			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo"},
+			"container=lxc", "tags=foo,bar", "networks=net1,^net2", "instance-type=foo",
+			"zones=az1,az2", "spaces=sp1,^sp2", "preemptible=true", "spot-price=0.25"},
 	},
 }
 
@@ -373,6 +452,46 @@ func (s *ConstraintsSuite) TestIncludeExcludeAndHaveNetworks(c *gc.C) {
 	c.Check(con.HaveNetworks(), jc.IsTrue)
 }
 
+func (s *ConstraintsSuite) TestHasZonesAndIncludesZone(c *gc.C) {
+	con := constraints.MustParse("mem=4G")
+	c.Check(con.HasZones(), jc.IsFalse)
+	c.Check(con.IncludesZone("az1"), jc.IsTrue)
+
+	con = constraints.MustParse("zones=")
+	c.Check(con.HasZones(), jc.IsFalse)
+	c.Check(con.IncludesZone("az1"), jc.IsTrue)
+
+	con = constraints.MustParse("zones=az1,az2")
+	c.Check(con.HasZones(), jc.IsTrue)
+	c.Check(con.IncludesZone("az1"), jc.IsTrue)
+	c.Check(con.IncludesZone("az2"), jc.IsTrue)
+	c.Check(con.IncludesZone("az3"), jc.IsFalse)
+}
+
+func (s *ConstraintsSuite) TestIsPreemptible(c *gc.C) {
+	con := constraints.MustParse("mem=4G")
+	c.Check(con.IsPreemptible(), jc.IsFalse)
+	con = constraints.MustParse("preemptible=")
+	c.Check(con.IsPreemptible(), jc.IsFalse)
+	con = constraints.MustParse("preemptible=false")
+	c.Check(con.IsPreemptible(), jc.IsFalse)
+	con = constraints.MustParse("preemptible=true")
+	c.Check(con.IsPreemptible(), jc.IsTrue)
+}
+
+func (s *ConstraintsSuite) TestIncludeExcludeAndHaveSpaces(c *gc.C) {
+	con := constraints.MustParse("spaces=sp1,^sp2,sp3,^sp4")
+	c.Assert(con.Spaces, gc.Not(gc.IsNil))
+	c.Check(*con.Spaces, gc.HasLen, 4)
+	c.Check(con.IncludeSpaces(), jc.SameContents, []string{"sp1", "sp3"})
+	c.Check(con.ExcludeSpaces(), jc.SameContents, []string{"sp2", "sp4"})
+	c.Check(con.HaveSpaces(), jc.IsTrue)
+	con = constraints.MustParse("mem=4G spaces=")
+	c.Check(con.HaveSpaces(), jc.IsFalse)
+	con = constraints.MustParse("mem=4G spaces=^sp1")
+	c.Check(con.HaveSpaces(), jc.IsTrue)
+}
+
 func (s *ConstraintsSuite) TestInvalidNetworks(c *gc.C) {
 	invalidNames := []string{
 		"%ne$t", "^net#2", "_", "tcp:ip",
@@ -400,6 +519,10 @@ func (s *ConstraintsSuite) TestIsEmpty(c *gc.C) {
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 	con = constraints.MustParse("networks=")
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+	con = constraints.MustParse("zones=")
+	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
+	con = constraints.MustParse("spaces=")
+	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 	con = constraints.MustParse("mem=")
 	c.Check(&con, gc.Not(jc.Satisfies), constraints.IsEmpty)
 	con = constraints.MustParse("arch=")
@@ -424,6 +547,14 @@ func strp(s string) *string {
 	return &s
 }
 
+func boolp(b bool) *bool {
+	return &b
+}
+
+func float64p(f float64) *float64 {
+	return &f
+}
+
 func ctypep(ctype string) *instance.ContainerType {
 	res := instance.ContainerType(ctype)
 	return &res
@@ -459,6 +590,18 @@ var constraintsRoundtripTests = []roundTrip{
 	{"Networks1", constraints.Value{Networks: nil}},
 	{"Networks2", constraints.Value{Networks: &[]string{}}},
 	{"Networks3", constraints.Value{Networks: &[]string{"net1", "^net2"}}},
+	{"Zones1", constraints.Value{Zones: nil}},
+	{"Zones2", constraints.Value{Zones: &[]string{}}},
+	{"Zones3", constraints.Value{Zones: &[]string{"az1", "az2"}}},
+	{"Spaces1", constraints.Value{Spaces: nil}},
+	{"Spaces2", constraints.Value{Spaces: &[]string{}}},
+	{"Spaces3", constraints.Value{Spaces: &[]string{"sp1", "^sp2"}}},
+	{"Preemptible1", constraints.Value{Preemptible: nil}},
+	{"Preemptible2", constraints.Value{Preemptible: boolp(false)}},
+	{"Preemptible3", constraints.Value{Preemptible: boolp(true)}},
+	{"SpotPrice1", constraints.Value{SpotPrice: nil}},
+	{"SpotPrice2", constraints.Value{SpotPrice: float64p(0)}},
+	{"SpotPrice3", constraints.Value{SpotPrice: float64p(0.125)}},
 	{"InstanceType1", constraints.Value{InstanceType: strp("")}},
 	{"InstanceType2", constraints.Value{InstanceType: strp("foo")}},
 	{"All", constraints.Value{
@@ -471,6 +614,10 @@ var constraintsRoundtripTests = []roundTrip{
 		Tags:         &[]string{"foo", "bar"},
 		Networks:     &[]string{"net1", "^net2"},
 		InstanceType: strp("foo"),
+		Zones:        &[]string{"az1", "az2"},
+		Spaces:       &[]string{"sp1", "^sp2"},
+		Preemptible:  boolp(true),
+		SpotPrice:    float64p(0.125),
 	}},
 }
 
@@ -33,6 +33,15 @@ var validationTests = []struct {
 		cons:        "root-disk=8G mem=4G arch=amd64 cpu-power=1000 cpu-cores=4 instance-type=foo",
 		unsupported: []string{"cpu-power", "instance-type"},
 	},
+	{
+		cons:        "mem=4G zones=az1,az2",
+		unsupported: []string{"zones"},
+	},
+	{
+		cons:  "mem=4G zones=az1,az3",
+		vocab: map[string][]interface{}{"zones": {"az1", "az2"}},
+		err:   "invalid constraint value: zones=az3\nvalid values are:.*",
+	},
 	{
 		// Ambiguous constraint errors take precedence over unsupported errors.
 		cons:        "root-disk=8G mem=4G cpu-cores=4 instance-type=foo",
@@ -4,6 +4,7 @@
 package container
 
 import (
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/instance"
 )
@@ -18,6 +19,12 @@ const (
 	// supports networking.
 	ConfigIPForwarding = "ip-forwarding"
 
+	// ConfigPortForwarding, if set to a non-empty value, instructs the
+	// host machine to forward the ports opened by units in its
+	// containers to the containers. Will be enabled if the environment
+	// does not support allocating addresses for containers.
+	ConfigPortForwarding = "port-forwarding"
+
 	DefaultNamespace = "juju"
 )
 
@@ -29,9 +36,13 @@ type ManagerConfig map[string]string
 // containers that it has started.
 type Manager interface {
 	// CreateContainer creates and starts a new container for the specified
-	// machine.
+	// machine. The container's resources are limited according to the
+	// given constraints where the container type supports it, and the
+	// limits applied are reported in the returned hardware
+	// characteristics.
 	CreateContainer(
 		machineConfig *cloudinit.MachineConfig,
+		cons constraints.Value,
 		series string,
 		network *NetworkConfig) (instance.Instance, *instance.HardwareCharacteristics, error)
 
@@ -102,6 +102,7 @@ var startParams StartParams
 
 func (manager *containerManager) CreateContainer(
 	machineConfig *cloudinit.MachineConfig,
+	cons constraints.Value,
 	series string,
 	networkConfig *container.NetworkConfig,
 ) (instance.Instance, *instance.HardwareCharacteristics, error) {
@@ -129,7 +130,7 @@ func (manager *containerManager) CreateContainer(
 		return nil, nil, err
 	}
 	// Create the container.
-	startParams = ParseConstraintsToStartParams(machineConfig.Constraints)
+	startParams = ParseConstraintsToStartParams(cons)
 	startParams.Arch = version.Current.Arch
 	startParams.Series = series
 	startParams.Network = networkConfig
@@ -149,7 +150,7 @@ func (manager *containerManager) CreateContainer(
 		logger.Warningf("failed to parse hardware: %v", err)
 	}
 
-	logger.Tracef("create the container, constraints: %v", machineConfig.Constraints)
+	logger.Tracef("create the container, constraints: %v", cons)
 	if err := kvmContainer.Start(startParams); err != nil {
 		err = errors.Annotate(err, "kvm container creation failed")
 		logger.Infof(err.Error())
@@ -101,6 +101,18 @@ func (s *KVMSuite) TestCreateContainer(c *gc.C) {
 	containertesting.AssertCloudInit(c, cloudInitFilename)
 }
 
+func (s *KVMSuite) TestCreateContainerWithConstraints(c *gc.C) {
+	cons := constraints.MustParse("mem=2G cpu-cores=4 root-disk=20G")
+	_, hardware := containertesting.CreateContainerWithConstraints(c, s.manager, "1/kvm/0", cons)
+
+	c.Assert(kvm.TestStartParams.Memory, gc.Equals, uint64(2048))
+	c.Assert(kvm.TestStartParams.CpuCores, gc.Equals, uint64(4))
+	c.Assert(kvm.TestStartParams.RootDisk, gc.Equals, uint64(20))
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(4))
+	c.Assert(*hardware.RootDisk, gc.Equals, uint64(20*1024))
+}
+
 func (s *KVMSuite) TestDestroyContainer(c *gc.C) {
 	instance := containertesting.CreateContainer(c, s.manager, "1/lxc/0")
 
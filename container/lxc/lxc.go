@@ -27,6 +27,7 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs/cloudinit"
 	"github.com/juju/juju/instance"
@@ -189,6 +190,7 @@ func preferFastLXC(release string) bool {
 // CreateContainer creates or clones an LXC container.
 func (manager *containerManager) CreateContainer(
 	machineConfig *cloudinit.MachineConfig,
+	cons constraints.Value,
 	series string,
 	networkConfig *container.NetworkConfig,
 ) (inst instance.Instance, _ *instance.HardwareCharacteristics, err error) {
@@ -313,6 +315,14 @@ func (manager *containerManager) CreateContainer(
 	if err := mountHostLogDir(name, manager.logdir); err != nil {
 		return nil, nil, errors.Annotate(err, "failed to mount the directory to log to")
 	}
+	hardware := &instance.HardwareCharacteristics{
+		Arch: &version.Current.Arch,
+	}
+	if limits := generateResourceLimits(cons, hardware); limits != "" {
+		if err := appendToContainerConfig(name, limits); err != nil {
+			return nil, nil, errors.Annotate(err, "failed to set resource limits")
+		}
+	}
 	// Update the network settings inside the run-time config of the
 	// container (e.g. /var/lib/lxc/<name>/config) before starting it.
 	netConfig := generateNetworkConfig(networkConfig)
@@ -361,10 +371,6 @@ func (manager *containerManager) CreateContainer(
 		return nil, nil, errors.Annotate(err, "container failed to start")
 	}
 
-	hardware := &instance.HardwareCharacteristics{
-		Arch: &version.Current.Arch,
-	}
-
 	return &lxcInstance{lxcContainer, name}, hardware, nil
 }
 
@@ -691,6 +697,36 @@ func appendToContainerConfig(name, line string) error {
 	return nil
 }
 
+// cpuPeriod is the CFS scheduling period, in microseconds, over which
+// a container's CPU usage is limited.
+const cpuPeriod = 100000
+
+// generateResourceLimits returns the cgroup settings needed to limit a
+// container's memory and CPU usage according to the given constraints,
+// and records the limits applied in hardware. Constraints which cannot
+// be enforced for LXC containers are logged and ignored.
+func generateResourceLimits(cons constraints.Value, hardware *instance.HardwareCharacteristics) string {
+	var limits bytes.Buffer
+	if cons.Mem != nil && *cons.Mem > 0 {
+		mem := *cons.Mem
+		fmt.Fprintf(&limits, "lxc.cgroup.memory.limit_in_bytes = %dM\n", mem)
+		hardware.Mem = &mem
+	}
+	if cons.CpuCores != nil && *cons.CpuCores > 0 {
+		cores := *cons.CpuCores
+		fmt.Fprintf(&limits, "lxc.cgroup.cpu.cfs_period_us = %d\n", cpuPeriod)
+		fmt.Fprintf(&limits, "lxc.cgroup.cpu.cfs_quota_us = %d\n", cores*cpuPeriod)
+		hardware.CpuCores = &cores
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %dM being ignored as not supported", *cons.RootDisk)
+	}
+	if cons.CpuPower != nil {
+		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+	}
+	return limits.String()
+}
+
 func autostartContainer(name string) error {
 	// Now symlink the config file into the restart directory, if it exists.
 	// This is for backwards compatiblity. From Trusty onwards, the auto start
@@ -27,6 +27,7 @@ import (
 	"launchpad.net/golxc"
 
 	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/lxc"
 	"github.com/juju/juju/container/lxc/mock"
@@ -722,6 +723,34 @@ func (s *LxcSuite) AssertEvent(c *gc.C, event mock.Event, expected mock.Action,
 	}
 }
 
+func (s *LxcSuite) TestCreateContainerWithConstraints(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	cons := constraints.MustParse("mem=2G cpu-cores=2 root-disk=10G")
+	instance, hardware := containertesting.CreateContainerWithConstraints(c, manager, "1/lxc/0", cons)
+
+	name := string(instance.Id())
+	lxcConfContents, err := ioutil.ReadFile(lxc.ContainerConfigFilename(name))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(lxcConfContents), jc.Contains, "lxc.cgroup.memory.limit_in_bytes = 2048M\n")
+	c.Assert(string(lxcConfContents), jc.Contains, "lxc.cgroup.cpu.cfs_period_us = 100000\n")
+	c.Assert(string(lxcConfContents), jc.Contains, "lxc.cgroup.cpu.cfs_quota_us = 200000\n")
+
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+	// Root disk size cannot be limited for LXC containers.
+	c.Assert(hardware.RootDisk, gc.IsNil)
+}
+
+func (s *LxcSuite) TestCreateContainerWithoutConstraints(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	instance := containertesting.CreateContainer(c, manager, "1/lxc/0")
+
+	name := string(instance.Id())
+	lxcConfContents, err := ioutil.ReadFile(lxc.ContainerConfigFilename(name))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(lxcConfContents), gc.Not(jc.Contains), "lxc.cgroup")
+}
+
 func (s *LxcSuite) TestCreateContainerEvents(c *gc.C) {
 	manager := s.makeManager(c, "test")
 	instance := containertesting.CreateContainer(c, manager, "1")
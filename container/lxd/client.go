@@ -0,0 +1,289 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+const (
+	syncResponse  = "sync"
+	asyncResponse = "async"
+	errorResponse = "error"
+
+	// operationSuccess is the status code of an operation that
+	// completed successfully.
+	operationSuccess = 200
+)
+
+// response is the envelope in which LXD returns the result of every
+// API request.
+type response struct {
+	Type       string          `json:"type"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Operation  string          `json:"operation"`
+	ErrorCode  int             `json:"error_code"`
+	Error      string          `json:"error"`
+	Metadata   json.RawMessage `json:"metadata"`
+}
+
+// operation describes the state of a background operation, as returned
+// when waiting for an asynchronous request to complete.
+type operation struct {
+	Id         string          `json:"id"`
+	Status     string          `json:"status"`
+	StatusCode int             `json:"status_code"`
+	Metadata   json.RawMessage `json:"metadata"`
+	Err        string          `json:"err"`
+}
+
+// containerSource identifies the image a container is created from.
+type containerSource struct {
+	Type  string `json:"type"`
+	Alias string `json:"alias,omitempty"`
+}
+
+// containerSpec holds the parameters of a container creation request.
+type containerSpec struct {
+	Name     string                       `json:"name"`
+	Source   containerSource              `json:"source"`
+	Config   map[string]string            `json:"config,omitempty"`
+	Devices  map[string]map[string]string `json:"devices,omitempty"`
+	Profiles []string                     `json:"profiles,omitempty"`
+}
+
+// containerState holds the run-time state of a container.
+type containerState struct {
+	Status     string `json:"status"`
+	StatusCode int    `json:"status_code"`
+}
+
+// stateChange holds the parameters of a request to start or stop a
+// container.
+type stateChange struct {
+	Action  string `json:"action"`
+	Timeout int    `json:"timeout"`
+	Force   bool   `json:"force"`
+}
+
+// imageAlias associates a name with an image fingerprint.
+type imageAlias struct {
+	Name   string `json:"name,omitempty"`
+	Target string `json:"target"`
+}
+
+// client is a minimal client for the LXD REST API, which is served on
+// a local unix socket.
+type client struct {
+	http *http.Client
+}
+
+// newClient returns a client which talks to the LXD daemon listening
+// on the given unix socket.
+func newClient(socketPath string) *client {
+	transport := &http.Transport{
+		Dial: func(_, _ string) (net.Conn, error) {
+			return net.Dial("unix", socketPath)
+		},
+	}
+	return &client{http: &http.Client{Transport: transport}}
+}
+
+// do sends a request to the LXD daemon, waiting for the operation to
+// complete if the response is asynchronous, and returns the metadata
+// of the result.
+func (c *client) do(method, urlPath string, body io.Reader, contentType string) (json.RawMessage, error) {
+	// The host is ignored, as all connections are made to the socket.
+	req, err := http.NewRequest(method, "http://lxd"+urlPath, body)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot %s %s", method, urlPath)
+	}
+	defer resp.Body.Close()
+
+	var result response
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, errors.Annotatef(err, "cannot decode response to %s %s", method, urlPath)
+	}
+	switch result.Type {
+	case syncResponse:
+		return result.Metadata, nil
+	case asyncResponse:
+		return c.wait(result.Operation)
+	case errorResponse:
+		if result.ErrorCode == http.StatusNotFound {
+			return nil, errors.NewNotFound(nil, result.Error)
+		}
+		return nil, errors.Errorf("lxd: %s", result.Error)
+	}
+	return nil, errors.Errorf("unexpected response type %q", result.Type)
+}
+
+// query sends a JSON-encoded request to the LXD daemon and decodes the
+// metadata of the result into out, if it is not nil.
+func (c *client) query(method, urlPath string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		body = bytes.NewReader(data)
+	}
+	metadata, err := c.do(method, urlPath, body, "application/json")
+	if err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(metadata, out); err != nil {
+		return errors.Annotatef(err, "cannot decode result of %s %s", method, urlPath)
+	}
+	return nil
+}
+
+// wait blocks until the operation at the given path completes, and
+// returns the metadata of its result.
+func (c *client) wait(operationPath string) (json.RawMessage, error) {
+	var op operation
+	if err := c.query("GET", operationPath+"/wait", nil, &op); err != nil {
+		return nil, errors.Annotate(err, "cannot wait for operation")
+	}
+	if op.StatusCode != operationSuccess {
+		return nil, errors.Errorf("lxd operation failed: %s", op.Err)
+	}
+	return op.Metadata, nil
+}
+
+// containerNames returns the names of all the containers known to LXD.
+func (c *client) containerNames() ([]string, error) {
+	var urls []string
+	if err := c.query("GET", "/1.0/containers", nil, &urls); err != nil {
+		return nil, errors.Trace(err)
+	}
+	names := make([]string, len(urls))
+	for i, url := range urls {
+		names[i] = path.Base(url)
+	}
+	return names, nil
+}
+
+// containerStatus returns the status of the named container, such as
+// "Running" or "Stopped".
+func (c *client) containerStatus(name string) (string, error) {
+	var state containerState
+	if err := c.query("GET", "/1.0/containers/"+name+"/state", nil, &state); err != nil {
+		return "", errors.Trace(err)
+	}
+	return state.Status, nil
+}
+
+// createContainer creates a new container, without starting it.
+func (c *client) createContainer(spec containerSpec) error {
+	return c.query("POST", "/1.0/containers", spec, nil)
+}
+
+// startContainer starts the named container.
+func (c *client) startContainer(name string) error {
+	return c.query("PUT", "/1.0/containers/"+name+"/state", stateChange{
+		Action:  "start",
+		Timeout: -1,
+	}, nil)
+}
+
+// stopContainer forcibly stops the named container.
+func (c *client) stopContainer(name string) error {
+	return c.query("PUT", "/1.0/containers/"+name+"/state", stateChange{
+		Action:  "stop",
+		Timeout: -1,
+		Force:   true,
+	}, nil)
+}
+
+// deleteContainer removes the named container, which must be stopped.
+func (c *client) deleteContainer(name string) error {
+	return c.query("DELETE", "/1.0/containers/"+name, nil, nil)
+}
+
+// hasImageAlias reports whether an image with the given alias is known
+// to LXD.
+func (c *client) hasImageAlias(alias string) (bool, error) {
+	err := c.query("GET", "/1.0/images/aliases/"+alias, nil, nil)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, errors.Trace(err)
+	}
+	return true, nil
+}
+
+// importImage uploads a split image, made up of a metadata tarball and
+// a root filesystem tarball, and gives it the specified alias.
+func (c *client) importImage(metadata, rootfs io.Reader, alias string) error {
+	body, writer := io.Pipe()
+	form := multipart.NewWriter(writer)
+	go func() {
+		writer.CloseWithError(writeImageParts(form, metadata, rootfs))
+	}()
+	result, err := c.do("POST", "/1.0/images", body, form.FormDataContentType())
+	// Make sure the writer is not left blocked if the request failed
+	// before the body was consumed.
+	body.Close()
+	if err != nil {
+		return errors.Annotate(err, "cannot upload image")
+	}
+	var image struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := json.Unmarshal(result, &image); err != nil {
+		return errors.Annotate(err, "cannot decode uploaded image")
+	}
+	if strings.TrimSpace(image.Fingerprint) == "" {
+		return errors.New("uploaded image has no fingerprint")
+	}
+	err = c.query("POST", "/1.0/images/aliases", imageAlias{
+		Name:   alias,
+		Target: image.Fingerprint,
+	}, nil)
+	return errors.Annotatef(err, "cannot create image alias %q", alias)
+}
+
+// writeImageParts writes the metadata and root filesystem tarballs of
+// a split image as parts of a multipart form.
+func writeImageParts(form *multipart.Writer, metadata, rootfs io.Reader) error {
+	for _, part := range []struct {
+		name string
+		data io.Reader
+	}{
+		{"metadata", metadata},
+		{"rootfs", rootfs},
+	} {
+		w, err := form.CreateFormFile(part.name, part.name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if _, err := io.Copy(w, part.data); err != nil {
+			return errors.Annotatef(err, "cannot write image %s", part.name)
+		}
+	}
+	return form.Close()
+}
@@ -0,0 +1,19 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"github.com/juju/juju/container"
+)
+
+var (
+	RuntimeGOOS    = &runtimeGOOS
+	ReleaseVersion = &releaseVersion
+)
+
+// EnsureImage imports the image for the given series and architecture
+// with the manager, if it has not already been imported.
+func EnsureImage(manager container.Manager, series, arch string) (string, error) {
+	return manager.(*containerManager).ensureImage(series, arch)
+}
@@ -0,0 +1,195 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"archive/tar"
+	"bytes"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+)
+
+// lxdArchitectures maps juju architecture names to the kernel
+// architecture names used by LXD.
+var lxdArchitectures = map[string]string{
+	arch.AMD64:   "x86_64",
+	arch.I386:    "i686",
+	arch.ARM:     "armv7l",
+	arch.ARM64:   "aarch64",
+	arch.PPC64EL: "ppc64le",
+}
+
+// cloudInitTemplates are the files written into a new container from
+// its image's templates, so that cloud-init finds the user-data set
+// in the container's config.
+var cloudInitTemplates = map[string]string{
+	"cloud-init-meta.tpl": "instance-id: {{ container.name }}\nlocal-hostname: {{ container.name }}\n",
+	"cloud-init-user.tpl": `{{ config_get("user.user-data", "") }}`,
+}
+
+type imageTemplate struct {
+	When     []string `yaml:"when"`
+	Template string   `yaml:"template"`
+}
+
+type imageMetadata struct {
+	Architecture string                   `yaml:"architecture"`
+	CreationDate int64                    `yaml:"creation_date"`
+	Properties   map[string]string        `yaml:"properties"`
+	Templates    map[string]imageTemplate `yaml:"templates"`
+}
+
+// imageAliasName returns the alias under which the image for the
+// given series and architecture is known to LXD.
+func imageAliasName(series, arch string) string {
+	return fmt.Sprintf("juju-%s-%s", series, arch)
+}
+
+// ensureImage makes sure an image for the given series and
+// architecture has been imported into LXD, and returns its alias.
+//
+// Images are built from the same Ubuntu cloud root filesystem tarballs
+// used for LXC containers, fetched through the state server's image
+// cache when an ImageURLGetter is available.
+func (manager *containerManager) ensureImage(series, arch string) (string, error) {
+	alias := imageAliasName(series, arch)
+	// Containers are started concurrently; only one of them should
+	// import any given image.
+	defer lockImage(alias)()
+	found, err := manager.client.hasImageAlias(alias)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if found {
+		return alias, nil
+	}
+
+	var imageURL string
+	httpClient := utils.GetValidatingHTTPClient()
+	if manager.imageURLGetter != nil {
+		imageURL, err = manager.imageURLGetter.ImageURL(instance.LXC, series, arch)
+		if manager.imageURLGetter.CACert() != nil {
+			// The state server's certificate cannot be verified
+			// against its address.
+			httpClient = utils.GetNonValidatingHTTPClient()
+		}
+	} else {
+		imageURL, err = container.ImageDownloadURL(instance.LXC, series, arch)
+	}
+	if err != nil {
+		return "", errors.Annotate(err, "cannot determine image URL")
+	}
+	metadata, err := generateImageMetadata(series, arch)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+
+	logger.Debugf("fetching image for %s/%s from %v", series, arch, imageURL)
+	resp, err := httpClient.Get(imageURL)
+	if err != nil {
+		return "", errors.Annotatef(err, "cannot fetch image from %v", imageURL)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", errors.Errorf("cannot fetch image from %v: %s", imageURL, resp.Status)
+	}
+	if err := manager.client.importImage(bytes.NewReader(metadata), resp.Body, alias); err != nil {
+		// Another process may have imported the image at the
+		// same time, in which case we can use that one.
+		if found, _ := manager.client.hasImageAlias(alias); found {
+			logger.Debugf("image %q already imported: %v", alias, err)
+			return alias, nil
+		}
+		return "", errors.Trace(err)
+	}
+	logger.Infof("imported image %q", alias)
+	return alias, nil
+}
+
+var (
+	imageLocksMutex sync.Mutex
+	imageLocks      = make(map[string]*sync.Mutex)
+)
+
+// lockImage acquires the lock for importing the image with the given
+// alias, and returns a function that releases it.
+func lockImage(alias string) func() {
+	imageLocksMutex.Lock()
+	lock, ok := imageLocks[alias]
+	if !ok {
+		lock = new(sync.Mutex)
+		imageLocks[alias] = lock
+	}
+	imageLocksMutex.Unlock()
+	lock.Lock()
+	return lock.Unlock
+}
+
+// generateImageMetadata returns the metadata tarball of a split LXD
+// image for the given series and architecture.
+func generateImageMetadata(series, arch string) ([]byte, error) {
+	lxdArch, ok := lxdArchitectures[arch]
+	if !ok {
+		return nil, errors.NotSupportedf("architecture %q", arch)
+	}
+	metadata := imageMetadata{
+		Architecture: lxdArch,
+		CreationDate: time.Now().Unix(),
+		Properties: map[string]string{
+			"os":           "ubuntu",
+			"release":      series,
+			"architecture": arch,
+			"description":  fmt.Sprintf("Ubuntu %s %s (juju)", series, arch),
+		},
+		Templates: map[string]imageTemplate{
+			"/var/lib/cloud/seed/nocloud-net/meta-data": {
+				When:     []string{"create", "copy"},
+				Template: "cloud-init-meta.tpl",
+			},
+			"/var/lib/cloud/seed/nocloud-net/user-data": {
+				When:     []string{"create", "copy"},
+				Template: "cloud-init-user.tpl",
+			},
+		},
+	}
+	metadataYAML, err := goyaml.Marshal(metadata)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	files := map[string]string{"metadata.yaml": string(metadataYAML)}
+	for name, content := range cloudInitTemplates {
+		files["templates/"+name] = content
+	}
+	for name, content := range files {
+		hdr := &tar.Header{
+			Name:    name,
+			Mode:    0644,
+			Size:    int64(len(content)),
+			ModTime: time.Now(),
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return nil, errors.Trace(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,241 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/version"
+)
+
+var logger = loggo.GetLogger("juju.container.lxd")
+
+const (
+	// DefaultLxdBridge is the bridge LXD connects containers to in
+	// its default profile.
+	DefaultLxdBridge = "lxcbr0"
+
+	// statusRunning is the status LXD reports for a running container.
+	statusRunning = "Running"
+)
+
+var (
+	// LxdSocket is the path of the unix socket the LXD daemon serves
+	// its API on.
+	LxdSocket = "/var/lib/lxd/unix.socket"
+
+	runtimeGOOS    = runtime.GOOS
+	releaseVersion = version.ReleaseVersion
+)
+
+// IsLXDSupported returns a boolean value indicating whether or not
+// we can run LXD containers. LXD is packaged from Ubuntu 15.04 onwards.
+func IsLXDSupported() (bool, error) {
+	if runtimeGOOS != "linux" {
+		return false, nil
+	}
+	release := releaseVersion()
+	if release == "" {
+		return false, errors.New("cannot determine the host's release")
+	}
+	value, err := strconv.ParseFloat(release, 64)
+	if err != nil {
+		return false, errors.Annotatef(err, "cannot parse release %q", release)
+	}
+	return value >= 15.04, nil
+}
+
+// containerManager creates and manages LXD containers through the LXD
+// daemon's REST API.
+type containerManager struct {
+	name           string
+	imageURLGetter container.ImageURLGetter
+	client         *client
+}
+
+var _ container.Manager = (*containerManager)(nil)
+
+// NewContainerManager returns a manager object that can start and
+// stop LXD containers. The containers that are created are namespaced
+// by the name parameter inside the given ManagerConfig.
+func NewContainerManager(conf container.ManagerConfig, imageURLGetter container.ImageURLGetter) (container.Manager, error) {
+	name := conf.PopValue(container.ConfigName)
+	if name == "" {
+		return nil, errors.Errorf("name is required")
+	}
+	// Containers log to their own filesystems; the host log directory
+	// is not shared with them.
+	conf.PopValue(container.ConfigLogDir)
+	conf.WarnAboutUnused()
+	return &containerManager{
+		name:           name,
+		imageURLGetter: imageURLGetter,
+		client:         newClient(LxdSocket),
+	}, nil
+}
+
+// CreateContainer creates and starts an LXD container.
+func (manager *containerManager) CreateContainer(
+	machineConfig *cloudinit.MachineConfig,
+	cons constraints.Value,
+	series string,
+	networkConfig *container.NetworkConfig,
+) (instance.Instance, *instance.HardwareCharacteristics, error) {
+	name := names.NewMachineTag(machineConfig.MachineId).String()
+	if manager.name != "" {
+		name = fmt.Sprintf("%s-%s", manager.name, name)
+	}
+
+	// Create the cloud-init.
+	directory, err := container.NewDirectory(name)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to create a directory for the container")
+	}
+	logger.Tracef("write cloud-init")
+	userDataFilename, err := container.WriteUserData(machineConfig, networkConfig, directory)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to write user data")
+	}
+	userData, err := ioutil.ReadFile(userDataFilename)
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to read user data")
+	}
+
+	alias, err := manager.ensureImage(series, arch.HostArch())
+	if err != nil {
+		return nil, nil, errors.Annotate(err, "failed to ensure image")
+	}
+
+	hardware := &instance.HardwareCharacteristics{
+		Arch: &version.Current.Arch,
+	}
+	config := resourceLimits(cons, hardware)
+	config["user.user-data"] = string(userData)
+	spec := containerSpec{
+		Name:    name,
+		Source:  containerSource{Type: "image", Alias: alias},
+		Config:  config,
+		Devices: networkDevices(networkConfig),
+	}
+	logger.Debugf("creating lxd container %q from image %q", name, alias)
+	if err := manager.client.createContainer(spec); err != nil {
+		return nil, nil, errors.Annotate(err, "lxd container creation failed")
+	}
+	if err := manager.client.startContainer(name); err != nil {
+		logger.Warningf("container failed to start: %v", err)
+		if derr := manager.client.deleteContainer(name); derr != nil {
+			logger.Errorf("container failed to start and failed to delete: %v", derr)
+			return nil, nil, errors.Annotate(err, "container failed to start and failed to delete: manual cleanup of containers needed")
+		}
+		return nil, nil, errors.Wrap(err, instance.NewRetryableCreationError("container failed to start and was deleted: "+name))
+	}
+	logger.Tracef("lxd container %q started", name)
+	return &lxdInstance{name, manager.client}, hardware, nil
+}
+
+// resourceLimits returns the container config limiting its memory and
+// CPU usage according to the given constraints, and records the limits
+// applied in hardware.
+func resourceLimits(cons constraints.Value, hardware *instance.HardwareCharacteristics) map[string]string {
+	config := make(map[string]string)
+	if cons.Mem != nil && *cons.Mem > 0 {
+		mem := *cons.Mem
+		config["limits.memory"] = fmt.Sprintf("%dMB", mem)
+		hardware.Mem = &mem
+	}
+	if cons.CpuCores != nil && *cons.CpuCores > 0 {
+		cores := *cons.CpuCores
+		config["limits.cpu"] = fmt.Sprint(cores)
+		hardware.CpuCores = &cores
+	}
+	if cons.RootDisk != nil {
+		logger.Infof("root-disk constraint of %dM being ignored as not supported", *cons.RootDisk)
+	}
+	if cons.CpuPower != nil {
+		logger.Infof("cpu-power constraint of %v being ignored as not supported", *cons.CpuPower)
+	}
+	return config
+}
+
+// networkDevices returns the devices connecting a container to the
+// network described by networkConfig. When no bridge is specified the
+// container is connected as described by LXD's default profile.
+func networkDevices(networkConfig *container.NetworkConfig) map[string]map[string]string {
+	if networkConfig == nil || networkConfig.NetworkType != container.BridgeNetwork {
+		return nil
+	}
+	return map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  networkConfig.Device,
+			"name":    "eth0",
+		},
+	}
+}
+
+// DestroyContainer stops and removes the container identified by the
+// instance id.
+func (manager *containerManager) DestroyContainer(id instance.Id) error {
+	name := string(id)
+	status, err := manager.client.containerStatus(name)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get status of container %q", name)
+	}
+	if status == statusRunning {
+		if err := manager.client.stopContainer(name); err != nil {
+			return errors.Annotatef(err, "cannot stop container %q", name)
+		}
+	}
+	if err := manager.client.deleteContainer(name); err != nil {
+		return errors.Annotatef(err, "cannot delete container %q", name)
+	}
+	return container.RemoveDirectory(name)
+}
+
+// ListContainers returns the running containers started by this
+// manager.
+func (manager *containerManager) ListContainers() ([]instance.Instance, error) {
+	names, err := manager.client.containerNames()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot list containers")
+	}
+	managerPrefix := fmt.Sprintf("%s-", manager.name)
+	var result []instance.Instance
+	for _, name := range names {
+		// Filter out those not starting with our name.
+		if !strings.HasPrefix(name, managerPrefix) {
+			continue
+		}
+		status, err := manager.client.containerStatus(name)
+		if err != nil {
+			logger.Warningf("cannot get status of container %q: %v", name, err)
+			continue
+		}
+		if status == statusRunning {
+			result = append(result, &lxdInstance{name, manager.client})
+		}
+	}
+	return result, nil
+}
+
+// IsInitialized reports whether the LXD daemon is available.
+func (manager *containerManager) IsInitialized() bool {
+	_, err := os.Stat(LxdSocket)
+	return err == nil
+}
@@ -0,0 +1,186 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	containertesting "github.com/juju/juju/container/testing"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/arch"
+	"github.com/juju/juju/version"
+)
+
+type LxdSuite struct {
+	lxdtesting.TestSuite
+}
+
+var _ = gc.Suite(&LxdSuite{})
+
+func (s *LxdSuite) makeManager(c *gc.C, name string) container.Manager {
+	params := container.ManagerConfig{
+		container.ConfigName: name,
+	}
+	manager, err := lxd.NewContainerManager(params, s.ImageURLGetter)
+	c.Assert(err, jc.ErrorIsNil)
+	return manager
+}
+
+func (*LxdSuite) TestManagerNameNeeded(c *gc.C) {
+	manager, err := lxd.NewContainerManager(container.ManagerConfig{container.ConfigName: ""}, nil)
+	c.Assert(err, gc.ErrorMatches, "name is required")
+	c.Assert(manager, gc.IsNil)
+}
+
+func (s *LxdSuite) TestIsLXDSupported(c *gc.C) {
+	for i, test := range []struct {
+		goos      string
+		release   string
+		supported bool
+	}{
+		{"linux", "14.04", false},
+		{"linux", "15.04", true},
+		{"linux", "15.10", true},
+		{"windows", "15.10", false},
+	} {
+		c.Logf("test %d: %s %s", i, test.goos, test.release)
+		release := test.release
+		s.PatchValue(lxd.RuntimeGOOS, test.goos)
+		s.PatchValue(lxd.ReleaseVersion, func() string { return release })
+		supported, err := lxd.IsLXDSupported()
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(supported, gc.Equals, test.supported)
+	}
+}
+
+func (s *LxdSuite) TestCreateContainer(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	inst := containertesting.CreateContainer(c, manager, "1/lxd/0")
+
+	name := string(inst.Id())
+	c.Assert(name, gc.Equals, "test-machine-1-lxd-0")
+	c.Assert(inst.Status(), gc.Equals, "Running")
+
+	containers := s.Server.Containers()
+	c.Assert(containers, gc.HasLen, 1)
+	created := containers[name]
+	c.Assert(created.Image, gc.Equals, "juju-quantal-"+arch.HostArch())
+	c.Assert(created.Devices, jc.DeepEquals, map[string]map[string]string{
+		"eth0": {
+			"type":    "nic",
+			"nictype": "bridged",
+			"parent":  "nic42",
+			"name":    "eth0",
+		},
+	})
+
+	// The user data is passed to cloud-init through the container's
+	// config, and also kept on the host.
+	cloudInitFilename := filepath.Join(s.ContainerDir, name, "cloud-init")
+	data := containertesting.AssertCloudInit(c, cloudInitFilename)
+	c.Assert(created.Config["user.user-data"], gc.Equals, string(data))
+}
+
+func (s *LxdSuite) TestCreateContainerImportsImage(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	containertesting.CreateContainer(c, manager, "1/lxd/0")
+	containertesting.CreateContainer(c, manager, "1/lxd/1")
+
+	// The image is imported once, and reused.
+	c.Assert(s.Server.ImageUploads(), gc.Equals, 1)
+	images := s.Server.Images()
+	c.Assert(images, gc.HasLen, 1)
+	image := images["juju-quantal-"+arch.HostArch()]
+	c.Assert(string(image.RootFS), gc.Equals, lxdtesting.FakeRootFS)
+	c.Assert(image.Files["metadata.yaml"], jc.Contains, "/var/lib/cloud/seed/nocloud-net/user-data")
+	c.Assert(image.Files["templates/cloud-init-user.tpl"], gc.Equals, `{{ config_get("user.user-data", "") }}`)
+}
+
+func (s *LxdSuite) TestEnsureImageConcurrently(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	const n = 5
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func() {
+			_, err := lxd.EnsureImage(manager, "quantal", arch.HostArch())
+			errs <- err
+		}()
+	}
+	for i := 0; i < n; i++ {
+		c.Assert(<-errs, jc.ErrorIsNil)
+	}
+	c.Assert(s.Server.ImageUploads(), gc.Equals, 1)
+	c.Assert(s.Server.Images(), gc.HasLen, 1)
+}
+
+func (s *LxdSuite) TestCreateContainerWithConstraints(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	cons := constraints.MustParse("mem=2G cpu-cores=2 root-disk=10G")
+	inst, hardware := containertesting.CreateContainerWithConstraints(c, manager, "1/lxd/0", cons)
+
+	created := s.Server.Containers()[string(inst.Id())]
+	c.Assert(created.Config["limits.memory"], gc.Equals, "2048MB")
+	c.Assert(created.Config["limits.cpu"], gc.Equals, "2")
+	c.Assert(*hardware.Arch, gc.Equals, version.Current.Arch)
+	c.Assert(*hardware.Mem, gc.Equals, uint64(2048))
+	c.Assert(*hardware.CpuCores, gc.Equals, uint64(2))
+	c.Assert(hardware.RootDisk, gc.IsNil)
+}
+
+func (s *LxdSuite) TestDestroyContainer(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	inst := containertesting.CreateContainer(c, manager, "1/lxd/0")
+
+	err := manager.DestroyContainer(inst.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.Server.Containers(), gc.HasLen, 0)
+
+	name := string(inst.Id())
+	// Check that the container dir is no longer in the container dir
+	c.Assert(filepath.Join(s.ContainerDir, name), jc.DoesNotExist)
+	// but instead, in the removed container dir
+	c.Assert(filepath.Join(s.RemovedDir, name), jc.IsDirectory)
+}
+
+func (s *LxdSuite) TestListContainers(c *gc.C) {
+	foo := s.makeManager(c, "foo")
+	bar := s.makeManager(c, "bar")
+
+	foo1 := containertesting.CreateContainer(c, foo, "1/lxd/0")
+	foo2 := containertesting.CreateContainer(c, foo, "1/lxd/1")
+	bar1 := containertesting.CreateContainer(c, bar, "1/lxd/2")
+	s.Server.AddContainer("foo-stopped", "Stopped")
+	s.Server.AddContainer("other", "Running")
+
+	result, err := foo.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceIds(result), jc.SameContents, []string{string(foo1.Id()), string(foo2.Id())})
+
+	result, err = bar.ListContainers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(instanceIds(result), jc.SameContents, []string{string(bar1.Id())})
+}
+
+func (s *LxdSuite) TestIsInitialized(c *gc.C) {
+	manager := s.makeManager(c, "test")
+	c.Assert(manager.IsInitialized(), jc.IsTrue)
+
+	s.PatchValue(&lxd.LxdSocket, filepath.Join(c.MkDir(), "missing"))
+	c.Assert(manager.IsInitialized(), jc.IsFalse)
+}
+
+func instanceIds(instances []instance.Instance) []string {
+	var ids []string
+	for _, inst := range instances {
+		ids = append(ids, string(inst.Id()))
+	}
+	return ids
+}
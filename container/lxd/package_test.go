@@ -0,0 +1,18 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package lxd_test
+
+import (
+	"runtime"
+	"testing"
+
+	gc "gopkg.in/check.v1"
+)
+
+func Test(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("LXD is currently not supported on windows")
+	}
+	gc.TestingT(t)
+}
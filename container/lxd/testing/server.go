@@ -0,0 +1,382 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Container records a container created through the fake server.
+type Container struct {
+	Name    string
+	Status  string
+	Image   string
+	Config  map[string]string
+	Devices map[string]map[string]string
+}
+
+// Image records an image uploaded to the fake server.
+type Image struct {
+	Fingerprint string
+	// Files holds the contents of the files in the metadata tarball,
+	// keyed by name.
+	Files map[string]string
+	// RootFS holds the contents of the root filesystem tarball.
+	RootFS []byte
+}
+
+// Server is a fake LXD daemon serving a subset of the LXD REST API on
+// a unix socket. Operations complete immediately.
+type Server struct {
+	SocketPath string
+
+	mu         sync.Mutex
+	listener   net.Listener
+	containers map[string]*Container
+	images     map[string]*Image
+	aliases    map[string]string
+	uploads    int
+	operations map[string]interface{}
+	nextOp     int
+}
+
+// NewServer starts a fake LXD server listening on the given socket
+// path.
+func NewServer(socketPath string) (*Server, error) {
+	listener, err := net.Listen("unix", socketPath)
+	if err != nil {
+		return nil, err
+	}
+	srv := &Server{
+		SocketPath: socketPath,
+		listener:   listener,
+		containers: make(map[string]*Container),
+		images:     make(map[string]*Image),
+		aliases:    make(map[string]string),
+		operations: make(map[string]interface{}),
+	}
+	go http.Serve(listener, srv)
+	return srv, nil
+}
+
+// Close stops the server.
+func (srv *Server) Close() error {
+	return srv.listener.Close()
+}
+
+// Containers returns the containers known to the server, keyed by name.
+func (srv *Server) Containers() map[string]Container {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	result := make(map[string]Container)
+	for name, c := range srv.containers {
+		result[name] = *c
+	}
+	return result
+}
+
+// AddContainer adds a container with the given name and status, as if
+// it had been created outside juju.
+func (srv *Server) AddContainer(name, status string) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	srv.containers[name] = &Container{Name: name, Status: status}
+}
+
+// Images returns the images uploaded to the server, keyed by alias.
+func (srv *Server) Images() map[string]Image {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	result := make(map[string]Image)
+	for alias, fingerprint := range srv.aliases {
+		result[alias] = *srv.images[fingerprint]
+	}
+	return result
+}
+
+// ImageUploads returns the number of images uploaded to the server.
+func (srv *Server) ImageUploads() int {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+	return srv.uploads
+}
+
+// ServeHTTP implements http.Handler.
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	srv.mu.Lock()
+	defer srv.mu.Unlock()
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 2 || parts[0] != "1.0" {
+		srv.sendError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch {
+	case parts[1] == "containers" && len(parts) == 2:
+		srv.serveContainers(w, r)
+	case parts[1] == "containers" && len(parts) == 3:
+		srv.serveContainer(w, r, parts[2])
+	case parts[1] == "containers" && len(parts) == 4 && parts[3] == "state":
+		srv.serveContainerState(w, r, parts[2])
+	case parts[1] == "images" && len(parts) == 2:
+		srv.serveImageUpload(w, r)
+	case parts[1] == "images" && len(parts) >= 3 && parts[2] == "aliases":
+		srv.serveImageAliases(w, r, strings.Join(parts[3:], "/"))
+	case parts[1] == "operations" && len(parts) == 4 && parts[3] == "wait":
+		srv.serveOperationWait(w, parts[2])
+	default:
+		srv.sendError(w, http.StatusNotFound, "not found")
+	}
+}
+
+func (srv *Server) serveContainers(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case "GET":
+		var urls []string
+		for name := range srv.containers {
+			urls = append(urls, "/1.0/containers/"+name)
+		}
+		srv.sendSync(w, urls)
+	case "POST":
+		var spec struct {
+			Name   string `json:"name"`
+			Source struct {
+				Type  string `json:"type"`
+				Alias string `json:"alias"`
+			} `json:"source"`
+			Config  map[string]string            `json:"config"`
+			Devices map[string]map[string]string `json:"devices"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+			srv.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := srv.containers[spec.Name]; ok {
+			srv.sendError(w, http.StatusConflict, "container already exists")
+			return
+		}
+		if _, ok := srv.aliases[spec.Source.Alias]; !ok {
+			srv.sendError(w, http.StatusNotFound, "image not found")
+			return
+		}
+		srv.containers[spec.Name] = &Container{
+			Name:    spec.Name,
+			Status:  "Stopped",
+			Image:   spec.Source.Alias,
+			Config:  spec.Config,
+			Devices: spec.Devices,
+		}
+		srv.sendAsync(w, nil)
+	default:
+		srv.sendError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveContainer(w http.ResponseWriter, r *http.Request, name string) {
+	container, ok := srv.containers[name]
+	if !ok {
+		srv.sendError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case "DELETE":
+		if container.Status == "Running" {
+			srv.sendError(w, http.StatusBadRequest, "container is running")
+			return
+		}
+		delete(srv.containers, name)
+		srv.sendAsync(w, nil)
+	default:
+		srv.sendError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveContainerState(w http.ResponseWriter, r *http.Request, name string) {
+	container, ok := srv.containers[name]
+	if !ok {
+		srv.sendError(w, http.StatusNotFound, "not found")
+		return
+	}
+	switch r.Method {
+	case "GET":
+		srv.sendSync(w, map[string]interface{}{
+			"status": container.Status,
+		})
+	case "PUT":
+		var change struct {
+			Action string `json:"action"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&change); err != nil {
+			srv.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch change.Action {
+		case "start":
+			container.Status = "Running"
+		case "stop":
+			container.Status = "Stopped"
+		default:
+			srv.sendError(w, http.StatusBadRequest, "unknown action")
+			return
+		}
+		srv.sendAsync(w, nil)
+	default:
+		srv.sendError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveImageUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != "POST" {
+		srv.sendError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+	reader, err := r.MultipartReader()
+	if err != nil {
+		srv.sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	image := &Image{Files: make(map[string]string)}
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			srv.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		data, err := ioutil.ReadAll(part)
+		if err != nil {
+			srv.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		switch part.FormName() {
+		case "metadata":
+			tr := tar.NewReader(bytes.NewReader(data))
+			for {
+				hdr, err := tr.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					srv.sendError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				content, err := ioutil.ReadAll(tr)
+				if err != nil {
+					srv.sendError(w, http.StatusBadRequest, err.Error())
+					return
+				}
+				image.Files[hdr.Name] = string(content)
+			}
+		case "rootfs":
+			image.RootFS = data
+		}
+	}
+	if _, ok := image.Files["metadata.yaml"]; !ok {
+		srv.sendError(w, http.StatusBadRequest, "missing metadata.yaml")
+		return
+	}
+	image.Fingerprint = fmt.Sprintf("%x", sha256.Sum256(image.RootFS))
+	srv.images[image.Fingerprint] = image
+	srv.uploads++
+	srv.sendAsync(w, map[string]string{"fingerprint": image.Fingerprint})
+}
+
+func (srv *Server) serveImageAliases(w http.ResponseWriter, r *http.Request, alias string) {
+	switch r.Method {
+	case "GET":
+		fingerprint, ok := srv.aliases[alias]
+		if !ok {
+			srv.sendError(w, http.StatusNotFound, "not found")
+			return
+		}
+		srv.sendSync(w, map[string]string{"name": alias, "target": fingerprint})
+	case "POST":
+		var newAlias struct {
+			Name   string `json:"name"`
+			Target string `json:"target"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&newAlias); err != nil {
+			srv.sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		if _, ok := srv.images[newAlias.Target]; !ok {
+			srv.sendError(w, http.StatusNotFound, "image not found")
+			return
+		}
+		if _, ok := srv.aliases[newAlias.Name]; ok {
+			srv.sendError(w, http.StatusConflict, "alias already exists")
+			return
+		}
+		srv.aliases[newAlias.Name] = newAlias.Target
+		srv.sendSync(w, nil)
+	default:
+		srv.sendError(w, http.StatusMethodNotAllowed, "method not allowed")
+	}
+}
+
+func (srv *Server) serveOperationWait(w http.ResponseWriter, id string) {
+	metadata, ok := srv.operations[id]
+	if !ok {
+		srv.sendError(w, http.StatusNotFound, "not found")
+		return
+	}
+	delete(srv.operations, id)
+	srv.sendSync(w, map[string]interface{}{
+		"id":          id,
+		"status":      "Success",
+		"status_code": 200,
+		"metadata":    metadata,
+	})
+}
+
+func (srv *Server) sendSync(w http.ResponseWriter, metadata interface{}) {
+	srv.send(w, http.StatusOK, map[string]interface{}{
+		"type":        "sync",
+		"status":      "Success",
+		"status_code": 200,
+		"metadata":    metadata,
+	})
+}
+
+func (srv *Server) sendAsync(w http.ResponseWriter, metadata interface{}) {
+	srv.nextOp++
+	id := fmt.Sprint(srv.nextOp)
+	srv.operations[id] = metadata
+	srv.send(w, http.StatusAccepted, map[string]interface{}{
+		"type":        "async",
+		"status":      "OK",
+		"status_code": 100,
+		"operation":   "/1.0/operations/" + id,
+	})
+}
+
+func (srv *Server) sendError(w http.ResponseWriter, code int, message string) {
+	srv.send(w, code, map[string]interface{}{
+		"type":       "error",
+		"error":      message,
+		"error_code": code,
+	})
+}
+
+func (srv *Server) send(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Functions defined in this file should *ONLY* be used for testing.  These
+// functions are exported for testing purposes only, and shouldn't be called
+// from code that isn't in a test file.
+
+package testing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/testing"
+)
+
+// FakeRootFS is the content served as the root filesystem tarball of
+// every image.
+const FakeRootFS = "fake root filesystem"
+
+// TestSuite replaces the LXD daemon that the manager talks to with a
+// fake server, and serves images from a local HTTP server.
+type TestSuite struct {
+	testing.BaseSuite
+	Server         *Server
+	ImageServer    *httptest.Server
+	ImageURLGetter container.ImageURLGetter
+	ContainerDir   string
+	RemovedDir     string
+}
+
+func (s *TestSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.ContainerDir = c.MkDir()
+	s.PatchValue(&container.ContainerDir, s.ContainerDir)
+	s.RemovedDir = c.MkDir()
+	s.PatchValue(&container.RemovedContainerDir, s.RemovedDir)
+
+	socketPath := filepath.Join(c.MkDir(), "unix.socket")
+	server, err := NewServer(socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Server = server
+	s.PatchValue(&lxd.LxdSocket, socketPath)
+
+	s.ImageServer = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(FakeRootFS))
+	}))
+	s.ImageURLGetter = &imageURLGetter{s.ImageServer.URL}
+}
+
+func (s *TestSuite) TearDownTest(c *gc.C) {
+	s.ImageServer.Close()
+	s.Server.Close()
+	s.BaseSuite.TearDownTest(c)
+}
+
+type imageURLGetter struct {
+	serverURL string
+}
+
+// ImageURL is specified on the container.ImageURLGetter interface.
+func (ug *imageURLGetter) ImageURL(kind instance.ContainerType, series, arch string) (string, error) {
+	return ug.serverURL + "/" + string(kind) + "/" + series + "/" + arch, nil
+}
+
+// CACert is specified on the container.ImageURLGetter interface.
+func (ug *imageURLGetter) CACert() []byte {
+	return nil
+}
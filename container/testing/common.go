@@ -10,6 +10,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/cloudinit"
@@ -65,13 +66,36 @@ func CreateContainerWithMachineAndNetworkConfig(
 	networkConfig *container.NetworkConfig,
 ) instance.Instance {
 
-	inst, hardware, err := manager.CreateContainer(machineConfig, "quantal", networkConfig)
+	inst, hardware, err := manager.CreateContainer(machineConfig, constraints.Value{}, "quantal", networkConfig)
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(hardware, gc.NotNil)
 	c.Assert(hardware.String(), gc.Not(gc.Equals), "")
 	return inst
 }
 
+// CreateContainerWithConstraints creates a container limited by the
+// given constraints, and returns it along with the hardware
+// characteristics reported for it.
+func CreateContainerWithConstraints(
+	c *gc.C,
+	manager container.Manager,
+	machineId string,
+	cons constraints.Value,
+) (instance.Instance, *instance.HardwareCharacteristics) {
+
+	machineConfig, err := MockMachineConfig(machineId)
+	c.Assert(err, jc.ErrorIsNil)
+	envConfig, err := config.New(config.NoDefaults, dummy.SampleConfig())
+	c.Assert(err, jc.ErrorIsNil)
+	machineConfig.Config = envConfig
+
+	networkConfig := container.BridgeNetworkConfig("nic42", nil)
+	inst, hardware, err := manager.CreateContainer(machineConfig, cons, "quantal", networkConfig)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hardware, gc.NotNil)
+	return inst, hardware
+}
+
 func AssertCloudInit(c *gc.C, filename string) []byte {
 	c.Assert(filename, jc.IsNonEmptyFile)
 	data, err := ioutil.ReadFile(filename)
@@ -96,7 +120,7 @@ func CreateContainerTest(c *gc.C, manager container.Manager, machineId string) (
 
 	network := container.BridgeNetworkConfig("nic42", nil)
 
-	inst, hardware, err := manager.CreateContainer(machineConfig, "quantal", network)
+	inst, hardware, err := manager.CreateContainer(machineConfig, constraints.Value{}, "quantal", network)
 
 	if err != nil {
 		return nil, errors.Trace(err)
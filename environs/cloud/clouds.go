@@ -0,0 +1,125 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package cloud reads and writes the cloud definitions and the
+// credentials shared by all environments on a client, so they need not
+// be repeated in each environments.yaml stanza.
+package cloud
+
+import (
+	"io/ioutil"
+	"os"
+	"sort"
+
+	"github.com/juju/errors"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// RegionKey is the environment attribute set to the name of the
+// selected region when the region defines no attributes of its own.
+const RegionKey = "region"
+
+// Cloud is the definition of a cloud: its provider type, the
+// environment attributes common to all its regions, such as endpoints,
+// and the regions environments can be created in.
+type Cloud struct {
+	// Type is the provider type of the cloud.
+	Type string `yaml:"type"`
+
+	// DefaultRegion is the region used when none is selected.
+	DefaultRegion string `yaml:"default-region,omitempty"`
+
+	// Config holds environment attributes common to all regions.
+	Config map[string]interface{} `yaml:"config,omitempty"`
+
+	// Regions holds the environment attributes specific to each
+	// region, by region name. If empty, any region is allowed.
+	Regions map[string]map[string]interface{} `yaml:"regions,omitempty"`
+}
+
+// RegionNames returns the sorted names of the cloud's regions.
+func (c Cloud) RegionNames() []string {
+	var names []string
+	for name := range c.Regions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// RegionConfig returns the environment attributes for the given region
+// of the cloud, or its default region if region is empty. The region's
+// own attributes are added to the cloud's; if it has none, the region
+// attribute is set to its name.
+func (c Cloud) RegionConfig(region string) (map[string]interface{}, error) {
+	if region == "" {
+		region = c.DefaultRegion
+	}
+	attrs := make(map[string]interface{})
+	for k, v := range c.Config {
+		attrs[k] = v
+	}
+	if region == "" {
+		return attrs, nil
+	}
+	regionAttrs, ok := c.Regions[region]
+	if !ok && len(c.Regions) > 0 {
+		return nil, errors.NotValidf("region %q", region)
+	}
+	if len(regionAttrs) == 0 {
+		attrs[RegionKey] = region
+	}
+	for k, v := range regionAttrs {
+		attrs[k] = v
+	}
+	return attrs, nil
+}
+
+// JujuCloudsPath returns the location of the cloud definitions file
+// in the juju home directory.
+func JujuCloudsPath() string {
+	return osenv.JujuHomePath("clouds.yaml")
+}
+
+// ParseClouds parses the contents of a cloud definitions file and
+// returns the clouds it defines, by name.
+func ParseClouds(data []byte) (map[string]Cloud, error) {
+	var raw struct {
+		Clouds map[string]Cloud `yaml:"clouds"`
+	}
+	if err := goyaml.Unmarshal(data, &raw); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, cloud := range raw.Clouds {
+		if cloud.Type == "" {
+			return nil, errors.NotValidf("cloud %q with no type", name)
+		}
+		if cloud.DefaultRegion != "" && len(cloud.Regions) > 0 {
+			if _, ok := cloud.Regions[cloud.DefaultRegion]; !ok {
+				return nil, errors.NotValidf("cloud %q default region %q", name, cloud.DefaultRegion)
+			}
+		}
+	}
+	if raw.Clouds == nil {
+		raw.Clouds = make(map[string]Cloud)
+	}
+	return raw.Clouds, nil
+}
+
+// ReadClouds reads the cloud definitions file at the given path. A
+// missing file defines no clouds.
+func ReadClouds(path string) (map[string]Cloud, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]Cloud), nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	clouds, err := ParseClouds(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %q", path)
+	}
+	return clouds, nil
+}
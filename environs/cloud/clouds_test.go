@@ -0,0 +1,110 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloud_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/cloud"
+	"github.com/juju/juju/testing"
+)
+
+type cloudsSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&cloudsSuite{})
+
+var cloudsYAML = `
+clouds:
+  aws:
+    type: ec2
+    default-region: us-east-1
+    regions:
+      us-east-1:
+      eu-west-1:
+  hpcloud:
+    type: openstack
+    config:
+      auth-mode: userpass
+    regions:
+      az-1:
+        auth-url: https://az-1.example.com/v2.0
+        region: region-a.geo-1
+  private:
+    type: openstack
+    config:
+      auth-url: https://keystone.example.com/v2.0
+`[1:]
+
+func (s *cloudsSuite) TestParseClouds(c *gc.C) {
+	clouds, err := cloud.ParseClouds([]byte(cloudsYAML))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clouds, gc.HasLen, 3)
+	c.Assert(clouds["aws"].Type, gc.Equals, "ec2")
+	c.Assert(clouds["aws"].RegionNames(), jc.DeepEquals, []string{"eu-west-1", "us-east-1"})
+	c.Assert(clouds["private"].RegionNames(), gc.HasLen, 0)
+}
+
+func (s *cloudsSuite) TestParseCloudsErrors(c *gc.C) {
+	_, err := cloud.ParseClouds([]byte("clouds:\n  aws:\n    regions:\n      us-east-1:\n"))
+	c.Assert(err, gc.ErrorMatches, `cloud "aws" with no type not valid`)
+
+	_, err = cloud.ParseClouds([]byte("clouds:\n  aws:\n    type: ec2\n    default-region: nowhere\n    regions:\n      us-east-1:\n"))
+	c.Assert(err, gc.ErrorMatches, `cloud "aws" default region "nowhere" not valid`)
+}
+
+func (s *cloudsSuite) TestRegionConfig(c *gc.C) {
+	clouds, err := cloud.ParseClouds([]byte(cloudsYAML))
+	c.Assert(err, jc.ErrorIsNil)
+
+	attrs, err := clouds["aws"].RegionConfig("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs, jc.DeepEquals, map[string]interface{}{"region": "us-east-1"})
+
+	attrs, err = clouds["aws"].RegionConfig("eu-west-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs, jc.DeepEquals, map[string]interface{}{"region": "eu-west-1"})
+
+	_, err = clouds["aws"].RegionConfig("nowhere")
+	c.Assert(err, gc.ErrorMatches, `region "nowhere" not valid`)
+
+	attrs, err = clouds["hpcloud"].RegionConfig("az-1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs, jc.DeepEquals, map[string]interface{}{
+		"auth-mode": "userpass",
+		"auth-url":  "https://az-1.example.com/v2.0",
+		"region":    "region-a.geo-1",
+	})
+
+	// Any region is allowed when the cloud doesn't list them.
+	attrs, err = clouds["private"].RegionConfig("regionOne")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(attrs, jc.DeepEquals, map[string]interface{}{
+		"auth-url": "https://keystone.example.com/v2.0",
+		"region":   "regionOne",
+	})
+}
+
+func (s *cloudsSuite) TestReadClouds(c *gc.C) {
+	clouds, err := cloud.ReadClouds(cloud.JujuCloudsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clouds, gc.HasLen, 0)
+
+	path := filepath.Join(c.MkDir(), "clouds.yaml")
+	err = ioutil.WriteFile(path, []byte(cloudsYAML), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	clouds, err = cloud.ReadClouds(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(clouds, gc.HasLen, 3)
+
+	err = ioutil.WriteFile(path, []byte("clouds: [\n"), 0644)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = cloud.ReadClouds(path)
+	c.Assert(err, gc.ErrorMatches, `cannot parse ".*clouds.yaml": .*`)
+}
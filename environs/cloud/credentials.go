@@ -0,0 +1,99 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloud
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	goyaml "gopkg.in/yaml.v1"
+
+	"github.com/juju/juju/juju/osenv"
+)
+
+// Credential holds the environment attributes used to authenticate
+// with clouds of one provider type, such as access and secret keys.
+type Credential struct {
+	// Type is the provider type the credential is for.
+	Type string `yaml:"type"`
+
+	// Attributes holds the environment attributes set by the
+	// credential.
+	Attributes map[string]string `yaml:"attributes"`
+}
+
+// JujuCredentialsPath returns the location of the credentials file in
+// the juju home directory.
+func JujuCredentialsPath() string {
+	return osenv.JujuHomePath("credentials.yaml")
+}
+
+type credentialsDoc struct {
+	Credentials map[string]Credential `yaml:"credentials"`
+}
+
+// ParseCredentials parses the contents of a credentials file and
+// returns the credentials it holds, by name.
+func ParseCredentials(data []byte) (map[string]Credential, error) {
+	var doc credentialsDoc
+	if err := goyaml.Unmarshal(data, &doc); err != nil {
+		return nil, errors.Trace(err)
+	}
+	for name, cred := range doc.Credentials {
+		if err := cred.Validate(); err != nil {
+			return nil, errors.Annotatef(err, "credential %q", name)
+		}
+	}
+	if doc.Credentials == nil {
+		doc.Credentials = make(map[string]Credential)
+	}
+	return doc.Credentials, nil
+}
+
+// Validate returns an error if the credential is not usable.
+func (c Credential) Validate() error {
+	if c.Type == "" {
+		return errors.NotValidf("empty provider type")
+	}
+	if len(c.Attributes) == 0 {
+		return errors.NotValidf("empty attributes")
+	}
+	return nil
+}
+
+// ReadCredentials reads the credentials file at the given path. A
+// missing file holds no credentials.
+func ReadCredentials(path string) (map[string]Credential, error) {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return make(map[string]Credential), nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	creds, err := ParseCredentials(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot parse %q", path)
+	}
+	return creds, nil
+}
+
+// WriteCredentials replaces the credentials file at the given path
+// with one holding the given credentials. The file is only readable by
+// its owner.
+func WriteCredentials(path string, creds map[string]Credential) error {
+	data, err := goyaml.Marshal(credentialsDoc{creds})
+	if err != nil {
+		return errors.Annotate(err, "cannot marshal credentials")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return errors.Trace(err)
+	}
+	if err := utils.AtomicWriteFile(path, data, 0600); err != nil {
+		return errors.Annotatef(err, "cannot write %q", path)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package cloud_test
+
+import (
+	"os"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/environs/cloud"
+	"github.com/juju/juju/testing"
+)
+
+type credentialsSuite struct {
+	testing.FakeJujuHomeSuite
+}
+
+var _ = gc.Suite(&credentialsSuite{})
+
+func (s *credentialsSuite) TestReadMissing(c *gc.C) {
+	creds, err := cloud.ReadCredentials(cloud.JujuCredentialsPath())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(creds, gc.HasLen, 0)
+}
+
+func (s *credentialsSuite) TestWriteRead(c *gc.C) {
+	creds := map[string]cloud.Credential{
+		"work": {
+			Type: "ec2",
+			Attributes: map[string]string{
+				"access-key": "key",
+				"secret-key": "secret",
+			},
+		},
+	}
+	path := cloud.JujuCredentialsPath()
+	err := cloud.WriteCredentials(path, creds)
+	c.Assert(err, jc.ErrorIsNil)
+
+	info, err := os.Stat(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0600))
+
+	read, err := cloud.ReadCredentials(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(read, jc.DeepEquals, creds)
+}
+
+func (s *credentialsSuite) TestParseCredentialsInvalid(c *gc.C) {
+	_, err := cloud.ParseCredentials([]byte("credentials:\n  work:\n    attributes:\n      access-key: key\n"))
+	c.Assert(err, gc.ErrorMatches, `credential "work": empty provider type not valid`)
+
+	_, err = cloud.ParseCredentials([]byte("credentials:\n  work:\n    type: ec2\n"))
+	c.Assert(err, gc.ErrorMatches, `credential "work": empty attributes not valid`)
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/juju/loggo"
 	goyaml "gopkg.in/yaml.v1"
 
+	"github.com/juju/juju/environs/cloud"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
 )
@@ -61,10 +62,31 @@ var disallowedWithBootstrap = []string{
 	config.StorageDefaultBlockSourceKey,
 }
 
+// CloudKey is the environments.yaml attribute naming the cloud, from
+// the client's cloud definitions file, an environment is created in.
+const CloudKey = "cloud"
+
+// CloudSelection selects the credential and region used for an
+// environment in place of any named in its environments.yaml stanza.
+type CloudSelection struct {
+	// Credential is the name of a credential in the client's
+	// credentials file.
+	Credential string
+
+	// Region is the name of a region of the environment's cloud.
+	Region string
+}
+
 // Config returns the environment configuration for the environment
 // with the given name. If the configuration is not
 // found, an errors.NotFoundError is returned.
 func (envs *Environs) Config(name string) (*config.Config, error) {
+	return envs.ConfigWithSelection(name, CloudSelection{})
+}
+
+// ConfigWithSelection is like Config, but uses the credential and
+// region in sel, when set, in place of those named by the environment.
+func (envs *Environs) ConfigWithSelection(name string, sel CloudSelection) (*config.Config, error) {
 	if name == "" {
 		name = envs.Default
 		if name == "" {
@@ -75,6 +97,10 @@ func (envs *Environs) Config(name string) (*config.Config, error) {
 	if !ok {
 		return nil, errors.NotFoundf("environment %q", name)
 	}
+	attrs, err := resolveCloudAttrs(attrs, sel)
+	if err != nil {
+		return nil, errors.Annotatef(err, "environment %q", name)
+	}
 	if err := validateEnvironmentKind(attrs); err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -118,6 +144,122 @@ func (envs *Environs) Config(name string) (*config.Config, error) {
 	return cfg, nil
 }
 
+// resolveCloudAttrs returns a copy of the given environment attributes
+// with those of the cloud and credential they name added. Attributes
+// set by the environment take precedence over the cloud's, but cannot
+// also be set by the credential.
+func resolveCloudAttrs(attrs map[string]interface{}, sel CloudSelection) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for k, v := range attrs {
+		result[k] = v
+	}
+	delete(result, CloudKey)
+	if cloudName, _ := attrs[CloudKey].(string); cloudName != "" {
+		clouds, err := cloud.ReadClouds(cloud.JujuCloudsPath())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		c, ok := clouds[cloudName]
+		if !ok {
+			return nil, errors.NotFoundf("cloud %q", cloudName)
+		}
+		if envType, _ := attrs["type"].(string); envType != "" && envType != c.Type {
+			return nil, errors.Errorf("type %q does not match cloud %q type %q", envType, cloudName, c.Type)
+		}
+		result["type"] = c.Type
+
+		// The environment's region names one of the cloud's
+		// regions, which defines the provider's region attributes.
+		region, _ := attrs[cloud.RegionKey].(string)
+		if sel.Region != "" {
+			region = sel.Region
+		}
+		delete(result, cloud.RegionKey)
+		cloudAttrs, err := c.RegionConfig(region)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cloud %q", cloudName)
+		}
+		for k, v := range cloudAttrs {
+			if _, ok := result[k]; !ok {
+				result[k] = v
+			}
+		}
+	} else if sel.Region != "" {
+		result[cloud.RegionKey] = sel.Region
+	}
+
+	credName, _ := attrs[config.CredentialKey].(string)
+	if sel.Credential != "" {
+		credName = sel.Credential
+	}
+	if credName == "" {
+		return result, nil
+	}
+	delete(result, config.CredentialKey)
+	cred, err := readCredential(credName, result)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	for k, v := range cred.Attributes {
+		if _, ok := result[k]; ok {
+			return nil, errors.Errorf("attribute %q set by both the environment and credential %q", k, credName)
+		}
+		result[k] = v
+	}
+	result[config.CredentialKey] = credName
+	return result, nil
+}
+
+// readCredential returns the credential with the given name from the
+// client's credentials file, checking it is for the provider type of
+// the environment with the given attributes.
+func readCredential(name string, attrs map[string]interface{}) (cloud.Credential, error) {
+	creds, err := cloud.ReadCredentials(cloud.JujuCredentialsPath())
+	if err != nil {
+		return cloud.Credential{}, errors.Trace(err)
+	}
+	cred, ok := creds[name]
+	if !ok {
+		return cloud.Credential{}, errors.NotFoundf("credential %q", name)
+	}
+	if envType, _ := attrs["type"].(string); cred.Type != envType {
+		return cloud.Credential{}, errors.Errorf(
+			"credential %q is for provider type %q, not %q", name, cred.Type, envType,
+		)
+	}
+	return cred, nil
+}
+
+// withCurrentCredential returns a copy of the given bootstrap config
+// attributes with the attributes of the credential the environment was
+// prepared with replaced by the credential's current ones, so rotated
+// keys are used without editing the environment's information. If the
+// credential cannot be read, for example because it was removed or
+// renamed, the attributes it was prepared with are used instead.
+//
+// This only affects the client: the environment configuration held by
+// a bootstrapped environment's state server is not changed, and must
+// be updated with "juju set-environment".
+func withCurrentCredential(attrs map[string]interface{}) map[string]interface{} {
+	credName, _ := attrs[config.CredentialKey].(string)
+	if credName == "" {
+		return attrs
+	}
+	cred, err := readCredential(credName, attrs)
+	if err != nil {
+		logger.Warningf("using the environment's stored credential attributes: %v", err)
+		return attrs
+	}
+	result := make(map[string]interface{})
+	for k, v := range attrs {
+		result[k] = v
+	}
+	for k, v := range cred.Attributes {
+		result[k] = v
+	}
+	return result
+}
+
 func (envs *Environs) logBlockDeprecationWarnings(attrs map[string]interface{}) {
 	checkBlockVar := func(key string) {
 		if used, ok := attrs[key]; ok {
@@ -6,6 +6,7 @@ package config
 import (
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -107,6 +108,9 @@ const (
 	// AgentMetadataURLKey stores the key for this setting.
 	AgentMetadataURLKey = "agent-metadata-url"
 
+	// CharmStoreURLKey stores the key for this setting.
+	CharmStoreURLKey = "charm-store-url"
+
 	// HttpProxyKey stores the key for this setting.
 	HttpProxyKey = "http-proxy"
 
@@ -150,6 +154,10 @@ const (
 	// The default block storage source.
 	StorageDefaultBlockSourceKey = "storage-default-block-source"
 
+	// CredentialKey stores the name of the credential, from the
+	// client's credentials file, the environment was prepared with.
+	CredentialKey = "credential"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -586,6 +594,14 @@ func Validate(cfg, old *Config) error {
 			" of key-value pairs, not %q", authToken)
 	}
 
+	// Ensure that the charm store URL is an absolute HTTP(S) URL.
+	if storeURL, ok := cfg.CharmStoreURL(); ok {
+		u, err := url.Parse(storeURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid charm store URL in environment configuration: %q", storeURL)
+		}
+	}
+
 	// Ensure that the given harvesting method is valid.
 	if hvstMeth, ok := cfg.defined[ProvisionerHarvestModeKey].(string); ok {
 		if _, err := ParseHarvestMode(hvstMeth); err != nil {
@@ -1051,6 +1067,13 @@ func (c *Config) CharmStoreAuth() (string, bool) {
 	return auth, auth != ""
 }
 
+// CharmStoreURL returns the URL of the charm store that the environment
+// uses in place of the public charm store, and whether it has been set.
+func (c *Config) CharmStoreURL() (string, bool) {
+	url := c.asString(CharmStoreURLKey)
+	return url, url != ""
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -1124,6 +1147,13 @@ func (c *Config) StorageDefaultBlockSource() (string, bool) {
 	return bs, bs != ""
 }
 
+// Credential returns the name of the credential the environment was
+// prepared with, and whether one was used.
+func (c *Config) Credential() (string, bool) {
+	name := c.asString(CredentialKey)
+	return name, name != ""
+}
+
 // UnknownAttrs returns a copy of the raw configuration attributes
 // that are supposedly specific to the environment type. They could
 // also be wrong attributes, though. Only the specific environment
@@ -1190,6 +1220,7 @@ var fields = schema.Fields{
 	"rsyslog-ca-key":             schema.String(),
 	"logging-config":             schema.String(),
 	"charm-store-auth":           schema.String(),
+	CharmStoreURLKey:             schema.String(),
 	ProvisionerHarvestModeKey:    schema.String(),
 	HttpProxyKey:                 schema.String(),
 	HttpsProxyKey:                schema.String(),
@@ -1215,6 +1246,7 @@ var fields = schema.Fields{
 	PreventRemoveObjectKey:       schema.Bool(),
 	PreventAllChangesKey:         schema.Bool(),
 	StorageDefaultBlockSourceKey: schema.String(),
+	CredentialKey:                schema.String(),
 
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:    schema.String(),
@@ -1256,12 +1288,16 @@ var alwaysOptional = schema.Defaults{
 	LxcClone:                     schema.Omit,
 	"disable-network-management": schema.Omit,
 	AgentStreamKey:               schema.Omit,
+	CharmStoreURLKey:             schema.Omit,
 	SetNumaControlPolicyKey:      DefaultNumaControlPolicy,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
 	StorageDefaultBlockSourceKey: schema.Omit,
 
+	// The credential is only set if one was used.
+	CredentialKey: schema.Omit,
+
 	// Deprecated fields, retain for backwards compatibility.
 	ToolsMetadataURLKey:          "",
 	LxcUseClone:                  schema.Omit,
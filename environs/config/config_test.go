@@ -890,6 +890,23 @@ var configTests = []configTest{
 			"name":       "my-name",
 			"apt-mirror": "http://my.archive.ubuntu.com",
 		},
+	}, {
+		about:       "Explicit charm-store-url",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"charm-store-url": "https://10.0.0.1:17070/environment/some-uuid/charmstore",
+		},
+	}, {
+		about:       "Invalid charm-store-url",
+		useDefaults: config.UseDefaults,
+		attrs: testing.Attrs{
+			"type":            "my-type",
+			"name":            "my-name",
+			"charm-store-url": "10.0.0.1/charmstore",
+		},
+		err: `invalid charm store URL in environment configuration: "10.0.0.1/charmstore"`,
 	},
 }
 
@@ -1243,6 +1260,14 @@ func (test configTest) check(c *gc.C, home *gitjujutesting.FakeHome) {
 		c.Assert(urlPresent, jc.IsFalse)
 	}
 
+	storeURL, urlPresent := cfg.CharmStoreURL()
+	if v, _ := test.attrs["charm-store-url"].(string); v != "" {
+		c.Assert(storeURL, gc.Equals, v)
+		c.Assert(urlPresent, jc.IsTrue)
+	} else {
+		c.Assert(urlPresent, jc.IsFalse)
+	}
+
 	toolsURL, urlPresent := cfg.AgentMetadataURL()
 	oldToolsURL := cfg.AllAttrs()["tools-metadata-url"]
 	oldToolsURLAttrValue, oldTSTPresent := test.attrs["tools-metadata-url"]
@@ -5,6 +5,7 @@ package environs_test
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -17,6 +18,7 @@ import (
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/cloud"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/provider/dummy"
 	_ "github.com/juju/juju/provider/manual"
@@ -325,6 +327,114 @@ environments:
 	}
 }
 
+func (s *suite) writeCloudsAndCredentials(c *gc.C) {
+	clouds := `
+clouds:
+  dummycloud:
+    type: dummy
+    default-region: north
+    config:
+      state-server: false
+    regions:
+      north:
+      south:
+        region: far-south
+`
+	err := ioutil.WriteFile(cloud.JujuCloudsPath(), []byte(clouds), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	err = cloud.WriteCredentials(cloud.JujuCredentialsPath(), map[string]cloud.Credential{
+		"mine": {
+			Type:       "dummy",
+			Attributes: map[string]string{"secret": "rotated"},
+		},
+		"other": {
+			Type:       "ec2",
+			Attributes: map[string]string{"access-key": "key", "secret-key": "secret"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+var cloudEnvirons = `
+environments:
+    dummy:
+        cloud: dummycloud
+        credential: mine
+        authorized-keys: i-am-a-key
+    dummy-south:
+        cloud: dummycloud
+        region: south
+        authorized-keys: i-am-a-key
+    dummy-own-secret:
+        type: dummy
+        state-server: false
+        credential: mine
+        secret: mine
+        authorized-keys: i-am-a-key
+    dummy-unknown-cloud:
+        cloud: nowhere
+        authorized-keys: i-am-a-key
+`
+
+func (s *suite) TestConfigWithCloudAndCredential(c *gc.C) {
+	s.writeCloudsAndCredentials(c)
+	envs, err := environs.ReadEnvironsBytes([]byte(cloudEnvirons))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := envs.Config("dummy")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.Type(), gc.Equals, "dummy")
+	attrs := cfg.AllAttrs()
+	c.Assert(attrs["state-server"], jc.IsFalse)
+	c.Assert(attrs["region"], gc.Equals, "north")
+	c.Assert(attrs["secret"], gc.Equals, "rotated")
+	credential, ok := cfg.Credential()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(credential, gc.Equals, "mine")
+
+	cfg, err = envs.Config("dummy-south")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["region"], gc.Equals, "far-south")
+	_, ok = cfg.Credential()
+	c.Assert(ok, jc.IsFalse)
+}
+
+func (s *suite) TestConfigWithSelection(c *gc.C) {
+	s.writeCloudsAndCredentials(c)
+	envs, err := environs.ReadEnvironsBytes([]byte(cloudEnvirons))
+	c.Assert(err, jc.ErrorIsNil)
+
+	cfg, err := envs.ConfigWithSelection("dummy-south", environs.CloudSelection{
+		Credential: "mine",
+		Region:     "north",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	attrs := cfg.AllAttrs()
+	c.Assert(attrs["region"], gc.Equals, "north")
+	c.Assert(attrs["secret"], gc.Equals, "rotated")
+
+	_, err = envs.ConfigWithSelection("dummy", environs.CloudSelection{Region: "east"})
+	c.Assert(err, gc.ErrorMatches, `environment "dummy": cloud "dummycloud": region "east" not valid`)
+
+	_, err = envs.ConfigWithSelection("dummy", environs.CloudSelection{Credential: "other"})
+	c.Assert(err, gc.ErrorMatches, `environment "dummy": credential "other" is for provider type "ec2", not "dummy"`)
+
+	_, err = envs.ConfigWithSelection("dummy", environs.CloudSelection{Credential: "missing"})
+	c.Assert(err, gc.ErrorMatches, `environment "dummy": credential "missing" not found`)
+}
+
+func (s *suite) TestConfigWithCloudAndCredentialErrors(c *gc.C) {
+	s.writeCloudsAndCredentials(c)
+	envs, err := environs.ReadEnvironsBytes([]byte(cloudEnvirons))
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = envs.Config("dummy-own-secret")
+	c.Assert(err, gc.ErrorMatches, `environment "dummy-own-secret": attribute "secret" set by both the environment and credential "mine"`)
+
+	_, err = envs.Config("dummy-unknown-cloud")
+	c.Assert(err, gc.ErrorMatches, `environment "dummy-unknown-cloud": cloud "nowhere" not found`)
+}
+
 type dummyProvider struct {
 	environs.EnvironProvider
 }
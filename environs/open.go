@@ -68,7 +68,8 @@ func ConfigForName(name string, store configstore.Storage) (*config.Config, Conf
 			return nil, ConfigFromNowhere, EmptyConfig{fmt.Errorf("environment has no bootstrap configuration data")}
 		}
 		logger.Debugf("ConfigForName found bootstrap config %#v", info.BootstrapConfig())
-		cfg, err := config.New(config.NoDefaults, info.BootstrapConfig())
+		attrs := withCurrentCredential(info.BootstrapConfig())
+		cfg, err := config.New(config.NoDefaults, attrs)
 		return cfg, ConfigFromInfo, err
 	} else if !errors.IsNotFound(err) {
 		return nil, ConfigFromInfo, fmt.Errorf("cannot read environment info for %q: %v", name, err)
@@ -130,6 +131,30 @@ func prepareFromNameProductionFunc(name string, ctx BootstrapContext, store conf
 	return Prepare(cfg, ctx, store)
 }
 
+// PrepareFromNameWithSelection is like PrepareFromName, but uses the
+// credential and region in sel in place of any named by the
+// environment in the default environments file. As those are fixed
+// once an environment is prepared, it fails if it already is.
+func PrepareFromNameWithSelection(name string, sel CloudSelection, ctx BootstrapContext, store configstore.Storage) (Environ, error) {
+	envs, err := ReadEnvirons("")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = envs.Default
+	}
+	if _, err := store.ReadInfo(name); err == nil {
+		return nil, errors.Errorf("environment %q is already prepared: cannot select its credential or region", name)
+	} else if !errors.IsNotFound(err) {
+		return nil, errors.Annotatef(err, "cannot read environment info for %q", name)
+	}
+	cfg, err := envs.ConfigWithSelection(name, sel)
+	if err != nil {
+		return nil, err
+	}
+	return Prepare(cfg, ctx, store)
+}
+
 // NewFromAttrs returns a new environment based on the provided configuration
 // attributes.
 // TODO(rog) remove this function - it's almost always wrong to use it.
@@ -177,7 +202,8 @@ func Prepare(cfg *config.Config, ctx BootstrapContext, store configstore.Storage
 	} else if len(info.BootstrapConfig()) == 0 {
 		return nil, errors.New("found environment info but no bootstrap config")
 	} else {
-		cfg, err = config.New(config.NoDefaults, info.BootstrapConfig())
+		attrs := withCurrentCredential(info.BootstrapConfig())
+		cfg, err = config.New(config.NoDefaults, attrs)
 		if err != nil {
 			return nil, errors.Annotate(err, "cannot parse bootstrap config")
 		}
@@ -11,6 +11,7 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/environs/bootstrap"
+	"github.com/juju/juju/environs/cloud"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/environs/configstore"
 	"github.com/juju/juju/environs/filestorage"
@@ -122,6 +123,46 @@ func (*OpenSuite) TestPrepareFromName(c *gc.C) {
 	c.Assert(e.Config().Name(), gc.Equals, "erewhemos")
 }
 
+func (*OpenSuite) TestPrepareFromNameWithSelection(c *gc.C) {
+	writeCredential := func(secret string) {
+		err := cloud.WriteCredentials(cloud.JujuCredentialsPath(), map[string]cloud.Credential{
+			"mine": {
+				Type:       "dummy",
+				Attributes: map[string]string{"secret": secret},
+			},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	writeCredential("first")
+	store := configstore.NewMem()
+	ctx := envtesting.BootstrapContext(c)
+	sel := environs.CloudSelection{Credential: "mine"}
+	e, err := environs.PrepareFromNameWithSelection("erewhemos", sel, ctx, store)
+	c.Assert(err, jc.ErrorIsNil)
+	credential, ok := e.Config().Credential()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(credential, gc.Equals, "mine")
+	c.Assert(e.Config().AllAttrs()["secret"], gc.Equals, "first")
+
+	// Rotating the credential is reflected in the prepared environment.
+	writeCredential("second")
+	cfg, source, err := environs.ConfigForName("erewhemos", store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(source, gc.Equals, environs.ConfigFromInfo)
+	c.Assert(cfg.AllAttrs()["secret"], gc.Equals, "second")
+
+	// Without the credential, the prepared attributes are used.
+	err = cloud.WriteCredentials(cloud.JujuCredentialsPath(), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, _, err = environs.ConfigForName("erewhemos", store)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cfg.AllAttrs()["secret"], gc.Equals, "first")
+	c.Assert(c.GetTestLog(), jc.Contains, `using the environment's stored credential attributes: credential "mine" not found`)
+
+	_, err = environs.PrepareFromNameWithSelection("erewhemos", sel, ctx, store)
+	c.Assert(err, gc.ErrorMatches, `environment "erewhemos" is already prepared: cannot select its credential or region`)
+}
+
 func (*OpenSuite) TestConfigForName(c *gc.C) {
 	cfg, source, err := environs.ConfigForName("erewhemos", configstore.NewMem())
 	c.Assert(err, jc.ErrorIsNil)
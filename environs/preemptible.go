@@ -0,0 +1,24 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package environs
+
+import (
+	"github.com/juju/juju/instance"
+)
+
+// ReclaimedInstanceStatus is the instance status recorded for a
+// machine whose preemptible instance has been reclaimed by the
+// provider.
+const ReclaimedInstanceStatus = "reclaimed"
+
+// InstanceReclaimer is implemented by environments that can start
+// preemptible instances (see the "preemptible" constraint), which the
+// provider may take back at any time.
+type InstanceReclaimer interface {
+	// ReclaimedInstances returns those of the given instance ids
+	// whose instances have been reclaimed by the provider. Ids of
+	// instances that are still running, or that are missing for
+	// any other reason, are not returned.
+	ReclaimedInstances(ids []instance.Id) ([]instance.Id, error)
+}
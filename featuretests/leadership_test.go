@@ -24,6 +24,7 @@ import (
 	agentcmd "github.com/juju/juju/cmd/jujud/agent"
 	agenttesting "github.com/juju/juju/cmd/jujud/agent/testing"
 	cmdutil "github.com/juju/juju/cmd/jujud/util"
+	coreleadership "github.com/juju/juju/leadership"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
@@ -135,7 +136,7 @@ func (s *leadershipSuite) TestClaimLeadership(c *gc.C) {
 
 	unblocked := make(chan struct{})
 	go func() {
-		err := client.BlockUntilLeadershipReleased(s.serviceId)
+		err := client.BlockUntilLeadershipReleased(s.serviceId, nil)
 		c.Check(err, gc.IsNil)
 		unblocked <- struct{}{}
 	}()
@@ -171,7 +172,7 @@ func (s *leadershipSuite) TestUnblock(c *gc.C) {
 
 	unblocked := make(chan struct{})
 	go func() {
-		err := client.BlockUntilLeadershipReleased(s.serviceId)
+		err := client.BlockUntilLeadershipReleased(s.serviceId, nil)
 		c.Check(err, gc.IsNil)
 		unblocked <- struct{}{}
 	}()
@@ -188,6 +189,31 @@ func (s *leadershipSuite) TestUnblock(c *gc.C) {
 	}
 }
 
+func (s *leadershipSuite) TestBlockUntilLeadershipReleasedCancelled(c *gc.C) {
+
+	client := leadership.NewClient(s.clientFacade, s.facadeCaller)
+	defer func() { err := client.Close(); c.Assert(err, gc.IsNil) }()
+
+	err := client.ClaimLeadership(s.serviceId, s.unitId, 10*time.Second)
+	c.Assert(err, gc.IsNil)
+
+	cancel := make(chan struct{})
+	unblocked := make(chan error)
+	go func() {
+		unblocked <- client.BlockUntilLeadershipReleased(s.serviceId, cancel)
+	}()
+
+	time.Sleep(coretesting.ShortWait)
+	close(cancel)
+
+	select {
+	case <-time.After(coretesting.LongWait):
+		c.Errorf("Timed out waiting for the block to be cancelled.")
+	case err := <-unblocked:
+		c.Check(err, gc.Equals, coreleadership.ErrBlockCancelled)
+	}
+}
+
 type uniterLeadershipSuite struct {
 	agenttesting.AgentSuite
 
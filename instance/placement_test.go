@@ -42,6 +42,10 @@ func (s *PlacementSuite) TestParsePlacement(c *gc.C) {
 		arg:             "kvm:123",
 		expectScope:     string(instance.KVM),
 		expectDirective: "123",
+	}, {
+		arg:             "lxd:123",
+		expectScope:     string(instance.LXD),
+		expectDirective: "123",
 	}, {
 		arg:         "lxc",
 		expectScope: string(instance.LXC),
@@ -35,6 +35,9 @@ type DeployServiceParams struct {
 	// Networks holds a list of networks to required to start on boot.
 	Networks []string
 	Storage  map[string]storage.Constraints
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they are bound to.
+	EndpointBindings map[string]string
 }
 
 // DeployService takes a charm and various parameters and deploys it.
@@ -76,12 +79,16 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 			return nil, fmt.Errorf("cannot deploy with networks: not suppored by the environment")
 		}
 	}
-	service, err := st.AddService(
+	if err := checkSpacesExist(st, args.Constraints.IncludeSpaces()); err != nil {
+		return nil, err
+	}
+	service, err := st.AddServiceWithEndpointBindings(
 		args.ServiceName,
 		args.ServiceOwner,
 		args.Charm,
 		args.Networks,
 		stateStorageConstraints(args.Storage),
+		args.EndpointBindings,
 	)
 	if err != nil {
 		return nil, err
@@ -107,6 +114,19 @@ func DeployService(st *state.State, args DeployServiceParams) (*state.Service, e
 	return service, nil
 }
 
+// checkSpacesExist returns an error if any of the named spaces is not
+// known to the environment.
+func checkSpacesExist(st *state.State, spaces []string) error {
+	for _, name := range spaces {
+		if _, err := st.Space(name); errors.IsNotFound(err) {
+			return errors.Errorf("cannot deploy with spaces constraint: space %q not found", name)
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 // AddUnits starts n units of the given service and allocates machines
 // to them as necessary.
 func AddUnits(st *state.State, svc *state.Service, n int, machineIdSpec string) ([]*state.Unit, error) {
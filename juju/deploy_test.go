@@ -129,6 +129,69 @@ func (s *DeployLocalSuite) TestDeployConstraints(c *gc.C) {
 	s.assertConstraints(c, service, serviceCons)
 }
 
+func (s *DeployLocalSuite) TestDeploySpacesConstraint(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serviceCons := constraints.MustParse("spaces=internal,^dmz")
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			Constraints: serviceCons,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertConstraints(c, service, serviceCons)
+}
+
+func (s *DeployLocalSuite) TestDeploySpacesConstraintUnknownSpace(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName: "bob",
+			Charm:       s.charm,
+			Constraints: constraints.MustParse("spaces=internal"),
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot deploy with spaces constraint: space "internal" not found`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *DeployLocalSuite) TestDeployEndpointBindings(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"juju-info": "internal"},
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"juju-info": "internal"})
+}
+
+func (s *DeployLocalSuite) TestDeployInvalidEndpointBindings(c *gc.C) {
+	_, err := juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"juju-info": "internal"},
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": space "internal" not found`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = juju.DeployService(s.State,
+		juju.DeployServiceParams{
+			ServiceName:      "bob",
+			Charm:            s.charm,
+			EndpointBindings: map[string]string{"db": "internal"},
+		})
+	c.Assert(err, gc.ErrorMatches, `cannot add service "bob": service "bob" has no "db" relation`)
+	_, err = s.State.Service("bob")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
 func (s *DeployLocalSuite) TestDeployNumUnits(c *gc.C) {
 	err := s.State.SetEnvironConstraints(constraints.MustParse("mem=2G"))
 	c.Assert(err, jc.ErrorIsNil)
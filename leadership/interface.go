@@ -15,6 +15,10 @@ import (
 // leadership claim has been denied.
 var ErrClaimDenied = errors.New("leadership claim denied")
 
+// ErrBlockCancelled is the error which will be returned when a caller
+// stops waiting for a change of leadership before it happens.
+var ErrBlockCancelled = errors.New("waiting for leadership cancelled")
+
 type LeadershipManager interface {
 	// ClaimLeadership claims a leadership for the given serviceId and
 	// unitId. If successful, the leadership will persist for the supplied
@@ -26,8 +30,15 @@ type LeadershipManager interface {
 	ReleaseLeadership(serviceId, unitId string) (err error)
 
 	// BlockUntilLeadershipReleased blocks the caller until leadership is
-	// released for the given serviceId.
-	BlockUntilLeadershipReleased(serviceId string) (err error)
+	// released for the given serviceId, or until cancel is closed, in
+	// which case ErrBlockCancelled is returned.
+	BlockUntilLeadershipReleased(serviceId string, cancel <-chan struct{}) (err error)
+
+	// BlockUntilLeadershipLost blocks the caller until the given unitId
+	// no longer holds leadership for the given serviceId, whether it was
+	// released, expired, or transferred to another unit, or until cancel
+	// is closed, in which case ErrBlockCancelled is returned.
+	BlockUntilLeadershipLost(serviceId, unitId string, cancel <-chan struct{}) (err error)
 }
 
 type LeadershipLeaseManager interface {
@@ -40,6 +51,10 @@ type LeadershipLeaseManager interface {
 	// ReleaseLease releases the lease held for namespace by id.
 	ReleaseLease(namespace, id string) (err error)
 
+	// TransferLease hands the lease for namespace to id for the given
+	// duration, whoever holds it, in a single write.
+	TransferLease(namespace, id string, forDur time.Duration) error
+
 	// RetrieveLease retrieves the current lease token for a given
 	// namespace. This is not intended to be exposed to clients, and is
 	// only available within a server-process.
@@ -50,4 +65,8 @@ type LeadershipLeaseManager interface {
 	// reusable, but will be closed if it does not respond within
 	// "notificationTimeout".
 	LeaseReleasedNotifier(namespace string) (notifier <-chan struct{})
+
+	// StopLeaseReleasedNotifier stops notifications on a channel
+	// returned by LeaseReleasedNotifier for namespace.
+	StopLeaseReleasedNotifier(namespace string, notifier <-chan struct{})
 }
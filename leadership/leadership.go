@@ -4,6 +4,7 @@
 package leadership
 
 import (
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -54,13 +55,65 @@ func (m *Manager) ReleaseLeadership(sid, uid string) error {
 	return m.leaseMgr.ReleaseLease(leadershipNamespace(sid), uid)
 }
 
+// TransferLeadership hands leadership of the given service to the given
+// unit for the supplied duration. Leadership held by another unit is
+// replaced in a single lease write, so no other unit can claim it in
+// between; the previous leader is notified that it has lost it.
+func (m *Manager) TransferLeadership(sid, uid string, duration time.Duration) error {
+	err := m.leaseMgr.TransferLease(leadershipNamespace(sid), uid, duration)
+	if err != nil {
+		return errors.Annotatef(err, "unable to transfer %s leadership to %s", sid, uid)
+	}
+	return nil
+}
+
 // BlockUntilLeadershipReleased implements the LeadershipManager interface.
-func (m *Manager) BlockUntilLeadershipReleased(serviceId string) error {
-	notifier := m.leaseMgr.LeaseReleasedNotifier(leadershipNamespace(serviceId))
-	<-notifier
+func (m *Manager) BlockUntilLeadershipReleased(serviceId string, cancel <-chan struct{}) error {
+	namespace := leadershipNamespace(serviceId)
+	notifier := m.leaseMgr.LeaseReleasedNotifier(namespace)
+	defer m.leaseMgr.StopLeaseReleasedNotifier(namespace, notifier)
+	select {
+	case <-notifier:
+	case <-cancel:
+		return ErrBlockCancelled
+	}
+	return nil
+}
+
+// BlockUntilLeadershipLost implements the LeadershipManager interface.
+func (m *Manager) BlockUntilLeadershipLost(serviceId, unitId string, cancel <-chan struct{}) error {
+	namespace := leadershipNamespace(serviceId)
+	// Subscribe before checking, so a loss in between is not missed.
+	notifier := m.leaseMgr.LeaseReleasedNotifier(namespace)
+	defer m.leaseMgr.StopLeaseReleasedNotifier(namespace, notifier)
+	for m.leaseMgr.RetrieveLease(namespace).Id == unitId {
+		select {
+		case <-notifier:
+		case <-cancel:
+			return ErrBlockCancelled
+		}
+	}
 	return nil
 }
 
+// Leaders returns the leader unit id for each service whose leadership
+// is recorded in the supplied lease tokens and has not expired by the
+// supplied time.
+func Leaders(tokens []lease.Token, now time.Time) map[string]string {
+	leaders := make(map[string]string)
+	for _, tok := range tokens {
+		if !strings.HasSuffix(tok.Namespace, leadershipNamespaceSuffix) {
+			continue
+		}
+		if tok.Expired(now) {
+			continue
+		}
+		serviceId := strings.TrimSuffix(tok.Namespace, leadershipNamespaceSuffix)
+		leaders[serviceId] = tok.Id
+	}
+	return leaders
+}
+
 func leadershipNamespace(serviceId string) string {
 	return serviceId + leadershipNamespaceSuffix
 }
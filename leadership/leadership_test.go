@@ -28,10 +28,12 @@ var (
 type leadershipSuite struct{}
 
 type leaseStub struct {
-	ClaimLeaseFn            func(string, string, time.Duration) (string, error)
-	ReleaseLeaseFn          func(string, string) error
-	LeaseReleasedNotifierFn func(string) <-chan struct{}
-	RetrieveLeaseFn         func(string) lease.Token
+	ClaimLeaseFn                func(string, string, time.Duration) (string, error)
+	ReleaseLeaseFn              func(string, string) error
+	TransferLeaseFn             func(string, string, time.Duration) error
+	LeaseReleasedNotifierFn     func(string) <-chan struct{}
+	StopLeaseReleasedNotifierFn func(string, <-chan struct{})
+	RetrieveLeaseFn             func(string) lease.Token
 }
 
 func (s *leaseStub) ClaimLease(namespace, id string, forDur time.Duration) (string, error) {
@@ -48,6 +50,13 @@ func (s *leaseStub) ReleaseLease(namespace, id string) error {
 	return nil
 }
 
+func (s *leaseStub) TransferLease(namespace, id string, forDur time.Duration) error {
+	if s.TransferLeaseFn != nil {
+		return s.TransferLeaseFn(namespace, id, forDur)
+	}
+	return nil
+}
+
 func (s *leaseStub) LeaseReleasedNotifier(namespace string) <-chan struct{} {
 	if s.LeaseReleasedNotifierFn != nil {
 		return s.LeaseReleasedNotifierFn(namespace)
@@ -55,6 +64,12 @@ func (s *leaseStub) LeaseReleasedNotifier(namespace string) <-chan struct{} {
 	return nil
 }
 
+func (s *leaseStub) StopLeaseReleasedNotifier(namespace string, notifier <-chan struct{}) {
+	if s.StopLeaseReleasedNotifierFn != nil {
+		s.StopLeaseReleasedNotifierFn(namespace, notifier)
+	}
+}
+
 func (s *leaseStub) RetrieveLease(namespace string) lease.Token {
 	if s.RetrieveLeaseFn != nil {
 		return s.RetrieveLeaseFn(namespace)
@@ -116,8 +131,126 @@ func (s *leadershipSuite) TestBlockUntilLeadershipReleasedTranslation(c *gc.C) {
 	}
 
 	leaderMgr := NewLeadershipManager(stub)
-	err := leaderMgr.BlockUntilLeadershipReleased(StubServiceNm)
+	err := leaderMgr.BlockUntilLeadershipReleased(StubServiceNm, nil)
 
 	c.Check(numStubCalls, gc.Equals, 1)
 	c.Check(err, jc.ErrorIsNil)
 }
+
+func (s *leadershipSuite) TestBlockUntilLeadershipReleasedCancelled(c *gc.C) {
+
+	notifier := make(chan struct{})
+	var stopped <-chan struct{}
+	stub := &leaseStub{
+		LeaseReleasedNotifierFn: func(namespace string) <-chan struct{} {
+			return notifier
+		},
+		StopLeaseReleasedNotifierFn: func(namespace string, n <-chan struct{}) {
+			c.Check(namespace, gc.Equals, leadershipNamespace(StubServiceNm))
+			stopped = n
+		},
+	}
+
+	cancel := make(chan struct{})
+	close(cancel)
+	leaderMgr := NewLeadershipManager(stub)
+	err := leaderMgr.BlockUntilLeadershipReleased(StubServiceNm, cancel)
+
+	c.Check(err, gc.Equals, ErrBlockCancelled)
+	c.Check(stopped, gc.Equals, (<-chan struct{})(notifier))
+}
+
+func (s *leadershipSuite) TestTransferLeadershipTranslation(c *gc.C) {
+
+	numStubCalls := 0
+	stub := &leaseStub{
+		TransferLeaseFn: func(namespace, id string, forDur time.Duration) error {
+			numStubCalls++
+			c.Check(namespace, gc.Equals, leadershipNamespace(StubServiceNm))
+			c.Check(id, gc.Equals, StubUnitNm)
+			c.Check(forDur, gc.Equals, time.Minute)
+			return nil
+		},
+		ReleaseLeaseFn: func(namespace, id string) error {
+			c.Errorf("unexpected release")
+			return nil
+		},
+		ClaimLeaseFn: func(namespace, id string, forDur time.Duration) (string, error) {
+			c.Errorf("unexpected claim")
+			return id, nil
+		},
+	}
+
+	leaderMgr := NewLeadershipManager(stub)
+	err := leaderMgr.TransferLeadership(StubServiceNm, StubUnitNm, time.Minute)
+
+	c.Check(numStubCalls, gc.Equals, 1)
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *leadershipSuite) TestBlockUntilLeadershipLost(c *gc.C) {
+
+	// The unit leads until leadership is handed to another unit, which
+	// is notified as a release.
+	released := make(chan struct{}, 1)
+	leaders := []string{StubUnitNm, "stub-unit/1"}
+	stub := &leaseStub{
+		LeaseReleasedNotifierFn: func(namespace string) <-chan struct{} {
+			c.Check(namespace, gc.Equals, leadershipNamespace(StubServiceNm))
+			released <- struct{}{}
+			return released
+		},
+		RetrieveLeaseFn: func(namespace string) lease.Token {
+			c.Check(namespace, gc.Equals, leadershipNamespace(StubServiceNm))
+			leader := leaders[0]
+			leaders = leaders[1:]
+			return lease.Token{Namespace: namespace, Id: leader}
+		},
+	}
+
+	leaderMgr := NewLeadershipManager(stub)
+	err := leaderMgr.BlockUntilLeadershipLost(StubServiceNm, StubUnitNm, nil)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(leaders, gc.HasLen, 0)
+}
+
+func (s *leadershipSuite) TestBlockUntilLeadershipLostCancelled(c *gc.C) {
+
+	notifier := make(chan struct{})
+	var stopped <-chan struct{}
+	stub := &leaseStub{
+		LeaseReleasedNotifierFn: func(namespace string) <-chan struct{} {
+			return notifier
+		},
+		StopLeaseReleasedNotifierFn: func(namespace string, n <-chan struct{}) {
+			c.Check(namespace, gc.Equals, leadershipNamespace(StubServiceNm))
+			stopped = n
+		},
+		RetrieveLeaseFn: func(namespace string) lease.Token {
+			return lease.Token{Namespace: namespace, Id: StubUnitNm}
+		},
+	}
+
+	cancel := make(chan struct{})
+	close(cancel)
+	leaderMgr := NewLeadershipManager(stub)
+	err := leaderMgr.BlockUntilLeadershipLost(StubServiceNm, StubUnitNm, cancel)
+
+	c.Check(err, gc.Equals, ErrBlockCancelled)
+	c.Check(stopped, gc.Equals, (<-chan struct{})(notifier))
+}
+
+func (s *leadershipSuite) TestLeaders(c *gc.C) {
+	now := time.Now()
+	leaders := Leaders([]lease.Token{
+		{Namespace: leadershipNamespace("mysql"), Id: "mysql/1", Expiration: now.Add(time.Minute)},
+		// Just expired, but not yet by every state server's clock.
+		{Namespace: leadershipNamespace("wordpress"), Id: "wordpress/0", Expiration: now},
+		{Namespace: leadershipNamespace("mongodb"), Id: "mongodb/2", Expiration: now.Add(-time.Hour)},
+		{Namespace: "something-else", Id: "unit/0", Expiration: now.Add(time.Minute)},
+	}, now)
+	c.Check(leaders, gc.DeepEquals, map[string]string{
+		"mysql":     "mysql/1",
+		"wordpress": "wordpress/0",
+	})
+}
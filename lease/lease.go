@@ -18,6 +18,10 @@ const (
 
 	// This is a useful thing to know in several contexts.
 	maxDuration = time.Duration(1<<63 - 1)
+
+	// maxWriteAttempts is the number of times we'll try to write a lease
+	// change when other state servers keep changing the lease first.
+	maxWriteAttempts = 3
 )
 
 var (
@@ -25,20 +29,51 @@ var (
 	LeaseClaimDeniedErr = errors.New("lease claim denied")
 	NotLeaseOwnerErr    = errors.Unauthorizedf("caller did not own lease for namespace")
 	logger              = loggo.GetLogger("juju.lease")
+
+	// LeaseChangedErr is returned by a lease persistor when a lease
+	// change is refused because the persisted lease no longer matches
+	// the one the change was based on.
+	LeaseChangedErr = errors.New("lease changed")
+
+	// clockSkewAllowance is how long after its expiration time a lease is
+	// still considered to be held. Leases are shared by all state servers,
+	// and their expiration times are set by the clock of whichever server
+	// granted them; so no server may consider a lease expired until it has
+	// expired by every other server's clock as well. The clocks of the state
+	// servers must therefore never differ by more than this.
+	clockSkewAllowance = 10 * time.Second
+
+	// refreshInterval is how often the persisted leases are reloaded, to
+	// find out about leases claimed and released via other state servers.
+	refreshInterval = 5 * time.Second
 )
 
 func init() {
 	singleton = &leaseManager{
-		claimLease:       make(chan claimLeaseMsg),
-		releaseLease:     make(chan releaseLeaseMsg),
-		leaseReleasedSub: make(chan leaseReleasedMsg),
-		copyOfTokens:     make(chan copyTokensMsg),
+		retrieveLease:      make(chan retrieveLeaseMsg),
+		claimLease:         make(chan claimLeaseMsg),
+		releaseLease:       make(chan releaseLeaseMsg),
+		transferLease:      make(chan transferLeaseMsg),
+		leaseReleasedSub:   make(chan leaseReleasedMsg),
+		leaseReleasedUnsub: make(chan stopLeaseReleasedMsg),
+		copyOfTokens:       make(chan copyTokensMsg),
 	}
 }
 
+// leasePersistor stores lease tokens where every state server can see
+// them, and is the final arbiter of which claims and releases succeed.
 type leasePersistor interface {
-	WriteToken(string, Token) error
-	RemoveToken(id string) error
+	// WriteToken stores the given token in place of the previous token
+	// for its namespace; a nil previous token indicates that no token is
+	// expected to be stored. If the stored token does not match the
+	// previous one, LeaseChangedErr is returned.
+	WriteToken(prev *Token, tok Token) error
+
+	// RemoveToken removes the given token. If the stored token does not
+	// match it, LeaseChangedErr is returned.
+	RemoveToken(tok Token) error
+
+	// PersistedTokens returns all the stored tokens.
 	PersistedTokens() ([]Token, error)
 }
 
@@ -66,16 +101,32 @@ func Manager() *leaseManager {
 // Messages for channels.
 //
 
+type retrieveLeaseMsg struct {
+	Namespace string
+	Response  chan<- Token
+}
 type claimLeaseMsg struct {
 	Token    Token
-	Response chan<- Token
+	Response chan<- claimLeaseResult
+}
+type claimLeaseResult struct {
+	Token Token
+	Err   error
 }
 type releaseLeaseMsg struct {
 	Token    Token
 	Response chan<- error
 }
+type transferLeaseMsg struct {
+	Token    Token
+	Response chan<- error
+}
 type leaseReleasedMsg struct {
-	Watcher      chan<- struct{}
+	Watcher      chan struct{}
+	ForNamespace string
+}
+type stopLeaseReleasedMsg struct {
+	Watcher      <-chan struct{}
 	ForNamespace string
 }
 type copyTokensMsg struct {
@@ -83,12 +134,14 @@ type copyTokensMsg struct {
 }
 
 type leaseManager struct {
-	leasePersistor   leasePersistor
-	retrieveLease    chan Token
-	claimLease       chan claimLeaseMsg
-	releaseLease     chan releaseLeaseMsg
-	leaseReleasedSub chan leaseReleasedMsg
-	copyOfTokens     chan copyTokensMsg
+	leasePersistor     leasePersistor
+	retrieveLease      chan retrieveLeaseMsg
+	claimLease         chan claimLeaseMsg
+	releaseLease       chan releaseLeaseMsg
+	transferLease      chan transferLeaseMsg
+	leaseReleasedSub   chan leaseReleasedMsg
+	leaseReleasedUnsub chan stopLeaseReleasedMsg
+	copyOfTokens       chan copyTokensMsg
 }
 
 // CopyOfLeaseTokens returns a copy of the lease tokens currently held
@@ -100,15 +153,12 @@ func (m *leaseManager) CopyOfLeaseTokens() []Token {
 }
 
 // RetrieveLease returns the lease token currently stored for the
-// given namespace.
+// given namespace. The persisted leases are reloaded first, so that
+// leases claimed via other state servers are taken into account.
 func (m *leaseManager) RetrieveLease(namespace string) Token {
-	for _, tok := range m.CopyOfLeaseTokens() {
-		if tok.Namespace != namespace {
-			continue
-		}
-		return tok
-	}
-	return Token{}
+	ch := make(chan Token)
+	m.retrieveLease <- retrieveLeaseMsg{namespace, ch}
+	return <-ch
 }
 
 // Claimlease claims a lease for the given duration for the given
@@ -117,13 +167,16 @@ func (m *leaseManager) RetrieveLease(namespace string) Token {
 // owner's ID will be returned.
 func (m *leaseManager) ClaimLease(namespace, id string, forDur time.Duration) (leaseOwnerId string, err error) {
 
-	ch := make(chan Token)
+	ch := make(chan claimLeaseResult)
 	token := Token{namespace, id, time.Now().Add(forDur)}
 	message := claimLeaseMsg{token, ch}
 	m.claimLease <- message
-	activeClaim := <-ch
+	result := <-ch
+	if result.Err != nil {
+		return "", errors.Annotatef(result.Err, `could not claim lease for namespace %q, id %q`, namespace, id)
+	}
 
-	leaseOwnerId = activeClaim.Id
+	leaseOwnerId = result.Token.Id
 	if id != leaseOwnerId {
 		err = LeaseClaimDeniedErr
 	}
@@ -156,6 +209,20 @@ func (m *leaseManager) ReleaseLease(namespace, id string) (err error) {
 	return nil
 }
 
+// TransferLease hands the lease for namespace to id for the given
+// duration, whoever holds it. The lease changes hands in a single
+// write, so no other claim can be made in between; subscribers are
+// notified of its release by the previous holder.
+func (m *leaseManager) TransferLease(namespace, id string, forDur time.Duration) error {
+	ch := make(chan error)
+	token := Token{namespace, id, time.Now().Add(forDur)}
+	m.transferLease <- transferLeaseMsg{token, ch}
+	if err := <-ch; err != nil {
+		return errors.Annotatef(err, `could not transfer lease for namespace %q to id %q`, namespace, id)
+	}
+	return nil
+}
+
 // LeaseReleasedNotifier returns a channel a caller can block on to be
 // notified of when a lease is released for namespace. This channel is
 // reusable, but will be closed if it does not respond within
@@ -167,95 +234,231 @@ func (m *leaseManager) LeaseReleasedNotifier(namespace string) (notifier <-chan
 	return watcher
 }
 
+// StopLeaseReleasedNotifier stops the notification of releases of the
+// lease for namespace on a channel returned by LeaseReleasedNotifier,
+// once its owner has finished waiting on it.
+func (m *leaseManager) StopLeaseReleasedNotifier(namespace string, notifier <-chan struct{}) {
+	m.leaseReleasedUnsub <- stopLeaseReleasedMsg{notifier, namespace}
+}
+
 // workerLoop serializes all requests into a single thread.
 func (m *leaseManager) workerLoop(stop <-chan struct{}) error {
-	// TODO(fwereade): this method never returns any errors after it's
-	// entered the loop. This is bad; it may poison its cache and continue
-	// to operate, serving unhelpful results.
-
 	// These data-structures are local to ensure they're only utilized
 	// within this thread-safe context.
 
-	releaseSubs := make(map[string][]chan<- struct{}, 0)
+	releaseSubs := make(map[string][]chan struct{}, 0)
 
-	// Pull everything off our data-store & check for expirations.
+	// Pull everything off our data-store; expirations are checked
+	// at the start of every iteration below.
 	leaseCache, err := populateTokenCache(m.leasePersistor)
 	if err != nil {
 		return err
 	}
-	nextExpiration := m.expireLeases(leaseCache, releaseSubs)
+	refresh := time.After(refreshInterval)
 
 	for {
+		nextExpiration := m.expireLeases(leaseCache, releaseSubs)
 		select {
 		case <-stop:
 			return nil
 		case claim := <-m.claimLease:
-			lease := claimLease(leaseCache, claim.Token)
-			if lease.Id == claim.Token.Id {
-				// TODO(fwereade): we should *definitely* not be ignoring this error.
-				m.leasePersistor.WriteToken(lease.Namespace, lease)
-				if lease.Expiration.Before(nextExpiration) {
-					nextExpiration = lease.Expiration
-				}
-			}
-			claim.Response <- lease
+			lease, err := m.claim(leaseCache, releaseSubs, claim.Token)
+			claim.Response <- claimLeaseResult{lease, err}
 		case release := <-m.releaseLease:
-			// Unwind our layers from most volatile to least.
-			err := releaseLease(leaseCache, release.Token)
-			if err == nil {
-				namespace := release.Token.Namespace
-				err = m.leasePersistor.RemoveToken(namespace)
-				// TODO(fwereade): if the above error is non-nil, we should
-				// not be continuing as if nothing had happened.
-				notifyOfRelease(releaseSubs[namespace], namespace)
-			}
-			release.Response <- err
+			release.Response <- m.release(leaseCache, releaseSubs, release.Token)
+		case transfer := <-m.transferLease:
+			transfer.Response <- m.transfer(leaseCache, releaseSubs, transfer.Token)
 		case subscription := <-m.leaseReleasedSub:
 			subscribe(releaseSubs, subscription)
+		case subscription := <-m.leaseReleasedUnsub:
+			unsubscribe(releaseSubs, subscription)
+		case msg := <-m.retrieveLease:
+			if err := m.refresh(leaseCache, releaseSubs); err != nil {
+				logger.Warningf("could not refresh leases: %v", err)
+			}
+			var lease Token
+			if tok, ok := leaseCache[msg.Namespace]; ok && !expired(tok, time.Now()) {
+				lease = tok
+			}
+			msg.Response <- lease
 		case msg := <-m.copyOfTokens:
 			// create a copy of the lease cache for use by code
 			// external to our thread-safe context.
 			msg.Response <- copyTokens(leaseCache)
 		case <-time.After(nextExpiration.Sub(time.Now())):
-			nextExpiration = m.expireLeases(leaseCache, releaseSubs)
+			// Leases are expired at the start of the loop.
+		case <-refresh:
+			if err := m.refresh(leaseCache, releaseSubs); err != nil {
+				return errors.Trace(err)
+			}
+			refresh = time.After(refreshInterval)
+		}
+	}
+}
+
+// claim attempts to make the supplied claim, and returns the token of
+// whoever holds the lease afterwards.
+func (m *leaseManager) claim(
+	cache map[string]Token,
+	subscribers map[string][]chan struct{},
+	claim Token,
+) (Token, error) {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		var prev *Token
+		if active, ok := cache[claim.Namespace]; ok {
+			if active.Id != claim.Id && !expired(active, time.Now()) {
+				return active, nil
+			}
+			prev = &active
+		}
+		err := m.leasePersistor.WriteToken(prev, claim)
+		if err == nil {
+			cache[claim.Namespace] = claim
+			logger.Infof(`%q obtained lease for %q`, claim.Id, claim.Namespace)
+			return claim, nil
+		} else if errors.Cause(err) != LeaseChangedErr {
+			return Token{}, errors.Trace(err)
+		}
+		// Another state server changed the lease first; find out
+		// what it did, and try again.
+		if err := m.refresh(cache, subscribers); err != nil {
+			return Token{}, errors.Trace(err)
+		}
+	}
+	return Token{}, errors.Errorf("lease for namespace %q changed too many times", claim.Namespace)
+}
+
+// release attempts to release the lease held by the supplied token's id.
+func (m *leaseManager) release(
+	cache map[string]Token,
+	subscribers map[string][]chan struct{},
+	release Token,
+) error {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		active, ok := cache[release.Namespace]
+		if !ok || active.Id != release.Id {
+			return NotLeaseOwnerErr
+		}
+		err := m.leasePersistor.RemoveToken(active)
+		if err == nil {
+			delete(cache, release.Namespace)
+			logger.Infof(`%q released lease for namespace %q`, release.Id, release.Namespace)
+			notifyOfRelease(subscribers[release.Namespace], release.Namespace)
+			return nil
+		} else if errors.Cause(err) != LeaseChangedErr {
+			return errors.Trace(err)
+		}
+		if err := m.refresh(cache, subscribers); err != nil {
+			return errors.Trace(err)
 		}
 	}
+	return errors.Errorf("lease for namespace %q changed too many times", release.Namespace)
+}
+
+// transfer replaces the lease for the supplied token's namespace,
+// whoever holds it, with the supplied token.
+func (m *leaseManager) transfer(
+	cache map[string]Token,
+	subscribers map[string][]chan struct{},
+	transfer Token,
+) error {
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		var prev *Token
+		if active, ok := cache[transfer.Namespace]; ok {
+			prev = &active
+		}
+		err := m.leasePersistor.WriteToken(prev, transfer)
+		if err == nil {
+			cache[transfer.Namespace] = transfer
+			logger.Infof(`%q was given lease for %q`, transfer.Id, transfer.Namespace)
+			if prev != nil && prev.Id != transfer.Id {
+				notifyOfRelease(subscribers[transfer.Namespace], transfer.Namespace)
+			}
+			return nil
+		} else if errors.Cause(err) != LeaseChangedErr {
+			return errors.Trace(err)
+		}
+		if err := m.refresh(cache, subscribers); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Errorf("lease for namespace %q changed too many times", transfer.Namespace)
+}
+
+// refresh updates the cache to match the persisted leases, notifying
+// subscribers of any leases that have been released in the meantime.
+func (m *leaseManager) refresh(
+	cache map[string]Token,
+	subscribers map[string][]chan struct{},
+) error {
+	persisted, err := populateTokenCache(m.leasePersistor)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for namespace := range cache {
+		if _, ok := persisted[namespace]; !ok {
+			delete(cache, namespace)
+			notifyOfRelease(subscribers[namespace], namespace)
+		}
+	}
+	for namespace, token := range persisted {
+		cache[namespace] = token
+	}
+	return nil
 }
 
 func (m *leaseManager) expireLeases(
 	cache map[string]Token,
-	subscribers map[string][]chan<- struct{},
+	subscribers map[string][]chan struct{},
 ) time.Time {
 
 	// Having just looped through all the leases we're holding, we can
 	// inform the caller of when the next expiration will occur.
-	nextExpiration := time.Now().Add(maxDuration)
+	now := time.Now()
+	nextExpiration := now.Add(maxDuration)
 
 	for _, token := range cache {
 
-		if token.Expiration.After(time.Now()) {
+		if !expired(token, now) {
 			// For the tokens that aren't expiring yet, find the
 			// minimum time we should wait before cleaning up again.
-			if nextExpiration.After(token.Expiration) {
-				nextExpiration = token.Expiration
+			if expiration := token.Expiration.Add(clockSkewAllowance); nextExpiration.After(expiration) {
+				nextExpiration = expiration
 				logger.Debugf("Setting next expiration to %s", nextExpiration)
 			}
 			continue
 		}
 
 		logger.Infof(`Lease for namespace %q has expired.`, token.Namespace)
-		if err := releaseLease(cache, token); err != nil {
-			// TODO(fwereade): we should certainly be returning the error and
-			// killing the main loop.
-			logger.Errorf("Failed to release expired lease for namespace %q: %v", token.Namespace, err)
-		} else {
+		delete(cache, token.Namespace)
+		switch err := m.leasePersistor.RemoveToken(token); errors.Cause(err) {
+		case nil:
 			notifyOfRelease(subscribers[token.Namespace], token.Namespace)
+		case LeaseChangedErr:
+			// The lease was renewed or released via another state server;
+			// we'll find out which when we next refresh.
+			logger.Debugf("expired lease for namespace %q has already changed", token.Namespace)
+		default:
+			logger.Errorf("Failed to remove expired lease for namespace %q: %v", token.Namespace, err)
 		}
 	}
 
 	return nextExpiration
 }
 
+// expired returns whether the supplied token can be considered expired
+// by every state server at the supplied time.
+func expired(token Token, now time.Time) bool {
+	return now.After(token.Expiration.Add(clockSkewAllowance))
+}
+
+// Expired returns whether the token can be considered expired by every
+// state server at the supplied time. Until then, the lease it records
+// is still held.
+func (t Token) Expired(now time.Time) bool {
+	return expired(t, now)
+}
+
 func copyTokens(cache map[string]Token) (copy []Token) {
 	for _, t := range cache {
 		copy = append(copy, t)
@@ -263,36 +466,33 @@ func copyTokens(cache map[string]Token) (copy []Token) {
 	return copy
 }
 
-func claimLease(cache map[string]Token, claim Token) Token {
-	if active, ok := cache[claim.Namespace]; ok && active.Id != claim.Id {
-		return active
-	}
-	cache[claim.Namespace] = claim
-	logger.Infof(`%q obtained lease for %q`, claim.Id, claim.Namespace)
-	return claim
-}
-
-func releaseLease(cache map[string]Token, claim Token) error {
-	if active, ok := cache[claim.Namespace]; !ok || active.Id != claim.Id {
-		return NotLeaseOwnerErr
-	}
-	delete(cache, claim.Namespace)
-	logger.Infof(`%q released lease for namespace %q`, claim.Id, claim.Namespace)
-	return nil
+func subscribe(subMap map[string][]chan struct{}, subscription leaseReleasedMsg) {
+	subList := subMap[subscription.ForNamespace]
+	subList = append(subList, subscription.Watcher)
+	subMap[subscription.ForNamespace] = subList
 }
 
-func subscribe(subMap map[string][]chan<- struct{}, subscription leaseReleasedMsg) {
+func unsubscribe(subMap map[string][]chan struct{}, subscription stopLeaseReleasedMsg) {
 	subList := subMap[subscription.ForNamespace]
-	subList = append(subList, subscription.Watcher)
+	for i, watcher := range subList {
+		if watcher == subscription.Watcher {
+			subList = append(subList[:i], subList[i+1:]...)
+			break
+		}
+	}
+	if len(subList) == 0 {
+		delete(subMap, subscription.ForNamespace)
+		return
+	}
 	subMap[subscription.ForNamespace] = subList
 }
 
-func notifyOfRelease(subscribers []chan<- struct{}, namespace string) {
+func notifyOfRelease(subscribers []chan struct{}, namespace string) {
 	logger.Infof(`Notifying namespace %q subscribers that its lease has been released.`, namespace)
 	for _, subscriber := range subscribers {
 		// Spin off into go-routine so we don't rely on listeners to
 		// not block.
-		go func(subscriber chan<- struct{}) {
+		go func(subscriber chan struct{}) {
 			select {
 			case subscriber <- struct{}{}:
 			case <-time.After(notificationTimeout):
@@ -28,23 +28,42 @@ var (
 	_ = gc.Suite(&leaseSuite{})
 )
 
+// stubLeasePersistor stores tokens in memory, with the same
+// compare-and-swap semantics as the real persistor, unless its
+// behaviour is overridden by the Fn fields.
 type stubLeasePersistor struct {
-	WriteTokenFn      func(string, Token) error
-	RemoveTokenFn     func(string) error
+	WriteTokenFn      func(*Token, Token) error
+	RemoveTokenFn     func(Token) error
 	PersistedTokensFn func() ([]Token, error)
+
+	mu     sync.Mutex
+	tokens map[string]Token
 }
 
-func (p *stubLeasePersistor) WriteToken(id string, tok Token) error {
+func (p *stubLeasePersistor) WriteToken(prev *Token, tok Token) error {
 	if p.WriteTokenFn != nil {
-		return p.WriteTokenFn(id, tok)
+		return p.WriteTokenFn(prev, tok)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stored, ok := p.tokens[tok.Namespace]
+	if (prev == nil && ok) || (prev != nil && (!ok || !sameToken(*prev, stored))) {
+		return LeaseChangedErr
 	}
+	p.setToken(tok)
 	return nil
 }
 
-func (p *stubLeasePersistor) RemoveToken(id string) error {
+func (p *stubLeasePersistor) RemoveToken(tok Token) error {
 	if p.RemoveTokenFn != nil {
-		return p.RemoveTokenFn(id)
+		return p.RemoveTokenFn(tok)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if stored, ok := p.tokens[tok.Namespace]; !ok || !sameToken(tok, stored) {
+		return LeaseChangedErr
 	}
+	delete(p.tokens, tok.Namespace)
 	return nil
 }
 
@@ -52,7 +71,36 @@ func (p *stubLeasePersistor) PersistedTokens() ([]Token, error) {
 	if p.PersistedTokensFn != nil {
 		return p.PersistedTokensFn()
 	}
-	return nil, nil
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return copyTokens(p.tokens), nil
+}
+
+// SetToken stores the supplied token as though it had been written
+// via another state server.
+func (p *stubLeasePersistor) SetToken(tok Token) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.setToken(tok)
+}
+
+// DeleteToken removes the token for the supplied namespace as though
+// it had been removed via another state server.
+func (p *stubLeasePersistor) DeleteToken(namespace string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.tokens, namespace)
+}
+
+func (p *stubLeasePersistor) setToken(tok Token) {
+	if p.tokens == nil {
+		p.tokens = make(map[string]Token)
+	}
+	p.tokens[tok.Namespace] = tok
+}
+
+func sameToken(a, b Token) bool {
+	return a.Id == b.Id && a.Expiration.Equal(b.Expiration)
 }
 
 type leaseSuite struct{}
@@ -287,6 +335,99 @@ func (s *leaseSuite) TestReleaseLeaseNotification(c *gc.C) {
 	}
 }
 
+func (s *leaseSuite) TestStopLeaseReleasedNotifier(c *gc.C) {
+	stop := make(chan struct{})
+	go WorkerLoop(&stubLeasePersistor{})(stop)
+	defer func() { stop <- struct{}{} }()
+	mgr := Manager()
+	_, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Assert(err, jc.ErrorIsNil)
+
+	stopped := mgr.LeaseReleasedNotifier(testNamespace)
+	subscription := mgr.LeaseReleasedNotifier(testNamespace)
+	mgr.StopLeaseReleasedNotifier(testNamespace, stopped)
+
+	err = mgr.ReleaseLease(testNamespace, testId)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the notifier still in use hears of the release.
+	select {
+	case <-subscription:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("Failed to unblock after release. Waited for %s", coretesting.LongWait)
+	}
+	select {
+	case <-stopped:
+		c.Fatalf("stopped notifier was notified of release")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *leaseSuite) TestTransferLease(c *gc.C) {
+	persistor := &stubLeasePersistor{}
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+	mgr := Manager()
+	_, err := mgr.ClaimLease(testNamespace, "other/0", testDuration)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The lease is written once, replacing the current holder's.
+	var writes []*Token
+	persistor.WriteTokenFn = func(prev *Token, tok Token) error {
+		writes = append(writes, prev)
+		persistor.WriteTokenFn = nil
+		return persistor.WriteToken(prev, tok)
+	}
+	subscription := mgr.LeaseReleasedNotifier(testNamespace)
+	err = mgr.TransferLease(testNamespace, testId, testDuration)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(writes, gc.HasLen, 1)
+	c.Assert(writes[0], gc.NotNil)
+	c.Assert(writes[0].Id, gc.Equals, "other/0")
+
+	// The previous holder's release is notified.
+	select {
+	case <-subscription:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("previous holder not notified of release")
+	}
+	tok := mgr.RetrieveLease(testNamespace)
+	c.Check(tok.Id, gc.Equals, testId)
+
+	// Other claims are still denied.
+	ownerId, err := mgr.ClaimLease(testNamespace, "other/0", testDuration)
+	c.Check(err, gc.Equals, LeaseClaimDeniedErr)
+	c.Check(ownerId, gc.Equals, testId)
+}
+
+func (s *leaseSuite) TestTransferLeaseRetriesWhenLeaseChanged(c *gc.C) {
+	persistor := &stubLeasePersistor{}
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+	mgr := Manager()
+
+	// Another state server gets in first.
+	var writes []*Token
+	persistor.WriteTokenFn = func(prev *Token, tok Token) error {
+		writes = append(writes, prev)
+		if len(writes) == 1 {
+			persistor.SetToken(Token{testNamespace, "other/0", time.Now().Add(testDuration)})
+			return LeaseChangedErr
+		}
+		persistor.WriteTokenFn = nil
+		return persistor.WriteToken(prev, tok)
+	}
+
+	err := mgr.TransferLease(testNamespace, testId, testDuration)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(writes, gc.HasLen, 2)
+	c.Check(writes[0], gc.IsNil)
+	c.Check(writes[1].Id, gc.Equals, "other/0")
+	c.Check(mgr.RetrieveLease(testNamespace).Id, gc.Equals, testId)
+}
+
 func (s *leaseSuite) TestLeaseExpiration(c *gc.C) {
 
 	// WARNING: This code may be load-sensitive. Unfortunately it must
@@ -294,6 +435,12 @@ func (s *leaseSuite) TestLeaseExpiration(c *gc.C) {
 	// it is testing. For that reason, we try a few times to see if we
 	// can get a successful run.
 
+	// The lease should be released exactly when it expires.
+	defer func(allowance time.Duration) {
+		clockSkewAllowance = allowance
+	}(clockSkewAllowance)
+	clockSkewAllowance = 0
+
 	stop := make(chan struct{})
 	go WorkerLoop(&stubLeasePersistor{})(stop)
 	defer func() { stop <- struct{}{} }()
@@ -358,13 +505,12 @@ func (s *leaseSuite) TestManagerPeresistsOnClaims(c *gc.C) {
 	mgr := Manager()
 
 	numWriteCalls := 0
-	persistor.WriteTokenFn = func(id string, tok Token) error {
+	persistor.WriteTokenFn = func(prev *Token, tok Token) error {
 		numWriteCalls++
 
-		c.Assert(tok, gc.NotNil)
+		c.Check(prev, gc.IsNil)
 		c.Check(tok.Namespace, gc.Equals, testNamespace)
 		c.Check(tok.Id, gc.Equals, testId)
-		c.Check(id, gc.Equals, testNamespace)
 
 		return nil
 	}
@@ -389,9 +535,10 @@ func (s *leaseSuite) TestManagerRemovesOnRelease(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 
 	numRemoveCalls := 0
-	persistor.RemoveTokenFn = func(id string) error {
+	persistor.RemoveTokenFn = func(tok Token) error {
 		numRemoveCalls++
-		c.Check(id, gc.Equals, testNamespace)
+		c.Check(tok.Namespace, gc.Equals, testNamespace)
+		c.Check(tok.Id, gc.Equals, testId)
 		return nil
 	}
 
@@ -401,6 +548,119 @@ func (s *leaseSuite) TestManagerRemovesOnRelease(c *gc.C) {
 	c.Check(numRemoveCalls, gc.Equals, 1)
 }
 
+func (s *leaseSuite) TestClaimLeaseRetriesWhenLeaseChanged(c *gc.C) {
+
+	persistor := &stubLeasePersistor{}
+
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+
+	// Another state server gets in first.
+	numWriteCalls := 0
+	persistor.WriteTokenFn = func(prev *Token, tok Token) error {
+		numWriteCalls++
+		persistor.SetToken(Token{testNamespace, "other/0", time.Now().Add(testDuration)})
+		return LeaseChangedErr
+	}
+
+	ownerId, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Check(err, gc.Equals, LeaseClaimDeniedErr)
+	c.Check(ownerId, gc.Equals, "other/0")
+	c.Check(numWriteCalls, gc.Equals, 1)
+}
+
+func (s *leaseSuite) TestClaimLeaseGivesUpWhenLeaseKeepsChanging(c *gc.C) {
+
+	persistor := &stubLeasePersistor{}
+
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+
+	numWriteCalls := 0
+	persistor.WriteTokenFn = func(prev *Token, tok Token) error {
+		numWriteCalls++
+		return LeaseChangedErr
+	}
+
+	_, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Check(err, gc.ErrorMatches, `could not claim lease for namespace ".*", id ".*": lease for namespace ".*" changed too many times`)
+	c.Check(numWriteCalls, gc.Equals, maxWriteAttempts)
+}
+
+func (s *leaseSuite) TestClaimLeaseRespectsClockSkewAllowance(c *gc.C) {
+
+	persistor := &stubLeasePersistor{}
+	persistor.SetToken(Token{testNamespace, "other/0", time.Now().Add(-time.Second)})
+	persistor.SetToken(Token{testNamespace + "2", "other/0", time.Now().Add(-2 * clockSkewAllowance)})
+
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+
+	// The lease has expired by our clock, but might not have by the
+	// clock of the state server that granted it.
+	ownerId, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Check(err, gc.Equals, LeaseClaimDeniedErr)
+	c.Check(ownerId, gc.Equals, "other/0")
+
+	// Once the allowance has passed, the lease can be taken over.
+	ownerId, err = mgr.ClaimLease(testNamespace+"2", testId, testDuration)
+	c.Check(err, jc.ErrorIsNil)
+	c.Check(ownerId, gc.Equals, testId)
+}
+
+func (s *leaseSuite) TestRetrieveLeaseSeesExternalClaim(c *gc.C) {
+
+	persistor := &stubLeasePersistor{}
+
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+
+	persistor.SetToken(Token{testNamespace, "other/0", time.Now().Add(testDuration)})
+	tok := mgr.RetrieveLease(testNamespace)
+	c.Check(tok.Id, gc.Equals, "other/0")
+}
+
+func (s *leaseSuite) TestRefreshNotifiesOfExternalRelease(c *gc.C) {
+
+	defer func(interval time.Duration) {
+		refreshInterval = interval
+	}(refreshInterval)
+	refreshInterval = coretesting.ShortWait
+
+	persistor := &stubLeasePersistor{}
+
+	stop := make(chan struct{})
+	go WorkerLoop(persistor)(stop)
+	defer func() { stop <- struct{}{} }()
+
+	mgr := Manager()
+	_, err := mgr.ClaimLease(testNamespace, testId, testDuration)
+	c.Assert(err, jc.ErrorIsNil)
+	subscription := mgr.LeaseReleasedNotifier(testNamespace)
+
+	// Another state server releases the lease.
+	persistor.DeleteToken(testNamespace)
+
+	select {
+	case <-subscription:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("Failed to notify of release. Waited for %s", coretesting.LongWait)
+	}
+	c.Check(mgr.CopyOfLeaseTokens(), gc.HasLen, 0)
+}
+
 func (s *leaseSuite) TestManagerDepersistsAllTokensOnStart(c *gc.C) {
 
 	persistor := &stubLeasePersistor{}
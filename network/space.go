@@ -0,0 +1,19 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package network
+
+import (
+	"regexp"
+)
+
+// SpaceSnippet defines the regexp for a valid space name: lower case
+// letters and digits, optionally separated by single hyphens.
+const SpaceSnippet = "(?:[a-z0-9]+(?:-[a-z0-9]+)*)"
+
+var validSpace = regexp.MustCompile("^" + SpaceSnippet + "$")
+
+// IsValidSpace reports whether name is a valid space name.
+func IsValidSpace(name string) bool {
+	return validSpace.MatchString(name)
+}
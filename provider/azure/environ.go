@@ -566,6 +566,9 @@ func (env *azureEnviron) selectInstanceTypeAndImage(constraint *instances.Instan
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -599,6 +602,11 @@ func (env *azureEnviron) PrecheckInstance(series string, cons constraints.Value,
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
+	if cons.HasZones() {
+		// Azure has no availability zones, so the
+		// constraint could not be honoured.
+		return fmt.Errorf("zones constraint not supported")
+	}
 	if !cons.HasInstanceType() {
 		return nil
 	}
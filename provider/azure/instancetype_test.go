@@ -404,3 +404,10 @@ func (s *instanceTypeSuite) TestPrecheckInstanceInvalidInstanceType(c *gc.C) {
 	err := env.PrecheckInstance("precise", cons, placement)
 	c.Assert(err, gc.ErrorMatches, `invalid instance type "Super"`)
 }
+
+func (s *instanceTypeSuite) TestPrecheckInstanceZones(c *gc.C) {
+	env := s.setupEnvWithDummyMetadata(c)
+	cons := constraints.MustParse("zones=az1")
+	err := env.PrecheckInstance("precise", cons, "")
+	c.Assert(err, gc.ErrorMatches, "zones constraint not supported")
+}
@@ -5,7 +5,11 @@ package common
 
 import (
 	"sort"
+	"strings"
 
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 )
@@ -131,6 +135,43 @@ func AvailabilityZoneAllocations(env ZonedEnviron, group []instance.Id) ([]Avail
 	return zoneInstances, nil
 }
 
+// ValidatePlacementZone returns an error if the availability zone
+// selected by a placement directive is excluded by the zones
+// constraint.
+func ValidatePlacementZone(zone string, cons constraints.Value) error {
+	if !cons.IncludesZone(zone) {
+		return errors.Errorf(
+			"availability zone %q does not satisfy zones constraint %q",
+			zone, strings.Join(*cons.Zones, ","),
+		)
+	}
+	return nil
+}
+
+// ConstrainZoneAllocations returns those of the given availability
+// zone allocations which are permitted by the zones constraint, in
+// their original order. If there is no zones constraint then the
+// allocations are returned unchanged. An error is returned if none of
+// the zones named by the constraint is available.
+func ConstrainZoneAllocations(allocations []AvailabilityZoneInstances, cons constraints.Value) ([]AvailabilityZoneInstances, error) {
+	if !cons.HasZones() {
+		return allocations, nil
+	}
+	var result []AvailabilityZoneInstances
+	for _, zoneInstances := range allocations {
+		if cons.IncludesZone(zoneInstances.ZoneName) {
+			result = append(result, zoneInstances)
+		}
+	}
+	if len(result) == 0 {
+		return nil, errors.Errorf(
+			"none of the availability zones in zones constraint %q are available",
+			strings.Join(*cons.Zones, ","),
+		)
+	}
+	return result, nil
+}
+
 var internalAvailabilityZoneAllocations = AvailabilityZoneAllocations
 
 // DistributeInstances is a common function for implement the
@@ -9,6 +9,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/common"
@@ -254,3 +255,38 @@ func (s *AvailabilityZoneSuite) TestDistributeInstances(c *gc.C) {
 		c.Assert(eligible, jc.SameContents, test.eligible)
 	}
 }
+
+func (s *AvailabilityZoneSuite) TestValidatePlacementZone(c *gc.C) {
+	err := common.ValidatePlacementZone("az1", constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("zones=az1,az2")
+	err = common.ValidatePlacementZone("az1", cons)
+	c.Assert(err, jc.ErrorIsNil)
+	err = common.ValidatePlacementZone("az0", cons)
+	c.Assert(err, gc.ErrorMatches, `availability zone "az0" does not satisfy zones constraint "az1,az2"`)
+}
+
+func (s *AvailabilityZoneSuite) TestConstrainZoneAllocations(c *gc.C) {
+	allocations := []common.AvailabilityZoneInstances{{
+		ZoneName:  "az0",
+		Instances: []instance.Id{"i0"},
+	}, {
+		ZoneName:  "az2",
+		Instances: []instance.Id{"i2", "i3"},
+	}, {
+		ZoneName:  "az1",
+		Instances: []instance.Id{"i1", "i4"},
+	}}
+
+	result, err := common.ConstrainZoneAllocations(allocations, constraints.Value{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, allocations)
+
+	result, err = common.ConstrainZoneAllocations(allocations, constraints.MustParse("zones=az1,az2,az3"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, allocations[1:])
+
+	_, err = common.ConstrainZoneAllocations(allocations, constraints.MustParse("zones=az3"))
+	c.Assert(err, gc.ErrorMatches, `none of the availability zones in zones constraint "az3" are available`)
+}
@@ -17,3 +17,9 @@ const (
 func MiBToGiB(m uint64) uint64 {
 	return (m + 1023) / 1024
 }
+
+// GiBToMiB converts the provided gigabytes (base-2) into megabytes
+// (base-2).
+func GiBToMiB(g uint64) uint64 {
+	return g * 1024
+}
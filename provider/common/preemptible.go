@@ -0,0 +1,26 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// ValidatePreemptible returns an error if the preemptible and
+// spot-price constraints cannot be honoured for a machine with the
+// given jobs. A spot price is only meaningful for preemptible
+// machines, and state servers must never run on capacity the provider
+// may take away at any time.
+func ValidatePreemptible(cons constraints.Value, jobs []multiwatcher.MachineJob) error {
+	if cons.SpotPrice != nil && *cons.SpotPrice > 0 && !cons.IsPreemptible() {
+		return errors.NotValidf("spot-price constraint without preemptible=true")
+	}
+	if cons.IsPreemptible() && multiwatcher.AnyJobNeedsState(jobs...) {
+		return errors.NotSupportedf("preemptible instances for state servers")
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package common_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/state/multiwatcher"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type PreemptibleSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&PreemptibleSuite{})
+
+var (
+	hostUnitsJobs   = []multiwatcher.MachineJob{multiwatcher.JobHostUnits}
+	stateServerJobs = []multiwatcher.MachineJob{multiwatcher.JobHostUnits, multiwatcher.JobManageEnviron}
+)
+
+func (s *PreemptibleSuite) TestValidatePreemptible(c *gc.C) {
+	for i, test := range []struct {
+		cons string
+		jobs []multiwatcher.MachineJob
+	}{
+		{"", hostUnitsJobs},
+		{"", stateServerJobs},
+		{"preemptible=true", hostUnitsJobs},
+		{"preemptible=true spot-price=0.1", hostUnitsJobs},
+		{"preemptible=false", stateServerJobs},
+		{"spot-price=", hostUnitsJobs},
+	} {
+		c.Logf("test %d: %q", i, test.cons)
+		err := common.ValidatePreemptible(constraints.MustParse(test.cons), test.jobs)
+		c.Check(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *PreemptibleSuite) TestValidatePreemptibleSpotPriceOnly(c *gc.C) {
+	err := common.ValidatePreemptible(constraints.MustParse("spot-price=0.1"), hostUnitsJobs)
+	c.Assert(err, gc.ErrorMatches, "spot-price constraint without preemptible=true not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *PreemptibleSuite) TestValidatePreemptibleStateServer(c *gc.C) {
+	err := common.ValidatePreemptible(constraints.MustParse("preemptible=true"), stateServerJobs)
+	c.Assert(err, gc.ErrorMatches, "preemptible instances for state servers not supported")
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
@@ -12,6 +12,7 @@ import (
 
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/storage"
 )
 
@@ -66,7 +67,7 @@ func getBlockDeviceMappings(
 	// The first block device is for the root disk.
 	blockDeviceMappings := []ec2.BlockDeviceMapping{{
 		DeviceName: "/dev/sda1",
-		VolumeSize: int64(mibToGib(rootDiskSizeMiB)),
+		VolumeSize: int64(common.MiBToGiB(rootDiskSizeMiB)),
 	}}
 
 	// Not all machines have this many instance stores.
@@ -102,7 +103,7 @@ func getBlockDeviceMappings(
 			return nil, nil, nil, errors.NotImplementedf("allocating unattached volumes")
 		}
 		mapping := ec2.BlockDeviceMapping{
-			VolumeSize: int64(mibToGib(params.Size)),
+			VolumeSize: int64(common.MiBToGiB(params.Size)),
 			// TODO(axw) DeleteOnTermination
 		}
 		// Translate user values for storage provider parameters.
@@ -132,7 +133,7 @@ func getBlockDeviceMappings(
 
 		volume := storage.Volume{
 			Tag:  params.Tag,
-			Size: gibToMib(uint64(mapping.VolumeSize)),
+			Size: common.GiBToMiB(uint64(mapping.VolumeSize)),
 			// VolumeId will be filled in once the instance has
 			// been created, which will create the volumes too.
 		}
@@ -178,18 +179,6 @@ func validateBlockDeviceMapping(m ec2.BlockDeviceMapping) error {
 	return nil
 }
 
-// mibToGib converts mebibytes to gibibytes.
-// AWS expects GiB, we work in MiB; round up
-// to nearest GiB.
-func mibToGib(m uint64) uint64 {
-	return (m + 1023) / 1024
-}
-
-// gibToMib converts gibibytes to mebibytes.
-func gibToMib(g uint64) uint64 {
-	return g * 1024
-}
-
 var errTooManyVolumes = errors.New("too many EBS volumes to attach")
 
 // blockDeviceNamer returns a function that cycles through block device names.
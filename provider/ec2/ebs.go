@@ -4,10 +4,19 @@
 package ec2
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/juju/errors"
+	"github.com/juju/utils"
 	"github.com/juju/utils/set"
+	"gopkg.in/amz.v3/ec2"
 
 	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/storage/poolmanager"
 )
@@ -30,8 +39,26 @@ const (
 
 	// Specifies whether the volume should be encrypted.
 	EBS_Encrypted = "encrypted"
+
+	// tagEnvUUID is the name of the tag used to record the
+	// UUID of the environment that a volume belongs to.
+	tagEnvUUID = "juju-env-uuid"
+
+	volumeStatusAvailable = "available"
+	volumeStatusError     = "error"
+
+	// volumeNotFound is the error code returned by EC2 when
+	// a volume does not exist.
+	volumeNotFound = "InvalidVolume.NotFound"
 )
 
+// volumeAttempt is used to wait for newly created volumes
+// to become available.
+var volumeAttempt = utils.AttemptStrategy{
+	Total: 60 * time.Second,
+	Delay: 1 * time.Second,
+}
+
 func init() {
 	ebsssdPool, _ := storage.NewConfig("ebs-ssd", EBS_ProviderType, map[string]interface{}{"volume-type": "gp2"})
 	defaultPools := []*storage.Config{
@@ -87,7 +114,20 @@ func TranslateUserEBSOptions(userOptions map[string]interface{}) map[string]inte
 
 // VolumeSource is defined on the Provider interface.
 func (e *ebsProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
-	panic("not implemented")
+	ecfg, err := providerInstance.newConfig(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	uuid, ok := environConfig.UUID()
+	if !ok {
+		return nil, errors.NotFoundf("environment UUID")
+	}
+	ec2Client, _ := awsClients(ecfg)
+	source := &ebsVolumeSource{
+		ec2:     ec2Client,
+		envUUID: uuid,
+	}
+	return source, nil
 }
 
 // FilesystemSource is defined on the Provider interface.
@@ -95,31 +135,381 @@ func (e *ebsProvider) FilesystemSource(environConfig *config.Config, providerCon
 	return nil, errors.NotSupportedf("filesystems")
 }
 
+// ebsVolumeSource creates, attaches and destroys EBS volumes.
 type ebsVolumeSource struct {
+	ec2     *ec2.EC2
+	envUUID string
 }
 
 var _ storage.VolumeSource = (*ebsVolumeSource)(nil)
 
-func (v *ebsVolumeSource) CreateVolumes([]storage.VolumeParams) ([]storage.Volume, []storage.VolumeAttachment, error) {
-	panic("not implemented")
+// parseVolumeOptions translates the size and pool attributes of a
+// volume into the parameters of an EBS CreateVolume request. The
+// availability zone is not set.
+func parseVolumeOptions(size uint64, attrs map[string]interface{}) (ec2.CreateVolume, error) {
+	vol := ec2.CreateVolume{
+		VolumeSize: int(common.MiBToGiB(size)),
+	}
+	// TODO(wallyworld) - remove type assertions when juju/schema is used
+	options := TranslateUserEBSOptions(attrs)
+	if v, ok := options[EBS_VolumeType]; ok && v != "" {
+		vol.VolumeType = fmt.Sprint(v)
+	}
+	if v, ok := options[EBS_IOPS]; ok && v != "" {
+		iops, err := strconv.ParseInt(fmt.Sprint(v), 10, 64)
+		if err != nil {
+			return vol, errors.Annotatef(err, "invalid iops value %v, expected integer", v)
+		}
+		vol.IOPS = iops
+	}
+	if v, ok := options[EBS_Encrypted]; ok && v != "" {
+		encrypted, err := strconv.ParseBool(fmt.Sprint(v))
+		if err != nil {
+			return vol, errors.Annotatef(err, "invalid encrypted value %v, expected boolean", v)
+		}
+		vol.Encrypted = encrypted
+	}
+	return vol, nil
+}
+
+// validateVolume checks that the volume's size, type and IOPS are
+// acceptable to EBS.
+func validateVolume(vol ec2.CreateVolume) error {
+	return validateBlockDeviceMapping(ec2.BlockDeviceMapping{
+		VolumeSize: int64(vol.VolumeSize),
+		VolumeType: vol.VolumeType,
+		IOPS:       vol.IOPS,
+	})
+}
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes are created in the availability zone of the instance they
+// are to be attached to, and are then attached to it.
+func (v *ebsVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	instIds := make([]instance.Id, 0, len(params))
+	for _, p := range params {
+		if p.Attachment == nil || p.Attachment.InstanceId == "" {
+			return nil, nil, errors.NotSupportedf("creating volume %s without an instance", p.Tag.Id())
+		}
+		instIds = append(instIds, p.Attachment.InstanceId)
+	}
+	instances, err := v.instances(instIds)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	volumes := make([]storage.Volume, 0, len(params))
+	attachParams := make([]storage.VolumeAttachmentParams, 0, len(params))
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Don't leave volumes behind that nothing knows about.
+		for _, volume := range volumes {
+			if _, err := v.ec2.DeleteVolume(volume.VolumeId); err != nil {
+				logger.Warningf("cannot delete volume %q: %v", volume.VolumeId, err)
+			}
+		}
+	}()
+	// Check every volume before creating any, so that one bad
+	// volume does not fail the call part way through.
+	vols := make([]ec2.CreateVolume, len(params))
+	for i, p := range params {
+		vol, err := parseVolumeOptions(p.Size, p.Attributes)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid parameters for volume %s", p.Tag.Id())
+		}
+		if err := validateVolume(vol); err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid parameters for volume %s", p.Tag.Id())
+		}
+		vol.AvailZone = instances[p.Attachment.InstanceId].AvailZone
+		vols[i] = vol
+	}
+	for i, p := range params {
+		resp, err := v.ec2.CreateVolume(vols[i])
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:      p.Tag,
+			VolumeId: resp.Id,
+			Size:     common.GiBToMiB(uint64(resp.Size)),
+		})
+		if err := v.tagVolume(resp.Id); err != nil {
+			return nil, nil, errors.Annotatef(err, "tagging volume %s", p.Tag.Id())
+		}
+		attachment := *p.Attachment
+		attachment.VolumeId = resp.Id
+		attachParams = append(attachParams, attachment)
+	}
+	for _, volume := range volumes {
+		if err := v.waitVolumeAvailable(volume.VolumeId); err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", volume.Tag.Id())
+		}
+	}
+	attachments, err := v.attachVolumes(attachParams, instances)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return volumes, attachments, nil
+}
+
+// tagVolume tags the volume with the environment UUID, so that
+// volumes belonging to an environment can be identified.
+func (v *ebsVolumeSource) tagVolume(volumeId string) error {
+	_, err := v.ec2.CreateTags([]string{volumeId}, []ec2.Tag{{
+		Key:   tagEnvUUID,
+		Value: v.envUUID,
+	}})
+	return err
 }
 
+// waitVolumeAvailable waits until the volume has been created
+// and is ready to be attached.
+func (v *ebsVolumeSource) waitVolumeAvailable(volumeId string) error {
+	var status string
+	for a := volumeAttempt.Start(); a.Next(); {
+		resp, err := v.ec2.Volumes([]string{volumeId}, nil)
+		if err != nil {
+			if !a.HasNext() {
+				return errors.Trace(err)
+			}
+			// Don't fail, because eventual consistency.
+			logger.Debugf("error describing volume %q: %v", volumeId, err)
+			continue
+		}
+		if len(resp.Volumes) == 1 {
+			status = resp.Volumes[0].Status
+			if status == volumeStatusAvailable {
+				return nil
+			}
+			if status == volumeStatusError {
+				return errors.Errorf("volume %q failed to create", volumeId)
+			}
+		}
+	}
+	return errors.Errorf("timed out waiting for volume %q to become available (status %q)", volumeId, status)
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
 func (v *ebsVolumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
-	panic("not implemented")
+	resp, err := v.ec2.Volumes(volIds, nil)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	byId := make(map[string]ec2.Volume)
+	for _, vol := range resp.Volumes {
+		byId[vol.Id] = vol
+	}
+	volumes := make([]storage.Volume, len(volIds))
+	for i, volId := range volIds {
+		vol, ok := byId[volId]
+		if !ok {
+			return nil, errors.NotFoundf("volume %q", volId)
+		}
+		volumes[i] = storage.Volume{
+			VolumeId: vol.Id,
+			Size:     common.GiBToMiB(uint64(vol.Size)),
+		}
+	}
+	return volumes, nil
 }
 
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that no longer exist are considered destroyed.
 func (v *ebsVolumeSource) DestroyVolumes(volIds []string) []error {
-	panic("not implemented")
+	errs := make([]error, len(volIds))
+	for i, volId := range volIds {
+		if _, err := v.ec2.DeleteVolume(volId); err != nil {
+			if ec2ErrCode(err) == volumeNotFound {
+				continue
+			}
+			errs[i] = errors.Annotatef(err, "destroying %q", volId)
+		}
+	}
+	return errs
 }
 
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
 func (v *ebsVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
-	panic("not implemented")
+	vol, err := parseVolumeOptions(params.Size, params.Attributes)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return validateVolume(vol)
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *ebsVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	instIds := make([]instance.Id, len(params))
+	for i, p := range params {
+		instIds[i] = p.InstanceId
+	}
+	instances, err := v.instances(instIds)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return v.attachVolumes(params, instances)
+}
+
+// attachVolumes attaches volumes to the given instances, choosing
+// device names that are not already in use on each instance. Volumes
+// that are already attached to the requested instance are reported
+// as they are.
+func (v *ebsVolumeSource) attachVolumes(
+	params []storage.VolumeAttachmentParams,
+	instances map[instance.Id]*ec2.Instance,
+) ([]storage.VolumeAttachment, error) {
+	volumes, err := v.volumes(params)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// inUse records the device names in use on each instance.
+	inUse := make(map[instance.Id]set.Strings)
+	for id, inst := range instances {
+		names := set.NewStrings()
+		for _, m := range inst.BlockDeviceMappings {
+			names.Add(m.DeviceName)
+		}
+		inUse[id] = names
+	}
+
+	attachments := make([]storage.VolumeAttachment, len(params))
+	for i, p := range params {
+		inst := instances[p.InstanceId]
+		vol := volumes[p.VolumeId]
+		if vol.AvailZone != inst.AvailZone {
+			return nil, errors.Errorf(
+				"volume %s is in availability zone %q, instance %s is in %q",
+				p.VolumeId, vol.AvailZone, p.InstanceId, inst.AvailZone,
+			)
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:  p.Volume,
+			Machine: p.Machine,
+		}
+		if device := attachedDevice(vol, p.InstanceId); device != "" {
+			attachments[i].DeviceName = renamedDeviceName(device)
+			continue
+		}
+		requestDeviceName, actualDeviceName, err := freeDeviceName(inst, inUse[p.InstanceId])
+		if err != nil {
+			return nil, errors.Annotatef(err, "attaching volume %s to instance %s", p.VolumeId, p.InstanceId)
+		}
+		if _, err := v.ec2.AttachVolume(p.VolumeId, string(p.InstanceId), requestDeviceName); err != nil {
+			return nil, errors.Annotatef(err, "attaching volume %s to instance %s", p.VolumeId, p.InstanceId)
+		}
+		inUse[p.InstanceId].Add(requestDeviceName)
+		attachments[i].DeviceName = actualDeviceName
+	}
+	return attachments, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that are not attached to the specified instance are
+// considered detached.
+func (v *ebsVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) error {
+	volumes, err := v.volumes(params)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, p := range params {
+		device := attachedDevice(volumes[p.VolumeId], p.InstanceId)
+		if device == "" {
+			continue
+		}
+		if _, err := v.ec2.DetachVolume(p.VolumeId, string(p.InstanceId), device, false); err != nil {
+			return errors.Annotatef(err, "detaching volume %s from instance %s", p.VolumeId, p.InstanceId)
+		}
+	}
+	return nil
 }
 
-func (v *ebsVolumeSource) AttachVolumes([]storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
-	panic("not implemented")
+// instances returns the EC2 instances with the given IDs, keyed by ID.
+func (v *ebsVolumeSource) instances(ids []instance.Id) (map[instance.Id]*ec2.Instance, error) {
+	idSet := set.NewStrings()
+	for _, id := range ids {
+		idSet.Add(string(id))
+	}
+	resp, err := v.ec2.Instances(idSet.SortedValues(), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "querying instances")
+	}
+	instances := make(map[instance.Id]*ec2.Instance)
+	for i := range resp.Reservations {
+		r := &resp.Reservations[i]
+		for j := range r.Instances {
+			inst := &r.Instances[j]
+			instances[instance.Id(inst.InstanceId)] = inst
+		}
+	}
+	for _, id := range ids {
+		if _, ok := instances[id]; !ok {
+			return nil, errors.NotFoundf("instance %s", id)
+		}
+	}
+	return instances, nil
 }
 
-func (v *ebsVolumeSource) DetachVolumes([]storage.VolumeAttachmentParams) error {
-	panic("not implemented")
+// volumes returns the EBS volumes identified by the given
+// attachment parameters, keyed by volume ID.
+func (v *ebsVolumeSource) volumes(params []storage.VolumeAttachmentParams) (map[string]ec2.Volume, error) {
+	idSet := set.NewStrings()
+	for _, p := range params {
+		idSet.Add(p.VolumeId)
+	}
+	resp, err := v.ec2.Volumes(idSet.SortedValues(), nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "querying volumes")
+	}
+	volumes := make(map[string]ec2.Volume)
+	for _, vol := range resp.Volumes {
+		volumes[vol.Id] = vol
+	}
+	for _, p := range params {
+		if _, ok := volumes[p.VolumeId]; !ok {
+			return nil, errors.NotFoundf("volume %q", p.VolumeId)
+		}
+	}
+	return volumes, nil
+}
+
+// attachedDevice returns the device name that the volume is
+// attached to the instance with, or "" if it is not attached
+// to the instance.
+func attachedDevice(vol ec2.Volume, instId instance.Id) string {
+	for _, a := range vol.Attachments {
+		if a.InstanceId == string(instId) {
+			return a.Device
+		}
+	}
+	return ""
+}
+
+// freeDeviceName returns the first block device name, as requested
+// from EC2 and as it will appear on the machine, that is not in use
+// on the instance.
+func freeDeviceName(inst *ec2.Instance, inUse set.Strings) (requestName, actualName string, err error) {
+	nextDeviceName := blockDeviceNamer(inst.VirtType == paravirtual)
+	for {
+		requestName, actualName, err = nextDeviceName()
+		if err != nil {
+			return "", "", err
+		}
+		if !inUse.Contains(requestName) {
+			return requestName, actualName, nil
+		}
+	}
+}
+
+// renamedDeviceName returns the name that a device requested
+// with the given name will have on the machine.
+func renamedDeviceName(requestName string) string {
+	if !strings.HasPrefix(requestName, devicePrefix) {
+		return requestName
+	}
+	return renamedDevicePrefix + requestName[len(devicePrefix):]
 }
@@ -4,9 +4,14 @@
 package ec2_test
 
 import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
 	jc "github.com/juju/testing/checkers"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	"gopkg.in/amz.v3/ec2/ec2test"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/instance"
 	"github.com/juju/juju/provider/ec2"
 	"github.com/juju/juju/storage"
 	"github.com/juju/juju/testing"
@@ -56,3 +61,235 @@ func (*storageSuite) TestTranslateUserEBSOptions(c *gc.C) {
 		})
 	}
 }
+
+type ebsVolumeSuite struct {
+	testing.BaseSuite
+	srv      localServer
+	source   storage.VolumeSource
+	instId   instance.Id
+	ec2      *amzec2.EC2
+	envUUID  string
+	instZone string
+}
+
+var _ = gc.Suite(&ebsVolumeSuite{})
+
+func (s *ebsVolumeSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.srv.startServer(c)
+	s.AddCleanup(func(c *gc.C) { s.srv.stopServer(c) })
+
+	cfg := testing.CustomEnvironConfig(c, localConfigAttrs)
+	uuid, ok := cfg.UUID()
+	c.Assert(ok, jc.IsTrue)
+	s.envUUID = uuid
+	source, err := ec2.EBSProvider().VolumeSource(cfg, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source = source
+
+	ids := s.srv.ec2srv.NewInstances(1, "m1.medium", "ami-a7f539ce", ec2test.Running, nil)
+	s.instId = instance.Id(ids[0])
+	s.ec2 = ec2.VolumeSourceEC2(source)
+	resp, err := s.ec2.Instances(ids, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.instZone = resp.Reservations[0].Instances[0].AvailZone
+}
+
+func (s *ebsVolumeSuite) volumeParams(id string, size uint64, attrs map[string]interface{}) storage.VolumeParams {
+	return storage.VolumeParams{
+		Tag:        names.NewVolumeTag(id),
+		Size:       size,
+		Provider:   ec2.EBS_ProviderType,
+		Attributes: attrs,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("1"),
+				InstanceId: s.instId,
+			},
+			Volume: names.NewVolumeTag(id),
+		},
+	}
+}
+
+func (s *ebsVolumeSuite) createVolumes(c *gc.C, params ...storage.VolumeParams) ([]storage.Volume, []storage.VolumeAttachment) {
+	volumes, attachments, err := s.source.CreateVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	return volumes, attachments
+}
+
+func (s *ebsVolumeSuite) describeVolume(c *gc.C, volumeId string) amzec2.Volume {
+	resp, err := s.ec2.Volumes([]string{volumeId}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Volumes, gc.HasLen, 1)
+	return resp.Volumes[0]
+}
+
+func (s *ebsVolumeSuite) TestCreateVolumes(c *gc.C) {
+	volumes, attachments := s.createVolumes(c,
+		s.volumeParams("0", 10*1024, map[string]interface{}{
+			"volume-type": "provisioned-iops",
+			"iops":        "100",
+			"encrypted":   true,
+		}),
+		s.volumeParams("1", 1000, nil),
+	)
+	c.Assert(volumes, gc.HasLen, 2)
+	c.Assert(volumes[0].Tag, gc.Equals, names.NewVolumeTag("0"))
+	c.Assert(volumes[0].Size, gc.Equals, uint64(10*1024))
+	c.Assert(volumes[1].Tag, gc.Equals, names.NewVolumeTag("1"))
+	c.Assert(volumes[1].Size, gc.Equals, uint64(1024))
+
+	c.Assert(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:     names.NewVolumeTag("0"),
+		Machine:    names.NewMachineTag("1"),
+		DeviceName: "xvdf",
+	}, {
+		Volume:     names.NewVolumeTag("1"),
+		Machine:    names.NewMachineTag("1"),
+		DeviceName: "xvdg",
+	}})
+
+	vol := s.describeVolume(c, volumes[0].VolumeId)
+	c.Assert(vol.AvailZone, gc.Equals, s.instZone)
+	c.Assert(vol.VolumeType, gc.Equals, "io1")
+	c.Assert(vol.IOPS, gc.Equals, int64(100))
+	c.Assert(vol.Encrypted, jc.IsTrue)
+	c.Assert(vol.Tags, jc.DeepEquals, []amzec2.Tag{{Key: "juju-env-uuid", Value: s.envUUID}})
+	c.Assert(vol.Attachments, gc.HasLen, 1)
+	c.Assert(vol.Attachments[0].InstanceId, gc.Equals, string(s.instId))
+	c.Assert(vol.Attachments[0].Device, gc.Equals, "/dev/sdf")
+}
+
+func (s *ebsVolumeSuite) TestCreateVolumesNoInstance(c *gc.C) {
+	params := s.volumeParams("0", 1024, nil)
+	params.Attachment.InstanceId = ""
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{params})
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
+
+func (s *ebsVolumeSuite) TestCreateVolumesUnknownInstance(c *gc.C) {
+	params := s.volumeParams("0", 1024, nil)
+	params.Attachment.InstanceId = "i-42"
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{params})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *ebsVolumeSuite) TestCreateVolumesInvalidParamsCreatesNone(c *gc.C) {
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{
+		s.volumeParams("0", 1024, nil),
+		s.volumeParams("1", 2048*1024, nil),
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid parameters for volume 1: 2048 GiB exceeds the maximum of 1024 GiB")
+
+	_, _, err = s.source.CreateVolumes([]storage.VolumeParams{
+		s.volumeParams("0", 1024, nil),
+		s.volumeParams("1", 1024, map[string]interface{}{"iops": "lots"}),
+	})
+	c.Assert(err, gc.ErrorMatches, "invalid parameters for volume 1: invalid iops value lots, expected integer: .*")
+
+	filter := amzec2.NewFilter()
+	filter.Add("tag:juju-env-uuid", s.envUUID)
+	resp, err := s.ec2.Volumes(nil, filter)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Volumes, gc.HasLen, 0)
+}
+
+func (s *ebsVolumeSuite) TestDescribeVolumes(c *gc.C) {
+	volumes, _ := s.createVolumes(c, s.volumeParams("0", 1024, nil), s.volumeParams("1", 2048, nil))
+	described, err := s.source.DescribeVolumes([]string{volumes[1].VolumeId, volumes[0].VolumeId})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(described, jc.DeepEquals, []storage.Volume{{
+		VolumeId: volumes[1].VolumeId,
+		Size:     2048,
+	}, {
+		VolumeId: volumes[0].VolumeId,
+		Size:     1024,
+	}})
+}
+
+func (s *ebsVolumeSuite) TestDestroyVolumes(c *gc.C) {
+	volumes, _ := s.createVolumes(c, s.volumeParams("0", 1024, nil))
+	params := s.volumeParams("0", 1024, nil).Attachment
+	params.VolumeId = volumes[0].VolumeId
+	err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{*params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	errs := s.source.DestroyVolumes([]string{volumes[0].VolumeId, "vol-42"})
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+	_, err = s.source.DescribeVolumes([]string{volumes[0].VolumeId})
+	c.Assert(err, gc.NotNil)
+}
+
+func (s *ebsVolumeSuite) TestDestroyVolumesAttached(c *gc.C) {
+	volumes, _ := s.createVolumes(c, s.volumeParams("0", 1024, nil))
+	errs := s.source.DestroyVolumes([]string{volumes[0].VolumeId})
+	c.Assert(errs, gc.HasLen, 1)
+	c.Assert(errs[0], gc.ErrorMatches, `destroying "vol-[0-9]+": .*`)
+}
+
+func (s *ebsVolumeSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	volumes, attachments := s.createVolumes(c, s.volumeParams("0", 1024, nil))
+	params := s.volumeParams("0", 1024, nil).Attachment
+	params.VolumeId = volumes[0].VolumeId
+	reattached, err := s.source.AttachVolumes([]storage.VolumeAttachmentParams{*params})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reattached, jc.DeepEquals, attachments)
+}
+
+func (s *ebsVolumeSuite) TestAttachVolumesZoneMismatch(c *gc.C) {
+	resp, err := s.ec2.CreateVolume(amzec2.CreateVolume{
+		AvailZone:  "test-available",
+		VolumeSize: 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	params := s.volumeParams("0", 1024, nil).Attachment
+	params.VolumeId = resp.Id
+	_, err = s.source.AttachVolumes([]storage.VolumeAttachmentParams{*params})
+	c.Assert(err, gc.ErrorMatches, `volume vol-[0-9]+ is in availability zone "test-available", instance i-[0-9]+ is in ".*"`)
+}
+
+func (s *ebsVolumeSuite) TestDetachVolumes(c *gc.C) {
+	volumes, _ := s.createVolumes(c, s.volumeParams("0", 1024, nil))
+	params := s.volumeParams("0", 1024, nil).Attachment
+	params.VolumeId = volumes[0].VolumeId
+	err := s.source.DetachVolumes([]storage.VolumeAttachmentParams{*params})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.describeVolume(c, volumes[0].VolumeId).Attachments, gc.HasLen, 0)
+
+	// Detaching a detached volume is a no-op.
+	err = s.source.DetachVolumes([]storage.VolumeAttachmentParams{*params})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ebsVolumeSuite) TestValidateVolumeParams(c *gc.C) {
+	for i, test := range []struct {
+		size  uint64
+		attrs map[string]interface{}
+		err   string
+	}{{
+		size: 1024,
+	}, {
+		size: 2048 * 1024,
+		err:  "2048 GiB exceeds the maximum of 1024 GiB",
+	}, {
+		size:  1024,
+		attrs: map[string]interface{}{"volume-type": "provisioned-iops", "iops": "30"},
+		err:   "volume size is 1 GiB, must be at least 10 GiB for provisioned IOPS",
+	}, {
+		size:  1024,
+		attrs: map[string]interface{}{"iops": "foo"},
+		err:   `invalid iops value foo, expected integer: .*`,
+	}, {
+		size:  1024,
+		attrs: map[string]interface{}{"encrypted": "maybe"},
+		err:   `invalid encrypted value maybe, expected boolean: .*`,
+	}} {
+		c.Logf("test %d", i)
+		err := s.source.ValidateVolumeParams(s.volumeParams("0", test.size, test.attrs))
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
@@ -99,7 +99,22 @@ func (e *environ) SetConfig(cfg *config.Config) error {
 	e.ecfgMutex.Lock()
 	defer e.ecfgMutex.Unlock()
 	e.ecfgUnlocked = ecfg
+	e.ec2Unlocked, e.s3Unlocked = awsClients(ecfg)
 
+	bucket, err := e.s3Unlocked.Bucket(ecfg.controlBucket())
+	if err != nil {
+		return err
+	}
+
+	// create new storage instances, existing instances continue
+	// to reference their existing configuration.
+	e.storageUnlocked = &ec2storage{bucket: bucket}
+	return nil
+}
+
+// awsClients returns the EC2 and S3 clients for the
+// region and credentials in the given configuration.
+func awsClients(ecfg *environConfig) (*ec2.EC2, *s3.S3) {
 	auth := aws.Auth{ecfg.accessKey(), ecfg.secretKey()}
 	region := aws.Regions[ecfg.region()]
 
@@ -110,18 +125,7 @@ func (e *environ) SetConfig(cfg *config.Config) error {
 	if region == aws.CNNorth {
 		signer = aws.SignV4Factory(region.Name, "ec2")
 	}
-	e.ec2Unlocked = ec2.New(auth, region, signer)
-	e.s3Unlocked = s3.New(auth, region)
-
-	bucket, err := e.s3Unlocked.Bucket(ecfg.controlBucket())
-	if err != nil {
-		return err
-	}
-
-	// create new storage instances, existing instances continue
-	// to reference their existing configuration.
-	e.storageUnlocked = &ec2storage{bucket: bucket}
-	return nil
+	return ec2.New(auth, region, signer), s3.New(auth, region)
 }
 
 func (e *environ) defaultVpc() (network.Id, bool, error) {
@@ -344,7 +348,11 @@ func (e *environ) parsePlacement(placement string) (*ec2Placement, error) {
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (e *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" {
-		if _, err := e.parsePlacement(placement); err != nil {
+		p, err := e.parsePlacement(placement)
+		if err != nil {
+			return err
+		}
+		if err := common.ValidatePlacementZone(p.availabilityZone.Name, cons); err != nil {
 			return err
 		}
 	}
@@ -413,6 +421,9 @@ var availabilityZoneAllocations = common.AvailabilityZoneAllocations
 
 // StartInstance is specified in the InstanceBroker interface.
 func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if err := common.ValidatePreemptible(args.Constraints, args.MachineConfig.Jobs); err != nil {
+		return nil, errors.Trace(err)
+	}
 	var availabilityZones []string
 	if args.Placement != "" {
 		placement, err := e.parsePlacement(args.Placement)
@@ -422,12 +433,15 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if placement.availabilityZone.State != "available" {
 			return nil, errors.Errorf("availability zone %q is %s", placement.availabilityZone.Name, placement.availabilityZone.State)
 		}
+		if err := common.ValidatePlacementZone(placement.availabilityZone.Name, args.Constraints); err != nil {
+			return nil, err
+		}
 		availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
 	}
 
 	// If no availability zone is specified, then automatically spread across
-	// the known zones for optimal spread across the instance distribution
-	// group.
+	// the known zones (limited to those allowed by the zones constraint)
+	// for optimal spread across the instance distribution group.
 	if len(availabilityZones) == 0 {
 		var group []instance.Id
 		var err error
@@ -441,6 +455,10 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if err != nil {
 			return nil, err
 		}
+		zoneInstances, err = common.ConstrainZoneAllocations(zoneInstances, args.Constraints)
+		if err != nil {
+			return nil, err
+		}
 		for _, z := range zoneInstances {
 			availabilityZones = append(availabilityZones, z.ZoneName)
 		}
@@ -500,7 +518,7 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 	rootDiskSize := uint64(blockDeviceMappings[0].VolumeSize) * 1024
 
 	for _, availZone := range availabilityZones {
-		instResp, err = runInstances(e.ec2(), &ec2.RunInstances{
+		ri := &ec2.RunInstances{
 			AvailZone:           availZone,
 			ImageId:             spec.Image.Id,
 			MinCount:            1,
@@ -509,7 +527,12 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 			InstanceType:        spec.InstanceType.Name,
 			SecurityGroups:      groups,
 			BlockDeviceMappings: blockDeviceMappings,
-		})
+		}
+		if args.Constraints.IsPreemptible() {
+			instResp, err = runSpotInstances(e.ec2(), ri, spotPrice(args.Constraints, spec.InstanceType))
+		} else {
+			instResp, err = runInstances(e.ec2(), ri)
+		}
 		if isZoneConstrainedError(err) {
 			logger.Infof("%q is constrained, trying another availability zone", availZone)
 		} else {
@@ -564,6 +587,17 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 
 var runInstances = _runInstances
 
+// spotPrice returns the maximum price, in US dollars per hour, to bid
+// for a spot instance of the given type. Unless the spot-price
+// constraint says otherwise, we bid up to the on-demand price.
+func spotPrice(cons constraints.Value, itype instances.InstanceType) float64 {
+	if cons.SpotPrice != nil && *cons.SpotPrice > 0 {
+		return *cons.SpotPrice
+	}
+	// Instance type costs are recorded in USDe-3/hour.
+	return float64(itype.Cost) / 1000
+}
+
 // runInstances calls ec2.RunInstances for a fixed number of attempts until
 // RunInstances returns an error code that does not indicate an error that
 // may be caused by eventual consistency.
@@ -1307,7 +1341,9 @@ func (m permSet) ipPerms() (ps []ec2.IPPerm) {
 // constrained for the instance type being provisioned, or is
 // otherwise unusable for the specific request made.
 func isZoneConstrainedError(err error) bool {
-	switch err := err.(type) {
+	switch err := errors.Cause(err).(type) {
+	case *spotRequestError:
+		return err.zoneConstrained()
 	case *ec2.Error:
 		switch err.Code {
 		case "Unsupported", "InsufficientInstanceCapacity":
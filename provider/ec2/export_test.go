@@ -22,6 +22,10 @@ func EBSProvider() jujustorage.Provider {
 	return &ebsProvider{}
 }
 
+func VolumeSourceEC2(source jujustorage.VolumeSource) *ec2.EC2 {
+	return source.(*ebsVolumeSource).ec2
+}
+
 func ControlBucketName(e environs.Environ) string {
 	return e.(*environ).ecfg().controlBucket()
 }
@@ -50,6 +54,7 @@ var (
 	EC2AvailabilityZones        = &ec2AvailabilityZones
 	AvailabilityZoneAllocations = &availabilityZoneAllocations
 	RunInstances                = &runInstances
+	RunSpotInstances            = &runSpotInstances
 	BlockDeviceNamer            = blockDeviceNamer
 	GetBlockDeviceMappings      = getBlockDeviceMappings
 )
@@ -579,6 +579,53 @@ func (t *localServerSuite) testStartInstanceAvailZoneOneConstrained(c *gc.C, run
 	c.Check(*hwc.AvailabilityZone, gc.Equals, "az2")
 }
 
+func (t *localServerSuite) TestStartInstancePreemptible(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The test server knows nothing of spot requests, so start
+	// an ordinary instance in place of the spot instance.
+	var prices []float64
+	realRunInstances := *ec2.RunInstances
+	t.PatchValue(ec2.RunInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances) (*amzec2.RunInstancesResp, error) {
+		c.Errorf("on-demand instance started for preemptible machine")
+		return realRunInstances(e, ri)
+	})
+	t.PatchValue(ec2.RunSpotInstances, func(e *amzec2.EC2, ri *amzec2.RunInstances, price float64) (*amzec2.RunInstancesResp, error) {
+		prices = append(prices, price)
+		return e.RunInstances(ri)
+	})
+
+	testing.AssertStartInstanceWithConstraints(c, env, "1", constraints.MustParse("preemptible=true"))
+	testing.AssertStartInstanceWithConstraints(c, env, "2", constraints.MustParse("preemptible=true spot-price=0.005"))
+	c.Assert(prices, gc.HasLen, 2)
+	// Without a spot price we bid up to the on-demand price.
+	c.Check(prices[0], jc.GreaterThan, 0.0)
+	c.Check(prices[1], gc.Equals, 0.005)
+}
+
+func (t *localServerSuite) TestStartInstanceSpotPriceWithoutPreemptible(c *gc.C) {
+	env := t.Prepare(c)
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, _, _, err = testing.StartInstanceWithConstraints(env, "1", constraints.MustParse("spot-price=0.005"))
+	c.Assert(err, gc.ErrorMatches, "spot-price constraint without preemptible=true not valid")
+}
+
+func (t *localServerSuite) TestBootstrapPreemptibleStateServer(c *gc.C) {
+	env := t.Prepare(c)
+	t.PatchValue(ec2.RunSpotInstances, func(*amzec2.EC2, *amzec2.RunInstances, float64) (*amzec2.RunInstancesResp, error) {
+		c.Fatalf("spot instance requested for state server")
+		return nil, nil
+	})
+	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{
+		Constraints: constraints.MustParse("preemptible=true"),
+	})
+	c.Assert(err, gc.ErrorMatches, ".*preemptible instances for state servers not supported")
+}
+
 func (t *localServerSuite) TestAddresses(c *gc.C) {
 	env := t.Prepare(c)
 	err := bootstrap.Bootstrap(envtesting.BootstrapContext(c), env, bootstrap.BootstrapParams{})
@@ -0,0 +1,351 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/ec2"
+
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+)
+
+// The amz ec2 package has no support for spot instances, so the few
+// calls we need are made directly against the EC2 query API, signed
+// with the same credentials as all the other requests.
+
+const spotAPIVersion = "2014-10-01"
+
+// Spot instance request states.
+const (
+	spotStateOpen      = "open"
+	spotStateActive    = "active"
+	spotStateClosed    = "closed"
+	spotStateCancelled = "cancelled"
+	spotStateFailed    = "failed"
+)
+
+// reclaimedSpotStatusCodes holds the spot request status codes that
+// EC2 reports when it has terminated (or is about to terminate) a spot
+// instance to take back its capacity.
+var reclaimedSpotStatusCodes = set.NewStrings(
+	"marked-for-termination",
+	"instance-terminated-by-price",
+	"instance-terminated-no-capacity",
+	"instance-terminated-capacity-oversubscribed",
+	"instance-terminated-launch-group-constraint",
+)
+
+// spotAttempt governs how long we wait for a spot instance request to
+// be fulfilled before giving up on it.
+var spotAttempt = utils.AttemptStrategy{
+	Total: 5 * time.Minute,
+	Delay: 5 * time.Second,
+}
+
+// spotInstanceRequest describes a single spot instance request.
+type spotInstanceRequest struct {
+	Id            string `xml:"spotInstanceRequestId"`
+	State         string `xml:"state"`
+	StatusCode    string `xml:"status>code"`
+	StatusMessage string `xml:"status>message"`
+	InstanceId    string `xml:"instanceId"`
+}
+
+// spotInstanceRequestsResp is the response to both
+// RequestSpotInstances and DescribeSpotInstanceRequests.
+type spotInstanceRequestsResp struct {
+	RequestId string                `xml:"requestId"`
+	Requests  []spotInstanceRequest `xml:"spotInstanceRequestSet>item"`
+}
+
+// cancelSpotInstanceRequestsResp is the response to
+// CancelSpotInstanceRequests.
+type cancelSpotInstanceRequestsResp struct {
+	RequestId string `xml:"requestId"`
+}
+
+var runSpotInstances = _runSpotInstances
+
+// runSpotInstances requests a single one-time spot instance matching
+// ri, bidding at most price US dollars per hour, and waits for it to
+// be started. If the request is not fulfilled in time it is
+// cancelled, and any instance started in the meantime is terminated.
+func _runSpotInstances(e *ec2.EC2, ri *ec2.RunInstances, price float64) (*ec2.RunInstancesResp, error) {
+	req, err := requestSpotInstance(e, ri, price)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot request spot instance")
+	}
+	logger.Infof("requested spot instance (%s) at up to $%v/hour", req.Id, price)
+	instId, err := waitSpotInstance(e, req)
+	if err != nil {
+		if err := cancelSpotInstanceRequest(e, req.Id); err != nil {
+			logger.Errorf("cannot cancel spot instance request %s: %v", req.Id, err)
+		}
+		return nil, errors.Trace(err)
+	}
+	var resp *ec2.InstancesResp
+	for a := shortAttempt.Start(); a.Next(); {
+		resp, err = e.Instances([]string{instId}, nil)
+		if err == nil && len(resp.Reservations) > 0 {
+			break
+		}
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get spot instance %s", instId)
+	}
+	if len(resp.Reservations) == 0 {
+		return nil, errors.NotFoundf("spot instance %s", instId)
+	}
+	return &ec2.RunInstancesResp{
+		ReservationId: resp.Reservations[0].ReservationId,
+		Instances:     resp.Reservations[0].Instances,
+	}, nil
+}
+
+// requestSpotInstance issues a RequestSpotInstances call for a single
+// instance with the launch specification described by ri.
+func requestSpotInstance(e *ec2.EC2, ri *ec2.RunInstances, price float64) (*spotInstanceRequest, error) {
+	params := map[string]string{
+		"Action":        "RequestSpotInstances",
+		"SpotPrice":     strconv.FormatFloat(price, 'f', -1, 64),
+		"InstanceCount": "1",
+		"Type":          "one-time",
+	}
+	const spec = "LaunchSpecification."
+	params[spec+"ImageId"] = ri.ImageId
+	params[spec+"InstanceType"] = ri.InstanceType
+	if ri.AvailZone != "" {
+		params[spec+"Placement.AvailabilityZone"] = ri.AvailZone
+	}
+	if ri.UserData != nil {
+		params[spec+"UserData"] = base64.StdEncoding.EncodeToString(ri.UserData)
+	}
+	i, j := 1, 1
+	for _, g := range ri.SecurityGroups {
+		if g.Id != "" {
+			params[spec+"SecurityGroupId."+strconv.Itoa(i)] = g.Id
+			i++
+		} else {
+			params[spec+"SecurityGroup."+strconv.Itoa(j)] = g.Name
+			j++
+		}
+	}
+	for i, b := range ri.BlockDeviceMappings {
+		prefix := spec + "BlockDeviceMapping." + strconv.Itoa(i+1) + "."
+		if b.DeviceName != "" {
+			params[prefix+"DeviceName"] = b.DeviceName
+		}
+		if b.VirtualName != "" {
+			params[prefix+"VirtualName"] = b.VirtualName
+		}
+		if b.SnapshotId != "" {
+			params[prefix+"Ebs.SnapshotId"] = b.SnapshotId
+		}
+		if b.VolumeType != "" {
+			params[prefix+"Ebs.VolumeType"] = b.VolumeType
+		}
+		if b.VolumeSize > 0 {
+			params[prefix+"Ebs.VolumeSize"] = strconv.FormatInt(b.VolumeSize, 10)
+		}
+		if b.IOPS > 0 {
+			params[prefix+"Ebs.Iops"] = strconv.FormatInt(b.IOPS, 10)
+		}
+		if b.DeleteOnTermination {
+			params[prefix+"Ebs.DeleteOnTermination"] = "true"
+		}
+	}
+	var resp spotInstanceRequestsResp
+	if err := spotQuery(e, params, &resp); err != nil {
+		return nil, err
+	}
+	if len(resp.Requests) != 1 {
+		return nil, errors.Errorf("expected 1 spot instance request, got %d", len(resp.Requests))
+	}
+	return &resp.Requests[0], nil
+}
+
+// waitSpotInstance waits for the given spot instance request to be
+// fulfilled, and returns the id of the instance started for it.
+func waitSpotInstance(e *ec2.EC2, req *spotInstanceRequest) (string, error) {
+	for a := spotAttempt.Start(); ; {
+		switch req.State {
+		case spotStateActive:
+			if req.InstanceId != "" {
+				return req.InstanceId, nil
+			}
+		case spotStateClosed, spotStateCancelled, spotStateFailed:
+			return "", &spotRequestError{req}
+		}
+		logger.Debugf("spot instance request %s is %s: %s", req.Id, req.State, req.StatusCode)
+		if !a.Next() {
+			return "", &spotRequestError{req}
+		}
+		reqs, err := describeSpotInstanceRequests(e, map[string]string{
+			"SpotInstanceRequestId.1": req.Id,
+		})
+		if err != nil {
+			logger.Warningf("cannot get spot instance request %s: %v", req.Id, err)
+			continue
+		}
+		if len(reqs) == 1 {
+			req = &reqs[0]
+		}
+	}
+}
+
+// zoneConstrainedSpotStatusCodes holds the spot request status codes
+// that EC2 reports when a request cannot be fulfilled in its
+// availability zone, but might be in another. Spot prices are set
+// per zone, so a bid too low in one zone may be enough in another.
+var zoneConstrainedSpotStatusCodes = set.NewStrings(
+	"capacity-not-available",
+	"capacity-oversubscribed",
+	"price-too-low",
+)
+
+// spotRequestError describes why a spot instance request has not
+// been fulfilled.
+type spotRequestError struct {
+	req *spotInstanceRequest
+}
+
+// Error is part of the error interface.
+func (e *spotRequestError) Error() string {
+	msg := e.req.StatusMessage
+	if msg == "" {
+		msg = e.req.StatusCode
+	}
+	return fmt.Sprintf("spot instance request %s not fulfilled (%s): %s", e.req.Id, e.req.State, msg)
+}
+
+// zoneConstrained reports whether the request was not fulfilled
+// because of a shortage in its availability zone.
+func (e *spotRequestError) zoneConstrained() bool {
+	return zoneConstrainedSpotStatusCodes.Contains(e.req.StatusCode)
+}
+
+// cancelSpotInstanceRequest cancels the given spot instance request,
+// terminating the instance started for it if there is one.
+func cancelSpotInstanceRequest(e *ec2.EC2, id string) error {
+	var resp cancelSpotInstanceRequestsResp
+	if err := spotQuery(e, map[string]string{
+		"Action":                  "CancelSpotInstanceRequests",
+		"SpotInstanceRequestId.1": id,
+	}, &resp); err != nil {
+		return err
+	}
+	// Cancelling a request does not terminate an instance that was
+	// started for it just before we gave up.
+	reqs, err := describeSpotInstanceRequests(e, map[string]string{
+		"SpotInstanceRequestId.1": id,
+	})
+	if err != nil {
+		return err
+	}
+	for _, req := range reqs {
+		if req.InstanceId != "" {
+			if _, err := e.TerminateInstances([]string{req.InstanceId}); err != nil {
+				return errors.Annotatef(err, "cannot terminate spot instance %s", req.InstanceId)
+			}
+		}
+	}
+	return nil
+}
+
+// describeSpotInstanceRequests returns the spot instance requests
+// selected by the given DescribeSpotInstanceRequests parameters.
+func describeSpotInstanceRequests(e *ec2.EC2, params map[string]string) ([]spotInstanceRequest, error) {
+	params["Action"] = "DescribeSpotInstanceRequests"
+	var resp spotInstanceRequestsResp
+	if err := spotQuery(e, params, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Requests, nil
+}
+
+var _ environs.InstanceReclaimer = (*environ)(nil)
+
+// ReclaimedInstances is specified in the environs.InstanceReclaimer
+// interface.
+func (e *environ) ReclaimedInstances(ids []instance.Id) ([]instance.Id, error) {
+	return reclaimedInstances(e.ec2(), ids)
+}
+
+// reclaimedInstances returns those of the given instance ids whose
+// spot instance requests report that EC2 has taken the instance back.
+func reclaimedInstances(e *ec2.EC2, ids []instance.Id) ([]instance.Id, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	params := map[string]string{
+		"Filter.1.Name": "instance-id",
+	}
+	for i, id := range ids {
+		params["Filter.1.Value."+strconv.Itoa(i+1)] = string(id)
+	}
+	reqs, err := describeSpotInstanceRequests(e, params)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get spot instance requests")
+	}
+	var reclaimed []instance.Id
+	for _, req := range reqs {
+		if req.InstanceId != "" && reclaimedSpotStatusCodes.Contains(req.StatusCode) {
+			reclaimed = append(reclaimed, instance.Id(req.InstanceId))
+		}
+	}
+	return reclaimed, nil
+}
+
+// spotQuery issues a signed EC2 query API request with the given
+// parameters, and decodes the response into resp.
+func spotQuery(e *ec2.EC2, params map[string]string, resp interface{}) error {
+	req, err := http.NewRequest("GET", e.Region.EC2Endpoint, nil)
+	if err != nil {
+		return err
+	}
+	now := time.Now().In(time.UTC)
+	query := req.URL.Query()
+	for name, value := range params {
+		query.Add(name, value)
+	}
+	query.Add("Version", spotAPIVersion)
+	query.Add("Timestamp", now.Format(time.RFC3339))
+	req.URL.RawQuery = query.Encode()
+	req.Header.Set("x-amz-date", now.Format(aws.ISO8601BasicFormat))
+	if err := e.Sign(req, e.Auth); err != nil {
+		return err
+	}
+	r, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer r.Body.Close()
+	if r.StatusCode != http.StatusOK {
+		var errResp struct {
+			Errors []ec2.Error `xml:"Errors>Error"`
+		}
+		xml.NewDecoder(r.Body).Decode(&errResp)
+		var ec2err ec2.Error
+		if len(errResp.Errors) > 0 {
+			ec2err = errResp.Errors[0]
+		}
+		ec2err.StatusCode = r.StatusCode
+		if ec2err.Message == "" {
+			ec2err.Message = r.Status
+		}
+		return &ec2err
+	}
+	return xml.NewDecoder(r.Body).Decode(resp)
+}
@@ -0,0 +1,262 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ec2
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils"
+	"gopkg.in/amz.v3/aws"
+	amzec2 "gopkg.in/amz.v3/ec2"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type spotSuite struct {
+	coretesting.BaseSuite
+
+	server *httptest.Server
+	ec2    *amzec2.EC2
+
+	// mu guards the following fields, which are
+	// used by the test server.
+	mu        sync.Mutex
+	requests  []url.Values
+	responses map[string][]string
+}
+
+var _ = gc.Suite(&spotSuite{})
+
+func (s *spotSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.requests = nil
+	s.responses = make(map[string][]string)
+	s.server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.AddCleanup(func(*gc.C) { s.server.Close() })
+	s.ec2 = amzec2.New(
+		aws.Auth{AccessKey: "x", SecretKey: "x"},
+		aws.Region{Name: "test", EC2Endpoint: s.server.URL},
+		aws.SignV2,
+	)
+	s.PatchValue(&spotAttempt, utils.AttemptStrategy{Total: 50 * time.Millisecond, Delay: 10 * time.Millisecond})
+	s.PatchValue(&shortAttempt, utils.AttemptStrategy{})
+}
+
+// serveHTTP records each request and replies with the next queued
+// response for its action; the last response for an action is
+// repeated once the others have been used.
+func (s *spotSuite) serveHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := req.URL.Query()
+	s.requests = append(s.requests, query)
+	action := query.Get("Action")
+	responses := s.responses[action]
+	if len(responses) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprintf(w, `<Response><Errors><Error><Code>InvalidAction</Code><Message>unexpected %s</Message></Error></Errors></Response>`, action)
+		return
+	}
+	if len(responses) > 1 {
+		s.responses[action] = responses[1:]
+	}
+	fmt.Fprint(w, responses[0])
+}
+
+func (s *spotSuite) actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var actions []string
+	for _, req := range s.requests {
+		actions = append(actions, req.Get("Action"))
+	}
+	return actions
+}
+
+func spotRequestsXML(state, code, instanceId string) string {
+	return fmt.Sprintf(`
+<DescribeSpotInstanceRequestsResponse>
+  <requestId>req</requestId>
+  <spotInstanceRequestSet>
+    <item>
+      <spotInstanceRequestId>sir-1</spotInstanceRequestId>
+      <state>%s</state>
+      <status><code>%s</code><message>status %s</message></status>
+      <instanceId>%s</instanceId>
+    </item>
+  </spotInstanceRequestSet>
+</DescribeSpotInstanceRequestsResponse>`, state, code, code, instanceId)
+}
+
+const describeInstancesXML = `
+<DescribeInstancesResponse>
+  <requestId>req</requestId>
+  <reservationSet>
+    <item>
+      <reservationId>r-1</reservationId>
+      <instancesSet>
+        <item>
+          <instanceId>i-1</instanceId>
+          <instanceState><code>0</code><name>pending</name></instanceState>
+          <placement><availabilityZone>az1</availabilityZone></placement>
+        </item>
+      </instancesSet>
+    </item>
+  </reservationSet>
+</DescribeInstancesResponse>`
+
+var testRunInstances = &amzec2.RunInstances{
+	AvailZone:      "az1",
+	ImageId:        "ami-1",
+	InstanceType:   "m1.small",
+	UserData:       []byte("#cloud-config"),
+	SecurityGroups: []amzec2.SecurityGroup{{Id: "sg-1"}, {Name: "juju-machine-1"}},
+	BlockDeviceMappings: []amzec2.BlockDeviceMapping{{
+		DeviceName: "/dev/sda1",
+		VolumeSize: 8,
+	}},
+}
+
+func (s *spotSuite) TestRunSpotInstances(c *gc.C) {
+	s.responses["RequestSpotInstances"] = []string{spotRequestsXML("open", "pending-evaluation", "")}
+	s.responses["DescribeSpotInstanceRequests"] = []string{spotRequestsXML("active", "fulfilled", "i-1")}
+	s.responses["DescribeInstances"] = []string{describeInstancesXML}
+
+	resp, err := runSpotInstances(s.ec2, testRunInstances, 0.05)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resp.Instances, gc.HasLen, 1)
+	c.Assert(resp.Instances[0].InstanceId, gc.Equals, "i-1")
+	c.Assert(s.actions(), jc.DeepEquals, []string{
+		"RequestSpotInstances", "DescribeSpotInstanceRequests", "DescribeInstances",
+	})
+
+	req := s.requests[0]
+	c.Check(req.Get("Version"), gc.Equals, spotAPIVersion)
+	c.Check(req.Get("SpotPrice"), gc.Equals, "0.05")
+	c.Check(req.Get("InstanceCount"), gc.Equals, "1")
+	c.Check(req.Get("Type"), gc.Equals, "one-time")
+	c.Check(req.Get("LaunchSpecification.ImageId"), gc.Equals, "ami-1")
+	c.Check(req.Get("LaunchSpecification.InstanceType"), gc.Equals, "m1.small")
+	c.Check(req.Get("LaunchSpecification.Placement.AvailabilityZone"), gc.Equals, "az1")
+	c.Check(req.Get("LaunchSpecification.UserData"), gc.Equals, base64.StdEncoding.EncodeToString([]byte("#cloud-config")))
+	c.Check(req.Get("LaunchSpecification.SecurityGroupId.1"), gc.Equals, "sg-1")
+	c.Check(req.Get("LaunchSpecification.SecurityGroup.1"), gc.Equals, "juju-machine-1")
+	c.Check(req.Get("LaunchSpecification.BlockDeviceMapping.1.DeviceName"), gc.Equals, "/dev/sda1")
+	c.Check(req.Get("LaunchSpecification.BlockDeviceMapping.1.Ebs.VolumeSize"), gc.Equals, "8")
+	c.Check(s.requests[1].Get("SpotInstanceRequestId.1"), gc.Equals, "sir-1")
+}
+
+func (s *spotSuite) TestRunSpotInstancesRequestFailed(c *gc.C) {
+	s.responses["RequestSpotInstances"] = []string{spotRequestsXML("open", "pending-evaluation", "")}
+	s.responses["DescribeSpotInstanceRequests"] = []string{spotRequestsXML("failed", "bad-parameters", "")}
+	s.responses["CancelSpotInstanceRequests"] = []string{`<CancelSpotInstanceRequestsResponse/>`}
+
+	_, err := runSpotInstances(s.ec2, testRunInstances, 0.05)
+	c.Assert(err, gc.ErrorMatches, `spot instance request sir-1 not fulfilled \(failed\): status bad-parameters`)
+	c.Assert(isZoneConstrainedError(err), jc.IsFalse)
+	c.Assert(s.actions(), jc.DeepEquals, []string{
+		"RequestSpotInstances", "DescribeSpotInstanceRequests",
+		"CancelSpotInstanceRequests", "DescribeSpotInstanceRequests",
+	})
+}
+
+func (s *spotSuite) TestRunSpotInstancesTimeout(c *gc.C) {
+	s.responses["RequestSpotInstances"] = []string{spotRequestsXML("open", "price-too-low", "")}
+	s.responses["DescribeSpotInstanceRequests"] = []string{
+		spotRequestsXML("open", "price-too-low", ""),
+	}
+	s.responses["CancelSpotInstanceRequests"] = []string{`<CancelSpotInstanceRequestsResponse/>`}
+
+	_, err := runSpotInstances(s.ec2, testRunInstances, 0.001)
+	c.Assert(err, gc.ErrorMatches, `spot instance request sir-1 not fulfilled \(open\): status price-too-low`)
+	c.Assert(isZoneConstrainedError(err), jc.IsTrue)
+	actions := s.actions()
+	c.Assert(actions[len(actions)-2:], jc.DeepEquals, []string{
+		"CancelSpotInstanceRequests", "DescribeSpotInstanceRequests",
+	})
+}
+
+func (s *spotSuite) TestRunSpotInstancesNoCapacity(c *gc.C) {
+	s.responses["RequestSpotInstances"] = []string{spotRequestsXML("open", "pending-evaluation", "")}
+	s.responses["DescribeSpotInstanceRequests"] = []string{
+		spotRequestsXML("closed", "capacity-not-available", ""),
+	}
+	s.responses["CancelSpotInstanceRequests"] = []string{`<CancelSpotInstanceRequestsResponse/>`}
+
+	// Another zone may have capacity, so StartInstance should
+	// try the next one.
+	_, err := runSpotInstances(s.ec2, testRunInstances, 0.05)
+	c.Assert(err, gc.ErrorMatches, `spot instance request sir-1 not fulfilled \(closed\): status capacity-not-available`)
+	c.Assert(isZoneConstrainedError(err), jc.IsTrue)
+}
+
+func (s *spotSuite) TestRunSpotInstancesTerminatesLateInstance(c *gc.C) {
+	s.responses["RequestSpotInstances"] = []string{spotRequestsXML("open", "price-too-low", "")}
+	s.responses["DescribeSpotInstanceRequests"] = []string{
+		spotRequestsXML("failed", "bad-parameters", ""),
+		spotRequestsXML("cancelled", "request-canceled-and-instance-running", "i-1"),
+	}
+	s.responses["CancelSpotInstanceRequests"] = []string{`<CancelSpotInstanceRequestsResponse/>`}
+	s.responses["TerminateInstances"] = []string{`<TerminateInstancesResponse/>`}
+
+	_, err := runSpotInstances(s.ec2, testRunInstances, 0.05)
+	c.Assert(err, gc.ErrorMatches, `spot instance request sir-1 not fulfilled .*`)
+	actions := s.actions()
+	c.Assert(actions[len(actions)-1], gc.Equals, "TerminateInstances")
+	c.Assert(s.requests[len(s.requests)-1].Get("InstanceId.1"), gc.Equals, "i-1")
+}
+
+func (s *spotSuite) TestRequestSpotInstanceError(c *gc.C) {
+	_, err := runSpotInstances(s.ec2, testRunInstances, 0.05)
+	c.Assert(err, gc.ErrorMatches, `cannot request spot instance: unexpected RequestSpotInstances \(InvalidAction\)`)
+	c.Assert(ec2ErrCode(errors.Cause(err)), gc.Equals, "InvalidAction")
+}
+
+func (s *spotSuite) TestReclaimedInstances(c *gc.C) {
+	s.responses["DescribeSpotInstanceRequests"] = []string{`
+<DescribeSpotInstanceRequestsResponse>
+  <spotInstanceRequestSet>
+    <item>
+      <spotInstanceRequestId>sir-1</spotInstanceRequestId>
+      <state>closed</state>
+      <status><code>instance-terminated-by-price</code></status>
+      <instanceId>i-1</instanceId>
+    </item>
+    <item>
+      <spotInstanceRequestId>sir-2</spotInstanceRequestId>
+      <state>closed</state>
+      <status><code>instance-terminated-by-user</code></status>
+      <instanceId>i-2</instanceId>
+    </item>
+  </spotInstanceRequestSet>
+</DescribeSpotInstanceRequestsResponse>`}
+
+	reclaimed, err := reclaimedInstances(s.ec2, []instance.Id{"i-1", "i-2", "i-3"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reclaimed, jc.DeepEquals, []instance.Id{"i-1"})
+
+	c.Assert(s.requests, gc.HasLen, 1)
+	req := s.requests[0]
+	c.Check(req.Get("Filter.1.Name"), gc.Equals, "instance-id")
+	c.Check(req.Get("Filter.1.Value.1"), gc.Equals, "i-1")
+	c.Check(req.Get("Filter.1.Value.2"), gc.Equals, "i-2")
+	c.Check(req.Get("Filter.1.Value.3"), gc.Equals, "i-3")
+}
+
+func (s *spotSuite) TestReclaimedInstancesNoIds(c *gc.C) {
+	reclaimed, err := reclaimedInstances(s.ec2, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reclaimed, gc.HasLen, 0)
+	c.Assert(s.requests, gc.HasLen, 0)
+}
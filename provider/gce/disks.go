@@ -0,0 +1,329 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/poolmanager"
+)
+
+const (
+	// GCEProviderType is the storage provider type for GCE
+	// persistent disks.
+	GCEProviderType = storage.ProviderType("gce")
+
+	// GCEDiskType is the pool attribute that selects the kind of
+	// persistent disk to create: pd-standard (the default) or pd-ssd.
+	GCEDiskType = "disk-type"
+
+	// volumeIdSeparator separates the zone from the disk name in a
+	// volume ID. GCE disks are zonal resources, so every request
+	// about a disk needs to know the zone it lives in.
+	volumeIdSeparator = "--"
+)
+
+func init() {
+	ssdPool, _ := storage.NewConfig("gce-ssd", GCEProviderType, map[string]interface{}{
+		GCEDiskType: google.DiskPersistentSSD,
+	})
+	poolmanager.RegisterDefaultStoragePools([]*storage.Config{ssdPool})
+}
+
+// storageProvider creates volume sources which use GCE persistent
+// disks.
+type storageProvider struct{}
+
+var _ storage.Provider = (*storageProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (*storageProvider) ValidateConfig(cfg *storage.Config) error {
+	for attr, value := range cfg.Attrs() {
+		if attr != GCEDiskType {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if _, err := diskType(value); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (*storageProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// VolumeSource is defined on the Provider interface.
+func (*storageProvider) VolumeSource(environConfig *config.Config, cfg *storage.Config) (storage.VolumeSource, error) {
+	env, err := newEnviron(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	source := &volumeSource{
+		gce:        env.gce,
+		envUUID:    env.uuid,
+		instPrefix: common.MachineFullName(env, ""),
+	}
+	return source, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (*storageProvider) FilesystemSource(environConfig *config.Config, cfg *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// diskType returns the kind of persistent disk named by the given
+// pool attribute value.
+func diskType(value interface{}) (string, error) {
+	switch value {
+	case nil, "":
+		return google.DiskPersistentStandard, nil
+	case google.DiskPersistentStandard, google.DiskPersistentSSD:
+		return value.(string), nil
+	}
+	return "", errors.NotValidf("%s %v", GCEDiskType, value)
+}
+
+// volumeSource creates, attaches and destroys GCE persistent disks.
+type volumeSource struct {
+	gce        gceConnection
+	envUUID    string
+	instPrefix string
+}
+
+var _ storage.VolumeSource = (*volumeSource)(nil)
+
+// diskName returns the name of the GCE disk backing the given volume.
+// The environment UUID is included so that disks from different
+// environments in the same project do not collide.
+func (v *volumeSource) diskName(tag names.VolumeTag) string {
+	return fmt.Sprintf("juju-%s-%s", v.envUUID, tag.String())
+}
+
+func formatVolumeId(zone, diskName string) string {
+	return zone + volumeIdSeparator + diskName
+}
+
+func parseVolumeId(volId string) (zone, diskName string, err error) {
+	parts := strings.SplitN(volId, volumeIdSeparator, 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.NotValidf("volume ID %q", volId)
+	}
+	return parts[0], parts[1], nil
+}
+
+// newVolume converts a GCE disk into a storage.Volume.
+func newVolume(tag names.VolumeTag, disk *google.Disk) storage.Volume {
+	return storage.Volume{
+		Tag:      tag,
+		VolumeId: formatVolumeId(disk.ZoneName, disk.Name),
+		// GCE exposes attached disks under /dev/disk/by-id using
+		// the device name, which we always set to the disk name.
+		Serial: "google-" + disk.Name,
+		Size:   disk.SizeGB * 1024,
+	}
+}
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	// GCE disks must be created in the same zone as the instance
+	// they are attached to, so we only support creating volumes
+	// along with an attachment.
+	instIds := make([]instance.Id, len(params))
+	for i, p := range params {
+		if err := v.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Trace(err)
+		}
+		if p.Attachment == nil || p.Attachment.InstanceId == "" {
+			return nil, nil, errors.NotSupportedf("creating volume %s without an instance", p.Tag.Id())
+		}
+		instIds[i] = p.Attachment.InstanceId
+	}
+	zones, err := v.instanceZones()
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+
+	volumes := make([]storage.Volume, 0, len(params))
+	defer func() {
+		if err == nil {
+			return
+		}
+		for _, vol := range volumes {
+			zone, name, _ := parseVolumeId(vol.VolumeId)
+			if err := v.gce.RemoveDisk(zone, name); err != nil {
+				logger.Warningf("failed to remove disk %q: %v", name, err)
+			}
+		}
+	}()
+
+	for i, p := range params {
+		zone, ok := zones[instIds[i]]
+		if !ok {
+			return nil, nil, errors.NotFoundf("instance %q", instIds[i])
+		}
+		pdType, _ := diskType(p.Attributes[GCEDiskType])
+		spec := google.DiskSpec{
+			Name:               v.diskName(p.Tag),
+			SizeHintGB:         common.MiBToGiB(p.Size),
+			PersistentDiskType: pdType,
+		}
+		disk, err := v.gce.AddDisk(zone, spec)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+		}
+		volumes = append(volumes, newVolume(p.Tag, disk))
+	}
+
+	attachParams := make([]storage.VolumeAttachmentParams, len(params))
+	for i, p := range params {
+		attachParams[i] = *p.Attachment
+		attachParams[i].Volume = p.Tag
+		attachParams[i].VolumeId = volumes[i].VolumeId
+	}
+	attachments, err := v.AttachVolumes(attachParams)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return volumes, attachments, nil
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	volumes := make([]storage.Volume, len(volIds))
+	for i, volId := range volIds {
+		zone, name, err := parseVolumeId(volId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		disk, err := v.gce.Disk(zone, name)
+		if err != nil {
+			return nil, errors.Annotatef(err, "describing volume %q", volId)
+		}
+		volumes[i] = newVolume(names.VolumeTag{}, disk)
+	}
+	return volumes, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DestroyVolumes(volIds []string) []error {
+	results := make([]error, len(volIds))
+	for i, volId := range volIds {
+		zone, name, err := parseVolumeId(volId)
+		if err != nil {
+			results[i] = errors.Trace(err)
+			continue
+		}
+		results[i] = errors.Annotatef(v.gce.RemoveDisk(zone, name), "destroying volume %q", volId)
+	}
+	return results
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (v *volumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	for attr, value := range params.Attributes {
+		if attr != GCEDiskType {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+		if _, err := diskType(value); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	results := make([]storage.VolumeAttachment, len(params))
+	for i, p := range params {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		instId := string(p.InstanceId)
+		attached, err := v.attachedDisk(zone, instId, name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if attached == nil {
+			// The disk must be in the same zone as the instance;
+			// GCE rejects the request otherwise.
+			attached, err = v.gce.AttachDisk(zone, name, instId, false)
+			if err != nil {
+				return nil, errors.Annotatef(err, "attaching volume %s to %s", p.Volume.Id(), p.Machine.Id())
+			}
+		}
+		results[i] = storage.VolumeAttachment{
+			Volume:  p.Volume,
+			Machine: p.Machine,
+			// The kernel's name for the device (e.g. sdb) is not
+			// stable across reboots, so we leave it blank and rely
+			// on the volume's serial instead.
+			ReadOnly: attached.Readonly,
+		}
+	}
+	return results, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+func (v *volumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) error {
+	for _, p := range params {
+		zone, name, err := parseVolumeId(p.VolumeId)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		instId := string(p.InstanceId)
+		attached, err := v.attachedDisk(zone, instId, name)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if attached == nil {
+			// Already detached.
+			continue
+		}
+		if err := v.gce.DetachDisk(zone, instId, attached.DeviceName); err != nil {
+			return errors.Annotatef(err, "detaching volume %s from %s", p.Volume.Id(), p.Machine.Id())
+		}
+	}
+	return nil
+}
+
+// attachedDisk returns the named disk's attachment to the identified
+// instance, or nil if the disk is not attached to it.
+func (v *volumeSource) attachedDisk(zone, instId, diskName string) (*google.AttachedDisk, error) {
+	disks, err := v.gce.InstanceDisks(zone, instId)
+	if err != nil {
+		return nil, errors.Annotatef(err, "getting disks of instance %q", instId)
+	}
+	for _, disk := range disks {
+		if disk.DiskName == diskName {
+			return &disk, nil
+		}
+	}
+	return nil, nil
+}
+
+// instanceZones returns the zone of each of the environment's
+// instances, keyed by instance ID.
+func (v *volumeSource) instanceZones() (map[instance.Id]string, error) {
+	instances, err := v.gce.Instances(v.instPrefix, instStatuses...)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting instances")
+	}
+	zones := make(map[instance.Id]string)
+	for _, inst := range instances {
+		zones[instance.Id(inst.ID)] = inst.ZoneName
+	}
+	return zones, nil
+}
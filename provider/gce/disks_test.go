@@ -0,0 +1,261 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gce_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/gce"
+	"github.com/juju/juju/provider/gce/google"
+	"github.com/juju/juju/storage"
+)
+
+type disksSuite struct {
+	gce.BaseSuite
+
+	provider storage.Provider
+	source   storage.VolumeSource
+	diskName string
+	disk     *google.Disk
+}
+
+var _ = gc.Suite(&disksSuite{})
+
+func (s *disksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.provider = gce.NewStorageProvider()
+	cfg, err := storage.NewConfig("gce", gce.GCEProviderType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source, err = s.provider.VolumeSource(s.Config, cfg)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The source connects on creation.
+	s.FakeConn.Calls = nil
+	s.FakeConn.Insts = []google.Instance{*s.BaseInstance}
+	s.diskName = s.Prefix + "volume-0"
+	s.disk = &google.Disk{
+		Name:     s.diskName,
+		ZoneName: "home-zone",
+		Type:     google.DiskPersistentSSD,
+		SizeGB:   2,
+		Status:   google.DiskStatusReady,
+	}
+	s.FakeConn.Disk = s.disk
+}
+
+func (s *disksSuite) volumeParams(attrs map[string]interface{}) storage.VolumeParams {
+	return storage.VolumeParams{
+		Tag:        names.NewVolumeTag("0"),
+		Size:       2000,
+		Provider:   gce.GCEProviderType,
+		Attributes: attrs,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("0"),
+				InstanceId: instance.Id("spam"),
+			},
+			Volume: names.NewVolumeTag("0"),
+		},
+	}
+}
+
+func (s *disksSuite) TestValidateConfig(c *gc.C) {
+	for _, diskType := range []string{"pd-standard", "pd-ssd"} {
+		cfg, err := storage.NewConfig("foo", gce.GCEProviderType, map[string]interface{}{
+			"disk-type": diskType,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(s.provider.ValidateConfig(cfg), jc.ErrorIsNil)
+	}
+}
+
+func (s *disksSuite) TestValidateConfigInvalid(c *gc.C) {
+	cfg, err := storage.NewConfig("foo", gce.GCEProviderType, map[string]interface{}{
+		"disk-type": "local-ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Check(err, gc.ErrorMatches, "disk-type local-ssd not valid")
+
+	cfg, err = storage.NewConfig("foo", gce.GCEProviderType, map[string]interface{}{
+		"iops": 30,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.provider.ValidateConfig(cfg)
+	c.Check(err, gc.ErrorMatches, `unknown provider config option "iops"`)
+}
+
+func (s *disksSuite) TestSupports(c *gc.C) {
+	c.Check(s.provider.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Check(s.provider.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+func (s *disksSuite) TestCreateVolumes(c *gc.C) {
+	params := s.volumeParams(map[string]interface{}{"disk-type": "pd-ssd"})
+	volumes, attachments, err := s.source.CreateVolumes([]storage.VolumeParams{params})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		Tag:      names.NewVolumeTag("0"),
+		VolumeId: "home-zone--" + s.diskName,
+		Serial:   "google-" + s.diskName,
+		Size:     2048,
+	}})
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+	}})
+
+	called, calls := s.FakeConn.WasCalled("AddDisk")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(calls[0].DiskSpec, jc.DeepEquals, google.DiskSpec{
+		Name:               s.diskName,
+		SizeHintGB:         2,
+		PersistentDiskType: google.DiskPersistentSSD,
+	})
+	called, calls = s.FakeConn.WasCalled("AttachDisk")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(calls[0].DiskName, gc.Equals, s.diskName)
+	c.Check(calls[0].ID, gc.Equals, "spam")
+}
+
+func (s *disksSuite) TestCreateVolumesDefaultDiskType(c *gc.C) {
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{s.volumeParams(nil)})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, calls := s.FakeConn.WasCalled("AddDisk")
+	c.Check(calls[0].DiskSpec.PersistentDiskType, gc.Equals, google.DiskPersistentStandard)
+}
+
+func (s *disksSuite) TestCreateVolumesNoInstance(c *gc.C) {
+	params := s.volumeParams(nil)
+	params.Attachment = nil
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{params})
+
+	c.Check(err, jc.Satisfies, errors.IsNotSupported)
+	s.CheckNoAPI(c)
+}
+
+func (s *disksSuite) TestCreateVolumesUnknownInstance(c *gc.C) {
+	s.FakeConn.Insts = nil
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{s.volumeParams(nil)})
+
+	c.Check(err, gc.ErrorMatches, `instance "spam" not found`)
+	called, _ := s.FakeConn.WasCalled("AddDisk")
+	c.Check(called, jc.IsFalse)
+}
+
+func (s *disksSuite) TestCreateVolumesAttachFailureCleansUp(c *gc.C) {
+	s.FakeConn.Err = errors.New("<unknown>")
+	// Instances, AddDisk, InstanceDisks, AttachDisk
+	s.FakeConn.FailOnCall = 3
+
+	_, _, err := s.source.CreateVolumes([]storage.VolumeParams{s.volumeParams(nil)})
+	c.Check(err, gc.ErrorMatches, `attaching volume 0 to 0: <unknown>`)
+
+	called, calls := s.FakeConn.WasCalled("RemoveDisk")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(calls[0].DiskName, gc.Equals, s.diskName)
+}
+
+func (s *disksSuite) TestDescribeVolumes(c *gc.C) {
+	volumes, err := s.source.DescribeVolumes([]string{"home-zone--" + s.diskName})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(volumes, jc.DeepEquals, []storage.Volume{{
+		VolumeId: "home-zone--" + s.diskName,
+		Serial:   "google-" + s.diskName,
+		Size:     2048,
+	}})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Disk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, s.diskName)
+}
+
+func (s *disksSuite) TestDescribeVolumesInvalidId(c *gc.C) {
+	_, err := s.source.DescribeVolumes([]string{"bogus"})
+
+	c.Check(err, gc.ErrorMatches, `volume ID "bogus" not valid`)
+	s.CheckNoAPI(c)
+}
+
+func (s *disksSuite) TestDestroyVolumes(c *gc.C) {
+	errs := s.source.DestroyVolumes([]string{"home-zone--" + s.diskName, "bogus"})
+
+	c.Assert(errs, gc.HasLen, 2)
+	c.Check(errs[0], jc.ErrorIsNil)
+	c.Check(errs[1], gc.ErrorMatches, `volume ID "bogus" not valid`)
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[0].DiskName, gc.Equals, s.diskName)
+}
+
+func (s *disksSuite) TestValidateVolumeParams(c *gc.C) {
+	err := s.source.ValidateVolumeParams(s.volumeParams(map[string]interface{}{"disk-type": "pd-ssd"}))
+	c.Check(err, jc.ErrorIsNil)
+
+	err = s.source.ValidateVolumeParams(s.volumeParams(map[string]interface{}{"disk-type": "ssd"}))
+	c.Check(err, gc.ErrorMatches, "disk-type ssd not valid")
+}
+
+func (s *disksSuite) attachmentParams() []storage.VolumeAttachmentParams {
+	return []storage.VolumeAttachmentParams{{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("0"),
+			InstanceId: instance.Id("spam"),
+		},
+		Volume:   names.NewVolumeTag("0"),
+		VolumeId: "home-zone--" + s.diskName,
+	}}
+}
+
+func (s *disksSuite) TestAttachVolumesAlreadyAttached(c *gc.C) {
+	s.FakeConn.Attached = []google.AttachedDisk{{
+		DiskName:   s.diskName,
+		DeviceName: s.diskName,
+	}}
+
+	attachments, err := s.source.AttachVolumes(s.attachmentParams())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attachments, jc.DeepEquals, []storage.VolumeAttachment{{
+		Volume:  names.NewVolumeTag("0"),
+		Machine: names.NewMachineTag("0"),
+	}})
+	called, _ := s.FakeConn.WasCalled("AttachDisk")
+	c.Check(called, jc.IsFalse)
+}
+
+func (s *disksSuite) TestDetachVolumes(c *gc.C) {
+	s.FakeConn.Attached = []google.AttachedDisk{{
+		DiskName:   s.diskName,
+		DeviceName: "juju-device",
+	}}
+
+	err := s.source.DetachVolumes(s.attachmentParams())
+	c.Assert(err, jc.ErrorIsNil)
+
+	called, calls := s.FakeConn.WasCalled("DetachDisk")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].ZoneName, gc.Equals, "home-zone")
+	c.Check(calls[0].ID, gc.Equals, "spam")
+	c.Check(calls[0].DeviceName, gc.Equals, "juju-device")
+}
+
+func (s *disksSuite) TestDetachVolumesNotAttached(c *gc.C) {
+	err := s.source.DetachVolumes(s.attachmentParams())
+	c.Assert(err, jc.ErrorIsNil)
+
+	called, _ := s.FakeConn.WasCalled("DetachDisk")
+	c.Check(called, jc.IsFalse)
+}
@@ -16,8 +16,6 @@ import (
 	"github.com/juju/juju/provider/gce/google"
 )
 
-// Note: This provider/environment does *not* implement storage.
-
 type gceConnection interface {
 	Connect(auth google.Auth) error
 	VerifyCredentials() error
@@ -34,6 +32,19 @@ type gceConnection interface {
 	ClosePorts(fwname string, ports ...network.PortRange) error
 
 	AvailabilityZones(region string) ([]google.AvailabilityZone, error)
+
+	// AddDisk creates a new persistent disk in the given zone.
+	AddDisk(zone string, spec google.DiskSpec) (*google.Disk, error)
+	// Disk gets the up-to-date info about the named disk and returns
+	// it. If the disk does not exist then errors.NotFound is returned.
+	Disk(zone, name string) (*google.Disk, error)
+	Disks(zone, prefix string) ([]*google.Disk, error)
+	// RemoveDisk removes the named disk. If it does not exist then
+	// this is a noop.
+	RemoveDisk(zone, name string) error
+	AttachDisk(zone, diskName, instanceID string, readonly bool) (*google.AttachedDisk, error)
+	DetachDisk(zone, instanceID, deviceName string) error
+	InstanceDisks(zone, instanceID string) ([]google.AttachedDisk, error)
 }
 
 type environ struct {
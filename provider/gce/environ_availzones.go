@@ -73,14 +73,20 @@ func (env *environ) availZoneUp(name string) (*google.AvailabilityZone, error) {
 	return zone, nil
 }
 
+// DistributeInstances implements the state.InstanceDistributor policy.
+func (env *environ) DistributeInstances(candidates, distributionGroup []instance.Id) ([]instance.Id, error) {
+	return common.DistributeInstances(env, candidates, distributionGroup)
+}
+
 var availabilityZoneAllocations = common.AvailabilityZoneAllocations
 
 // parseAvailabilityZones returns the availability zones that should be
 // tried for the given instance spec. If a placement argument was
 // provided then only that one is returned. Otherwise the environment is
-// queried for available zones. In that case, the resulting list is
-// roughly ordered such that the environment's instances are spread
-// evenly across the region.
+// queried for available zones, limited to those allowed by the zones
+// constraint (if any). In that case, the resulting list is roughly
+// ordered such that the environment's instances are spread evenly
+// across the region.
 func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([]string, error) {
 	if args.Placement != "" {
 		// args.Placement will always be a zone name or empty.
@@ -89,7 +95,11 @@ func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([
 			return nil, errors.Trace(err)
 		}
 		// TODO(ericsnow) Fail if placement.Zone is not in the env's configured region?
-		return []string{placement.Zone.Name()}, nil
+		zoneName := placement.Zone.Name()
+		if err := common.ValidatePlacementZone(zoneName, args.Constraints); err != nil {
+			return nil, errors.Trace(err)
+		}
+		return []string{zoneName}, nil
 	}
 
 	// If no availability zone is specified, then automatically spread across
@@ -107,6 +117,10 @@ func (env *environ) parseAvailabilityZones(args environs.StartInstanceParams) ([
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	zoneInstances, err = common.ConstrainZoneAllocations(zoneInstances, args.Constraints)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 	logger.Infof("found %d zones: %v", len(zoneInstances), zoneInstances)
 
 	var zoneNames []string
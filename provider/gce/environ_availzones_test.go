@@ -165,3 +165,65 @@ func (s *environAZSuite) TestParseAvailabilityZonesNoneFound(c *gc.C) {
 
 	c.Check(err, jc.Satisfies, errors.IsNotFound)
 }
+
+func (s *environAZSuite) TestParseAvailabilityZonesZonesConstraint(c *gc.C) {
+	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+		ZoneName: "a-zone",
+	}, {
+		ZoneName:  "home-zone",
+		Instances: []instance.Id{s.Instance.Id()},
+	}, {
+		ZoneName:  "b-zone",
+		Instances: []instance.Id{"other1", "other2"},
+	}}
+	s.StartInstArgs.Constraints.Zones = &[]string{"b-zone", "home-zone"}
+
+	zones, err := gce.ParseAvailabilityZones(s.Env, s.StartInstArgs)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(zones, jc.DeepEquals, []string{"home-zone", "b-zone"})
+}
+
+func (s *environAZSuite) TestParseAvailabilityZonesZonesConstraintUnavailable(c *gc.C) {
+	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+		ZoneName: "home-zone",
+	}}
+	s.StartInstArgs.Constraints.Zones = &[]string{"b-zone"}
+
+	_, err := gce.ParseAvailabilityZones(s.Env, s.StartInstArgs)
+
+	c.Check(err, gc.ErrorMatches, `none of the availability zones in zones constraint "b-zone" are available`)
+}
+
+func (s *environAZSuite) TestParseAvailabilityZonesPlacementZonesConstraint(c *gc.C) {
+	s.StartInstArgs.Placement = "zone=a-zone"
+	s.StartInstArgs.Constraints.Zones = &[]string{"b-zone"}
+	s.FakeConn.Zones = []google.AvailabilityZone{
+		google.NewZone("a-zone", google.StatusUp),
+	}
+
+	_, err := gce.ParseAvailabilityZones(s.Env, s.StartInstArgs)
+
+	c.Check(err, gc.ErrorMatches, `availability zone "a-zone" does not satisfy zones constraint "b-zone"`)
+}
+
+func (s *environAZSuite) TestDistributeInstances(c *gc.C) {
+	s.FakeEnviron.Insts = []instance.Instance{s.Instance}
+	s.FakeConn.Zones = []google.AvailabilityZone{
+		google.NewZone("home-zone", google.StatusUp),
+		google.NewZone("a-zone", google.StatusUp),
+	}
+	ids := []instance.Id{s.Instance.Id()}
+
+	// home-zone already has an instance of the group, so the only
+	// candidate in it is not eligible while a-zone is empty.
+	eligible, err := s.Env.DistributeInstances(ids, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(eligible, gc.HasLen, 0)
+
+	// With a-zone gone, home-zone is the best zone.
+	s.FakeConn.Zones = s.FakeConn.Zones[:1]
+	eligible, err = s.Env.DistributeInstances(ids, ids)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(eligible, jc.DeepEquals, ids)
+}
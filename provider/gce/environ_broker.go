@@ -35,6 +35,9 @@ func (env *environ) StartInstance(args environs.StartInstanceParams) (*environs.
 
 	// Start a new instance.
 
+	if err := common.ValidatePreemptible(args.Constraints, args.MachineConfig.Jobs); err != nil {
+		return nil, errors.Trace(err)
+	}
 	if args.MachineConfig.HasNetworks() {
 		return nil, errors.New("starting instances with networks is not supported yet")
 	}
@@ -174,6 +177,7 @@ func (env *environ) newRawInstance(args environs.StartInstanceParams, spec *inst
 		NetworkInterfaces: []string{"ExternalNAT"},
 		Metadata:          metadata,
 		Tags:              tags,
+		Preemptible:       args.Constraints.IsPreemptible(),
 		// Network is omitted (left empty).
 	}
 
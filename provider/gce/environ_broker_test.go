@@ -7,6 +7,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/constraints"
 	"github.com/juju/juju/environs/imagemetadata"
 	"github.com/juju/juju/environs/instances"
 	"github.com/juju/juju/environs/simplestreams"
@@ -88,6 +89,14 @@ func (s *environBrokerSuite) TestStartInstance(c *gc.C) {
 	c.Check(result.Hardware, gc.DeepEquals, s.hardware)
 }
 
+func (s *environBrokerSuite) TestStartInstancePreemptibleStateServer(c *gc.C) {
+	s.StartInstArgs.Constraints = constraints.MustParse("preemptible=true")
+
+	_, err := s.Env.StartInstance(s.StartInstArgs)
+
+	c.Check(err, gc.ErrorMatches, "preemptible instances for state servers not supported")
+}
+
 func (s *environBrokerSuite) TestFinishMachineConfig(c *gc.C) {
 	err := gce.FinishMachineConfig(s.Env, s.StartInstArgs, s.spec)
 
@@ -127,6 +136,22 @@ func (s *environBrokerSuite) TestNewRawInstance(c *gc.C) {
 	c.Check(inst, gc.DeepEquals, s.BaseInstance)
 }
 
+func (s *environBrokerSuite) TestNewRawInstancePreemptible(c *gc.C) {
+	s.FakeConn.Inst = s.BaseInstance
+	s.FakeCommon.AZInstances = []common.AvailabilityZoneInstances{{
+		ZoneName:  "home-zone",
+		Instances: []instance.Id{s.Instance.Id()},
+	}}
+	s.StartInstArgs.Constraints = constraints.MustParse("preemptible=true")
+
+	_, err := gce.NewRawInstance(s.Env, s.StartInstArgs, s.spec)
+	c.Assert(err, jc.ErrorIsNil)
+
+	called, calls := s.FakeConn.WasCalled("AddInstance")
+	c.Assert(called, jc.IsTrue)
+	c.Check(calls[0].InstanceSpec.Preemptible, jc.IsTrue)
+}
+
 func (s *environBrokerSuite) TestGetMetadata(c *gc.C) {
 	metadata, err := gce.GetMetadata(s.StartInstArgs)
 
@@ -91,6 +91,42 @@ func (env *environ) instances() ([]instance.Instance, error) {
 	return results, err
 }
 
+// reclaimedStatuses is the list of statuses of a preemptible
+// instance that GCE has taken back.
+var reclaimedStatuses = []string{
+	google.StatusStopping,
+	google.StatusTerminated,
+}
+
+var _ environs.InstanceReclaimer = (*environ)(nil)
+
+// ReclaimedInstances implements environs.InstanceReclaimer. Juju
+// removes instances rather than stopping them, so a stopped
+// preemptible instance is one that GCE has reclaimed.
+func (env *environ) ReclaimedInstances(ids []instance.Id) ([]instance.Id, error) {
+	env = env.getSnapshot()
+
+	prefix := common.MachineFullName(env, "")
+	instances, err := env.gce.Instances(prefix, reclaimedStatuses...)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var reclaimed []instance.Id
+	for _, inst := range instances {
+		if !inst.Preemptible {
+			continue
+		}
+		for _, id := range ids {
+			if instance.Id(inst.ID) == id {
+				reclaimed = append(reclaimed, id)
+				break
+			}
+		}
+	}
+	return reclaimed, nil
+}
+
 // StateServerInstances returns the IDs of the instances corresponding
 // to juju state servers.
 func (env *environ) StateServerInstances() ([]instance.Id, error) {
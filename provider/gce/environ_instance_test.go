@@ -126,6 +126,42 @@ func (s *environInstSuite) TestStateServerInstancesMixed(c *gc.C) {
 	c.Check(ids, jc.DeepEquals, []instance.Id{"spam"})
 }
 
+func (s *environInstSuite) TestReclaimedInstances(c *gc.C) {
+	reclaimed := google.NewInstance(google.InstanceSummary{
+		ID:          "spam",
+		Status:      google.StatusTerminated,
+		Preemptible: true,
+	}, nil)
+	stopped := google.NewInstance(google.InstanceSummary{
+		ID:     "eggs",
+		Status: google.StatusTerminated,
+	}, nil)
+	other := google.NewInstance(google.InstanceSummary{
+		ID:          "ham",
+		Status:      google.StatusTerminated,
+		Preemptible: true,
+	}, nil)
+	s.FakeConn.Insts = []google.Instance{*reclaimed, *stopped, *other}
+
+	ids, err := s.Env.ReclaimedInstances([]instance.Id{"spam", "eggs"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(ids, jc.DeepEquals, []instance.Id{"spam"})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "Instances")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, s.Prefix+"machine-")
+	c.Check(s.FakeConn.Calls[0].Statuses, jc.DeepEquals, []string{google.StatusStopping, google.StatusTerminated})
+}
+
+func (s *environInstSuite) TestReclaimedInstancesFailed(c *gc.C) {
+	failure := errors.New("<unknown>")
+	s.FakeConn.Err = failure
+
+	_, err := s.Env.ReclaimedInstances([]instance.Id{"spam"})
+
+	c.Check(errors.Cause(err), gc.Equals, failure)
+}
+
 func (s *environInstSuite) TestParsePlacement(c *gc.C) {
 	zone := google.NewZone("a-zone", google.StatusUp)
 	s.FakeConn.Zones = []google.AvailabilityZone{zone}
@@ -16,9 +16,15 @@ import (
 // PrecheckInstance verifies that the provided series and constraints
 // are valid for use in creating an instance in this environment.
 func (env *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
-	if _, err := env.parsePlacement(placement); err != nil {
+	parsed, err := env.parsePlacement(placement)
+	if err != nil {
 		return errors.Trace(err)
 	}
+	if parsed != nil {
+		if err := common.ValidatePlacementZone(parsed.Zone.Name(), cons); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	if cons.HasInstanceType() {
 		if !checkInstanceType(cons) {
@@ -67,6 +73,7 @@ func (env *environ) lookupArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.Networks,
+	constraints.SpotPrice,
 }
 
 // instanceTypeConstraints defines the fields defined on each of the
@@ -101,6 +101,18 @@ func (s *environPolSuite) TestPrecheckInstanceAvailZone(c *gc.C) {
 	c.Check(err, jc.ErrorIsNil)
 }
 
+func (s *environPolSuite) TestPrecheckInstanceAvailZoneZonesConstraint(c *gc.C) {
+	s.FakeConn.Zones = []google.AvailabilityZone{
+		google.NewZone("a-zone", google.StatusUp),
+	}
+
+	cons := constraints.MustParse("zones=b-zone,c-zone")
+	placement := "zone=a-zone"
+	err := s.Env.PrecheckInstance(testing.FakeDefaultSeries, cons, placement)
+
+	c.Check(err, gc.ErrorMatches, `availability zone "a-zone" does not satisfy zones constraint "b-zone,c-zone"`)
+}
+
 func (s *environPolSuite) TestPrecheckInstanceAvailZoneUnavailable(c *gc.C) {
 	s.FakeConn.Zones = []google.AvailabilityZone{
 		google.NewZone("a-zone", google.StatusDown),
@@ -147,6 +159,19 @@ func (s *environPolSuite) TestConstraintsValidator(c *gc.C) {
 	c.Check(unsupported, gc.HasLen, 0)
 }
 
+func (s *environPolSuite) TestConstraintsValidatorSpotPrice(c *gc.C) {
+	s.FakeCommon.Arches = []string{arch.AMD64}
+
+	validator, err := s.Env.ConstraintsValidator()
+	c.Assert(err, jc.ErrorIsNil)
+
+	cons := constraints.MustParse("arch=amd64 preemptible=true spot-price=0.1")
+	unsupported, err := validator.Validate(cons)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(unsupported, jc.SameContents, []string{"spot-price"})
+}
+
 func (s *environPolSuite) TestConstraintsValidatorEmpty(c *gc.C) {
 	validator, err := s.Env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
@@ -52,6 +52,30 @@ type rawConnectionWrapper interface {
 	// GCE region. If none are found the the list is empty. Any failure in
 	// the low-level request is returned as an error.
 	ListAvailabilityZones(projectID, region string) ([]*compute.Zone, error)
+	// GetDisk sends an API request to GCE for the information about
+	// the named persistent disk in the given zone and returns it. If
+	// the disk is not found, errors.NotFound is returned.
+	GetDisk(projectID, zone, name string) (*compute.Disk, error)
+	// ListDisks sends a request to the GCE API for a list of all
+	// persistent disks in the zone for which the name starts with the
+	// provided prefix (if any).
+	ListDisks(projectID, zone, prefix string) ([]*compute.Disk, error)
+	// AddDisk requests GCE to create a persistent disk in the given
+	// zone, with the provided disk data. The call blocks until the
+	// disk is created or the request fails.
+	AddDisk(projectID, zone string, disk *compute.Disk) error
+	// RemoveDisk requests GCE to remove the named persistent disk
+	// from the given zone. The call blocks until the disk is removed
+	// or the request fails.
+	RemoveDisk(projectID, zone, name string) error
+	// AttachDisk requests GCE to attach an existing persistent disk
+	// to the identified instance. The call blocks until the disk is
+	// attached or the request fails.
+	AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error
+	// DetachDisk requests GCE to detach the disk with the given device
+	// name from the identified instance. The call blocks until the
+	// disk is detached or the request fails.
+	DetachDisk(projectID, zone, instanceID, deviceName string) error
 }
 
 // TODO(ericsnow) Add specific error types for common failures
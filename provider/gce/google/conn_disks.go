@@ -0,0 +1,113 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google
+
+import (
+	"code.google.com/p/google-api-go-client/compute/v1"
+	"github.com/juju/errors"
+)
+
+// AddDisk creates a new persistent disk in the given zone, as described
+// by the provided spec, and returns it. The call blocks until the disk
+// is created or the request fails.
+func (gce *Connection) AddDisk(zone string, spec DiskSpec) (*Disk, error) {
+	if spec.Name == "" {
+		return nil, errors.New("disk name not set")
+	}
+	if err := gce.raw.AddDisk(gce.ProjectID, zone, spec.newDetached(zone)); err != nil {
+		return nil, errors.Annotatef(err, "creating disk %q", spec.Name)
+	}
+
+	// Get the disk back from GCE to pick up the fields it fills in.
+	disk, err := gce.Disk(zone, spec.Name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return disk, nil
+}
+
+// Disk returns the named persistent disk from the given zone. If the
+// disk does not exist then errors.NotFound is returned.
+func (gce *Connection) Disk(zone, name string) (*Disk, error) {
+	raw, err := gce.raw.GetDisk(gce.ProjectID, zone, name)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return newDisk(raw), nil
+}
+
+// Disks returns the persistent disks in the given zone for which the
+// name starts with the provided prefix. If the prefix is empty then
+// all the zone's disks are returned.
+func (gce *Connection) Disks(zone, prefix string) ([]*Disk, error) {
+	raw, err := gce.raw.ListDisks(gce.ProjectID, zone, prefix)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var results []*Disk
+	for _, disk := range raw {
+		results = append(results, newDisk(disk))
+	}
+	return results, nil
+}
+
+// RemoveDisk removes the named persistent disk from the given zone. If
+// the disk does not exist then this is a noop. A disk that is attached
+// to an instance cannot be removed.
+func (gce *Connection) RemoveDisk(zone, name string) error {
+	if _, err := gce.raw.GetDisk(gce.ProjectID, zone, name); errors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+
+	err := gce.raw.RemoveDisk(gce.ProjectID, zone, name)
+	return errors.Annotatef(err, "removing disk %q", name)
+}
+
+// AttachDisk attaches the named persistent disk to the identified
+// instance, which must be in the same zone as the disk. The disk's
+// name is used as the device name, so the disk will show up on the
+// instance as /dev/disk/by-id/google-<name>.
+func (gce *Connection) AttachDisk(zone, diskName, instanceID string, readonly bool) (*AttachedDisk, error) {
+	mode := diskModeRW
+	if readonly {
+		mode = diskModeRO
+	}
+	disk := &compute.AttachedDisk{
+		Type:       diskTypePersistent,
+		Mode:       mode,
+		Source:     formatDiskSource(gce.ProjectID, zone, diskName),
+		DeviceName: diskName,
+	}
+	if err := gce.raw.AttachDisk(gce.ProjectID, zone, instanceID, disk); err != nil {
+		return nil, errors.Annotatef(err, "attaching disk %q to %q", diskName, instanceID)
+	}
+
+	attached := newAttachedDisk(disk)
+	return &attached, nil
+}
+
+// DetachDisk detaches the disk with the given device name from the
+// identified instance.
+func (gce *Connection) DetachDisk(zone, instanceID, deviceName string) error {
+	err := gce.raw.DetachDisk(gce.ProjectID, zone, instanceID, deviceName)
+	return errors.Annotatef(err, "detaching %q from %q", deviceName, instanceID)
+}
+
+// InstanceDisks returns the disks currently attached to the
+// identified instance, including its boot disk.
+func (gce *Connection) InstanceDisks(zone, instanceID string) ([]AttachedDisk, error) {
+	inst, err := gce.raw.GetInstance(gce.ProjectID, zone, instanceID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	var results []AttachedDisk
+	for _, disk := range inst.Disks {
+		results = append(results, newAttachedDisk(disk))
+	}
+	return results, nil
+}
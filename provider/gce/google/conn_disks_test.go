@@ -0,0 +1,175 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package google_test
+
+import (
+	"code.google.com/p/google-api-go-client/compute/v1"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/provider/gce/google"
+)
+
+type connDisksSuite struct {
+	google.BaseSuite
+
+	rawDisk compute.Disk
+}
+
+var _ = gc.Suite(&connDisksSuite{})
+
+func (s *connDisksSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+
+	s.rawDisk = compute.Disk{
+		Name:   "juju-disk-0",
+		Zone:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone",
+		Type:   "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/diskTypes/pd-ssd",
+		SizeGb: 10,
+		Status: google.DiskStatusReady,
+	}
+}
+
+func (s *connDisksSuite) TestConnectionAddDisk(c *gc.C) {
+	s.FakeConn.Disk = &s.rawDisk
+
+	disk, err := s.Conn.AddDisk("a-zone", google.DiskSpec{
+		Name:               "juju-disk-0",
+		SizeHintGB:         10,
+		PersistentDiskType: google.DiskPersistentSSD,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disk, jc.DeepEquals, &google.Disk{
+		Name:     "juju-disk-0",
+		ZoneName: "a-zone",
+		Type:     "pd-ssd",
+		SizeGB:   10,
+		Status:   google.DiskStatusReady,
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AddDisk")
+	c.Check(s.FakeConn.Calls[0].ProjectID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].Disk, jc.DeepEquals, &compute.Disk{
+		Name:   "juju-disk-0",
+		SizeGb: 10,
+		Type:   "zones/a-zone/diskTypes/pd-ssd",
+	})
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-disk-0")
+}
+
+func (s *connDisksSuite) TestConnectionAddDiskNoName(c *gc.C) {
+	_, err := s.Conn.AddDisk("a-zone", google.DiskSpec{SizeHintGB: 10})
+
+	c.Check(err, gc.ErrorMatches, "disk name not set")
+	c.Check(s.FakeConn.Calls, gc.HasLen, 0)
+}
+
+func (s *connDisksSuite) TestConnectionAddDiskFailed(c *gc.C) {
+	s.FakeConn.Err = errors.New("<unknown>")
+
+	_, err := s.Conn.AddDisk("a-zone", google.DiskSpec{Name: "juju-disk-0"})
+
+	c.Check(err, gc.ErrorMatches, `creating disk "juju-disk-0": <unknown>`)
+}
+
+func (s *connDisksSuite) TestConnectionDisks(c *gc.C) {
+	s.FakeConn.Disks = []*compute.Disk{&s.rawDisk}
+
+	disks, err := s.Conn.Disks("a-zone", "juju-")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disks, gc.HasLen, 1)
+	c.Check(disks[0].Name, gc.Equals, "juju-disk-0")
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "ListDisks")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].Prefix, gc.Equals, "juju-")
+}
+
+func (s *connDisksSuite) TestConnectionRemoveDisk(c *gc.C) {
+	s.FakeConn.Disk = &s.rawDisk
+
+	err := s.Conn.RemoveDisk("a-zone", "juju-disk-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 2)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetDisk")
+	c.Check(s.FakeConn.Calls[1].FuncName, gc.Equals, "RemoveDisk")
+	c.Check(s.FakeConn.Calls[1].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[1].Name, gc.Equals, "juju-disk-0")
+}
+
+func (s *connDisksSuite) TestConnectionRemoveDiskNotFound(c *gc.C) {
+	s.FakeConn.Err = errors.NotFoundf("disk %q", "juju-disk-0")
+
+	err := s.Conn.RemoveDisk("a-zone", "juju-disk-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetDisk")
+}
+
+func (s *connDisksSuite) TestConnectionAttachDisk(c *gc.C) {
+	attached, err := s.Conn.AttachDisk("a-zone", "juju-disk-0", "spam", false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attached, jc.DeepEquals, &google.AttachedDisk{
+		DiskName:   "juju-disk-0",
+		DeviceName: "juju-disk-0",
+	})
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AttachDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].AttachedDisk, jc.DeepEquals, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     "projects/spam/zones/a-zone/disks/juju-disk-0",
+		DeviceName: "juju-disk-0",
+	})
+}
+
+func (s *connDisksSuite) TestConnectionAttachDiskReadonly(c *gc.C) {
+	attached, err := s.Conn.AttachDisk("a-zone", "juju-disk-0", "spam", true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(attached.Readonly, jc.IsTrue)
+	c.Check(s.FakeConn.Calls[0].AttachedDisk.Mode, gc.Equals, "READ_ONLY")
+}
+
+func (s *connDisksSuite) TestConnectionDetachDisk(c *gc.C) {
+	err := s.Conn.DetachDisk("a-zone", "spam", "juju-disk-0")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls, gc.HasLen, 1)
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "DetachDisk")
+	c.Check(s.FakeConn.Calls[0].ZoneName, gc.Equals, "a-zone")
+	c.Check(s.FakeConn.Calls[0].ID, gc.Equals, "spam")
+	c.Check(s.FakeConn.Calls[0].DeviceName, gc.Equals, "juju-disk-0")
+}
+
+func (s *connDisksSuite) TestConnectionInstanceDisks(c *gc.C) {
+	s.FakeConn.Instance = &s.RawInstanceFull
+	s.RawInstanceFull.Disks = append(s.RawInstanceFull.Disks, &compute.AttachedDisk{
+		Type:       "PERSISTENT",
+		Mode:       "READ_WRITE",
+		Source:     "https://www.googleapis.com/compute/v1/projects/spam/zones/a-zone/disks/juju-disk-0",
+		DeviceName: "juju-disk-0",
+	})
+
+	disks, err := s.Conn.InstanceDisks("a-zone", "spam")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(disks, gc.HasLen, 2)
+	c.Check(disks[0].Boot, jc.IsTrue)
+	c.Check(disks[1], jc.DeepEquals, google.AttachedDisk{
+		DiskName:   "juju-disk-0",
+		DeviceName: "juju-disk-0",
+	})
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "GetInstance")
+}
@@ -106,6 +106,22 @@ func (s *instanceSuite) TestConnectionAddInstanceAPI(c *gc.C) {
 	})
 }
 
+func (s *instanceSuite) TestConnectionAddInstancePreemptible(c *gc.C) {
+	s.FakeConn.Instance = &s.RawInstanceFull
+	spec := s.InstanceSpec
+	spec.Preemptible = true
+
+	_, err := s.Conn.AddInstance(spec, "a-zone")
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.FakeConn.Calls[0].FuncName, gc.Equals, "AddInstance")
+	c.Check(s.FakeConn.Calls[0].InstValue.Scheduling, jc.DeepEquals, &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  false,
+		OnHostMaintenance: "TERMINATE",
+	})
+}
+
 func (s *connSuite) TestConnectionAddInstanceFailed(c *gc.C) {
 	s.FakeConn.Instance = &s.RawInstanceFull
 
@@ -4,6 +4,9 @@
 package google
 
 import (
+	"fmt"
+	"path"
+
 	"code.google.com/p/google-api-go-client/compute/v1"
 )
 
@@ -13,6 +16,19 @@ const (
 	diskTypePersistent = "PERSISTENT"
 )
 
+// The different types of persistent disks supported by GCE.
+const (
+	DiskPersistentStandard = "pd-standard"
+	DiskPersistentSSD      = "pd-ssd"
+)
+
+// The different statuses of a GCE persistent disk.
+const (
+	DiskStatusCreating = "CREATING"
+	DiskStatusFailed   = "FAILED"
+	DiskStatusReady    = "READY"
+)
+
 // The different disk modes supported by GCE.
 const (
 	diskModeRW = "READ_WRITE"
@@ -48,6 +64,16 @@ type DiskSpec struct {
 	// AutoDelete indicates that the attached disk should be removed
 	// when the instance to which it is attached is removed.
 	AutoDelete bool
+	// Name is the name of the disk. It is only used for persistent
+	// disks created independently of an instance. (detached only)
+	Name string
+	// Description is a free-form description of the disk. (detached
+	// only)
+	Description string
+	// PersistentDiskType is the kind of persistent disk to create,
+	// e.g. pd-ssd. If not set then pd-standard is used. (detached
+	// only)
+	PersistentDiskType string
 }
 
 // TooSmall checks the spec's size hint and indicates whether or not
@@ -98,3 +124,86 @@ func (ds *DiskSpec) newAttached() *compute.AttachedDisk {
 	}
 	return &disk
 }
+
+// newDetached builds a compute.Disk, for a persistent disk in the
+// given zone, using the information in the disk spec and returns it.
+//
+// Note: Not all Disk fields are set.
+func (ds *DiskSpec) newDetached(zone string) *compute.Disk {
+	diskType := ds.PersistentDiskType
+	if diskType == "" {
+		diskType = DiskPersistentStandard
+	}
+
+	disk := compute.Disk{
+		Name:        ds.Name,
+		Description: ds.Description,
+		SizeGb:      int64(ds.SizeGB()),
+		Type:        formatDiskType(zone, diskType),
+		// SourceImage and SourceSnapshot are not supported.
+	}
+	return &disk
+}
+
+func formatDiskType(zone, name string) string {
+	return fmt.Sprintf("zones/%s/diskTypes/%s", zone, name)
+}
+
+func formatDiskSource(projectID, zone, name string) string {
+	return fmt.Sprintf("projects/%s/zones/%s/disks/%s", projectID, zone, name)
+}
+
+// Disk holds the information about a GCE persistent disk.
+type Disk struct {
+	// Name is the unique name of the disk within its zone.
+	Name string
+	// ZoneName is the unqualified name of the zone in which the disk
+	// was created.
+	ZoneName string
+	// Type is the kind of persistent disk, e.g. pd-ssd.
+	Type string
+	// SizeGB is the size of the disk in Gigabytes.
+	SizeGB uint64
+	// Status is the disk's current status, e.g. READY.
+	Status string
+	// Description is the free-form description of the disk.
+	Description string
+}
+
+func newDisk(raw *compute.Disk) *Disk {
+	return &Disk{
+		Name:        raw.Name,
+		ZoneName:    path.Base(raw.Zone),
+		Type:        path.Base(raw.Type),
+		SizeGB:      uint64(raw.SizeGb),
+		Status:      raw.Status,
+		Description: raw.Description,
+	}
+}
+
+// AttachedDisk holds the information about a disk attached to an
+// instance.
+type AttachedDisk struct {
+	// DiskName is the name of the attached disk.
+	DiskName string
+	// DeviceName is the name by which the disk is exposed to the
+	// instance. On Linux the disk shows up as
+	// /dev/disk/by-id/google-<DeviceName>.
+	DeviceName string
+	// Boot indicates that the disk is the instance's boot disk.
+	Boot bool
+	// Readonly indicates that the disk is attached read-only.
+	Readonly bool
+}
+
+func newAttachedDisk(raw *compute.AttachedDisk) AttachedDisk {
+	attached := AttachedDisk{
+		DeviceName: raw.DeviceName,
+		Boot:       raw.Boot,
+		Readonly:   raw.Mode == diskModeRO,
+	}
+	if raw.Source != "" {
+		attached.DiskName = path.Base(raw.Source)
+	}
+	return attached
+}
@@ -140,3 +140,22 @@ func (s *diskSuite) TestRootDiskSpec(c *gc.C) {
 		diskMode: "READ_WRITE",
 	})
 }
+
+func (s *diskSuite) TestDiskSpecNewDetached(c *gc.C) {
+	s.DiskSpec.Name = "juju-disk-0"
+	s.DiskSpec.PersistentDiskType = google.DiskPersistentSSD
+	disk := google.NewDetached(s.DiskSpec, "a-zone")
+
+	c.Check(disk, jc.DeepEquals, &compute.Disk{
+		Name:   "juju-disk-0",
+		SizeGb: 5,
+		Type:   "zones/a-zone/diskTypes/pd-ssd",
+	})
+}
+
+func (s *diskSuite) TestDiskSpecNewDetachedDefaultType(c *gc.C) {
+	s.DiskSpec.Name = "juju-disk-0"
+	disk := google.NewDetached(s.DiskSpec, "a-zone")
+
+	c.Check(disk.Type, gc.Equals, "zones/a-zone/diskTypes/pd-standard")
+}
@@ -30,6 +30,10 @@ func NewAttached(spec DiskSpec) *compute.AttachedDisk {
 	return spec.newAttached()
 }
 
+func NewDetached(spec DiskSpec, zone string) *compute.Disk {
+	return spec.newDetached(zone)
+}
+
 func NewAvailabilityZone(zone *compute.Zone) AvailabilityZone {
 	return AvailabilityZone{zone: zone}
 }
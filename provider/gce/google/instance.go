@@ -49,6 +49,10 @@ type InstanceSpec struct {
 	// useful when making bulk calls or in relation to some API methods
 	// (e.g. related to firewalls access rules).
 	Tags []string
+	// Preemptible indicates that the instance may be stopped by GCE
+	// at any time, in exchange for a lower price. Preemptible
+	// instances are never restarted automatically.
+	Preemptible bool
 }
 
 func (is InstanceSpec) raw() *compute.Instance {
@@ -58,10 +62,25 @@ func (is InstanceSpec) raw() *compute.Instance {
 		NetworkInterfaces: is.networkInterfaces(),
 		Metadata:          packMetadata(is.Metadata),
 		Tags:              &compute.Tags{Items: is.Tags},
+		Scheduling:        is.scheduling(),
 		// MachineType is set in the addInstance call.
 	}
 }
 
+func (is InstanceSpec) scheduling() *compute.Scheduling {
+	if !is.Preemptible {
+		// Use the GCE defaults.
+		return nil
+	}
+	// GCE requires preemptible instances to be terminated on host
+	// maintenance and not to be restarted automatically.
+	return &compute.Scheduling{
+		Preemptible:       true,
+		AutomaticRestart:  false,
+		OnHostMaintenance: "TERMINATE",
+	}
+}
+
 // Summary builds an InstanceSummary based on the spec and returns it.
 func (is InstanceSpec) Summary() InstanceSummary {
 	raw := is.raw()
@@ -105,15 +124,19 @@ type InstanceSummary struct {
 	Metadata map[string]string
 	// Addresses are the IP Addresses associated with the instance.
 	Addresses []network.Address
+	// Preemptible indicates whether GCE may stop the instance at
+	// any time.
+	Preemptible bool
 }
 
 func newInstanceSummary(raw *compute.Instance) InstanceSummary {
 	return InstanceSummary{
-		ID:        raw.Name,
-		ZoneName:  path.Base(raw.Zone),
-		Status:    raw.Status,
-		Metadata:  unpackMetadata(raw.Metadata),
-		Addresses: extractAddresses(raw.NetworkInterfaces...),
+		ID:          raw.Name,
+		ZoneName:    path.Base(raw.Zone),
+		Status:      raw.Status,
+		Metadata:    unpackMetadata(raw.Metadata),
+		Addresses:   extractAddresses(raw.NetworkInterfaces...),
+		Preemptible: raw.Scheduling != nil && raw.Scheduling.Preemptible,
 	}
 }
 
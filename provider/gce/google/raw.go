@@ -176,6 +176,87 @@ func (rc *rawConn) ListAvailabilityZones(projectID, region string) ([]*compute.Z
 	return results, nil
 }
 
+func (rc *rawConn) GetDisk(projectID, zone, name string) (*compute.Disk, error) {
+	call := rc.Disks.List(projectID, zone)
+	call = call.Filter("name eq " + name)
+	diskList, err := call.Do()
+	if err != nil {
+		return nil, errors.Annotate(err, "while getting disk from GCE")
+	}
+
+	if len(diskList.Items) == 0 {
+		return nil, errors.NotFoundf("disk %q", name)
+	}
+	return diskList.Items[0], nil
+}
+
+func (rc *rawConn) ListDisks(projectID, zone, prefix string) ([]*compute.Disk, error) {
+	call := rc.Disks.List(projectID, zone)
+	if prefix != "" {
+		call = call.Filter("name eq " + prefix + ".*")
+	}
+
+	var results []*compute.Disk
+	for {
+		diskList, err := call.Do()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+
+		results = append(results, diskList.Items...)
+		if diskList.NextPageToken == "" {
+			break
+		}
+		call = call.PageToken(diskList.NextPageToken)
+	}
+	return results, nil
+}
+
+func (rc *rawConn) AddDisk(projectID, zone string, disk *compute.Disk) error {
+	call := rc.Disks.Insert(projectID, zone, disk)
+	operation, err := call.Do()
+	if err != nil {
+		// We are guaranteed the insert failed at the point.
+		return errors.Annotate(err, "sending new disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) RemoveDisk(projectID, zone, name string) error {
+	call := rc.Disks.Delete(projectID, zone, name)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error {
+	call := rc.Instances.AttachDisk(projectID, zone, instanceID, disk)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Annotate(err, "sending attach disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
+func (rc *rawConn) DetachDisk(projectID, zone, instanceID, deviceName string) error {
+	call := rc.Instances.DetachDisk(projectID, zone, instanceID, deviceName)
+	operation, err := call.Do()
+	if err != nil {
+		return errors.Annotate(err, "sending detach disk request")
+	}
+
+	err = rc.waitOperation(projectID, operation, attemptsLong)
+	return errors.Trace(err)
+}
+
 type waitError struct {
 	op    *compute.Operation
 	cause error
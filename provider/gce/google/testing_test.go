@@ -156,6 +156,10 @@ type fakeCall struct {
 	Instance  *compute.Instance
 	InstValue compute.Instance
 	Firewall  *compute.Firewall
+
+	Disk         *compute.Disk
+	AttachedDisk *compute.AttachedDisk
+	DeviceName   string
 }
 
 type fakeConn struct {
@@ -166,6 +170,8 @@ type fakeConn struct {
 	Instances  []*compute.Instance
 	Firewall   *compute.Firewall
 	Zones      []*compute.Zone
+	Disk       *compute.Disk
+	Disks      []*compute.Disk
 	Err        error
 	FailOnCall int
 }
@@ -324,3 +330,101 @@ func (rc *fakeConn) ListAvailabilityZones(projectID, region string) ([]*compute.
 	}
 	return rc.Zones, err
 }
+
+func (rc *fakeConn) GetDisk(projectID, zone, name string) (*compute.Disk, error) {
+	call := fakeCall{
+		FuncName:  "GetDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Disk, err
+}
+
+func (rc *fakeConn) ListDisks(projectID, zone, prefix string) ([]*compute.Disk, error) {
+	call := fakeCall{
+		FuncName:  "ListDisks",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Prefix:    prefix,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return rc.Disks, err
+}
+
+func (rc *fakeConn) AddDisk(projectID, zone string, disk *compute.Disk) error {
+	call := fakeCall{
+		FuncName:  "AddDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Disk:      disk,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) RemoveDisk(projectID, zone, name string) error {
+	call := fakeCall{
+		FuncName:  "RemoveDisk",
+		ProjectID: projectID,
+		ZoneName:  zone,
+		Name:      name,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) AttachDisk(projectID, zone, instanceID string, disk *compute.AttachedDisk) error {
+	call := fakeCall{
+		FuncName:     "AttachDisk",
+		ProjectID:    projectID,
+		ZoneName:     zone,
+		ID:           instanceID,
+		AttachedDisk: disk,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
+
+func (rc *fakeConn) DetachDisk(projectID, zone, instanceID, deviceName string) error {
+	call := fakeCall{
+		FuncName:   "DetachDisk",
+		ProjectID:  projectID,
+		ZoneName:   zone,
+		ID:         instanceID,
+		DeviceName: deviceName,
+	}
+	rc.Calls = append(rc.Calls, call)
+
+	err := rc.Err
+	if len(rc.Calls) != rc.FailOnCall+1 {
+		err = nil
+	}
+	return err
+}
@@ -377,6 +377,9 @@ type fakeConnCall struct {
 	FirewallName string
 	PortRanges   []network.PortRange
 	Region       string
+	DiskSpec     google.DiskSpec
+	DiskName     string
+	DeviceName   string
 }
 
 type fakeConn struct {
@@ -386,6 +389,9 @@ type fakeConn struct {
 	Insts      []google.Instance
 	PortRanges []network.PortRange
 	Zones      []google.AvailabilityZone
+	Disk       *google.Disk
+	Disks      []*google.Disk
+	Attached   []google.AttachedDisk
 	Err        error
 	FailOnCall int
 }
@@ -482,6 +488,76 @@ func (fc *fakeConn) AvailabilityZones(region string) ([]google.AvailabilityZone,
 	return fc.Zones, fc.err()
 }
 
+func (fc *fakeConn) AddDisk(zone string, spec google.DiskSpec) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "AddDisk",
+		ZoneName: zone,
+		DiskSpec: spec,
+	})
+	return fc.Disk, fc.err()
+}
+
+func (fc *fakeConn) Disk(zone, name string) (*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.Disk, fc.err()
+}
+
+func (fc *fakeConn) Disks(zone, prefix string) ([]*google.Disk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "Disks",
+		ZoneName: zone,
+		Prefix:   prefix,
+	})
+	return fc.Disks, fc.err()
+}
+
+func (fc *fakeConn) RemoveDisk(zone, name string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "RemoveDisk",
+		ZoneName: zone,
+		DiskName: name,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) AttachDisk(zone, diskName, instanceID string, readonly bool) (*google.AttachedDisk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "AttachDisk",
+		ZoneName: zone,
+		DiskName: diskName,
+		ID:       instanceID,
+	})
+	attached := &google.AttachedDisk{
+		DiskName:   diskName,
+		DeviceName: diskName,
+		Readonly:   readonly,
+	}
+	return attached, fc.err()
+}
+
+func (fc *fakeConn) DetachDisk(zone, instanceID, deviceName string) error {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName:   "DetachDisk",
+		ZoneName:   zone,
+		ID:         instanceID,
+		DeviceName: deviceName,
+	})
+	return fc.err()
+}
+
+func (fc *fakeConn) InstanceDisks(zone, instanceID string) ([]google.AttachedDisk, error) {
+	fc.Calls = append(fc.Calls, fakeConnCall{
+		FuncName: "InstanceDisks",
+		ZoneName: zone,
+		ID:       instanceID,
+	})
+	return fc.Attached, fc.err()
+}
+
 func (fc *fakeConn) WasCalled(funcName string) (bool, []fakeConnCall) {
 	var calls []fakeConnCall
 	called := false
@@ -78,6 +78,11 @@ func (env *joyentEnviron) PrecheckInstance(series string, cons constraints.Value
 	if placement != "" {
 		return fmt.Errorf("unknown placement directive: %s", placement)
 	}
+	if cons.HasZones() {
+		// Joyent has no availability zones, so the
+		// constraint could not be honoured.
+		return fmt.Errorf("zones constraint not supported")
+	}
 	if !cons.HasInstanceType() {
 		return nil
 	}
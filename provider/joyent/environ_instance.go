@@ -59,6 +59,9 @@ func (env *joyentEnviron) machineFullName(machineId string) string {
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -404,10 +404,17 @@ func (s *localServerSuite) TestConstraintsValidator(c *gc.C) {
 	env := s.Prepare(c)
 	validator, err := env.ConstraintsValidator()
 	c.Assert(err, jc.ErrorIsNil)
-	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10")
+	cons := constraints.MustParse("arch=amd64 tags=bar cpu-power=10 zones=az1 spot-price=0.1")
 	unsupported, err := validator.Validate(cons)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags"})
+	c.Assert(unsupported, jc.SameContents, []string{"cpu-power", "tags", "zones", "spot-price"})
+}
+
+func (s *localServerSuite) TestPrecheckInstanceZones(c *gc.C) {
+	env := s.Prepare(c)
+	cons := constraints.MustParse("zones=az1")
+	err := env.PrecheckInstance("trusty", cons, "")
+	c.Assert(err, gc.ErrorMatches, "zones constraint not supported")
 }
 
 func (s *localServerSuite) TestConstraintsValidatorVocab(c *gc.C) {
@@ -337,10 +337,12 @@ func (env *localEnviron) setLocalStorage() error {
 }
 
 var unsupportedConstraints = []string{
-	constraints.CpuCores,
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -388,7 +390,7 @@ func (env *localEnviron) StartInstance(args environs.StartInstanceParams) (*envi
 var createContainer = func(env *localEnviron, args environs.StartInstanceParams) (instance.Instance, *instance.HardwareCharacteristics, error) {
 	series := args.Tools.OneSeries()
 	network := container.BridgeNetworkConfig(env.config.networkBridge(), args.NetworkInfo)
-	inst, hardware, err := env.containerManager.CreateContainer(args.MachineConfig, series, network)
+	inst, hardware, err := env.containerManager.CreateContainer(args.MachineConfig, args.Constraints, series, network)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -487,8 +487,14 @@ func (env *maasEnviron) PrecheckInstance(series string, cons constraints.Value,
 	if placement == "" {
 		return nil
 	}
-	_, err := env.parsePlacement(placement)
-	return err
+	p, err := env.parsePlacement(placement)
+	if err != nil {
+		return err
+	}
+	if p.zoneName != "" {
+		return common.ValidatePlacementZone(p.zoneName, cons)
+	}
+	return nil
 }
 
 const (
@@ -686,6 +692,8 @@ func (environ *maasEnviron) startNode(node gomaasapi.MAASObject, series string,
 var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -782,15 +790,23 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 		}
 		switch {
 		case placement.zoneName != "":
+			if err := common.ValidatePlacementZone(placement.zoneName, args.Constraints); err != nil {
+				return nil, err
+			}
 			availabilityZones = append(availabilityZones, placement.zoneName)
 		default:
 			nodeName = placement.nodeName
+			// Have MAAS check that the named node is in one of
+			// the zones allowed by the zones constraint.
+			if args.Constraints.HasZones() {
+				availabilityZones = append(availabilityZones, *args.Constraints.Zones...)
+			}
 		}
 	}
 
 	// If no placement is specified, then automatically spread across
-	// the known zones for optimal spread across the instance distribution
-	// group.
+	// the known zones (limited to those allowed by the zones constraint)
+	// for optimal spread across the instance distribution group.
 	if args.Placement == "" {
 		var group []instance.Id
 		var err error
@@ -803,10 +819,18 @@ func (environ *maasEnviron) StartInstance(args environs.StartInstanceParams) (
 		zoneInstances, err := availabilityZoneAllocations(environ, group)
 		if errors.IsNotImplemented(err) {
 			// Availability zones are an extension, so we may get a
-			// not implemented error; ignore these.
+			// not implemented error; ignore these unless the user
+			// asked for specific zones.
+			if args.Constraints.HasZones() {
+				return nil, errors.Annotate(err, "cannot satisfy zones constraint")
+			}
 		} else if err != nil {
 			return nil, errors.Annotate(err, "cannot get availability zone allocations")
 		} else if len(zoneInstances) > 0 {
+			zoneInstances, err = common.ConstrainZoneAllocations(zoneInstances, args.Constraints)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
 			for _, z := range zoneInstances {
 				availabilityZones = append(availabilityZones, z.ZoneName)
 			}
@@ -306,6 +306,9 @@ var unsupportedConstraints = []string{
 	constraints.CpuPower,
 	constraints.InstanceType,
 	constraints.Tags,
+	constraints.Zones,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -0,0 +1,413 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"github.com/juju/utils/set"
+	"launchpad.net/goose/cinder"
+	"launchpad.net/goose/nova"
+
+	"github.com/juju/juju/environs/config"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/provider/common"
+	"github.com/juju/juju/storage"
+)
+
+const (
+	CinderProviderType = storage.ProviderType("cinder")
+
+	// Config attributes
+
+	// The Cinder volume type to create volumes with. If not
+	// specified, the cloud's default volume type is used.
+	CinderVolumeType = "volume-type"
+
+	// metadataEnvUUID is the name of the metadata item used to
+	// record the UUID of the environment that a volume belongs to.
+	metadataEnvUUID = "juju-env-uuid"
+
+	volumeStatusAvailable = "available"
+	volumeStatusError     = "error"
+)
+
+// cinderAttempt is used to wait for newly created volumes
+// to become available.
+var cinderAttempt = utils.AttemptStrategy{
+	Total: 60 * time.Second,
+	Delay: 1 * time.Second,
+}
+
+var cinderConfigOptions = set.NewStrings(
+	CinderVolumeType,
+)
+
+// cinderProvider creates volume sources which use Cinder volumes.
+type cinderProvider struct {
+	newStorage func(*config.Config) (openstackStorage, error)
+}
+
+var _ storage.Provider = (*cinderProvider)(nil)
+
+// ValidateConfig is defined on the Provider interface.
+func (p *cinderProvider) ValidateConfig(providerConfig *storage.Config) error {
+	for attr := range providerConfig.Attrs() {
+		if !cinderConfigOptions.Contains(attr) {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+	}
+	return nil
+}
+
+// Supports is defined on the Provider interface.
+func (p *cinderProvider) Supports(k storage.StorageKind) bool {
+	return k == storage.StorageKindBlock
+}
+
+// VolumeSource is defined on the Provider interface.
+func (p *cinderProvider) VolumeSource(environConfig *config.Config, providerConfig *storage.Config) (storage.VolumeSource, error) {
+	uuid, ok := environConfig.UUID()
+	if !ok {
+		return nil, errors.NotFoundf("environment UUID")
+	}
+	storageAdapter, err := p.newStorage(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	source := &cinderVolumeSource{
+		storage: storageAdapter,
+		envUUID: uuid,
+	}
+	return source, nil
+}
+
+// FilesystemSource is defined on the Provider interface.
+func (p *cinderProvider) FilesystemSource(environConfig *config.Config, providerConfig *storage.Config) (storage.FilesystemSource, error) {
+	return nil, errors.NotSupportedf("filesystems")
+}
+
+// openstackStorage is the subset of the Cinder and Nova APIs
+// used to manage volumes and their attachments.
+type openstackStorage interface {
+	GetVolumesDetail() ([]cinder.Volume, error)
+	CreateVolume(cinder.CreateVolumeVolumeParams) (*cinder.Volume, error)
+	GetVolume(volumeId string) (*cinder.Volume, error)
+	DeleteVolume(volumeId string) error
+	GetServer(serverId string) (*nova.ServerDetail, error)
+	AttachVolume(serverId, volumeId, device string) (*nova.VolumeAttachment, error)
+	DetachVolume(serverId, attachmentId string) error
+	ListVolumeAttachments(serverId string) ([]nova.VolumeAttachment, error)
+}
+
+// openstackStorageAdapter implements openstackStorage
+// with the Cinder and Nova clients.
+type openstackStorageAdapter struct {
+	cinder *cinder.Client
+	*nova.Client
+}
+
+// newOpenstackStorage returns an openstackStorage that uses
+// the Cinder endpoint of the environment's OpenStack cloud.
+func newOpenstackStorage(environConfig *config.Config) (openstackStorage, error) {
+	env, err := providerInstance.Open(environConfig)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	e := env.(*environ)
+	if !e.client.IsAuthenticated() {
+		if err := authenticateClient(e); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	endpoint, err := makeServiceURL(e.client, "volume", nil)
+	if err != nil {
+		return nil, errors.Annotate(err, "getting Cinder endpoint")
+	}
+	endpointURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, errors.Annotate(err, "parsing Cinder endpoint")
+	}
+	return &openstackStorageAdapter{
+		cinder: cinder.Basic(endpointURL, e.client.TenantId(), e.client.Token),
+		Client: e.nova(),
+	}, nil
+}
+
+// GetVolumesDetail is part of the openstackStorage interface.
+func (a *openstackStorageAdapter) GetVolumesDetail() ([]cinder.Volume, error) {
+	resp, err := a.cinder.GetVolumesDetail()
+	if err != nil {
+		return nil, err
+	}
+	return resp.Volumes, nil
+}
+
+// CreateVolume is part of the openstackStorage interface.
+func (a *openstackStorageAdapter) CreateVolume(args cinder.CreateVolumeVolumeParams) (*cinder.Volume, error) {
+	resp, err := a.cinder.CreateVolume(args)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Volume, nil
+}
+
+// GetVolume is part of the openstackStorage interface.
+func (a *openstackStorageAdapter) GetVolume(volumeId string) (*cinder.Volume, error) {
+	resp, err := a.cinder.GetVolume(volumeId)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Volume, nil
+}
+
+// DeleteVolume is part of the openstackStorage interface.
+func (a *openstackStorageAdapter) DeleteVolume(volumeId string) error {
+	return a.cinder.DeleteVolume(volumeId)
+}
+
+// cinderVolumeSource creates, attaches and destroys Cinder volumes.
+type cinderVolumeSource struct {
+	storage openstackStorage
+	envUUID string
+}
+
+var _ storage.VolumeSource = (*cinderVolumeSource)(nil)
+
+// CreateVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that are to be attached to an instance are created in the
+// instance's availability zone, and are then attached to it.
+func (s *cinderVolumeSource) CreateVolumes(params []storage.VolumeParams) (_ []storage.Volume, _ []storage.VolumeAttachment, err error) {
+	volumes := make([]storage.Volume, 0, len(params))
+	var attachParams []storage.VolumeAttachmentParams
+	defer func() {
+		if err == nil {
+			return
+		}
+		// Don't leave volumes behind that nothing knows about.
+		for _, volume := range volumes {
+			if err := s.storage.DeleteVolume(volume.VolumeId); err != nil {
+				logger.Warningf("cannot delete volume %q: %v", volume.VolumeId, err)
+			}
+		}
+	}()
+	for _, p := range params {
+		if err := s.ValidateVolumeParams(p); err != nil {
+			return nil, nil, errors.Annotatef(err, "invalid parameters for volume %s", p.Tag.Id())
+		}
+		args := cinder.CreateVolumeVolumeParams{
+			Name: p.Tag.String(),
+			// Cinder volume sizes are in GiB;
+			// round up to the nearest GiB.
+			Size:     int(common.MiBToGiB(p.Size)),
+			Metadata: map[string]string{metadataEnvUUID: s.envUUID},
+		}
+		if volumeType, ok := p.Attributes[CinderVolumeType]; ok {
+			args.VolumeType = fmt.Sprint(volumeType)
+		}
+		if p.Attachment != nil && p.Attachment.InstanceId != "" {
+			server, err := s.storage.GetServer(string(p.Attachment.InstanceId))
+			if err != nil {
+				return nil, nil, errors.Annotatef(err, "getting instance %s", p.Attachment.InstanceId)
+			}
+			args.AvailabilityZone = server.AvailabilityZone
+		}
+		vol, err := s.storage.CreateVolume(args)
+		if err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", p.Tag.Id())
+		}
+		volumes = append(volumes, storage.Volume{
+			Tag:      p.Tag,
+			VolumeId: vol.ID,
+			Size:     common.GiBToMiB(uint64(vol.Size)),
+		})
+		if p.Attachment != nil && p.Attachment.InstanceId != "" {
+			attachment := *p.Attachment
+			attachment.VolumeId = vol.ID
+			attachParams = append(attachParams, attachment)
+		}
+	}
+	for _, volume := range volumes {
+		if err := s.waitVolumeAvailable(volume.VolumeId); err != nil {
+			return nil, nil, errors.Annotatef(err, "creating volume %s", volume.Tag.Id())
+		}
+	}
+	attachments, err := s.AttachVolumes(attachParams)
+	if err != nil {
+		return nil, nil, errors.Trace(err)
+	}
+	return volumes, attachments, nil
+}
+
+// waitVolumeAvailable waits until the volume has been created
+// and is ready to be attached.
+func (s *cinderVolumeSource) waitVolumeAvailable(volumeId string) error {
+	var status string
+	for a := cinderAttempt.Start(); a.Next(); {
+		vol, err := s.storage.GetVolume(volumeId)
+		if err != nil {
+			if !a.HasNext() {
+				return errors.Trace(err)
+			}
+			logger.Debugf("error getting volume %q: %v", volumeId, err)
+			continue
+		}
+		status = vol.Status
+		switch status {
+		case volumeStatusAvailable:
+			return nil
+		case volumeStatusError:
+			return errors.Errorf("volume %q failed to create", volumeId)
+		}
+	}
+	return errors.Errorf("timed out waiting for volume %q to become available (status %q)", volumeId, status)
+}
+
+// DescribeVolumes is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) DescribeVolumes(volIds []string) ([]storage.Volume, error) {
+	byId, err := s.volumesById()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	volumes := make([]storage.Volume, len(volIds))
+	for i, volId := range volIds {
+		vol, ok := byId[volId]
+		if !ok {
+			return nil, errors.NotFoundf("volume %q", volId)
+		}
+		volumes[i] = storage.Volume{
+			VolumeId: vol.ID,
+			Size:     common.GiBToMiB(uint64(vol.Size)),
+		}
+	}
+	return volumes, nil
+}
+
+// DestroyVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that no longer exist are considered destroyed.
+func (s *cinderVolumeSource) DestroyVolumes(volIds []string) []error {
+	errs := make([]error, len(volIds))
+	byId, err := s.volumesById()
+	if err != nil {
+		for i := range errs {
+			errs[i] = errors.Trace(err)
+		}
+		return errs
+	}
+	for i, volId := range volIds {
+		if _, ok := byId[volId]; !ok {
+			continue
+		}
+		if err := s.storage.DeleteVolume(volId); err != nil {
+			errs[i] = errors.Annotatef(err, "destroying %q", volId)
+		}
+	}
+	return errs
+}
+
+// volumesById returns all of the tenant's volumes, keyed by ID.
+func (s *cinderVolumeSource) volumesById() (map[string]cinder.Volume, error) {
+	volumes, err := s.storage.GetVolumesDetail()
+	if err != nil {
+		return nil, errors.Annotate(err, "listing volumes")
+	}
+	byId := make(map[string]cinder.Volume)
+	for _, vol := range volumes {
+		byId[vol.ID] = vol
+	}
+	return byId, nil
+}
+
+// ValidateVolumeParams is specified on the storage.VolumeSource interface.
+func (s *cinderVolumeSource) ValidateVolumeParams(params storage.VolumeParams) error {
+	for attr := range params.Attributes {
+		if !cinderConfigOptions.Contains(attr) {
+			return errors.Errorf("unknown provider config option %q", attr)
+		}
+	}
+	return nil
+}
+
+// AttachVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that are already attached to the requested instance are
+// reported as they are.
+func (s *cinderVolumeSource) AttachVolumes(params []storage.VolumeAttachmentParams) ([]storage.VolumeAttachment, error) {
+	attachments := make([]storage.VolumeAttachment, len(params))
+	existing := make(map[instance.Id][]nova.VolumeAttachment)
+	for i, p := range params {
+		serverAttachments, ok := existing[p.InstanceId]
+		if !ok {
+			var err error
+			serverAttachments, err = s.storage.ListVolumeAttachments(string(p.InstanceId))
+			if err != nil {
+				return nil, errors.Annotatef(err, "listing volumes attached to instance %s", p.InstanceId)
+			}
+			existing[p.InstanceId] = serverAttachments
+		}
+		attachment := findAttachment(serverAttachments, p.VolumeId)
+		if attachment == nil {
+			var err error
+			// Let Nova choose the device name.
+			attachment, err = s.storage.AttachVolume(string(p.InstanceId), p.VolumeId, "")
+			if err != nil {
+				return nil, errors.Annotatef(err, "attaching volume %s to instance %s", p.VolumeId, p.InstanceId)
+			}
+			existing[p.InstanceId] = append(serverAttachments, *attachment)
+		}
+		attachments[i] = storage.VolumeAttachment{
+			Volume:  p.Volume,
+			Machine: p.Machine,
+		}
+		if attachment.Device != nil {
+			attachments[i].DeviceName = strings.TrimPrefix(*attachment.Device, "/dev/")
+		}
+	}
+	return attachments, nil
+}
+
+// DetachVolumes is specified on the storage.VolumeSource interface.
+//
+// Volumes that are not attached to the specified instance are
+// considered detached.
+func (s *cinderVolumeSource) DetachVolumes(params []storage.VolumeAttachmentParams) error {
+	existing := make(map[instance.Id][]nova.VolumeAttachment)
+	for _, p := range params {
+		serverAttachments, ok := existing[p.InstanceId]
+		if !ok {
+			var err error
+			serverAttachments, err = s.storage.ListVolumeAttachments(string(p.InstanceId))
+			if err != nil {
+				return errors.Annotatef(err, "listing volumes attached to instance %s", p.InstanceId)
+			}
+			existing[p.InstanceId] = serverAttachments
+		}
+		attachment := findAttachment(serverAttachments, p.VolumeId)
+		if attachment == nil {
+			continue
+		}
+		if err := s.storage.DetachVolume(string(p.InstanceId), attachment.Id); err != nil {
+			return errors.Annotatef(err, "detaching volume %s from instance %s", p.VolumeId, p.InstanceId)
+		}
+	}
+	return nil
+}
+
+// findAttachment returns the attachment of the given volume,
+// or nil if there is none.
+func findAttachment(attachments []nova.VolumeAttachment, volumeId string) *nova.VolumeAttachment {
+	for i, attachment := range attachments {
+		if attachment.VolumeId == volumeId {
+			return &attachments[i]
+		}
+	}
+	return nil
+}
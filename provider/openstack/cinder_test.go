@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package openstack_test
+
+import (
+	"fmt"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/goose/testservices/hook"
+
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/provider/openstack"
+	"github.com/juju/juju/storage"
+	"github.com/juju/juju/storage/provider/registry"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type cinderProviderSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&cinderProviderSuite{})
+
+func (s *cinderProviderSuite) cinderProvider(c *gc.C) storage.Provider {
+	p, err := registry.StorageProvider(openstack.CinderProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	return p
+}
+
+func (s *cinderProviderSuite) TestValidateConfig(c *gc.C) {
+	p := s.cinderProvider(c)
+	cfg, err := storage.NewConfig("foo", openstack.CinderProviderType, map[string]interface{}{
+		"volume-type": "ssd",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), jc.ErrorIsNil)
+
+	cfg, err = storage.NewConfig("foo", openstack.CinderProviderType, map[string]interface{}{
+		"invalid": "config",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(p.ValidateConfig(cfg), gc.ErrorMatches, `unknown provider config option "invalid"`)
+}
+
+func (s *cinderProviderSuite) TestSupports(c *gc.C) {
+	p := s.cinderProvider(c)
+	c.Assert(p.Supports(storage.StorageKindBlock), jc.IsTrue)
+	c.Assert(p.Supports(storage.StorageKindFilesystem), jc.IsFalse)
+}
+
+// The remaining tests run against the Cinder and Nova service
+// doubles, as part of localServerSuite.
+
+func (s *localServerSuite) cinderVolumeSource(c *gc.C) storage.VolumeSource {
+	p, err := registry.StorageProvider(openstack.CinderProviderType)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg, err := storage.NewConfig("cinder", openstack.CinderProviderType, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	source, err := p.VolumeSource(s.env.Config(), cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	return source
+}
+
+func (s *localServerSuite) cinderVolumeParams(tag string, inst instance.Instance, attrs map[string]interface{}) storage.VolumeParams {
+	return storage.VolumeParams{
+		Tag:        names.NewVolumeTag(tag),
+		Size:       2 * 1024,
+		Provider:   openstack.CinderProviderType,
+		Attributes: attrs,
+		Attachment: &storage.VolumeAttachmentParams{
+			AttachmentParams: storage.AttachmentParams{
+				Machine:    names.NewMachineTag("100"),
+				InstanceId: inst.Id(),
+			},
+			Volume: names.NewVolumeTag(tag),
+		},
+	}
+}
+
+func (s *localServerSuite) cinderAttachmentParams(tag, volumeId string, inst instance.Instance) storage.VolumeAttachmentParams {
+	return storage.VolumeAttachmentParams{
+		AttachmentParams: storage.AttachmentParams{
+			Machine:    names.NewMachineTag("100"),
+			InstanceId: inst.Id(),
+		},
+		Volume:   names.NewVolumeTag(tag),
+		VolumeId: volumeId,
+	}
+}
+
+// attachedVolumeIds returns the IDs of the volumes
+// that Nova reports as attached to the instance.
+func (s *localServerSuite) attachedVolumeIds(c *gc.C, inst instance.Instance) []string {
+	attachments, err := openstack.GetNovaClient(s.env).ListVolumeAttachments(string(inst.Id()))
+	c.Assert(err, jc.ErrorIsNil)
+	var ids []string
+	for _, attachment := range attachments {
+		ids = append(ids, attachment.VolumeId)
+	}
+	return ids
+}
+
+func (s *localServerSuite) TestCinderCreateVolumes(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, "100")
+	source := s.cinderVolumeSource(c)
+	volumes, attachments, err := source.CreateVolumes([]storage.VolumeParams{
+		s.cinderVolumeParams("0", inst, nil),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 1)
+	c.Assert(volumes[0].Tag, gc.Equals, names.NewVolumeTag("0"))
+	c.Assert(volumes[0].Size, gc.Equals, uint64(2*1024))
+	c.Assert(attachments, gc.HasLen, 1)
+	c.Assert(attachments[0].Volume, gc.Equals, names.NewVolumeTag("0"))
+	c.Assert(attachments[0].Machine, gc.Equals, names.NewMachineTag("100"))
+	c.Assert(s.attachedVolumeIds(c, inst), jc.DeepEquals, []string{volumes[0].VolumeId})
+
+	described, err := source.DescribeVolumes([]string{volumes[0].VolumeId})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(described, jc.DeepEquals, []storage.Volume{{
+		VolumeId: volumes[0].VolumeId,
+		Size:     2 * 1024,
+	}})
+}
+
+func (s *localServerSuite) TestCinderCreateVolumesInvalidParams(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, "100")
+	source := s.cinderVolumeSource(c)
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{
+		s.cinderVolumeParams("0", inst, map[string]interface{}{"iops": 100}),
+	})
+	c.Assert(err, gc.ErrorMatches, `invalid parameters for volume 0: unknown provider config option "iops"`)
+}
+
+func (s *localServerSuite) TestCinderCreateVolumesErrorCleansUp(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, "100")
+	cleanup := s.srv.Service.Nova.RegisterControlPoint(
+		"attachVolume",
+		func(sc hook.ServiceControl, args ...interface{}) error {
+			return fmt.Errorf("failed on purpose")
+		},
+	)
+	defer cleanup()
+
+	source := s.cinderVolumeSource(c)
+	_, _, err := source.CreateVolumes([]storage.VolumeParams{
+		s.cinderVolumeParams("0", inst, nil),
+	})
+	c.Assert(err, gc.ErrorMatches, `attaching volume .* to instance .*: .*failed on purpose.*`)
+
+	// The volume that could not be attached has been deleted.
+	volumes, err := openstack.CinderVolumes(s.env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(volumes, gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestCinderDescribeVolumesNotFound(c *gc.C) {
+	source := s.cinderVolumeSource(c)
+	_, err := source.DescribeVolumes([]string{"42"})
+	c.Assert(err, gc.ErrorMatches, `volume "42" not found`)
+}
+
+func (s *localServerSuite) TestCinderDestroyVolumes(c *gc.C) {
+	source := s.cinderVolumeSource(c)
+	volumes, _, err := source.CreateVolumes([]storage.VolumeParams{{
+		Tag:      names.NewVolumeTag("0"),
+		Size:     1024,
+		Provider: openstack.CinderProviderType,
+	}})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Volumes that do not exist are considered destroyed.
+	errs := source.DestroyVolumes([]string{volumes[0].VolumeId, "42"})
+	c.Assert(errs, jc.DeepEquals, []error{nil, nil})
+
+	remaining, err := openstack.CinderVolumes(s.env)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remaining, gc.HasLen, 0)
+}
+
+func (s *localServerSuite) TestCinderAttachVolumesAlreadyAttached(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, "100")
+	source := s.cinderVolumeSource(c)
+	volumes, attachments, err := source.CreateVolumes([]storage.VolumeParams{
+		s.cinderVolumeParams("0", inst, nil),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	again, err := source.AttachVolumes([]storage.VolumeAttachmentParams{
+		s.cinderAttachmentParams("0", volumes[0].VolumeId, inst),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(again, jc.DeepEquals, attachments)
+	c.Assert(s.attachedVolumeIds(c, inst), jc.DeepEquals, []string{volumes[0].VolumeId})
+}
+
+func (s *localServerSuite) TestCinderDetachVolumes(c *gc.C) {
+	inst, _ := testing.AssertStartInstance(c, s.env, "100")
+	source := s.cinderVolumeSource(c)
+	volumes, _, err := source.CreateVolumes([]storage.VolumeParams{
+		s.cinderVolumeParams("0", inst, nil),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	params := []storage.VolumeAttachmentParams{
+		s.cinderAttachmentParams("0", volumes[0].VolumeId, inst),
+	}
+	err = source.DetachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.attachedVolumeIds(c, inst), gc.HasLen, 0)
+
+	// Detaching a detached volume is a no-op.
+	err = source.DetachVolumes(params)
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 	"text/template"
 
+	"launchpad.net/goose/cinder"
 	"launchpad.net/goose/errors"
 	"launchpad.net/goose/identity"
 	"launchpad.net/goose/nova"
@@ -36,8 +37,19 @@ func init() {
 var (
 	ShortAttempt   = &shortAttempt
 	StorageAttempt = &storageAttempt
+	CinderAttempt  = &cinderAttempt
 )
 
+// CinderVolumes returns all of the volumes in the
+// environment's Cinder endpoint.
+func CinderVolumes(e environs.Environ) ([]cinder.Volume, error) {
+	s, err := newOpenstackStorage(e.Config())
+	if err != nil {
+		return nil, err
+	}
+	return s.GetVolumesDetail()
+}
+
 // MetadataStorage returns a Storage instance which is used to store simplestreams metadata for tests.
 func MetadataStorage(e environs.Environ) storage.Storage {
 	ecfg := e.(*environ).ecfg()
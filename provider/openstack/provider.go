@@ -573,6 +573,8 @@ func (e *environ) SupportedArchitectures() ([]string, error) {
 var unsupportedConstraints = []string{
 	constraints.Tags,
 	constraints.CpuPower,
+	constraints.Preemptible,
+	constraints.SpotPrice,
 }
 
 // ConstraintsValidator is defined on the Environs interface.
@@ -682,7 +684,11 @@ func (e *environ) parsePlacement(placement string) (*openstackPlacement, error)
 // PrecheckInstance is defined on the state.Prechecker interface.
 func (e *environ) PrecheckInstance(series string, cons constraints.Value, placement string) error {
 	if placement != "" {
-		if _, err := e.parsePlacement(placement); err != nil {
+		p, err := e.parsePlacement(placement)
+		if err != nil {
+			return err
+		}
+		if err := common.ValidatePlacementZone(p.availabilityZone.Name, cons); err != nil {
 			return err
 		}
 	}
@@ -946,12 +952,15 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		if !placement.availabilityZone.State.Available {
 			return nil, fmt.Errorf("availability zone %q is unavailable", placement.availabilityZone.Name)
 		}
+		if err := common.ValidatePlacementZone(placement.availabilityZone.Name, args.Constraints); err != nil {
+			return nil, err
+		}
 		availabilityZones = append(availabilityZones, placement.availabilityZone.Name)
 	}
 
 	// If no availability zone is specified, then automatically spread across
-	// the known zones for optimal spread across the instance distribution
-	// group.
+	// the known zones (limited to those allowed by the zones constraint)
+	// for optimal spread across the instance distribution group.
 	if len(availabilityZones) == 0 {
 		var group []instance.Id
 		var err error
@@ -964,10 +973,18 @@ func (e *environ) StartInstance(args environs.StartInstanceParams) (*environs.St
 		zoneInstances, err := availabilityZoneAllocations(e, group)
 		if errors.IsNotImplemented(err) {
 			// Availability zones are an extension, so we may get a
-			// not implemented error; ignore these.
+			// not implemented error; ignore these unless the user
+			// asked for specific zones.
+			if args.Constraints.HasZones() {
+				return nil, errors.Annotate(err, "cannot satisfy zones constraint")
+			}
 		} else if err != nil {
 			return nil, err
 		} else {
+			zoneInstances, err = common.ConstrainZoneAllocations(zoneInstances, args.Constraints)
+			if err != nil {
+				return nil, err
+			}
 			for _, zone := range zoneInstances {
 				availabilityZones = append(availabilityZones, zone.ZoneName)
 			}
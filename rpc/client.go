@@ -10,6 +10,10 @@ import (
 
 var ErrShutdown = errors.New("connection is shut down")
 
+// ErrCancelled is returned by Conn.CallWithCancel when the
+// call is cancelled before a response is received.
+var ErrCancelled = errors.New("call cancelled")
+
 // Call represents an active RPC.
 type Call struct {
 	Request
@@ -17,6 +21,9 @@ type Call struct {
 	Response interface{}
 	Error    error
 	Done     chan *Call
+
+	// reqId holds the id the request was sent with.
+	reqId uint64
 }
 
 // RequestError represents an error returned from an RPC request.
@@ -54,6 +61,7 @@ func (conn *Conn) send(call *Call) {
 	}
 	conn.reqId++
 	reqId := conn.reqId
+	call.reqId = reqId
 	conn.clientPending[reqId] = call
 	conn.mutex.Unlock()
 
@@ -149,6 +157,55 @@ func (conn *Conn) Call(req Request, params, response interface{}) error {
 	return call.Error
 }
 
+// CallWithCancel is like Call except that if the cancel channel is
+// closed before the response arrives, the server is asked to cancel
+// the request and ErrCancelled is returned. Any response subsequently
+// sent by the server is discarded. Servers that do not support
+// cancellation will run the request to completion.
+func (conn *Conn) CallWithCancel(req Request, params, response interface{}, cancel <-chan struct{}) error {
+	call := conn.Go(req, params, response, make(chan *Call, 1))
+	select {
+	case <-call.Done:
+		return call.Error
+	case <-cancel:
+	}
+	if !conn.cancel(call) {
+		// The response arrived before we could cancel
+		// the call, so return it as usual.
+		<-call.Done
+		return call.Error
+	}
+	return ErrCancelled
+}
+
+// cancel abandons the given call and sends a cancel message to the
+// server. It returns false if the call has already completed.
+func (conn *Conn) cancel(call *Call) bool {
+	conn.sending.Lock()
+	defer conn.sending.Unlock()
+
+	conn.mutex.Lock()
+	if conn.clientPending[call.reqId] != call {
+		conn.mutex.Unlock()
+		return false
+	}
+	delete(conn.clientPending, call.reqId)
+	shutdown := conn.closing || conn.shutdown
+	conn.mutex.Unlock()
+
+	if shutdown {
+		return true
+	}
+	hdr := &Header{
+		RequestId: call.reqId,
+		Cancel:    true,
+	}
+	if err := conn.codec.WriteMessage(hdr, struct{}{}); err != nil {
+		logger.Errorf("cannot send cancel for request %d: %v", call.reqId, err)
+	}
+	return true
+}
+
 // Go invokes the request asynchronously.  It returns the Call structure representing
 // the invocation.  The done channel will signal when the call is complete by returning
 // the same Call object.  If done is nil, Go will allocate a new channel.
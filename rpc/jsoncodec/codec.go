@@ -62,28 +62,32 @@ func (c *Codec) isLogging() bool {
 // parameters or response yet, so we delay parsing by storing them
 // in a RawMessage.
 type inMsg struct {
-	RequestId uint64
-	Type      string
-	Version   int
-	Id        string
-	Request   string
-	Params    json.RawMessage
-	Error     string
-	ErrorCode string
-	Response  json.RawMessage
+	RequestId    uint64
+	Type         string
+	Version      int
+	Id           string
+	Request      string
+	Params       json.RawMessage
+	Error        string
+	ErrorCode    string
+	Response     json.RawMessage
+	Cancel       bool
+	Subscription string
 }
 
 // outMsg holds an outgoing message.
 type outMsg struct {
-	RequestId uint64
-	Type      string      `json:",omitempty"`
-	Version   int         `json:",omitempty"`
-	Id        string      `json:",omitempty"`
-	Request   string      `json:",omitempty"`
-	Params    interface{} `json:",omitempty"`
-	Error     string      `json:",omitempty"`
-	ErrorCode string      `json:",omitempty"`
-	Response  interface{} `json:",omitempty"`
+	RequestId    uint64
+	Type         string      `json:",omitempty"`
+	Version      int         `json:",omitempty"`
+	Id           string      `json:",omitempty"`
+	Request      string      `json:",omitempty"`
+	Params       interface{} `json:",omitempty"`
+	Error        string      `json:",omitempty"`
+	ErrorCode    string      `json:",omitempty"`
+	Response     interface{} `json:",omitempty"`
+	Cancel       bool        `json:",omitempty"`
+	Subscription string      `json:",omitempty"`
 }
 
 func (c *Codec) Close() error {
@@ -131,6 +135,8 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	}
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.Cancel = c.msg.Cancel
+	hdr.Subscription = c.msg.Subscription
 	return nil
 }
 
@@ -191,6 +197,8 @@ func (m *outMsg) init(hdr *rpc.Header, body interface{}) {
 	m.Request = hdr.Request.Action
 	m.Error = hdr.Error
 	m.ErrorCode = hdr.ErrorCode
+	m.Cancel = hdr.Cancel
+	m.Subscription = hdr.Subscription
 	if hdr.IsRequest() {
 		m.Params = body
 	} else {
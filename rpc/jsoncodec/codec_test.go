@@ -72,6 +72,19 @@ var readTests = []struct {
 		},
 	},
 	expectBody: &value{X: "param"},
+}, {
+	msg: `{"RequestId": 5, "Cancel": true}`,
+	expectHdr: rpc.Header{
+		RequestId: 5,
+		Cancel:    true,
+	},
+	expectBody: new(map[string]interface{}),
+}, {
+	msg: `{"RequestId": 0, "Subscription": "sub", "Response": {"X": "pushed"}}`,
+	expectHdr: rpc.Header{
+		Subscription: "sub",
+	},
+	expectBody: &value{X: "pushed"},
 }}
 
 func (*suite) TestRead(c *gc.C) {
@@ -255,6 +268,19 @@ var writeTests = []struct {
 	},
 	body:   &value{X: "param"},
 	expect: `{"RequestId": 4, "Type": "foo", "Version": 2, "Request": "frob", "Params": {"X": "param"}}`,
+}, {
+	hdr: &rpc.Header{
+		RequestId: 5,
+		Cancel:    true,
+	},
+	body:   struct{}{},
+	expect: `{"RequestId": 5, "Params": {}, "Cancel": true}`,
+}, {
+	hdr: &rpc.Header{
+		Subscription: "sub",
+	},
+	body:   &value{X: "pushed"},
+	expect: `{"RequestId": 0, "Subscription": "sub", "Response": {"X": "pushed"}}`,
 }}
 
 func (*suite) TestWrite(c *gc.C) {
@@ -1,34 +1,140 @@
 package jsoncodec
 
 import (
+	"bytes"
+	"compress/flate"
 	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
 	"net"
+	"net/http"
+	"strings"
 
 	"code.google.com/p/go.net/websocket"
 )
 
+// CompressionHeader is the HTTP header a client sets, when opening
+// a websocket connection, to the name of a compression method it
+// can read. The only method currently supported is "deflate".
+const CompressionHeader = "X-Juju-Compression"
+
+// CompressionDeflate names the deflate compression method.
+const CompressionDeflate = "deflate"
+
+// compressionThreshold holds the size of the smallest message that
+// will be compressed when compression is enabled. Smaller messages
+// are not worth the overhead.
+const compressionThreshold = 1024
+
+// maxInflatedSize holds the largest size to which a compressed
+// message may decompress. Compressed messages are accepted before
+// the other side has authenticated, so without a limit a small
+// message could be used to exhaust the memory of the receiver.
+var maxInflatedSize int64 = 64 << 20
+
 // NewWebsocket returns an rpc codec that uses the given websocket
-// connection to send and receive messages.
+// connection to send and receive messages. Messages are always sent
+// as plain JSON; compressed messages are accepted from the other side.
 func NewWebsocket(conn *websocket.Conn) *Codec {
-	return New(wsJSONConn{conn})
+	return New(wsJSONConn{
+		conn:  conn,
+		codec: newWebsocketCodec(false),
+	})
+}
+
+// NewWebsocketCompressed is like NewWebsocket except that large
+// messages are sent compressed with deflate, in binary frames.
+// It should only be used when the other side has said, with
+// CompressionHeader, that it can read them.
+func NewWebsocketCompressed(conn *websocket.Conn) *Codec {
+	return New(wsJSONConn{
+		conn:  conn,
+		codec: newWebsocketCodec(true),
+	})
+}
+
+// AcceptsCompression reports whether the client that made the
+// given websocket request can read compressed messages.
+func AcceptsCompression(req *http.Request) bool {
+	for _, method := range strings.Split(req.Header.Get(CompressionHeader), ",") {
+		if strings.TrimSpace(method) == CompressionDeflate {
+			return true
+		}
+	}
+	return false
 }
 
 type wsJSONConn struct {
-	conn *websocket.Conn
+	conn  *websocket.Conn
+	codec websocket.Codec
 }
 
 func (conn wsJSONConn) Send(msg interface{}) error {
-	return websocket.JSON.Send(conn.conn, msg)
+	return conn.codec.Send(conn.conn, msg)
 }
 
 func (conn wsJSONConn) Receive(msg interface{}) error {
-	return websocket.JSON.Receive(conn.conn, msg)
+	return conn.codec.Receive(conn.conn, msg)
 }
 
 func (conn wsJSONConn) Close() error {
 	return conn.conn.Close()
 }
 
+// newWebsocketCodec returns a websocket codec that sends messages
+// as JSON in text frames or, if compress is true and the message is
+// large, as deflated JSON in binary frames. It can receive either.
+func newWebsocketCodec(compress bool) websocket.Codec {
+	return websocket.Codec{
+		Marshal: func(v interface{}) ([]byte, byte, error) {
+			data, err := json.Marshal(v)
+			if err != nil || !compress || len(data) < compressionThreshold {
+				return data, websocket.TextFrame, err
+			}
+			data, err = deflate(data)
+			return data, websocket.BinaryFrame, err
+		},
+		Unmarshal: func(data []byte, payloadType byte, v interface{}) error {
+			if payloadType == websocket.BinaryFrame {
+				var err error
+				if data, err = inflate(data); err != nil {
+					return fmt.Errorf("cannot decompress message: %v", err)
+				}
+			}
+			return json.Unmarshal(data, v)
+		},
+	}
+}
+
+func deflate(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func inflate(data []byte) ([]byte, error) {
+	r := flate.NewReader(bytes.NewReader(data))
+	defer r.Close()
+	data, err := ioutil.ReadAll(io.LimitReader(r, maxInflatedSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > maxInflatedSize {
+		return nil, fmt.Errorf("message exceeds %d bytes", maxInflatedSize)
+	}
+	return data, nil
+}
+
 // NewNet returns an rpc codec that uses the given net
 // connection to send and receive messages.
 func NewNet(conn net.Conn) *Codec {
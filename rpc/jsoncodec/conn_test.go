@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jsoncodec_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"code.google.com/p/go.net/websocket"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/testing"
+)
+
+type connSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&connSuite{})
+
+// largeBody returns a value that encodes to a large, repetitive JSON
+// document, similar to that returned by FullStatus.
+func largeBody(n int) map[string]value {
+	body := make(map[string]value)
+	for i := 0; i < n; i++ {
+		body[fmt.Sprintf("machine-%d", i)] = value{
+			X: fmt.Sprintf("agent-state: started, instance-id: i-%08d, series: trusty", i),
+		}
+	}
+	return body
+}
+
+// websocketPair returns a pair of websocket connections joined
+// together. The server side is made with the given function.
+// The client asks for compression if compress is true.
+func websocketPair(c *gc.C, compress bool, newServer func(*websocket.Conn) *jsoncodec.Codec) (client *websocket.Conn, server *jsoncodec.Codec, cleanup func()) {
+	serverc := make(chan *jsoncodec.Codec)
+	done := make(chan struct{})
+	srv := httptest.NewServer(websocket.Server{
+		Handler: func(conn *websocket.Conn) {
+			serverc <- newServer(conn)
+			<-done
+		},
+	})
+	cfg, err := websocket.NewConfig(strings.Replace(srv.URL, "http://", "ws://", 1), "http://localhost/")
+	c.Assert(err, jc.ErrorIsNil)
+	if compress {
+		cfg.Header.Set(jsoncodec.CompressionHeader, jsoncodec.CompressionDeflate)
+	}
+	client, err = websocket.DialConfig(cfg)
+	c.Assert(err, jc.ErrorIsNil)
+	server = <-serverc
+	return client, server, func() {
+		client.Close()
+		close(done)
+		srv.Close()
+	}
+}
+
+// newNegotiatedServer returns a server codec that compresses
+// messages if the client asked for it.
+func newNegotiatedServer(conn *websocket.Conn) *jsoncodec.Codec {
+	if jsoncodec.AcceptsCompression(conn.Request()) {
+		return jsoncodec.NewWebsocketCompressed(conn)
+	}
+	return jsoncodec.NewWebsocket(conn)
+}
+
+func (*connSuite) TestAcceptsCompression(c *gc.C) {
+	for i, test := range []struct {
+		header string
+		expect bool
+	}{
+		{"", false},
+		{"gzip", false},
+		{"deflate", true},
+		{"gzip, deflate", true},
+	} {
+		c.Logf("test %d: %q", i, test.header)
+		req, err := http.NewRequest("GET", "http://localhost/", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		if test.header != "" {
+			req.Header.Set(jsoncodec.CompressionHeader, test.header)
+		}
+		c.Check(jsoncodec.AcceptsCompression(req), gc.Equals, test.expect)
+	}
+}
+
+func (*connSuite) TestCompressedRoundTrip(c *gc.C) {
+	client, server, cleanup := websocketPair(c, true, newNegotiatedServer)
+	defer cleanup()
+	clientCodec := jsoncodec.NewWebsocket(client)
+
+	for i, body := range []map[string]value{largeBody(1), largeBody(1000)} {
+		c.Logf("test %d", i)
+		hdr := &rpc.Header{RequestId: uint64(i + 1)}
+		err := server.WriteMessage(hdr, body)
+		c.Assert(err, jc.ErrorIsNil)
+
+		var gotHdr rpc.Header
+		err = clientCodec.ReadHeader(&gotHdr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(gotHdr, gc.DeepEquals, *hdr)
+		var gotBody map[string]value
+		err = clientCodec.ReadBody(&gotBody, false)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(gotBody, gc.DeepEquals, body)
+	}
+}
+
+func (*connSuite) TestCompressesOnlyLargeMessages(c *gc.C) {
+	client, server, cleanup := websocketPair(c, true, newNegotiatedServer)
+	defer cleanup()
+
+	err := server.WriteMessage(&rpc.Header{RequestId: 1}, largeBody(1))
+	c.Assert(err, jc.ErrorIsNil)
+	frame, payloadType := receiveFrame(c, client)
+	c.Assert(payloadType, gc.Equals, byte(websocket.TextFrame))
+	c.Assert(string(frame), jc.Contains, "machine-0")
+
+	body := largeBody(1000)
+	err = server.WriteMessage(&rpc.Header{RequestId: 2}, body)
+	c.Assert(err, jc.ErrorIsNil)
+	frame, payloadType = receiveFrame(c, client)
+	c.Assert(payloadType, gc.Equals, byte(websocket.BinaryFrame))
+	c.Assert(string(frame), gc.Not(jc.Contains), "machine-0")
+}
+
+func (*connSuite) TestNoCompressionWithoutNegotiation(c *gc.C) {
+	client, server, cleanup := websocketPair(c, false, newNegotiatedServer)
+	defer cleanup()
+
+	err := server.WriteMessage(&rpc.Header{RequestId: 1}, largeBody(1000))
+	c.Assert(err, jc.ErrorIsNil)
+	_, payloadType := receiveFrame(c, client)
+	c.Assert(payloadType, gc.Equals, byte(websocket.TextFrame))
+}
+
+func (s *connSuite) TestInflatedSizeLimited(c *gc.C) {
+	s.PatchValue(jsoncodec.MaxInflatedSize, int64(4096))
+	client, server, cleanup := websocketPair(c, true, newNegotiatedServer)
+	defer cleanup()
+
+	// Send a compressed message that inflates beyond the limit.
+	data, err := json.Marshal(largeBody(1000))
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.BestSpeed)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write(data)
+	c.Assert(err, jc.ErrorIsNil)
+	err = w.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	err = websocket.Message.Send(client, buf.Bytes())
+	c.Assert(err, jc.ErrorIsNil)
+
+	var hdr rpc.Header
+	err = server.ReadHeader(&hdr)
+	c.Assert(err, gc.ErrorMatches, ".*cannot decompress message: message exceeds 4096 bytes")
+}
+
+// receiveFrame reads a single raw frame from the given connection.
+func receiveFrame(c *gc.C, conn *websocket.Conn) ([]byte, byte) {
+	var data []byte
+	var payloadType byte
+	raw := websocket.Codec{
+		Unmarshal: func(msg []byte, t byte, v interface{}) error {
+			data, payloadType = msg, t
+			return nil
+		},
+	}
+	err := raw.Receive(conn, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	return data, payloadType
+}
+
+type BenchmarkSuite struct{}
+
+var _ = gc.Suite(&BenchmarkSuite{})
+
+func (*BenchmarkSuite) BenchmarkPlainCodec(c *gc.C) {
+	benchmarkCodec(c, false)
+}
+
+func (*BenchmarkSuite) BenchmarkCompressedCodec(c *gc.C) {
+	benchmarkCodec(c, true)
+}
+
+// benchmarkCodec measures the time taken to send a large message
+// across a websocket connection and read it at the other end.
+func benchmarkCodec(c *gc.C, compress bool) {
+	client, server, cleanup := websocketPair(c, compress, newNegotiatedServer)
+	defer cleanup()
+	clientCodec := jsoncodec.NewWebsocket(client)
+	body := largeBody(1000)
+
+	c.ResetTimer()
+	for i := 0; i < c.N; i++ {
+		go func() {
+			err := server.WriteMessage(&rpc.Header{RequestId: 1}, body)
+			c.Check(err, jc.ErrorIsNil)
+		}()
+		var hdr rpc.Header
+		err := clientCodec.ReadHeader(&hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		var gotBody map[string]value
+		err = clientCodec.ReadBody(&gotBody, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
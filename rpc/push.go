@@ -0,0 +1,79 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package rpc
+
+// PushHandler receives messages pushed by the other side of a
+// connection on a subscription. See Conn.Subscribe.
+type PushHandler interface {
+	// NewBody returns a value that the next pushed message
+	// body will be read into. It should be a struct pointer.
+	NewBody() interface{}
+
+	// HandlePush is called with each pushed message body, as
+	// previously returned by NewBody. It is called from the
+	// connection's input loop, so it must not block.
+	HandlePush(body interface{})
+}
+
+// Push sends a message with the given body to the other side of the
+// connection on the given subscription. Unlike a request, a pushed
+// message receives no reply; if the other side has no handler for
+// the subscription, the message is discarded.
+func (conn *Conn) Push(subscription string, body interface{}) error {
+	conn.sending.Lock()
+	defer conn.sending.Unlock()
+
+	conn.mutex.Lock()
+	shutdown := conn.closing || conn.shutdown
+	conn.mutex.Unlock()
+	if shutdown {
+		return ErrShutdown
+	}
+	if body == nil {
+		body = struct{}{}
+	}
+	hdr := &Header{
+		Subscription: subscription,
+	}
+	return conn.codec.WriteMessage(hdr, body)
+}
+
+// Subscribe arranges for messages pushed by the other side of the
+// connection on the given subscription to be passed to the handler,
+// replacing any handler previously registered for it.
+//
+// Subscribe should be called before asking the other side to start
+// pushing, so that no messages are missed.
+func (conn *Conn) Subscribe(subscription string, handler PushHandler) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.subscriptions[subscription] = handler
+}
+
+// Unsubscribe removes the handler for the given subscription.
+// Any messages subsequently pushed on it will be discarded.
+func (conn *Conn) Unsubscribe(subscription string) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	delete(conn.subscriptions, subscription)
+}
+
+// handlePush reads a message pushed by the other side and passes
+// it to the subscription's handler, if there is one.
+func (conn *Conn) handlePush(hdr *Header) error {
+	conn.mutex.Lock()
+	handler := conn.subscriptions[hdr.Subscription]
+	conn.mutex.Unlock()
+	if handler == nil {
+		// Nobody is interested in the message, but we
+		// must read the body anyway.
+		return conn.readBody(nil, false)
+	}
+	body := handler.NewBody()
+	if err := conn.readBody(body, false); err != nil {
+		return err
+	}
+	handler.HandlePush(body)
+	return nil
+}
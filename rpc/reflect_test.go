@@ -94,6 +94,30 @@ func (*reflectSuite) TestObjTypeOf(c *gc.C) {
 	c.Check(m, gc.DeepEquals, rpcreflect.ObjMethod{})
 }
 
+func (*reflectSuite) TestObjTypeOfCancellable(c *gc.C) {
+	objType := rpcreflect.ObjTypeOf(reflect.TypeOf(&CancelMethods{}))
+	c.Check(objType.DiscardedMethods(), gc.HasLen, 0)
+	c.Check(objType.MethodNames(), gc.DeepEquals, []string{"Echo", "Wait"})
+
+	m, err := objType.Method("Wait")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Cancellable, jc.IsTrue)
+	c.Check(m.Params, gc.IsNil)
+	c.Check(m.Result, gc.Equals, reflect.TypeOf(stringVal{}))
+
+	m, err = objType.Method("Echo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(m.Cancellable, jc.IsTrue)
+	c.Check(m.Params, gc.Equals, reflect.TypeOf(stringVal{}))
+	c.Check(m.Result, gc.Equals, reflect.TypeOf(stringVal{}))
+
+	// A cancellable method can still be called without a
+	// cancel channel.
+	ret, err := m.Call(reflect.ValueOf(&CancelMethods{}), reflect.ValueOf(stringVal{"foo"}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ret.Interface(), gc.Equals, stringVal{"Echo foo"})
+}
+
 func (*reflectSuite) TestValueOf(c *gc.C) {
 	v := rpcreflect.ValueOf(reflect.ValueOf(nil))
 	c.Check(v.IsValid(), jc.IsFalse)
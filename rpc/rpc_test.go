@@ -211,6 +211,35 @@ func (a *DelayedMethods) Delay() (stringVal, error) {
 	}
 }
 
+type CancelRoot struct {
+	ready     chan struct{}
+	cancelled chan struct{}
+	done      chan string
+}
+
+func (r *CancelRoot) CancelMethods(id string) (*CancelMethods, error) {
+	return &CancelMethods{r}, nil
+}
+
+type CancelMethods struct {
+	root *CancelRoot
+}
+
+func (a *CancelMethods) Wait(cancel <-chan struct{}) (stringVal, error) {
+	a.root.ready <- struct{}{}
+	select {
+	case s := <-a.root.done:
+		return stringVal{s}, nil
+	case <-cancel:
+		a.root.cancelled <- struct{}{}
+		return stringVal{}, fmt.Errorf("wait cancelled")
+	}
+}
+
+func (a *CancelMethods) Echo(cancel <-chan struct{}, s stringVal) stringVal {
+	return stringVal{"Echo " + s.Val}
+}
+
 type ErrorMethods struct {
 	err error
 }
@@ -239,6 +268,10 @@ func (a *CallbackMethods) Factorial(x int64val) (int64val, error) {
 	return int64val{x.I * r.I}, nil
 }
 
+func (a *CallbackMethods) Push(s stringVal) error {
+	return a.root.conn.Push("sub", s)
+}
+
 func (a *ChangeAPIMethods) ChangeAPI() {
 	a.r.conn.Serve(&changedAPIRoot{}, nil)
 }
@@ -853,6 +886,145 @@ func (*rpcSuite) TestServerWaitsForOutstandingCalls(c *gc.C) {
 	start <- "xxx"
 }
 
+func newCancelRoot() *CancelRoot {
+	return &CancelRoot{
+		ready:     make(chan struct{}, 1),
+		cancelled: make(chan struct{}, 1),
+		done:      make(chan string, 1),
+	}
+}
+
+func (*rpcSuite) TestCallWithCancel(c *gc.C) {
+	root := newCancelRoot()
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	cancel := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		var r stringVal
+		result <- client.CallWithCancel(rpc.Request{"CancelMethods", 0, "", "Wait"}, nil, &r, cancel)
+	}()
+	chanRead(c, root.ready, "CancelMethods.Wait ready")
+	close(cancel)
+	err := chanReadError(c, result, "cancelled call result")
+	c.Assert(err, gc.Equals, rpc.ErrCancelled)
+	chanRead(c, root.cancelled, "CancelMethods.Wait cancelled")
+
+	// The connection is still usable after cancelling a call,
+	// and the late error reply to the cancelled call is discarded.
+	var r stringVal
+	err = client.Call(rpc.Request{"CancelMethods", 0, "", "Echo"}, stringVal{"hello"}, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r, gc.Equals, stringVal{"Echo hello"})
+}
+
+func (*rpcSuite) TestCallWithCancelNotCancelled(c *gc.C) {
+	root := newCancelRoot()
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	root.done <- "xxx"
+	var r stringVal
+	err := client.CallWithCancel(rpc.Request{"CancelMethods", 0, "", "Wait"}, nil, &r, make(chan struct{}))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r, gc.Equals, stringVal{"xxx"})
+	chanRead(c, root.ready, "CancelMethods.Wait ready")
+}
+
+func (*rpcSuite) TestCallWithCancelNonCancellableMethod(c *gc.C) {
+	ready := make(chan struct{})
+	start := make(chan string)
+	root := SimpleRoot()
+	root.delayed = map[string]*DelayedMethods{
+		"1": {
+			ready: ready,
+			done:  start,
+		},
+	}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	cancel := make(chan struct{})
+	result := make(chan error, 1)
+	go func() {
+		var r stringVal
+		result <- client.CallWithCancel(rpc.Request{"DelayedMethods", 0, "1", "Delay"}, nil, &r, cancel)
+	}()
+	chanRead(c, ready, "DelayedMethods.Delay ready")
+	close(cancel)
+	err := chanReadError(c, result, "cancelled call result")
+	c.Assert(err, gc.Equals, rpc.ErrCancelled)
+
+	// The method runs to completion, and its reply is discarded.
+	start <- "xxx"
+	var r stringVal
+	err = client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r1"}, nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r, gc.Equals, stringVal{"Call0r1 ret"})
+}
+
+func (*rpcSuite) TestCancelledOnClose(c *gc.C) {
+	root := newCancelRoot()
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	done := make(chan struct{})
+	go func() {
+		var r stringVal
+		err := client.Call(rpc.Request{"CancelMethods", 0, "", "Wait"}, nil, &r)
+		c.Check(err, gc.Equals, rpc.ErrShutdown)
+		close(done)
+	}()
+	chanRead(c, root.ready, "CancelMethods.Wait ready")
+
+	// Closing the client causes the server connection to close,
+	// which cancels all its outstanding requests.
+	err := client.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	chanRead(c, root.cancelled, "CancelMethods.Wait cancelled")
+	chanRead(c, done, "call done")
+	closeClient(c, client, srvDone)
+}
+
+type pushHandler struct {
+	pushed chan *stringVal
+}
+
+func (h *pushHandler) NewBody() interface{} {
+	return new(stringVal)
+}
+
+func (h *pushHandler) HandlePush(body interface{}) {
+	h.pushed <- body.(*stringVal)
+}
+
+func (*rpcSuite) TestPush(c *gc.C) {
+	root := &Root{}
+	client, srvDone, _, _ := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+
+	handler := &pushHandler{make(chan *stringVal, 1)}
+	client.Subscribe("sub", handler)
+	// The pushed message is written before the reply, so
+	// it will have been handled by the time Call returns.
+	err := client.Call(rpc.Request{"CallbackMethods", 0, "", "Push"}, stringVal{"hello"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(handler.pushed, gc.HasLen, 1)
+	c.Assert(<-handler.pushed, gc.DeepEquals, &stringVal{"hello"})
+
+	// Once unsubscribed, pushed messages are discarded.
+	client.Unsubscribe("sub")
+	err = client.Call(rpc.Request{"CallbackMethods", 0, "", "Push"}, stringVal{"again"}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(handler.pushed, gc.HasLen, 0)
+}
+
+func (*rpcSuite) TestPushAfterClose(c *gc.C) {
+	client, srvDone, _, _ := newRPCClientServer(c, &Root{}, nil, false)
+	closeClient(c, client, srvDone)
+	err := client.Push("sub", stringVal{"hello"})
+	c.Assert(err, gc.Equals, rpc.ErrShutdown)
+}
+
 func chanRead(c *gc.C, ch <-chan struct{}, what string) {
 	select {
 	case <-ch:
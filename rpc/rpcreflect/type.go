@@ -13,6 +13,7 @@ import (
 var (
 	errorType  = reflect.TypeOf((*error)(nil)).Elem()
 	stringType = reflect.TypeOf("")
+	cancelType = reflect.TypeOf((<-chan struct{})(nil))
 )
 
 var (
@@ -212,10 +213,20 @@ type ObjMethod struct {
 	// if the method returns no value.
 	Result reflect.Type
 
+	// Cancellable holds whether the method takes a
+	// cancellation channel as its first argument.
+	Cancellable bool
+
 	// Call calls the method with the given argument
 	// on the given receiver value. If the method does
 	// not return a value, the returned value will not be valid.
+	// A cancellable method is passed a nil cancel channel.
 	Call func(rcvr, arg reflect.Value) (reflect.Value, error)
+
+	// CallCancel is like Call, except that a cancellable
+	// method is passed the given cancel channel, which
+	// will be closed if the caller abandons the request.
+	CallCancel func(rcvr, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error)
 }
 
 // ObjTypeOf returns information on all RPC methods
@@ -269,7 +280,7 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 		return nil
 	}
 	var p ObjMethod
-	var assemble func(arg reflect.Value) []reflect.Value
+	var assemble func(arg reflect.Value, cancel <-chan struct{}) []reflect.Value
 	// N.B. The method type has the receiver as its first argument
 	// unless the receiver is an interface.
 	receiverArgCount := 1
@@ -277,33 +288,44 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 		receiverArgCount = 0
 	}
 	t := m.Type
+	if t.NumIn() > receiverArgCount && t.In(receiverArgCount) == cancelType {
+		// Method(<-chan struct{}, ...) ...
+		p.Cancellable = true
+		receiverArgCount++
+	}
 	switch {
 	case t.NumIn() == 0+receiverArgCount:
 		// Method() ...
-		assemble = func(arg reflect.Value) []reflect.Value {
+		assemble = func(arg reflect.Value, cancel <-chan struct{}) []reflect.Value {
 			return nil
 		}
 	case t.NumIn() == 1+receiverArgCount:
 		// Method(T) ...
 		p.Params = t.In(receiverArgCount)
-		assemble = func(arg reflect.Value) []reflect.Value {
+		assemble = func(arg reflect.Value, cancel <-chan struct{}) []reflect.Value {
 			return []reflect.Value{arg}
 		}
 	default:
 		return nil
 	}
+	if p.Cancellable {
+		assembleArg := assemble
+		assemble = func(arg reflect.Value, cancel <-chan struct{}) []reflect.Value {
+			return append([]reflect.Value{reflect.ValueOf(cancel)}, assembleArg(arg, cancel)...)
+		}
+	}
 
 	switch {
 	case t.NumOut() == 0:
 		// Method(...)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallCancel = func(rcvr, arg reflect.Value, cancel <-chan struct{}) (r reflect.Value, err error) {
+			rcvr.Method(m.Index).Call(assemble(arg, cancel))
 			return
 		}
 	case t.NumOut() == 1 && t.Out(0) == errorType:
 		// Method(...) error
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallCancel = func(rcvr, arg reflect.Value, cancel <-chan struct{}) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(arg, cancel))
 			if !out[0].IsNil() {
 				err = out[0].Interface().(error)
 			}
@@ -312,15 +334,15 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	case t.NumOut() == 1:
 		// Method(...) R
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (reflect.Value, error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallCancel = func(rcvr, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error) {
+			out := rcvr.Method(m.Index).Call(assemble(arg, cancel))
 			return out[0], nil
 		}
 	case t.NumOut() == 2 && t.Out(1) == errorType:
 		// Method(...) (R, error)
 		p.Result = t.Out(0)
-		p.Call = func(rcvr, arg reflect.Value) (r reflect.Value, err error) {
-			out := rcvr.Method(m.Index).Call(assemble(arg))
+		p.CallCancel = func(rcvr, arg reflect.Value, cancel <-chan struct{}) (r reflect.Value, err error) {
+			out := rcvr.Method(m.Index).Call(assemble(arg, cancel))
 			r = out[0]
 			if !out[1].IsNil() {
 				err = out[1].Interface().(error)
@@ -337,5 +359,9 @@ func newMethod(m reflect.Method, receiverKind reflect.Kind) *ObjMethod {
 	if p.Result != nil && p.Result.Kind() != reflect.Struct {
 		return nil
 	}
+	callCancel := p.CallCancel
+	p.Call = func(rcvr, arg reflect.Value) (reflect.Value, error) {
+		return callCancel(rcvr, arg, nil)
+	}
 	return &p
 }
@@ -108,11 +108,15 @@ func (v Value) FindMethod(rootMethodName string, version int, objMethodName stri
 }
 
 func (caller methodCaller) Call(objId string, arg reflect.Value) (reflect.Value, error) {
+	return caller.CallCancel(objId, arg, nil)
+}
+
+func (caller methodCaller) CallCancel(objId string, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error) {
 	obj, err := caller.rootMethod.Call(caller.rootValue, objId)
 	if err != nil {
 		return reflect.Value{}, err
 	}
-	return caller.objMethod.Call(obj, arg)
+	return caller.objMethod.CallCancel(obj, arg, cancel)
 }
 
 func (caller methodCaller) ParamsType() reflect.Type {
@@ -134,3 +138,11 @@ type MethodCaller interface {
 	// call the method on that instance.
 	Call(objId string, arg reflect.Value) (reflect.Value, error)
 }
+
+// CancelCaller may be implemented by a MethodCaller that
+// can pass a cancellation channel through to the method
+// being called. The channel is closed when the caller
+// abandons the request.
+type CancelCaller interface {
+	CallCancel(objId string, arg reflect.Value, cancel <-chan struct{}) (reflect.Value, error)
+}
@@ -62,6 +62,17 @@ type Header struct {
 
 	// ErrorCode holds the code of the error, if any.
 	ErrorCode string
+
+	// Cancel is set when the message asks for the outstanding
+	// request with RequestId to be cancelled. A cancel message
+	// has no Request and its body is always empty.
+	Cancel bool
+
+	// Subscription is set when the message has been pushed
+	// from the other side without a request, and holds the
+	// subscription the message is for. Pushed messages have
+	// no RequestId and never receive a reply.
+	Subscription string
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -80,9 +91,11 @@ type Request struct {
 }
 
 // IsRequest returns whether the header represents an RPC request.  If
-// it is not a request, it is a response.
+// it is not a request, it is a response. Cancel messages count as
+// requests, as they are always sent by the side that made the
+// original request.
 func (hdr *Header) IsRequest() bool {
-	return hdr.Request.Type != "" || hdr.Request.Action != ""
+	return hdr.Request.Type != "" || hdr.Request.Action != "" || hdr.Cancel
 }
 
 // Note that we use "client request" and "server request" to name
@@ -102,6 +115,11 @@ type Conn struct {
 	// srvPending represents the current server requests.
 	srvPending sync.WaitGroup
 
+	// srvCancel holds a cancel channel for each current
+	// server request, keyed by request id. It is guarded
+	// by mutex.
+	srvCancel map[uint64]chan struct{}
+
 	// sending guards the write side of the codec - it ensures
 	// that codec.WriteMessage is not called concurrently.
 	// It also guards shutdown.
@@ -130,6 +148,10 @@ type Conn struct {
 	// clientPending holds all pending client requests.
 	clientPending map[uint64]*Call
 
+	// subscriptions holds the handlers for messages pushed
+	// from the other side, keyed by subscription.
+	subscriptions map[string]PushHandler
+
 	// closing is set when the connection is shutting down via
 	// Close.  When this is set, no more client or server requests
 	// will be initiated.
@@ -194,6 +216,8 @@ func NewConn(codec Codec, notifier RequestNotifier) *Conn {
 	return &Conn{
 		codec:         codec,
 		clientPending: make(map[uint64]*Call),
+		srvCancel:     make(map[uint64]chan struct{}),
+		subscriptions: make(map[string]PushHandler),
 		notifier:      notifier,
 	}
 }
@@ -240,6 +264,12 @@ func (conn *Conn) Start() {
 //	Method(T) (R, error)
 //	Method(T) error
 //
+// Any of these forms may also take a cancel channel of type
+// <-chan struct{} as their first argument. The channel is closed
+// if the client cancels the request (see Conn.CallWithCancel) or
+// the connection is closed, allowing long-running methods to give
+// up early.
+//
 // If transformErrors is non-nil, it will be called on all returned
 // non-nil errors, for example to transform the errors into ServerErrors
 // with specified codes.  There will be a panic if transformErrors
@@ -338,7 +368,12 @@ func (conn *Conn) Close() error {
 
 // Kill server requests if appropriate. Client requests will be
 // terminated when the input loop finishes.
+// Called with conn.mutex held.
 func (conn *Conn) killRequests() {
+	for reqId, cancel := range conn.srvCancel {
+		close(cancel)
+		delete(conn.srvCancel, reqId)
+	}
 	if killer, ok := conn.root.(Killer); ok {
 		killer.Kill()
 	}
@@ -409,9 +444,14 @@ func (conn *Conn) loop() error {
 		if err != nil {
 			return err
 		}
-		if hdr.IsRequest() {
+		switch {
+		case hdr.Cancel:
+			err = conn.handleCancel(&hdr)
+		case hdr.Subscription != "":
+			err = conn.handlePush(&hdr)
+		case hdr.IsRequest():
 			err = conn.handleRequest(&hdr)
-		} else {
+		default:
 			err = conn.handleResponse(&hdr)
 		}
 		if err != nil {
@@ -477,8 +517,10 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	conn.mutex.Lock()
 	closing := conn.closing
 	if !closing {
+		cancel := make(chan struct{})
+		conn.srvCancel[hdr.RequestId] = cancel
 		conn.srvPending.Add(1)
-		go conn.runRequest(req, arg, startTime)
+		go conn.runRequest(req, arg, cancel, startTime)
 	}
 	conn.mutex.Unlock()
 	if closing {
@@ -488,6 +530,35 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 	return nil
 }
 
+// handleCancel handles a request from the other side to cancel
+// one of its outstanding requests. The request's cancel channel
+// is closed, which will cause a cancellable method to return
+// early; other methods run to completion. In both cases the
+// reply is sent as usual.
+func (conn *Conn) handleCancel(hdr *Header) error {
+	if err := conn.readBody(nil, true); err != nil {
+		return err
+	}
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if cancel, ok := conn.srvCancel[hdr.RequestId]; ok {
+		logger.Debugf("cancelling request %d", hdr.RequestId)
+		close(cancel)
+		delete(conn.srvCancel, hdr.RequestId)
+	}
+	return nil
+}
+
+// releaseCancel forgets the cancel channel for the given
+// server request, which has now completed.
+func (conn *Conn) releaseCancel(reqId uint64, cancel chan struct{}) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	if conn.srvCancel[reqId] == cancel {
+		delete(conn.srvCancel, reqId)
+	}
+}
+
 func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, startTime time.Time) error {
 	conn.sending.Lock()
 	defer conn.sending.Unlock()
@@ -547,9 +618,17 @@ func (conn *Conn) bindRequest(hdr *Header) (boundRequest, error) {
 }
 
 // runRequest runs the given request and sends the reply.
-func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, startTime time.Time) {
+// The cancel channel is closed if the client cancels the request.
+func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, cancel chan struct{}, startTime time.Time) {
 	defer conn.srvPending.Done()
-	rv, err := req.Call(req.hdr.Request.Id, arg)
+	defer conn.releaseCancel(req.hdr.RequestId, cancel)
+	var rv reflect.Value
+	var err error
+	if caller, ok := req.MethodCaller.(rpcreflect.CancelCaller); ok {
+		rv, err = caller.CallCancel(req.hdr.Request.Id, arg, cancel)
+	} else {
+		rv, err = req.Call(req.hdr.Request.Id, arg)
+	}
 	if err != nil {
 		err = conn.writeErrorResponse(&req.hdr, req.transformErrors(err), startTime)
 	} else {
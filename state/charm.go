@@ -29,6 +29,10 @@ type charmDoc struct {
 	StoragePath   string
 	PendingUpload bool
 	Placeholder   bool
+
+	// Resources holds the names of the resources declared in the
+	// charm's metadata, which charm.Meta does not record.
+	Resources []string `bson:"resources,omitempty"`
 }
 
 // Charm represents the state of a charm in the environment.
@@ -106,6 +110,11 @@ func (c *Charm) Actions() *charm.Actions {
 	return c.doc.Actions
 }
 
+// Resources returns the names of the resources declared by the charm.
+func (c *Charm) Resources() []string {
+	return c.doc.Resources
+}
+
 // StoragePath returns the storage path of the charm bundle.
 func (c *Charm) StoragePath() string {
 	return c.doc.StoragePath
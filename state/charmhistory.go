@@ -0,0 +1,114 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+)
+
+// charmHistoryLimit is the maximum number of entries kept in the charm
+// history of a service; older entries are discarded.
+const charmHistoryLimit = 20
+
+// charmHistoryDoc records a charm set on a service.
+type charmHistoryDoc struct {
+	CharmURL *charm.URL `bson:"charmurl"`
+	Time     time.Time  `bson:"time"`
+	User     string     `bson:"user,omitempty"`
+}
+
+// CharmHistoryEntry records a charm set on a service, when it was
+// set, and by whom.
+type CharmHistoryEntry struct {
+	CharmURL *charm.URL
+	// Time is zero for the first charm of services deployed before
+	// charm history was recorded.
+	Time time.Time
+	// User is empty if the charm was not set by a user.
+	User string
+}
+
+// CharmHistory returns the charms set on the service, oldest first.
+// The last entry is the current charm of the service.
+func (s *Service) CharmHistory() []CharmHistoryEntry {
+	entries := make([]CharmHistoryEntry, len(s.doc.CharmHistory))
+	for i, doc := range s.doc.CharmHistory {
+		entries[i] = CharmHistoryEntry{
+			CharmURL: doc.CharmURL,
+			Time:     doc.Time,
+			User:     doc.User,
+		}
+	}
+	return entries
+}
+
+// PreviousCharmURL returns the URL of the charm the service ran
+// before its current one. It returns an error satisfying
+// errors.IsNotFound if the service has never changed charm.
+func (s *Service) PreviousCharmURL() (*charm.URL, error) {
+	history := s.doc.CharmHistory
+	for i := len(history) - 2; i >= 0; i-- {
+		if *history[i].CharmURL != *s.doc.CharmURL {
+			return history[i].CharmURL, nil
+		}
+	}
+	return nil, errors.NotFoundf("previous charm of service %q", s.doc.Name)
+}
+
+// RevertCharm changes the charm of the service back to the one
+// reported by PreviousCharmURL, and returns its URL. It fails if the
+// service's charm is changed concurrently. The user, which may be
+// empty, is recorded in the charm history; if upgradeArgs is not nil,
+// the units are upgraded in batches as by SetCharmInBatches.
+func (s *Service) RevertCharm(force bool, user names.UserTag, upgradeArgs *CharmUpgradeArgs) (_ *charm.URL, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot revert charm of service %q", s.doc.Name)
+	if upgradeArgs != nil {
+		if err := upgradeArgs.validate(); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	current := s.doc.CharmURL
+	previous, err := s.PreviousCharmURL()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ch, err := s.st.Charm(previous)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	err = s.setCharmWithUpgrade(ch, current, force, charmUsername(user), upgradeArgs)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return previous, nil
+}
+
+// pushCharmHistoryUpdate returns the update that records curl as the
+// current charm in the service's history, along with the assertion
+// that the history is as expected. Services created before charm
+// history was recorded have no history; their current charm is
+// recorded first, so that it can be reverted to.
+func (s *Service) pushCharmHistoryUpdate(curl *charm.URL, user string) (bson.DocElem, bson.D) {
+	var entries []charmHistoryDoc
+	var assert bson.D
+	if len(s.doc.CharmHistory) == 0 {
+		entries = append(entries, charmHistoryDoc{CharmURL: s.doc.CharmURL})
+		assert = bson.D{{"charmhistory", bson.D{{"$exists", false}}}}
+	}
+	entries = append(entries, charmHistoryDoc{
+		CharmURL: curl,
+		Time:     nowToTheSecond(),
+		User:     user,
+	})
+	update := bson.DocElem{"$push", bson.D{{"charmhistory", bson.D{
+		{"$each", entries},
+		{"$slice", -charmHistoryLimit},
+	}}}}
+	return update, assert
+}
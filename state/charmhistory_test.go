@@ -0,0 +1,187 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type CharmHistorySuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+}
+
+var _ = gc.Suite(&CharmHistorySuite{})
+
+func (s *CharmHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.service = s.AddTestingService(c, "mysql", s.charm)
+}
+
+type historyEntry struct {
+	curl *charm.URL
+	user string
+	// unknownTime is true for entries recorded without a time.
+	unknownTime bool
+}
+
+// assertHistory checks that the service's charm history holds the
+// expected entries, recorded no earlier than since.
+func (s *CharmHistorySuite) assertHistory(c *gc.C, since time.Time, expected ...historyEntry) {
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	history := s.service.CharmHistory()
+	c.Assert(history, gc.HasLen, len(expected))
+	for i, entry := range history {
+		c.Check(entry.CharmURL, gc.DeepEquals, expected[i].curl)
+		c.Check(entry.User, gc.Equals, expected[i].user)
+		if expected[i].unknownTime {
+			c.Check(entry.Time.IsZero(), jc.IsTrue)
+		} else {
+			c.Check(entry.Time.Before(since), jc.IsFalse)
+		}
+	}
+}
+
+func (s *CharmHistorySuite) TestAddServiceRecordsCharm(c *gc.C) {
+	since := state.NowToTheSecond().Add(-time.Minute)
+	s.assertHistory(c, since, historyEntry{curl: s.charm.URL(), user: s.Owner.Username()})
+	_, err := s.service.PreviousCharmURL()
+	c.Assert(err, gc.ErrorMatches, `previous charm of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmHistorySuite) TestSetCharmRecordsHistory(c *gc.C) {
+	since := state.NowToTheSecond().Add(-time.Minute)
+	ch2 := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err := s.service.SetCharmByUser(ch2, false, names.NewUserTag("bob"))
+	c.Assert(err, jc.ErrorIsNil)
+	ch3 := s.AddMetaCharm(c, "mysql", metaBase, 3)
+	err = s.service.SetCharm(ch3, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Setting the same charm again only changes the force flag.
+	err = s.service.SetCharm(ch3, true)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertHistory(c, since,
+		historyEntry{curl: s.charm.URL(), user: s.Owner.Username()},
+		historyEntry{curl: ch2.URL(), user: "bob@local"},
+		historyEntry{curl: ch3.URL()},
+	)
+	previous, err := s.service.PreviousCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(previous, gc.DeepEquals, ch2.URL())
+}
+
+func (s *CharmHistorySuite) TestPreviousCharmURLAfterRevert(c *gc.C) {
+	ch2 := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err := s.service.SetCharm(ch2, false)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.SetCharm(s.charm, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Reverting again goes back to the charm that was reverted.
+	previous, err := s.service.PreviousCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(previous, gc.DeepEquals, ch2.URL())
+}
+
+func (s *CharmHistorySuite) TestHistoryIsLimited(c *gc.C) {
+	var last *charm.URL
+	for rev := 2; rev < 30; rev++ {
+		ch := s.AddMetaCharm(c, "mysql", metaBase, rev)
+		err := s.service.SetCharm(ch, false)
+		c.Assert(err, jc.ErrorIsNil)
+		last = ch.URL()
+	}
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	history := s.service.CharmHistory()
+	c.Assert(history, gc.HasLen, 20)
+	c.Assert(history[0].CharmURL.Revision, gc.Equals, 10)
+	c.Assert(history[19].CharmURL, gc.DeepEquals, last)
+}
+
+func (s *CharmHistorySuite) TestServiceWithoutHistory(c *gc.C) {
+	// Services deployed before charm history was recorded have none.
+	services, closer := state.GetRawCollection(s.State, state.ServicesC)
+	defer closer()
+	err := services.UpdateId(state.DocID(s.State, "mysql"), bson.D{{"$unset", bson.D{{"charmhistory", 1}}}})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.service.CharmHistory(), gc.HasLen, 0)
+
+	since := state.NowToTheSecond().Add(-time.Minute)
+	ch2 := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err = s.service.SetCharm(ch2, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertHistory(c, since,
+		historyEntry{curl: s.charm.URL(), unknownTime: true},
+		historyEntry{curl: ch2.URL()},
+	)
+	previous, err := s.service.PreviousCharmURL()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(previous, gc.DeepEquals, s.charm.URL())
+}
+
+func (s *CharmHistorySuite) TestRevertCharm(c *gc.C) {
+	since := state.NowToTheSecond().Add(-time.Minute)
+	ch2 := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err := s.service.SetCharm(ch2, false)
+	c.Assert(err, jc.ErrorIsNil)
+
+	reverted, err := s.service.RevertCharm(true, names.NewUserTag("bob"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reverted, gc.DeepEquals, s.charm.URL())
+	curl, force := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.charm.URL())
+	c.Assert(force, jc.IsTrue)
+	s.assertHistory(c, since,
+		historyEntry{curl: s.charm.URL(), user: s.Owner.Username()},
+		historyEntry{curl: ch2.URL()},
+		historyEntry{curl: s.charm.URL(), user: "bob@local"},
+	)
+
+	// Reverting again goes back to the charm that was reverted.
+	reverted, err = s.service.RevertCharm(false, names.UserTag{}, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(reverted, gc.DeepEquals, ch2.URL())
+}
+
+func (s *CharmHistorySuite) TestRevertCharmWithoutPrevious(c *gc.C) {
+	_, err := s.service.RevertCharm(false, names.UserTag{}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot revert charm of service "mysql": previous charm of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmHistorySuite) TestRevertCharmConcurrentChange(c *gc.C) {
+	ch2 := s.AddMetaCharm(c, "mysql", metaBase, 2)
+	err := s.service.SetCharm(ch2, false)
+	c.Assert(err, jc.ErrorIsNil)
+	ch3 := s.AddMetaCharm(c, "mysql", metaBase, 3)
+	defer state.SetBeforeHooks(c, s.State, func() {
+		service, err := s.State.Service("mysql")
+		c.Assert(err, jc.ErrorIsNil)
+		err = service.SetCharm(ch3, false)
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+
+	_, err = s.service.RevertCharm(false, names.UserTag{}, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot revert charm of service "mysql": charm changed from ".*-2" to ".*-3"`)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, ch3.URL())
+}
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/state/charmmirror"
+)
+
+var (
+	charmMirrorNewStorage = charmmirror.NewStorage
+)
+
+// CharmMirror returns a new charmmirror.StorageCloser that stores
+// charm metadata in the "juju" database's "charmmirror" collection.
+// The mirror is shared by all the environments of the state server,
+// so its archives are held in the state server environment's blob
+// storage.
+func (st *State) CharmMirror() (charmmirror.StorageCloser, error) {
+	info, err := st.StateServerInfo()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot get state server info")
+	}
+	uuid := info.EnvironmentTag.Id()
+	session := st.db.Session.Copy()
+	txnRunner := st.txnRunner(session)
+	rs := blobstore.NewGridFS(blobstoreDB, uuid, session)
+	db := st.db.With(session)
+	managedStorage := blobstore.NewManagedStorage(db, rs)
+	metadataCollection := db.C(charmMirrorC)
+	storage := charmMirrorNewStorage(uuid, managedStorage, metadataCollection, txnRunner)
+	return &charmMirrorCloser{storage, session}, nil
+}
+
+type charmMirrorCloser struct {
+	charmmirror.Storage
+	session *mgo.Session
+}
+
+func (c *charmMirrorCloser) Close() error {
+	c.session.Close()
+	return nil
+}
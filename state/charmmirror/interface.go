@@ -0,0 +1,48 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmmirror
+
+import (
+	"io"
+
+	"gopkg.in/juju/charm.v4"
+)
+
+// Metadata describes a mirrored charm archive.
+type Metadata struct {
+	// URL is the charm store URL of the charm, including
+	// its revision.
+	URL    *charm.URL
+	Size   int64
+	SHA256 string
+}
+
+// Storage provides methods for storing and retrieving charm store
+// charm archives by URL.
+type Storage interface {
+	// AddCharm adds the charm archive and metadata into state. Mirrored
+	// revisions are immutable: adding the same archive again has no
+	// effect, and adding different content for a mirrored revision
+	// returns an error satisfying errors.IsAlreadyExists.
+	AddCharm(io.Reader, Metadata) error
+
+	// Charm returns the Metadata and archive contents for the
+	// specified charm URL if it exists, else an error satisfying
+	// errors.IsNotFound. The URL must include a revision.
+	Charm(*charm.URL) (Metadata, io.ReadCloser, error)
+
+	// Metadata returns the Metadata for the specified charm URL if
+	// it exists, else an error satisfying errors.IsNotFound. If the
+	// URL has no revision, the latest mirrored revision is returned.
+	Metadata(*charm.URL) (Metadata, error)
+
+	// AllMetadata returns metadata for all the mirrored charms.
+	AllMetadata() ([]Metadata, error)
+}
+
+// StorageCloser extends the Storage interface with a Close method.
+type StorageCloser interface {
+	Storage
+	Close() error
+}
@@ -0,0 +1,220 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmmirror
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+var logger = loggo.GetLogger("juju.state.charmmirror")
+
+type charmStorage struct {
+	envUUID            string
+	managedStorage     blobstore.ManagedStorage
+	metadataCollection *mgo.Collection
+	txnRunner          jujutxn.Runner
+}
+
+var _ Storage = (*charmStorage)(nil)
+
+// NewStorage constructs a new Storage that stores charm archives
+// in the provided ManagedStorage, and charm metadata in the provided
+// collection using the provided transaction runner.
+func NewStorage(
+	envUUID string,
+	managedStorage blobstore.ManagedStorage,
+	metadataCollection *mgo.Collection,
+	runner jujutxn.Runner,
+) Storage {
+	return &charmStorage{
+		envUUID:            envUUID,
+		managedStorage:     managedStorage,
+		metadataCollection: metadataCollection,
+		txnRunner:          runner,
+	}
+}
+
+func (s *charmStorage) AddCharm(r io.Reader, metadata Metadata) (resultErr error) {
+	curl := metadata.URL
+	if curl == nil || curl.Schema != "cs" {
+		return errors.NotValidf("charm URL %v", curl)
+	}
+	if curl.Revision < 0 {
+		return errors.NotValidf("charm URL %q without revision", curl)
+	}
+
+	// Revisions are immutable: the same archive may be mirrored again,
+	// but a revision can never be replaced by different content.
+	existing, err := s.charmMetadata(curl)
+	if err == nil {
+		return checkSameArchive(existing, metadata)
+	} else if !errors.IsNotFound(err) {
+		return errors.Annotate(err, "cannot read charm metadata")
+	}
+
+	// Add the charm archive to storage.
+	path := charmPath(curl, metadata.SHA256)
+	if err := s.managedStorage.PutForEnvironment(s.envUUID, path, r, metadata.Size); err != nil {
+		return errors.Annotate(err, "cannot store charm archive")
+	}
+	defer func() {
+		if resultErr == nil {
+			return
+		}
+		err := s.managedStorage.RemoveForEnvironment(s.envUUID, path)
+		if err != nil {
+			logger.Errorf("failed to remove charm blob: %v", err)
+		}
+	}()
+
+	newDoc := charmMetadataDoc{
+		Id:       curl.String(),
+		User:     curl.User,
+		Series:   curl.Series,
+		Name:     curl.Name,
+		Revision: curl.Revision,
+		Size:     metadata.Size,
+		SHA256:   metadata.SHA256,
+		Path:     path,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		// On the first attempt we assume we're adding a new charm.
+		// If that fails, the same revision was mirrored concurrently,
+		// and we succeed only if it has the same content.
+		if attempt > 0 {
+			existing, err := s.charmMetadata(curl)
+			if err != nil {
+				return nil, err
+			}
+			if err := checkSameArchive(existing, metadata); err != nil {
+				return nil, err
+			}
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      s.metadataCollection.Name,
+			Id:     newDoc.Id,
+			Assert: txn.DocMissing,
+			Insert: &newDoc,
+		}}, nil
+	}
+	if err := s.txnRunner.Run(buildTxn); err != nil {
+		if errors.IsAlreadyExists(err) {
+			return err
+		}
+		return errors.Annotate(err, "cannot store charm metadata")
+	}
+	return nil
+}
+
+// checkSameArchive returns an error satisfying errors.IsAlreadyExists
+// if the mirrored charm described by existing differs from that
+// described by metadata.
+func checkSameArchive(existing charmMetadataDoc, metadata Metadata) error {
+	if existing.SHA256 != metadata.SHA256 {
+		return errors.NewAlreadyExists(nil, fmt.Sprintf(
+			"charm %q already mirrored with different content", metadata.URL,
+		))
+	}
+	return nil
+}
+
+func (s *charmStorage) Charm(curl *charm.URL) (Metadata, io.ReadCloser, error) {
+	if curl.Revision < 0 {
+		return Metadata{}, nil, errors.NotValidf("charm URL %q without revision", curl)
+	}
+	metadataDoc, err := s.charmMetadata(curl)
+	if err != nil {
+		return Metadata{}, nil, err
+	}
+	r, _, err := s.managedStorage.GetForEnvironment(s.envUUID, metadataDoc.Path)
+	if err != nil {
+		return Metadata{}, nil, err
+	}
+	return metadataDoc.metadata(), r, nil
+}
+
+func (s *charmStorage) Metadata(curl *charm.URL) (Metadata, error) {
+	metadataDoc, err := s.charmMetadata(curl)
+	if err != nil {
+		return Metadata{}, err
+	}
+	return metadataDoc.metadata(), nil
+}
+
+func (s *charmStorage) AllMetadata() ([]Metadata, error) {
+	var docs []charmMetadataDoc
+	if err := s.metadataCollection.Find(nil).All(&docs); err != nil {
+		return nil, err
+	}
+	list := make([]Metadata, len(docs))
+	for i, doc := range docs {
+		list[i] = doc.metadata()
+	}
+	return list, nil
+}
+
+type charmMetadataDoc struct {
+	Id       string `bson:"_id"`
+	User     string `bson:"user,omitempty"`
+	Series   string `bson:"series"`
+	Name     string `bson:"name"`
+	Revision int    `bson:"revision"`
+	Size     int64  `bson:"size"`
+	SHA256   string `bson:"sha256"`
+	Path     string `bson:"path"`
+}
+
+func (doc *charmMetadataDoc) metadata() Metadata {
+	return Metadata{
+		URL:    charm.MustParseURL(doc.Id),
+		Size:   doc.Size,
+		SHA256: doc.SHA256,
+	}
+}
+
+// charmMetadata returns the metadata document for the charm with
+// the given URL or, if the URL has no revision, for the latest
+// mirrored revision of the charm.
+func (s *charmStorage) charmMetadata(curl *charm.URL) (charmMetadataDoc, error) {
+	var doc charmMetadataDoc
+	var err error
+	if curl.Revision < 0 {
+		// Charms outside any user namespace are stored without a
+		// user field.
+		var user interface{} = bson.D{{"$exists", false}}
+		if curl.User != "" {
+			user = curl.User
+		}
+		query := bson.D{
+			{"user", user},
+			{"series", curl.Series},
+			{"name", curl.Name},
+		}
+		err = s.metadataCollection.Find(query).Sort("-revision").One(&doc)
+	} else {
+		err = s.metadataCollection.FindId(curl.String()).One(&doc)
+	}
+	if err == mgo.ErrNotFound {
+		return doc, errors.NotFoundf("charm %q", curl)
+	} else if err != nil {
+		return doc, err
+	}
+	return doc, nil
+}
+
+// charmPath returns the storage path for the specified charm.
+func charmPath(curl *charm.URL, hash string) string {
+	return fmt.Sprintf("charmmirror/%s-%s", curl, hash)
+}
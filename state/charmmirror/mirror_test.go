@@ -0,0 +1,161 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmmirror_test
+
+import (
+	"io/ioutil"
+	"strings"
+	stdtesting "testing"
+
+	"github.com/juju/blobstore"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	jujutxn "github.com/juju/txn"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/state/charmmirror"
+	"github.com/juju/juju/testing"
+)
+
+var _ = gc.Suite(&MirrorSuite{})
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type MirrorSuite struct {
+	testing.BaseSuite
+	mongo              *gitjujutesting.MgoInstance
+	session            *mgo.Session
+	storage            charmmirror.Storage
+	managedStorage     blobstore.ManagedStorage
+	metadataCollection *mgo.Collection
+}
+
+func (s *MirrorSuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.mongo = &gitjujutesting.MgoInstance{}
+	s.mongo.Start(nil)
+
+	var err error
+	s.session, err = s.mongo.Dial()
+	c.Assert(err, jc.ErrorIsNil)
+	rs := blobstore.NewGridFS("blobstore", "my-uuid", s.session)
+	catalogue := s.session.DB("catalogue")
+	s.managedStorage = blobstore.NewManagedStorage(catalogue, rs)
+	s.metadataCollection = catalogue.C("charmmirror")
+	txnRunner := jujutxn.NewRunner(jujutxn.RunnerParams{Database: catalogue})
+	s.storage = charmmirror.NewStorage("my-uuid", s.managedStorage, s.metadataCollection, txnRunner)
+}
+
+func (s *MirrorSuite) TearDownTest(c *gc.C) {
+	s.session.Close()
+	s.mongo.DestroyWithLog()
+	s.BaseSuite.TearDownTest(c)
+}
+
+func (s *MirrorSuite) addCharm(c *gc.C, url, content string) charmmirror.Metadata {
+	metadata := charmmirror.Metadata{
+		URL:    charm.MustParseURL(url),
+		Size:   int64(len(content)),
+		SHA256: "hash(" + content + ")",
+	}
+	err := s.storage.AddCharm(strings.NewReader(content), metadata)
+	c.Assert(err, jc.ErrorIsNil)
+	return metadata
+}
+
+func (s *MirrorSuite) assertCharm(c *gc.C, url string, expected charmmirror.Metadata, content string) {
+	metadata, r, err := s.storage.Charm(charm.MustParseURL(url))
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	c.Assert(metadata, jc.DeepEquals, expected)
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+}
+
+func (s *MirrorSuite) TestAddCharm(c *gc.C) {
+	metadata := s.addCharm(c, "cs:trusty/mysql-38", "some-charm")
+	s.assertCharm(c, "cs:trusty/mysql-38", metadata, "some-charm")
+}
+
+func (s *MirrorSuite) TestAddCharmSameContent(c *gc.C) {
+	s.addCharm(c, "cs:trusty/mysql-38", "abc")
+	metadata := s.addCharm(c, "cs:trusty/mysql-38", "abc")
+	s.assertCharm(c, "cs:trusty/mysql-38", metadata, "abc")
+
+	all, err := s.storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(all, gc.HasLen, 1)
+}
+
+func (s *MirrorSuite) TestAddCharmDifferentContent(c *gc.C) {
+	metadata := s.addCharm(c, "cs:trusty/mysql-38", "abc")
+	err := s.storage.AddCharm(strings.NewReader("def"), charmmirror.Metadata{
+		URL:    charm.MustParseURL("cs:trusty/mysql-38"),
+		Size:   3,
+		SHA256: "hash(def)",
+	})
+	c.Assert(err, gc.ErrorMatches, `charm "cs:trusty/mysql-38" already mirrored with different content`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+
+	// The mirrored revision is untouched, and the rejected
+	// content is not stored.
+	s.assertCharm(c, "cs:trusty/mysql-38", metadata, "abc")
+	_, _, err = s.managedStorage.GetForEnvironment("my-uuid", "charmmirror/cs:trusty/mysql-38-hash(def)")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MirrorSuite) TestAddCharmInvalidURL(c *gc.C) {
+	err := s.storage.AddCharm(strings.NewReader("abc"), charmmirror.Metadata{
+		URL:  charm.MustParseURL("local:trusty/mysql-1"),
+		Size: 3,
+	})
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = s.storage.AddCharm(strings.NewReader("abc"), charmmirror.Metadata{
+		URL:  charm.MustParseURL("cs:trusty/mysql"),
+		Size: 3,
+	})
+	c.Assert(err, gc.ErrorMatches, `charm URL "cs:trusty/mysql" without revision not valid`)
+}
+
+func (s *MirrorSuite) TestCharmNotFound(c *gc.C) {
+	_, _, err := s.storage.Charm(charm.MustParseURL("cs:trusty/mysql-38"))
+	c.Assert(err, gc.ErrorMatches, `charm "cs:trusty/mysql-38" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MirrorSuite) TestMetadataLatest(c *gc.C) {
+	s.addCharm(c, "cs:trusty/mysql-3", "a")
+	latest := s.addCharm(c, "cs:trusty/mysql-10", "b")
+	s.addCharm(c, "cs:precise/mysql-20", "c")
+	s.addCharm(c, "cs:~bob/trusty/mysql-30", "d")
+
+	metadata, err := s.storage.Metadata(charm.MustParseURL("cs:trusty/mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, jc.DeepEquals, latest)
+
+	metadata, err = s.storage.Metadata(charm.MustParseURL("cs:~bob/trusty/mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.URL.String(), gc.Equals, "cs:~bob/trusty/mysql-30")
+
+	_, err = s.storage.Metadata(charm.MustParseURL("cs:vivid/mysql"))
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *MirrorSuite) TestAllMetadata(c *gc.C) {
+	metadata, err := s.storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 0)
+
+	s.addCharm(c, "cs:trusty/mysql-3", "a")
+	s.addCharm(c, "cs:precise/wordpress-1", "b")
+	metadata, err = s.storage.AllMetadata()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata, gc.HasLen, 2)
+}
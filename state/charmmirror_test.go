@@ -0,0 +1,70 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+
+	"github.com/juju/blobstore"
+	jc "github.com/juju/testing/checkers"
+	jujutxn "github.com/juju/txn"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/charmmirror"
+)
+
+type CharmMirrorSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&CharmMirrorSuite{})
+
+func (s *CharmMirrorSuite) TestStorageParams(c *gc.C) {
+	env, err := s.State.StateServerEnvironment()
+	c.Assert(err, jc.ErrorIsNil)
+
+	var called bool
+	s.PatchValue(state.CharmMirrorNewStorage, func(
+		envUUID string,
+		managedStorage blobstore.ManagedStorage,
+		metadataCollection *mgo.Collection,
+		runner jujutxn.Runner,
+	) charmmirror.Storage {
+		called = true
+		c.Assert(envUUID, gc.Equals, env.UUID())
+		c.Assert(managedStorage, gc.NotNil)
+		c.Assert(metadataCollection.Name, gc.Equals, "charmmirror")
+		c.Assert(runner, gc.NotNil)
+		return nil
+	})
+
+	mirror, err := s.State.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	mirror.Close()
+	c.Assert(called, jc.IsTrue)
+}
+
+func (s *CharmMirrorSuite) TestSharedByHostedEnvironments(c *gc.C) {
+	mirror, err := s.State.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	defer mirror.Close()
+	err = mirror.AddCharm(strings.NewReader("archive"), charmmirror.Metadata{
+		URL:    charm.MustParseURL("cs:trusty/mysql-38"),
+		Size:   7,
+		SHA256: "hash",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	st := s.Factory.MakeEnvironment(c, nil)
+	defer st.Close()
+	hostedMirror, err := st.CharmMirror()
+	c.Assert(err, jc.ErrorIsNil)
+	defer hostedMirror.Close()
+	metadata, err := hostedMirror.Metadata(charm.MustParseURL("cs:trusty/mysql"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metadata.URL.String(), gc.Equals, "cs:trusty/mysql-38")
+}
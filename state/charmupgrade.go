@@ -0,0 +1,380 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// DefaultCharmUpgradeTimeout is the time a batch of units is given to
+// upgrade before the next batch is released, if no timeout is given.
+const DefaultCharmUpgradeTimeout = 10 * time.Minute
+
+// CharmUpgradeArgs holds the parameters of a rolling charm upgrade.
+type CharmUpgradeArgs struct {
+	// BatchSize is the number of units upgraded at once.
+	BatchSize int
+
+	// PauseOnError causes the upgrade to pause when a unit of the
+	// current batch is in an error state.
+	PauseOnError bool
+
+	// Timeout is the time a batch is given to become healthy before
+	// the next batch is released. Zero means DefaultCharmUpgradeTimeout.
+	Timeout time.Duration
+}
+
+// charmUpgradeDoc records the progress of a rolling charm upgrade of a
+// service. Units of the service that are held back keep running the
+// charm the upgrade started from until they are released in a batch.
+type charmUpgradeDoc struct {
+	FromURL      *charm.URL    `bson:"fromurl"`
+	BatchSize    int           `bson:"batchsize"`
+	PauseOnError bool          `bson:"pauseonerror"`
+	Timeout      time.Duration `bson:"timeout"`
+
+	// Batch holds the names of the units currently upgrading.
+	Batch        []string  `bson:"batch"`
+	BatchStarted time.Time `bson:"batchstarted"`
+
+	// Held holds the names of the units waiting to be upgraded, in
+	// the order they will be released.
+	Held []string `bson:"held"`
+
+	Paused       bool   `bson:"paused"`
+	PausedReason string `bson:"pausedreason,omitempty"`
+}
+
+// CharmUpgradeStatus describes a rolling charm upgrade in progress.
+type CharmUpgradeStatus struct {
+	FromURL *charm.URL
+	Batch   []string
+	Held    []string
+	Paused  bool
+	// PausedReason explains why a paused upgrade was paused.
+	PausedReason string
+}
+
+// CharmUpgrade returns the progress of the service's rolling charm
+// upgrade. It returns an error satisfying errors.IsNotFound if no
+// rolling upgrade is in progress.
+func (s *Service) CharmUpgrade() (*CharmUpgradeStatus, error) {
+	up := s.doc.CharmUpgrade
+	if up == nil {
+		return nil, errors.NotFoundf("charm upgrade of service %q", s.doc.Name)
+	}
+	return &CharmUpgradeStatus{
+		FromURL:      up.FromURL,
+		Batch:        up.Batch,
+		Held:         up.Held,
+		Paused:       up.Paused,
+		PausedReason: up.PausedReason,
+	}, nil
+}
+
+// CharmURLForUnit returns the charm URL the named unit of the service
+// should run, and whether units should upgrade to it even if they are
+// in an error state. Units held back by a rolling charm upgrade keep
+// the charm the upgrade started from.
+func (s *Service) CharmURLForUnit(unitName string) (*charm.URL, bool) {
+	if up := s.doc.CharmUpgrade; up != nil && containsString(up.Held, unitName) {
+		return up.FromURL, s.doc.ForceCharm
+	}
+	return s.CharmURL()
+}
+
+// SetCharmInBatches changes the charm for the service like
+// SetCharmByUser, but upgrades the existing units in batches: each
+// batch is released only once the previous one is healthy, or its
+// timeout expired. New units are started with the new charm at once.
+// See AdvanceCharmUpgrade.
+func (s *Service) SetCharmInBatches(ch *Charm, force bool, user names.UserTag, args CharmUpgradeArgs) error {
+	if err := args.validate(); err != nil {
+		return errors.Trace(err)
+	}
+	return s.setCharmWithUpgrade(ch, nil, force, charmUsername(user), &args)
+}
+
+// validate returns an error if the arguments are not valid, and
+// otherwise fills in the default timeout if none is set.
+func (args *CharmUpgradeArgs) validate() error {
+	if args.BatchSize < 1 {
+		return errors.Errorf("batch size must be at least 1")
+	}
+	if args.Timeout < 0 {
+		return errors.Errorf("timeout must not be negative")
+	}
+	if args.Timeout == 0 {
+		args.Timeout = DefaultCharmUpgradeTimeout
+	}
+	return nil
+}
+
+// charmUsername returns the name recorded in the charm history for
+// the given user, which is empty if no user is given.
+func charmUsername(user names.UserTag) string {
+	if user.Id() == "" {
+		return ""
+	}
+	return user.Username()
+}
+
+// charmUpgradeOp returns the operation that starts a rolling upgrade
+// of the service's units from its current charm to curl, or that
+// cancels any rolling upgrade in progress if args is nil. It returns
+// nil if there are no units to upgrade.
+func (s *Service) charmUpgradeOp(curl *charm.URL, args *CharmUpgradeArgs) (*txn.Op, error) {
+	if args == nil {
+		return &txn.Op{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Update: bson.D{{"$unset", bson.D{{"charmupgrade", nil}}}},
+		}, nil
+	}
+	if s.doc.CharmUpgrade != nil {
+		return nil, errors.Errorf("charm upgrade of service %q already in progress", s.doc.Name)
+	}
+	if *s.doc.CharmURL == *curl {
+		return nil, nil
+	}
+	units, err := s.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var held []string
+	for _, unit := range units {
+		// Units yet to deploy a charm will deploy the new one.
+		if unitURL, _ := unit.CharmURL(); unitURL != nil && *unitURL != *curl {
+			held = append(held, unit.Name())
+		}
+	}
+	if len(held) == 0 {
+		return nil, nil
+	}
+	sort.Sort(unitNameSlice(held))
+	batch, held := splitBatch(held, args.BatchSize)
+	doc := &charmUpgradeDoc{
+		FromURL:      s.doc.CharmURL,
+		BatchSize:    args.BatchSize,
+		PauseOnError: args.PauseOnError,
+		Timeout:      args.Timeout,
+		Batch:        batch,
+		BatchStarted: nowToTheSecond(),
+		Held:         held,
+	}
+	return &txn.Op{
+		C:      servicesC,
+		Id:     s.doc.DocID,
+		Assert: bson.D{{"charmupgrade", bson.D{{"$exists", false}}}},
+		Update: bson.D{{"$set", bson.D{{"charmupgrade", doc}}}},
+	}, nil
+}
+
+// AdvanceCharmUpgrade releases the next batch of units of the
+// service's rolling charm upgrade, once every unit of the current
+// batch runs the new charm with an active agent and a running (or
+// unknown) workload, or the batch timed out. A unit of the batch in an error
+// state keeps the batch unhealthy; if the upgrade pauses on error, it
+// pauses the upgrade instead. The upgrade completes when no units are
+// left to release. It does nothing if no rolling upgrade is in
+// progress, or if it is paused.
+func (s *Service) AdvanceCharmUpgrade() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		up := s.doc.CharmUpgrade
+		if up == nil || up.Paused {
+			return nil, jujutxn.ErrNoOperations
+		}
+		healthy, failure, err := s.charmUpgradeBatchHealth(up)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		var update bson.D
+		switch {
+		case failure != "" && up.PauseOnError:
+			update = bson.D{{"$set", bson.D{
+				{"charmupgrade.paused", true},
+				{"charmupgrade.pausedreason", failure},
+			}}}
+		case healthy || nowToTheSecond().Sub(up.BatchStarted) >= up.Timeout:
+			update = releaseBatchUpdate(up)
+		default:
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:  servicesC,
+			Id: s.doc.DocID,
+			Assert: bson.D{
+				{"charmupgrade.batchstarted", up.BatchStarted},
+				{"charmupgrade.paused", false},
+			},
+			Update: update,
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot advance charm upgrade of service %q", s)
+	}
+	return nil
+}
+
+// ResumeCharmUpgrade resumes the service's paused rolling charm
+// upgrade, releasing the next batch of units.
+func (s *Service) ResumeCharmUpgrade() error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		up := s.doc.CharmUpgrade
+		if up == nil {
+			return nil, errors.NotFoundf("charm upgrade of service %q", s.doc.Name)
+		}
+		if !up.Paused {
+			return nil, errors.Errorf("charm upgrade of service %q is not paused", s.doc.Name)
+		}
+		return []txn.Op{{
+			C:  servicesC,
+			Id: s.doc.DocID,
+			Assert: bson.D{
+				{"charmupgrade.batchstarted", up.BatchStarted},
+				{"charmupgrade.paused", true},
+			},
+			Update: releaseBatchUpdate(up),
+		}}, nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot resume charm upgrade of service %q", s)
+	}
+	return nil
+}
+
+// releaseBatchUpdate returns the update that releases the next batch
+// of held units, or completes the upgrade if none are left.
+func releaseBatchUpdate(up *charmUpgradeDoc) bson.D {
+	if len(up.Held) == 0 {
+		return bson.D{{"$unset", bson.D{{"charmupgrade", nil}}}}
+	}
+	batch, held := splitBatch(up.Held, up.BatchSize)
+	return bson.D{{"$set", bson.D{
+		{"charmupgrade.batch", batch},
+		{"charmupgrade.batchstarted", nowToTheSecond()},
+		{"charmupgrade.held", held},
+		{"charmupgrade.paused", false},
+		{"charmupgrade.pausedreason", ""},
+	}}}
+}
+
+// charmUpgradeBatchHealth reports whether all units of the current
+// batch have upgraded and are healthy, or describes the first unit of
+// the batch found in an error state. Units that have been removed
+// count as healthy.
+func (s *Service) charmUpgradeBatchHealth(up *charmUpgradeDoc) (healthy bool, failure string, err error) {
+	healthy = true
+	for _, name := range up.Batch {
+		unit, err := s.st.Unit(name)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return false, "", errors.Trace(err)
+		}
+		if unit.Life() == Dead {
+			continue
+		}
+		agentStatus, info, _, err := unit.AgentStatus()
+		if err != nil {
+			return false, "", errors.Trace(err)
+		}
+		if agentStatus == StatusError {
+			return false, fmt.Sprintf("unit %q is in an error state: %s", name, info), nil
+		}
+		workloadStatus, info, _, err := unit.Status()
+		if err != nil {
+			return false, "", errors.Trace(err)
+		}
+		if workloadStatus == StatusError {
+			return false, fmt.Sprintf("unit %q is in an error state: %s", name, info), nil
+		}
+		unitURL, _ := unit.CharmURL()
+		if unitURL == nil || *unitURL != *s.doc.CharmURL {
+			healthy = false
+		} else if agentStatus != StatusActive || !healthyWorkloadStatus(workloadStatus) {
+			healthy = false
+		}
+	}
+	return healthy, "", nil
+}
+
+// healthyWorkloadStatus reports whether a unit of a batch with the
+// supplied workload status is healthy. An unknown status means the
+// charm does not report the state of its workload, so only the agent
+// is taken into account; busy, waiting and blocked units leave the
+// batch to time out.
+func healthyWorkloadStatus(status Status) bool {
+	return status == StatusRunning || status == StatusUnknown
+}
+
+// ServicesUpgradingCharm returns the services with a rolling charm
+// upgrade in progress.
+func (st *State) ServicesUpgradingCharm() ([]*Service, error) {
+	servicesCollection, closer := st.getCollection(servicesC)
+	defer closer()
+
+	var docs []serviceDoc
+	err := servicesCollection.Find(bson.D{{"charmupgrade", bson.D{{"$exists", true}}}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get services upgrading charm")
+	}
+	services := make([]*Service, len(docs))
+	for i := range docs {
+		services[i] = newService(st, &docs[i])
+	}
+	return services, nil
+}
+
+// splitBatch splits the first size unit names off unitNames.
+func splitBatch(unitNames []string, size int) (batch, rest []string) {
+	if size > len(unitNames) {
+		size = len(unitNames)
+	}
+	return unitNames[:size], unitNames[size:]
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// unitNameSlice sorts unit names of a service by unit number.
+type unitNameSlice []string
+
+func (s unitNameSlice) Len() int      { return len(s) }
+func (s unitNameSlice) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s unitNameSlice) Less(i, j int) bool {
+	return unitNumber(s[i]) < unitNumber(s[j])
+}
+
+func unitNumber(name string) int {
+	n, _ := strconv.Atoi(name[strings.LastIndex(name, "/")+1:])
+	return n
+}
@@ -0,0 +1,275 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+
+	"github.com/juju/juju/state"
+)
+
+type CharmUpgradeSuite struct {
+	ConnSuite
+	charm   *state.Charm
+	service *state.Service
+	units   []*state.Unit
+	newCh   *state.Charm
+}
+
+var _ = gc.Suite(&CharmUpgradeSuite{})
+
+func (s *CharmUpgradeSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.charm = s.AddTestingCharm(c, "mysql")
+	s.service = s.AddTestingService(c, "mysql", s.charm)
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := s.service.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(s.charm.URL())
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+	s.newCh = s.AddMetaCharm(c, "mysql", metaBase, 2)
+}
+
+func (s *CharmUpgradeSuite) startUpgrade(c *gc.C, pauseOnError bool) {
+	err := s.service.SetCharmInBatches(s.newCh, false, names.NewUserTag("bob"), state.CharmUpgradeArgs{
+		BatchSize:    1,
+		PauseOnError: pauseOnError,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmUpgradeSuite) assertUpgrade(c *gc.C, batch, held []string, paused bool) *state.CharmUpgradeStatus {
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade, err := s.service.CharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(upgrade.FromURL, gc.DeepEquals, s.charm.URL())
+	c.Assert(upgrade.Batch, jc.DeepEquals, batch)
+	c.Assert(upgrade.Held, jc.DeepEquals, held)
+	c.Assert(upgrade.Paused, gc.Equals, paused)
+	return upgrade
+}
+
+func (s *CharmUpgradeSuite) assertNoUpgrade(c *gc.C) {
+	err := s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.CharmUpgrade()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *CharmUpgradeSuite) assertCharmURLForUnit(c *gc.C, unit *state.Unit, expected *charm.URL) {
+	curl, force := s.service.CharmURLForUnit(unit.Name())
+	c.Assert(curl, gc.DeepEquals, expected)
+	c.Assert(force, jc.IsFalse)
+}
+
+// upgradeUnit marks the unit as running the new charm with an active
+// agent and a running workload.
+func (s *CharmUpgradeSuite) upgradeUnit(c *gc.C, unit *state.Unit) {
+	err := unit.SetCharmURL(s.newCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetAgentStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetStatus(state.StatusRunning, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// expireBatch makes the current batch of the upgrade time out.
+func (s *CharmUpgradeSuite) expireBatch(c *gc.C) {
+	services, closer := state.GetRawCollection(s.State, state.ServicesC)
+	defer closer()
+	err := services.UpdateId(state.DocID(s.State, "mysql"), bson.D{{"$set", bson.D{
+		{"charmupgrade.batchstarted", time.Now().Add(-time.Hour)},
+	}}})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmInBatchesHoldsUnits(c *gc.C) {
+	// Units yet to deploy a charm are not held.
+	newUnit, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.startUpgrade(c, false)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, false)
+	curl, _ := s.service.CharmURL()
+	c.Assert(curl, gc.DeepEquals, s.newCh.URL())
+	s.assertCharmURLForUnit(c, s.units[0], s.newCh.URL())
+	s.assertCharmURLForUnit(c, s.units[1], s.charm.URL())
+	s.assertCharmURLForUnit(c, s.units[2], s.charm.URL())
+	s.assertCharmURLForUnit(c, newUnit, s.newCh.URL())
+
+	services, err := s.State.ServicesUpgradingCharm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(services, gc.HasLen, 1)
+	c.Assert(services[0].Name(), gc.Equals, "mysql")
+
+	err = s.service.SetCharmInBatches(s.newCh, false, names.UserTag{}, state.CharmUpgradeArgs{BatchSize: 1})
+	c.Assert(err, gc.ErrorMatches, `charm upgrade of service "mysql" already in progress`)
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmInBatchesInvalidArgs(c *gc.C) {
+	err := s.service.SetCharmInBatches(s.newCh, false, names.UserTag{}, state.CharmUpgradeArgs{})
+	c.Assert(err, gc.ErrorMatches, "batch size must be at least 1")
+	err = s.service.SetCharmInBatches(s.newCh, false, names.UserTag{}, state.CharmUpgradeArgs{
+		BatchSize: 1,
+		Timeout:   -time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "timeout must not be negative")
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceWaitsForHealthyBatch(c *gc.C) {
+	s.startUpgrade(c, false)
+
+	err := s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, false)
+
+	s.upgradeUnit(c, s.units[0])
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+	s.assertCharmURLForUnit(c, s.units[1], s.newCh.URL())
+	s.assertCharmURLForUnit(c, s.units[2], s.charm.URL())
+
+	s.upgradeUnit(c, s.units[1])
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/2"}, []string{}, false)
+
+	s.upgradeUnit(c, s.units[2])
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoUpgrade(c)
+	s.assertCharmURLForUnit(c, s.units[2], s.newCh.URL())
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceWaitsForRunningWorkload(c *gc.C) {
+	s.startUpgrade(c, false)
+	err := s.units[0].SetCharmURL(s.newCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The workload is still busy, as it was when the unit was added.
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, false)
+
+	err = s.units[0].SetStatus(state.StatusWaiting, "for database", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, false)
+
+	err = s.units[0].SetStatus(state.StatusRunning, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceWithUnknownWorkload(c *gc.C) {
+	s.startUpgrade(c, false)
+	err := s.units[0].SetCharmURL(s.newCh.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].SetAgentStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A charm that does not report the state of its workload is judged
+	// by its agent alone.
+	err = s.units[0].SetStatus(state.StatusUnknown, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceAfterTimeout(c *gc.C) {
+	s.startUpgrade(c, false)
+	s.expireBatch(c)
+
+	err := s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+}
+
+func (s *CharmUpgradeSuite) TestAdvancePausesOnError(c *gc.C) {
+	s.startUpgrade(c, true)
+	err := s.units[0].SetAgentStatus(state.StatusError, `hook failed: "upgrade-charm"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade := s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, true)
+	c.Assert(upgrade.PausedReason, gc.Equals, `unit "mysql/0" is in an error state: hook failed: "upgrade-charm"`)
+	s.assertCharmURLForUnit(c, s.units[1], s.charm.URL())
+
+	// A paused upgrade does not advance, even once the unit recovers.
+	s.upgradeUnit(c, s.units[0])
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, true)
+
+	err = s.service.ResumeCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	upgrade = s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+	c.Assert(upgrade.PausedReason, gc.Equals, "")
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceWaitsOnErrorUnlessPausing(c *gc.C) {
+	s.startUpgrade(c, false)
+	err := s.units[0].SetAgentStatus(state.StatusError, `hook failed: "upgrade-charm"`, nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// A failing batch is unhealthy, and so waits for the timeout.
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/0"}, []string{"mysql/1", "mysql/2"}, false)
+
+	s.expireBatch(c)
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+}
+
+func (s *CharmUpgradeSuite) TestAdvanceSkipsRemovedUnits(c *gc.C) {
+	s.startUpgrade(c, false)
+	err := s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = s.service.AdvanceCharmUpgrade()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUpgrade(c, []string{"mysql/1"}, []string{"mysql/2"}, false)
+}
+
+func (s *CharmUpgradeSuite) TestResumeNotPaused(c *gc.C) {
+	err := s.service.ResumeCharmUpgrade()
+	c.Assert(err, gc.ErrorMatches, `cannot resume charm upgrade of service "mysql": charm upgrade of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	s.startUpgrade(c, true)
+	err = s.service.ResumeCharmUpgrade()
+	c.Assert(err, gc.ErrorMatches, `cannot resume charm upgrade of service "mysql": charm upgrade of service "mysql" is not paused`)
+}
+
+func (s *CharmUpgradeSuite) TestSetCharmCancelsUpgrade(c *gc.C) {
+	s.startUpgrade(c, false)
+	ch3 := s.AddMetaCharm(c, "mysql", metaBase, 3)
+	err := s.service.SetCharm(ch3, false)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertNoUpgrade(c)
+	s.assertCharmURLForUnit(c, s.units[2], ch3.URL())
+}
@@ -24,6 +24,7 @@ const (
 	cleanupServicesForDyingEnvironment cleanupKind = "services"
 	cleanupForceDestroyedMachine       cleanupKind = "machine"
 	cleanupAttachmentsForDyingStorage  cleanupKind = "storageAttachments"
+	cleanupResourceBlob                cleanupKind = "resourceBlob"
 )
 
 // cleanupDoc represents a potentially large set of documents that should be
@@ -88,6 +89,8 @@ func (st *State) Cleanup() error {
 			err = st.cleanupForceDestroyedMachine(doc.Prefix)
 		case cleanupAttachmentsForDyingStorage:
 			err = st.cleanupAttachmentsForDyingStorage(doc.Prefix)
+		case cleanupResourceBlob:
+			err = st.cleanupResourceBlob(doc.Prefix)
 		default:
 			err = fmt.Errorf("unknown cleanup kind %q", doc.Kind)
 		}
@@ -82,11 +82,14 @@ var multiEnvCollections = set.NewStrings(
 	rebootC,
 	relationScopesC,
 	relationsC,
+	remoteServicesC,
 	requestedNetworksC,
 	sequenceC,
+	serviceOffersC,
 	servicesC,
 	settingsC,
 	settingsrefsC,
+	spacesC,
 	statusesC,
 	storageAttachmentsC,
 	storageConstraintsC,
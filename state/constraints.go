@@ -27,6 +27,10 @@ type constraintsDoc struct {
 	Container    *instance.ContainerType
 	Tags         *[]string `bson:",omitempty"`
 	Networks     *[]string `bson:",omitempty"`
+	Zones        *[]string `bson:",omitempty"`
+	Spaces       *[]string `bson:",omitempty"`
+	Preemptible  *bool     `bson:",omitempty"`
+	SpotPrice    *float64  `bson:",omitempty"`
 }
 
 func (doc constraintsDoc) value() constraints.Value {
@@ -40,6 +44,10 @@ func (doc constraintsDoc) value() constraints.Value {
 		Container:    doc.Container,
 		Tags:         doc.Tags,
 		Networks:     doc.Networks,
+		Zones:        doc.Zones,
+		Spaces:       doc.Spaces,
+		Preemptible:  doc.Preemptible,
+		SpotPrice:    doc.SpotPrice,
 	}
 }
 
@@ -55,6 +63,10 @@ func newConstraintsDoc(st *State, cons constraints.Value) constraintsDoc {
 		Container:    cons.Container,
 		Tags:         cons.Tags,
 		Networks:     cons.Networks,
+		Zones:        cons.Zones,
+		Spaces:       cons.Spaces,
+		Preemptible:  cons.Preemptible,
+		SpotPrice:    cons.SpotPrice,
 	}
 }
 
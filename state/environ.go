@@ -81,6 +81,26 @@ func (st *State) GetEnvironment(tag names.EnvironTag) (*Environment, error) {
 	return env, nil
 }
 
+// EnvironmentByName returns the environment with the given name. It
+// returns an error satisfying errors.IsNotFound if there is none, and
+// an error if environments of several owners share the name.
+func (st *State) EnvironmentByName(name string) (*Environment, error) {
+	environments, closer := st.getCollection(environmentsC)
+	defer closer()
+
+	var docs []environmentDoc
+	if err := environments.Find(bson.D{{"name", name}}).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get environment %q", name)
+	}
+	switch len(docs) {
+	case 0:
+		return nil, errors.NotFoundf("environment %q", name)
+	case 1:
+		return &Environment{st: st, doc: docs[0]}, nil
+	}
+	return nil, errors.Errorf("environment name %q is ambiguous", name)
+}
+
 // NewEnvironment creates a new environment with its own UUID and
 // prepares it for use. Environment and State instances for the new
 // environment are returned.
@@ -36,6 +36,7 @@ const (
 
 var (
 	ToolstorageNewStorage  = &toolstorageNewStorage
+	CharmMirrorNewStorage  = &charmMirrorNewStorage
 	ImageStorageNewStorage = &imageStorageNewStorage
 	MachineIdLessThan      = machineIdLessThan
 	StateServerAvailable   = &stateServerAvailable
@@ -40,49 +40,69 @@ type LeasePersistor struct {
 	getCollection  func(string) (_ stateCollection, closer func())
 }
 
-// WriteToken writes the given token to the data store with the given
-// ID.
-func (p *LeasePersistor) WriteToken(id string, tok lease.Token) error {
+// WriteToken writes the given token to the data store, replacing the
+// previous token for its namespace. A nil previous token indicates that
+// no token is expected to be stored. Every state server shares the data
+// store, so the write is made only if the stored token still matches the
+// previous one; if it does not, lease.LeaseChangedErr is returned.
+func (p *LeasePersistor) WriteToken(prev *lease.Token, tok lease.Token) error {
 
 	entity := leaseEntity{time.Now(), tok}
 
-	// Write's should always overwrite anything that's there. The
-	// business-logic of managing leases is handled elsewhere.
-	ops := []txn.Op{
-		// First remove anything that's there.
-		{
+	var op txn.Op
+	if prev == nil {
+		op = txn.Op{
 			C:      p.collectionName,
-			Id:     id,
-			Remove: true,
-		},
-		// Then insert the token.
-		{
+			Id:     tok.Namespace,
 			Assert: txn.DocMissing,
-			C:      p.collectionName,
-			Id:     id,
 			Insert: entity,
-		},
+		}
+	} else {
+		op = txn.Op{
+			C:      p.collectionName,
+			Id:     tok.Namespace,
+			Assert: tokenAssert(*prev),
+			Update: bson.D{{"$set", entity}},
+		}
 	}
 
-	if err := p.runTransaction(ops); err != nil {
+	if err := p.runTransaction([]txn.Op{op}); err == txn.ErrAborted {
+		return lease.LeaseChangedErr
+	} else if err != nil {
 		return errors.Annotatef(err, `could not add token "%s" to data-store`, tok.Id)
 	}
 
 	return nil
 }
 
-// RemoveToken removes the lease token with the given ID from the data
-// store.
-func (p *LeasePersistor) RemoveToken(id string) error {
-
-	ops := []txn.Op{{C: p.collectionName, Id: id, Remove: true}}
-	if err := p.runTransaction(ops); err != nil {
-		return errors.Annotatef(err, `could not remove token "%s"`, id)
+// RemoveToken removes the given lease token from the data store. If the
+// stored token no longer matches it, lease.LeaseChangedErr is returned.
+func (p *LeasePersistor) RemoveToken(tok lease.Token) error {
+
+	ops := []txn.Op{{
+		C:      p.collectionName,
+		Id:     tok.Namespace,
+		Assert: tokenAssert(tok),
+		Remove: true,
+	}}
+	if err := p.runTransaction(ops); err == txn.ErrAborted {
+		return lease.LeaseChangedErr
+	} else if err != nil {
+		return errors.Annotatef(err, `could not remove token "%s"`, tok.Id)
 	}
 
 	return nil
 }
 
+// tokenAssert returns an assertion that the stored token matches the
+// given one.
+func tokenAssert(tok lease.Token) bson.D {
+	return bson.D{
+		{"token.id", tok.Id},
+		{"token.expiration", tok.Expiration},
+	}
+}
+
 // PersistedTokens retrieves all tokens currently persisted.
 func (p *LeasePersistor) PersistedTokens() (tokens []lease.Token, _ error) {
 
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	gc "gopkg.in/check.v1"
+	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/mgo.v2/txn"
 
 	"github.com/juju/juju/lease"
@@ -42,47 +43,105 @@ func (s *leaseSuite) TestWriteToken(c *gc.C) {
 	tok := lease.Token{testNamespace, testId, time.Now().Add(testDuration)}
 
 	stubRunTransaction := func(ops []txn.Op) error {
-		c.Assert(ops, gc.HasLen, 2)
+		c.Assert(ops, gc.HasLen, 1)
 
-		// First delete.
+		c.Check(ops[0].Assert, gc.Equals, txn.DocMissing)
 		c.Check(ops[0].C, gc.Equals, testCollectionName)
-		c.Check(ops[0].Remove, gc.Equals, true)
-		c.Check(ops[0].Id, gc.Equals, testId)
+		c.Check(ops[0].Insert.(leaseEntity).Token, gc.DeepEquals, tok)
+		c.Check(ops[0].Id, gc.Equals, testNamespace)
+
+		return nil
+	}
+
+	persistor := NewLeasePersistor(testCollectionName, stubRunTransaction, stubGetCollection)
+
+	err := persistor.WriteToken(nil, tok)
+
+	c.Assert(err, gc.IsNil)
+}
 
-		// Then insert.
-		c.Check(ops[1].Assert, gc.Equals, txn.DocMissing)
-		c.Check(ops[1].C, gc.Equals, testCollectionName)
-		c.Check(ops[1].Insert.(leaseEntity).Token, gc.DeepEquals, tok)
-		c.Check(ops[1].Id, gc.Equals, testId)
+func (s *leaseSuite) TestWriteTokenReplacesPrevious(c *gc.C) {
+
+	prev := lease.Token{testNamespace, "other-unit/1", time.Now()}
+	tok := lease.Token{testNamespace, testId, time.Now().Add(testDuration)}
+
+	stubRunTransaction := func(ops []txn.Op) error {
+		c.Assert(ops, gc.HasLen, 1)
+
+		c.Check(ops[0].C, gc.Equals, testCollectionName)
+		c.Check(ops[0].Id, gc.Equals, testNamespace)
+		c.Check(ops[0].Assert, gc.DeepEquals, bson.D{
+			{"token.id", prev.Id},
+			{"token.expiration", prev.Expiration},
+		})
+		update := ops[0].Update.(bson.D)
+		c.Assert(update, gc.HasLen, 1)
+		c.Check(update[0].Name, gc.Equals, "$set")
+		c.Check(update[0].Value.(leaseEntity).Token, gc.DeepEquals, tok)
 
 		return nil
 	}
 
 	persistor := NewLeasePersistor(testCollectionName, stubRunTransaction, stubGetCollection)
 
-	err := persistor.WriteToken(testId, tok)
+	err := persistor.WriteToken(&prev, tok)
 
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *leaseSuite) TestWriteTokenChanged(c *gc.C) {
+
+	tok := lease.Token{testNamespace, testId, time.Now().Add(testDuration)}
+
+	stubRunTransaction := func(ops []txn.Op) error {
+		return txn.ErrAborted
+	}
+
+	persistor := NewLeasePersistor(testCollectionName, stubRunTransaction, stubGetCollection)
+
+	err := persistor.WriteToken(nil, tok)
+
+	c.Assert(err, gc.Equals, lease.LeaseChangedErr)
+}
+
 func (s *leaseSuite) TestRemoveToken(c *gc.C) {
 
+	tok := lease.Token{testNamespace, testId, time.Now().Add(testDuration)}
+
 	stubRunTransaction := func(ops []txn.Op) error {
 		c.Assert(ops, gc.HasLen, 1)
 
 		c.Check(ops[0].C, gc.Equals, testCollectionName)
 		c.Check(ops[0].Remove, gc.Equals, true)
-		c.Check(ops[0].Id, gc.Equals, testId)
+		c.Check(ops[0].Id, gc.Equals, testNamespace)
+		c.Check(ops[0].Assert, gc.DeepEquals, bson.D{
+			{"token.id", tok.Id},
+			{"token.expiration", tok.Expiration},
+		})
 
 		return nil
 	}
 
 	persistor := NewLeasePersistor(testCollectionName, stubRunTransaction, stubGetCollection)
-	err := persistor.RemoveToken(testId)
+	err := persistor.RemoveToken(tok)
 
 	c.Assert(err, gc.IsNil)
 }
 
+func (s *leaseSuite) TestRemoveTokenChanged(c *gc.C) {
+
+	tok := lease.Token{testNamespace, testId, time.Now().Add(testDuration)}
+
+	stubRunTransaction := func(ops []txn.Op) error {
+		return txn.ErrAborted
+	}
+
+	persistor := NewLeasePersistor(testCollectionName, stubRunTransaction, stubGetCollection)
+	err := persistor.RemoveToken(tok)
+
+	c.Assert(err, gc.Equals, lease.LeaseChangedErr)
+}
+
 func (s *leaseSuite) TestPersistedTokens(c *gc.C) {
 
 	closerCallCount := 0
@@ -1200,6 +1200,53 @@ func (s *MachineSuite) TestWatchMachine(c *gc.C) {
 	testing.NewNotifyWatcherC(c, s.State, w).AssertOneChange()
 }
 
+func (s *MachineSuite) TestWatchContainerPorts(c *gc.C) {
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, s.machine.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	svc := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	hostUnit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = hostUnit.AssignToMachine(s.machine)
+	c.Assert(err, jc.ErrorIsNil)
+	containerUnit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = containerUnit.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := s.machine.WatchContainerPorts()
+	defer testing.AssertStop(c, w)
+
+	// Initial event.
+	wc := testing.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	// Opening ports on the container triggers an event.
+	err = containerUnit.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Opening ports on the host does not.
+	err = hostUnit.OpenPort("tcp", 8080)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	// Changing the container's addresses triggers an event.
+	err = container.SetMachineAddresses(network.NewAddress("10.0.3.5", network.ScopeUnknown))
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	// Closing ports on the container triggers an event.
+	err = containerUnit.ClosePort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+
+	testing.AssertStop(c, w)
+	wc.AssertClosed()
+}
+
 func (s *MachineSuite) TestWatchDiesOnStateClose(c *gc.C) {
 	// This test is testing logic in watcher.entityWatcher, which
 	// is also used by:
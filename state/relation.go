@@ -6,9 +6,11 @@ package state
 import (
 	stderrors "errors"
 	"fmt"
+	"regexp"
 	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -147,7 +149,7 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 		return nil, false, errAlreadyDying
 	}
 	if r.doc.UnitCount == 0 {
-		removeOps, err := r.removeOps(ignoreService, nil)
+		removeOps, err := r.removeOps(ignoreService, "")
 		if err != nil {
 			return nil, false, err
 		}
@@ -163,16 +165,17 @@ func (r *Relation) destroyOps(ignoreService string) (ops []txn.Op, isRemove bool
 
 // removeOps returns the operations necessary to remove the relation. If
 // ignoreService is not empty, no operations affecting that service will be
-// included; if departingUnit is not nil, this implies that the relation's
+// included; if departingService is not empty, the last unit of that
+// service is leaving the relation, which implies that the relation's
 // services may be Dying and otherwise unreferenced, and may thus require
 // removal themselves.
-func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.Op, error) {
+func (r *Relation) removeOps(ignoreService, departingService string) ([]txn.Op, error) {
 	relOp := txn.Op{
 		C:      relationsC,
 		Id:     r.doc.DocID,
 		Remove: true,
 	}
-	if departingUnit != nil {
+	if departingService != "" {
 		relOp.Assert = bson.D{{"life", Dying}, {"unitcount", 1}}
 	} else {
 		relOp.Assert = bson.D{{"life", Alive}, {"unitcount", 0}}
@@ -182,14 +185,21 @@ func (r *Relation) removeOps(ignoreService string, departingUnit *Unit) ([]txn.O
 		if ep.ServiceName == ignoreService {
 			continue
 		}
+		remoteOps, isRemote, err := r.st.removeRemoteServiceRelationOps(ep.ServiceName)
+		if err != nil {
+			return nil, err
+		} else if isRemote {
+			ops = append(ops, remoteOps...)
+			continue
+		}
 		var asserts bson.D
 		hasRelation := bson.D{{"relationcount", bson.D{{"$gt", 0}}}}
-		if departingUnit == nil {
+		if departingService == "" {
 			// We're constructing a destroy operation, either of the relation
 			// or one of its services, and can therefore be assured that both
 			// services are Alive.
 			asserts = append(hasRelation, isAliveDoc...)
-		} else if ep.ServiceName == departingUnit.ServiceName() {
+		} else if ep.ServiceName == departingService {
 			// This service must have at least one unit -- the one that's
 			// departing the relation -- so it cannot be ready for removal.
 			cannotDieYet := bson.D{{"unitcount", bson.D{{"$gt", 0}}}}
@@ -294,3 +304,62 @@ func (r *Relation) Unit(u *Unit) (*RelationUnit, error) {
 		scope:    strings.Join(scope, "#"),
 	}, nil
 }
+
+// RelationScopeUnit describes a unit in scope in a relation.
+type RelationScopeUnit struct {
+	// Unit is the name of the unit.
+	Unit string
+
+	// Departing is true if the unit is preparing to leave the relation.
+	Departing bool
+
+	// SettingsChanged is the time the unit's settings in the relation
+	// were last changed; it is zero if this is not known.
+	SettingsChanged time.Time
+
+	// Settings holds the unit's settings in the relation.
+	Settings map[string]interface{}
+}
+
+// ScopeUnits returns the units in scope in the relation, with their
+// settings, sorted by unit name.
+func (r *Relation) ScopeUnits() ([]RelationScopeUnit, error) {
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	prefix := fmt.Sprintf("r#%d#", r.doc.Id)
+	sel := bson.D{{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}}}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units in relation %q", r)
+	}
+	units := make([]RelationScopeUnit, len(docs))
+	for i, doc := range docs {
+		settings, err := readSettings(r.st, doc.Key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read settings of unit %q in relation %q", doc.unitName(), r)
+		}
+		units[i] = RelationScopeUnit{
+			Unit:            doc.unitName(),
+			Departing:       doc.Departing,
+			SettingsChanged: doc.SettingsChanged,
+			Settings:        settings.Map(),
+		}
+	}
+	sort.Sort(scopeUnitsByName(units))
+	return units, nil
+}
+
+// scopeUnitsByName sorts the units in a relation by service name and
+// unit number.
+type scopeUnitsByName []RelationScopeUnit
+
+func (s scopeUnitsByName) Len() int      { return len(s) }
+func (s scopeUnitsByName) Swap(i, j int) { s[i], s[j] = s[j], s[i] }
+func (s scopeUnitsByName) Less(i, j int) bool {
+	si, sj := strings.Split(s[i].Unit, "/")[0], strings.Split(s[j].Unit, "/")[0]
+	if si != sj {
+		return si < sj
+	}
+	return unitNumber(s[i].Unit) < unitNumber(s[j].Unit)
+}
@@ -315,3 +315,54 @@ func assertOneRelation(c *gc.C, srv *state.Service, relId int, endpoints ...stat
 	c.Assert(eps, gc.DeepEquals, []state.Endpoint{expectEp})
 	return rel
 }
+
+func (s *RelationSuite) TestScopeUnits(c *gc.C) {
+	wordpress := s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+	mysql := s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+	eps, err := s.State.InferEndpoints("wordpress", "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddRelation(eps...)
+	c.Assert(err, jc.ErrorIsNil)
+	units, err := rel.ScopeUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 0)
+
+	enterScope := func(svc *state.Service, settings map[string]interface{}) *state.RelationUnit {
+		unit, err := svc.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		ru, err := rel.Unit(unit)
+		c.Assert(err, jc.ErrorIsNil)
+		err = ru.EnterScope(settings)
+		c.Assert(err, jc.ErrorIsNil)
+		return ru
+	}
+	mysqlRU := enterScope(mysql, map[string]interface{}{"password": "old"})
+	wordpressRU := enterScope(wordpress, nil)
+	err = wordpressRU.PrepareLeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := mysqlRU.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("password", "new")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+
+	units, err = rel.ScopeUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 2)
+	c.Assert(units[0].Unit, gc.Equals, "mysql/0")
+	c.Assert(units[0].Departing, jc.IsFalse)
+	c.Assert(units[0].Settings, jc.DeepEquals, map[string]interface{}{"password": "new"})
+	c.Assert(units[0].SettingsChanged.IsZero(), jc.IsFalse)
+	c.Assert(units[1].Unit, gc.Equals, "wordpress/0")
+	c.Assert(units[1].Departing, jc.IsTrue)
+	c.Assert(units[1].Settings, gc.HasLen, 0)
+
+	// Settings outlive the unit's presence in scope.
+	err = wordpressRU.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = wordpressRU.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	units, err = rel.ScopeUnits()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(units, gc.HasLen, 1)
+}
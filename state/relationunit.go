@@ -7,6 +7,7 @@ import (
 	stderrors "errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/names"
@@ -39,8 +40,23 @@ func (ru *RelationUnit) Endpoint() Endpoint {
 }
 
 // PrivateAddress returns the private address of the unit and whether it is valid.
+// If the unit's service binds the relation endpoint to a space, the address is
+// chosen from the unit machine's addresses in that space.
 func (ru *RelationUnit) PrivateAddress() (string, bool) {
-	return ru.unit.PrivateAddress()
+	svc, err := ru.unit.Service()
+	if err != nil {
+		return ru.unit.PrivateAddress()
+	}
+	space := svc.doc.EndpointBindings[ru.endpoint.Name]
+	if space == "" {
+		return ru.unit.PrivateAddress()
+	}
+	address, err := addressInSpace(ru.st, space, ru.unit.addressesOfMachine())
+	if err != nil {
+		logger.Warningf("cannot use space %q for unit %q endpoint %q: %v", space, ru.unit, ru.endpoint.Name, err)
+		return ru.unit.PrivateAddress()
+	}
+	return address.Value, true
 }
 
 // ErrCannotEnterScope indicates that a relation unit failed to enter its scope
@@ -129,9 +145,10 @@ func (ru *RelationUnit) EnterScope(settings map[string]interface{}) error {
 		Id:     rsDocID,
 		Assert: txn.DocMissing,
 		Insert: relationScopeDoc{
-			DocID:   rsDocID,
-			Key:     ruKey,
-			EnvUUID: ru.st.EnvironUUID(),
+			DocID:           rsDocID,
+			Key:             ruKey,
+			EnvUUID:         ru.st.EnvironUUID(),
+			SettingsChanged: nowToTheSecond(),
 		},
 	})
 
@@ -335,7 +352,7 @@ func (ru *RelationUnit) LeaveScope() error {
 				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
 			})
 		} else {
-			relOps, err := ru.relation.removeOps("", ru.unit)
+			relOps, err := ru.relation.removeOps("", ru.unit.ServiceName())
 			if err != nil {
 				return nil, err
 			}
@@ -387,13 +404,33 @@ func (ru *RelationUnit) WatchScope() *RelationScopeWatcher {
 }
 
 // Settings returns a Settings which allows access to the unit's settings
-// within the relation.
+// within the relation. Writing changes to them records the time of the
+// change while the unit is in scope.
 func (ru *RelationUnit) Settings() (*Settings, error) {
 	key, err := ru.key(ru.unit.Name())
 	if err != nil {
 		return nil, err
 	}
-	return readSettings(ru.st, key)
+	s, err := readSettings(ru.st, key)
+	if err != nil {
+		return nil, err
+	}
+	s.writeOps = func() []txn.Op {
+		return []txn.Op{settingsChangedOp(ru.st, key)}
+	}
+	return s, nil
+}
+
+// settingsChangedOp returns the operation that records a change to the
+// settings of the unit with the given scope key, if it is in scope.
+func settingsChangedOp(st *State, key string) txn.Op {
+	// No assertion is made: updates to missing documents are skipped,
+	// and settings outlive the scope documents of their units.
+	return txn.Op{
+		C:      relationScopesC,
+		Id:     st.docID(key),
+		Update: bson.D{{"$set", bson.D{{"settingschanged", nowToTheSecond()}}}},
+	}
 }
 
 // ReadSettings returns a map holding the settings of the unit with the
@@ -436,10 +473,11 @@ func (ru *RelationUnit) key(uname string) (string, error) {
 // relationScopeDoc represents a unit which is in a relation scope.
 // The relation, container, role, and unit are all encoded in the key.
 type relationScopeDoc struct {
-	DocID     string `bson:"_id"`
-	Key       string `bson:"key"`
-	EnvUUID   string `bson:"env-uuid"`
-	Departing bool
+	DocID           string `bson:"_id"`
+	Key             string `bson:"key"`
+	EnvUUID         string `bson:"env-uuid"`
+	Departing       bool
+	SettingsChanged time.Time `bson:"settingschanged,omitempty"`
 }
 
 func (d *relationScopeDoc) unitName() string {
@@ -180,6 +180,42 @@ func (s *RelationUnitSuite) TestProReqSettings(c *gc.C) {
 	}
 }
 
+func (s *RelationUnitSuite) TestPrivateAddressBoundToSpace(c *gc.C) {
+	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeGlobal)
+	machine, err := s.State.AddMachine("quantal", state.JobHostUnits)
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.pu0.AssignToMachine(machine)
+	c.Assert(err, jc.ErrorIsNil)
+	err = machine.SetAddresses(
+		network.NewAddress("10.0.0.10", network.ScopeCloudLocal),
+		network.NewAddress("192.168.1.10", network.ScopeCloudLocal),
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Without a binding, the unit's private address is used.
+	address, ok := prr.pru0.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.0.0.10")
+
+	_, err = s.State.AddSubnet(state.SubnetInfo{CIDR: "192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", []string{"192.168.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = prr.psvc.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	address, ok = prr.pru0.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "192.168.1.10")
+
+	// The unit's private address is used if it has none in the space.
+	err = machine.SetAddresses(network.NewAddress("10.0.0.10", network.ScopeCloudLocal))
+	c.Assert(err, jc.ErrorIsNil)
+	address, ok = prr.pru0.PrivateAddress()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(address, gc.Equals, "10.0.0.10")
+}
+
 func (s *RelationUnitSuite) TestContainerSettings(c *gc.C) {
 	prr := NewProReqRelation(c, &s.ConnSuite, charm.ScopeContainer)
 	rus := RUs{prr.pru0, prr.pru1, prr.rru0, prr.rru1}
@@ -0,0 +1,319 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// AddCrossEnvironmentRelation relates a service of this environment to
+// a service offered by the offering environment. Each endpoint is given
+// as "<service>[:<relation>]". The offered service becomes a remote
+// service of this environment, and the local service a remote service
+// of the offering one; a relation with the same key is added to both
+// environments, and the remote relations worker of each environment
+// mirrors the units of the other into it. The relation added to this
+// environment is returned.
+func (st *State) AddCrossEnvironmentRelation(localEndpoint string, offering *State, offeredEndpoint string) (_ *Relation, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot relate %q to %q of environment %q", localEndpoint, offeredEndpoint, offering.EnvironUUID())
+	if offering.EnvironUUID() == st.EnvironUUID() {
+		return nil, errors.Errorf("services belong to the same environment")
+	}
+	offeredService, offeredRelation := splitEndpointName(offeredEndpoint)
+	offer, err := offering.ServiceOffer(offeredService)
+	if errors.IsNotFound(err) {
+		return nil, errors.Errorf("service %q is not offered", offeredService)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	offered, err := offer.Endpoints()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	localEps, err := st.endpoints(localEndpoint, notPeer)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var candidates [][]Endpoint
+	for _, localEp := range localEps {
+		if localEp.Scope != charm.ScopeGlobal {
+			continue
+		}
+		for _, offeredEp := range offered {
+			if offeredRelation != "" && offeredEp.Name != offeredRelation {
+				continue
+			}
+			if localEp.CanRelateTo(offeredEp) {
+				candidates = append(candidates, []Endpoint{localEp, offeredEp})
+			}
+		}
+	}
+	switch len(candidates) {
+	case 0:
+		return nil, errors.Errorf("no relations found")
+	case 1:
+	default:
+		keys := []string{}
+		for _, cand := range candidates {
+			keys = append(keys, fmt.Sprintf("%q", relationKey(cand)))
+		}
+		return nil, errors.Errorf("ambiguous relation: %q could refer to %s",
+			localEndpoint+" "+offeredEndpoint, strings.Join(keys, "; "))
+	}
+	localEp, offeredEp := candidates[0][0], candidates[0][1]
+
+	// Each step is a separate transaction, possibly in the other
+	// environment, so those completed are undone if a later one fails.
+	var undo []func() error
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(undo) - 1; i >= 0; i-- {
+			if undoErr := undo[i](); undoErr != nil {
+				logger.Errorf("cannot undo relating %q to %q: %v", localEndpoint, offeredEndpoint, undoErr)
+			}
+		}
+	}()
+	if _, err := st.AddRemoteService(offeredEp.ServiceName, offering.EnvironTag(), []charm.Relation{offeredEp.Relation}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	undo = append(undo, func() error {
+		return st.removeUnusedRemoteService(offeredEp.ServiceName)
+	})
+	if _, err := offering.AddRemoteService(localEp.ServiceName, st.EnvironTag(), []charm.Relation{localEp.Relation}); err != nil {
+		return nil, errors.Trace(err)
+	}
+	undo = append(undo, func() error {
+		return offering.removeUnusedRemoteService(localEp.ServiceName)
+	})
+	rel, err := st.AddRelation(localEp, offeredEp)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// The relation has no units yet, so destroying it removes it,
+	// along with the remote service if it has no other relations.
+	undo = append(undo, rel.Destroy)
+	if _, err := offering.AddRelation(offeredEp, localEp); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return rel, nil
+}
+
+// splitEndpointName splits an endpoint name of the form
+// "<service>[:<relation>]" into its service and relation names.
+func splitEndpointName(name string) (serviceName, relationName string) {
+	if i := strings.Index(name, ":"); i != -1 {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// remoteUnitKey returns the scope key of the named unit of a remote
+// service within the relation.
+func (r *Relation) remoteUnitKey(unitName string) (string, error) {
+	if !names.IsValidUnit(unitName) {
+		return "", errors.Errorf("%q is not a valid unit name", unitName)
+	}
+	serviceName, err := names.UnitService(unitName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	if _, err := r.st.RemoteService(serviceName); err != nil {
+		return "", errors.Trace(err)
+	}
+	return fmt.Sprintf("r#%d#%s#%s", r.doc.Id, ep.Role, unitName), nil
+}
+
+// UnitSettings returns the settings within the relation of the units of
+// the named service that have joined it and are not departing, keyed by
+// unit name.
+func (r *Relation) UnitSettings(serviceName string) (map[string]map[string]interface{}, error) {
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	prefix := fmt.Sprintf("r#%d#%s#%s/", r.doc.Id, ep.Role, serviceName)
+	sel := bson.D{
+		{"key", bson.D{{"$regex", "^" + regexp.QuoteMeta(prefix)}}},
+		{"departing", bson.D{{"$ne", true}}},
+	}
+	var docs []relationScopeDoc
+	if err := relationScopes.Find(sel).All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get units of service %q in relation %q", serviceName, r)
+	}
+	result := make(map[string]map[string]interface{})
+	for _, doc := range docs {
+		settings, err := readSettings(r.st, doc.Key)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot read settings of unit %q in relation %q", doc.unitName(), r)
+		}
+		result[doc.unitName()] = settings.Map()
+	}
+	return result, nil
+}
+
+// SetRemoteUnitSettings ensures that the named unit of a remote service
+// is in scope in the relation with the supplied settings. A unit enters
+// scope only while the relation is alive; if it is not, the error
+// returned is ErrCannotEnterScope.
+func (r *Relation) SetRemoteUnitSettings(unitName string, settings map[string]interface{}) error {
+	key, err := r.remoteUnitKey(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := r.Refresh(); errors.IsNotFound(err) {
+				return nil, ErrCannotEnterScope
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		count, err := relationScopes.FindId(key).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if count != 0 {
+			// The unit is already in scope; just update its settings.
+			current, err := readSettings(r.st, key)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if reflect.DeepEqual(current.Map(), settings) {
+				return nil, jujutxn.ErrNoOperations
+			}
+			op, _, err := replaceSettingsOp(r.st, key, settings)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return []txn.Op{op, settingsChangedOp(r.st, key)}, nil
+		}
+		if r.doc.Life != Alive {
+			return nil, ErrCannotEnterScope
+		}
+		ops := []txn.Op{{
+			C:      relationsC,
+			Id:     r.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$inc", bson.D{{"unitcount", 1}}}},
+		}}
+		// As for units of this environment, the settings doc must exist
+		// before the scope doc does.
+		if _, err := readSettings(r.st, key); errors.IsNotFound(err) {
+			ops = append(ops, createSettingsOp(r.st, key, settings))
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		} else {
+			op, _, err := replaceSettingsOp(r.st, key, settings)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, op)
+		}
+		rsDocID := r.st.docID(key)
+		return append(ops, txn.Op{
+			C:      relationScopesC,
+			Id:     rsDocID,
+			Assert: txn.DocMissing,
+			Insert: relationScopeDoc{
+				DocID:           rsDocID,
+				Key:             key,
+				EnvUUID:         r.st.EnvironUUID(),
+				SettingsChanged: nowToTheSecond(),
+			},
+		}), nil
+	}
+	if err := r.st.run(buildTxn); err == ErrCannotEnterScope {
+		return err
+	} else if err != nil {
+		return errors.Annotatef(err, "cannot set settings of unit %q in relation %q", unitName, r)
+	}
+	return nil
+}
+
+// RemoveRemoteUnit ensures that the named unit of a remote service is
+// no longer in scope in the relation. As when a unit of this
+// environment leaves scope, a dying relation is removed along with the
+// last unit in it.
+func (r *Relation) RemoveRemoteUnit(unitName string) error {
+	key, err := r.remoteUnitKey(unitName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	relationScopes, closer := r.st.getCollection(relationScopesC)
+	defer closer()
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := r.Refresh(); errors.IsNotFound(err) {
+				return nil, jujutxn.ErrNoOperations
+			} else if err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		count, err := relationScopes.FindId(key).Count()
+		if err != nil {
+			return nil, errors.Trace(err)
+		} else if count == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		ops := []txn.Op{{
+			C:      relationScopesC,
+			Id:     r.st.docID(key),
+			Assert: txn.DocExists,
+			Remove: true,
+		}}
+		if r.doc.Life == Alive {
+			ops = append(ops, txn.Op{
+				C:      relationsC,
+				Id:     r.doc.DocID,
+				Assert: bson.D{{"life", Alive}},
+				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
+			})
+		} else if r.doc.UnitCount > 1 {
+			ops = append(ops, txn.Op{
+				C:      relationsC,
+				Id:     r.doc.DocID,
+				Assert: bson.D{{"unitcount", bson.D{{"$gt", 1}}}},
+				Update: bson.D{{"$inc", bson.D{{"unitcount", -1}}}},
+			})
+		} else {
+			serviceName, err := names.UnitService(unitName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			relOps, err := r.removeOps("", serviceName)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			ops = append(ops, relOps...)
+		}
+		return ops, nil
+	}
+	if err := r.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot remove unit %q from relation %q", unitName, r)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type RemoteRelationSuite struct {
+	ConnSuite
+	offering  *state.State
+	mysql     *state.Service
+	wordpress *state.Service
+}
+
+var _ = gc.Suite(&RemoteRelationSuite{})
+
+func (s *RemoteRelationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.offering = s.factory.MakeEnvironment(c, nil)
+	s.AddCleanup(func(*gc.C) { s.offering.Close() })
+	s.mysql = state.AddTestingService(c, s.offering, "mysql", state.AddTestingCharm(c, s.offering, "mysql"), s.Owner)
+	s.wordpress = s.AddTestingService(c, "wordpress", s.AddTestingCharm(c, "wordpress"))
+}
+
+func (s *RemoteRelationSuite) addRelation(c *gc.C) *state.Relation {
+	err := s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	rel, err := s.State.AddCrossEnvironmentRelation("wordpress", s.offering, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	return rel
+}
+
+func (s *RemoteRelationSuite) TestOfferService(c *gc.C) {
+	_, err := s.offering.ServiceOffer("mysql")
+	c.Assert(err, gc.ErrorMatches, `offer of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	err = s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	offer, err := s.offering.ServiceOffer("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offer.Service(), gc.Equals, "mysql")
+	c.Assert(offer.EndpointNames(), jc.DeepEquals, []string{"server"})
+	eps, err := offer.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, gc.HasLen, 1)
+	c.Assert(eps[0].Interface, gc.Equals, "mysql")
+
+	offers, err := s.offering.ServiceOffers()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(offers, gc.HasLen, 1)
+
+	err = s.offering.OfferService("mysql", []string{"juju-info"})
+	c.Assert(err, gc.ErrorMatches, `cannot offer service "mysql": endpoint "juju-info" cannot be offered`)
+	err = s.offering.OfferService("mysql", []string{"foo"})
+	c.Assert(err, gc.ErrorMatches, `cannot offer service "mysql": .*service "mysql" has no "foo" relation`)
+}
+
+func (s *RemoteRelationSuite) TestOfferRemovedWithService(c *gc.C) {
+	err := s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.offering.ServiceOffer("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteRelationSuite) TestAddCrossEnvironmentRelation(c *gc.C) {
+	rel := s.addRelation(c)
+	c.Assert(rel.String(), gc.Equals, "wordpress:db mysql:server")
+
+	remote, err := s.State.RemoteService("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.SourceEnvironTag(), gc.Equals, s.offering.EnvironTag())
+	eps, err := remote.Endpoints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(eps, jc.DeepEquals, []state.Endpoint{{
+		ServiceName: "mysql",
+		Relation: charm.Relation{
+			Name:      "server",
+			Role:      charm.RoleProvider,
+			Interface: "mysql",
+			Scope:     charm.ScopeGlobal,
+		},
+	}})
+	rels, err := remote.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 1)
+
+	// The offering environment has the counterpart relation, with the
+	// consuming service as its remote service.
+	counterpart, err := s.offering.KeyRelation(rel.String())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(counterpart.Life(), gc.Equals, state.Alive)
+	remote, err = s.offering.RemoteService("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remote.SourceEnvironTag(), gc.Equals, s.State.EnvironTag())
+
+	// The name of a remote service cannot be taken by a local one.
+	_, err = s.State.AddService("mysql", s.Owner.String(), s.AddTestingCharm(c, "mysql"), nil, nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "mysql": service already exists`)
+}
+
+func (s *RemoteRelationSuite) TestAddCrossEnvironmentRelationNotOffered(c *gc.C) {
+	_, err := s.State.AddCrossEnvironmentRelation("wordpress", s.offering, "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot relate "wordpress" to "mysql" of environment ".*": service "mysql" is not offered`)
+
+	err = s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddCrossEnvironmentRelation("wordpress:url", s.offering, "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot relate "wordpress:url" to "mysql" of environment ".*": no relations found`)
+	_, err = s.State.AddCrossEnvironmentRelation("wordpress", s.State, "mysql")
+	c.Assert(err, gc.ErrorMatches, `.*: services belong to the same environment`)
+}
+
+func (s *RemoteRelationSuite) TestAddCrossEnvironmentRelationUndoesRemoteServices(c *gc.C) {
+	err := s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// The consuming service cannot become a remote service of the
+	// offering environment, which has a service of the same name.
+	state.AddTestingService(c, s.offering, "wordpress", state.AddTestingCharm(c, s.offering, "wordpress"), s.Owner)
+	_, err = s.State.AddCrossEnvironmentRelation("wordpress", s.offering, "mysql")
+	c.Assert(err, gc.ErrorMatches, `.*cannot add remote service "wordpress": service already exists`)
+
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteRelationSuite) TestAddCrossEnvironmentRelationUndoesRelation(c *gc.C) {
+	err := s.offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	// The offered service goes away before the offering environment's
+	// relation is added, after all other steps have completed.
+	defer state.SetBeforeHooks(c, s.offering, nil, func() {
+		err := s.mysql.Destroy()
+		c.Assert(err, jc.ErrorIsNil)
+	}).Check()
+	_, err = s.State.AddCrossEnvironmentRelation("wordpress", s.offering, "mysql")
+	c.Assert(err, gc.ErrorMatches, `cannot relate "wordpress" to "mysql" of environment ".*": .*`)
+
+	rels, err := s.wordpress.Relations()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(rels, gc.HasLen, 0)
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.offering.RemoteService("wordpress")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *RemoteRelationSuite) TestRemoteUnits(c *gc.C) {
+	rel := s.addRelation(c)
+
+	err := rel.SetRemoteUnitSettings("mysql/0", map[string]interface{}{"host": "db-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetRemoteUnitSettings("mysql/1", map[string]interface{}{"host": "db-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetRemoteUnitSettings("mysql/0", map[string]interface{}{"host": "db-0", "port": "3306"})
+	c.Assert(err, jc.ErrorIsNil)
+	settings, err := rel.UnitSettings("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(settings, jc.DeepEquals, map[string]map[string]interface{}{
+		"mysql/0": {"host": "db-0", "port": "3306"},
+		"mysql/1": {"host": "db-1"},
+	})
+
+	// Remote units are seen by units of this environment.
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	remoteSettings, err := ru.ReadSettings("mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(remoteSettings, jc.DeepEquals, map[string]interface{}{"host": "db-1"})
+
+	err = rel.SetRemoteUnitSettings("wordpress/0", nil)
+	c.Assert(err, gc.ErrorMatches, `remote service "wordpress" not found`)
+
+	// Removing the last remote unit of a dying relation removes it,
+	// along with the remote service.
+	err = rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.SetRemoteUnitSettings("mysql/2", nil)
+	c.Assert(err, gc.Equals, state.ErrCannotEnterScope)
+	err = rel.RemoveRemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.RemoveRemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.RemoveRemoteUnit("mysql/1")
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	err = s.wordpress.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RemoteRelationSuite) TestWatchUnits(c *gc.C) {
+	rel := s.addRelation(c)
+	w, err := rel.WatchUnits("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewRelationUnitsWatcherC(c, s.State, w)
+	wc.AssertChange(nil, nil)
+	wc.AssertNoChange()
+
+	err = rel.SetRemoteUnitSettings("mysql/0", map[string]interface{}{"host": "db-0"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange([]string{"mysql/0"}, nil)
+	wc.AssertNoChange()
+	err = rel.SetRemoteUnitSettings("mysql/0", map[string]interface{}{"host": "db-1"})
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange([]string{"mysql/0"}, nil)
+	wc.AssertNoChange()
+
+	// Units of the other service are not reported.
+	unit, err := s.wordpress.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := rel.Unit(unit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(nil)
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	err = rel.RemoveRemoteUnit("mysql/0")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(nil, []string{"mysql/0"})
+	wc.AssertNoChange()
+}
+
+func (s *RemoteRelationSuite) TestWatchRemoteServices(c *gc.C) {
+	w := s.State.WatchRemoteServices()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	rel := s.addRelation(c)
+	wc.AssertChange("mysql")
+	wc.AssertNoChange()
+
+	err := rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange("mysql")
+	wc.AssertNoChange()
+}
+
+func (s *RemoteRelationSuite) TestDestroyRelationRemovesRemoteService(c *gc.C) {
+	rel := s.addRelation(c)
+	err := rel.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = rel.Refresh()
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.RemoteService("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The counterpart is left for the offering environment to remove.
+	_, err = s.offering.RemoteService("wordpress")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,284 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/juju/errors"
+	"github.com/juju/names"
+	jujutxn "github.com/juju/txn"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// remoteServiceDoc represents a service of another environment that
+// services of this environment are related to.
+type remoteServiceDoc struct {
+	DocID         string           `bson:"_id"`
+	Name          string           `bson:"name"`
+	EnvUUID       string           `bson:"env-uuid"`
+	SourceEnvUUID string           `bson:"sourceenvuuid"`
+	Endpoints     []charm.Relation `bson:"endpoints"`
+	RelationCount int              `bson:"relationcount"`
+}
+
+// RemoteService represents a service of another environment on the
+// same state server that services of this environment are related to.
+// Its units take part in relations through the remote relations
+// worker, which mirrors them from the environment they belong to.
+type RemoteService struct {
+	st  *State
+	doc remoteServiceDoc
+}
+
+// Name returns the name of the service, in both environments.
+func (s *RemoteService) Name() string {
+	return s.doc.Name
+}
+
+// String returns the name of the service.
+func (s *RemoteService) String() string {
+	return s.doc.Name
+}
+
+// SourceEnvironTag returns the tag of the environment the service
+// belongs to.
+func (s *RemoteService) SourceEnvironTag() names.EnvironTag {
+	return names.NewEnvironTag(s.doc.SourceEnvUUID)
+}
+
+// Endpoints returns the endpoints of the service that services of this
+// environment may relate to.
+func (s *RemoteService) Endpoints() ([]Endpoint, error) {
+	eps := make([]Endpoint, len(s.doc.Endpoints))
+	for i, rel := range s.doc.Endpoints {
+		eps[i] = Endpoint{
+			ServiceName: s.doc.Name,
+			Relation:    rel,
+		}
+	}
+	sort.Sort(epSlice(eps))
+	return eps, nil
+}
+
+// Endpoint returns the relation endpoint with the supplied name, if it
+// exists.
+func (s *RemoteService) Endpoint(relationName string) (Endpoint, error) {
+	for _, rel := range s.doc.Endpoints {
+		if rel.Name == relationName {
+			return Endpoint{
+				ServiceName: s.doc.Name,
+				Relation:    rel,
+			}, nil
+		}
+	}
+	return Endpoint{}, fmt.Errorf("remote service %q has no %q relation", s, relationName)
+}
+
+// Relations returns the relations of the service.
+func (s *RemoteService) Relations() ([]*Relation, error) {
+	return serviceRelations(s.st, s.doc.Name)
+}
+
+// Refresh refreshes the contents of the RemoteService from the
+// underlying state. It returns an error that satisfies
+// errors.IsNotFound if the remote service has been removed.
+func (s *RemoteService) Refresh() error {
+	remoteServices, closer := s.st.getCollection(remoteServicesC)
+	defer closer()
+
+	err := remoteServices.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("remote service %q", s)
+	}
+	if err != nil {
+		return errors.Annotatef(err, "cannot refresh remote service %q", s)
+	}
+	return nil
+}
+
+// AddRemoteService records that services of this environment may relate
+// to the endpoints of the named service of the source environment. If
+// the remote service is already known, any endpoints not yet recorded
+// are added to it. A remote service is removed along with its last
+// relation.
+func (st *State) AddRemoteService(name string, sourceEnv names.EnvironTag, endpoints []charm.Relation) (_ *RemoteService, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add remote service %q", name)
+	if !names.IsValidService(name) {
+		return nil, errors.Errorf("invalid name")
+	}
+	if sourceEnv == st.EnvironTag() {
+		return nil, errors.Errorf("service belongs to this environment")
+	}
+	docID := st.docID(name)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.Service(name); err == nil {
+			return nil, errors.Errorf("service already exists")
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     docID,
+			Assert: txn.DocMissing,
+		}}
+		existing, err := st.RemoteService(name)
+		if errors.IsNotFound(err) {
+			return append(ops, txn.Op{
+				C:      remoteServicesC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &remoteServiceDoc{
+					DocID:         docID,
+					Name:          name,
+					EnvUUID:       st.EnvironUUID(),
+					SourceEnvUUID: sourceEnv.Id(),
+					Endpoints:     endpoints,
+				},
+			}), nil
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if existing.doc.SourceEnvUUID != sourceEnv.Id() {
+			return nil, errors.Errorf("service of environment %q already exists", existing.doc.SourceEnvUUID)
+		}
+		var added []charm.Relation
+		for _, rel := range endpoints {
+			if _, err := existing.Endpoint(rel.Name); err != nil {
+				added = append(added, rel)
+			}
+		}
+		if len(added) == 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return append(ops, txn.Op{
+			C:  remoteServicesC,
+			Id: docID,
+			Assert: bson.D{
+				{"sourceenvuuid", sourceEnv.Id()},
+				{"endpoints", existing.doc.Endpoints},
+			},
+			Update: bson.D{{"$push", bson.D{{"endpoints", bson.D{{"$each", added}}}}}},
+		}), nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return st.RemoteService(name)
+}
+
+// removeUnusedRemoteService removes the named remote service if it has
+// no relations. A remote service is otherwise removed along with its
+// last relation; this is for one recorded by AddRemoteService that
+// never gained any.
+func (st *State) removeUnusedRemoteService(name string) error {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		remote, err := st.RemoteService(name)
+		if errors.IsNotFound(err) {
+			return nil, jujutxn.ErrNoOperations
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if remote.doc.RelationCount != 0 {
+			return nil, jujutxn.ErrNoOperations
+		}
+		return []txn.Op{{
+			C:      remoteServicesC,
+			Id:     remote.doc.DocID,
+			Assert: bson.D{{"relationcount", 0}},
+			Remove: true,
+		}}, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot remove remote service %q", name)
+	}
+	return nil
+}
+
+// RemoteService returns the remote service with the given name. It
+// returns an error satisfying errors.IsNotFound if there is none.
+func (st *State) RemoteService(name string) (*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	s := &RemoteService{st: st}
+	err := remoteServices.FindId(name).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("remote service %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote service %q", name)
+	}
+	return s, nil
+}
+
+// AllRemoteServices returns all remote services of the environment.
+func (st *State) AllRemoteServices() ([]*RemoteService, error) {
+	remoteServices, closer := st.getCollection(remoteServicesC)
+	defer closer()
+
+	var docs []remoteServiceDoc
+	if err := remoteServices.Find(nil).Sort("name").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get remote services")
+	}
+	result := make([]*RemoteService, len(docs))
+	for i, doc := range docs {
+		result[i] = &RemoteService{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// addRemoteServiceRelationOp returns the operation that records a new
+// relation of the remote service of the endpoint.
+func (st *State) addRemoteServiceRelationOp(ep Endpoint) (txn.Op, error) {
+	remote, err := st.RemoteService(ep.ServiceName)
+	if errors.IsNotFound(err) {
+		return txn.Op{}, errors.Errorf("service %q does not exist", ep.ServiceName)
+	} else if err != nil {
+		return txn.Op{}, errors.Trace(err)
+	}
+	if ep.Scope != charm.ScopeGlobal {
+		return txn.Op{}, errors.Errorf("relations with remote service %q must be global", ep.ServiceName)
+	}
+	if remoteEp, err := remote.Endpoint(ep.Name); err != nil || remoteEp.Relation != ep.Relation {
+		return txn.Op{}, errors.Errorf("%q does not implement %q", ep.ServiceName, ep)
+	}
+	return txn.Op{
+		C:      remoteServicesC,
+		Id:     remote.doc.DocID,
+		Assert: txn.DocExists,
+		Update: bson.D{{"$inc", bson.D{{"relationcount", 1}}}},
+	}, nil
+}
+
+// removeRemoteServiceRelationOps returns the operations that record the
+// removal of a relation of the named service, if it is a remote
+// service, removing the remote service along with its last relation.
+// It returns false if the service is not a remote service.
+func (st *State) removeRemoteServiceRelationOps(name string) ([]txn.Op, bool, error) {
+	remote, err := st.RemoteService(name)
+	if errors.IsNotFound(err) {
+		return nil, false, nil
+	} else if err != nil {
+		return nil, false, errors.Trace(err)
+	}
+	if remote.doc.RelationCount <= 1 {
+		return []txn.Op{{
+			C:      remoteServicesC,
+			Id:     remote.doc.DocID,
+			Assert: bson.D{{"relationcount", 1}},
+			Remove: true,
+		}}, true, nil
+	}
+	return []txn.Op{{
+		C:      remoteServicesC,
+		Id:     remote.doc.DocID,
+		Assert: bson.D{{"relationcount", bson.D{{"$gt", 1}}}},
+		Update: bson.D{{"$inc", bson.D{{"relationcount", -1}}}},
+	}}, true, nil
+}
@@ -0,0 +1,279 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"archive/zip"
+	"crypto/sha512"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+	goyaml "gopkg.in/yaml.v1"
+
+	blobstorage "github.com/juju/juju/state/storage"
+)
+
+// validResourceName matches the names that may be given to resources.
+// Resource names are used as document keys, so they must never contain
+// dots or dollar signs.
+var validResourceName = regexp.MustCompile("^[a-z][a-z0-9]*(-[a-z0-9]+)*$")
+
+// IsValidResourceName returns whether name is a valid resource name.
+func IsValidResourceName(name string) bool {
+	return validResourceName.MatchString(name)
+}
+
+// Resource describes the current revision of a named binary blob
+// attached to a service.
+type Resource struct {
+	// Name identifies the resource within its service.
+	Name string
+
+	// Revision is incremented every time new content is stored
+	// for the resource.
+	Revision int
+
+	// Size is the length of the content in bytes.
+	Size int64
+
+	// SHA384 is the hex-encoded SHA384 digest of the content.
+	SHA384 string
+
+	// Uploaded is the time at which the content was stored.
+	Uploaded time.Time
+}
+
+// charmResourcesMeta holds the resources section of a charm's
+// metadata.yaml, which is not understood by charm.Meta.
+type charmResourcesMeta struct {
+	Resources map[string]struct {
+		Description string `yaml:"description"`
+	} `yaml:"resources"`
+}
+
+// readCharmResources returns the sorted names of the resources declared
+// in the metadata of the supplied charm. Only charm directories and
+// archives carry the raw metadata; other charms declare no resources.
+func readCharmResources(ch charm.Charm) ([]string, error) {
+	var data []byte
+	var err error
+	switch ch := ch.(type) {
+	case *charm.CharmDir:
+		data, err = ioutil.ReadFile(filepath.Join(ch.Path, "metadata.yaml"))
+	case *charm.CharmArchive:
+		data, err = readArchiveMetadata(ch.Path)
+	default:
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read charm metadata")
+	}
+	var meta charmResourcesMeta
+	if err := goyaml.Unmarshal(data, &meta); err != nil {
+		return nil, errors.Annotate(err, "cannot parse charm resources")
+	}
+	var names []string
+	for name := range meta.Resources {
+		if !IsValidResourceName(name) {
+			return nil, errors.NotValidf("resource name %q", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// readArchiveMetadata returns the content of metadata.yaml in the charm
+// archive at path.
+func readArchiveMetadata(path string) ([]byte, error) {
+	reader, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer reader.Close()
+	for _, file := range reader.File {
+		if file.Name != "metadata.yaml" {
+			continue
+		}
+		content, err := file.Open()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		defer content.Close()
+		return ioutil.ReadAll(content)
+	}
+	return nil, errors.NotFoundf("metadata.yaml in %q", path)
+}
+
+// resourceDoc records a resource revision inside the owning service's
+// document, keyed on the resource name.
+type resourceDoc struct {
+	Revision int       `bson:"revision"`
+	Path     string    `bson:"path"`
+	Size     int64     `bson:"size"`
+	SHA384   string    `bson:"sha384"`
+	Uploaded time.Time `bson:"uploaded"`
+}
+
+func (doc resourceDoc) resource(name string) Resource {
+	return Resource{
+		Name:     name,
+		Revision: doc.Revision,
+		Size:     doc.Size,
+		SHA384:   doc.SHA384,
+		Uploaded: doc.Uploaded,
+	}
+}
+
+// resourceStorage returns the blob storage holding resource content.
+func (st *State) resourceStorage() blobstorage.Storage {
+	return blobstorage.NewStorage(st.EnvironUUID(), st.MongoSession())
+}
+
+// Resources returns the current revision of each of the service's
+// resources, ordered by name.
+func (s *Service) Resources() []Resource {
+	names := make([]string, 0, len(s.doc.Resources))
+	for name := range s.doc.Resources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	resources := make([]Resource, len(names))
+	for i, name := range names {
+		resources[i] = s.doc.Resources[name].resource(name)
+	}
+	return resources
+}
+
+// Resource returns the current revision of the named resource.
+func (s *Service) Resource(name string) (Resource, error) {
+	doc, ok := s.doc.Resources[name]
+	if !ok {
+		return Resource{}, errors.NotFoundf("resource %q of service %q", name, s)
+	}
+	return doc.resource(name), nil
+}
+
+// OpenResource returns the current revision of the named resource,
+// together with a reader for its content. The caller is responsible
+// for closing the reader.
+func (s *Service) OpenResource(name string) (Resource, io.ReadCloser, error) {
+	doc, ok := s.doc.Resources[name]
+	if !ok {
+		return Resource{}, nil, errors.NotFoundf("resource %q of service %q", name, s)
+	}
+	reader, _, err := s.st.resourceStorage().Get(doc.Path)
+	if err != nil {
+		return Resource{}, nil, errors.Annotatef(err, "cannot read resource %q of service %q", name, s)
+	}
+	return doc.resource(name), reader, nil
+}
+
+// SetResource stores the content read from r, which must be exactly
+// size bytes long, as the next revision of the named resource. The
+// content of the previous revision, if any, is left for a cleanup to
+// remove, so that it can still be read by downloads already under way.
+func (s *Service) SetResource(name string, r io.Reader, size int64) (_ Resource, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set resource %q of service %q", name, s)
+	if !IsValidResourceName(name) {
+		return Resource{}, errors.NotValidf("resource name %q", name)
+	}
+	uuid, err := utils.NewUUID()
+	if err != nil {
+		return Resource{}, errors.Trace(err)
+	}
+	stor := s.st.resourceStorage()
+	path := fmt.Sprintf("resources/%s/%s-%s", s.doc.Name, name, uuid)
+	hash := sha512.New384()
+	if err := stor.Put(path, io.TeeReader(r, hash), size); err != nil {
+		return Resource{}, errors.Trace(err)
+	}
+	doc := resourceDoc{
+		Path:     path,
+		Size:     size,
+		SHA384:   hex.EncodeToString(hash.Sum(nil)),
+		Uploaded: nowToTheSecond(),
+	}
+
+	field := "resources." + name
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if s.doc.Life != Alive {
+			return nil, errNotAlive
+		}
+		ch, _, err := s.Charm()
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if !declaresResource(ch, name) {
+			return nil, errors.NewNotValid(nil, fmt.Sprintf("resource %q not declared by charm %q", name, ch))
+		}
+		var revisionAssert bson.DocElem
+		var cleanupOps []txn.Op
+		if current, ok := s.doc.Resources[name]; ok {
+			doc.Revision = current.Revision + 1
+			revisionAssert = bson.DocElem{field + ".revision", current.Revision}
+			cleanupOps = append(cleanupOps, s.st.newCleanupOp(cleanupResourceBlob, current.Path))
+		} else {
+			doc.Revision = 1
+			revisionAssert = bson.DocElem{field, bson.D{{"$exists", false}}}
+		}
+		return append([]txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: bson.D{{"life", Alive}, {"charmurl", s.doc.CharmURL}, revisionAssert},
+			Update: bson.D{{"$set", bson.D{{field, doc}}}},
+		}}, cleanupOps...), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		if removeErr := stor.Remove(path); removeErr != nil {
+			logger.Warningf("cannot remove unused resource content at %q: %v", path, removeErr)
+		}
+		if err == errNotAlive {
+			return Resource{}, errors.New("service is not alive")
+		}
+		return Resource{}, errors.Trace(err)
+	}
+	if s.doc.Resources == nil {
+		s.doc.Resources = make(map[string]resourceDoc)
+	}
+	s.doc.Resources[name] = doc
+	return doc.resource(name), nil
+}
+
+// declaresResource returns whether ch declares the named resource.
+func declaresResource(ch *Charm, name string) bool {
+	for _, declared := range ch.Resources() {
+		if declared == name {
+			return true
+		}
+	}
+	return false
+}
+
+// cleanupResourceBlob removes the stored content of a resource that
+// belonged to a removed service, or of a revision that has since been
+// replaced.
+func (st *State) cleanupResourceBlob(path string) error {
+	err := st.resourceStorage().Remove(path)
+	if errors.IsNotFound(err) {
+		return nil
+	}
+	return err
+}
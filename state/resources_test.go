@@ -0,0 +1,196 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"encoding/hex"
+	"io/ioutil"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/testcharms"
+)
+
+type ResourcesSuite struct {
+	ConnSuite
+	service *state.Service
+}
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func (s *ResourcesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.service = s.AddTestingService(c, "mysql", s.AddTestingCharm(c, "mysql"))
+}
+
+func (s *ResourcesSuite) setResource(c *gc.C, name, content string) state.Resource {
+	resource, err := s.service.SetResource(name, bytes.NewBufferString(content), int64(len(content)))
+	c.Assert(err, jc.ErrorIsNil)
+	return resource
+}
+
+func (s *ResourcesSuite) assertContent(c *gc.C, svc *state.Service, name, content string) {
+	resource, reader, err := svc.OpenResource(name)
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, content)
+	c.Assert(resource.Size, gc.Equals, int64(len(content)))
+}
+
+func sha384(content string) string {
+	hash := sha512.Sum384([]byte(content))
+	return hex.EncodeToString(hash[:])
+}
+
+func (s *ResourcesSuite) TestNoResources(c *gc.C) {
+	c.Assert(s.service.Resources(), gc.HasLen, 0)
+	_, err := s.service.Resource("jar")
+	c.Assert(err, gc.ErrorMatches, `resource "jar" of service "mysql" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, _, err = s.service.OpenResource("jar")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ResourcesSuite) TestSetResource(c *gc.C) {
+	resource := s.setResource(c, "jar", "some content")
+	c.Assert(resource.Name, gc.Equals, "jar")
+	c.Assert(resource.Revision, gc.Equals, 1)
+	c.Assert(resource.Size, gc.Equals, int64(12))
+	c.Assert(resource.SHA384, gc.Equals, sha384("some content"))
+	c.Assert(resource.Uploaded.IsZero(), jc.IsFalse)
+	s.assertContent(c, s.service, "jar", "some content")
+
+	// The resource is visible through a freshly loaded service.
+	svc, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	stored, err := svc.Resource("jar")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stored.Revision, gc.Equals, 1)
+	c.Assert(stored.SHA384, gc.Equals, resource.SHA384)
+	s.assertContent(c, svc, "jar", "some content")
+}
+
+func (s *ResourcesSuite) TestSetResourceIncrementsRevision(c *gc.C) {
+	s.setResource(c, "jar", "first")
+	s.setResource(c, "tarball", "other")
+	resource := s.setResource(c, "jar", "second")
+	c.Assert(resource.Revision, gc.Equals, 2)
+	s.assertContent(c, s.service, "jar", "second")
+
+	resources := s.service.Resources()
+	c.Assert(resources, gc.HasLen, 2)
+	c.Assert(resources[0].Name, gc.Equals, "jar")
+	c.Assert(resources[0].Revision, gc.Equals, 2)
+	c.Assert(resources[1].Name, gc.Equals, "tarball")
+	c.Assert(resources[1].Revision, gc.Equals, 1)
+}
+
+func (s *ResourcesSuite) TestSetResourceStaleService(c *gc.C) {
+	svc, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	s.setResource(c, "jar", "first")
+
+	resource, err := svc.SetResource("jar", bytes.NewBufferString("second"), 6)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resource.Revision, gc.Equals, 2)
+
+	err = s.service.Refresh()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertContent(c, s.service, "jar", "second")
+}
+
+func (s *ResourcesSuite) TestSetResourceCleansUpPreviousContent(c *gc.C) {
+	s.setResource(c, "jar", "first")
+	stale, err := s.State.Service("mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	_, reader, err := stale.OpenResource("jar")
+	c.Assert(err, jc.ErrorIsNil)
+	defer reader.Close()
+
+	// A download of the previous revision already under way is
+	// not cut short by the new revision.
+	s.setResource(c, "jar", "second")
+	data, err := ioutil.ReadAll(reader)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "first")
+	s.assertContent(c, stale, "jar", "first")
+
+	// The previous content is removed when the cleanup runs.
+	needsCleanup, err := s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needsCleanup, jc.IsTrue)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = stale.OpenResource("jar")
+	c.Assert(err, gc.ErrorMatches, `cannot read resource "jar" of service "mysql": .*`)
+	s.assertContent(c, s.service, "jar", "second")
+}
+
+func (s *ResourcesSuite) TestSetResourceInvalidName(c *gc.C) {
+	for _, name := range []string{"", "Jar", "some.jar", "$jar", "jar-", "1jar"} {
+		c.Logf("name %q", name)
+		_, err := s.service.SetResource(name, bytes.NewBufferString("x"), 1)
+		c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	}
+}
+
+func (s *ResourcesSuite) TestSetResourceUndeclared(c *gc.C) {
+	_, err := s.service.SetResource("war", bytes.NewBufferString("x"), 1)
+	c.Assert(err, gc.ErrorMatches, `cannot set resource "war" of service "mysql": resource "war" not declared by charm "local:quantal/quantal-mysql-1"`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	c.Assert(s.service.Resources(), gc.HasLen, 0)
+}
+
+func (s *ResourcesSuite) TestCharmResources(c *gc.C) {
+	ch, _, err := s.service.Charm()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Resources(), jc.DeepEquals, []string{"jar", "tarball"})
+
+	// Resources are also read from charm archives.
+	archive := testcharms.Repo.CharmArchive(c.MkDir(), "wordpress")
+	curl := charm.MustParseURL("local:quantal/wordpress-7")
+	ch, err = s.State.AddCharm(archive, curl, "dummy-path", "wordpress-7-sha256")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ch.Resources(), jc.DeepEquals, []string{"jar", "war"})
+
+	// Charms that declare no resources accept none.
+	svc := s.AddTestingService(c, "dummy", s.AddTestingCharm(c, "dummy"))
+	_, err = svc.SetResource("jar", bytes.NewBufferString("x"), 1)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ResourcesSuite) TestSetResourceServiceNotAlive(c *gc.C) {
+	_, err := s.service.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.service.SetResource("jar", bytes.NewBufferString("x"), 1)
+	c.Assert(err, gc.ErrorMatches, `cannot set resource "jar" of service "mysql": service is not alive`)
+}
+
+func (s *ResourcesSuite) TestRemoveServiceCleansUpContent(c *gc.C) {
+	s.setResource(c, "jar", "some content")
+	err := s.service.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Service("mysql")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// The content stays around until the cleanup runs.
+	s.assertContent(c, s.service, "jar", "some content")
+	needsCleanup, err := s.State.NeedsCleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(needsCleanup, jc.IsTrue)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+	_, _, err = s.service.OpenResource("jar")
+	c.Assert(err, gc.ErrorMatches, `cannot read resource "jar" of service "mysql": .*`)
+}
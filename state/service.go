@@ -46,6 +46,22 @@ type serviceDoc struct {
 	OwnerTag          string     `bson:"ownertag"`
 	TxnRevno          int64      `bson:"txn-revno"`
 	MetricCredentials []byte     `bson:"metric-credentials"`
+
+	// EndpointBindings maps relation endpoint names to the names of
+	// the spaces they are bound to.
+	EndpointBindings map[string]string `bson:"endpointbindings,omitempty"`
+
+	// Resources maps resource names to the current revision of
+	// each resource attached to the service.
+	Resources map[string]resourceDoc `bson:"resources,omitempty"`
+
+	// CharmHistory records the charms set on the service, oldest
+	// first.
+	CharmHistory []charmHistoryDoc `bson:"charmhistory,omitempty"`
+
+	// CharmUpgrade records the progress of a rolling charm upgrade
+	// of the service's units, if any.
+	CharmUpgrade *charmUpgradeDoc `bson:"charmupgrade,omitempty"`
 }
 
 func newService(st *State, doc *serviceDoc) *Service {
@@ -229,6 +245,16 @@ func (s *Service) removeOps(asserts bson.D) []txn.Op {
 		removeConstraintsOp(s.st, s.globalKey()),
 		annotationRemoveOp(s.st, s.globalKey()),
 		removeLeadershipSettingsOp(s.Tag().Id()),
+		removeServiceOfferOp(s.st, s.doc.Name),
+	}
+	if len(s.doc.Resources) > 0 {
+		// Resource content is held outside the database transaction, so
+		// it is removed by cleanups; make sure no new revision has been
+		// stored since we read the service, or its content would leak.
+		ops[0].Assert = append(asserts, bson.DocElem{"txn-revno", s.doc.TxnRevno})
+		for _, doc := range s.doc.Resources {
+			ops = append(ops, s.st.newCleanupOp(cleanupResourceBlob, doc.Path))
+		}
 	}
 	return ops
 }
@@ -374,7 +400,7 @@ func (s *Service) checkRelationsOps(ch *Charm, relations []*Relation) ([]txn.Op,
 
 // changeCharmOps returns the operations necessary to set a service's
 // charm URL to a new value.
-func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
+func (s *Service) changeCharmOps(ch *Charm, force bool, user string) ([]txn.Op, error) {
 	// Build the new service config from what can be used of the old one.
 	var newSettings charm.Settings
 	oldSettings, err := readSettings(s.st, s.settingsKey())
@@ -421,6 +447,7 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 	// Build the transaction.
 	var ops []txn.Op
 	differentCharm := bson.D{{"charmurl", bson.D{{"$ne", ch.URL()}}}}
+	historyUpdate, historyAssert := s.pushCharmHistoryUpdate(ch.URL(), user)
 	if oldSettings != nil {
 		// Old settings shouldn't change (when they exist).
 		ops = append(ops, oldSettings.assertUnchangedOp())
@@ -430,12 +457,16 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 		settingsOp,
 		// Increment the ref count.
 		incOp,
-		// Update the charm URL and force flag (if relevant).
+		// Update the charm URL and force flag (if relevant), and
+		// record the new charm in the service's history.
 		{
 			C:      servicesC,
 			Id:     s.doc.DocID,
-			Assert: append(notDeadDoc, differentCharm...),
-			Update: bson.D{{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}}},
+			Assert: append(append(notDeadDoc, differentCharm...), historyAssert...),
+			Update: bson.D{
+				{"$set", bson.D{{"charmurl", ch.URL()}, {"forcecharm", force}}},
+				historyUpdate,
+			},
 		},
 	}...)
 	// Add any extra peer relations that need creation.
@@ -474,8 +505,27 @@ func (s *Service) changeCharmOps(ch *Charm, force bool) ([]txn.Op, error) {
 
 // SetCharm changes the charm for the service. New units will be started with
 // this charm, and existing units will be upgraded to use it. If force is true,
-// units will be upgraded even if they are in an error state.
+// units will be upgraded even if they are in an error state. Any rolling
+// charm upgrade in progress is cancelled, releasing all its units.
 func (s *Service) SetCharm(ch *Charm, force bool) error {
+	return s.setCharm(ch, force, "")
+}
+
+// SetCharmByUser changes the charm for the service like SetCharm, and
+// records the given user as having made the change in the service's
+// charm history.
+func (s *Service) SetCharmByUser(ch *Charm, force bool, user names.UserTag) error {
+	return s.setCharm(ch, force, user.Username())
+}
+
+func (s *Service) setCharm(ch *Charm, force bool, user string) error {
+	return s.setCharmWithUpgrade(ch, nil, force, user, nil)
+}
+
+// setCharmWithUpgrade changes the charm for the service, upgrading its
+// units in batches if upgradeArgs is not nil. If current is not nil,
+// the change fails unless the service's charm is still current.
+func (s *Service) setCharmWithUpgrade(ch *Charm, current *charm.URL, force bool, user string, upgradeArgs *CharmUpgradeArgs) error {
 	if ch.Meta().Subordinate != s.doc.Subordinate {
 		return errors.Errorf("cannot change a service's subordinacy")
 	}
@@ -498,6 +548,13 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 			} else if !notDead {
 				return nil, ErrDead
 			}
+			// The charm history may have changed.
+			if err := s.Refresh(); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		if current != nil && *s.doc.CharmURL != *current {
+			return nil, errors.Errorf("charm changed from %q to %q", current, s.doc.CharmURL)
 		}
 		// Make sure the service doesn't have this charm already.
 		sel := bson.D{{"_id", s.doc.DocID}, {"charmurl", ch.URL()}}
@@ -515,17 +572,32 @@ func (s *Service) SetCharm(ch *Charm, force bool) error {
 			}}
 		} else {
 			// Change the charm URL.
-			ops, err = s.changeCharmOps(ch, force)
+			ops, err = s.changeCharmOps(ch, force, user)
 			if err != nil {
 				return nil, errors.Trace(err)
 			}
 		}
+		if current != nil {
+			ops = append(ops, txn.Op{
+				C:      servicesC,
+				Id:     s.doc.DocID,
+				Assert: bson.D{{"charmurl", current}},
+			})
+		}
+		upgradeOp, err := s.charmUpgradeOp(ch.URL(), upgradeArgs)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if upgradeOp != nil {
+			ops = append(ops, *upgradeOp)
+		}
 		return ops, nil
 	}
 	err := s.st.run(buildTxn)
 	if err == nil {
 		s.doc.CharmURL = ch.URL()
 		s.doc.ForceCharm = force
+		err = s.Refresh()
 	}
 	return err
 }
@@ -902,6 +974,101 @@ func (s *Service) Networks() ([]string, error) {
 	return readRequestedNetworks(s.st, s.globalKey())
 }
 
+// EndpointBindings returns the names of the spaces the service's
+// relation endpoints are bound to, keyed by endpoint name. Endpoints
+// that are not bound to a space are not included.
+func (s *Service) EndpointBindings() map[string]string {
+	bindings := make(map[string]string, len(s.doc.EndpointBindings))
+	for endpoint, space := range s.doc.EndpointBindings {
+		bindings[endpoint] = space
+	}
+	return bindings
+}
+
+// SetEndpointBindings binds the named relation endpoints of the service
+// to the named spaces, replacing any existing bindings. Each endpoint
+// must be defined by the service's charm, and each space must exist.
+// The address a unit of the service advertises on a relation is then
+// chosen from the space its endpoint is bound to.
+func (s *Service) SetEndpointBindings(bindings map[string]string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot set endpoint bindings for service %q", s)
+
+	ch, _, err := s.Charm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			alive, err := isAlive(s.st, servicesC, s.doc.DocID)
+			if err != nil {
+				return nil, errors.Trace(err)
+			} else if !alive {
+				return nil, errNotAlive
+			}
+		}
+		spaceOps, err := endpointBindingsOps(s.st, s.doc.Name, ch.Meta(), bindings)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     s.doc.DocID,
+			Assert: isAliveDoc,
+			Update: bson.D{{"$set", bson.D{{"endpointbindings", bindings}}}},
+		}}
+		return append(ops, spaceOps...), nil
+	}
+	if err := s.st.run(buildTxn); err != nil {
+		return err
+	}
+	s.doc.EndpointBindings = bindings
+	return nil
+}
+
+// endpointBindingsOps returns an error if any of the bound endpoints
+// is not defined by the charm, or if any of the spaces does not exist.
+// Otherwise it returns operations asserting that the spaces are alive.
+func endpointBindingsOps(st *State, serviceName string, meta *charm.Meta, bindings map[string]string) ([]txn.Op, error) {
+	var spaces []string
+	seen := make(map[string]bool)
+	for endpoint, space := range bindings {
+		if !charmHasEndpoint(meta, endpoint) {
+			return nil, errors.Errorf("service %q has no %q relation", serviceName, endpoint)
+		}
+		if !seen[space] {
+			seen[space] = true
+			spaces = append(spaces, space)
+		}
+	}
+	sort.Strings(spaces)
+	var ops []txn.Op
+	for _, space := range spaces {
+		if err := checkSpaceAlive(st, space); err != nil {
+			return nil, errors.Trace(err)
+		}
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(space),
+			Assert: isAliveDoc,
+		})
+	}
+	return ops, nil
+}
+
+// charmHasEndpoint reports whether a service with the given
+// charm metadata has the named relation endpoint.
+func charmHasEndpoint(meta *charm.Meta, name string) bool {
+	if name == "juju-info" {
+		return true
+	}
+	for _, rels := range []map[string]charm.Relation{meta.Peers, meta.Provides, meta.Requires} {
+		if _, ok := rels[name]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 // MetricCredentials returns any metric credentials associated with this service.
 func (s *Service) MetricCredentials() []byte {
 	return s.doc.MetricCredentials
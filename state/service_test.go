@@ -1398,6 +1398,14 @@ func (s *ServiceSuite) TestConstraints(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cons5, gc.DeepEquals, cons4)
 
+	// List constraints such as zones are stored too.
+	cons6 := constraints.MustParse("zones=az1,az2")
+	err = s.mysql.SetConstraints(cons6)
+	c.Assert(err, jc.ErrorIsNil)
+	cons7, err := s.mysql.Constraints()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cons7, gc.DeepEquals, cons6)
+
 	// Destroy the existing service; there's no way to directly assert
 	// that the constraints are deleted...
 	err = s.mysql.Destroy()
@@ -1732,6 +1740,70 @@ func (s *ServiceSuite) TestNetworksOnService(c *gc.C) {
 	c.Check(requestedNetworks, gc.DeepEquals, networks)
 }
 
+func (s *ServiceSuite) TestEndpointBindings(c *gc.C) {
+	c.Assert(s.mysql.EndpointBindings(), gc.HasLen, 0)
+
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.mysql.EndpointBindings(), jc.DeepEquals, map[string]string{"server": "internal"})
+
+	service, err := s.State.Service(s.mysql.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"server": "internal"})
+}
+
+func (s *ServiceSuite) TestAddServiceWithEndpointBindings(c *gc.C) {
+	_, err := s.State.AddServiceWithEndpointBindings(
+		"db", s.Owner.String(), s.charm, nil, nil, map[string]string{"server": "internal"},
+	)
+	c.Assert(err, gc.ErrorMatches, `cannot add service "db": space "internal" not found`)
+	_, err = s.State.Service("db")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	_, err = s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	service, err := s.State.AddServiceWithEndpointBindings(
+		"db", s.Owner.String(), s.charm, nil, nil, map[string]string{"server": "internal"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"server": "internal"})
+	service, err = s.State.Service("db")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(service.EndpointBindings(), jc.DeepEquals, map[string]string{"server": "internal"})
+}
+
+func (s *ServiceSuite) TestSetEndpointBindingsUnknownEndpoint(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetEndpointBindings(map[string]string{"website": "internal"})
+	c.Assert(err, gc.ErrorMatches,
+		`cannot set endpoint bindings for service "mysql": service "mysql" has no "website" relation`,
+	)
+}
+
+func (s *ServiceSuite) TestSetEndpointBindingsUnknownSpace(c *gc.C) {
+	err := s.mysql.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, gc.ErrorMatches,
+		`cannot set endpoint bindings for service "mysql": space "internal" not found`,
+	)
+	c.Assert(s.mysql.EndpointBindings(), gc.HasLen, 0)
+}
+
+func (s *ServiceSuite) TestSetEndpointBindingsOnDying(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.mysql.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.mysql.SetEndpointBindings(map[string]string{"server": "internal"})
+	c.Assert(err, gc.ErrorMatches,
+		`cannot set endpoint bindings for service "mysql": not found or not alive`,
+	)
+}
+
 func (s *ServiceSuite) TestMetricCredentials(c *gc.C) {
 	err := s.mysql.SetMetricCredentials([]byte("hello there"))
 	c.Assert(err, jc.ErrorIsNil)
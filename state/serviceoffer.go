@@ -0,0 +1,178 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/charm.v4"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// serviceOfferDoc records the endpoints of a service that services of
+// other environments may relate to.
+type serviceOfferDoc struct {
+	DocID     string   `bson:"_id"`
+	Service   string   `bson:"service"`
+	EnvUUID   string   `bson:"env-uuid"`
+	Endpoints []string `bson:"endpoints"`
+}
+
+// ServiceOffer represents the endpoints of a service offered for
+// relation to services of other environments on the same state server.
+type ServiceOffer struct {
+	st  *State
+	doc serviceOfferDoc
+}
+
+// Service returns the name of the offered service.
+func (o *ServiceOffer) Service() string {
+	return o.doc.Service
+}
+
+// EndpointNames returns the names of the offered endpoints.
+func (o *ServiceOffer) EndpointNames() []string {
+	return o.doc.Endpoints
+}
+
+// Endpoints returns the offered endpoints of the service.
+func (o *ServiceOffer) Endpoints() ([]Endpoint, error) {
+	svc, err := o.st.Service(o.doc.Service)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var eps []Endpoint
+	for _, name := range o.doc.Endpoints {
+		ep, err := svc.Endpoint(name)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		eps = append(eps, ep)
+	}
+	return eps, nil
+}
+
+// OfferService offers the named endpoints of the service for relation
+// to services of other environments. If no endpoints are given, all
+// the service's endpoints that can be offered are. Offering a service
+// again replaces the endpoints offered.
+func (st *State) OfferService(serviceName string, endpoints []string) (err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot offer service %q", serviceName)
+	svc, err := st.Service(serviceName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if svc.doc.Life != Alive {
+		return errors.Errorf("service is not alive")
+	}
+	eps, err := svc.Endpoints()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	var offered []string
+	if len(endpoints) == 0 {
+		for _, ep := range eps {
+			if canOffer(ep) {
+				offered = append(offered, ep.Name)
+			}
+		}
+		if len(offered) == 0 {
+			return errors.Errorf("service has no endpoints that can be offered")
+		}
+	} else {
+		for _, name := range endpoints {
+			ep, err := svc.Endpoint(name)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			if !canOffer(ep) {
+				return errors.Errorf("endpoint %q cannot be offered", name)
+			}
+			offered = append(offered, ep.Name)
+		}
+	}
+
+	docID := st.docID(serviceName)
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		ops := []txn.Op{{
+			C:      servicesC,
+			Id:     docID,
+			Assert: isAliveDoc,
+		}}
+		if _, err := st.ServiceOffer(serviceName); errors.IsNotFound(err) {
+			ops = append(ops, txn.Op{
+				C:      serviceOffersC,
+				Id:     docID,
+				Assert: txn.DocMissing,
+				Insert: &serviceOfferDoc{
+					DocID:     docID,
+					Service:   serviceName,
+					EnvUUID:   st.EnvironUUID(),
+					Endpoints: offered,
+				},
+			})
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		} else {
+			ops = append(ops, txn.Op{
+				C:      serviceOffersC,
+				Id:     docID,
+				Assert: txn.DocExists,
+				Update: bson.D{{"$set", bson.D{{"endpoints", offered}}}},
+			})
+		}
+		return ops, nil
+	}
+	return st.run(buildTxn)
+}
+
+// canOffer returns whether the endpoint may be offered to other
+// environments. Only global relations between distinct services can
+// cross environments.
+func canOffer(ep Endpoint) bool {
+	return ep.Role != charm.RolePeer && ep.Scope == charm.ScopeGlobal && !ep.IsImplicit()
+}
+
+// ServiceOffer returns the offer of the named service. It returns an
+// error satisfying errors.IsNotFound if the service is not offered.
+func (st *State) ServiceOffer(serviceName string) (*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	offer := &ServiceOffer{st: st}
+	err := offers.FindId(serviceName).One(&offer.doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("offer of service %q", serviceName)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "cannot get offer of service %q", serviceName)
+	}
+	return offer, nil
+}
+
+// ServiceOffers returns the offers of all services of the environment.
+func (st *State) ServiceOffers() ([]*ServiceOffer, error) {
+	offers, closer := st.getCollection(serviceOffersC)
+	defer closer()
+
+	var docs []serviceOfferDoc
+	if err := offers.Find(nil).Sort("service").All(&docs); err != nil {
+		return nil, errors.Annotatef(err, "cannot get service offers")
+	}
+	result := make([]*ServiceOffer, len(docs))
+	for i, doc := range docs {
+		result[i] = &ServiceOffer{st: st, doc: doc}
+	}
+	return result, nil
+}
+
+// removeServiceOfferOp returns the operation that removes the offer of
+// the named service, if any.
+func removeServiceOfferOp(st *State, serviceName string) txn.Op {
+	return txn.Op{
+		C:      serviceOffersC,
+		Id:     st.docID(serviceName),
+		Remove: true,
+	}
+}
@@ -78,6 +78,9 @@ type Settings struct {
 	// is called.
 	core     map[string]interface{}
 	txnRevno int64
+	// writeOps, if set, returns any further operations to run along
+	// with each write of changes to the node.
+	writeOps func() []txn.Op
 }
 
 // Keys returns the current keys in alphabetical order.
@@ -169,6 +172,9 @@ func (c *Settings) Write() ([]ItemChange, error) {
 		Assert: txn.DocExists,
 		Update: setUnsetUpdate(updates, deletions),
 	}}
+	if c.writeOps != nil {
+		ops = append(ops, c.writeOps()...)
+	}
 	err := c.st.runTransaction(ops)
 	if err == txn.ErrAborted {
 		return nil, errors.NotFoundf("settings")
@@ -0,0 +1,219 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"net"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+
+	"github.com/juju/juju/network"
+)
+
+// Space represents a named group of subnets. Spaces are used to
+// decide which of a machine's addresses is given to each of the
+// relation endpoints bound to them.
+type Space struct {
+	st  *State
+	doc spaceDoc
+}
+
+type spaceDoc struct {
+	DocID   string `bson:"_id"`
+	EnvUUID string `bson:"env-uuid"`
+	Life    Life   `bson:"life"`
+	Name    string `bson:"name"`
+}
+
+// Name returns the name of the space.
+func (s *Space) Name() string {
+	return s.doc.Name
+}
+
+// Life returns whether the space is Alive, Dying or Dead.
+func (s *Space) Life() Life {
+	return s.doc.Life
+}
+
+// String implements fmt.Stringer.
+func (s *Space) String() string {
+	return s.doc.Name
+}
+
+// Subnets returns all the subnets in the space.
+func (s *Space) Subnets() ([]*Subnet, error) {
+	subnetsCollection, closer := s.st.getCollection(subnetsC)
+	defer closer()
+
+	docs := []subnetDoc{}
+	err := subnetsCollection.Find(bson.D{{"spacename", s.doc.Name}}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get subnets of space %q", s)
+	}
+	var subnets []*Subnet
+	for _, doc := range docs {
+		subnets = append(subnets, &Subnet{s.st, doc})
+	}
+	return subnets, nil
+}
+
+// Refresh refreshes the contents of the Space from the underlying
+// state. It returns an error that satisfies errors.IsNotFound if the
+// Space has been removed.
+func (s *Space) Refresh() error {
+	spaces, closer := s.st.getCollection(spacesC)
+	defer closer()
+
+	err := spaces.FindId(s.doc.DocID).One(&s.doc)
+	if err == mgo.ErrNotFound {
+		return errors.NotFoundf("space %q", s)
+	}
+	if err != nil {
+		return errors.Errorf("cannot refresh space %q: %v", s, err)
+	}
+	return nil
+}
+
+// AddSpace creates and returns a new space containing the subnets with
+// the given CIDRs. The subnets must already be known, and must not be
+// part of another space. If a space with the same name already exists,
+// an error satisfying errors.IsAlreadyExists is returned.
+func (st *State) AddSpace(name string, subnets []string) (space *Space, err error) {
+	defer errors.DeferredAnnotatef(&err, "cannot add space %q", name)
+
+	if !network.IsValidSpace(name) {
+		return nil, errors.NotValidf("space name %q", name)
+	}
+	spaceDoc := spaceDoc{
+		DocID:   st.docID(name),
+		EnvUUID: st.EnvironUUID(),
+		Life:    Alive,
+		Name:    name,
+	}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if _, err := st.Space(name); err == nil {
+			return nil, errors.AlreadyExistsf("space %q", name)
+		} else if !errors.IsNotFound(err) {
+			return nil, errors.Trace(err)
+		}
+		ops := []txn.Op{{
+			C:      spacesC,
+			Id:     spaceDoc.DocID,
+			Assert: txn.DocMissing,
+			Insert: spaceDoc,
+		}}
+		for _, cidr := range subnets {
+			subnet, err := st.Subnet(cidr)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if subnet.Life() != Alive {
+				return nil, errors.Errorf("subnet %q is not alive", cidr)
+			}
+			if subnet.SpaceName() != "" {
+				return nil, errors.Errorf("subnet %q already in space %q", cidr, subnet.SpaceName())
+			}
+			ops = append(ops, txn.Op{
+				C:  subnetsC,
+				Id: subnet.doc.DocID,
+				Assert: bson.D{
+					{"life", Alive},
+					{"spacename", bson.D{{"$in", []interface{}{nil, ""}}}},
+				},
+				Update: bson.D{{"$set", bson.D{{"spacename", name}}}},
+			})
+		}
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &Space{st, spaceDoc}, nil
+}
+
+// Space returns the space with the given name. If the space does not
+// exist, an error satisfying errors.IsNotFound is returned.
+func (st *State) Space(name string) (*Space, error) {
+	spaces, closer := st.getCollection(spacesC)
+	defer closer()
+
+	doc := &spaceDoc{}
+	err := spaces.FindId(name).One(doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("space %q", name)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get space %q", name)
+	}
+	return &Space{st, *doc}, nil
+}
+
+// AllSpaces returns all known spaces in the environment.
+func (st *State) AllSpaces() ([]*Space, error) {
+	spacesCollection, closer := st.getCollection(spacesC)
+	defer closer()
+
+	docs := []spaceDoc{}
+	err := spacesCollection.Find(nil).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get all spaces")
+	}
+	var spaces []*Space
+	for _, doc := range docs {
+		spaces = append(spaces, &Space{st, doc})
+	}
+	return spaces, nil
+}
+
+// checkSpaceAlive returns an error if the named space does not exist
+// or is not alive.
+func checkSpaceAlive(st *State, name string) error {
+	alive, err := isAlive(st, spacesC, st.docID(name))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !alive {
+		return errors.NotFoundf("space %q", name)
+	}
+	return nil
+}
+
+// addressInSpace returns the first of the given addresses which lies
+// within one of the named space's subnets. If there is no such
+// address, an error satisfying errors.IsNotFound is returned.
+func addressInSpace(st *State, name string, addresses []network.Address) (network.Address, error) {
+	space, err := st.Space(name)
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	subnets, err := space.Subnets()
+	if err != nil {
+		return network.Address{}, errors.Trace(err)
+	}
+	var nets []*net.IPNet
+	for _, subnet := range subnets {
+		_, ipNet, err := net.ParseCIDR(subnet.CIDR())
+		if err != nil {
+			// Subnets are validated when added, so this should
+			// never happen.
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	for _, addr := range addresses {
+		ip := net.ParseIP(addr.Value)
+		if ip == nil {
+			continue
+		}
+		for _, ipNet := range nets {
+			if ipNet.Contains(ip) {
+				return addr, nil
+			}
+		}
+	}
+	return network.Address{}, errors.NotFoundf("address in space %q", name)
+}
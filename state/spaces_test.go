@@ -0,0 +1,148 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type SpaceSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&SpaceSuite{})
+
+func (s *SpaceSuite) addSubnet(c *gc.C, cidr, space string) *state.Subnet {
+	subnet, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:      cidr,
+		SpaceName: space,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return subnet
+}
+
+func (s *SpaceSuite) TestAddSpace(c *gc.C) {
+	s.addSubnet(c, "10.0.0.0/24", "")
+	s.addSubnet(c, "10.0.1.0/24", "")
+	s.addSubnet(c, "10.0.2.0/24", "")
+
+	space, err := s.State.AddSpace("internal", []string{"10.0.0.0/24", "10.0.1.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(space.Name(), gc.Equals, "internal")
+	c.Assert(space.String(), gc.Equals, "internal")
+	c.Assert(space.Life(), gc.Equals, state.Alive)
+
+	space, err = s.State.Space("internal")
+	c.Assert(err, jc.ErrorIsNil)
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	var cidrs []string
+	for _, subnet := range subnets {
+		cidrs = append(cidrs, subnet.CIDR())
+		c.Check(subnet.SpaceName(), gc.Equals, "internal")
+	}
+	c.Assert(cidrs, jc.SameContents, []string{"10.0.0.0/24", "10.0.1.0/24"})
+
+	subnet, err := s.State.Subnet("10.0.2.0/24")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnet.SpaceName(), gc.Equals, "")
+}
+
+func (s *SpaceSuite) TestAddSpaceNoSubnets(c *gc.C) {
+	space, err := s.State.AddSpace("empty", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	subnets, err := space.Subnets()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(subnets, gc.HasLen, 0)
+}
+
+func (s *SpaceSuite) TestAddSpaceInvalidName(c *gc.C) {
+	_, err := s.State.AddSpace("Not_Valid", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "Not_Valid": space name "Not_Valid" not valid`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *SpaceSuite) TestAddSpaceAlreadyExists(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.AddSpace("internal", nil)
+	c.Assert(err, gc.ErrorMatches, `cannot add space "internal": space "internal" already exists`)
+	c.Assert(err, jc.Satisfies, errors.IsAlreadyExists)
+}
+
+func (s *SpaceSuite) TestAddSpaceUnknownSubnet(c *gc.C) {
+	_, err := s.State.AddSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches, `cannot add space "internal": subnet "10.0.0.0/24" not found`)
+
+	_, err = s.State.Space("internal")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpaceSuite) TestAddSpaceSubnetInAnotherSpace(c *gc.C) {
+	s.addSubnet(c, "10.0.0.0/24", "")
+	_, err := s.State.AddSpace("internal", []string{"10.0.0.0/24"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.AddSpace("public", []string{"10.0.0.0/24"})
+	c.Assert(err, gc.ErrorMatches,
+		`cannot add space "public": subnet "10.0.0.0/24" already in space "internal"`,
+	)
+}
+
+func (s *SpaceSuite) TestAddSubnetInSpace(c *gc.C) {
+	_, err := s.State.AddSpace("internal", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	subnet := s.addSubnet(c, "10.0.0.0/24", "internal")
+	c.Assert(subnet.SpaceName(), gc.Equals, "internal")
+}
+
+func (s *SpaceSuite) TestAddSubnetInUnknownSpace(c *gc.C) {
+	_, err := s.State.AddSubnet(state.SubnetInfo{
+		CIDR:      "10.0.0.0/24",
+		SpaceName: "internal",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot add subnet "10.0.0.0/24": space "internal" not found`)
+}
+
+func (s *SpaceSuite) TestSpaceNotFound(c *gc.C) {
+	_, err := s.State.Space("missing")
+	c.Assert(err, gc.ErrorMatches, `space "missing" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *SpaceSuite) TestAllSpaces(c *gc.C) {
+	spaces, err := s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(spaces, gc.HasLen, 0)
+
+	for _, name := range []string{"internal", "public"} {
+		_, err := s.State.AddSpace(name, nil)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	spaces, err = s.State.AllSpaces()
+	c.Assert(err, jc.ErrorIsNil)
+	var names []string
+	for _, space := range spaces {
+		names = append(names, space.Name())
+	}
+	c.Assert(names, jc.SameContents, []string{"internal", "public"})
+}
+
+func (s *SpaceSuite) TestAllSubnets(c *gc.C) {
+	s.addSubnet(c, "10.0.0.0/24", "")
+	s.addSubnet(c, "10.0.1.0/24", "")
+
+	subnets, err := s.State.AllSubnets()
+	c.Assert(err, jc.ErrorIsNil)
+	var cidrs []string
+	for _, subnet := range subnets {
+		cidrs = append(cidrs, subnet.CIDR())
+	}
+	c.Assert(cidrs, jc.SameContents, []string{"10.0.0.0/24", "10.0.1.0/24"})
+}
@@ -55,6 +55,7 @@ const (
 	constraintsC       = "constraints"
 	unitsC             = "units"
 	subnetsC           = "subnets"
+	spacesC            = "spaces"
 	ipaddressesC       = "ipaddresses"
 
 	// actionsC and related collections store state of Actions that
@@ -99,6 +100,18 @@ const (
 	// toolsmetadataC is the collection used to store tools metadata.
 	toolsmetadataC = "toolsmetadata"
 
+	// charmMirrorC is the collection used to store the metadata of
+	// charm archives mirrored from the charm store.
+	charmMirrorC = "charmmirror"
+
+	// serviceOffersC holds the services offered for relation to
+	// other environments.
+	serviceOffersC = "serviceoffers"
+
+	// remoteServicesC holds the services of other environments that
+	// services of this environment are related to.
+	remoteServicesC = "remoteservices"
+
 	// These collections are used by the mgo transaction runner.
 	txnLogC = "txns.log"
 	txnsC   = "txns"
@@ -777,6 +790,10 @@ func (st *State) AddCharm(ch charm.Charm, curl *charm.URL, storagePath, bundleSh
 
 	err = charms.Find(bson.D{{"_id", curl.String()}, {"placeholder", true}}).One(&existing)
 	if err == mgo.ErrNotFound {
+		resources, err := readCharmResources(ch)
+		if err != nil {
+			return nil, errors.Annotatef(err, "cannot add charm %q", curl)
+		}
 		cdoc := &charmDoc{
 			DocID:        st.docID(curl.String()),
 			URL:          curl,
@@ -787,6 +804,7 @@ func (st *State) AddCharm(ch charm.Charm, curl *charm.URL, storagePath, bundleSh
 			Actions:      ch.Actions(),
 			BundleSha256: bundleSha256,
 			StoragePath:  storagePath,
+			Resources:    resources,
 		}
 		err = charms.Insert(cdoc)
 		if err != nil {
@@ -1163,11 +1181,16 @@ func (st *State) updateCharmDoc(
 		escapedName := escapeReplacer.Replace(optionName)
 		escapedConfig.Options[escapedName] = option
 	}
+	resources, err := readCharmResources(ch)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot update charm %q", curl)
+	}
 	updateFields := bson.D{{"$set", bson.D{
 		{"meta", ch.Meta()},
 		{"config", escapedConfig},
 		{"actions", ch.Actions()},
 		{"metrics", ch.Metrics()},
+		{"resources", resources},
 		{"storagepath", storagePath},
 		{"bundlesha256", bundleSha256},
 		{"pendingupload", false},
@@ -1222,6 +1245,17 @@ func (st *State) addPeerRelationsOps(serviceName string, peers map[string]charm.
 // they will be created automatically.
 func (st *State) AddService(
 	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+) (service *Service, err error) {
+	return st.AddServiceWithEndpointBindings(name, owner, ch, networks, storage, nil)
+}
+
+// AddServiceWithEndpointBindings is like AddService, but also binds the
+// named relation endpoints of the service to the named spaces, as
+// SetEndpointBindings does. The service is not created if any of the
+// bindings is invalid.
+func (st *State) AddServiceWithEndpointBindings(
+	name, owner string, ch *Charm, networks []string, storage map[string]StorageConstraints,
+	bindings map[string]string,
 ) (service *Service, err error) {
 	defer errors.DeferredAnnotatef(&err, "cannot add service %q", name)
 	ownerTag, err := names.ParseUserTag(owner)
@@ -1255,19 +1289,32 @@ func (st *State) AddService(
 	if err := validateStorageConstraints(st, storage, ch.Meta()); err != nil {
 		return nil, errors.Trace(err)
 	}
+	spaceOps, err := endpointBindingsOps(st, name, ch.Meta(), bindings)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if len(bindings) == 0 {
+		bindings = nil
+	}
 	serviceID := st.docID(name)
 	// Create the service addition operations.
 	peers := ch.Meta().Peers
 	svcDoc := &serviceDoc{
-		DocID:         serviceID,
-		Name:          name,
-		EnvUUID:       env.UUID(),
-		Series:        ch.URL().Series,
-		Subordinate:   ch.Meta().Subordinate,
-		CharmURL:      ch.URL(),
-		RelationCount: len(peers),
-		Life:          Alive,
-		OwnerTag:      owner,
+		DocID:            serviceID,
+		Name:             name,
+		EnvUUID:          env.UUID(),
+		Series:           ch.URL().Series,
+		Subordinate:      ch.Meta().Subordinate,
+		CharmURL:         ch.URL(),
+		RelationCount:    len(peers),
+		Life:             Alive,
+		OwnerTag:         owner,
+		EndpointBindings: bindings,
+		CharmHistory: []charmHistoryDoc{{
+			CharmURL: ch.URL(),
+			Time:     nowToTheSecond(),
+			User:     ownerTag.Username(),
+		}},
 	}
 	svc := newService(st, svcDoc)
 	ops := []txn.Op{
@@ -1295,6 +1342,13 @@ func (st *State) AddService(
 			Assert: txn.DocMissing,
 			Insert: svcDoc,
 		},
+		{
+			// Services of other environments related to this one
+			// share the namespace of its services.
+			C:      remoteServicesC,
+			Id:     serviceID,
+			Assert: txn.DocMissing,
+		},
 	}
 	// Collect peer relation addition operations.
 	peerOps, err := st.addPeerRelationsOps(name, peers)
@@ -1302,6 +1356,7 @@ func (st *State) AddService(
 		return nil, errors.Trace(err)
 	}
 	ops = append(ops, peerOps...)
+	ops = append(ops, spaceOps...)
 
 	if err := st.runTransaction(ops); err == txn.ErrAborted {
 		err := env.Refresh()
@@ -1310,6 +1365,9 @@ func (st *State) AddService(
 		} else if err != nil {
 			return nil, errors.Trace(err)
 		}
+		if _, err := endpointBindingsOps(st, name, ch.Meta(), bindings); err != nil {
+			return nil, errors.Trace(err)
+		}
 		return nil, errors.Errorf("service already exists")
 	} else if err != nil {
 		return nil, errors.Trace(err)
@@ -1395,6 +1453,7 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		AllocatableIPHigh: args.AllocatableIPHigh,
 		AllocatableIPLow:  args.AllocatableIPLow,
 		AvailabilityZone:  args.AvailabilityZone,
+		SpaceName:         args.SpaceName,
 	}
 	subnet = &Subnet{doc: subDoc, st: st}
 	err = subnet.Validate()
@@ -1407,15 +1466,28 @@ func (st *State) AddSubnet(args SubnetInfo) (subnet *Subnet, err error) {
 		Assert: txn.DocMissing,
 		Insert: subDoc,
 	}}
+	if args.SpaceName != "" {
+		ops = append(ops, txn.Op{
+			C:      spacesC,
+			Id:     st.docID(args.SpaceName),
+			Assert: isAliveDoc,
+		})
+	}
 
 	err = st.runTransaction(ops)
 	switch err {
 	case txn.ErrAborted:
 		if _, err = st.Subnet(args.CIDR); err == nil {
 			return nil, errors.AlreadyExistsf("subnet %q", args.CIDR)
-		} else if err != nil {
+		} else if !errors.IsNotFound(err) {
 			return nil, errors.Trace(err)
 		}
+		if args.SpaceName != "" {
+			if err := checkSpaceAlive(st, args.SpaceName); err != nil {
+				return nil, errors.Trace(err)
+			}
+		}
+		return nil, errors.Trace(txn.ErrAborted)
 	case nil:
 		// if the ProviderId was not unique adding the subnet can fail
 		// without an error. Refreshing catches this
@@ -1443,6 +1515,22 @@ func (st *State) Subnet(cidr string) (*Subnet, error) {
 	return &Subnet{st, *doc}, nil
 }
 
+// AllSubnets returns all known subnets in the environment.
+func (st *State) AllSubnets() (subnets []*Subnet, err error) {
+	subnetsCollection, closer := st.getCollection(subnetsC)
+	defer closer()
+
+	docs := []subnetDoc{}
+	err = subnetsCollection.Find(nil).All(&docs)
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get all subnets")
+	}
+	for _, doc := range docs {
+		subnets = append(subnets, &Subnet{st, doc})
+	}
+	return subnets, nil
+}
+
 // AddNetwork creates a new network with the given params. If a
 // network with the same name or provider id already exists in state,
 // an error satisfying errors.IsAlreadyExists is returned.
@@ -1766,7 +1854,15 @@ func (st *State) AddRelation(eps ...Endpoint) (r *Relation, err error) {
 		for _, ep := range eps {
 			svc, err := st.Service(ep.ServiceName)
 			if errors.IsNotFound(err) {
-				return nil, errors.Errorf("service %q does not exist", ep.ServiceName)
+				// The service may belong to another environment, in
+				// which case its units join the relation through the
+				// remote relations worker.
+				op, err := st.addRemoteServiceRelationOp(ep)
+				if err != nil {
+					return nil, errors.Trace(err)
+				}
+				ops = append(ops, op)
+				continue
 			} else if err != nil {
 				return nil, errors.Trace(err)
 			} else if svc.doc.Life != Alive {
@@ -38,6 +38,10 @@ type SubnetInfo struct {
 	// AvailabilityZone describes which availability zone this subnet is in. It can
 	// be empty if the provider does not support availability zones.
 	AvailabilityZone string
+
+	// SpaceName is the name of the space the subnet belongs to. It can
+	// be empty if the subnet is not part of any space.
+	SpaceName string
 }
 
 type Subnet struct {
@@ -55,6 +59,7 @@ type subnetDoc struct {
 	AllocatableIPLow  string `bson:"allocatableiplow,omitempty"`
 	VLANTag           int    `bson:"vlantag,omitempty"`
 	AvailabilityZone  string `bson:"availabilityzone,omitempty"`
+	SpaceName         string `bson:"spacename,omitempty"`
 }
 
 // Life returns whether the subnet is Alive, Dying or Dead.
@@ -168,6 +173,12 @@ func (s *Subnet) AvailabilityZone() string {
 	return s.doc.AvailabilityZone
 }
 
+// SpaceName returns the name of the space the subnet belongs to. If
+// the subnet is not part of any space it will be the empty string.
+func (s *Subnet) SpaceName() string {
+	return s.doc.SpaceName
+}
+
 // Validate validates the subnet, checking the CIDR, VLANTag and
 // AllocatableIPHigh and Low, if present.
 func (s *Subnet) Validate() error {
@@ -184,6 +195,9 @@ func (s *Subnet) Validate() error {
 	if s.doc.VLANTag < 0 || s.doc.VLANTag > 4094 {
 		return errors.Errorf("invalid VLAN tag %d: must be between 0 and 4094", s.doc.VLANTag)
 	}
+	if s.doc.SpaceName != "" && !network.IsValidSpace(s.doc.SpaceName) {
+		return errors.Errorf("invalid space name %q", s.doc.SpaceName)
+	}
 	present := func(str string) bool {
 		return str != ""
 	}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/names"
 	"github.com/juju/utils/set"
+	"gopkg.in/juju/charm.v4"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
 	"launchpad.net/tomb"
@@ -183,6 +184,12 @@ func (st *State) WatchServices() StringsWatcher {
 	return newLifecycleWatcher(st, servicesC, nil, st.isForStateEnv, nil)
 }
 
+// WatchRemoteServices returns a StringsWatcher that notifies of remote
+// services being added to and removed from the environment.
+func (st *State) WatchRemoteServices() StringsWatcher {
+	return newLifecycleWatcher(st, remoteServicesC, nil, st.isForStateEnv, nil)
+}
+
 // WatchStorageAttachments returns a StringsWatcher that notifies of
 // changes to the lifecycles of all storage instances attached to the
 // specified unit.
@@ -221,10 +228,20 @@ func (s *Service) WatchUnits() StringsWatcher {
 // WatchRelations returns a StringsWatcher that notifies of changes to the
 // lifecycles of relations involving s.
 func (s *Service) WatchRelations() StringsWatcher {
-	prefix := s.doc.Name + ":"
+	return watchServiceRelations(s.st, s.doc.Name)
+}
+
+// WatchRelations returns a StringsWatcher that notifies of changes to the
+// lifecycles of relations involving s.
+func (s *RemoteService) WatchRelations() StringsWatcher {
+	return watchServiceRelations(s.st, s.doc.Name)
+}
+
+func watchServiceRelations(st *State, serviceName string) StringsWatcher {
+	prefix := serviceName + ":"
 	infix := " " + prefix
 	filter := func(id interface{}) bool {
-		k, err := s.st.strictLocalID(id.(string))
+		k, err := st.strictLocalID(id.(string))
 		if err != nil {
 			return false
 		}
@@ -232,8 +249,8 @@ func (s *Service) WatchRelations() StringsWatcher {
 		return out
 	}
 
-	members := bson.D{{"endpoints.servicename", s.doc.Name}}
-	return newLifecycleWatcher(s.st, relationsC, members, filter, nil)
+	members := bson.D{{"endpoints.servicename", serviceName}}
+	return newLifecycleWatcher(st, relationsC, members, filter, nil)
 }
 
 // WatchEnvironMachines returns a StringsWatcher that notifies of changes to
@@ -761,13 +778,27 @@ var _ Watcher = (*relationUnitsWatcher)(nil)
 // Watch returns a watcher that notifies of changes to conterpart units in
 // the relation.
 func (ru *RelationUnit) Watch() RelationUnitsWatcher {
-	return newRelationUnitsWatcher(ru)
+	return newRelationUnitsWatcher(ru.st, ru.WatchScope())
 }
 
-func newRelationUnitsWatcher(ru *RelationUnit) RelationUnitsWatcher {
+// WatchUnits returns a watcher that notifies of changes to the units of
+// the named service in the relation, which must be global.
+func (r *Relation) WatchUnits(serviceName string) (RelationUnitsWatcher, error) {
+	ep, err := r.Endpoint(serviceName)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if ep.Scope != charm.ScopeGlobal {
+		return nil, errors.Errorf("cannot watch units of non-global relation %q", r)
+	}
+	scope := fmt.Sprintf("r#%d#%s", r.doc.Id, ep.Role)
+	return newRelationUnitsWatcher(r.st, newRelationScopeWatcher(r.st, scope, "")), nil
+}
+
+func newRelationUnitsWatcher(st *State, sw *RelationScopeWatcher) RelationUnitsWatcher {
 	w := &relationUnitsWatcher{
-		commonWatcher: commonWatcher{st: ru.st},
-		sw:            ru.WatchScope(),
+		commonWatcher: commonWatcher{st: st},
+		sw:            sw,
 		watching:      make(set.Strings),
 		updates:       make(chan watcher.Change),
 		out:           make(chan multiwatcher.RelationUnitsChange),
@@ -2441,6 +2472,108 @@ func (w *rebootWatcher) loop() error {
 	}
 }
 
+// WatchContainerPorts returns a NotifyWatcher that notifies of changes
+// to the ports opened on, or the documents of, the containers directly
+// inside m. A host forwarding ports to its containers uses it to learn
+// when to update the forwarding rules.
+func (m *Machine) WatchContainerPorts() NotifyWatcher {
+	return newContainerPortsWatcher(m.st, m.Id())
+}
+
+// containerPortsWatcher notifies of changes to the opened ports and
+// machine documents of a machine's containers.
+type containerPortsWatcher struct {
+	commonWatcher
+	hostId string
+	out    chan struct{}
+}
+
+var _ Watcher = (*containerPortsWatcher)(nil)
+
+func newContainerPortsWatcher(st *State, hostId string) NotifyWatcher {
+	w := &containerPortsWatcher{
+		commonWatcher: commonWatcher{st: st},
+		hostId:        hostId,
+		out:           make(chan struct{}),
+	}
+	go func() {
+		defer w.tomb.Done()
+		defer close(w.out)
+		w.tomb.Kill(w.loop())
+	}()
+	return w
+}
+
+// Changes returns the event channel for the containerPortsWatcher.
+func (w *containerPortsWatcher) Changes() <-chan struct{} {
+	return w.out
+}
+
+// isHostedId reports whether the given local machine id is that of a
+// container directly inside the watched host.
+func (w *containerPortsWatcher) isHostedId(machineId string) bool {
+	return ParentId(machineId) == w.hostId
+}
+
+func (w *containerPortsWatcher) loop() error {
+	portsFilter := func(key interface{}) bool {
+		id, ok := key.(string)
+		if !ok {
+			w.tomb.Kill(fmt.Errorf("expected string, got %T: %v", key, key))
+			return false
+		}
+		localID, err := w.st.strictLocalID(id)
+		if err != nil {
+			return false
+		}
+		parts, err := extractPortsIdParts(localID)
+		if err != nil {
+			return false
+		}
+		return w.isHostedId(parts[machineIdPart])
+	}
+	machinesFilter := func(key interface{}) bool {
+		id, ok := key.(string)
+		if !ok {
+			w.tomb.Kill(fmt.Errorf("expected string, got %T: %v", key, key))
+			return false
+		}
+		localID, err := w.st.strictLocalID(id)
+		if err != nil {
+			return false
+		}
+		return w.isHostedId(localID)
+	}
+	portsCh := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(openedPortsC, portsCh, portsFilter)
+	defer w.st.watcher.UnwatchCollection(openedPortsC, portsCh)
+	machinesCh := make(chan watcher.Change)
+	w.st.watcher.WatchCollectionWithFilter(machinesC, machinesCh, machinesFilter)
+	defer w.st.watcher.UnwatchCollection(machinesC, machinesCh)
+
+	out := w.out
+	for {
+		select {
+		case <-w.tomb.Dying():
+			return tomb.ErrDying
+		case <-w.st.watcher.Dead():
+			return stateWatcherDeadError(w.st.watcher.Err())
+		case ch := <-portsCh:
+			if _, ok := collect(ch, portsCh, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case ch := <-machinesCh:
+			if _, ok := collect(ch, machinesCh, w.tomb.Dying()); !ok {
+				return tomb.ErrDying
+			}
+			out = w.out
+		case out <- struct{}{}:
+			out = nil
+		}
+	}
+}
+
 // WatchLeadershipSettings returns a LeadershipSettingsWatcher for
 // watching -- wait for it -- leadership settings.
 func (st *State) WatchLeadershipSettings(serviceId string) *LeadershipSettingsWatcher {
@@ -0,0 +1,47 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmupgrader
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.charmupgrader")
+
+// defaultInterval is the standard value for the interval setting.
+const defaultInterval = 10 * time.Second
+
+// interval sets how often rolling charm upgrades are checked.
+var interval = defaultInterval
+
+// NewCharmUpgrader returns a worker that periodically advances the
+// rolling charm upgrades of services, releasing the next batch of
+// units once the current one is healthy or has timed out.
+func NewCharmUpgrader(st *state.State) worker.Worker {
+	return worker.NewPeriodicWorker(func(stop <-chan struct{}) error {
+		return advanceUpgrades(st)
+	}, interval)
+}
+
+func advanceUpgrades(st *state.State) error {
+	services, err := st.ServicesUpgradingCharm()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for _, service := range services {
+		logger.Debugf("checking charm upgrade of service %q", service)
+		if err := service.AdvanceCharmUpgrade(); errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
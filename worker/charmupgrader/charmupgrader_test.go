@@ -0,0 +1,65 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmupgrader_test
+
+import (
+	stdtesting "testing"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/charmupgrader"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type charmUpgraderSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&charmUpgraderSuite{})
+
+func (s *charmUpgraderSuite) TestReleasesBatches(c *gc.C) {
+	charmupgrader.SetInterval(10 * time.Millisecond)
+	defer charmupgrader.RestoreInterval()
+
+	oldCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Revision: "1"})
+	service := s.Factory.MakeService(c, &factory.ServiceParams{Name: "wordpress", Charm: oldCharm})
+	unit0 := s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, SetCharmURL: true})
+	s.Factory.MakeUnit(c, &factory.UnitParams{Service: service, SetCharmURL: true})
+	newCharm := s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress", Revision: "2"})
+	err := service.SetCharmInBatches(newCharm, false, s.AdminUserTag(c), state.CharmUpgradeArgs{BatchSize: 1})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := charmupgrader.NewCharmUpgrader(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// The first unit upgrades, releasing the second one.
+	err = unit0.SetCharmURL(newCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit0.SetAgentStatus(state.StatusActive, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit0.SetStatus(state.StatusRunning, "", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := service.Refresh()
+		c.Assert(err, jc.ErrorIsNil)
+		upgrade, err := service.CharmUpgrade()
+		c.Assert(err, jc.ErrorIsNil)
+		if len(upgrade.Held) == 0 {
+			c.Assert(upgrade.Batch, jc.DeepEquals, []string{"wordpress/1"})
+			return
+		}
+	}
+	c.Fatalf("batch not released")
+}
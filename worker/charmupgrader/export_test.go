@@ -0,0 +1,16 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package charmupgrader
+
+import (
+	"time"
+)
+
+func SetInterval(i time.Duration) {
+	interval = i
+}
+
+func RestoreInterval() {
+	interval = defaultInterval
+}
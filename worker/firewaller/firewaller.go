@@ -40,6 +40,7 @@ type Firewaller struct {
 	exposedChange   chan *exposedChange
 	globalMode      bool
 	globalPortRef   map[network.PortRange]int
+	instancePortRef map[names.MachineTag]map[network.PortRange]int
 	machinePorts    map[names.MachineTag]machineRanges
 }
 
@@ -47,13 +48,14 @@ type Firewaller struct {
 // depending on what the API supports.
 func NewFirewaller(st *apifirewaller.State) (_ worker.Worker, err error) {
 	fw := &Firewaller{
-		st:            st,
-		machineds:     make(map[names.MachineTag]*machineData),
-		unitsChange:   make(chan *unitsChange),
-		unitds:        make(map[names.UnitTag]*unitData),
-		serviceds:     make(map[names.ServiceTag]*serviceData),
-		exposedChange: make(chan *exposedChange),
-		machinePorts:  make(map[names.MachineTag]machineRanges),
+		st:              st,
+		machineds:       make(map[names.MachineTag]*machineData),
+		unitsChange:     make(chan *unitsChange),
+		unitds:          make(map[names.UnitTag]*unitData),
+		serviceds:       make(map[names.ServiceTag]*serviceData),
+		exposedChange:   make(chan *exposedChange),
+		instancePortRef: make(map[names.MachineTag]map[network.PortRange]int),
+		machinePorts:    make(map[names.MachineTag]machineRanges),
 	}
 	defer func() {
 		if err != nil {
@@ -330,10 +332,12 @@ func (fw *Firewaller) reconcileGlobal() error {
 
 // reconcileInstances compares the initially started watcher for machines,
 // units and services with the opened and closed ports of the instances and
-// opens and closes the appropriate ports for each instance.
+// opens and closes the appropriate ports for each instance. Ports opened
+// on containers are opened on their host's instance.
 func (fw *Firewaller) reconcileInstances() error {
+	hostTags := make(map[names.MachineTag]bool)
 	for _, machined := range fw.machineds {
-		m, err := machined.machine()
+		_, err := machined.machine()
 		if params.IsCodeNotFound(err) {
 			if err := fw.forgetMachine(machined); err != nil {
 				return err
@@ -342,29 +346,34 @@ func (fw *Firewaller) reconcileInstances() error {
 		} else if err != nil {
 			return err
 		}
-		instanceId, err := m.InstanceId()
-		if err != nil {
-			return err
-		}
-		instances, err := fw.environ.Instances([]instance.Id{instanceId})
+		hostTags[hostMachineTag(machined.tag)] = true
+	}
+	for hostTag := range hostTags {
+		inst, err := fw.hostInstance(hostTag)
 		if err == environs.ErrNoInstances {
 			return nil
+		} else if params.IsCodeNotFound(err) {
+			continue
 		} else if err != nil {
 			return err
 		}
-		machineId := machined.tag.Id()
-		initialPortRanges, err := instances[0].Ports(machineId)
+		hostId := hostTag.Id()
+		initialPortRanges, err := inst.Ports(hostId)
 		if err != nil {
 			return err
 		}
+		var want []network.PortRange
+		for portRange := range fw.instancePortRef[hostTag] {
+			want = append(want, portRange)
+		}
 
 		// Check which ports to open or to close.
-		toOpen := diffRanges(machined.openedPorts, initialPortRanges)
-		toClose := diffRanges(initialPortRanges, machined.openedPorts)
+		toOpen := diffRanges(want, initialPortRanges)
+		toClose := diffRanges(initialPortRanges, want)
 		if len(toOpen) > 0 {
 			logger.Infof("opening instance port ranges %v for %q",
-				toOpen, machined.tag)
-			if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+				toOpen, hostTag)
+			if err := inst.OpenPorts(hostId, toOpen); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
@@ -372,8 +381,8 @@ func (fw *Firewaller) reconcileInstances() error {
 		}
 		if len(toClose) > 0 {
 			logger.Infof("closing instance port ranges %v for %q",
-				toClose, machined.tag)
-			if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+				toClose, hostTag)
+			if err := inst.ClosePorts(hostId, toClose); err != nil {
 				// TODO(mue) Add local retry logic.
 				return err
 			}
@@ -427,6 +436,13 @@ func (fw *Firewaller) unitsChanged(change *unitsChange) error {
 // openedPortsChanged handles port change notifications
 func (fw *Firewaller) openedPortsChanged(machineTag names.MachineTag, networkTag names.NetworkTag) error {
 
+	if _, ok := fw.machineds[machineTag]; !ok && names.IsContainerMachine(machineTag.Id()) {
+		// Containers are not reported by the machines watcher, so
+		// start watching them once ports are opened on them.
+		if err := fw.machineLifeChanged(machineTag); err != nil {
+			return err
+		}
+	}
 	machined, ok := fw.machineds[machineTag]
 	if !ok {
 		// It is common to receive a port change notification before
@@ -558,34 +574,56 @@ func (fw *Firewaller) flushGlobalPorts(rawOpen, rawClose []network.PortRange) er
 	return nil
 }
 
-// flushInstancePorts opens and closes ports global on the machine.
-func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose []network.PortRange) error {
+// flushInstancePorts opens and closes ports global on the machine. Ports
+// of containers are opened and closed on their host's instance, so it
+// keeps a reference count for ports per instance so that only 0-to-1 and
+// 1-to-0 events modify the instance.
+func (fw *Firewaller) flushInstancePorts(machined *machineData, rawOpen, rawClose []network.PortRange) error {
 	// If there's nothing to do, do nothing.
 	// This is important because when a machine is first created,
 	// it will have no instance id but also no open ports -
 	// InstanceId will fail but we don't care.
+	if len(rawOpen) == 0 && len(rawClose) == 0 {
+		return nil
+	}
+	hostTag := hostMachineTag(machined.tag)
+	portRef := fw.instancePortRef[hostTag]
+	if portRef == nil {
+		portRef = make(map[network.PortRange]int)
+		fw.instancePortRef[hostTag] = portRef
+	}
+	// Filter which ports are really to open or close.
+	var toOpen, toClose []network.PortRange
+	for _, portRange := range rawOpen {
+		if portRef[portRange] == 0 {
+			toOpen = append(toOpen, portRange)
+		}
+		portRef[portRange]++
+	}
+	for _, portRange := range rawClose {
+		portRef[portRange]--
+		if portRef[portRange] <= 0 {
+			toClose = append(toClose, portRange)
+			delete(portRef, portRange)
+		}
+	}
+	if len(portRef) == 0 {
+		delete(fw.instancePortRef, hostTag)
+	}
 	if len(toOpen) == 0 && len(toClose) == 0 {
 		return nil
 	}
-	m, err := machined.machine()
+	inst, err := fw.hostInstance(hostTag)
 	if params.IsCodeNotFound(err) {
 		return nil
 	}
 	if err != nil {
 		return err
 	}
-	machineId := machined.tag.Id()
-	instanceId, err := m.InstanceId()
-	if err != nil {
-		return err
-	}
-	instances, err := fw.environ.Instances([]instance.Id{instanceId})
-	if err != nil {
-		return err
-	}
+	hostId := hostTag.Id()
 	// Open and close the ports.
 	if len(toOpen) > 0 {
-		if err := instances[0].OpenPorts(machineId, toOpen); err != nil {
+		if err := inst.OpenPorts(hostId, toOpen); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
@@ -593,7 +631,7 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 		logger.Infof("opened port ranges %v on %q", toOpen, machined.tag)
 	}
 	if len(toClose) > 0 {
-		if err := instances[0].ClosePorts(machineId, toClose); err != nil {
+		if err := inst.ClosePorts(hostId, toClose); err != nil {
 			// TODO(mue) Add local retry logic.
 			return err
 		}
@@ -603,6 +641,35 @@ func (fw *Firewaller) flushInstancePorts(machined *machineData, toOpen, toClose
 	return nil
 }
 
+// hostInstance returns the instance of the top-level machine with the
+// given tag.
+func (fw *Firewaller) hostInstance(hostTag names.MachineTag) (instance.Instance, error) {
+	m, err := fw.st.Machine(hostTag)
+	if err != nil {
+		return nil, err
+	}
+	instanceId, err := m.InstanceId()
+	if err != nil {
+		return nil, err
+	}
+	instances, err := fw.environ.Instances([]instance.Id{instanceId})
+	if err != nil {
+		return nil, err
+	}
+	return instances[0], nil
+}
+
+// hostMachineTag returns the tag of the top-level machine whose
+// instance has the ports opened on the machine with the given tag:
+// the outermost host for containers, the machine itself otherwise.
+func hostMachineTag(tag names.MachineTag) names.MachineTag {
+	id := tag.Id()
+	if !names.IsContainerMachine(id) {
+		return tag
+	}
+	return names.NewMachineTag(strings.SplitN(id, "/", 2)[0])
+}
+
 // machineLifeChanged starts watching new machines when the firewaller
 // is starting, or when new machines come to life, and stops watching
 // machines that are dying.
@@ -237,6 +237,51 @@ func (s *InstanceModeSuite) TestMultipleExposedServices(c *gc.C) {
 	s.assertPorts(c, inst2, m2.Id(), nil)
 }
 
+func (s *InstanceModeSuite) TestContainerPortsOpenedOnHost(c *gc.C) {
+	fw, err := firewaller.NewFirewaller(s.firewaller)
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertKillAndWait(c, fw)
+
+	svc1 := s.AddTestingService(c, "wordpress", s.charm)
+	err = svc1.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	u1, host := s.addUnit(c, svc1)
+	inst := s.startInstance(c, host)
+
+	svc2 := s.AddTestingService(c, "mysql", s.charm)
+	err = svc2.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	container, err := s.State.AddMachineInsideMachine(state.MachineTemplate{
+		Series: "quantal",
+		Jobs:   []state.MachineJob{state.JobHostUnits},
+	}, host.Id(), instance.LXC)
+	c.Assert(err, jc.ErrorIsNil)
+	u2, err := svc2.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.AssignToMachine(container)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Ports opened on the container end up on its host's instance.
+	err = u2.OpenPort("tcp", 3306)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, host.Id(), []network.PortRange{{80, 80, "tcp"}, {3306, 3306, "tcp"}})
+
+	// A port opened on both stays open until closed on both.
+	err = u1.OpenPort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.ClosePort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, host.Id(), []network.PortRange{{80, 80, "tcp"}, {3306, 3306, "tcp"}})
+
+	err = u1.ClosePort("tcp", 80)
+	c.Assert(err, jc.ErrorIsNil)
+	err = u2.ClosePort("tcp", 3306)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertPorts(c, inst, host.Id(), nil)
+}
+
 func (s *InstanceModeSuite) TestMachineWithoutInstanceId(c *gc.C) {
 	fw, err := firewaller.NewFirewaller(s.firewaller)
 	c.Assert(err, jc.ErrorIsNil)
@@ -81,9 +81,12 @@ func (a *aggregator) loop() error {
 				ids[i] = req.instId
 			}
 			insts, err := a.environ.Instances(ids)
+			reclaimed := a.reclaimedInstances(ids, insts, err)
 			for i, req := range reqs {
 				var reply instanceInfoReply
-				if err != nil && err != environs.ErrPartialInstances {
+				if reclaimed[req.instId] {
+					reply.info = instanceInfo{status: environs.ReclaimedInstanceStatus}
+				} else if err != nil && err != environs.ErrPartialInstances {
 					reply.err = err
 				} else {
 					reply.info, reply.err = a.instInfo(req.instId, insts[i])
@@ -95,6 +98,40 @@ func (a *aggregator) loop() error {
 	}
 }
 
+// reclaimedInstances returns the set of requested instance ids that
+// are missing from the result of an Instances call because the
+// provider has reclaimed them. It returns nil if the environ cannot
+// tell, or if no instances are missing.
+func (a *aggregator) reclaimedInstances(ids []instance.Id, insts []instance.Instance, err error) map[instance.Id]bool {
+	reclaimer, ok := a.environ.(environs.InstanceReclaimer)
+	if !ok {
+		return nil
+	}
+	var missing []instance.Id
+	switch err {
+	case environs.ErrNoInstances:
+		missing = ids
+	case environs.ErrPartialInstances:
+		for i, inst := range insts {
+			if inst == nil {
+				missing = append(missing, ids[i])
+			}
+		}
+	default:
+		return nil
+	}
+	reclaimedIds, err := reclaimer.ReclaimedInstances(missing)
+	if err != nil {
+		logger.Warningf("cannot check for reclaimed instances: %v", err)
+		return nil
+	}
+	reclaimed := make(map[instance.Id]bool)
+	for _, id := range reclaimedIds {
+		reclaimed[id] = true
+	}
+	return reclaimed
+}
+
 // instInfo returns the instance info for the given id
 // and instance. If inst is nil, it returns a not-found error.
 func (*aggregator) instInfo(id instance.Id, inst instance.Instance) (instanceInfo, error) {
@@ -227,3 +227,68 @@ func (s *aggregateSuite) TestKillAndWait(c *gc.C) {
 	err := aggregator.Wait()
 	c.Assert(err, jc.ErrorIsNil)
 }
+
+type reclaimingInstanceGetter struct {
+	testInstanceGetter
+	reclaimed    []instance.Id
+	reclaimErr   error
+	reclaimedIds []instance.Id
+}
+
+func (g *reclaimingInstanceGetter) ReclaimedInstances(ids []instance.Id) ([]instance.Id, error) {
+	g.reclaimedIds = ids
+	if g.reclaimErr != nil {
+		return nil, g.reclaimErr
+	}
+	var result []instance.Id
+	for _, id := range ids {
+		for _, reclaimed := range g.reclaimed {
+			if id == reclaimed {
+				result = append(result, id)
+			}
+		}
+	}
+	return result, nil
+}
+
+func (s *aggregateSuite) TestReclaimedInstance(c *gc.C) {
+	testGetter := &reclaimingInstanceGetter{reclaimed: []instance.Id{"foo"}}
+	testGetter.err = environs.ErrNoInstances
+
+	aggregator := newAggregator(testGetter)
+	info, err := aggregator.instanceInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, gc.DeepEquals, instanceInfo{status: environs.ReclaimedInstanceStatus})
+	c.Assert(testGetter.reclaimedIds, gc.DeepEquals, []instance.Id{"foo"})
+}
+
+func (s *aggregateSuite) TestMissingInstanceNotReclaimed(c *gc.C) {
+	testGetter := new(reclaimingInstanceGetter)
+	testGetter.err = environs.ErrPartialInstances
+
+	aggregator := newAggregator(testGetter)
+	_, err := aggregator.instanceInfo("foo")
+	c.Assert(err, gc.ErrorMatches, "instance foo not found")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(testGetter.reclaimedIds, gc.DeepEquals, []instance.Id{"foo"})
+}
+
+func (s *aggregateSuite) TestReclaimedInstancesError(c *gc.C) {
+	testGetter := &reclaimingInstanceGetter{reclaimErr: fmt.Errorf("no spot for you")}
+	testGetter.err = environs.ErrPartialInstances
+
+	aggregator := newAggregator(testGetter)
+	_, err := aggregator.instanceInfo("foo")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *aggregateSuite) TestReclaimedInstancesNotCheckedWhenAllFound(c *gc.C) {
+	testGetter := new(reclaimingInstanceGetter)
+	testGetter.newTestInstance("foo", "running", []string{"127.0.0.1"})
+
+	aggregator := newAggregator(testGetter)
+	info, err := aggregator.instanceInfo("foo")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.status, gc.Equals, "running")
+	c.Assert(testGetter.reclaimedIds, gc.IsNil)
+}
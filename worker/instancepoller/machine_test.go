@@ -17,6 +17,7 @@ import (
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -58,6 +59,35 @@ func (s *machineSuite) TestSetsInstanceInfoInitially(c *gc.C) {
 	c.Assert(m.instStatus, gc.Equals, "running")
 }
 
+func (s *machineSuite) TestSetsErrorStatusWhenInstanceReclaimed(c *gc.C) {
+	context := &testMachineContext{
+		getInstanceInfo: instanceInfoGetter(c, "i1234", nil, environs.ReclaimedInstanceStatus, nil),
+		dyingc:          make(chan struct{}),
+	}
+	m := &testMachine{
+		id:         "99",
+		instanceId: "i1234",
+		instStatus: "running",
+		refresh:    func() error { return nil },
+		addresses:  testAddrs,
+		life:       state.Alive,
+		status:     state.StatusStarted,
+	}
+	died := make(chan machine)
+	s.PatchValue(&ShortPoll, coretesting.ShortWait/10)
+	s.PatchValue(&LongPoll, coretesting.ShortWait/10)
+
+	go runMachine(context, m, nil, died)
+	time.Sleep(coretesting.ShortWait)
+
+	killMachineLoop(c, m, context.dyingc, died)
+	c.Assert(context.killAllErr, gc.Equals, nil)
+	c.Assert(m.instStatus, gc.Equals, environs.ReclaimedInstanceStatus)
+	c.Assert(m.setStatus, gc.Equals, state.StatusError)
+	c.Assert(m.setStatusInfo, gc.Equals, "instance reclaimed by the provider")
+	c.Assert(m.addresses, gc.HasLen, 0)
+}
+
 func (s *machineSuite) TestShortPollIntervalWhenNoAddress(c *gc.C) {
 	s.PatchValue(&ShortPoll, 1*time.Millisecond)
 	s.PatchValue(&LongPoll, coretesting.LongWait)
@@ -333,6 +363,8 @@ type testMachine struct {
 	life            state.Life
 	addresses       []network.Address
 	setAddressCount int
+	setStatus       state.Status
+	setStatusInfo   string
 }
 
 func (m *testMachine) Id() string {
@@ -381,6 +413,14 @@ func (m *testMachine) SetInstanceStatus(status string) error {
 	return nil
 }
 
+func (m *testMachine) SetStatus(status state.Status, info string, data map[string]interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.setStatus = status
+	m.setStatusInfo = info
+	return nil
+}
+
 func (m *testMachine) SetAddresses(addrs ...network.Address) error {
 	if m.setAddressesErr != nil {
 		return m.setAddressesErr
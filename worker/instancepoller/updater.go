@@ -10,6 +10,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 
+	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/state"
@@ -42,9 +43,14 @@ type machine interface {
 	Refresh() error
 	Life() state.Life
 	Status() (status state.Status, info string, data map[string]interface{}, err error)
+	SetStatus(status state.Status, info string, data map[string]interface{}) error
 	IsManual() (bool, error)
 }
 
+// reclaimedStatusInfo is the machine status message set when the
+// provider reclaims a machine's preemptible instance.
+const reclaimedStatusInfo = "instance reclaimed by the provider"
+
 type instanceInfo struct {
 	addresses []network.Address
 	status    string
@@ -258,6 +264,14 @@ func pollInstanceInfo(context machineContext, m machine) (instInfo instanceInfo,
 			if err = m.SetInstanceStatus(instInfo.status); err != nil {
 				logger.Errorf("cannot set instance status on %q: %v", m, err)
 			}
+			if instInfo.status == environs.ReclaimedInstanceStatus {
+				// The instance is gone for good; make sure the machine
+				// doesn't just look like its agent went away.
+				logger.Warningf("machine %q: %s", m.Id(), reclaimedStatusInfo)
+				if err = m.SetStatus(state.StatusError, reclaimedStatusInfo, nil); err != nil {
+					logger.Errorf("cannot set status on %q: %v", m, err)
+				}
+			}
 		}
 	}
 	if !addressesEqual(m.Addresses(), instInfo.addresses) {
@@ -25,11 +25,14 @@ type tracker struct {
 	duration    time.Duration
 	isMinion    bool
 
-	claimLease   chan struct{}
-	renewLease   <-chan time.Time
-	claimTickets chan chan bool
-	waitTickets  chan chan bool
-	waiting      []chan bool
+	claimLease    chan struct{}
+	lostLease     chan struct{}
+	renewLease    <-chan time.Time
+	claimTickets  chan chan bool
+	waitLeader    chan chan bool
+	waitMinion    chan chan bool
+	waitingLeader []chan bool
+	waitingMinion []chan bool
 }
 
 // NewTrackerWorker returns a TrackerWorker that attempts to claim and retain
@@ -49,12 +52,16 @@ func NewTrackerWorker(tag names.UnitTag, leadership leadership.LeadershipManager
 		leadership:   leadership,
 		duration:     duration,
 		claimTickets: make(chan chan bool),
-		waitTickets:  make(chan chan bool),
+		waitLeader:   make(chan chan bool),
+		waitMinion:   make(chan chan bool),
 	}
 	go func() {
 		defer t.tomb.Done()
 		defer func() {
-			for _, ticketCh := range t.waiting {
+			for _, ticketCh := range t.waitingLeader {
+				close(ticketCh)
+			}
+			for _, ticketCh := range t.waitingMinion {
 				close(ticketCh)
 			}
 		}()
@@ -90,7 +97,12 @@ func (t *tracker) ClaimLeader() Ticket {
 
 // WaitLeader is part of the Tracker interface.
 func (t *tracker) WaitLeader() Ticket {
-	return t.submit(t.waitTickets)
+	return t.submit(t.waitLeader)
+}
+
+// WaitMinion is part of the Tracker interface.
+func (t *tracker) WaitMinion() Ticket {
+	return t.submit(t.waitMinion)
 }
 
 func (t *tracker) loop() error {
@@ -114,14 +126,25 @@ func (t *tracker) loop() error {
 			if err := t.refresh(); err != nil {
 				return errors.Trace(err)
 			}
+		case <-t.lostLease:
+			logger.Infof("%s checking lease for %s leadership after losing it", t.unitName, t.serviceName)
+			t.lostLease = nil
+			if err := t.refresh(); err != nil {
+				return errors.Trace(err)
+			}
 		case ticketCh := <-t.claimTickets:
 			logger.Infof("%s got claim request for %s leadership", t.unitName, t.serviceName)
 			if err := t.resolveClaim(ticketCh); err != nil {
 				return errors.Trace(err)
 			}
-		case ticketCh := <-t.waitTickets:
+		case ticketCh := <-t.waitLeader:
 			logger.Infof("%s got wait request for %s leadership", t.unitName, t.serviceName)
-			if err := t.resolveWait(ticketCh); err != nil {
+			if err := t.resolveWaitLeader(ticketCh); err != nil {
+				return errors.Trace(err)
+			}
+		case ticketCh := <-t.waitMinion:
+			logger.Infof("%s got wait request for %s leadership loss", t.unitName, t.serviceName)
+			if err := t.resolveWaitMinion(ticketCh); err != nil {
 				return errors.Trace(err)
 			}
 		}
@@ -152,10 +175,26 @@ func (t *tracker) setLeader(untilTime time.Time) error {
 	t.isMinion = false
 	t.claimLease = nil
 	t.renewLease = time.After(renewTime.Sub(time.Now()))
+	if t.lostLease == nil {
+		// Leadership can be taken away before it's next renewed, when
+		// it's handed to another unit; find out as soon as it is.
+		t.lostLease = make(chan struct{})
+		go func() {
+			defer close(t.lostLease)
+			logger.Infof("%s waiting for %s leadership loss", t.unitName, t.serviceName)
+			err := t.leadership.BlockUntilLeadershipLost(t.serviceName, t.unitName, t.tomb.Dying())
+			if err != nil && err != leadership.ErrBlockCancelled {
+				logger.Warningf("error while %s waiting for %s leadership loss: %v", t.unitName, t.serviceName, err)
+			}
+			// As with the claimLease goroutine, closing the channel
+			// triggers a refresh on the main loop, which will find
+			// out what's really going on.
+		}()
+	}
 
-	for len(t.waiting) > 0 {
+	for len(t.waitingLeader) > 0 {
 		var ticketCh chan bool
-		ticketCh, t.waiting = t.waiting[0], t.waiting[1:]
+		ticketCh, t.waitingLeader = t.waitingLeader[0], t.waitingLeader[1:]
 		defer close(ticketCh)
 		if err := t.sendTrue(ticketCh); err != nil {
 			return errors.Trace(err)
@@ -174,18 +213,26 @@ func (t *tracker) setMinion() error {
 		go func() {
 			defer close(t.claimLease)
 			logger.Infof("%s waiting for %s leadership release", t.unitName, t.serviceName)
-			err := t.leadership.BlockUntilLeadershipReleased(t.serviceName)
-			if err != nil {
+			err := t.leadership.BlockUntilLeadershipReleased(t.serviceName, t.tomb.Dying())
+			if err != nil && err != leadership.ErrBlockCancelled {
 				logger.Warningf("error while %s waiting for %s leadership release: %v", t.unitName, t.serviceName, err)
 			}
 			// We don't need to do anything else with the error, because we just
 			// close the claimLease channel and trigger a leadership claim on the
 			// main loop; if anything's gone seriously wrong we'll find out right
-			// away and shut down anyway. (And if this goroutine outlives the
-			// tracker, it keeps it around as a zombie, but I don't see a way
-			// around that...)
+			// away and shut down anyway. The wait is cancelled when the tracker
+			// dies, so this goroutine does not outlive it.
 		}()
 	}
+
+	for len(t.waitingMinion) > 0 {
+		var ticketCh chan bool
+		ticketCh, t.waitingMinion = t.waitingMinion[0], t.waitingMinion[1:]
+		defer close(ticketCh)
+		if err := t.sendTrue(ticketCh); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	return nil
 }
 
@@ -224,12 +271,12 @@ func (t *tracker) resolveClaim(ticketCh chan bool) error {
 	return t.sendTrue(ticketCh)
 }
 
-// resolveWait will send true on the supplied channel if leadership can be
+// resolveWaitLeader will send true on the supplied channel if leadership can be
 // guaranteed for the tracker's duration. It will then close the channel. If
 // leadership cannot be guaranteed, the channel is left untouched until either
 // the termination of the tracker or the next invocation of setLeader; at which
 // point true is sent if applicable, and the channel is closed.
-func (t *tracker) resolveWait(ticketCh chan bool) error {
+func (t *tracker) resolveWaitLeader(ticketCh chan bool) error {
 	var dontClose bool
 	defer func() {
 		if !dontClose {
@@ -245,7 +292,33 @@ func (t *tracker) resolveWait(ticketCh chan bool) error {
 	}
 
 	logger.Infof("waiting for %s to attain %s leadership", t.unitName, t.serviceName)
-	t.waiting = append(t.waiting, ticketCh)
+	t.waitingLeader = append(t.waitingLeader, ticketCh)
+	dontClose = true
+	return nil
+}
+
+// resolveWaitMinion will send true on the supplied channel if leadership cannot
+// be guaranteed for the tracker's duration. It will then close the channel. If
+// leadership can be guaranteed, the channel is left untouched until either the
+// termination of the tracker or the next invocation of setMinion; at which
+// point true is sent if applicable, and the channel is closed.
+func (t *tracker) resolveWaitMinion(ticketCh chan bool) error {
+	var dontClose bool
+	defer func() {
+		if !dontClose {
+			close(ticketCh)
+		}
+	}()
+
+	if leader, err := t.isLeader(); err != nil {
+		return errors.Trace(err)
+	} else if !leader {
+		logger.Infof("reporting %s leadership loss for %s", t.serviceName, t.unitName)
+		return t.sendTrue(ticketCh)
+	}
+
+	logger.Infof("waiting for %s to lose %s leadership", t.unitName, t.serviceName)
+	t.waitingMinion = append(t.waitingMinion, ticketCh)
 	dontClose = true
 	return nil
 }
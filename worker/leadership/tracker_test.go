@@ -42,6 +42,7 @@ func (s *TrackerSuite) SetUpTest(c *gc.C) {
 	s.manager = &StubLeadershipManager{
 		Stub:     &testing.Stub{},
 		releases: make(chan struct{}),
+		losses:   make(chan struct{}),
 	}
 }
 
@@ -50,6 +51,7 @@ func (s *TrackerSuite) TearDownTest(c *gc.C) {
 		// It's not impossible that there's a goroutine waiting for a
 		// BlockUntilLeadershipReleased. Make sure it completes.
 		close(s.manager.releases)
+		close(s.manager.losses)
 		s.manager = nil
 	}
 }
@@ -168,6 +170,50 @@ func (s *TrackerSuite) TestLoseLeadership(c *gc.C) {
 	}})
 }
 
+func (s *TrackerSuite) TestLoseLeadershipBeforeRenewal(c *gc.C) {
+	s.manager.Stub.Errors = []error{nil, coreleadership.ErrClaimDenied, nil}
+	// Don't renew the lease during the test.
+	duration := time.Hour
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, duration)
+	defer assertStop(c, tracker)
+
+	// Check the first ticket succeeds.
+	assertClaimLeader(c, tracker, true)
+
+	// Hand leadership to another unit; the tracker notices straight
+	// away, and reports the loss.
+	select {
+	case s.manager.losses <- struct{}{}:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("tracker not waiting for leadership loss")
+	}
+	assertWaitMinion(c, tracker, true)
+	assertClaimLeader(c, tracker, false)
+
+	// Stop the tracker before trying to look at its stub.
+	assertStop(c, tracker)
+
+	// Unblock the release goroutine, lest data races.
+	s.unblockRelease(c)
+
+	s.manager.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", 2 * duration,
+		},
+	}, {
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", 2 * duration,
+		},
+	}, {
+		FuncName: "BlockUntilLeadershipReleased",
+		Args: []interface{}{
+			"led-service",
+		},
+	}})
+}
+
 func (s *TrackerSuite) TestGainLeadership(c *gc.C) {
 	s.manager.Stub.Errors = []error{coreleadership.ErrClaimDenied, nil, nil}
 	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
@@ -343,6 +389,87 @@ func (s *TrackerSuite) TestWaitLeaderNeverBecomeLeader(c *gc.C) {
 	}})
 }
 
+func (s *TrackerSuite) TestWaitMinionAlreadyMinion(c *gc.C) {
+	s.manager.Stub.Errors = []error{coreleadership.ErrClaimDenied, nil}
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Check the ticket succeeds.
+	assertWaitMinion(c, tracker, true)
+
+	// Stop the tracker before trying to look at its stub.
+	assertStop(c, tracker)
+
+	// Unblock the release goroutine, lest data races.
+	s.unblockRelease(c)
+
+	s.manager.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "BlockUntilLeadershipReleased",
+		Args: []interface{}{
+			"led-service",
+		},
+	}})
+}
+
+func (s *TrackerSuite) TestWaitMinionBecomeMinion(c *gc.C) {
+	s.manager.Stub.Errors = []error{nil, coreleadership.ErrClaimDenied, nil}
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Check the initial ticket is not ready while we're leader, waiting not
+	// quite long enough to trigger a refresh...
+	ticket := tracker.WaitMinion()
+	select {
+	case <-time.After(refreshes(0)):
+	case <-ticket.Ready():
+		c.Fatalf("got unexpected readiness: %v", ticket.Wait())
+	}
+
+	// ...and succeeds once the refresh triggers ErrClaimDenied.
+	assertTicket(c, ticket, true)
+
+	// Stop the tracker before trying to look at its stub.
+	assertStop(c, tracker)
+
+	// Unblock the release goroutine, lest data races.
+	s.unblockRelease(c)
+
+	s.manager.CheckCalls(c, []testing.StubCall{{
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "ClaimLeadership",
+		Args: []interface{}{
+			"led-service", "led-service/123", leaseDuration,
+		},
+	}, {
+		FuncName: "BlockUntilLeadershipReleased",
+		Args: []interface{}{
+			"led-service",
+		},
+	}})
+}
+
+func (s *TrackerSuite) TestWaitMinionNeverBecomeMinion(c *gc.C) {
+	tracker := leadership.NewTrackerWorker(s.unitTag, s.manager, trackerDuration)
+	defer assertStop(c, tracker)
+
+	// Get a ticket and stop the tracker while it's pending.
+	ticket := tracker.WaitMinion()
+	assertStop(c, tracker)
+
+	// Check the ticket got closed without sending true.
+	assertTicket(c, ticket, false)
+	assertTicket(c, ticket, false)
+}
+
 func assertClaimLeader(c *gc.C, tracker leadership.Tracker, expect bool) {
 	// Grab a ticket...
 	ticket := tracker.ClaimLeader()
@@ -366,6 +493,20 @@ func assertWaitLeader(c *gc.C, tracker leadership.Tracker, expect bool) {
 	}
 }
 
+func assertWaitMinion(c *gc.C, tracker leadership.Tracker, expect bool) {
+	ticket := tracker.WaitMinion()
+	if expect {
+		assertTicket(c, ticket, true)
+		assertTicket(c, ticket, true)
+		return
+	}
+	select {
+	case <-time.After(coretesting.ShortWait):
+	case <-ticket.Ready():
+		c.Fatalf("got unexpected readiness: %v", ticket.Wait())
+	}
+}
+
 func assertTicket(c *gc.C, ticket leadership.Ticket, expect bool) {
 	// Wait for the ticket to give a value...
 	select {
@@ -15,6 +15,7 @@ type StubLeadershipManager struct {
 	leadership.LeadershipManager
 	*testing.Stub
 	releases chan struct{}
+	losses   chan struct{}
 }
 
 func (stub *StubLeadershipManager) ClaimLeadership(serviceName, unitName string, duration time.Duration) error {
@@ -22,8 +23,23 @@ func (stub *StubLeadershipManager) ClaimLeadership(serviceName, unitName string,
 	return stub.NextErr()
 }
 
-func (stub *StubLeadershipManager) BlockUntilLeadershipReleased(serviceName string) error {
+func (stub *StubLeadershipManager) BlockUntilLeadershipReleased(serviceName string, cancel <-chan struct{}) error {
 	stub.MethodCall(stub, "BlockUntilLeadershipReleased", serviceName)
-	<-stub.releases
+	select {
+	case <-stub.releases:
+	case <-cancel:
+		return leadership.ErrBlockCancelled
+	}
 	return stub.NextErr()
 }
+
+// BlockUntilLeadershipLost is not recorded as a call, because it's made
+// concurrently with the tracker's claims whenever it becomes leader.
+func (stub *StubLeadershipManager) BlockUntilLeadershipLost(serviceName, unitName string, cancel <-chan struct{}) error {
+	select {
+	case <-stub.losses:
+	case <-cancel:
+		return leadership.ErrBlockCancelled
+	}
+	return nil
+}
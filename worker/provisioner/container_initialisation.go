@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/container"
 	"github.com/juju/juju/container/kvm"
 	"github.com/juju/juju/container/lxc"
+	"github.com/juju/juju/container/lxd"
 	"github.com/juju/juju/environs"
 	"github.com/juju/juju/instance"
 	"github.com/juju/juju/state"
@@ -164,6 +165,14 @@ func (cs *ContainerSetup) TearDown() error {
 	return nil
 }
 
+// startPortForwarder starts the worker forwarding the ports opened on
+// the machine's containers to them, unless it is already running.
+func (cs *ContainerSetup) startPortForwarder() error {
+	return cs.runner.StartWorker(portForwarderWorkerName, func() (worker.Worker, error) {
+		return NewContainerPortForwarder(cs.machine), nil
+	})
+}
+
 // getContainerArtifacts returns type-specific interfaces for
 // managing containers.
 //
@@ -184,6 +193,12 @@ func (cs *ContainerSetup) getContainerArtifacts(
 	if err != nil {
 		return nil, nil, nil, err
 	}
+	if managerConfig.PopValue(container.ConfigPortForwarding) != "" {
+		// The containers can only be reached through this machine.
+		if err := cs.startPortForwarder(); err != nil {
+			return nil, nil, nil, err
+		}
+	}
 
 	switch containerType {
 	case instance.LXC:
@@ -211,6 +226,23 @@ func (cs *ContainerSetup) getContainerArtifacts(
 			logger.Errorf("failed to create new kvm broker")
 			return nil, nil, nil, err
 		}
+
+	case instance.LXD:
+		series, err := cs.machine.Series()
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		initialiser = lxd.NewContainerInitialiser(series)
+		broker, err = NewLxdBroker(cs.provisioner, cs.config, managerConfig, cs.imageURLGetter)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		// Like LXC, LXD containers must have the same architecture
+		// as the host.
+		toolsFinder = hostArchToolsFinder{toolsFinder}
+
 	default:
 		return nil, nil, nil, fmt.Errorf("unknown container type: %v", containerType)
 	}
@@ -251,6 +283,10 @@ func containerManagerConfig(
 	return managerConfig, nil
 }
 
+// portForwarderWorkerName is the name of the worker forwarding ports
+// to containers on the machine.
+const portForwarderWorkerName = "container-port-forwarder"
+
 // Override for testing.
 var (
 	StartProvisioner = startProvisionerWorker
@@ -169,7 +169,7 @@ func (s *ContainerSetupSuite) TestContainerProvisionerStarted(c *gc.C) {
 			Constraints: s.defaultConstraints,
 		})
 		c.Assert(err, jc.ErrorIsNil)
-		err = m.SetSupportedContainers([]instance.ContainerType{instance.LXC, instance.KVM})
+		err = m.SetSupportedContainers(instance.ContainerTypes)
 		c.Assert(err, jc.ErrorIsNil)
 		err = m.SetAgentVersion(version.Current)
 		c.Assert(err, jc.ErrorIsNil)
@@ -184,6 +184,13 @@ func (s *ContainerSetupSuite) TestLxcContainerUsesConstraintsArch(c *gc.C) {
 	s.testContainerConstraintsArch(c, instance.LXC, arch.PPC64EL)
 }
 
+func (s *ContainerSetupSuite) TestLxdContainerUsesConstraintsArch(c *gc.C) {
+	// LXD should override the architecture in constraints with the
+	// host's architecture.
+	s.PatchValue(&version.Current.Arch, arch.PPC64EL)
+	s.testContainerConstraintsArch(c, instance.LXD, arch.PPC64EL)
+}
+
 func (s *ContainerSetupSuite) TestKvmContainerUsesHostArch(c *gc.C) {
 	// KVM should do what it's told, and use the architecture in
 	// constraints.
@@ -260,6 +267,33 @@ func (s *ContainerSetupSuite) TestLxcContainerUsesImageURL(c *gc.C) {
 
 }
 
+func (s *ContainerSetupSuite) TestLxdContainerUsesImageURL(c *gc.C) {
+	// create a machine to host the container.
+	m, err := s.BackingState.AddOneMachine(state.MachineTemplate{
+		Series:      coretesting.FakeDefaultSeries,
+		Jobs:        []state.MachineJob{state.JobHostUnits},
+		Constraints: s.defaultConstraints,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetSupportedContainers([]instance.ContainerType{instance.LXD})
+	c.Assert(err, jc.ErrorIsNil)
+	err = m.SetAgentVersion(version.Current)
+	c.Assert(err, jc.ErrorIsNil)
+
+	brokerCalled := false
+	newlxdbroker := func(api provisioner.APICalls, agentConfig agent.Config, managerConfig container.ManagerConfig,
+		imageURLGetter container.ImageURLGetter) (environs.InstanceBroker, error) {
+		imageURL, err := imageURLGetter.ImageURL(instance.LXC, "trusty", "amd64")
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(imageURL, gc.Equals, "imageURL")
+		brokerCalled = true
+		return nil, fmt.Errorf("lxd broker error")
+	}
+	s.PatchValue(&provisioner.NewLxdBroker, newlxdbroker)
+	s.createContainer(c, m, instance.LXD)
+	c.Assert(brokerCalled, jc.IsTrue)
+}
+
 func (s *ContainerSetupSuite) TestContainerManagerConfigName(c *gc.C) {
 	pr := s.st.Provisioner()
 	expect := func(expect string) {
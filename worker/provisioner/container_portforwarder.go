@@ -0,0 +1,224 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"text/template"
+
+	"github.com/juju/errors"
+
+	apiprovisioner "github.com/juju/juju/api/provisioner"
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/worker"
+)
+
+// PortForwardingMachine is the host machine as seen by the container
+// port forwarder.
+type PortForwardingMachine interface {
+	WatchContainerPorts() (apiwatcher.NotifyWatcher, error)
+	PortForwards() ([]params.PortForward, error)
+}
+
+var _ PortForwardingMachine = (*apiprovisioner.Machine)(nil)
+
+// portForwardChain is the iptables chain in the nat table holding the
+// rules forwarding ports on the host to its containers. The rules are
+// built in newPortForwardChain, which then replaces it.
+const (
+	portForwardChain    = "juju-port-forward"
+	newPortForwardChain = "juju-port-forward-new"
+)
+
+// portForwardHooks are the nat table chains sending traffic for the
+// host address through the forwarding chain: PREROUTING for traffic
+// arriving from other machines, and OUTPUT for traffic starting on the
+// host itself.
+var portForwardHooks = []string{"PREROUTING", "OUTPUT"}
+
+var (
+	// iptablesNewChain is the command template to create the chain
+	// named .Chain. Exit code 0 means the chain was created, 1 means
+	// it already exists.
+	iptablesNewChain = mustParseTemplate("iptablesNewChain", `
+iptables -t nat -N {{.Chain}}`[1:])
+
+	// iptablesFlushChain is the command template to remove all rules
+	// from the chain named .Chain. Exit code 1 means the chain does
+	// not exist.
+	iptablesFlushChain = mustParseTemplate("iptablesFlushChain", `
+iptables -t nat -F {{.Chain}}`[1:])
+
+	// iptablesDeleteChain is the command template to delete the empty
+	// chain named .Chain. Exit code 1 means the chain does not exist.
+	iptablesDeleteChain = mustParseTemplate("iptablesDeleteChain", `
+iptables -t nat -X {{.Chain}}`[1:])
+
+	// iptablesRenameChain is the command template to rename the chain
+	// named .Chain to .NewName. Rules jumping to the chain follow it.
+	iptablesRenameChain = mustParseTemplate("iptablesRenameChain", `
+iptables -t nat -E {{.Chain}} {{.NewName}}`[1:])
+
+	// iptablesInsertJump is the command template to insert, ahead of
+	// any other rules in the chain named .Hook, the rule sending
+	// traffic for the host address .HostIP through the chain named
+	// .Chain.
+	iptablesInsertJump = mustParseTemplate("iptablesInsertJump", `
+iptables -t nat -I {{.Hook}} -d {{.HostIP}} -j {{.Chain}}`[1:])
+
+	// iptablesDeleteJump is the command template to delete the rule
+	// added by iptablesInsertJump. Exit code 1 means the rule does not
+	// exist.
+	iptablesDeleteJump = mustParseTemplate("iptablesDeleteJump", `
+iptables -t nat -D {{.Hook}} -d {{.HostIP}} -j {{.Chain}}`[1:])
+
+	// iptablesAddPortForward is the command template to add a rule
+	// to the chain named .Chain forwarding the .Protocol ports
+	// .FromPort to .ToPort to the same ports on .ContainerIP.
+	iptablesAddPortForward = mustParseTemplate("iptablesAddPortForward", `
+iptables -t nat -A {{.Chain}} -p {{.Protocol}} --dport {{.FromPort}}:{{.ToPort}} -j DNAT --to-destination {{.ContainerIP}}`[1:])
+)
+
+// NewContainerPortForwarder returns a worker which forwards the ports
+// opened on the host machine's containers to them, so containers which
+// cannot be given addresses of their own can be reached from other
+// machines through the host.
+func NewContainerPortForwarder(machine PortForwardingMachine) worker.Worker {
+	return worker.NewNotifyWorker(&portForwarder{machine: machine})
+}
+
+type portForwarder struct {
+	machine  PortForwardingMachine
+	hostAddr network.Address
+}
+
+// SetUp is defined on the worker.NotifyWatchHandler interface.
+func (pf *portForwarder) SetUp() (apiwatcher.NotifyWatcher, error) {
+	_, hostAddr, err := discoverPrimaryNIC()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	pf.hostAddr = hostAddr
+	return pf.machine.WatchContainerPorts()
+}
+
+// Handle is defined on the worker.NotifyWatchHandler interface.
+func (pf *portForwarder) Handle() error {
+	forwards, err := pf.machine.PortForwards()
+	if err != nil {
+		return errors.Annotate(err, "cannot get ports to forward")
+	}
+	return setupPortForwards(pf.hostAddr, forwards)
+}
+
+// TearDown is defined on the worker.NotifyWatchHandler interface.
+func (pf *portForwarder) TearDown() error {
+	// The forwarding rules are left in place, so the containers stay
+	// reachable while the machine agent restarts.
+	return nil
+}
+
+// setupPortForwards replaces the iptables rules forwarding ports on
+// the host address hostAddr with rules for the given forwards. A port
+// can only be forwarded to one container; conflicting forwards are
+// logged and skipped.
+//
+// The new rules are built in a separate chain, which is put in front
+// of the existing one before that is removed, so the ports which stay
+// forwarded are never left unforwarded.
+var setupPortForwards = func(hostAddr network.Address, forwards []params.PortForward) error {
+	if hostAddr.Value == "" {
+		return errors.Errorf("host address must be set")
+	}
+	type chainData struct {
+		Chain   string
+		NewName string
+		Hook    string
+		HostIP  string
+	}
+	newChain := chainData{Chain: newPortForwardChain, HostIP: hostAddr.Value}
+	oldChain := chainData{Chain: portForwardChain, HostIP: hostAddr.Value}
+
+	// Build the new chain, which may be left over from an earlier
+	// failed attempt.
+	if err := runIPTables(iptablesNewChain, newChain); err != nil {
+		return errors.Trace(err)
+	}
+	if err := runIPTables(iptablesFlushChain, newChain); err != nil {
+		return errors.Trace(err)
+	}
+	var forwarded []params.PortForward
+	for _, fwd := range forwards {
+		portRange := fwd.PortRange.NetworkPortRange()
+		conflicting := false
+		for _, prev := range forwarded {
+			if prev.PortRange.NetworkPortRange().ConflictsWith(portRange) {
+				logger.Warningf(
+					"cannot forward port range %v to %q: conflicts with port range %v forwarded to %q",
+					portRange, fwd.ContainerTag, prev.PortRange.NetworkPortRange(), prev.ContainerTag,
+				)
+				conflicting = true
+				break
+			}
+		}
+		if conflicting {
+			continue
+		}
+		ruleData := struct {
+			Chain       string
+			Protocol    string
+			FromPort    int
+			ToPort      int
+			ContainerIP string
+		}{newPortForwardChain, portRange.Protocol, portRange.FromPort, portRange.ToPort, fwd.ContainerAddress}
+		if _, err := runTemplateCommand(iptablesAddPortForward, false, ruleData); err != nil {
+			return errors.Trace(err)
+		}
+		forwarded = append(forwarded, fwd)
+	}
+
+	// Send traffic through the new chain ahead of the old one, then
+	// remove the old one and give the new one its name.
+	for _, hook := range portForwardHooks {
+		newChain.Hook = hook
+		if _, err := runTemplateCommand(iptablesInsertJump, false, newChain); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, hook := range portForwardHooks {
+		oldChain.Hook = hook
+		if err := runIPTables(iptablesDeleteJump, oldChain); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for _, t := range []*template.Template{iptablesFlushChain, iptablesDeleteChain} {
+		if err := runIPTables(t, oldChain); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	newChain.NewName = portForwardChain
+	if _, err := runTemplateCommand(iptablesRenameChain, false, newChain); err != nil {
+		return errors.Trace(err)
+	}
+	logger.Infof("forwarding %d port range(s) to containers", len(forwarded))
+	return nil
+}
+
+// runIPTables runs the iptables command template t with the given
+// data. Exit code 1, reported by iptables when the chain or rule to
+// create already exists, or the one to remove does not, is not
+// treated as an error.
+func runIPTables(t *template.Template, data interface{}) error {
+	code, err := runTemplateCommand(t, true, data)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	switch code {
+	case 0, 1:
+		return nil
+	}
+	// Unexpected code - better report it.
+	return errors.Errorf("iptables failed with unexpected exit code %d", code)
+}
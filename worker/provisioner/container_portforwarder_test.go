@@ -0,0 +1,250 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"launchpad.net/tomb"
+
+	apiwatcher "github.com/juju/juju/api/watcher"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/network"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type portForwarderSuite struct {
+	coretesting.BaseSuite
+
+	iptablesLog string
+}
+
+var _ = gc.Suite(&portForwarderSuite{})
+
+func (s *portForwarderSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Skipping port forwarding tests on windows")
+	}
+	s.BaseSuite.SetUpTest(c)
+
+	// Isolate the test from the host machine. Patch iptables with a
+	// script which logs its arguments and returns code=1 for deletes,
+	// as when there is no old chain to replace.
+	s.iptablesLog = filepath.Join(c.MkDir(), "iptables.log")
+	script := fmt.Sprintf(
+		`echo "$@" >> %q; if [[ "$3" == "-D" ]]; then exit 1; fi`,
+		s.iptablesLog,
+	)
+	gitjujutesting.PatchExecutable(c, s, "iptables", script)
+}
+
+func (s *portForwarderSuite) assertIPTablesCalls(c *gc.C, expected ...string) {
+	data, err := ioutil.ReadFile(s.iptablesLog)
+	c.Assert(err, jc.ErrorIsNil)
+	calls := strings.Split(strings.TrimSpace(string(data)), "\n")
+	c.Assert(calls, jc.DeepEquals, expected)
+}
+
+// setupCalls returns the iptables calls expected to set up the given
+// port forwarding rules in the new chain and swap it in.
+func setupCalls(rules ...string) []string {
+	calls := []string{
+		"-t nat -N juju-port-forward-new",
+		"-t nat -F juju-port-forward-new",
+	}
+	calls = append(calls, rules...)
+	return append(calls,
+		"-t nat -I PREROUTING -d 0.1.2.1 -j juju-port-forward-new",
+		"-t nat -I OUTPUT -d 0.1.2.1 -j juju-port-forward-new",
+		"-t nat -D PREROUTING -d 0.1.2.1 -j juju-port-forward",
+		"-t nat -D OUTPUT -d 0.1.2.1 -j juju-port-forward",
+		"-t nat -F juju-port-forward",
+		"-t nat -X juju-port-forward",
+		"-t nat -E juju-port-forward-new juju-port-forward",
+	)
+}
+
+func (s *portForwarderSuite) TestSetupPortForwards(c *gc.C) {
+	forwards := []params.PortForward{{
+		ContainerTag:     "machine-0-lxc-0",
+		ContainerAddress: "10.0.3.5",
+		PortRange:        params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	}, {
+		ContainerTag:     "machine-0-lxc-1",
+		ContainerAddress: "10.0.3.6",
+		PortRange:        params.PortRange{FromPort: 53, ToPort: 53, Protocol: "udp"},
+	}, {
+		ContainerTag:     "machine-0-lxc-1",
+		ContainerAddress: "10.0.3.6",
+		PortRange:        params.PortRange{FromPort: 8000, ToPort: 8080, Protocol: "tcp"},
+	}}
+	addr := network.NewAddress("0.1.2.1", network.ScopeUnknown)
+	err := provisioner.SetupPortForwards(addr, forwards)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIPTablesCalls(c, setupCalls(
+		"-t nat -A juju-port-forward-new -p tcp --dport 80:80 -j DNAT --to-destination 10.0.3.5",
+		"-t nat -A juju-port-forward-new -p udp --dport 53:53 -j DNAT --to-destination 10.0.3.6",
+		"-t nat -A juju-port-forward-new -p tcp --dport 8000:8080 -j DNAT --to-destination 10.0.3.6",
+	)...)
+}
+
+func (s *portForwarderSuite) TestSetupPortForwardsSkipsConflicts(c *gc.C) {
+	forwards := []params.PortForward{{
+		ContainerTag:     "machine-0-lxc-0",
+		ContainerAddress: "10.0.3.5",
+		PortRange:        params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+	}, {
+		ContainerTag:     "machine-0-lxc-1",
+		ContainerAddress: "10.0.3.6",
+		PortRange:        params.PortRange{FromPort: 70, ToPort: 90, Protocol: "tcp"},
+	}}
+	addr := network.NewAddress("0.1.2.1", network.ScopeUnknown)
+	err := provisioner.SetupPortForwards(addr, forwards)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertIPTablesCalls(c, setupCalls(
+		"-t nat -A juju-port-forward-new -p tcp --dport 80:80 -j DNAT --to-destination 10.0.3.5",
+	)...)
+	c.Assert(c.GetTestLog(), jc.Contains,
+		`cannot forward port range 70-90/tcp to "machine-0-lxc-1": conflicts with port range 80/tcp forwarded to "machine-0-lxc-0"`,
+	)
+}
+
+func (s *portForwarderSuite) TestSetupPortForwardsWithoutHostAddress(c *gc.C) {
+	err := provisioner.SetupPortForwards(network.Address{}, nil)
+	c.Assert(err, gc.ErrorMatches, "host address must be set")
+}
+
+func (s *portForwarderSuite) TestSetupPortForwardsIPTablesError(c *gc.C) {
+	gitjujutesting.PatchExecutableThrowError(c, s, "iptables", 42)
+
+	addr := network.NewAddress("0.1.2.1", network.ScopeUnknown)
+	err := provisioner.SetupPortForwards(addr, nil)
+	c.Assert(err, gc.ErrorMatches, "iptables failed with unexpected exit code 42")
+}
+
+func (s *portForwarderSuite) TestWorkerForwardsPortsOnChange(c *gc.C) {
+	s.PatchValue(provisioner.NetInterfaces, func() ([]net.Interface, error) {
+		return []net.Interface{{
+			Index: 0,
+			Name:  "eth0",
+			Flags: net.FlagUp,
+		}}, nil
+	})
+	s.PatchValue(provisioner.InterfaceAddrs, func(i *net.Interface) ([]net.Addr, error) {
+		return []net.Addr{&fakeAddr{"0.1.2.1/24"}}, nil
+	})
+	machine := &fakePortForwardingMachine{
+		watcher: newFakeNotifyWatcher(),
+		forwards: []params.PortForward{{
+			ContainerTag:     "machine-0-lxc-0",
+			ContainerAddress: "10.0.3.5",
+			PortRange:        params.PortRange{FromPort: 80, ToPort: 80, Protocol: "tcp"},
+		}},
+		called: make(chan struct{}, 1),
+	}
+	w := provisioner.NewContainerPortForwarder(machine)
+	defer func() {
+		w.Kill()
+		c.Assert(w.Wait(), jc.ErrorIsNil)
+	}()
+
+	machine.watcher.changes <- struct{}{}
+	select {
+	case <-machine.called:
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for port forwards")
+	}
+	// PortForwards is called before the rules are set up, so wait
+	// for the worker to finish handling the change.
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		data, _ := ioutil.ReadFile(s.iptablesLog)
+		if strings.Count(string(data), "\n") == len(setupCalls())+1 {
+			break
+		}
+	}
+	s.assertIPTablesCalls(c, setupCalls(
+		"-t nat -A juju-port-forward-new -p tcp --dport 80:80 -j DNAT --to-destination 10.0.3.5",
+	)...)
+}
+
+func (s *portForwarderSuite) TestWorkerFailsOnPortForwardsError(c *gc.C) {
+	s.PatchValue(provisioner.NetInterfaces, func() ([]net.Interface, error) {
+		return []net.Interface{{
+			Index: 0,
+			Name:  "eth0",
+			Flags: net.FlagUp,
+		}}, nil
+	})
+	s.PatchValue(provisioner.InterfaceAddrs, func(i *net.Interface) ([]net.Addr, error) {
+		return []net.Addr{&fakeAddr{"0.1.2.1/24"}}, nil
+	})
+	machine := &fakePortForwardingMachine{
+		watcher: newFakeNotifyWatcher(),
+		err:     errors.New("boom!"),
+		called:  make(chan struct{}, 1),
+	}
+	w := provisioner.NewContainerPortForwarder(machine)
+	defer w.Kill()
+
+	machine.watcher.changes <- struct{}{}
+	c.Assert(w.Wait(), gc.ErrorMatches, "cannot get ports to forward: boom!")
+}
+
+type fakePortForwardingMachine struct {
+	watcher  *fakeNotifyWatcher
+	forwards []params.PortForward
+	err      error
+	called   chan struct{}
+}
+
+var _ provisioner.PortForwardingMachine = (*fakePortForwardingMachine)(nil)
+
+func (m *fakePortForwardingMachine) WatchContainerPorts() (apiwatcher.NotifyWatcher, error) {
+	return m.watcher, nil
+}
+
+func (m *fakePortForwardingMachine) PortForwards() ([]params.PortForward, error) {
+	m.called <- struct{}{}
+	return m.forwards, m.err
+}
+
+type fakeNotifyWatcher struct {
+	tomb    tomb.Tomb
+	changes chan struct{}
+}
+
+func newFakeNotifyWatcher() *fakeNotifyWatcher {
+	w := &fakeNotifyWatcher{changes: make(chan struct{})}
+	go func() {
+		defer w.tomb.Done()
+		<-w.tomb.Dying()
+	}()
+	return w
+}
+
+func (w *fakeNotifyWatcher) Changes() <-chan struct{} {
+	return w.changes
+}
+
+func (w *fakeNotifyWatcher) Stop() error {
+	w.tomb.Kill(nil)
+	return w.tomb.Wait()
+}
+
+func (w *fakeNotifyWatcher) Err() error {
+	return w.tomb.Err()
+}
@@ -36,6 +36,7 @@ var (
 	InterfaceAddrs         = &interfaceAddrs
 	DiscoverPrimaryNIC     = discoverPrimaryNIC
 	MaybeAllocateStaticIP  = maybeAllocateStaticIP
+	SetupPortForwards      = setupPortForwards
 )
 
 const (
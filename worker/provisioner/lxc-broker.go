@@ -36,6 +36,7 @@ var _ environs.InstanceBroker = (*lxcBroker)(nil)
 type APICalls interface {
 	ContainerConfig() (params.ContainerConfig, error)
 	PrepareContainerInterfaceInfo(names.MachineTag) ([]network.InterfaceInfo, error)
+	PrepareContainerPortForwarding(names.MachineTag) error
 }
 
 var _ APICalls = (*apiprovisioner.State)(nil)
@@ -130,7 +131,7 @@ func (broker *lxcBroker) StartInstance(args environs.StartInstanceParams) (*envi
 		return nil, err
 	}
 
-	inst, hardware, err := broker.manager.CreateContainer(args.MachineConfig, series, network)
+	inst, hardware, err := broker.manager.CreateContainer(args.MachineConfig, args.Constraints, series, network)
 	if err != nil {
 		lxcLogger.Errorf("failed to start container: %v", err)
 		return nil, err
@@ -422,6 +423,21 @@ func maybeAllocateStaticIP(
 		return nil, errors.Trace(err)
 	}
 	logger.Debugf("PrepareContainerInterfaceInfo returned %#v", finalIfaceInfo)
+	if len(finalIfaceInfo) == 0 {
+		// No address was allocated, so the container will use the
+		// host's bridge. Only on the NAT bridge it cannot be reached
+		// from other machines, and has to be reached through this
+		// machine instead.
+		if bridgeDevice != lxc.DefaultLxcBridge {
+			logger.Infof("container %q will use its own addresses on bridge %q", containerId, bridgeDevice)
+			return nil, nil
+		}
+		if err := apiFacade.PrepareContainerPortForwarding(names.NewMachineTag(containerId)); err != nil {
+			return nil, errors.Trace(err)
+		}
+		logger.Infof("container %q will use the host's bridge, with ports forwarded to it", containerId)
+		return nil, nil
+	}
 
 	// Populate ConfigType and DNSServers as needed.
 	var dnsServers []network.Address
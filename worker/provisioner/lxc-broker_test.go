@@ -144,6 +144,25 @@ func (s *lxcBrokerSuite) TestStartInstance(c *gc.C) {
 	c.Assert(string(lxcConfContents), jc.Contains, "lxc.network.link = lxcbr0")
 }
 
+func (s *lxcBrokerSuite) TestStartInstanceWithConstraints(c *gc.C) {
+	machineConfig := s.machineConfig(c, "1/lxc/0")
+	possibleTools := coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+	}}
+	result, err := s.broker.StartInstance(environs.StartInstanceParams{
+		Constraints:   constraints.MustParse("mem=1G cpu-cores=2"),
+		Tools:         possibleTools,
+		MachineConfig: machineConfig,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Hardware.String(), gc.Equals, "arch=amd64 cpu-cores=2 mem=1024M")
+
+	lxcConfContents, err := ioutil.ReadFile(filepath.Join(s.LxcDir, string(result.Instance.Id()), "config"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(lxcConfContents), jc.Contains, "lxc.cgroup.memory.limit_in_bytes = 1024M")
+}
+
 func (s *lxcBrokerSuite) TestStartInstanceHostArch(c *gc.C) {
 	machineConfig := s.machineConfig(c, "1/lxc/0")
 
@@ -706,6 +725,51 @@ func (s *lxcBrokerSuite) TestMaybeAllocateStaticIP(c *gc.C) {
 	}})
 }
 
+func (s *lxcBrokerSuite) TestMaybeAllocateStaticIPWithForwarding(c *gc.C) {
+	s.PatchValue(provisioner.NetInterfaces, func() ([]net.Interface, error) {
+		return []net.Interface{{
+			Index: 0,
+			Name:  "fake0",
+			Flags: net.FlagUp,
+		}}, nil
+	})
+	s.PatchValue(provisioner.InterfaceAddrs, func(i *net.Interface) ([]net.Addr, error) {
+		return []net.Addr{&fakeAddr{"0.1.2.1/24"}}, nil
+	})
+
+	// When no interface info is prepared for the container, it will be
+	// reached through the host when on the NAT bridge, so there is
+	// nothing to configure.
+	api := &forwardingAPI{}
+	result, err := provisioner.MaybeAllocateStaticIP("42", "lxcbr0", api, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.IsNil)
+	c.Assert(api.forwarded, jc.DeepEquals, []names.MachineTag{names.NewMachineTag("42")})
+
+	// On any other bridge the container gets addresses of its own.
+	api = &forwardingAPI{}
+	result, err = provisioner.MaybeAllocateStaticIP("42", "br0", api, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.IsNil)
+	c.Assert(api.forwarded, gc.HasLen, 0)
+}
+
+// forwardingAPI is a fakeAPI for an environment which does not
+// support allocating addresses for containers.
+type forwardingAPI struct {
+	fakeAPI
+	forwarded []names.MachineTag
+}
+
+func (*forwardingAPI) PrepareContainerInterfaceInfo(tag names.MachineTag) ([]network.InterfaceInfo, error) {
+	return nil, nil
+}
+
+func (f *forwardingAPI) PrepareContainerPortForwarding(tag names.MachineTag) error {
+	f.forwarded = append(f.forwarded, tag)
+	return nil
+}
+
 type lxcProvisionerSuite struct {
 	CommonProvisionerSuite
 	lxcSuite
@@ -871,3 +935,7 @@ func (f *fakeAPI) PrepareContainerInterfaceInfo(tag names.MachineTag) ([]network
 		GatewayAddress: network.NewAddress("0.1.2.1", network.ScopeUnknown),
 	}}, nil
 }
+
+func (*fakeAPI) PrepareContainerPortForwarding(tag names.MachineTag) error {
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/container"
+	"github.com/juju/juju/container/lxd"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+)
+
+var lxdLogger = loggo.GetLogger("juju.provisioner.lxd")
+
+var _ environs.InstanceBroker = (*lxdBroker)(nil)
+
+// Override for testing.
+var NewLxdBroker = newLxdBroker
+
+func newLxdBroker(
+	api APICalls, agentConfig agent.Config, managerConfig container.ManagerConfig,
+	imageURLGetter container.ImageURLGetter,
+) (environs.InstanceBroker, error) {
+	manager, err := lxd.NewContainerManager(managerConfig, imageURLGetter)
+	if err != nil {
+		return nil, err
+	}
+	return &lxdBroker{
+		manager:     manager,
+		api:         api,
+		agentConfig: agentConfig,
+	}, nil
+}
+
+type lxdBroker struct {
+	manager     container.Manager
+	api         APICalls
+	agentConfig agent.Config
+}
+
+// StartInstance is specified in the Broker interface.
+func (broker *lxdBroker) StartInstance(args environs.StartInstanceParams) (*environs.StartInstanceResult, error) {
+	if args.MachineConfig.HasNetworks() {
+		return nil, errors.New("starting lxd containers with networks is not supported yet")
+	}
+	machineId := args.MachineConfig.MachineId
+	lxdLogger.Infof("starting lxd container for machineId: %s", machineId)
+
+	// Default to using the host network until we can configure.
+	bridgeDevice := broker.agentConfig.Value(agent.LxcBridge)
+	if bridgeDevice == "" {
+		bridgeDevice = lxd.DefaultLxdBridge
+	}
+	allocatedInfo, err := maybeAllocateStaticIP(
+		machineId, bridgeDevice, broker.api, args.NetworkInfo,
+	)
+	if err != nil {
+		// It's fine, just ignore it. The effect will be that the
+		// container won't have a static address configured.
+		logger.Infof("not allocating static IP for container %q: %v", machineId, err)
+	} else {
+		args.NetworkInfo = allocatedInfo
+	}
+	network := container.BridgeNetworkConfig(bridgeDevice, args.NetworkInfo)
+
+	// LXD containers share the host's kernel, so must use tools for
+	// the host's architecture.
+	archTools, err := args.Tools.Match(tools.Filter{
+		Arch: version.Current.Arch,
+	})
+	if err == tools.ErrNoMatches {
+		return nil, errors.Errorf(
+			"need tools for arch %s, only found %s",
+			version.Current.Arch,
+			args.Tools.Arches(),
+		)
+	}
+
+	series := archTools.OneSeries()
+	args.MachineConfig.MachineContainerType = instance.LXD
+	args.MachineConfig.Tools = archTools[0]
+
+	config, err := broker.api.ContainerConfig()
+	if err != nil {
+		lxdLogger.Errorf("failed to get container config: %v", err)
+		return nil, err
+	}
+	if err := environs.PopulateMachineConfig(
+		args.MachineConfig,
+		config.ProviderType,
+		config.AuthorizedKeys,
+		config.SSLHostnameVerification,
+		config.Proxy,
+		config.AptProxy,
+		config.AptMirror,
+		config.PreferIPv6,
+		config.EnableOSRefreshUpdate,
+		config.EnableOSUpgrade,
+	); err != nil {
+		lxdLogger.Errorf("failed to populate machine config: %v", err)
+		return nil, err
+	}
+
+	inst, hardware, err := broker.manager.CreateContainer(args.MachineConfig, args.Constraints, series, network)
+	if err != nil {
+		lxdLogger.Errorf("failed to start container: %v", err)
+		return nil, err
+	}
+	lxdLogger.Infof("started lxd container for machineId: %s, %s, %s", machineId, inst.Id(), hardware.String())
+	return &environs.StartInstanceResult{
+		Instance: inst,
+		Hardware: hardware,
+	}, nil
+}
+
+// StopInstances shuts down the given instances.
+func (broker *lxdBroker) StopInstances(ids ...instance.Id) error {
+	// TODO: potentially parallelise.
+	for _, id := range ids {
+		lxdLogger.Infof("stopping lxd container for instance: %s", id)
+		if err := broker.manager.DestroyContainer(id); err != nil {
+			lxdLogger.Errorf("container did not stop: %v", err)
+			return err
+		}
+	}
+	return nil
+}
+
+// AllInstances only returns running containers.
+func (broker *lxdBroker) AllInstances() (result []instance.Instance, err error) {
+	return broker.manager.ListContainers()
+}
@@ -0,0 +1,170 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package provisioner_test
+
+import (
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/names"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/agent"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/container"
+	lxdtesting "github.com/juju/juju/container/lxd/testing"
+	"github.com/juju/juju/environs"
+	"github.com/juju/juju/environs/cloudinit"
+	"github.com/juju/juju/instance"
+	instancetest "github.com/juju/juju/instance/testing"
+	"github.com/juju/juju/juju/arch"
+	jujutesting "github.com/juju/juju/juju/testing"
+	coretesting "github.com/juju/juju/testing"
+	coretools "github.com/juju/juju/tools"
+	"github.com/juju/juju/version"
+	"github.com/juju/juju/worker/provisioner"
+)
+
+type lxdBrokerSuite struct {
+	lxdtesting.TestSuite
+	broker      environs.InstanceBroker
+	agentConfig agent.ConfigSetterWriter
+}
+
+var _ = gc.Suite(&lxdBrokerSuite{})
+
+func (s *lxdBrokerSuite) SetUpTest(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("Skipping lxd tests on windows")
+	}
+	s.TestSuite.SetUpTest(c)
+	var err error
+	s.agentConfig, err = agent.NewAgentConfig(
+		agent.AgentConfigParams{
+			DataDir:           "/not/used/here",
+			Tag:               names.NewMachineTag("1"),
+			UpgradedToVersion: version.Current.Number,
+			Password:          "dummy-secret",
+			Nonce:             "nonce",
+			APIAddresses:      []string{"10.0.0.1:1234"},
+			CACert:            coretesting.CACert,
+			Environment:       coretesting.EnvironmentTag,
+		})
+	c.Assert(err, jc.ErrorIsNil)
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	s.broker, err = provisioner.NewLxdBroker(&fakeAPI{}, s.agentConfig, managerConfig, s.ImageURLGetter)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *lxdBrokerSuite) machineConfig(c *gc.C, machineId string, networks []string) *cloudinit.MachineConfig {
+	machineNonce := "fake-nonce"
+	// To isolate the tests from the host's architecture, we override it here.
+	s.PatchValue(&version.Current.Arch, arch.AMD64)
+	stateInfo := jujutesting.FakeStateInfo(machineId)
+	apiInfo := jujutesting.FakeAPIInfo(machineId)
+	machineConfig, err := environs.NewMachineConfig(machineId, machineNonce, "released", "quantal", true, networks, stateInfo, apiInfo)
+	c.Assert(err, jc.ErrorIsNil)
+	return machineConfig
+}
+
+func (s *lxdBrokerSuite) startInstanceParams(c *gc.C, machineId string, networks []string) environs.StartInstanceParams {
+	return environs.StartInstanceParams{
+		Constraints: constraints.Value{},
+		Tools: coretools.List{&coretools.Tools{
+			Version: version.MustParseBinary("2.3.4-quantal-amd64"),
+			URL:     "http://tools.testing.invalid/2.3.4-quantal-amd64.tgz",
+		}},
+		MachineConfig: s.machineConfig(c, machineId, networks),
+	}
+}
+
+func (s *lxdBrokerSuite) startInstance(c *gc.C, machineId string) instance.Instance {
+	result, err := s.broker.StartInstance(s.startInstanceParams(c, machineId, nil))
+	c.Assert(err, jc.ErrorIsNil)
+	return result.Instance
+}
+
+func (s *lxdBrokerSuite) TestStartInstance(c *gc.C) {
+	machineId := "1/lxd/0"
+	lxd := s.startInstance(c, machineId)
+	c.Assert(lxd.Id(), gc.Equals, instance.Id("juju-machine-1-lxd-0"))
+	c.Assert(s.lxdContainerDir(lxd), jc.IsDirectory)
+
+	containers := s.Server.Containers()
+	c.Assert(containers, gc.HasLen, 1)
+	created := containers["juju-machine-1-lxd-0"]
+	c.Assert(created.Status, gc.Equals, "Running")
+	c.Assert(created.Image, gc.Equals, "juju-quantal-"+arch.HostArch())
+	c.Assert(created.Devices["eth0"]["parent"], gc.Equals, "lxcbr0")
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceWithBridgeEnvironment(c *gc.C) {
+	s.agentConfig.SetValue(agent.LxcBridge, "br0")
+	managerConfig := container.ManagerConfig{container.ConfigName: "juju"}
+	broker, err := provisioner.NewLxdBroker(&fakeAPI{}, s.agentConfig, managerConfig, s.ImageURLGetter)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := broker.StartInstance(s.startInstanceParams(c, "1/lxd/0", nil))
+	c.Assert(err, jc.ErrorIsNil)
+
+	created := s.Server.Containers()[string(result.Instance.Id())]
+	c.Assert(created.Devices["eth0"]["parent"], gc.Equals, "br0")
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceWithNetworks(c *gc.C) {
+	_, err := s.broker.StartInstance(s.startInstanceParams(c, "1/lxd/0", []string{"net1"}))
+	c.Assert(err, gc.ErrorMatches, "starting lxd containers with networks is not supported yet")
+	c.Assert(s.Server.Containers(), gc.HasLen, 0)
+}
+
+func (s *lxdBrokerSuite) TestStartInstanceNoToolsForArch(c *gc.C) {
+	params := s.startInstanceParams(c, "1/lxd/0", nil)
+	params.Tools = coretools.List{&coretools.Tools{
+		Version: version.MustParseBinary("2.3.4-quantal-ppc64el"),
+		URL:     "http://tools.testing.invalid/2.3.4-quantal-ppc64el.tgz",
+	}}
+	_, err := s.broker.StartInstance(params)
+	c.Assert(err, gc.ErrorMatches, "need tools for arch amd64, only found \\[ppc64el\\]")
+}
+
+func (s *lxdBrokerSuite) TestStopInstance(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0")
+	lxd1 := s.startInstance(c, "1/lxd/1")
+	lxd2 := s.startInstance(c, "1/lxd/2")
+
+	err := s.broker.StopInstances(lxd0.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c, lxd1, lxd2)
+	c.Assert(s.lxdContainerDir(lxd0), jc.DoesNotExist)
+	c.Assert(s.lxdRemovedContainerDir(lxd0), jc.IsDirectory)
+
+	err = s.broker.StopInstances(lxd1.Id(), lxd2.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertInstances(c)
+}
+
+func (s *lxdBrokerSuite) TestAllInstances(c *gc.C) {
+	lxd0 := s.startInstance(c, "1/lxd/0")
+	lxd1 := s.startInstance(c, "1/lxd/1")
+	s.assertInstances(c, lxd0, lxd1)
+
+	err := s.broker.StopInstances(lxd1.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	lxd2 := s.startInstance(c, "1/lxd/2")
+	s.assertInstances(c, lxd0, lxd2)
+}
+
+func (s *lxdBrokerSuite) assertInstances(c *gc.C, inst ...instance.Instance) {
+	results, err := s.broker.AllInstances()
+	c.Assert(err, jc.ErrorIsNil)
+	instancetest.MatchInstances(c, results, inst...)
+}
+
+func (s *lxdBrokerSuite) lxdContainerDir(inst instance.Instance) string {
+	return filepath.Join(s.ContainerDir, string(inst.Id()))
+}
+
+func (s *lxdBrokerSuite) lxdRemovedContainerDir(inst instance.Instance) string {
+	return filepath.Join(s.RemovedDir, string(inst.Id()))
+}
@@ -0,0 +1,350 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"launchpad.net/tomb"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/state/watcher"
+	"github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.remoterelations")
+
+// remoteRelations mirrors the units of the remote services of an
+// environment into its relations.
+type remoteRelations struct {
+	tomb     tomb.Tomb
+	st       *state.State
+	services map[string]*remoteServiceData
+}
+
+// NewRemoteRelations returns a worker that mirrors the units of remote
+// services, and their settings, into the relations of the environment
+// as they change in the environment of each remote service, and removes
+// relations whose counterpart in that environment has gone away.
+func NewRemoteRelations(st *state.State) worker.Worker {
+	rr := &remoteRelations{
+		st:       st,
+		services: make(map[string]*remoteServiceData),
+	}
+	go func() {
+		defer rr.tomb.Done()
+		rr.tomb.Kill(rr.loop())
+	}()
+	return rr
+}
+
+// Kill is part of the worker.Worker interface.
+func (rr *remoteRelations) Kill() {
+	rr.tomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (rr *remoteRelations) Wait() error {
+	return rr.tomb.Wait()
+}
+
+func (rr *remoteRelations) loop() error {
+	defer rr.stopServices()
+	w := rr.st.WatchRemoteServices()
+	defer watcher.Stop(w, &rr.tomb)
+	for {
+		select {
+		case <-rr.tomb.Dying():
+			return tomb.ErrDying
+		case serviceNames, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+			for _, name := range serviceNames {
+				if err := rr.serviceChanged(name); err != nil {
+					return errors.Annotatef(err, "cannot update remote service %q", name)
+				}
+			}
+		}
+	}
+}
+
+// serviceChanged starts or stops mirroring the units of the named
+// remote service as it is added or removed.
+func (rr *remoteRelations) serviceChanged(name string) error {
+	remote, err := rr.st.RemoteService(name)
+	if err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	if sd, ok := rr.services[name]; ok {
+		if remote != nil && remote.SourceEnvironTag() == sd.remote.SourceEnvironTag() {
+			return nil
+		}
+		delete(rr.services, name)
+		if err := sd.Stop(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	if remote == nil {
+		return nil
+	}
+	source, err := rr.st.ForEnviron(remote.SourceEnvironTag())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	rr.services[name] = newRemoteServiceData(rr, remote, source)
+	return nil
+}
+
+func (rr *remoteRelations) stopServices() {
+	for name, sd := range rr.services {
+		if err := sd.Stop(); err != nil {
+			logger.Errorf("error stopping remote service %q: %v", name, err)
+		}
+	}
+}
+
+// remoteServiceData mirrors the units of a remote service into its
+// relations, for as long as their counterparts in the environment of
+// the service are alive.
+type remoteServiceData struct {
+	tomb      tomb.Tomb
+	rr        *remoteRelations
+	remote    *state.RemoteService
+	source    *state.State
+	relations map[string]*relationData
+}
+
+func newRemoteServiceData(rr *remoteRelations, remote *state.RemoteService, source *state.State) *remoteServiceData {
+	sd := &remoteServiceData{
+		rr:        rr,
+		remote:    remote,
+		source:    source,
+		relations: make(map[string]*relationData),
+	}
+	go func() {
+		defer sd.tomb.Done()
+		defer sd.source.Close()
+		sd.tomb.Kill(sd.loop())
+		if err := sd.tomb.Err(); err != nil {
+			rr.tomb.Kill(errors.Annotatef(err, "cannot update remote service %q", remote))
+		}
+	}()
+	return sd
+}
+
+// Stop stops mirroring the units of the remote service.
+func (sd *remoteServiceData) Stop() error {
+	sd.tomb.Kill(nil)
+	return sd.tomb.Wait()
+}
+
+func (sd *remoteServiceData) loop() error {
+	defer sd.stopRelations()
+	localw := sd.remote.WatchRelations()
+	defer watcher.Stop(localw, &sd.tomb)
+
+	// The relations of the service in its own environment are the
+	// counterparts of those here. If it has gone, so have they.
+	var sourcew state.StringsWatcher
+	var sourceChanges <-chan []string
+	service, err := sd.source.Service(sd.remote.Name())
+	if err == nil {
+		sourcew = service.WatchRelations()
+		defer watcher.Stop(sourcew, &sd.tomb)
+		sourceChanges = sourcew.Changes()
+	} else if !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	for {
+		var keys []string
+		var ok bool
+		select {
+		case <-sd.tomb.Dying():
+			return tomb.ErrDying
+		case keys, ok = <-localw.Changes():
+			if !ok {
+				return watcher.EnsureErr(localw)
+			}
+		case keys, ok = <-sourceChanges:
+			if !ok {
+				return watcher.EnsureErr(sourcew)
+			}
+		}
+		for _, key := range keys {
+			if err := sd.relationChanged(key); err != nil {
+				return errors.Annotatef(err, "cannot update relation %q", key)
+			}
+		}
+	}
+}
+
+// relationChanged mirrors the units of the remote service into the
+// relation with the supplied key while both it and its counterpart are
+// alive, and removes them from it otherwise.
+func (sd *remoteServiceData) relationChanged(key string) error {
+	rel, err := sd.rr.st.KeyRelation(key)
+	if errors.IsNotFound(err) {
+		return sd.stopRelation(key)
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	counterpart, err := sd.source.KeyRelation(key)
+	if errors.IsNotFound(err) {
+		logger.Debugf("relation %q removed from environment %q", key, sd.source.EnvironUUID())
+	} else if err != nil {
+		return errors.Trace(err)
+	} else if counterpart.Life() != state.Alive {
+		logger.Debugf("relation %q dying in environment %q", key, sd.source.EnvironUUID())
+	} else if rel.Life() == state.Alive {
+		if _, ok := sd.relations[key]; !ok {
+			sd.relations[key] = newRelationData(sd, rel, counterpart)
+		}
+		return nil
+	}
+	if err := sd.stopRelation(key); err != nil {
+		return errors.Trace(err)
+	}
+	return removeRelation(rel, sd.remote.Name())
+}
+
+func (sd *remoteServiceData) stopRelation(key string) error {
+	rd, ok := sd.relations[key]
+	if !ok {
+		return nil
+	}
+	delete(sd.relations, key)
+	return rd.Stop()
+}
+
+func (sd *remoteServiceData) stopRelations() {
+	for key, rd := range sd.relations {
+		if err := rd.Stop(); err != nil {
+			logger.Errorf("error stopping relation %q: %v", key, err)
+		}
+	}
+}
+
+// removeRelation removes the units of the remote service from the
+// relation and destroys it, so that it is removed once the units of
+// this environment have left it too.
+func removeRelation(rel *state.Relation, remoteName string) error {
+	mirrored, err := rel.UnitSettings(remoteName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for unitName := range mirrored {
+		logger.Debugf("unit %q leaving relation %q", unitName, rel)
+		if err := rel.RemoveRemoteUnit(unitName); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	if err := rel.Destroy(); err != nil && !errors.IsNotFound(err) {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// relationData mirrors the units of a remote service in the
+// counterpart of a relation into the relation, as they enter and leave
+// its scope and change their settings.
+type relationData struct {
+	tomb        tomb.Tomb
+	sd          *remoteServiceData
+	rel         *state.Relation
+	counterpart *state.Relation
+}
+
+func newRelationData(sd *remoteServiceData, rel, counterpart *state.Relation) *relationData {
+	rd := &relationData{
+		sd:          sd,
+		rel:         rel,
+		counterpart: counterpart,
+	}
+	go func() {
+		defer rd.tomb.Done()
+		rd.tomb.Kill(rd.loop())
+		if err := rd.tomb.Err(); err != nil {
+			sd.tomb.Kill(errors.Annotatef(err, "cannot update relation %q", rel))
+		}
+	}()
+	return rd
+}
+
+// Stop stops mirroring the units of the remote service.
+func (rd *relationData) Stop() error {
+	rd.tomb.Kill(nil)
+	return rd.tomb.Wait()
+}
+
+func (rd *relationData) loop() error {
+	w, err := rd.counterpart.WatchUnits(rd.sd.remote.Name())
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer watcher.Stop(w, &rd.tomb)
+	initial := true
+	for {
+		select {
+		case <-rd.tomb.Dying():
+			return tomb.ErrDying
+		case change, ok := <-w.Changes():
+			if !ok {
+				return watcher.EnsureErr(w)
+			}
+			if err := rd.update(change, initial); err != nil {
+				return errors.Trace(err)
+			}
+			initial = false
+		}
+	}
+}
+
+// update applies a change to the units of the remote service in the
+// counterpart to the relation. The initial change holds all units in
+// the counterpart, so any others are those that left it while their
+// departure was not being watched.
+func (rd *relationData) update(change multiwatcher.RelationUnitsChange, initial bool) error {
+	remoteName := rd.sd.remote.Name()
+	departed := change.Departed
+	if initial {
+		mirrored, err := rd.rel.UnitSettings(remoteName)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for unitName := range mirrored {
+			if _, ok := change.Changed[unitName]; !ok {
+				departed = append(departed, unitName)
+			}
+		}
+	}
+	for _, unitName := range departed {
+		logger.Debugf("unit %q leaving relation %q", unitName, rd.rel)
+		if err := rd.rel.RemoveRemoteUnit(unitName); err != nil && !errors.IsNotFound(err) {
+			return errors.Trace(err)
+		}
+	}
+	if len(change.Changed) == 0 {
+		return nil
+	}
+	settings, err := rd.counterpart.UnitSettings(remoteName)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	for unitName := range change.Changed {
+		unitSettings, ok := settings[unitName]
+		if !ok {
+			// The unit has since left, which the next change reports.
+			continue
+		}
+		err := rd.rel.SetRemoteUnitSettings(unitName, unitSettings)
+		if err == state.ErrCannotEnterScope || errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
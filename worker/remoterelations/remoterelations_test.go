@@ -0,0 +1,128 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remoterelations_test
+
+import (
+	"reflect"
+	stdtesting "testing"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/juju/testing"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/watcher"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
+	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/remoterelations"
+)
+
+func TestPackage(t *stdtesting.T) {
+	coretesting.MgoTestPackage(t)
+}
+
+type remoteRelationsSuite struct {
+	testing.JujuConnSuite
+}
+
+var _ = gc.Suite(&remoteRelationsSuite{})
+
+func (s *remoteRelationsSuite) SetUpTest(c *gc.C) {
+	s.JujuConnSuite.SetUpTest(c)
+	// The worker watches the offering environment through a state of
+	// its own, which the test cannot sync.
+	s.PatchValue(&watcher.Period, 100*time.Millisecond)
+}
+
+func (s *remoteRelationsSuite) TestMirrorsRemoteUnits(c *gc.C) {
+	offering := s.Factory.MakeEnvironment(c, nil)
+	defer offering.Close()
+	f := factory.NewFactory(offering)
+	mysql := f.MakeService(c, &factory.ServiceParams{
+		Name:  "mysql",
+		Charm: f.MakeCharm(c, &factory.CharmParams{Name: "mysql"}),
+	})
+	mysqlUnit := f.MakeUnit(c, &factory.UnitParams{Service: mysql})
+	err := offering.OfferService("mysql", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.Factory.MakeService(c, &factory.ServiceParams{
+		Name:  "wordpress",
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	rel, err := s.State.AddCrossEnvironmentRelation("wordpress", offering, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+	counterpart, err := offering.KeyRelation(rel.String())
+	c.Assert(err, jc.ErrorIsNil)
+	ru, err := counterpart.Unit(mysqlUnit)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.EnterScope(map[string]interface{}{"host": "db.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := remoterelations.NewRemoteRelations(s.State)
+	defer func() { c.Assert(worker.Stop(w), jc.ErrorIsNil) }()
+
+	// The offered unit joins the relation in this environment, and
+	// changes to its settings are mirrored.
+	s.assertUnitSettings(c, rel, map[string]map[string]interface{}{
+		"mysql/0": {"host": "db.example.com"},
+	})
+	settings, err := ru.Settings()
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set("port", "3306")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitSettings(c, rel, map[string]map[string]interface{}{
+		"mysql/0": {"host": "db.example.com", "port": "3306"},
+	})
+
+	// As are units leaving it.
+	mysqlUnit1 := f.MakeUnit(c, &factory.UnitParams{Service: mysql})
+	ru1, err := counterpart.Unit(mysqlUnit1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru1.EnterScope(map[string]interface{}{"host": "db-1.example.com"})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitSettings(c, rel, map[string]map[string]interface{}{
+		"mysql/0": {"host": "db.example.com", "port": "3306"},
+		"mysql/1": {"host": "db-1.example.com"},
+	})
+	err = ru1.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertUnitSettings(c, rel, map[string]map[string]interface{}{
+		"mysql/0": {"host": "db.example.com", "port": "3306"},
+	})
+
+	// Once the counterpart is removed, so is the relation, along with
+	// the remote service.
+	err = counterpart.Destroy()
+	c.Assert(err, jc.ErrorIsNil)
+	err = ru.LeaveScope()
+	c.Assert(err, jc.ErrorIsNil)
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		err := rel.Refresh()
+		if errors.IsNotFound(err) {
+			_, err = s.State.RemoteService("mysql")
+			c.Assert(err, jc.Satisfies, errors.IsNotFound)
+			return
+		}
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Fatalf("relation not removed")
+}
+
+func (s *remoteRelationsSuite) assertUnitSettings(c *gc.C, rel *state.Relation, expected map[string]map[string]interface{}) {
+	var settings map[string]map[string]interface{}
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		var err error
+		settings, err = rel.UnitSettings("mysql")
+		c.Assert(err, jc.ErrorIsNil)
+		if reflect.DeepEqual(settings, expected) {
+			return
+		}
+	}
+	c.Assert(settings, jc.DeepEquals, expected)
+}
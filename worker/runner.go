@@ -5,6 +5,7 @@ package worker
 
 import (
 	"errors"
+	"sort"
 	"time"
 
 	"launchpad.net/tomb"
@@ -39,6 +40,7 @@ type runner struct {
 	stopc         chan string
 	donec         chan doneInfo
 	startedc      chan startInfo
+	reportc       chan chan []workerSnapshot
 	isFatal       func(error) bool
 	moreImportant func(err0, err1 error) bool
 }
@@ -76,6 +78,7 @@ func NewRunner(isFatal func(error) bool, moreImportant func(err0, err1 error) bo
 		stopc:         make(chan string),
 		donec:         make(chan doneInfo),
 		startedc:      make(chan startInfo),
+		reportc:       make(chan chan []workerSnapshot),
 		isFatal:       isFatal,
 		moreImportant: moreImportant,
 	}
@@ -127,6 +130,114 @@ func (runner *runner) Kill() {
 	runner.tomb.Kill(nil)
 }
 
+// States reported for workers by Runner.Report.
+const (
+	// WorkerStarting is reported for a worker that is waiting to
+	// be started or restarted.
+	WorkerStarting = "starting"
+
+	// WorkerRunning is reported for a running worker.
+	WorkerRunning = "running"
+
+	// WorkerStopping is reported for a worker that has been asked
+	// to stop but has not yet done so.
+	WorkerStopping = "stopping"
+)
+
+// WorkerReport describes a worker run by a Runner.
+type WorkerReport struct {
+	// Name holds the id the worker was started with.
+	Name string
+
+	// State holds one of WorkerStarting, WorkerRunning
+	// and WorkerStopping.
+	State string
+
+	// Restarts holds the number of times the worker
+	// has been restarted after exiting with an error.
+	// Restarts asked for by stopping and starting the
+	// worker again are not counted.
+	Restarts int
+
+	// LastError holds the error the worker last exited with,
+	// if any.
+	LastError error
+
+	// Workers holds reports on the workers run by this
+	// worker, if it implements Reporter.
+	Workers []WorkerReport
+}
+
+// Reporter is implemented by workers that can report
+// on the workers they run.
+type Reporter interface {
+	// Report returns a report on each of the workers,
+	// sorted by name.
+	Report() []WorkerReport
+}
+
+var _ Reporter = (*runner)(nil)
+
+// workerSnapshot records the state of a worker
+// at the time a report was requested.
+type workerSnapshot struct {
+	report WorkerReport
+	worker Worker
+}
+
+func snapshotWorkers(workers map[string]*workerInfo) []workerSnapshot {
+	snapshots := make([]workerSnapshot, 0, len(workers))
+	for id, info := range workers {
+		state := WorkerRunning
+		switch {
+		case info.killed:
+			state = WorkerStopping
+		case info.worker == nil:
+			state = WorkerStarting
+		}
+		snapshots = append(snapshots, workerSnapshot{
+			report: WorkerReport{
+				Name:      id,
+				State:     state,
+				Restarts:  info.restarts,
+				LastError: info.lastErr,
+			},
+			worker: info.worker,
+		})
+	}
+	return snapshots
+}
+
+// Report implements Reporter. It returns nil
+// if the runner is not running.
+func (runner *runner) Report() []WorkerReport {
+	reply := make(chan []workerSnapshot)
+	select {
+	case runner.reportc <- reply:
+	case <-runner.tomb.Dead():
+		return nil
+	}
+	// The reports of any child workers are collected
+	// outside the runner's loop so that it is not blocked
+	// while they are made.
+	snapshots := <-reply
+	reports := make([]WorkerReport, len(snapshots))
+	for i, snapshot := range snapshots {
+		reports[i] = snapshot.report
+		if reporter, ok := snapshot.worker.(Reporter); ok {
+			reports[i].Workers = reporter.Report()
+		}
+	}
+	sort.Sort(workerReportsByName(reports))
+	return reports
+}
+
+type workerReportsByName []WorkerReport
+
+func (r workerReportsByName) Len() int           { return len(r) }
+func (r workerReportsByName) Less(i, j int) bool { return r[i].Name < r[j].Name }
+func (r workerReportsByName) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
 // Stop kills the given worker and waits for it to exit.
 func Stop(worker Worker) error {
 	worker.Kill()
@@ -138,6 +249,12 @@ type workerInfo struct {
 	worker       Worker
 	restartDelay time.Duration
 	stopping     bool
+	restarts     int
+	lastErr      error
+
+	// killed records that the worker has been killed,
+	// and has not been started again since.
+	killed bool
 }
 
 func (runner *runner) run() error {
@@ -191,6 +308,8 @@ func (runner *runner) run() error {
 			if info := workers[id]; info != nil {
 				killWorker(id, info)
 			}
+		case reply := <-runner.reportc:
+			reply <- snapshotWorkers(workers)
 		case info := <-runner.startedc:
 			workerInfo := workers[info.id]
 			workerInfo.worker = info.worker
@@ -199,6 +318,8 @@ func (runner *runner) run() error {
 			}
 		case info := <-runner.donec:
 			workerInfo := workers[info.id]
+			workerInfo.worker = nil
+			deliberate := workerInfo.killed
 			if !workerInfo.stopping && info.err == nil {
 				delete(workers, info.id)
 				break
@@ -218,6 +339,9 @@ func (runner *runner) run() error {
 				} else {
 					logger.Errorf("exited %q: %v", info.id, info.err)
 				}
+				if !deliberate {
+					workerInfo.lastErr = info.err
+				}
 			}
 			if workerInfo.start == nil {
 				// The worker has been deliberately stopped;
@@ -227,6 +351,10 @@ func (runner *runner) run() error {
 			}
 			go runner.runWorker(workerInfo.restartDelay, info.id, workerInfo.start)
 			workerInfo.restartDelay = RestartDelay
+			workerInfo.killed = false
+			if info.err != nil && !deliberate {
+				workerInfo.restarts++
+			}
 		}
 	}
 }
@@ -244,6 +372,7 @@ func killWorker(id string, info *workerInfo) {
 		info.worker = nil
 	}
 	info.stopping = true
+	info.killed = true
 	info.start = nil
 }
 
@@ -312,6 +312,92 @@ func (*runnerSuite) TestFatalErrorWhileSelfStartWorker(c *gc.C) {
 	c.Assert(err, gc.Equals, fatalStarter.startErr)
 }
 
+func (*runnerSuite) TestReport(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer worker.Stop(runner)
+	reporter := runner.(worker.Reporter)
+
+	starter := newTestWorkerStarter()
+	err := runner.StartWorker("worker", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	for i := 0; i < 2; i++ {
+		starter.die <- fmt.Errorf("error %d", i)
+		starter.assertStarted(c, false)
+		starter.assertStarted(c, true)
+	}
+
+	childStarter := newTestWorkerStarter()
+	err = runner.StartWorker("child-runner", func() (worker.Worker, error) {
+		child := worker.NewRunner(noneFatal, noImportance)
+		err := child.StartWorker("child", testWorkerStart(childStarter))
+		return child, err
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	childStarter.assertStarted(c, true)
+
+	// The runners may not yet have recorded
+	// that the workers have started.
+	var reports []worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		reports = reporter.Report()
+		if len(reports) == 2 && reports[1].State == worker.WorkerRunning &&
+			len(reports[0].Workers) == 1 && reports[0].Workers[0].State == worker.WorkerRunning {
+			break
+		}
+	}
+	c.Assert(reports, jc.DeepEquals, []worker.WorkerReport{{
+		Name:  "child-runner",
+		State: worker.WorkerRunning,
+		Workers: []worker.WorkerReport{{
+			Name:  "child",
+			State: worker.WorkerRunning,
+		}},
+	}, {
+		Name:      "worker",
+		State:     worker.WorkerRunning,
+		Restarts:  2,
+		LastError: fmt.Errorf("error 1"),
+	}})
+}
+
+func (*runnerSuite) TestReportIgnoresDeliberateRestarts(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	defer worker.Stop(runner)
+	starter := newTestWorkerStarter()
+	starter.stopErr = fmt.Errorf("stopped")
+	starter.stopWait = make(chan struct{})
+
+	err := runner.StartWorker("worker", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	starter.assertStarted(c, true)
+	err = runner.StopWorker("worker")
+	c.Assert(err, jc.ErrorIsNil)
+	err = runner.StartWorker("worker", testWorkerStart(starter))
+	c.Assert(err, jc.ErrorIsNil)
+	close(starter.stopWait)
+	starter.assertStarted(c, false)
+	starter.assertStarted(c, true)
+
+	var reports []worker.WorkerReport
+	for a := testing.LongAttempt.Start(); a.Next(); {
+		reports = runner.(worker.Reporter).Report()
+		if len(reports) == 1 && reports[0].State == worker.WorkerRunning {
+			break
+		}
+	}
+	c.Assert(reports, jc.DeepEquals, []worker.WorkerReport{{
+		Name:  "worker",
+		State: worker.WorkerRunning,
+	}})
+}
+
+func (*runnerSuite) TestReportWhenDead(c *gc.C) {
+	runner := worker.NewRunner(noneFatal, noImportance)
+	c.Assert(worker.Stop(runner), gc.IsNil)
+	c.Assert(runner.(worker.Reporter).Report(), gc.IsNil)
+}
+
 type testWorkerStarter struct {
 	startCount int32
 
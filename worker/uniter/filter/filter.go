@@ -34,24 +34,29 @@ type filter struct {
 	// The out* chans, when set to the corresponding out*On chan (rather than
 	// nil) indicate that an event of the appropriate type is ready to send
 	// to the client.
-	outConfig        chan struct{}
-	outConfigOn      chan struct{}
-	outAction        chan string
-	outActionOn      chan string
-	outUpgrade       chan *charm.URL
-	outUpgradeOn     chan *charm.URL
-	outResolved      chan params.ResolvedMode
-	outResolvedOn    chan params.ResolvedMode
-	outRelations     chan []int
-	outRelationsOn   chan []int
-	outMeterStatus   chan struct{}
-	outMeterStatusOn chan struct{}
-	outStorage       chan []names.StorageTag
-	outStorageOn     chan []names.StorageTag
+	outConfig           chan struct{}
+	outConfigOn         chan struct{}
+	outAction           chan string
+	outActionOn         chan string
+	outUpgrade          chan *charm.URL
+	outUpgradeOn        chan *charm.URL
+	outResolved         chan params.ResolvedMode
+	outResolvedOn       chan params.ResolvedMode
+	outRelations        chan []int
+	outRelationsOn      chan []int
+	outMeterStatus      chan struct{}
+	outMeterStatusOn    chan struct{}
+	outStorage          chan []names.StorageTag
+	outStorageOn        chan []names.StorageTag
+	outLeaderSettings   chan struct{}
+	outLeaderSettingsOn chan struct{}
+	outResources        chan map[string]int
+	outResourcesOn      chan map[string]int
 	// The want* chans are used to indicate that the filter should send
 	// events if it has them available.
-	wantForcedUpgrade chan bool
-	wantResolved      chan struct{}
+	wantForcedUpgrade  chan bool
+	wantResolved       chan struct{}
+	wantLeaderSettings chan bool
 
 	// discardConfig is used to indicate that any pending config event
 	// should be discarded.
@@ -95,38 +100,53 @@ type filter struct {
 	actionsPending   []string
 	nextAction       string
 
+	// wantLeaderSettingsEvents records whether leader settings changes
+	// should currently be delivered as events.
+	wantLeaderSettingsEvents bool
+
 	// meterStatusCode and meterStatusInfo reflect the meter status values of the unit.
 	meterStatusCode string
 	meterStatusInfo string
+
+	// resources holds the last seen revision of each of the service's
+	// resources.
+	resources map[string]int
 }
 
 // NewFilter returns a filter that handles state changes pertaining to the
 // supplied unit.
 func NewFilter(st *uniter.State, unitTag names.UnitTag) (Filter, error) {
 	f := &filter{
-		st:                st,
-		outUnitDying:      make(chan struct{}),
-		outConfig:         nil,
-		outConfigOn:       make(chan struct{}),
-		outAction:         nil,
-		outActionOn:       make(chan string),
-		outUpgrade:        nil,
-		outUpgradeOn:      make(chan *charm.URL),
-		outResolved:       nil,
-		outResolvedOn:     make(chan params.ResolvedMode),
-		outRelations:      nil,
-		outRelationsOn:    make(chan []int),
-		outMeterStatus:    nil,
-		outMeterStatusOn:  make(chan struct{}),
-		outStorage:        nil,
-		outStorageOn:      make(chan []names.StorageTag),
-		wantForcedUpgrade: make(chan bool),
-		wantResolved:      make(chan struct{}),
-		discardConfig:     make(chan struct{}),
-		setCharm:          make(chan *charm.URL),
-		didSetCharm:       make(chan struct{}),
-		clearResolved:     make(chan struct{}),
-		didClearResolved:  make(chan struct{}),
+		st:                  st,
+		outUnitDying:        make(chan struct{}),
+		outConfig:           nil,
+		outConfigOn:         make(chan struct{}),
+		outAction:           nil,
+		outActionOn:         make(chan string),
+		outUpgrade:          nil,
+		outUpgradeOn:        make(chan *charm.URL),
+		outResolved:         nil,
+		outResolvedOn:       make(chan params.ResolvedMode),
+		outRelations:        nil,
+		outRelationsOn:      make(chan []int),
+		outMeterStatus:      nil,
+		outMeterStatusOn:    make(chan struct{}),
+		outStorage:          nil,
+		outStorageOn:        make(chan []names.StorageTag),
+		outLeaderSettings:   nil,
+		outLeaderSettingsOn: make(chan struct{}),
+		outResources:        nil,
+		outResourcesOn:      make(chan map[string]int),
+		wantForcedUpgrade:   make(chan bool),
+		wantResolved:        make(chan struct{}),
+		wantLeaderSettings:  make(chan bool),
+		discardConfig:       make(chan struct{}),
+		setCharm:            make(chan *charm.URL),
+		didSetCharm:         make(chan struct{}),
+		clearResolved:       make(chan struct{}),
+		didClearResolved:    make(chan struct{}),
+
+		wantLeaderSettingsEvents: true,
 	}
 	go func() {
 		defer f.tomb.Done()
@@ -200,6 +220,31 @@ func (f *filter) StorageEvents() <-chan []names.StorageTag {
 	return f.outStorageOn
 }
 
+// LeaderSettingsEvents returns a channel that will receive a signal whenever
+// the service's leader settings change, as long as leader settings events
+// are wanted.
+func (f *filter) LeaderSettingsEvents() <-chan struct{} {
+	return f.outLeaderSettingsOn
+}
+
+// ResourcesEvents returns a channel that will receive the revisions of all
+// the service's resources when they are first read, and whenever a new
+// revision of one of them is uploaded.
+func (f *filter) ResourcesEvents() <-chan map[string]int {
+	return f.outResourcesOn
+}
+
+// WantLeaderSettingsEvents controls whether the filter will generate leader
+// settings events. Leader settings events are wanted initially; if they are
+// turned off and then on again, an event will be sent immediately, so that
+// the client can catch up with any changes made in the meantime.
+func (f *filter) WantLeaderSettingsEvents(wanted bool) {
+	select {
+	case <-f.tomb.Dying():
+	case f.wantLeaderSettings <- wanted:
+	}
+}
+
 // WantUpgradeEvent controls whether the filter will generate upgrade
 // events for unforced service charm changes.
 func (f *filter) WantUpgradeEvent(mustForce bool) {
@@ -354,6 +399,17 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 		return err
 	}
 	defer watcher.Stop(storagew, &f.tomb)
+	// Leader settings can only be watched if the API server supports them.
+	var leaderSettingsw apiwatcher.NotifyWatcher
+	var leaderSettingsChanges <-chan struct{}
+	if f.st.LeadershipSettings != nil {
+		leaderSettingsw, err = f.st.LeadershipSettings.WatchLeadershipSettings(f.service.Tag().Id())
+		if err != nil {
+			return err
+		}
+		leaderSettingsChanges = leaderSettingsw.Changes()
+	}
+	defer f.maybeStopWatcher(leaderSettingsw)
 
 	// Config events cannot be meaningfully discarded until one is available;
 	// once we receive the initial config and address changes, we unblock
@@ -457,6 +513,14 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 				tags[i] = tag
 			}
 			f.storageChanged(tags)
+		case _, ok = <-leaderSettingsChanges:
+			filterLogger.Debugf("got leader settings change")
+			if !ok {
+				return watcher.EnsureErr(leaderSettingsw)
+			}
+			if f.wantLeaderSettingsEvents {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			}
 
 		// Send events on active out chans.
 		case f.outUpgrade <- f.upgrade:
@@ -482,6 +546,12 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			filterLogger.Debugf("sent storage event")
 			f.outStorage = nil
 			f.storage = nil
+		case f.outLeaderSettings <- nothing:
+			filterLogger.Debugf("sent leader settings event")
+			f.outLeaderSettings = nil
+		case f.outResources <- f.resources:
+			filterLogger.Debugf("sent resources event")
+			f.outResources = nil
 
 		// Handle explicit requests.
 		case curl := <-f.setCharm:
@@ -528,6 +598,14 @@ func (f *filter) loop(unitTag names.UnitTag) (err error) {
 			if err = f.upgradeChanged(); err != nil {
 				return err
 			}
+		case wanted := <-f.wantLeaderSettings:
+			filterLogger.Debugf("want leader settings events %v", wanted)
+			if wanted && !f.wantLeaderSettingsEvents {
+				f.outLeaderSettings = f.outLeaderSettingsOn
+			} else if !wanted {
+				f.outLeaderSettings = nil
+			}
+			f.wantLeaderSettingsEvents = wanted
 		case <-f.wantResolved:
 			filterLogger.Debugf("want resolved event")
 			if f.resolved != params.ResolvedNone {
@@ -618,9 +696,41 @@ func (f *filter) serviceChanged() error {
 		filterLogger.Infof("service is dead")
 		return worker.ErrTerminateAgent
 	}
+	if err := f.resourcesChanged(); err != nil {
+		return err
+	}
 	return f.upgradeChanged()
 }
 
+// resourcesChanged compares the revisions of the service's resources
+// with those last seen, and prepares a resources event if any differ.
+// The first read of a service with resources always prepares an event;
+// it's up to the uniter to compare the revisions with those it recorded
+// before it was last stopped.
+func (f *filter) resourcesChanged() error {
+	resources, err := f.service.Resources()
+	if errors.IsNotImplemented(err) {
+		// The API server predates resources.
+		resources = nil
+	} else if err != nil {
+		return err
+	}
+	revisions := make(map[string]int)
+	changed := false
+	for _, resource := range resources {
+		revisions[resource.Name] = resource.Revision
+		if revision, ok := f.resources[resource.Name]; !ok || revision != resource.Revision {
+			changed = true
+		}
+	}
+	if changed {
+		filterLogger.Debugf("preparing new resources event")
+		f.outResources = f.outResourcesOn
+	}
+	f.resources = revisions
+	return nil
+}
+
 // upgradeChanged responds to changes in the service or in the
 // upgrade requests that defines which charm changes should be
 // delivered as upgrades.
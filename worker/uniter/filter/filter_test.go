@@ -5,6 +5,7 @@ package filter_test
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/juju/names"
@@ -600,6 +601,76 @@ func (s *FilterSuite) TestMeterStatusEvents(c *gc.C) {
 	meterC.AssertOneReceive()
 }
 
+func (s *FilterSuite) TestLeaderSettingsEvents(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	leaderSettingsC := s.notifyAsserterC(c, f.LeaderSettingsEvents())
+	// Initial leader settings trigger an event.
+	leaderSettingsC.AssertOneReceive()
+
+	// Changed leader settings trigger an event.
+	s.setLeaderSetting(c, "foo", "bar")
+	leaderSettingsC.AssertOneReceive()
+
+	// Unwanted events are not delivered...
+	f.WantLeaderSettingsEvents(false)
+	s.setLeaderSetting(c, "foo", "baz")
+	leaderSettingsC.AssertNoReceive()
+
+	// ...but wanting them again triggers an event right away.
+	f.WantLeaderSettingsEvents(true)
+	leaderSettingsC.AssertOneReceive()
+
+	// Wanting them when they're already wanted changes nothing.
+	f.WantLeaderSettingsEvents(true)
+	leaderSettingsC.AssertNoReceive()
+}
+
+func (s *FilterSuite) TestResourcesEvents(c *gc.C) {
+	_, err := s.wordpress.SetResource("jar", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	resourcesC := s.contentAsserterC(c, f.ResourcesEvents())
+	// Initial resources are delivered, so that the uniter can compare
+	// them with the revisions it last saw.
+	c.Assert(resourcesC.AssertOneReceive(), gc.DeepEquals, map[string]int{"jar": 1})
+
+	// Unrelated service changes do not trigger an event.
+	err = s.wordpress.SetExposed()
+	c.Assert(err, jc.ErrorIsNil)
+	resourcesC.AssertNoReceive()
+
+	// A new revision triggers an event.
+	_, err = s.wordpress.SetResource("jar", strings.NewReader("new content"), 11)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resourcesC.AssertOneReceive(), gc.DeepEquals, map[string]int{"jar": 2})
+
+	// So does a new resource.
+	_, err = s.wordpress.SetResource("war", strings.NewReader("content"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(resourcesC.AssertOneReceive(), gc.DeepEquals, map[string]int{"jar": 2, "war": 1})
+}
+
+func (s *FilterSuite) TestResourcesEventsWithoutResources(c *gc.C) {
+	f, err := filter.NewFilter(s.uniter, s.unit.Tag().(names.UnitTag))
+	c.Assert(err, jc.ErrorIsNil)
+	defer statetesting.AssertStop(c, f)
+	resourcesC := s.contentAsserterC(c, f.ResourcesEvents())
+	resourcesC.AssertNoReceive()
+}
+
+func (s *FilterSuite) setLeaderSetting(c *gc.C, key, value string) {
+	settings, err := s.State.ReadLeadershipSettings(s.wordpress.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set(key, value)
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (s *FilterSuite) TestStorageEvents(c *gc.C) {
 	storageCharm := s.AddTestingCharm(c, "storage-block2")
 	svc := s.AddTestingServiceWithStorage(c, "storage-block2", storageCharm, map[string]state.StorageConstraints{
@@ -58,6 +58,21 @@ type Filter interface {
 	// associated storage instances whose Life status has changed.
 	StorageEvents() <-chan []names.StorageTag
 
+	// LeaderSettingsEvents returns a channel that will receive a signal whenever
+	// the service's leader settings change, as long as leader settings events
+	// are wanted.
+	LeaderSettingsEvents() <-chan struct{}
+
+	// ResourcesEvents returns a channel that will receive the revisions of all
+	// the service's resources when they are first read, and whenever a new
+	// revision of one of them is uploaded.
+	ResourcesEvents() <-chan map[string]int
+
+	// WantLeaderSettingsEvents controls whether the filter will generate leader
+	// settings events. Leader settings events are wanted initially; if they are
+	// turned off and then on again, an event will be sent immediately.
+	WantLeaderSettingsEvents(wanted bool)
+
 	// WantUpgradeEvent controls whether the filter will generate upgrade
 	// events for unforced service charm changes.
 	WantUpgradeEvent(mustForce bool)
@@ -43,6 +43,9 @@ var validateTests = []struct {
 	{hook.Info{Kind: hooks.ConfigChanged}, ""},
 	{hook.Info{Kind: hooks.CollectMetrics}, ""},
 	{hook.Info{Kind: hooks.MeterStatusChanged}, ""},
+	{hook.Info{Kind: hooks.LeaderElected}, ""},
+	{hook.Info{Kind: hooks.LeaderDeposed}, ""},
+	{hook.Info{Kind: hooks.LeaderSettingsChanged}, ""},
 	{hook.Info{Kind: hooks.Action}, "hooks.Kind Action is deprecated"},
 	{hook.Info{Kind: hooks.UpgradeCharm}, ""},
 	{hook.Info{Kind: hooks.Stop}, ""},
@@ -15,6 +15,8 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/state/watcher"
 	"github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/leadership"
+	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 )
 
@@ -152,6 +154,8 @@ func ModeTerminating(u *Uniter) (next Mode, err error) {
 // * service configuration changes
 // * charm upgrade requests
 // * relation changes
+// * leadership and leader settings changes
+// * requests to run hooks
 // * unit death
 func ModeAbide(u *Uniter) (next Mode, err error) {
 	defer modeContext("ModeAbide", &err)()
@@ -172,6 +176,8 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 		return nil, errors.Trace(err)
 	}
 	u.f.WantUpgradeEvent(false)
+	// Only minions need to know when the leader changes its settings.
+	u.f.WantLeaderSettingsEvents(!opState.Leader)
 	u.relations.StartHooks()
 	defer func() {
 		if e := u.relations.StopHooks(); e != nil {
@@ -194,6 +200,15 @@ func ModeAbide(u *Uniter) (next Mode, err error) {
 // modeAbideAliveLoop handles all state changes for ModeAbide when the unit
 // is in an Alive state.
 func modeAbideAliveLoop(u *Uniter) (Mode, error) {
+	// Wait for whichever leadership change would contradict the leadership
+	// we last recorded; we'll run the corresponding hook when it happens.
+	ticket := leadershipChangeTicket(u, u.operationState().Leader)
+	var leaderElected, leaderDeposed <-chan struct{}
+	if u.operationState().Leader {
+		leaderDeposed = ticket.Ready()
+	} else {
+		leaderElected = ticket.Ready()
+	}
 	for {
 		lastCollectMetrics := time.Unix(u.operationState().CollectMetricsTime, 0)
 		collectMetricsSignal := u.collectMetricsAt(
@@ -217,12 +232,46 @@ func modeAbideAliveLoop(u *Uniter) (Mode, error) {
 			creator = newSimpleRunHookOp(hooks.ConfigChanged)
 		case <-u.f.MeterStatusEvents():
 			creator = newSimpleRunHookOp(hooks.MeterStatusChanged)
+		case <-u.f.LeaderSettingsEvents():
+			creator = newSimpleRunHookOp(hooks.LeaderSettingsChanged)
+		case revisions := <-u.f.ResourcesEvents():
+			// A new resource revision is delivered to the charm as an
+			// upgrade-charm hook (followed by config-changed) without
+			// changing the charm itself. The revisions are compared with
+			// those recorded in the state file, so that uploads made while
+			// the agent was stopped are delivered too.
+			if sameResourceRevisions(revisions, u.operationState().ResourceRevisions) {
+				continue
+			}
+			return continueAfter(u, newRunHookOp(hook.Info{
+				Kind:              hooks.UpgradeCharm,
+				ResourceRevisions: revisions,
+			}))
+		case <-leaderElected:
+			u.leadershipTicket = nil
+			if !ticket.Wait() {
+				return nil, errLeadershipTrackerStopped
+			}
+			return continueAfter(u, newSimpleRunHookOp(hooks.LeaderElected))
+		case <-leaderDeposed:
+			u.leadershipTicket = nil
+			if !ticket.Wait() {
+				return nil, errLeadershipTrackerStopped
+			}
+			return continueAfter(u, newSimpleRunHookOp(hooks.LeaderDeposed))
 		case <-collectMetricsSignal:
 			creator = newSimpleRunHookOp(hooks.CollectMetrics)
 		case hookInfo := <-u.relations.Hooks():
 			creator = newRunHookOp(hookInfo)
 		case hookInfo := <-u.storage.Hooks():
 			creator = newRunHookOp(hookInfo)
+		case request := <-u.hookRequests:
+			hookInfo, err := u.requestedHook(request.args)
+			request.response <- hookResponse{hookInfo, err}
+			if err != nil {
+				continue
+			}
+			creator = newRunHookOp(hookInfo)
 		}
 		if err := u.runOperation(creator); err != nil {
 			return nil, errors.Trace(err)
@@ -256,6 +305,9 @@ func modeAbideDyingLoop(u *Uniter) (next Mode, err error) {
 			creator = newSimpleRunHookOp(hooks.ConfigChanged)
 		case hookInfo := <-u.relations.Hooks():
 			creator = newRunHookOp(hookInfo)
+		case request := <-u.hookRequests:
+			refuseHookRequest(request, "unit is dying")
+			continue
 		}
 		if err := u.runOperation(creator); err != nil {
 			return nil, errors.Trace(err)
@@ -300,6 +352,8 @@ func ModeHookError(u *Uniter) (next Mode, err error) {
 			return nil, tomb.ErrDying
 		case curl := <-u.f.UpgradeEvents():
 			return ModeUpgrading(curl), nil
+		case request := <-u.hookRequests:
+			refuseHookRequest(request, statusMessage)
 		case rm := <-u.f.ResolvedEvents():
 			var creator creator
 			switch rm {
@@ -334,18 +388,42 @@ func ModeConflicted(curl *charm.URL) Mode {
 		u.f.WantResolvedEvent()
 		u.f.WantUpgradeEvent(true)
 		var creator creator
-		select {
-		case <-u.tomb.Dying():
-			return nil, tomb.ErrDying
-		case curl = <-u.f.UpgradeEvents():
-			creator = newRevertUpgradeOp(curl)
-		case <-u.f.ResolvedEvents():
-			creator = newResolvedUpgradeOp(curl)
+		for creator == nil {
+			select {
+			case <-u.tomb.Dying():
+				return nil, tomb.ErrDying
+			case curl = <-u.f.UpgradeEvents():
+				creator = newRevertUpgradeOp(curl)
+			case <-u.f.ResolvedEvents():
+				creator = newResolvedUpgradeOp(curl)
+			case request := <-u.hookRequests:
+				refuseHookRequest(request, "upgrade failed")
+			}
 		}
 		return continueAfter(u, creator)
 	}
 }
 
+// leadershipChangeTicket returns a ticket that becomes ready when the
+// unit's leadership no longer matches leader. A pending ticket left by an
+// earlier call is returned again, rather than a new one being requested,
+// unless it was requested for different leadership.
+func leadershipChangeTicket(u *Uniter, leader bool) leadership.Ticket {
+	if u.leadershipTicket == nil || u.leadershipTicketLeader != leader {
+		if leader {
+			u.leadershipTicket = u.leadershipTracker.WaitMinion()
+		} else {
+			u.leadershipTicket = u.leadershipTracker.WaitLeader()
+		}
+		u.leadershipTicketLeader = leader
+	}
+	return u.leadershipTicket
+}
+
+// errLeadershipTrackerStopped is returned when a leadership ticket is resolved
+// without success, which only happens when the tracker has stopped.
+var errLeadershipTrackerStopped = errors.New("leadership tracker stopped")
+
 // modeContext returns a function that implements logging and common error
 // manipulation for Mode funcs.
 func modeContext(name string, err *error) func() {
@@ -364,3 +442,17 @@ func continueAfter(u *Uniter, creator creator) (Mode, error) {
 	}
 	return ModeContinue, nil
 }
+
+// sameResourceRevisions returns whether the supplied resource revisions
+// are identical.
+func sameResourceRevisions(current, recorded map[string]int) bool {
+	if len(current) != len(recorded) {
+		return false
+	}
+	for name, revision := range current {
+		if recorded[name] != revision {
+			return false
+		}
+	}
+	return true
+}
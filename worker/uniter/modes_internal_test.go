@@ -0,0 +1,66 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package uniter
+
+import (
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/worker/leadership"
+)
+
+type modesSuite struct{}
+
+var _ = gc.Suite(&modesSuite{})
+
+type fakeTicket struct {
+	ready chan struct{}
+}
+
+func (t *fakeTicket) Wait() bool {
+	<-t.ready
+	return true
+}
+
+func (t *fakeTicket) Ready() <-chan struct{} {
+	return t.ready
+}
+
+type fakeTracker struct {
+	leadership.Tracker
+	waitLeader int
+	waitMinion int
+}
+
+func (t *fakeTracker) WaitLeader() leadership.Ticket {
+	t.waitLeader++
+	return &fakeTicket{make(chan struct{})}
+}
+
+func (t *fakeTracker) WaitMinion() leadership.Ticket {
+	t.waitMinion++
+	return &fakeTicket{make(chan struct{})}
+}
+
+func (s *modesSuite) TestLeadershipChangeTicketReused(c *gc.C) {
+	tracker := &fakeTracker{}
+	u := &Uniter{leadershipTracker: tracker}
+
+	// A pending ticket is reused for as long as the recorded
+	// leadership stays the same.
+	ticket := leadershipChangeTicket(u, false)
+	c.Check(leadershipChangeTicket(u, false), gc.Equals, ticket)
+	c.Check(tracker.waitLeader, gc.Equals, 1)
+
+	// It is replaced when the recorded leadership changes...
+	minionTicket := leadershipChangeTicket(u, true)
+	c.Check(minionTicket, gc.Not(gc.Equals), ticket)
+	c.Check(leadershipChangeTicket(u, true), gc.Equals, minionTicket)
+	c.Check(tracker.waitMinion, gc.Equals, 1)
+
+	// ...or once it has been used.
+	u.leadershipTicket = nil
+	c.Check(leadershipChangeTicket(u, true), gc.Not(gc.Equals), minionTicket)
+	c.Check(tracker.waitMinion, gc.Equals, 2)
+	c.Check(tracker.waitLeader, gc.Equals, 1)
+}
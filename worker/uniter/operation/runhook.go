@@ -53,11 +53,22 @@ func (rh *runHook) Prepare(state State) (*State, error) {
 	}
 	rh.name = name
 	rh.runner = rnr
-	return stateChange{
+	newState := stateChange{
 		Kind: RunHook,
 		Step: Pending,
 		Hook: &rh.info,
-	}.apply(state), nil
+	}.apply(state)
+	if rh.info.Kind == hooks.LeaderElected {
+		// Record leadership before the hook runs, so that we never
+		// fail to run leader-deposed after an interrupted leader-elected.
+		newState.Leader = true
+	}
+	if rh.info.Kind == hooks.UpgradeCharm && rh.info.ResourceRevisions != nil {
+		// Likewise, record the resource revisions before the hook runs;
+		// an interrupted hook is resumed with the same revisions.
+		newState.ResourceRevisions = rh.info.ResourceRevisions
+	}
+	return newState, nil
 }
 
 // Execute runs the hook.
@@ -105,8 +116,9 @@ func (rh *runHook) Execute(state State) (*State, error) {
 }
 
 // Commit updates relation state to include the fact of the hook's execution,
-// records the impact of start and collect-metrics hooks, and queues follow-up
-// config-changed hooks to directly follow install and upgrade-charm hooks.
+// records the impact of start, collect-metrics and leader-deposed hooks, and
+// queues follow-up config-changed hooks to directly follow install and
+// upgrade-charm hooks.
 // Commit is part of the Operation interface.
 func (rh *runHook) Commit(state State) (*State, error) {
 	if err := rh.callbacks.CommitHook(rh.info); err != nil {
@@ -140,6 +152,8 @@ func (rh *runHook) Commit(state State) (*State, error) {
 		newState.Started = true
 	case hooks.CollectMetrics:
 		newState.CollectMetricsTime = time.Now().Unix()
+	case hooks.LeaderDeposed:
+		newState.Leader = false
 	}
 	return newState, nil
 }
@@ -161,6 +161,63 @@ func (s *RunHookSuite) TestPrepareSuccess_Preserve(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestPrepareSuccess_LeaderElected_SetLeader(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+	} {
+		c.Logf("variant %d", i)
+		runnerFactory := NewRunHookRunnerFactory(errors.New("should not call"))
+		callbacks := NewPrepareHookCallbacks()
+		factory := operation.NewFactory(nil, runnerFactory, callbacks, nil, nil)
+		op, err := newHook(factory, hook.Info{Kind: hooks.LeaderElected})
+		c.Assert(err, jc.ErrorIsNil)
+
+		newState, err := op.Prepare(overwriteState)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(newState, gc.DeepEquals, &operation.State{
+			Leader:             true,
+			Started:            true,
+			CollectMetricsTime: 1234567,
+			Kind:               operation.RunHook,
+			Step:               operation.Pending,
+			Hook:               &hook.Info{Kind: hooks.LeaderElected},
+		})
+	}
+}
+
+func (s *RunHookSuite) TestPrepareSuccess_UpgradeCharm_SetResourceRevisions(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+	} {
+		c.Logf("variant %d", i)
+		runnerFactory := NewRunHookRunnerFactory(errors.New("should not call"))
+		callbacks := NewPrepareHookCallbacks()
+		factory := operation.NewFactory(nil, runnerFactory, callbacks, nil, nil)
+		revisions := map[string]int{"jar": 3}
+		op, err := newHook(factory, hook.Info{
+			Kind:              hooks.UpgradeCharm,
+			ResourceRevisions: revisions,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+
+		newState, err := op.Prepare(overwriteState)
+		c.Check(err, jc.ErrorIsNil)
+		c.Check(newState, gc.DeepEquals, &operation.State{
+			Started:            true,
+			CollectMetricsTime: 1234567,
+			Kind:               operation.RunHook,
+			Step:               operation.Pending,
+			Hook: &hook.Info{
+				Kind:              hooks.UpgradeCharm,
+				ResourceRevisions: revisions,
+			},
+			ResourceRevisions: revisions,
+		})
+	}
+}
+
 func (s *RunHookSuite) testExecuteLockError(c *gc.C, newHook newHook) {
 	runnerFactory := NewRunHookRunnerFactory(errors.New("should not call"))
 	callbacks := &ExecuteHookCallbacks{
@@ -486,6 +543,55 @@ func (s *RunHookSuite) TestCommitSuccess_Start_Preserve(c *gc.C) {
 	}
 }
 
+func (s *RunHookSuite) TestCommitSuccess_LeaderDeposed_ClearLeader(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+		(operation.Factory).NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hooks.LeaderDeposed},
+			operation.State{
+				Leader:  true,
+				Started: true,
+			},
+			operation.State{
+				Started: true,
+				Kind:    operation.Continue,
+				Step:    operation.Pending,
+				Hook:    &hook.Info{Kind: hooks.LeaderDeposed},
+			},
+		)
+	}
+}
+
+func (s *RunHookSuite) TestCommitSuccess_LeaderElected_PreserveLeader(c *gc.C) {
+	for i, newHook := range []newHook{
+		(operation.Factory).NewRunHook,
+		(operation.Factory).NewRetryHook,
+		(operation.Factory).NewSkipHook,
+	} {
+		c.Logf("variant %d", i)
+		s.testCommitSuccess(c,
+			newHook,
+			hook.Info{Kind: hooks.LeaderElected},
+			operation.State{
+				Leader:  true,
+				Started: true,
+			},
+			operation.State{
+				Leader:  true,
+				Started: true,
+				Kind:    operation.Continue,
+				Step:    operation.Pending,
+				Hook:    &hook.Info{Kind: hooks.LeaderElected},
+			},
+		)
+	}
+}
+
 func (s *RunHookSuite) testQueueHook_BlankSlate(c *gc.C, cause, effect hooks.Kind) {
 	for i, newHook := range []newHook{
 		(operation.Factory).NewRunHook,
@@ -89,6 +89,10 @@ type State struct {
 	// It's set to nil if the hook was not run at all. Recording time as int64
 	// because the yaml encoder cannot encode the time.Time struct.
 	CollectMetricsTime int64 `yaml:"collectmetricstime,omitempty"`
+
+	// ResourceRevisions records the revisions of the service's resources
+	// that were last delivered to the charm by an upgrade-charm hook.
+	ResourceRevisions map[string]int `yaml:"resources,omitempty"`
 }
 
 // validate returns an error if the state violates expectations.
@@ -43,6 +43,11 @@ func (paths Paths) GetCharmDir() string {
 	return paths.State.CharmDir
 }
 
+// GetResourcesDir exists to satisfy the context.Paths interface.
+func (paths Paths) GetResourcesDir() string {
+	return paths.State.ResourcesDir
+}
+
 // GetJujucSocket exists to satisfy the context.Paths interface.
 func (paths Paths) GetJujucSocket() string {
 	return paths.Runtime.JujucServerSocket
@@ -85,6 +90,10 @@ type StatePaths struct {
 	// StorageDir holds storage-specific information about what the
 	// uniter is doing and/or has done.
 	StorageDir string
+
+	// ResourcesDir holds the service resources downloaded by the
+	// resource-get hook tool.
+	ResourcesDir string
 }
 
 // NewPaths returns the set of filesystem paths that the supplied unit should
@@ -120,6 +129,7 @@ func NewPaths(dataDir string, unitTag names.UnitTag) Paths {
 			BundlesDir:     join(stateDir, "bundles"),
 			DeployerDir:    join(stateDir, "deployer"),
 			StorageDir:     join(stateDir, "storage"),
+			ResourcesDir:   join(baseDir, "resources"),
 		},
 	}
 }
@@ -50,6 +50,7 @@ func (s *PathsSuite) TestWindows(c *gc.C) {
 			BundlesDir:     relAgent("state", "bundles"),
 			DeployerDir:    relAgent("state", "deployer"),
 			StorageDir:     relAgent("state", "storage"),
+			ResourcesDir:   relAgent("resources"),
 		},
 	})
 }
@@ -76,6 +77,7 @@ func (s *PathsSuite) TestOther(c *gc.C) {
 			BundlesDir:     relAgent("state", "bundles"),
 			DeployerDir:    relAgent("state", "deployer"),
 			StorageDir:     relAgent("state", "storage"),
+			ResourcesDir:   relAgent("resources"),
 		},
 	})
 }
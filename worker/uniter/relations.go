@@ -48,6 +48,14 @@ type Relations interface {
 	// GetInfo returns information about current relation state.
 	GetInfo() map[int]*runner.RelationInfo
 
+	// ChangedHook returns the hook info needed to run, on request, the
+	// relation-changed hook of the named endpoint for the relation with
+	// the supplied id and the supplied remote unit. If relationId is -1
+	// the unit's only relation of that name is used, and if remoteUnit
+	// is empty the relation's only remote unit is used. An error is
+	// returned if the hook is invalid given current relation state.
+	ChangedHook(name string, relationId int, remoteUnit string) (hook.Info, error)
+
 	// Update checks for and responds to changes in the life states of the
 	// relations with the supplied ids. If any id corresponds to an alive
 	// relation that is not already recorded, the unit will enter scope for
@@ -205,6 +213,52 @@ func (r *relations) GetInfo() map[int]*runner.RelationInfo {
 	return relationInfos
 }
 
+// ChangedHook is part of the Relations interface.
+func (r *relations) ChangedHook(name string, relationId int, remoteUnit string) (hook.Info, error) {
+	var relationer *Relationer
+	if relationId == -1 {
+		for id, candidate := range r.relationers {
+			if candidate.ru.Endpoint().Name != name {
+				continue
+			}
+			if relationer != nil {
+				return hook.Info{}, errors.Errorf("unit has more than one %q relation; a relation id must be specified", name)
+			}
+			relationer, relationId = candidate, id
+		}
+		if relationer == nil {
+			return hook.Info{}, errors.Errorf("unit has no %q relation", name)
+		}
+	} else {
+		relationer = r.relationers[relationId]
+		if relationer == nil || relationer.ru.Endpoint().Name != name {
+			return hook.Info{}, errors.Errorf("unit has no %q relation with id %d", name, relationId)
+		}
+	}
+	if relationer.IsImplicit() {
+		return hook.Info{}, errors.Errorf("relation %d is implicit and does not run hooks", relationId)
+	}
+	state := relationer.dir.State()
+	if remoteUnit == "" {
+		if len(state.Members) != 1 {
+			return hook.Info{}, errors.Errorf("relation %d has %d remote units; a remote unit must be specified", relationId, len(state.Members))
+		}
+		for unitName := range state.Members {
+			remoteUnit = unitName
+		}
+	}
+	hookInfo := hook.Info{
+		Kind:          hooks.RelationChanged,
+		RelationId:    relationId,
+		RemoteUnit:    remoteUnit,
+		ChangeVersion: state.Members[remoteUnit],
+	}
+	if err := state.Validate(hookInfo); err != nil {
+		return hook.Info{}, errors.Trace(err)
+	}
+	return hookInfo, nil
+}
+
 // Update is part of the Relations interface.
 func (r *relations) Update(ids []int) error {
 	for _, id := range ids {
@@ -15,9 +15,13 @@ import (
 	"github.com/juju/utils/exec"
 
 	"github.com/juju/juju/juju/sockets"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
-const JujuRunEndpoint = "JujuRunServer.RunCommands"
+const (
+	JujuRunEndpoint     = "JujuRunServer.RunCommands"
+	JujuRunHookEndpoint = "JujuRunServer.RunHook"
+)
 
 // RunCommandsArgs stores the arguments for a RunCommands call.
 type RunCommandsArgs struct {
@@ -31,11 +35,25 @@ type RunCommandsArgs struct {
 	ForceRemoteUnit bool
 }
 
+// RunHookArgs stores the arguments for a RunHook call.
+type RunHookArgs struct {
+	// Hook is the name of the hook to run, such as "config-changed"
+	// or "db-relation-changed".
+	Hook string
+	// RelationId is the relation to run a relation hook for, or -1
+	// if it should be inferred from the hook name.
+	RelationId int
+	// RemoteUnitName is the remote unit to run a relation hook for.
+	RemoteUnitName string
+}
+
 // A CommandRunner is something that will actually execute the commands and
 // return the results of that execution in the exec.ExecResponse (which
-// contains stdout, stderr, and return code).
+// contains stdout, stderr, and return code). It also queues hooks to be
+// run on request.
 type CommandRunner interface {
 	RunCommands(RunCommandsArgs RunCommandsArgs) (results *exec.ExecResponse, err error)
+	RunHook(RunHookArgs RunHookArgs) (hook.Info, error)
 }
 
 // RunListener is responsible for listening on the network connection and
@@ -67,6 +85,19 @@ func (r *JujuRunServer) RunCommands(args RunCommandsArgs, result *exec.ExecRespo
 	return err
 }
 
+// RunHook delegates the queueing of the hook to the runner and returns
+// the details of the hook queued.
+func (r *JujuRunServer) RunHook(args RunHookArgs, result *hook.Info) error {
+	logger.Debugf("RunHook: %+v", args)
+	info, err := r.runner.RunHook(args)
+	if err != nil {
+		// The error is reported to the user as it stands.
+		return errors.Trace(err)
+	}
+	*result = info
+	return nil
+}
+
 // NewRunListener returns a new RunListener that is listening on given
 // socket or named pipe passed in. If a valid RunListener is returned, is
 // has the go routine running, and should be closed by the creator
@@ -7,13 +7,16 @@ import (
 	"path/filepath"
 	"runtime"
 
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/exec"
 	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v4/hooks"
 
 	"github.com/juju/juju/juju/sockets"
 	"github.com/juju/juju/testing"
 	"github.com/juju/juju/worker/uniter"
+	"github.com/juju/juju/worker/uniter/hook"
 )
 
 type ListenerSuite struct {
@@ -81,6 +84,32 @@ func (s *ListenerSuite) TestClientCall(c *gc.C) {
 	c.Assert(result.Code, gc.Equals, 42)
 }
 
+func (s *ListenerSuite) TestClientCallRunHook(c *gc.C) {
+	s.NewRunListener(c)
+
+	client, err := sockets.Dial(s.socketPath)
+	c.Assert(err, jc.ErrorIsNil)
+	defer client.Close()
+
+	var result hook.Info
+	args := uniter.RunHookArgs{
+		Hook:           "db-relation-changed",
+		RelationId:     3,
+		RemoteUnitName: "mysql/0",
+	}
+	err = client.Call(uniter.JujuRunHookEndpoint, args, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, hook.Info{
+		Kind:       hooks.RelationChanged,
+		RelationId: 3,
+		RemoteUnit: "mysql/0",
+	})
+
+	args = uniter.RunHookArgs{Hook: "install", RelationId: -1}
+	err = client.Call(uniter.JujuRunHookEndpoint, args, &result)
+	c.Assert(err, gc.ErrorMatches, `cannot run "install" hook on request`)
+}
+
 type mockRunner struct {
 	c *gc.C
 }
@@ -95,3 +124,15 @@ func (r *mockRunner) RunCommands(args uniter.RunCommandsArgs) (results *exec.Exe
 		Stderr: []byte(args.Commands + " stderr"),
 	}, nil
 }
+
+func (r *mockRunner) RunHook(args uniter.RunHookArgs) (hook.Info, error) {
+	r.c.Log("mock runner: hook " + args.Hook)
+	if args.Hook == "install" {
+		return hook.Info{}, errors.Errorf("cannot run %q hook on request", args.Hook)
+	}
+	return hook.Info{
+		Kind:       hooks.RelationChanged,
+		RelationId: args.RelationId,
+		RemoteUnit: args.RemoteUnitName,
+	}, nil
+}
@@ -136,6 +136,10 @@ type HookContext struct {
 
 	// storageId is the tag of the storage instance associated with the running hook.
 	storageTag names.StorageTag
+
+	// resourcesDir is the directory into which service resources are
+	// downloaded.
+	resourcesDir string
 }
 
 func (ctx *HookContext) RequestReboot(priority jujuc.RebootPriority) error {
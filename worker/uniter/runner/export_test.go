@@ -22,6 +22,7 @@ var (
 	ValidatePortRange = validatePortRange
 	TryOpenPorts      = tryOpenPorts
 	TryClosePorts     = tryClosePorts
+	DownloadResource  = downloadResource
 )
 
 func RunnerPaths(rnr Runner) Paths {
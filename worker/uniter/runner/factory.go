@@ -273,6 +273,7 @@ func (f *factory) coreContext() (*HookContext, error) {
 		definedMetrics:     nil,
 		pendingPorts:       make(map[PortRange]PortRangeInfo),
 		storage:            f.storage,
+		resourcesDir:       f.paths.GetResourcesDir(),
 	}
 	if err := f.updateContext(ctx); err != nil {
 		return nil, err
@@ -122,6 +122,11 @@ type Context interface {
 	// HookStorageAttachment returns the storage attachment associated
 	// the executing hook if it was found, and whether it was found.
 	HookStorage() (ContextStorage, bool)
+
+	// DownloadResource ensures that the current revision of the named
+	// resource of the executing unit's service has been downloaded,
+	// and returns the path of the local copy.
+	DownloadResource(name string) (string, error)
 }
 
 // ContextRelation expresses the capabilities of a hook with respect to a relation.
@@ -148,6 +153,11 @@ type ContextRelation interface {
 
 	// ReadSettings returns the settings of any remote unit in the relation.
 	ReadSettings(unit string) (params.Settings, error)
+
+	// PrivateAddress returns the address the executing unit advertises
+	// on the relation: its address in the space the relation's endpoint
+	// is bound to, if any, and otherwise its private address.
+	PrivateAddress() (string, error)
 }
 
 // ContextStorage expresses the capabilities of a hook with respect to a
@@ -0,0 +1,62 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"launchpad.net/gnuflag"
+)
+
+// resourceGetCommand implements the resource-get command.
+type resourceGetCommand struct {
+	cmd.CommandBase
+	ctx  Context
+	name string
+	out  cmd.Output
+}
+
+// NewResourceGetCommand returns a new resourceGetCommand with the given context.
+func NewResourceGetCommand(ctx Context) cmd.Command {
+	return &resourceGetCommand{ctx: ctx}
+}
+
+// Info is part of the cmd.Command interface.
+func (c *resourceGetCommand) Info() *cmd.Info {
+	doc := `
+resource-get downloads the current revision of the named resource of the
+unit's service, unless the local copy is already up to date, and prints the
+path of the local copy. The file is replaced atomically, so a path printed
+earlier always refers to complete content.
+`
+	return &cmd.Info{
+		Name:    "resource-get",
+		Args:    "<name>",
+		Purpose: "fetch a service resource",
+		Doc:     doc,
+	}
+}
+
+// SetFlags is part of the cmd.Command interface.
+func (c *resourceGetCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+}
+
+// Init is part of the cmd.Command interface.
+func (c *resourceGetCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("no resource name specified")
+	}
+	c.name = args[0]
+	return cmd.CheckEmpty(args[1:])
+}
+
+// Run is part of the cmd.Command interface.
+func (c *resourceGetCommand) Run(ctx *cmd.Context) error {
+	path, err := c.ctx.DownloadResource(c.name)
+	if err != nil {
+		return errors.Annotatef(err, "cannot get resource %q", c.name)
+	}
+	return c.out.Write(ctx, path)
+}
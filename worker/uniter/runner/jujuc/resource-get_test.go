@@ -0,0 +1,75 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jujuc_test
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/uniter/runner/jujuc"
+)
+
+type resourceGetSuite struct {
+	jujutesting.IsolationSuite
+}
+
+var _ = gc.Suite(&resourceGetSuite{})
+
+func (s *resourceGetSuite) TestInitNoName(c *gc.C) {
+	command := jujuc.NewResourceGetCommand(nil)
+	err := command.Init(nil)
+	c.Assert(err, gc.ErrorMatches, "no resource name specified")
+}
+
+func (s *resourceGetSuite) TestInitTooManyArgs(c *gc.C) {
+	command := jujuc.NewResourceGetCommand(nil)
+	err := command.Init([]string{"jar", "blah"})
+	c.Assert(err, gc.ErrorMatches, `unrecognized args: \["blah"\]`)
+}
+
+func (s *resourceGetSuite) TestDownload(c *gc.C) {
+	jujucContext := &resourceGetContext{path: "/path/to/jar"}
+	command := jujuc.NewResourceGetCommand(jujucContext)
+	runContext := testing.Context(c)
+	code := cmd.Main(command, runContext, []string{"jar"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(jujucContext.name, gc.Equals, "jar")
+	c.Check(bufferString(runContext.Stdout), gc.Equals, "/path/to/jar\n")
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "")
+}
+
+func (s *resourceGetSuite) TestDownloadJSON(c *gc.C) {
+	jujucContext := &resourceGetContext{path: "/path/to/jar"}
+	command := jujuc.NewResourceGetCommand(jujucContext)
+	runContext := testing.Context(c)
+	code := cmd.Main(command, runContext, []string{"--format", "json", "jar"})
+	c.Check(code, gc.Equals, 0)
+	c.Check(bufferString(runContext.Stdout), jc.JSONEquals, "/path/to/jar")
+}
+
+func (s *resourceGetSuite) TestDownloadError(c *gc.C) {
+	jujucContext := &resourceGetContext{err: errors.New("zap")}
+	command := jujuc.NewResourceGetCommand(jujucContext)
+	runContext := testing.Context(c)
+	code := cmd.Main(command, runContext, []string{"jar"})
+	c.Check(code, gc.Equals, 1)
+	c.Check(bufferString(runContext.Stdout), gc.Equals, "")
+	c.Check(bufferString(runContext.Stderr), gc.Equals, "error: cannot get resource \"jar\": zap\n")
+}
+
+type resourceGetContext struct {
+	jujuc.Context
+	name string
+	path string
+	err  error
+}
+
+func (ctx *resourceGetContext) DownloadResource(name string) (string, error) {
+	ctx.name = name
+	return ctx.path, ctx.err
+}
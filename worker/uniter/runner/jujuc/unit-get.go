@@ -4,19 +4,20 @@
 package jujuc
 
 import (
-	"errors"
 	"fmt"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	"launchpad.net/gnuflag"
 )
 
 // UnitGetCommand implements the unit-get command.
 type UnitGetCommand struct {
 	cmd.CommandBase
-	ctx Context
-	Key string
-	out cmd.Output
+	ctx        Context
+	Key        string
+	RelationId int
+	out        cmd.Output
 }
 
 func NewUnitGetCommand(ctx Context) cmd.Command {
@@ -28,11 +29,22 @@ func (c *UnitGetCommand) Info() *cmd.Info {
 		Name:    "unit-get",
 		Args:    "<setting>",
 		Purpose: "print public-address or private-address",
+		Doc:     unitGetDoc,
 	}
 }
 
+const unitGetDoc = `
+When run in the context of a relation, or when a relation is given with
+-r, private-address is the address the unit advertises on that relation.
+If the relation's endpoint is bound to a space, this is the unit's address
+in that space.
+`
+
 func (c *UnitGetCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.out.AddFlags(f, "smart", cmd.DefaultFormatters)
+	rV := newRelationIdValue(c.ctx, &c.RelationId)
+	f.Var(rV, "r", "specify a relation by id")
+	f.Var(rV, "relation", "")
 }
 
 func (c *UnitGetCommand) Init(args []string) error {
@@ -49,7 +61,11 @@ func (c *UnitGetCommand) Init(args []string) error {
 func (c *UnitGetCommand) Run(ctx *cmd.Context) error {
 	value, ok := "", false
 	if c.Key == "private-address" {
-		value, ok = c.ctx.PrivateAddress()
+		var err error
+		value, ok, err = c.privateAddress()
+		if err != nil {
+			return err
+		}
 	} else {
 		value, ok = c.ctx.PublicAddress()
 	}
@@ -58,3 +74,22 @@ func (c *UnitGetCommand) Run(ctx *cmd.Context) error {
 	}
 	return c.out.Write(ctx, value)
 }
+
+// privateAddress returns the unit's private address. On a relation,
+// this is the address the unit advertises on it, which respects the
+// space the relation's endpoint is bound to.
+func (c *UnitGetCommand) privateAddress() (string, bool, error) {
+	if c.RelationId != -1 {
+		r, found := c.ctx.Relation(c.RelationId)
+		if !found {
+			return "", false, fmt.Errorf("unknown relation id")
+		}
+		address, err := r.PrivateAddress()
+		if err != nil {
+			return "", false, errors.Annotate(err, "cannot get relation address")
+		}
+		return address, true, nil
+	}
+	value, ok := c.ctx.PrivateAddress()
+	return value, ok, nil
+}
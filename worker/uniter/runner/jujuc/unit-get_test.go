@@ -65,6 +65,13 @@ options:
     specify output format (json|smart|yaml)
 -o, --output (= "")
     specify an output file
+-r, --relation  (= )
+    specify a relation by id
+
+When run in the context of a relation, or when a relation is given with
+-r, private-address is the address the unit advertises on that relation.
+If the relation's endpoint is bound to a space, this is the unit's address
+in that space.
 `)
 	c.Assert(bufferString(ctx.Stderr), gc.Equals, "")
 }
@@ -81,6 +88,44 @@ func (s *UnitGetSuite) TestOutputPath(c *gc.C) {
 	c.Assert(string(content), gc.Equals, "192.168.0.99\n")
 }
 
+func (s *UnitGetSuite) TestRelationPrivateAddress(c *gc.C) {
+	// The address comes from the endpoint's binding, not from
+	// the settings the charm controls.
+	s.rels[1].privateAddress = "10.0.1.99"
+	s.rels[1].units["u/0"]["private-address"] = "10.0.2.99"
+	for i, t := range []struct {
+		relid int
+		args  []string
+		out   string
+	}{{
+		relid: -1,
+		args:  []string{"private-address"},
+		out:   "192.168.0.99\n",
+	}, {
+		relid: 1,
+		args:  []string{"private-address"},
+		out:   "10.0.1.99\n",
+	}, {
+		relid: -1,
+		args:  []string{"private-address", "-r", "peer1:1"},
+		out:   "10.0.1.99\n",
+	}, {
+		relid: 1,
+		args:  []string{"public-address"},
+		out:   "gimli.minecraft.testing.invalid\n",
+	}} {
+		c.Logf("test %d: %v", i, t.args)
+		hctx := s.GetHookContext(c, t.relid, "")
+		com, err := jujuc.NewCommand(hctx, cmdString("unit-get"))
+		c.Assert(err, jc.ErrorIsNil)
+		ctx := testing.Context(c)
+		code := cmd.Main(com, ctx, t.args)
+		c.Check(code, gc.Equals, 0)
+		c.Check(bufferString(ctx.Stderr), gc.Equals, "")
+		c.Check(bufferString(ctx.Stdout), gc.Equals, t.out)
+	}
+}
+
 func (s *UnitGetSuite) TestUnknownSetting(c *gc.C) {
 	com := s.createCommand(c)
 	err := testing.InitCommand(com, []string{"protected-address"})
@@ -233,9 +233,10 @@ func (c *Context) OwnerTag() string {
 }
 
 type ContextRelation struct {
-	id    int
-	name  string
-	units map[string]Settings
+	id             int
+	name           string
+	units          map[string]Settings
+	privateAddress string
 }
 
 func (r *ContextRelation) Id() int {
@@ -263,6 +264,13 @@ func (r *ContextRelation) UnitNames() []string {
 	return s
 }
 
+func (r *ContextRelation) PrivateAddress() (string, error) {
+	if r.privateAddress == "" {
+		return "", fmt.Errorf("no private address set")
+	}
+	return r.privateAddress, nil
+}
+
 func (r *ContextRelation) ReadSettings(name string) (params.Settings, error) {
 	s, found := r.units[name]
 	if !found {
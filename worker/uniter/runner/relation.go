@@ -60,6 +60,10 @@ func (ctx *ContextRelation) ReadSettings(unit string) (settings params.Settings,
 	return ctx.cache.Settings(unit)
 }
 
+func (ctx *ContextRelation) PrivateAddress() (string, error) {
+	return ctx.ru.PrivateAddress()
+}
+
 func (ctx *ContextRelation) Settings() (jujuc.Settings, error) {
 	if ctx.settings == nil {
 		node, err := ctx.ru.Settings()
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+// resourceSource exposes the service resources available to a unit.
+// It is implemented by *uniter.Service.
+type resourceSource interface {
+	Resources() ([]params.ServiceResource, error)
+	OpenResource(name string) (io.ReadCloser, error)
+}
+
+// DownloadResource is part of the jujuc.Context interface.
+func (ctx *HookContext) DownloadResource(name string) (string, error) {
+	service, err := ctx.state.Service(ctx.unit.ServiceTag())
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	return downloadResource(service, name, ctx.resourcesDir)
+}
+
+// downloadResource ensures that dir holds the current revision of the
+// named resource, and returns the path to it. Content is only fetched
+// when the local copy is missing or out of date, and is verified
+// against the expected digest before it replaces the local copy.
+func downloadResource(source resourceSource, name, dir string) (string, error) {
+	resources, err := source.Resources()
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	var expected string
+	for _, resource := range resources {
+		if resource.Name == name {
+			expected = resource.SHA384
+			break
+		}
+	}
+	if expected == "" {
+		return "", errors.NotFoundf("resource %q", name)
+	}
+
+	path := filepath.Join(dir, name)
+	if digest, err := fileSHA384(path); err == nil && digest == expected {
+		return path, nil
+	} else if err != nil && !os.IsNotExist(err) {
+		return "", errors.Trace(err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", errors.Trace(err)
+	}
+	reader, err := source.OpenResource(name)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer reader.Close()
+	tempFile, err := ioutil.TempFile(dir, name+"-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	hash := sha512.New384()
+	_, err = io.Copy(io.MultiWriter(tempFile, hash), reader)
+	if closeErr := tempFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		if digest := fmt.Sprintf("%x", hash.Sum(nil)); digest != expected {
+			err = errors.Errorf("downloaded content has digest %q, expected %q", digest, expected)
+		}
+	}
+	if err == nil {
+		err = utils.ReplaceFile(tempFile.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tempFile.Name())
+		return "", errors.Trace(err)
+	}
+	return path, nil
+}
+
+// fileSHA384 returns the hex-encoded SHA384 digest of the file at path.
+func fileSHA384(path string) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	hash := sha512.New384()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner_test
+
+import (
+	"crypto/sha512"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/worker/uniter/runner"
+)
+
+type ResourcesSuite struct {
+	testing.IsolationSuite
+	source *stubResourceSource
+	dir    string
+}
+
+var _ = gc.Suite(&ResourcesSuite{})
+
+func (s *ResourcesSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.source = &stubResourceSource{}
+	s.source.set("jar", "content")
+	s.dir = filepath.Join(c.MkDir(), "resources")
+}
+
+func (s *ResourcesSuite) TestDownload(c *gc.C) {
+	path, err := runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(path, gc.Equals, filepath.Join(s.dir, "jar"))
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "content")
+	c.Assert(s.source.opened, gc.Equals, 1)
+}
+
+func (s *ResourcesSuite) TestDownloadUpToDate(c *gc.C) {
+	_, err := runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.source.opened, gc.Equals, 1)
+}
+
+func (s *ResourcesSuite) TestDownloadNewRevision(c *gc.C) {
+	path, err := runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	s.source.set("jar", "new content")
+	_, err = runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.source.opened, gc.Equals, 2)
+	data, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "new content")
+}
+
+func (s *ResourcesSuite) TestDownloadNotFound(c *gc.C) {
+	_, err := runner.DownloadResource(s.source, "war", s.dir)
+	c.Assert(err, gc.ErrorMatches, `resource "war" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ResourcesSuite) TestDownloadCorrupt(c *gc.C) {
+	s.source.content["jar"] = "corrupt"
+	_, err := runner.DownloadResource(s.source, "jar", s.dir)
+	c.Assert(err, gc.ErrorMatches, `downloaded content has digest ".*", expected ".*"`)
+	files, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(files, gc.HasLen, 0)
+}
+
+type stubResourceSource struct {
+	resources []params.ServiceResource
+	content   map[string]string
+	opened    int
+}
+
+func (s *stubResourceSource) set(name, content string) {
+	digest := sha512.Sum384([]byte(content))
+	s.resources = []params.ServiceResource{{
+		Name:     name,
+		Revision: len(s.resources) + 1,
+		Size:     int64(len(content)),
+		SHA384:   fmt.Sprintf("%x", digest),
+	}}
+	s.content = map[string]string{name: content}
+}
+
+func (s *stubResourceSource) Resources() ([]params.ServiceResource, error) {
+	return s.resources, nil
+}
+
+func (s *stubResourceSource) OpenResource(name string) (io.ReadCloser, error) {
+	s.opened++
+	return ioutil.NopCloser(strings.NewReader(s.content[name])), nil
+}
@@ -56,6 +56,10 @@ type Paths interface {
 	// the charm is installed.
 	GetCharmDir() string
 
+	// GetResourcesDir returns the filesystem path to the directory in
+	// which service resources are downloaded.
+	GetResourcesDir() string
+
 	// GetJujucSocket returns the path to the socket used by the hook tools
 	// to communicate back to the executing uniter process. It might be a
 	// filesystem path, or it might be abstract.
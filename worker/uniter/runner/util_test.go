@@ -44,15 +44,20 @@ func (MockEnvPaths) GetCharmDir() string {
 	return "path-to-charm"
 }
 
+func (MockEnvPaths) GetResourcesDir() string {
+	return "path-to-resources"
+}
+
 func (MockEnvPaths) GetJujucSocket() string {
 	return "path-to-jujuc.socket"
 }
 
 // RealPaths implements Paths for tests that do touch the filesystem.
 type RealPaths struct {
-	tools  string
-	charm  string
-	socket string
+	tools     string
+	charm     string
+	resources string
+	socket    string
 }
 
 func osDependentSockPath(c *gc.C) string {
@@ -65,9 +70,10 @@ func osDependentSockPath(c *gc.C) string {
 
 func NewRealPaths(c *gc.C) RealPaths {
 	return RealPaths{
-		tools:  c.MkDir(),
-		charm:  c.MkDir(),
-		socket: osDependentSockPath(c),
+		tools:     c.MkDir(),
+		charm:     c.MkDir(),
+		resources: c.MkDir(),
+		socket:    osDependentSockPath(c),
 	}
 }
 
@@ -79,6 +85,10 @@ func (p RealPaths) GetCharmDir() string {
 	return p.charm
 }
 
+func (p RealPaths) GetResourcesDir() string {
+	return p.resources
+}
+
 func (p RealPaths) GetJujucSocket() string {
 	return p.socket
 }
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/utils/exec"
 	"github.com/juju/utils/fslock"
 	corecharm "gopkg.in/juju/charm.v4"
+	"gopkg.in/juju/charm.v4/hooks"
 	"launchpad.net/tomb"
 
 	"github.com/juju/juju/api/uniter"
@@ -25,6 +26,7 @@ import (
 	"github.com/juju/juju/worker/leadership"
 	"github.com/juju/juju/worker/uniter/charm"
 	"github.com/juju/juju/worker/uniter/filter"
+	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner"
 	"github.com/juju/juju/worker/uniter/runner/jujuc"
@@ -66,9 +68,20 @@ type Uniter struct {
 	leadershipManager coreleadership.LeadershipManager
 	leadershipTracker leadership.Tracker
 
+	// leadershipTicket, if not nil, is a ticket that becomes ready when
+	// the unit's leadership no longer matches leadershipTicketLeader. It
+	// is kept until it is used, so that each return to ModeAbide does not
+	// leave another unresolved ticket queued in the leadership tracker.
+	leadershipTicket       leadership.Ticket
+	leadershipTicketLeader bool
+
 	hookLock    *fslock.Lock
 	runListener *RunListener
 
+	// hookRequests delivers requests to run hooks, received by the run
+	// listener, to the mode funcs.
+	hookRequests chan hookRequest
+
 	ranConfigChanged bool
 
 	// The execution observer is only used in tests at this stage. Should this
@@ -96,6 +109,7 @@ func NewUniter(
 		hookLock:          hookLock,
 		leadershipManager: leadershipManager,
 		collectMetricsAt:  inactiveMetricsTimer,
+		hookRequests:      make(chan hookRequest),
 	}
 	go func() {
 		defer u.tomb.Done()
@@ -363,6 +377,61 @@ func (u *Uniter) RunCommands(args RunCommandsArgs) (results *exec.ExecResponse,
 	return results, err
 }
 
+// hookRequest is a request to run a hook, which is answered on response
+// by the mode func that receives it.
+type hookRequest struct {
+	args     RunHookArgs
+	response chan<- hookResponse
+}
+
+// hookResponse holds the hook queued in answer to a hookRequest, or the
+// reason it was not.
+type hookResponse struct {
+	info hook.Info
+	err  error
+}
+
+// RunHook queues the hook described by args to be run like any hook run
+// in response to a change. It returns once the hook has been queued,
+// without waiting for it to run.
+func (u *Uniter) RunHook(args RunHookArgs) (hook.Info, error) {
+	logger.Tracef("run hook: %+v", args)
+	response := make(chan hookResponse, 1)
+	select {
+	case <-u.tomb.Dying():
+		return hook.Info{}, tomb.ErrDying
+	case u.hookRequests <- hookRequest{args, response}:
+	}
+	result := <-response
+	return result.info, result.err
+}
+
+// requestedHook returns the info of the hook described by args, or an
+// error if that hook cannot be run on request. Only hooks that a charm
+// must already expect to run repeatedly may be requested.
+func (u *Uniter) requestedHook(args RunHookArgs) (hook.Info, error) {
+	kind := hooks.Kind(args.Hook)
+	switch kind {
+	case hooks.Start, hooks.ConfigChanged, hooks.UpgradeCharm, hooks.CollectMetrics,
+		hooks.MeterStatusChanged, hooks.LeaderSettingsChanged:
+		if args.RelationId != -1 || args.RemoteUnitName != "" {
+			return hook.Info{}, errors.Errorf("%q hook does not run in a relation context", kind)
+		}
+		return hook.Info{Kind: kind}, nil
+	}
+	suffix := "-" + string(hooks.RelationChanged)
+	if name := strings.TrimSuffix(args.Hook, suffix); name != args.Hook && name != "" {
+		return u.relations.ChangedHook(name, args.RelationId, args.RemoteUnitName)
+	}
+	return hook.Info{}, errors.Errorf("cannot run %q hook on request", args.Hook)
+}
+
+// refuseHookRequest answers the supplied request with an error giving
+// the reason hooks cannot currently be run on request.
+func refuseHookRequest(request hookRequest, reason string) {
+	request.response <- hookResponse{err: errors.Errorf("cannot run hooks on request: %s", reason)}
+}
+
 // runOperation uses the uniter's operation factory to run the supplied creation
 // func, and then runs the resulting operation.
 //
@@ -987,6 +987,43 @@ func (s *UniterSuite) TestUniterUpgradeGitConflicts(c *gc.C) {
 	})
 }
 
+func (s *UniterSuite) TestUniterRunHook(c *gc.C) {
+	s.runUniterTests(c, []uniterTest{
+		ut(
+			"run config-changed on request",
+			quickStart{},
+			runHook{hook: "config-changed"},
+			waitHooks{"config-changed"},
+			verifyRunning{},
+		), ut(
+			"run relation-changed on request",
+			quickStartRelation{},
+			runHook{hook: "db-relation-changed"},
+			waitHooks{"db-relation-changed mysql/0 db:0"},
+			verifyRunning{},
+		), ut(
+			"hooks that change unit or relation state cannot be requested",
+			quickStartRelation{},
+			runHook{hook: "install", err: `cannot run "install" hook on request`},
+			runHook{hook: "db-relation-joined", err: `cannot run "db-relation-joined" hook on request`},
+			runHook{hook: "start", err: ""},
+			waitHooks{"start"},
+			verifyRunning{},
+		), ut(
+			"relation hooks must identify a relation",
+			quickStartRelation{},
+			runHook{hook: "foo-relation-changed", err: `unit has no "foo" relation`},
+			runHook{hook: "config-changed", err: ""},
+			waitHooks{"config-changed"},
+		), ut(
+			"hooks cannot be requested while a hook has failed",
+			startupError{"start"},
+			runHook{hook: "config-changed", err: `cannot run hooks on request: hook failed: "start"`},
+			waitHooks{},
+		),
+	})
+}
+
 func (s *UniterSuite) TestUniterRelations(c *gc.C) {
 	s.runUniterTests(c, []uniterTest{
 		// Relations.
@@ -1705,6 +1742,19 @@ func (s *UniterSuite) TestLeadership(c *gc.C) {
 			quickStart{},
 			runCommands{fmt.Sprintf("leader-set foo=bar baz=qux")},
 			verifyLeaderSettings{"foo": "bar", "baz": "qux"},
+		), ut(
+			"leader-elected runs once when the unit becomes leader",
+			createCharm{
+				customize: func(c *gc.C, ctx *context, path string) {
+					ctx.writeHook(c, filepath.Join(path, "hooks", "leader-elected"), true)
+				},
+			},
+			serveCharm{},
+			createUniter{},
+			waitUnit{status: params.StatusActive},
+			waitHooks{"install", "config-changed", "start", "leader-elected"},
+			verifyRunning{},
+			waitHooks{},
 		),
 	})
 }
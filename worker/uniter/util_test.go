@@ -1300,6 +1300,24 @@ func (cmds runCommands) step(c *gc.C, ctx *context) {
 	c.Check(string(result.Stderr), gc.Equals, "")
 }
 
+type runHook struct {
+	hook string
+	err  string
+}
+
+func (s runHook) step(c *gc.C, ctx *context) {
+	args := uniter.RunHookArgs{
+		Hook:       s.hook,
+		RelationId: -1,
+	}
+	_, err := ctx.uniter.RunHook(args)
+	if s.err != "" {
+		c.Assert(err, gc.ErrorMatches, s.err)
+		return
+	}
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 type asyncRunCommands []string
 
 func (cmds asyncRunCommands) step(c *gc.C, ctx *context) {